@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/kessler-frost/styx/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var alertsJSON bool
+var alertsFilter string
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "List active Alertmanager alerts",
+	Long: `Display alerts currently known to Alertmanager.
+
+Use --filter to narrow the list with an expression over Alert fields, e.g.:
+  styx alerts --filter 'Status.State == "active"' --json`,
+	RunE: runAlerts,
+}
+
+func init() {
+	alertsCmd.Flags().BoolVar(&alertsJSON, "json", false, "Output in JSON format")
+	alertsCmd.Flags().StringVar(&alertsFilter, "filter", "", "Filter expression (e.g. 'Status.State==\"active\"')")
+	rootCmd.AddCommand(alertsCmd)
+}
+
+func runAlerts(cmd *cobra.Command, args []string) error {
+	client := api.NewClient()
+	alerts, err := client.GetAlerts(alertsFilter)
+	if err != nil {
+		return fmt.Errorf("failed to get alerts: %w", err)
+	}
+
+	if alertsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(alerts)
+	}
+
+	if len(alerts) == 0 {
+		fmt.Println("No active alerts")
+		return nil
+	}
+
+	fmt.Println("Alerts")
+	fmt.Println("------")
+	fmt.Println()
+
+	for _, a := range alerts {
+		name := a.Labels["alertname"]
+		if name == "" {
+			name = "(unnamed)"
+		}
+		fmt.Printf("  [%s] %-30s %s\n", a.Status.State, name, a.Annotations["summary"])
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kessler-frost/styx/internal/authz"
+	"github.com/spf13/cobra"
+)
+
+var authzCmd = &cobra.Command{
+	Use:   "authz",
+	Short: "Show the effective Tailscale-identity authorization policy",
+	Long: `Styx resolves a caller's Tailscale identity via the LocalAPI WhoIs
+endpoint and authorizes it against a policy file (config-dir/authz.hcl)
+mapping identities to capabilities (read, deploy, admin), gating access to
+Nomad's HTTP API wherever it's fronted by
+services.NewAuthorizingNomadProxy instead of exposed directly. Edit the
+policy file directly - there's no 'authz grant' subcommand, to keep the
+trust boundary obvious: whoever can edit files in config-dir already has
+admin-equivalent access to this node.`,
+}
+
+var authzStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List identities and the capabilities they hold",
+	RunE:  runAuthzStatus,
+}
+
+func init() {
+	authzCmd.AddCommand(authzStatusCmd)
+	rootCmd.AddCommand(authzCmd)
+}
+
+func runAuthzStatus(cmd *cobra.Command, args []string) error {
+	policy, err := authz.LoadPolicy(authz.PolicyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", authz.PolicyPath, err)
+	}
+
+	entries := policy.Entries()
+	if len(entries) == 0 {
+		fmt.Printf("No policy at %s - every non-local caller is denied by default.\n", authz.PolicyPath)
+		return nil
+	}
+
+	fmt.Printf("Policy: %s\n\n", authz.PolicyPath)
+	for _, e := range entries {
+		fmt.Printf("  %-30s %v\n", e.Identity, e.Capabilities)
+	}
+	return nil
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"path/filepath"
 
+	"github.com/kessler-frost/styx/internal/api"
 	"github.com/kessler-frost/styx/internal/bootstrap"
+	"github.com/kessler-frost/styx/internal/diagnostic"
 	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/shutdown"
+	"github.com/kessler-frost/styx/internal/supervisor"
 	"github.com/spf13/cobra"
 )
 
@@ -30,19 +34,78 @@ func runBootstrapServer(cmd *cobra.Command, args []string) error {
 	}
 
 	// Start bootstrap server
-	server, err := bootstrap.NewServer(tsInfo.IP, certsDir, secretsDir)
+	selfAddr := bootstrap.ServerAddr{IP: tsInfo.IP, Name: tsInfo.Hostname}
+	server, err := bootstrap.NewServer(tsInfo.IP, configDir, certsDir, secretsDir, selfAddr)
 	if err != nil {
 		return fmt.Errorf("failed to start bootstrap server: %w", err)
 	}
 
-	server.Start()
+	if err := server.EnableAccessLog(logDir); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to enable bootstrap access logging: %v\n", err)
+	}
+
 	fmt.Printf("Bootstrap server listening on %s\n", server.Addr())
 
-	// Wait for signal
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	<-sigCh
+	// Run the server (and, optionally, the diagnostic server) under a
+	// supervisor.Tree so a crash (e.g. the listener dying) is restarted
+	// with backoff. Its ctx isn't tied to SIGINT/SIGTERM directly anymore:
+	// the shutdown.Coordinator below traps those and stops bootstrap-server
+	// itself via StopGraceful, which superviseOne treats as a clean exit
+	// (err == nil), not a crash to restart.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tree := supervisor.NewTree(supervisor.TreeBackoff{})
+	tree.Add("bootstrap-server", server)
+
+	if diagnosticAddr != "" {
+		diagServer := diagnostic.NewServer(diagnosticAddr, diagnostic.NewRegistry()).WithHTTPMetrics(server.Metrics())
+		tree.Add("diagnostic-server", diagServer)
+		fmt.Printf("Diagnostic server listening on %s\n", diagnosticAddr)
+	}
+
+	treeErrCh := make(chan error, 1)
+	go func() { treeErrCh <- tree.Serve(ctx) }()
+
+	apiClient := api.NewClient()
+	nodeID, err := localNodeID(apiClient)
+	if err != nil {
+		nodeID = "" // no local Nomad client node to drain, e.g. a server-only node
+	}
+
+	coordinator := (&shutdown.Coordinator{
+		NodeID:        nodeID,
+		Nomad:         apiClient,
+		DrainDeadline: shutdownTimeout,
+		DrainPoll: func() (int, error) {
+			allocs, err := apiClient.GetAllocs(fmt.Sprintf(`NodeID == "%s" and ClientStatus == "running"`, nodeID))
+			if err != nil {
+				return 0, err
+			}
+			return len(allocs), nil
+		},
+		Bootstrap: server,
+		Report: func(phase, message string) {
+			fmt.Printf("[shutdown] %s: %s\n", phase, message)
+		},
+	}).WithSocket(filepath.Join(styxBaseDir, "shutdown.sock"))
+
+	coordErrCh := make(chan error, 1)
+	go func() { coordErrCh <- coordinator.Run() }()
+
+	// Whichever finishes first wins: a permanent failure in the supervised
+	// tree (e.g. the bootstrap listener dying for good) should exit the
+	// process immediately rather than sit idle waiting for a SIGINT/SIGTERM
+	// that coordinator.Run is blocked on.
+	select {
+	case err := <-treeErrCh:
+		return err
+	case err := <-coordErrCh:
+		if err != nil {
+			return err
+		}
+	}
 
-	fmt.Println("Shutting down bootstrap server...")
-	return server.Stop()
+	cancel()
+	return <-treeErrCh
 }
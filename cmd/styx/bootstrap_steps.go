@@ -0,0 +1,742 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/bootstrap"
+	"github.com/kessler-frost/styx/internal/cluster"
+	"github.com/kessler-frost/styx/internal/config"
+	"github.com/kessler-frost/styx/internal/jointoken"
+	"github.com/kessler-frost/styx/internal/launchd"
+	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/pki"
+	"github.com/kessler-frost/styx/internal/services"
+	"github.com/kessler-frost/styx/internal/tailserve"
+	styxtls "github.com/kessler-frost/styx/internal/tls"
+	"github.com/kessler-frost/styx/internal/vault"
+)
+
+// serverBuild carries values computed by one server bring-up Step that a
+// later one needs (detected IP, generated config paths, ...). Steps close
+// over a shared *serverBuild rather than returning results through Runner,
+// which only tracks pass/fail per Step.
+type serverBuild struct {
+	ip                 string
+	nodeName           string
+	certs              *pki.CertPaths
+	cfg                config.ServerConfig
+	configPath         string
+	vaultConfigPath    string
+	templateSourcePath string
+	superviseBinPath   string
+	plistPath          string
+}
+
+// buildServerSteps returns the server bring-up sequence runServer hands to
+// a bootstrap.Runner: prerequisite checks, config/cert generation, launchd
+// load of a `styx supervise --role=server` job, Vault init/unseal, and
+// platform service deploy, in the same order runServer previously ran them
+// inline. When joinServerIP is set (--join-as-server), this server joins an
+// already-bootstrapped cluster as an additional voting server instead: it
+// TOFU-verifies the existing server's cluster identity, retry_joins it in
+// nomad.hcl, and skips the steps that only make sense for the first server
+// in a cluster (Vault init, platform service deploy, Tailscale Serve).
+func buildServerSteps(b *serverBuild, joinServerIP string) []bootstrap.Step {
+	steps := []bootstrap.Step{
+		bootstrap.FuncStep{
+			StepName: "check-prerequisites",
+			ApplyFunc: func(ctx context.Context) error {
+				nomadPath, err := exec.LookPath("nomad")
+				if err != nil {
+					return fmt.Errorf("nomad not found in PATH. Please install nomad first: brew install nomad")
+				}
+				fmt.Printf("Found nomad at: %s\n", nomadPath)
+
+				containerPath, err := exec.LookPath("container")
+				if err != nil {
+					return fmt.Errorf("container CLI not found. Please ensure macOS 26+ with Apple Containers is installed")
+				}
+				fmt.Printf("Found container CLI at: %s\n", containerPath)
+
+				if _, err := exec.LookPath("vault"); err != nil {
+					return fmt.Errorf("vault not found in PATH. Please install vault first: brew install vault")
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "detect-network",
+			ApplyFunc: func(ctx context.Context) error {
+				ip, err := network.GetPreferredIP()
+				if err != nil {
+					return fmt.Errorf("failed to detect local IP: %w", err)
+				}
+				b.ip = ip
+				fmt.Printf("Detected local IP: %s\n", ip)
+
+				if tailscale := network.GetTailscaleInfo(); tailscale.Running {
+					fmt.Printf("Tailscale connected: %s (%s)\n", tailscale.DNSName, tailscale.IP)
+					fmt.Println("  Services will be reachable via Tailscale from other nodes")
+					fmt.Println("  Transport encryption provided by Tailscale WireGuard")
+				} else {
+					fmt.Println("Tailscale not connected (cross-node networking will be limited)")
+					fmt.Println("  Install Tailscale: https://tailscale.com/download")
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName:  "container-network",
+			CheckFunc: func(ctx context.Context) (bool, error) { return network.NetworkExists(), nil },
+			ApplyFunc: func(ctx context.Context) error {
+				if err := network.EnsureStyxNetwork(); err != nil {
+					return fmt.Errorf("failed to create container network: %w", err)
+				}
+				fmt.Printf("Container network ready: %s (%s)\n", network.StyxNetworkName, network.StyxNetworkSubnet)
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "directories",
+			ApplyFunc: func(ctx context.Context) error {
+				dirs := []string{
+					dataDir, configDir, logDir, pluginDir, secretsDir, vaultDataDir,
+					filepath.Join(dataDir, "data", "postgres"),
+					filepath.Join(dataDir, "data", "rustfs"),
+					certsDir,
+				}
+				return ensureDirectories(dirs)
+			},
+		},
+	}
+
+	if joinServerIP != "" {
+		steps = append(steps, verifyClusterIdentityStep(joinServerIP, joinToken))
+	}
+
+	steps = append(steps,
+		bootstrap.FuncStep{
+			StepName: "copy-plugin",
+			ApplyFunc: func(ctx context.Context) error {
+				return copyPluginToDir(pluginDir)
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "provision-certs",
+			ApplyFunc: func(ctx context.Context) error {
+				nodeName, err := os.Hostname()
+				if err != nil {
+					return fmt.Errorf("failed to get hostname: %w", err)
+				}
+				if nodeName == "" {
+					nodeName = "node1"
+				}
+				b.nodeName = nodeName
+
+				fmt.Println("Provisioning mTLS certificates...")
+				ca, err := pki.LoadOrCreateCA(certsDir)
+				if err != nil {
+					return fmt.Errorf("failed to load or create CA: %w", err)
+				}
+				certs, err := pki.Issue(ca, certsDir, pki.IssueOptions{
+					NodeName:    nodeName,
+					Role:        pki.RoleServer,
+					AdvertiseIP: b.ip,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to issue server leaf certificate: %w", err)
+				}
+				b.certs = certs
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "generate-nomad-config",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("Generating server configuration...")
+				var retryJoin []string
+				if joinServerIP != "" {
+					retryJoin = []string{joinServerIP}
+				}
+				b.cfg = config.ServerConfig{
+					DataDir:         dataDir,
+					AdvertiseIP:     b.ip,
+					BootstrapExpect: bootstrapExpect,
+					Servers:         retryJoin,
+					PluginDir:       pluginDir,
+					CPUTotalCompute: config.GetCPUTotalCompute(),
+					CAFile:          b.certs.CAFile,
+					CertFile:        b.certs.CertFile,
+					KeyFile:         b.certs.KeyFile,
+				}
+				configContent, err := config.GenerateServerConfig(b.cfg)
+				if err != nil {
+					return fmt.Errorf("failed to generate config: %w", err)
+				}
+
+				b.configPath = filepath.Join(configDir, "nomad.hcl")
+				fmt.Printf("Writing Nomad config to: %s\n", b.configPath)
+				if err := config.WriteConfig(b.configPath, configContent); err != nil {
+					return fmt.Errorf("failed to write nomad config: %w", err)
+				}
+
+				b.templateSourcePath = filepath.Join(configDir, "nomad.hcl.ctmpl")
+				return os.WriteFile(b.templateSourcePath, []byte(config.ServerConfigCTemplate), 0600)
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "generate-vault-config",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("Generating Vault configuration (Raft storage)...")
+				vaultConfigContent, err := config.GenerateVaultConfig(config.VaultConfig{
+					DataDir:     vaultDataDir,
+					NodeID:      b.nodeName,
+					AdvertiseIP: b.ip,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to generate vault config: %w", err)
+				}
+
+				b.vaultConfigPath = filepath.Join(configDir, "vault.hcl")
+				fmt.Printf("Writing Vault config to: %s\n", b.vaultConfigPath)
+				return config.WriteConfig(b.vaultConfigPath, vaultConfigContent)
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "resolve-supervise-binary",
+			ApplyFunc: func(ctx context.Context) error {
+				styxBinPath, err := os.Executable()
+				if err != nil {
+					return fmt.Errorf("failed to resolve styx binary path: %w", err)
+				}
+				b.superviseBinPath = styxBinPath
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "load-launchd-service",
+			ApplyFunc: func(ctx context.Context) error {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return fmt.Errorf("failed to get home directory: %w", err)
+				}
+				b.plistPath = filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
+				fmt.Printf("Creating launchd plist at: %s\n", b.plistPath)
+
+				if err := os.MkdirAll(filepath.Dir(b.plistPath), 0755); err != nil {
+					return fmt.Errorf("failed to create LaunchAgents directory: %w", err)
+				}
+
+				superviseArgs := []string{"supervise", "--role=server", "--data-dir=" + dataDir, "--config-dir=" + configDir, "--secrets-dir=" + secretsDir, "--certs-dir=" + certsDir, "--log-dir=" + logDir}
+				if rendezvousURL != "" {
+					superviseArgs = append(superviseArgs, "--rendezvous="+rendezvousURL)
+				}
+
+				plistCfg := launchd.PlistConfig{
+					Label:      "com.styx.nomad",
+					Program:    b.superviseBinPath,
+					Args:       superviseArgs,
+					LogPath:    filepath.Join(logDir, "styx.log"),
+					ErrLogPath: filepath.Join(logDir, "styx-error.log"),
+					WorkingDir: configDir,
+					KeepAlive: &launchd.KeepAliveConfig{
+						SuccessfulExit: false,
+						Crashed:        true,
+						NetworkState:   true,
+					},
+					ThrottleInterval: 10,
+				}
+				if err := launchd.WritePlist(b.plistPath, plistCfg); err != nil {
+					return fmt.Errorf("failed to write plist: %w", err)
+				}
+
+				if launchd.IsLoaded("com.styx.nomad") {
+					fmt.Println("Unloading existing service...")
+					if err := launchd.Unload(b.plistPath); err != nil {
+						fmt.Printf("Warning: failed to unload existing service: %v\n", err)
+					}
+					time.Sleep(2 * time.Second)
+				}
+
+				fmt.Println("Loading launchd service...")
+				return launchd.Load(b.plistPath)
+			},
+			RollbackFunc: func(ctx context.Context) error {
+				if launchd.IsLoaded("com.styx.nomad") {
+					return launchd.Unload(b.plistPath)
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "bootstrap-vault",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("Waiting for Vault to become ready...")
+				if err := waitForService("vault", "http://127.0.0.1:8200/v1/sys/health", 30*time.Second, 200, 429, 501, 503); err != nil {
+					return fmt.Errorf("vault failed to start: %w\nCheck logs at %s", err, filepath.Join(logDir, "styx.log"))
+				}
+
+				fmt.Println("Initializing and unsealing Vault, and provisioning the nomad-cluster integration...")
+				opts := vaultBootstrapOptions(secretsDir)
+				bootstrapper := vault.NewBootstrapper(opts)
+				out, err := bootstrapper.Run()
+				if err != nil {
+					return fmt.Errorf("failed to bootstrap vault: %w", err)
+				}
+
+				if unsealProvider != "" {
+					fmt.Printf("Migrating vault init output to --unseal-provider=%s...\n", unsealProvider)
+					if err := vault.MigrateInitOutput(opts); err != nil {
+						return fmt.Errorf("failed to migrate vault init output to %s: %w", unsealProvider, err)
+					}
+				}
+
+				if printShares {
+					fmt.Println("\nUnseal key shares (distribute to operators):")
+					for i, key := range out.UnsealKeysB64 {
+						fmt.Printf("  Key %d: %s\n", i+1, key)
+					}
+					fmt.Printf("Root token: %s\n", out.RootToken)
+				}
+
+				fmt.Println("Stamping cluster identity into Vault's KV store...")
+				info, err := cluster.LoadOrCreate(configDir, secretsDir)
+				if err != nil {
+					return fmt.Errorf("failed to load cluster identity: %w", err)
+				}
+				if err := vault.StampClusterIdentity(out.RootToken, info.ClusterID, info.BootstrapToken); err != nil {
+					return fmt.Errorf("failed to stamp cluster identity into vault: %w", err)
+				}
+				b.cfg.ClusterID = info.ClusterID
+
+				fmt.Println("Waiting for Vault to become active...")
+				return waitForService("vault", "http://127.0.0.1:8200/v1/sys/health", 60*time.Second)
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "reload-nomad-with-vault-token",
+			ApplyFunc: func(ctx context.Context) error {
+				nomadVaultToken, err := vault.GetNomadToken(secretsDir)
+				if err != nil {
+					fmt.Printf("Warning: failed to read nomad-cluster vault token: %v\n", err)
+					return nil
+				}
+
+				b.cfg.VaultToken = nomadVaultToken
+				configContent, err := config.GenerateServerConfig(b.cfg)
+				if err != nil {
+					fmt.Printf("Warning: failed to regenerate nomad config with vault token: %v\n", err)
+					return nil
+				}
+				if err := config.WriteConfig(b.configPath, configContent); err != nil {
+					fmt.Printf("Warning: failed to write nomad config with vault token: %v\n", err)
+					return nil
+				}
+
+				fmt.Println("Reloading Nomad to pick up the nomad-cluster Vault token...")
+				if err := launchd.Reload("com.styx.nomad", b.plistPath); err != nil {
+					fmt.Printf("Warning: failed to reload nomad: %v\n", err)
+					return nil
+				}
+				if err := waitForService("nomad", "http://127.0.0.1:4646/v1/agent/health", 30*time.Second); err != nil {
+					fmt.Printf("Warning: nomad did not become healthy after reload: %v\n", err)
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "vault-nomad-integration",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("Setting up Vault-Nomad workload identity integration...")
+				rootToken, err := vault.GetRootToken(secretsDir)
+				if err != nil {
+					fmt.Printf("Warning: failed to read vault root token: %v\n", err)
+					return nil
+				}
+				store, err := vault.NewStore(vault.StoreKindVault, "")
+				if err != nil {
+					fmt.Printf("Warning: failed to create vault secret store: %v\n", err)
+					return nil
+				}
+				if err := vault.SetupNomadIntegration(store.WithToken(rootToken), secretsDir); err != nil {
+					fmt.Printf("Warning: failed to setup Vault-Nomad integration: %v\n", err)
+					fmt.Println("You can set this up later with 'vault policy write' and 'vault token create'")
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "vault-pki-bootstrap",
+			ApplyFunc: func(ctx context.Context) error {
+				if pkiBackend != "vault" {
+					return nil
+				}
+				fmt.Println("Bootstrapping Vault PKI secrets engines for Consul/Nomad leaf certificates...")
+				rootToken, err := vault.GetRootToken(secretsDir)
+				if err != nil {
+					fmt.Printf("Warning: failed to read vault root token, skipping vault PKI bootstrap: %v\n", err)
+					return nil
+				}
+				// "dc1"/"global" match internal/tls's and `styx tls`'s own
+				// defaults; there's no Datacenter/Region field on
+				// config.ServerConfig to read instead.
+				issuer := styxtls.NewVaultPKIIssuer("", rootToken)
+				if err := issuer.Bootstrap("dc1", "global"); err != nil {
+					fmt.Printf("Warning: failed to bootstrap vault PKI: %v\n", err)
+					fmt.Println("You can retry later with 'styx tls rotate --pki=vault --force'")
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "wait-for-nomad",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("Waiting for Nomad to become healthy...")
+				if err := waitForService("nomad", "http://127.0.0.1:4646/v1/agent/health", 60*time.Second); err != nil {
+					return fmt.Errorf("nomad failed to start: %w\nCheck logs at %s", err, filepath.Join(logDir, "styx.log"))
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "deploy-platform-services",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("\nDeploying platform services...")
+				if err := services.DeployAll(); err != nil {
+					return fmt.Errorf("failed to deploy platform services: %w", err)
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "enable-tailscale-serve",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("\nEnabling Tailscale Serve for HTTPS ingress...")
+				if err := tailserve.Enable(); err != nil {
+					fmt.Printf("  Warning: failed to enable Tailscale Serve: %v\n", err)
+					fmt.Println("  Traefik is still available at http://localhost:4200")
+				}
+				return nil
+			},
+		},
+	)
+
+	if joinServerIP == "" {
+		return steps
+	}
+
+	// An additional voting server joins an already-bootstrapped cluster's
+	// Vault and platform services rather than standing up its own, so skip
+	// the steps that only make sense for the first server.
+	serverOnly := map[string]bool{
+		"generate-vault-config":         true,
+		"bootstrap-vault":               true,
+		"reload-nomad-with-vault-token": true,
+		"vault-nomad-integration":       true,
+		"vault-pki-bootstrap":           true,
+		"deploy-platform-services":      true,
+		"enable-tailscale-serve":        true,
+	}
+	filtered := steps[:0]
+	for _, s := range steps {
+		if !serverOnly[s.Name()] {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// clientBuild carries values computed by one client bring-up Step that a
+// later one needs, the way serverBuild does for buildServerSteps.
+type clientBuild struct {
+	ip               string
+	configPath       string
+	superviseBinPath string
+	plistPath        string
+}
+
+// buildClientSteps returns the client join sequence runClient hands to a
+// bootstrap.Runner, mirroring buildServerSteps but without Vault/platform
+// service provisioning, since a client joins a server that already has
+// those. token is the join token minted by `styx token create` on serverIP
+// (see --token/STYX_JOIN_TOKEN on `styx init --join`), required by the
+// server's /bootstrap/* cert and gossip-key endpoints.
+func buildClientSteps(b *clientBuild, serverIP, token string) []bootstrap.Step {
+	return []bootstrap.Step{
+		bootstrap.FuncStep{
+			StepName: "check-prerequisites",
+			ApplyFunc: func(ctx context.Context) error {
+				nomadPath, err := exec.LookPath("nomad")
+				if err != nil {
+					return fmt.Errorf("nomad not found in PATH. Please install nomad first: brew install nomad")
+				}
+				fmt.Printf("Found nomad at: %s\n", nomadPath)
+
+				containerPath, err := exec.LookPath("container")
+				if err != nil {
+					return fmt.Errorf("container CLI not found. Please ensure macOS 26+ with Apple Containers is installed")
+				}
+				fmt.Printf("Found container CLI at: %s\n", containerPath)
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "check-server-reachable",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Printf("Checking Nomad server at %s...\n", serverIP)
+				client := &http.Client{Timeout: 5 * time.Second}
+				resp, err := client.Get(fmt.Sprintf("http://%s:4646/v1/agent/health", serverIP))
+				if err != nil {
+					return fmt.Errorf("cannot reach Nomad server at %s:4646: %w", serverIP, err)
+				}
+				defer resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					return fmt.Errorf("Nomad server at %s is not healthy (status %d)", serverIP, resp.StatusCode)
+				}
+				fmt.Println("Nomad server is reachable and healthy")
+				return nil
+			},
+		},
+		verifyClusterIdentityStep(serverIP, token),
+		bootstrap.FuncStep{
+			StepName: "fetch-bootstrap-secrets",
+			ApplyFunc: func(ctx context.Context) error {
+				if token == "" {
+					fmt.Println("No --token provided, skipping bootstrap cert/gossip-key fetch (pass --token or set STYX_JOIN_TOKEN if this server requires it)")
+					return nil
+				}
+				fmt.Println("Fetching bootstrap certificates and gossip key...")
+				if err := bootstrap.FetchBootstrapFiles(serverIP, certsDir, secretsDir, token); err != nil {
+					fmt.Printf("Warning: failed to fetch bootstrap files: %v\n", err)
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "detect-network",
+			ApplyFunc: func(ctx context.Context) error {
+				ip, err := network.GetPreferredIP()
+				if err != nil {
+					return fmt.Errorf("failed to detect local IP: %w", err)
+				}
+				b.ip = ip
+				fmt.Printf("Detected local IP: %s\n", ip)
+
+				if tailscale := network.GetTailscaleInfo(); tailscale.Running {
+					fmt.Printf("Tailscale connected: %s (%s)\n", tailscale.DNSName, tailscale.IP)
+					fmt.Println("  Services will be reachable via Tailscale from other nodes")
+				} else {
+					fmt.Println("Tailscale not connected (cross-node networking will be limited)")
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName:  "container-network",
+			CheckFunc: func(ctx context.Context) (bool, error) { return network.NetworkExists(), nil },
+			ApplyFunc: func(ctx context.Context) error {
+				if err := network.EnsureStyxNetwork(); err != nil {
+					return fmt.Errorf("failed to create container network: %w", err)
+				}
+				fmt.Printf("Container network ready: %s (%s)\n", network.StyxNetworkName, network.StyxNetworkSubnet)
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "directories",
+			ApplyFunc: func(ctx context.Context) error {
+				return ensureDirectories([]string{dataDir, configDir, logDir, pluginDir, certsDir})
+			},
+		},
+		bootstrap.FuncStep{
+			StepName:  "copy-plugin",
+			ApplyFunc: func(ctx context.Context) error { return copyPluginToDir(pluginDir) },
+		},
+		bootstrap.FuncStep{
+			StepName: "generate-nomad-config",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("Generating client configuration...")
+				configContent, err := config.GenerateClientConfig(config.ClientConfig{
+					DataDir:         dataDir,
+					AdvertiseIP:     b.ip,
+					Servers:         []string{serverIP},
+					PluginDir:       pluginDir,
+					CPUTotalCompute: config.GetCPUTotalCompute(),
+				})
+				if err != nil {
+					return fmt.Errorf("failed to generate config: %w", err)
+				}
+
+				b.configPath = filepath.Join(configDir, "nomad.hcl")
+				fmt.Printf("Writing Nomad config to: %s\n", b.configPath)
+				return config.WriteConfig(b.configPath, configContent)
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "resolve-supervise-binary",
+			ApplyFunc: func(ctx context.Context) error {
+				styxBinPath, err := os.Executable()
+				if err != nil {
+					return fmt.Errorf("failed to resolve styx binary path: %w", err)
+				}
+				b.superviseBinPath = styxBinPath
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "load-launchd-service",
+			ApplyFunc: func(ctx context.Context) error {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return fmt.Errorf("failed to get home directory: %w", err)
+				}
+				b.plistPath = filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
+				fmt.Printf("Creating launchd plist at: %s\n", b.plistPath)
+
+				if err := os.MkdirAll(filepath.Dir(b.plistPath), 0755); err != nil {
+					return fmt.Errorf("failed to create LaunchAgents directory: %w", err)
+				}
+
+				plistCfg := launchd.PlistConfig{
+					Label:      "com.styx.nomad",
+					Program:    b.superviseBinPath,
+					Args:       []string{"supervise", "--role=client", "--data-dir=" + dataDir, "--config-dir=" + configDir, "--secrets-dir=" + secretsDir, "--certs-dir=" + certsDir, "--log-dir=" + logDir},
+					LogPath:    filepath.Join(logDir, "styx.log"),
+					ErrLogPath: filepath.Join(logDir, "styx-error.log"),
+					WorkingDir: configDir,
+					KeepAlive: &launchd.KeepAliveConfig{
+						SuccessfulExit: false,
+						Crashed:        true,
+						NetworkState:   true,
+					},
+					ThrottleInterval: 10,
+				}
+				if err := launchd.WritePlist(b.plistPath, plistCfg); err != nil {
+					return fmt.Errorf("failed to write plist: %w", err)
+				}
+
+				if launchd.IsLoaded("com.styx.nomad") {
+					fmt.Println("Unloading existing service...")
+					if err := launchd.Unload(b.plistPath); err != nil {
+						fmt.Printf("Warning: failed to unload existing service: %v\n", err)
+					}
+					time.Sleep(2 * time.Second)
+				}
+
+				fmt.Println("Loading launchd service...")
+				return launchd.Load(b.plistPath)
+			},
+			RollbackFunc: func(ctx context.Context) error {
+				if launchd.IsLoaded("com.styx.nomad") {
+					return launchd.Unload(b.plistPath)
+				}
+				return nil
+			},
+		},
+		bootstrap.FuncStep{
+			StepName: "wait-for-nomad",
+			ApplyFunc: func(ctx context.Context) error {
+				fmt.Println("Waiting for Nomad client to start...")
+				if err := waitForService("nomad", "http://127.0.0.1:4646/v1/agent/health", 60*time.Second); err != nil {
+					return fmt.Errorf("nomad failed to start: %w\nCheck logs at %s", err, filepath.Join(logDir, "styx.log"))
+				}
+
+				fmt.Println("Waiting for client to register with server...")
+				time.Sleep(5 * time.Second)
+				return nil
+			},
+		},
+	}
+}
+
+// verifyClusterIdentityStep fetches serverIP's /bootstrap/cluster-info over
+// the bootstrap server's self-signed HTTPS listener and TOFU-verifies it
+// against this node's previously-persisted cluster.json (see
+// internal/cluster): on first join it prints the cluster_id and CA
+// fingerprint and asks the operator to confirm, then remembers them; on
+// every later join (including --resume) it requires an exact match, so a
+// client or additional server can't be silently pointed at a different
+// cluster. Shared between buildClientSteps and buildServerSteps's
+// --join-as-server path.
+//
+// When token is non-empty its embedded cert fingerprint (see
+// jointoken.Peek) pins the connection; otherwise the cert is accepted
+// unverified, same as fetch-bootstrap-secrets's token == "" fallback -
+// there's nothing to pin against without a token in hand.
+func verifyClusterIdentityStep(serverIP, token string) bootstrap.Step {
+	return bootstrap.FuncStep{
+		StepName: "verify-cluster-identity",
+		ApplyFunc: func(ctx context.Context) error {
+			fmt.Println("Verifying cluster identity...")
+			client, err := tofuClient(token)
+			if err != nil {
+				return fmt.Errorf("failed to set up cluster-identity client: %w", err)
+			}
+			resp, err := client.Get(fmt.Sprintf("https://%s:%d/bootstrap/cluster-info", serverIP, bootstrap.Port))
+			if err != nil {
+				return fmt.Errorf("failed to fetch cluster identity from %s: %w", serverIP, err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("server at %s returned %d for cluster identity", serverIP, resp.StatusCode)
+			}
+
+			var current cluster.JoinRecord
+			if err := json.NewDecoder(resp.Body).Decode(&current); err != nil {
+				return fmt.Errorf("failed to parse cluster identity response: %w", err)
+			}
+
+			previous, err := cluster.LoadJoinRecord(secretsDir)
+			if err != nil {
+				return fmt.Errorf("failed to load previously-trusted cluster identity: %w", err)
+			}
+			if previous == nil {
+				fmt.Printf("Server %s reports:\n  cluster_id:   %s\n  CA fingerprint: %s\n", serverIP, current.ClusterID, current.CAFingerprint)
+				if !promptYesNo("Trust this cluster?") {
+					return fmt.Errorf("cluster identity not confirmed, aborting join")
+				}
+				return cluster.SaveJoinRecord(secretsDir, &current)
+			}
+			return cluster.CheckJoinRecord(previous, &current)
+		},
+	}
+}
+
+// tofuClient returns an http.Client for talking to a bootstrap server's
+// self-signed HTTPS listener. If token is non-empty, the connection is
+// pinned to the certificate fingerprint embedded in its claims; otherwise
+// the certificate is accepted unverified (trust-on-first-use is enforced
+// one layer up, by verifyClusterIdentityStep's cluster.json comparison).
+func tofuClient(token string) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+
+	if token != "" {
+		claims, err := jointoken.Peek(token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read claims from token: %w", err)
+		}
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return fmt.Errorf("server presented no certificate")
+			}
+			if got := bootstrap.FingerprintCert(rawCerts[0]); got != claims.CertFingerprint {
+				return fmt.Errorf("server certificate fingerprint %s does not match token's pinned fingerprint %s", got, claims.CertFingerprint)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/kessler-frost/styx/internal/api"
+	"github.com/kessler-frost/styx/internal/cluster"
+	"github.com/kessler-frost/styx/internal/network"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replaceNodeForce  bool
+	replaceNodeDryRun bool
+)
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Manage Styx cluster membership",
+}
+
+var clusterReplaceNodeCmd = &cobra.Command{
+	Use:   "replace-node <name>",
+	Short: "Evict a dead Nomad server member so a replacement can rejoin under the same name",
+	Long: `Recover from a Nomad server stuck in "failed" or "left": force-leave it
+out of the serf pool, remove it from the raft peer set, and - if this host
+is that member - wipe the local raft state, so a freshly reinstalled
+replacement can rejoin reusing the retired node name.
+
+Refuses to touch a member that still reports alive unless --force is given.
+
+Examples:
+  styx cluster replace-node mac-3
+  styx cluster replace-node mac-3 --dry-run
+  styx cluster replace-node mac-3 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClusterReplaceNode,
+}
+
+func init() {
+	clusterReplaceNodeCmd.Flags().BoolVar(&replaceNodeForce, "force", false, "Replace the member even if it currently reports alive")
+	clusterReplaceNodeCmd.Flags().BoolVar(&replaceNodeDryRun, "dry-run", false, "Print the API calls that would be made without making them")
+	clusterCmd.AddCommand(clusterReplaceNodeCmd)
+	rootCmd.AddCommand(clusterCmd)
+}
+
+func runClusterReplaceNode(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	client := api.NewClient()
+	result, err := cluster.ReplaceNode(client, name, cluster.ReplaceNodeOptions{
+		Force:     replaceNodeForce,
+		DryRun:    replaceNodeDryRun,
+		RaftDir:   filepath.Join(dataDir, "server", "raft"),
+		LocalAddr: network.GetTailscaleInfo().IP,
+	})
+	if err != nil {
+		return err
+	}
+	if replaceNodeDryRun {
+		return nil
+	}
+
+	fmt.Printf("Evicted %s (%s) from the serf pool and raft peer set.\n", name, result.Member.Addr)
+	if result.WipedRaft {
+		fmt.Printf("Wiped local raft state at %s.\n", filepath.Join(dataDir, "server", "raft"))
+	}
+	fmt.Printf("\nTo bring up a replacement reusing this identity, run on the new node:\n  %s\n", result.RejoinCmd)
+	return nil
+}
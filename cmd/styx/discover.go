@@ -95,7 +95,7 @@ func joinWithBootstrap(server network.NomadServer) error {
 	// Try to fetch bootstrap files from server
 	fmt.Printf("Fetching credentials from %s...\n", server.Hostname)
 	if bootstrap.CheckBootstrapServer(server.IP) {
-		if err := bootstrap.FetchBootstrapFiles(server.IP, certsDir, secretsDir); err != nil {
+		if err := bootstrap.FetchBootstrapFiles(server.IP, certsDir, secretsDir, joinToken); err != nil {
 			fmt.Printf("Warning: failed to fetch bootstrap files: %v\n", err)
 			fmt.Println("You may need to manually copy certificates from the server.")
 		} else {
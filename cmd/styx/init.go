@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,18 +13,31 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kessler-frost/styx/internal/config"
+	"github.com/kessler-frost/styx/internal/bootstrap"
 	"github.com/kessler-frost/styx/internal/launchd"
 	"github.com/kessler-frost/styx/internal/network"
-	"github.com/kessler-frost/styx/internal/services"
-	"github.com/kessler-frost/styx/internal/tailserve"
 	"github.com/kessler-frost/styx/internal/vault"
 	"github.com/spf13/cobra"
 )
 
 var (
-	serveMode bool
-	joinIP    string
+	serveMode       bool
+	joinIP          string
+	resumeInit      bool
+	discoverNames   []string
+	rendezvousURL   string
+	unsealProvider  string
+	kmsKeyID        string
+	shamirPeers     []string
+	shamirThreshold int
+	transitAddr     string
+	transitKeyName  string
+	transitToken    string
+	passphrase      string
+	printShares     bool
+	bootstrapExpect int
+	joinAsServer    bool
+	joinToken       string
 )
 
 var initCmd = &cobra.Command{
@@ -31,23 +45,87 @@ var initCmd = &cobra.Command{
 	Short: "Start or join a Styx cluster",
 	Long: `Initialize Styx by starting a server or joining an existing cluster.
 
-By default, init auto-discovers servers on your Tailscale network:
+By default, init auto-discovers servers using every enabled backend in
+parallel - a Tailscale peer scan, LAN mDNS, a static ~/.styx/peers.yml
+seed list, and (with --rendezvous) a shared HTTPS rendezvous point:
   - If no servers found, prompts to start one
   - If one server found, auto-joins it
   - If multiple servers found, prompts for selection
 
 Flags:
-  --serve       Force server mode (starts Nomad + Vault + platform services)
-  --join <ip>   Join a specific server by IP address`,
+  --serve               Force server mode (starts Nomad + Vault + platform services)
+  --join <ip>           Join a specific server by IP address
+  --bootstrap-expect N  Number of voting servers to expect, for an HA cluster
+                        (default 1). Only meaningful with --serve.
+  --join-as-server      With --serve --join <ip>, join an existing cluster as
+                        an additional voting server instead of bootstrapping
+                        a new one, so 3/5-server HA clusters can form without
+                        hand-editing nomad.hcl.
+  --token <token>       Join token minted by 'styx token create' on the
+                        server being joined, required to fetch its CA/client
+                        certs and gossip key (or set STYX_JOIN_TOKEN).
+  --discover <backends> Restrict auto-discovery to a comma-separated list of
+                        backends: tailscale, mdns, seedfile, rendezvous
+  --rendezvous <url>    Shared HTTPS rendezvous URL for the rendezvous backend
+  --unseal-provider <p> Where Vault's unseal keys and root token are kept:
+                        keychain, awskms, gcpckms, shamir, transit, or
+                        passphrase (default: one file per Shamir share under
+                        the secrets dir). Migrates an existing init output and
+                        deletes the plaintext file if one was found.`,
 	RunE: runInit,
 }
 
 func init() {
 	initCmd.Flags().BoolVar(&serveMode, "serve", false, "Force server mode")
 	initCmd.Flags().StringVar(&joinIP, "join", "", "Join a specific server by IP")
+	initCmd.Flags().IntVar(&bootstrapExpect, "bootstrap-expect", 1, "Number of voting servers to expect in an HA cluster (only meaningful with --serve)")
+	initCmd.Flags().BoolVar(&joinAsServer, "join-as-server", false, "With --serve --join <ip>, join an existing cluster as an additional voting server")
+	initCmd.Flags().StringVar(&joinToken, "token", os.Getenv("STYX_JOIN_TOKEN"), "Join token minted by 'styx token create' on the server being joined (or set STYX_JOIN_TOKEN)")
+	initCmd.Flags().BoolVar(&resumeInit, "resume", false, "Resume a previously interrupted init, skipping steps already completed")
+	initCmd.Flags().StringSliceVar(&discoverNames, "discover", nil, "Restrict auto-discovery to these backends: tailscale, mdns, seedfile, rendezvous (default: all enabled)")
+	initCmd.Flags().StringVar(&rendezvousURL, "rendezvous", "", "Shared HTTPS rendezvous URL for the rendezvous discovery backend")
+	initCmd.Flags().StringVar(&unsealProvider, "unseal-provider", "", "Where to keep Vault's unseal keys and root token: keychain, awskms, gcpckms, shamir, transit, or passphrase (default: one file per Shamir share under the secrets dir)")
+	initCmd.Flags().StringVar(&kmsKeyID, "kms-key-id", "", "KMS key to encrypt under, for --unseal-provider=awskms|gcpckms")
+	initCmd.Flags().StringSliceVar(&shamirPeers, "shamir-peers", nil, "Tailscale IPs to deposit shares with, for --unseal-provider=shamir")
+	initCmd.Flags().IntVar(&shamirThreshold, "shamir-threshold", 0, "Number of --shamir-peers that must be reachable to reconstruct the init output, for --unseal-provider=shamir")
+	initCmd.Flags().StringVar(&transitAddr, "transit-addr", "", "Second Vault's address to auto-unseal through, for --unseal-provider=transit")
+	initCmd.Flags().StringVar(&transitKeyName, "transit-key-name", "", "Transit key to encrypt/decrypt under, for --unseal-provider=transit")
+	initCmd.Flags().StringVar(&transitToken, "transit-token", os.Getenv("STYX_VAULT_TRANSIT_TOKEN"), "Token authorizing transit encrypt/decrypt calls, for --unseal-provider=transit (or set STYX_VAULT_TRANSIT_TOKEN)")
+	initCmd.Flags().StringVar(&passphrase, "passphrase", os.Getenv("STYX_VAULT_PASSPHRASE"), "Operator passphrase to wrap the init output under, for --unseal-provider=passphrase (or set STYX_VAULT_PASSPHRASE)")
+	initCmd.Flags().BoolVar(&printShares, "print-shares", false, "Print the unseal key shares and root token to stdout after Vault is initialized, for distribution to operators")
 	rootCmd.AddCommand(initCmd)
 }
 
+// vaultBootstrapOptions translates the --unseal-provider family of flags
+// into vault.BootstrapOptions. An empty unsealProvider leaves Destination at
+// DefaultBootstrapOptions' default of one file per Shamir share.
+func vaultBootstrapOptions(secretsDir string) vault.BootstrapOptions {
+	opts := vault.DefaultBootstrapOptions(secretsDir)
+	switch unsealProvider {
+	case "keychain":
+		opts.Destination = vault.DestinationKeychain
+	case "awskms":
+		opts.Destination = vault.DestinationAWSKMS
+		opts.KMSKeyID = kmsKeyID
+	case "gcpckms":
+		opts.Destination = vault.DestinationGCPKMS
+		opts.KMSKeyID = kmsKeyID
+	case "shamir":
+		opts.Destination = vault.DestinationShamir
+		opts.ShamirPeers = shamirPeers
+		opts.ShamirThreshold = shamirThreshold
+	case "transit":
+		opts.Destination = vault.DestinationTransit
+		opts.TransitAddr = transitAddr
+		opts.TransitKeyName = transitKeyName
+		opts.TransitToken = transitToken
+	case "passphrase":
+		opts.Destination = vault.DestinationPassphrase
+		opts.Passphrase = passphrase
+	}
+	return opts
+}
+
 func runInit(cmd *cobra.Command, args []string) error {
 	// Check if already running
 	if launchd.IsLoaded("com.styx.nomad") {
@@ -71,46 +149,58 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	// Determine mode
 	if serveMode {
-		return runServer()
+		if joinAsServer {
+			if joinIP == "" {
+				return fmt.Errorf("--join-as-server requires --join <ip>")
+			}
+			return runServer(joinIP)
+		}
+		return runServer("")
+	}
+
+	if joinAsServer {
+		return fmt.Errorf("--join-as-server requires --serve")
 	}
 
 	if joinIP != "" {
-		return runClient(joinIP)
+		return runClient(joinIP, joinToken)
 	}
 
 	// Auto-discover mode
 	return runAutoDiscover()
 }
 
-// runAutoDiscover probes Tailscale peers for Nomad servers
+// runAutoDiscover tries every enabled network.Discoverer backend in
+// parallel (Tailscale peer scan, LAN mDNS, the ~/.styx/peers.yml seed
+// list, and rendezvous if --rendezvous is set), restricted to --discover
+// when given, and merges their results.
 func runAutoDiscover() error {
-	// Check Tailscale status
 	tsInfo := network.GetTailscaleInfo()
-	if !tsInfo.Running {
-		fmt.Println("Tailscale is not running.")
-		fmt.Println()
-		fmt.Println("To auto-discover servers, install and connect Tailscale:")
-		fmt.Println("  https://tailscale.com/download")
-		fmt.Println()
-		fmt.Println("Or use manual commands:")
-		fmt.Println("  styx init --serve       Start a server on this machine")
-		fmt.Println("  styx init --join <ip>   Join an existing server")
-		return nil
+	if tsInfo.Running {
+		fmt.Printf("Tailscale connected: %s (%s)\n", tsInfo.DNSName, tsInfo.IP)
 	}
 
-	fmt.Printf("Tailscale connected: %s (%s)\n", tsInfo.DNSName, tsInfo.IP)
-	fmt.Println("Discovering Nomad servers on Tailscale network...")
+	discoverers := network.SelectDiscoverers(network.AllDiscoverers(rendezvousURL), discoverNames)
+	if len(discoverers) == 0 {
+		return fmt.Errorf("no discovery backends enabled (check --discover)")
+	}
+
+	names := make([]string, len(discoverers))
+	for i, d := range discoverers {
+		names[i] = d.Name()
+	}
+	fmt.Printf("Discovering Nomad servers (%s)...\n", strings.Join(names, ", "))
 
-	servers := network.DiscoverNomadServers(3 * time.Second)
+	servers := network.DiscoverAll(context.Background(), discoverers, 3*time.Second)
 
 	// No servers found - prompt to start one
 	if len(servers) == 0 {
 		fmt.Println()
-		fmt.Println("No Nomad servers found on your Tailscale network.")
+		fmt.Println("No Nomad servers found.")
 		fmt.Println()
 
 		if promptYesNo("Start a server on this machine?") {
-			return runServer()
+			return runServer("")
 		}
 
 		fmt.Println()
@@ -124,14 +214,14 @@ func runAutoDiscover() error {
 		fmt.Printf("\nFound server: %s (%s)\n", server.Hostname, server.IP)
 		fmt.Println("Joining cluster...")
 		fmt.Println()
-		return runClient(server.IP)
+		return runClient(server.IP, joinToken)
 	}
 
 	// Multiple servers found - prompt for selection
 	fmt.Printf("\nFound %d Nomad servers:\n", len(servers))
 	fmt.Println()
 	for i, s := range servers {
-		fmt.Printf("  [%d] %s (%s)\n", i+1, s.Hostname, s.IP)
+		fmt.Printf("  [%d] %s (%s) via %s\n", i+1, s.Hostname, s.IP, s.Source)
 	}
 	fmt.Println()
 
@@ -143,7 +233,7 @@ func runAutoDiscover() error {
 	server := servers[selected]
 	fmt.Println("Joining cluster...")
 	fmt.Println()
-	return runClient(server.IP)
+	return runClient(server.IP, joinToken)
 }
 
 // ensureDirectories creates the specified directories if they don't exist
@@ -183,284 +273,31 @@ func copyPluginToDir(pluginDir string) error {
 	return nil
 }
 
-// runServer starts Styx in server mode (Nomad + Vault + platform services)
-func runServer() error {
-	// Check for nomad binary
-	nomadPath, err := exec.LookPath("nomad")
-	if err != nil {
-		return fmt.Errorf("nomad not found in PATH. Please install nomad first: brew install nomad")
-	}
-	fmt.Printf("Found nomad at: %s\n", nomadPath)
-
-	// Check for container CLI
-	containerPath, err := exec.LookPath("container")
-	if err != nil {
-		return fmt.Errorf("container CLI not found. Please ensure macOS 26+ with Apple Containers is installed")
-	}
-	fmt.Printf("Found container CLI at: %s\n", containerPath)
-
-	// Detect local IP
-	ip, err := network.GetPreferredIP()
-	if err != nil {
-		return fmt.Errorf("failed to detect local IP: %w", err)
-	}
-	fmt.Printf("Detected local IP: %s\n", ip)
-
-	// Check Tailscale status for networking
-	tailscale := network.GetTailscaleInfo()
-	if tailscale.Running {
-		fmt.Printf("Tailscale connected: %s (%s)\n", tailscale.DNSName, tailscale.IP)
-		fmt.Println("  Services will be reachable via Tailscale from other nodes")
-		fmt.Println("  Transport encryption provided by Tailscale WireGuard")
-	} else {
-		fmt.Println("Tailscale not connected (cross-node networking will be limited)")
-		fmt.Println("  Install Tailscale: https://tailscale.com/download")
-	}
-
-	// Create container network for service-to-service communication
-	fmt.Println("Creating container network...")
-	if err := network.EnsureStyxNetwork(); err != nil {
-		return fmt.Errorf("failed to create container network: %w", err)
-	}
-	fmt.Printf("Container network ready: %s (%s)\n", network.StyxNetworkName, network.StyxNetworkSubnet)
-
-	// Create directories
-	postgresDataDir := filepath.Join(dataDir, "data", "postgres")
-	rustfsDataDir := filepath.Join(dataDir, "data", "rustfs")
-
-	dirs := []string{
-		dataDir,
-		configDir,
-		logDir,
-		pluginDir,
-		secretsDir,
-		vaultDataDir,
-		postgresDataDir,
-		rustfsDataDir,
-	}
-
-	if err := ensureDirectories(dirs); err != nil {
-		return err
-	}
-
-	// Copy plugin to plugin directory
-	if err := copyPluginToDir(pluginDir); err != nil {
+// runServer starts Styx in server mode (Nomad + Vault + platform services),
+// driving buildServerSteps through a bootstrap.Runner so a partial failure
+// (e.g. Vault initialized but Nomad never came up) can be retried with
+// --resume instead of repeating already-applied side effects. joinServerIP
+// is set when --join-as-server points this server at an already-bootstrapped
+// cluster to join as an additional voting server rather than forming its own;
+// it's empty for the first server in a cluster.
+func runServer(joinServerIP string) error {
+	b := &serverBuild{}
+	stateFile := filepath.Join(dataDir, "state.json")
+	runner := bootstrap.NewRunner(stateFile, resumeInit, buildServerSteps(b, joinServerIP)...)
+	if err := runner.Run(context.Background()); err != nil {
 		return err
 	}
 
-	// Generate Nomad server config
-	fmt.Println("Generating server configuration...")
-	cfg := config.ServerConfig{
-		DataDir:         dataDir,
-		AdvertiseIP:     ip,
-		BootstrapExpect: 1,
-		PluginDir:       pluginDir,
-		CPUTotalCompute: config.GetCPUTotalCompute(),
-	}
-	configContent, err := config.GenerateServerConfig(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to generate config: %w", err)
-	}
-
-	configPath := filepath.Join(configDir, "nomad.hcl")
-	fmt.Printf("Writing Nomad config to: %s\n", configPath)
-	if err := config.WriteConfig(configPath, configContent); err != nil {
-		return fmt.Errorf("failed to write nomad config: %w", err)
-	}
-
-	// Generate Vault config
-	vaultPath, err := exec.LookPath("vault")
-	if err != nil {
-		return fmt.Errorf("vault not found in PATH. Please install vault first: brew install vault")
-	}
-	fmt.Printf("Found vault at: %s\n", vaultPath)
-
-	hostname, err := os.Hostname()
-	if err != nil {
-		return fmt.Errorf("failed to get hostname: %w", err)
-	}
-	nodeID := hostname
-	if nodeID == "" {
-		nodeID = "node1"
-	}
-
-	fmt.Println("Generating Vault configuration (Raft storage)...")
-	vaultCfg := config.VaultConfig{
-		DataDir:     vaultDataDir,
-		NodeID:      nodeID,
-		AdvertiseIP: ip,
-	}
-	vaultConfigContent, err := config.GenerateVaultConfig(vaultCfg)
-	if err != nil {
-		return fmt.Errorf("failed to generate vault config: %w", err)
-	}
-
-	vaultConfigPath := filepath.Join(configDir, "vault.hcl")
-	fmt.Printf("Writing Vault config to: %s\n", vaultConfigPath)
-	if err := config.WriteConfig(vaultConfigPath, vaultConfigContent); err != nil {
-		return fmt.Errorf("failed to write vault config: %w", err)
-	}
-
-	// Create wrapper script that starts Vault and Nomad
-	wrapperPath := filepath.Join(configDir, "styx-agent.sh")
-	wrapperContent := fmt.Sprintf(`#!/bin/bash
-# Styx agent wrapper - starts Vault and Nomad
-set -e
-
-cleanup() {
-    echo "Stopping services..."
-    kill $NOMAD_PID 2>/dev/null || true
-    kill $VAULT_PID 2>/dev/null || true
-    exit 0
-}
-
-trap cleanup SIGTERM SIGINT
-
-VAULT_ADDR="http://127.0.0.1:8200"
-export VAULT_ADDR
-
-# Start Vault
-"%s" server -config="%s" &
-VAULT_PID=$!
-
-# Wait for Vault to be ready
-echo "Waiting for Vault..."
-for i in {1..30}; do
-    if curl -s $VAULT_ADDR/v1/sys/health 2>/dev/null | grep -q .; then
-        echo "Vault is ready"
-        break
-    fi
-    sleep 1
-done
-
-# Auto-unseal Vault if sealed
-INIT_FILE="%s/vault-init.json"
-if [ -f "$INIT_FILE" ]; then
-    # Check if Vault is sealed
-    SEALED=$(curl -s $VAULT_ADDR/v1/sys/health | python3 -c "import sys,json; print(json.load(sys.stdin).get('sealed', False))" 2>/dev/null)
-    if [ "$SEALED" = "True" ]; then
-        echo "Vault is sealed, auto-unsealing..."
-        UNSEAL_KEY=$(python3 -c "import json; print(json.load(open('$INIT_FILE'))['unseal_keys_b64'][0])" 2>/dev/null)
-        if [ -n "$UNSEAL_KEY" ]; then
-            curl -s -X PUT -d "{\"key\":\"$UNSEAL_KEY\"}" $VAULT_ADDR/v1/sys/unseal > /dev/null
-            echo "Vault unsealed"
-        fi
-    fi
-fi
-
-# Start Nomad
-"%s" agent -config="%s/nomad.hcl" &
-NOMAD_PID=$!
-
-# Wait for either to exit
-wait
-`, vaultPath, vaultConfigPath, secretsDir, nomadPath, configDir)
-
-	fmt.Printf("Writing wrapper script to: %s\n", wrapperPath)
-	if err := os.WriteFile(wrapperPath, []byte(wrapperContent), 0755); err != nil {
-		return fmt.Errorf("failed to write wrapper script: %w", err)
-	}
-
-	// Generate and write launchd plist (user agent)
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
-	}
-	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
-	fmt.Printf("Creating launchd plist at: %s\n", plistPath)
-
-	if err := os.MkdirAll(filepath.Dir(plistPath), 0755); err != nil {
-		return fmt.Errorf("failed to create LaunchAgents directory: %w", err)
-	}
-
-	plistCfg := launchd.PlistConfig{
-		Label:      "com.styx.nomad",
-		Program:    "/bin/bash",
-		Args:       []string{wrapperPath},
-		LogPath:    filepath.Join(logDir, "styx.log"),
-		ErrLogPath: filepath.Join(logDir, "styx-error.log"),
-		WorkingDir: configDir,
-	}
-	if err := launchd.WritePlist(plistPath, plistCfg); err != nil {
-		return fmt.Errorf("failed to write plist: %w", err)
-	}
-
-	// Unload if already loaded
-	if launchd.IsLoaded("com.styx.nomad") {
-		fmt.Println("Unloading existing service...")
-		if err := launchd.Unload(plistPath); err != nil {
-			fmt.Printf("Warning: failed to unload existing service: %v\n", err)
-		}
-		time.Sleep(2 * time.Second)
-	}
-
-	// Load the service
-	fmt.Println("Loading launchd service...")
-	if err := launchd.Load(plistPath); err != nil {
-		return fmt.Errorf("failed to load service: %w", err)
-	}
-
-	// Initialize and unseal Vault
-	fmt.Println("Waiting for Vault to become ready...")
-	if err := waitForService("vault", "http://127.0.0.1:8200/v1/sys/health", 30*time.Second, 200, 429, 501, 503); err != nil {
-		return fmt.Errorf("vault failed to start: %w\nCheck logs at %s", err, filepath.Join(logDir, "styx.log"))
-	}
-
-	initialized, err := vault.IsInitialized()
-	if err != nil {
-		return fmt.Errorf("failed to check vault status: %w", err)
-	}
-
-	if !initialized {
-		fmt.Println("Initializing Vault...")
-		_, err = vault.Initialize(secretsDir)
-		if err != nil {
-			return fmt.Errorf("failed to initialize vault: %w", err)
-		}
-	}
-
-	sealed, err := vault.IsSealed()
-	if err != nil {
-		return fmt.Errorf("failed to check vault seal status: %w", err)
-	}
-	if sealed {
-		fmt.Println("Unsealing Vault...")
-		if err := vault.Unseal(secretsDir); err != nil {
-			return fmt.Errorf("failed to unseal vault: %w", err)
-		}
-	}
-
-	fmt.Println("Waiting for Vault to become active...")
-	if err := waitForService("vault", "http://127.0.0.1:8200/v1/sys/health", 60*time.Second); err != nil {
-		return fmt.Errorf("vault failed to become active: %w", err)
-	}
-
-	fmt.Println("Setting up Vault-Nomad integration...")
-	if err := vault.SetupNomadIntegration(secretsDir); err != nil {
-		fmt.Printf("Warning: failed to setup Vault-Nomad integration: %v\n", err)
-		fmt.Println("You can set this up later with 'vault policy write' and 'vault token create'")
-	}
-
-	// Wait for Nomad to become healthy
-	fmt.Println("Waiting for Nomad to become healthy...")
-	if err := waitForService("nomad", "http://127.0.0.1:4646/v1/agent/health", 60*time.Second); err != nil {
-		return fmt.Errorf("nomad failed to start: %w\nCheck logs at %s", err, filepath.Join(logDir, "styx.log"))
-	}
-
-	// Deploy platform services
-	fmt.Println("\nDeploying platform services...")
-	if err := services.DeployAll(); err != nil {
-		return fmt.Errorf("failed to deploy platform services: %w", err)
-	}
-
-	// Enable Tailscale Serve for HTTPS ingress
-	fmt.Println("\nEnabling Tailscale Serve for HTTPS ingress...")
-	if err := tailserve.Enable(); err != nil {
-		fmt.Printf("  Warning: failed to enable Tailscale Serve: %v\n", err)
-		fmt.Println("  Traefik is still available at http://localhost:4200")
+	if joinServerIP != "" {
+		fmt.Println("\nJoined the cluster as an additional server!")
+		fmt.Printf("Existing server: %s\n", joinServerIP)
+		fmt.Println("\nCheck status with:")
+		fmt.Println("  styx status           # Show Styx status")
+		fmt.Println("  nomad server members  # List Nomad servers")
+		fmt.Println("\nNomad UI:  http://127.0.0.1:4646")
+		return nil
 	}
 
-	// Get Tailscale info for displaying ingress URL
 	tsInfo := network.GetTailscaleInfo()
 
 	fmt.Println("\nStyx server started!")
@@ -485,171 +322,16 @@ wait
 	return nil
 }
 
-// runClient joins an existing Styx cluster
-func runClient(serverIP string) error {
-	// Check for nomad binary
-	nomadPath, err := exec.LookPath("nomad")
-	if err != nil {
-		return fmt.Errorf("nomad not found in PATH. Please install nomad first: brew install nomad")
-	}
-	fmt.Printf("Found nomad at: %s\n", nomadPath)
-
-	// Check for container CLI
-	containerPath, err := exec.LookPath("container")
-	if err != nil {
-		return fmt.Errorf("container CLI not found. Please ensure macOS 26+ with Apple Containers is installed")
-	}
-	fmt.Printf("Found container CLI at: %s\n", containerPath)
-
-	// Verify Nomad server is reachable
-	fmt.Printf("Checking Nomad server at %s...\n", serverIP)
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(fmt.Sprintf("http://%s:4646/v1/agent/health", serverIP))
-	if err != nil {
-		return fmt.Errorf("cannot reach Nomad server at %s:4646: %w", serverIP, err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Nomad server at %s is not healthy (status %d)", serverIP, resp.StatusCode)
-	}
-	fmt.Println("Nomad server is reachable and healthy")
-
-	// Detect local IP
-	ip, err := network.GetPreferredIP()
-	if err != nil {
-		return fmt.Errorf("failed to detect local IP: %w", err)
-	}
-	fmt.Printf("Detected local IP: %s\n", ip)
-
-	// Check Tailscale status
-	tailscale := network.GetTailscaleInfo()
-	if tailscale.Running {
-		fmt.Printf("Tailscale connected: %s (%s)\n", tailscale.DNSName, tailscale.IP)
-		fmt.Println("  Services will be reachable via Tailscale from other nodes")
-	} else {
-		fmt.Println("Tailscale not connected (cross-node networking will be limited)")
-	}
-
-	// Create container network for service-to-service communication
-	fmt.Println("Creating container network...")
-	if err := network.EnsureStyxNetwork(); err != nil {
-		return fmt.Errorf("failed to create container network: %w", err)
-	}
-	fmt.Printf("Container network ready: %s (%s)\n", network.StyxNetworkName, network.StyxNetworkSubnet)
-
-	// Create directories
-	dirs := []string{
-		dataDir,
-		configDir,
-		logDir,
-		pluginDir,
-	}
-
-	if err := ensureDirectories(dirs); err != nil {
-		return err
-	}
-
-	// Copy plugin to plugin directory
-	if err := copyPluginToDir(pluginDir); err != nil {
+// runClient joins an existing Styx cluster, driving buildClientSteps
+// through a bootstrap.Runner the same way runServer does.
+func runClient(serverIP, token string) error {
+	b := &clientBuild{}
+	stateFile := filepath.Join(dataDir, "state.json")
+	runner := bootstrap.NewRunner(stateFile, resumeInit, buildClientSteps(b, serverIP, token)...)
+	if err := runner.Run(context.Background()); err != nil {
 		return err
 	}
 
-	// Generate client config
-	fmt.Println("Generating client configuration...")
-	cfg := config.ClientConfig{
-		DataDir:         dataDir,
-		AdvertiseIP:     ip,
-		Servers:         []string{serverIP},
-		PluginDir:       pluginDir,
-		CPUTotalCompute: config.GetCPUTotalCompute(),
-	}
-	configContent, err := config.GenerateClientConfig(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to generate config: %w", err)
-	}
-
-	configPath := filepath.Join(configDir, "nomad.hcl")
-	fmt.Printf("Writing Nomad config to: %s\n", configPath)
-	if err := config.WriteConfig(configPath, configContent); err != nil {
-		return fmt.Errorf("failed to write nomad config: %w", err)
-	}
-
-	// Create wrapper script
-	wrapperPath := filepath.Join(configDir, "styx-agent.sh")
-	wrapperContent := fmt.Sprintf(`#!/bin/bash
-# Styx agent wrapper - starts Nomad
-set -e
-
-cleanup() {
-    echo "Stopping services..."
-    kill $NOMAD_PID 2>/dev/null || true
-    exit 0
-}
-
-trap cleanup SIGTERM SIGINT
-
-# Start Nomad
-"%s" agent -config="%s/nomad.hcl" &
-NOMAD_PID=$!
-
-# Wait for exit
-wait
-`, nomadPath, configDir)
-
-	fmt.Printf("Writing wrapper script to: %s\n", wrapperPath)
-	if err := os.WriteFile(wrapperPath, []byte(wrapperContent), 0755); err != nil {
-		return fmt.Errorf("failed to write wrapper script: %w", err)
-	}
-
-	// Generate and write launchd plist
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
-	}
-	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
-	fmt.Printf("Creating launchd plist at: %s\n", plistPath)
-
-	if err := os.MkdirAll(filepath.Dir(plistPath), 0755); err != nil {
-		return fmt.Errorf("failed to create LaunchAgents directory: %w", err)
-	}
-
-	plistCfg := launchd.PlistConfig{
-		Label:      "com.styx.nomad",
-		Program:    "/bin/bash",
-		Args:       []string{wrapperPath},
-		LogPath:    filepath.Join(logDir, "styx.log"),
-		ErrLogPath: filepath.Join(logDir, "styx-error.log"),
-		WorkingDir: configDir,
-	}
-	if err := launchd.WritePlist(plistPath, plistCfg); err != nil {
-		return fmt.Errorf("failed to write plist: %w", err)
-	}
-
-	// Unload if already loaded
-	if launchd.IsLoaded("com.styx.nomad") {
-		fmt.Println("Unloading existing service...")
-		if err := launchd.Unload(plistPath); err != nil {
-			fmt.Printf("Warning: failed to unload existing service: %v\n", err)
-		}
-		time.Sleep(2 * time.Second)
-	}
-
-	// Load the service
-	fmt.Println("Loading launchd service...")
-	if err := launchd.Load(plistPath); err != nil {
-		return fmt.Errorf("failed to load service: %w", err)
-	}
-
-	// Wait for Nomad to become healthy locally
-	fmt.Println("Waiting for Nomad client to start...")
-	if err := waitForService("nomad", "http://127.0.0.1:4646/v1/agent/health", 60*time.Second); err != nil {
-		return fmt.Errorf("nomad failed to start: %w\nCheck logs at %s", err, filepath.Join(logDir, "styx.log"))
-	}
-
-	// Wait for client to register with server
-	fmt.Println("Waiting for client to register with server...")
-	time.Sleep(5 * time.Second)
-
 	fmt.Println("\nSuccessfully joined the cluster!")
 	fmt.Printf("Server: %s\n", serverIP)
 	fmt.Println("\nCheck status with:")
@@ -763,7 +445,8 @@ func ensureVaultUnsealed() error {
 
 	if sealed {
 		fmt.Println("Vault is sealed, unsealing...")
-		if err := vault.Unseal(secretsDir); err != nil {
+		unsealer := &vault.Unsealer{SecretsDir: secretsDir}
+		if err := unsealer.Unseal(); err != nil {
 			return fmt.Errorf("failed to unseal vault: %w", err)
 		}
 		fmt.Println("Vault unsealed successfully")
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kessler-frost/styx/internal/intents"
+	"github.com/kessler-frost/styx/internal/services"
+	"github.com/spf13/cobra"
+)
+
+var intentCmd = &cobra.Command{
+	Use:   "intent",
+	Short: "Manage service-to-service traffic intentions",
+	Long: `Declare which platform services may reach each other, modeled after
+Consul service intentions. Intentions are stored in <config-dir>/intents.hcl
+and compiled into Traefik ipallowlist middleware tags (derived from Nomad
+service registrations) the next time the destination service deploys.`,
+}
+
+var intentAllowCmd = &cobra.Command{
+	Use:   "allow <source> <destination>",
+	Short: "Allow <source> to reach <destination>",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runIntentSet("allow"),
+}
+
+var intentDenyCmd = &cobra.Command{
+	Use:   "deny <source> <destination>",
+	Short: "Deny <source> from reaching <destination>",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runIntentSet("deny"),
+}
+
+var intentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured intentions",
+	RunE:  runIntentList,
+}
+
+func init() {
+	intentCmd.AddCommand(intentAllowCmd)
+	intentCmd.AddCommand(intentDenyCmd)
+	intentCmd.AddCommand(intentListCmd)
+	rootCmd.AddCommand(intentCmd)
+}
+
+// runIntentSet returns a RunE that records an allow/deny intent and, if its
+// destination is a known platform service that's currently running,
+// redeploys it so the new intent takes effect immediately.
+func runIntentSet(action string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		source, destination := args[0], args[1]
+
+		if _, err := intents.Upsert(services.IntentsPath, source, destination, action); err != nil {
+			return fmt.Errorf("failed to save intent: %w", err)
+		}
+		fmt.Printf("%s -> %s: %s\n", source, destination, action)
+
+		if services.GetService(destination) == nil {
+			return nil
+		}
+
+		client := services.DefaultClient()
+		if !client.IsHealthy() {
+			return nil
+		}
+		status, err := client.GetJobStatus(destination)
+		if err != nil || status == nil {
+			return nil
+		}
+
+		fmt.Printf("Redeploying %s with updated intentions...\n", destination)
+		if err := services.Deploy(destination); err != nil {
+			return fmt.Errorf("intent saved but failed to redeploy %s: %w", destination, err)
+		}
+		return nil
+	}
+}
+
+func runIntentList(cmd *cobra.Command, args []string) error {
+	list, err := intents.Load(services.IntentsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load intents: %w", err)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No intentions configured; all services can reach each other")
+		return nil
+	}
+
+	fmt.Println("Intentions")
+	fmt.Println("----------")
+	for _, in := range list {
+		fmt.Printf("  %-6s %s -> %s\n", in.Action, in.Source, in.Destination)
+	}
+	return nil
+}
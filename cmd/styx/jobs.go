@@ -10,22 +10,42 @@ import (
 )
 
 var jobsJSON bool
+var jobsFilter string
+var jobsPage int
+var jobsPerPage int
+var jobsFields []string
 
 var jobsCmd = &cobra.Command{
 	Use:   "jobs",
 	Short: "List Nomad jobs",
-	Long:  `Display all running Nomad jobs and their allocations.`,
-	RunE:  runJobs,
+	Long: `Display all running Nomad jobs and their allocations.
+
+Use --filter to narrow the list with an expression over Job/Alloc fields, e.g.:
+  styx jobs --filter 'Status == "dead"' --json
+  styx jobs --filter 'Type != "batch" and Status == "running"'
+
+Use --page/--per-page to paginate a long job list, and --fields to print
+just the named fields instead of the full Job object, e.g.:
+  styx jobs --per-page 20 --page 2 --fields Name,Status --json`,
+	RunE: runJobs,
 }
 
 func init() {
 	jobsCmd.Flags().BoolVar(&jobsJSON, "json", false, "Output in JSON format")
+	jobsCmd.Flags().StringVar(&jobsFilter, "filter", "", "Filter expression (e.g. 'Status==\"dead\"')")
+	jobsCmd.Flags().IntVar(&jobsPage, "page", 0, "Page of results to show, 1-based (requires --per-page)")
+	jobsCmd.Flags().IntVar(&jobsPerPage, "per-page", 0, "Number of results per page")
+	jobsCmd.Flags().StringSliceVar(&jobsFields, "fields", nil, "Only print these fields (comma-separated, --json only)")
 	rootCmd.AddCommand(jobsCmd)
 }
 
 func runJobs(cmd *cobra.Command, args []string) error {
 	client := api.NewClient()
-	jobs, err := client.GetJobs()
+	jobs, err := client.GetJobsWithOptions(api.ListOptions{
+		Filter:  jobsFilter,
+		Page:    jobsPage,
+		PerPage: jobsPerPage,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to get jobs: %w", err)
 	}
@@ -42,6 +62,9 @@ func runJobs(cmd *cobra.Command, args []string) error {
 	if jobsJSON {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
+		if rows := api.SelectFields(jobs, jobsFields); rows != nil {
+			return enc.Encode(rows)
+		}
 		return enc.Encode(jobs)
 	}
 
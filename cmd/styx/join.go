@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/kessler-frost/styx/internal/bootstrap"
 	"github.com/kessler-frost/styx/internal/config"
 	"github.com/kessler-frost/styx/internal/launchd"
 	"github.com/kessler-frost/styx/internal/network"
@@ -15,13 +17,17 @@ import (
 )
 
 var joinCmd = &cobra.Command{
-	Use:   "join <server-ip>",
+	Use:   "join [server-ip]",
 	Short: "Join an existing Styx cluster",
 	Long: `Join an existing Styx cluster as a client node.
 
 The server-ip argument should be the IP address of an existing Styx server node.
+If omitted, styx auto-discovers a live bootstrap server over Tailscale (by the
+styx-bootstrap- hostname convention) and LAN mDNS, and joins the fastest one
+that answers.
+
 This node will register with the server and be available to run workloads.`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: runJoin,
 }
 
@@ -30,7 +36,16 @@ func init() {
 }
 
 func runJoin(cmd *cobra.Command, args []string) error {
-	serverIP := args[0]
+	var serverIP string
+	if len(args) == 1 {
+		serverIP = args[0]
+	} else {
+		discovered, err := discoverServerIP()
+		if err != nil {
+			return err
+		}
+		serverIP = discovered
+	}
 
 	// Check if already running and healthy
 	if launchd.IsLoaded("com.styx.nomad") {
@@ -145,34 +160,13 @@ func runJoin(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to write nomad config: %w", err)
 	}
 
-	// Create wrapper script that starts Nomad
-	wrapperPath := filepath.Join(configDir, "styx-agent.sh")
-	wrapperContent := fmt.Sprintf(`#!/bin/bash
-# Styx agent wrapper - starts Nomad
-set -e
-
-cleanup() {
-    echo "Stopping services..."
-    kill $NOMAD_PID 2>/dev/null || true
-    exit 0
-}
-
-trap cleanup SIGTERM SIGINT
-
-# Start Nomad
-"%s" agent -config="%s/nomad.hcl" &
-NOMAD_PID=$!
-
-# Wait for exit
-wait
-`, nomadPath, configDir)
-
-	fmt.Printf("Writing wrapper script to: %s\n", wrapperPath)
-	if err := os.WriteFile(wrapperPath, []byte(wrapperContent), 0755); err != nil {
-		return fmt.Errorf("failed to write wrapper script: %w", err)
+	// Generate and write launchd plist (user agent), pointing it at `styx
+	// supervise` instead of a generated shell wrapper script.
+	styxBinPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve styx binary path: %w", err)
 	}
 
-	// Generate and write launchd plist (user agent)
 	home, _ := os.UserHomeDir()
 	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
 	fmt.Printf("Creating launchd plist at: %s\n", plistPath)
@@ -184,11 +178,17 @@ wait
 
 	plistCfg := launchd.PlistConfig{
 		Label:      "com.styx.nomad",
-		Program:    "/bin/bash",
-		Args:       []string{wrapperPath},
+		Program:    styxBinPath,
+		Args:       []string{"supervise", "--role=client", "--data-dir=" + dataDir, "--config-dir=" + configDir, "--secrets-dir=" + secretsDir, "--certs-dir=" + certsDir, "--log-dir=" + logDir},
 		LogPath:    filepath.Join(logDir, "styx.log"),
 		ErrLogPath: filepath.Join(logDir, "styx-error.log"),
 		WorkingDir: configDir,
+		KeepAlive: &launchd.KeepAliveConfig{
+			SuccessfulExit: false,
+			Crashed:        true,
+			NetworkState:   true,
+		},
+		ThrottleInterval: 10,
 	}
 	if err := launchd.WritePlist(plistPath, plistCfg); err != nil {
 		return fmt.Errorf("failed to write plist: %w", err)
@@ -228,3 +228,25 @@ wait
 
 	return nil
 }
+
+// discoverServerIP auto-selects a bootstrap server when the user runs
+// `styx join` with no arguments, by probing Tailscale peers matching the
+// styx-bootstrap- hostname convention and LAN mDNS candidates.
+func discoverServerIP() (string, error) {
+	fmt.Println("No server IP given, discovering bootstrap servers...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	candidates, err := bootstrap.DiscoverServers(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover bootstrap servers: %w", err)
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no bootstrap server found automatically; pass one explicitly: styx join <server-ip>")
+	}
+
+	best := candidates[0]
+	fmt.Printf("Found bootstrap server %s (%s), RTT %s\n", best.Name, best.IP, best.RTT)
+	return best.IP, nil
+}
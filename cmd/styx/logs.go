@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsFollow bool
+	logsStderr bool
+	logsTail   int
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [-f] [--stderr] [--tail N] <alloc-id> <task>",
+	Short: "Show or tail logs for an allocation's task",
+	Long: `Display the stdout (or --stderr) log for a task running in a Nomad allocation.
+
+Use --tail N to show the last N lines before exiting, or -f to follow new
+output. Combining both shows the last N lines and then keeps streaming.
+While following, styx reconnects automatically if the allocation restarts.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Follow log output")
+	logsCmd.Flags().BoolVar(&logsStderr, "stderr", false, "Show stderr instead of stdout")
+	logsCmd.Flags().IntVar(&logsTail, "tail", 0, "Number of lines to show from the end of the log")
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	allocID, task := args[0], args[1]
+	client := api.NewClient()
+
+	if logsTail > 0 {
+		data, err := client.TailAllocLogs(allocID, task, logsTail, logsStderr)
+		if err != nil {
+			return fmt.Errorf("failed to fetch log tail: %w", err)
+		}
+		os.Stdout.Write(data)
+	}
+
+	if !logsFollow {
+		return nil
+	}
+
+	return followAllocLogs(client, allocID, task)
+}
+
+// followAllocLogs streams an allocation's task log to stdout, reconnecting
+// with backoff when the stream drops (e.g. because the allocation was
+// rescheduled onto a replacement with the same ID but a different node).
+func followAllocLogs(client *api.Client, allocID, task string) error {
+	backoff := time.Second
+
+	for {
+		nodeName := allocNodeName(client, allocID)
+
+		var (
+			stream io.ReadCloser
+			err    error
+		)
+		if logsStderr {
+			stream, err = client.StreamAllocStderr(allocID, task, true)
+		} else {
+			stream, err = client.StreamAllocLogs(allocID, task, true)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "styx logs: %v (node=%s), retrying in %s\n", err, nodeName, backoff)
+			time.Sleep(backoff)
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+			continue
+		}
+
+		backoff = time.Second
+		_, copyErr := io.Copy(os.Stdout, stream)
+		stream.Close()
+
+		if copyErr != nil && copyErr != io.EOF {
+			fmt.Fprintf(os.Stderr, "styx logs: stream interrupted: %v, reconnecting...\n", copyErr)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		return nil
+	}
+}
+
+// allocNodeName looks up the node an allocation is running on, for
+// informational messages when a reconnect is needed.
+func allocNodeName(client *api.Client, allocID string) string {
+	allocs, err := client.GetAllocs(fmt.Sprintf(`ID == "%s"`, allocID))
+	if err != nil || len(allocs) == 0 {
+		return "unknown"
+	}
+	return allocs[0].NodeName
+}
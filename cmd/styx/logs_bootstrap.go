@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var logsBootstrapFollow bool
+
+var logsBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Tail the bootstrap server's structured access log",
+	Long: `Display the bootstrap server's JSON access log, written by
+'styx bootstrap-server' to <log-dir>/bootstrap-access.log.
+
+Use -f to follow new entries as they're written, similar to 'tail -f'.`,
+	RunE: runLogsBootstrap,
+}
+
+func init() {
+	logsBootstrapCmd.Flags().BoolVarP(&logsBootstrapFollow, "follow", "f", false, "Follow log output")
+	logsCmd.AddCommand(logsBootstrapCmd)
+}
+
+func runLogsBootstrap(cmd *cobra.Command, args []string) error {
+	path := filepath.Join(logDir, "bootstrap-access.log")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open bootstrap access log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(os.Stdout, f); err != nil {
+		return fmt.Errorf("failed to read bootstrap access log: %w", err)
+	}
+
+	if !logsBootstrapFollow {
+		return nil
+	}
+
+	return followFile(f)
+}
+
+// followFile polls f for newly appended lines and writes them to stdout,
+// similar to 'tail -f'. It does not handle rotation - a rotated bootstrap
+// access log requires re-running the command.
+func followFile(f *os.File) error {
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			fmt.Print(line)
+		}
+		if err == io.EOF {
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -10,22 +10,41 @@ import (
 )
 
 var nodesJSON bool
+var nodesFilter string
+var nodesPage int
+var nodesPerPage int
+var nodesFields []string
 
 var nodesCmd = &cobra.Command{
 	Use:   "nodes",
 	Short: "List cluster nodes",
-	Long:  `Display all Nomad client nodes in the cluster.`,
-	RunE:  runNodes,
+	Long: `Display all Nomad client nodes in the cluster.
+
+Use --filter to narrow the list with an expression over Node fields, e.g.:
+  styx nodes --filter 'Status == "down"' --json
+
+Use --page/--per-page to paginate a large cluster's node list, and --fields
+to print just the named fields instead of the full Node object, e.g.:
+  styx nodes --per-page 20 --page 2 --fields Name,Address --json`,
+	RunE: runNodes,
 }
 
 func init() {
 	nodesCmd.Flags().BoolVar(&nodesJSON, "json", false, "Output in JSON format")
+	nodesCmd.Flags().StringVar(&nodesFilter, "filter", "", "Filter expression (e.g. 'Status==\"down\"')")
+	nodesCmd.Flags().IntVar(&nodesPage, "page", 0, "Page of results to show, 1-based (requires --per-page)")
+	nodesCmd.Flags().IntVar(&nodesPerPage, "per-page", 0, "Number of results per page")
+	nodesCmd.Flags().StringSliceVar(&nodesFields, "fields", nil, "Only print these fields (comma-separated, --json only)")
 	rootCmd.AddCommand(nodesCmd)
 }
 
 func runNodes(cmd *cobra.Command, args []string) error {
 	client := api.NewClient()
-	nodes, err := client.GetNodes()
+	nodes, err := client.GetNodesWithOptions(api.ListOptions{
+		Filter:  nodesFilter,
+		Page:    nodesPage,
+		PerPage: nodesPerPage,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to get nodes: %w", err)
 	}
@@ -42,6 +61,9 @@ func runNodes(cmd *cobra.Command, args []string) error {
 	if nodesJSON {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
+		if rows := api.SelectFields(nodes, nodesFields); rows != nil {
+			return enc.Encode(rows)
+		}
 		return enc.Encode(nodes)
 	}
 
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kessler-frost/styx/internal/services"
+	"github.com/spf13/cobra"
+)
+
+var observabilityCmd = &cobra.Command{
+	Use:   "observability",
+	Short: "Manage the observability stack (metrics, logs, dashboards)",
+}
+
+var observabilityEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Deploy the full node-exporter/cadvisor/loki/grafana/prometheus stack",
+	Long: `Deploy node-exporter, cAdvisor, Loki, Grafana and Prometheus in one shot.
+
+Prometheus auto-discovers anything tagged prometheus.scrape=true via Nomad
+service discovery; tag a service prometheus.path=<path> or
+prometheus.scheme=<scheme> if it doesn't expose metrics at the default
+"/metrics" over http (see 'styx service edit prometheus').`,
+	RunE: runObservabilityEnable,
+}
+
+func init() {
+	observabilityCmd.AddCommand(observabilityEnableCmd)
+	rootCmd.AddCommand(observabilityCmd)
+}
+
+func runObservabilityEnable(cmd *cobra.Command, args []string) error {
+	client := services.DefaultClient()
+	if !client.IsHealthy() {
+		return fmt.Errorf("Nomad is not running. Start Styx first with 'styx init'")
+	}
+
+	fmt.Println("Deploying observability stack...")
+	if err := services.DeployObservability(); err != nil {
+		return fmt.Errorf("failed to deploy observability stack: %w", err)
+	}
+
+	fmt.Println("Observability stack deployed successfully")
+	return nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/launchd"
+	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/pki"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pkiNode     string
+	pkiRole     string
+	pkiWatch    bool
+	pkiRotateCA bool
+)
+
+var pkiCmd = &cobra.Command{
+	Use:   "pki",
+	Short: "Manage the cluster's internal mTLS certificate authority",
+	Long:  `Issue and rotate the CA and leaf certificates ServerConfig/ClientConfig/ConsulServerConfig/ConsulClientConfig expect at CAFile/CertFile/KeyFile.`,
+}
+
+var pkiIssueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Issue a leaf certificate for a node",
+	Long:  `Load (or create) the cluster CA and issue a leaf certificate for --node, with SANs covering its role's Nomad/Consul RPC names, localhost, and its advertise IP.`,
+	RunE:  runPKIIssue,
+}
+
+var pkiRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Re-issue this node's leaf certificate if it's close to expiring",
+	Long: `Check this node's leaf certificate and re-issue it if it's within
+RenewBefore of expiring. With --watch, keep doing this in the background
+and reload Nomad after each re-issue, the way 'styx supervise' runs it
+alongside Vault and Nomad.
+
+With --rotate-ca, generate a brand new root CA first - every leaf
+certificate issued under the old CA stops verifying immediately, so this
+re-issues this node's own leaf in the same run but does not reach out to
+any other node; run 'styx pki rotate --rotate-ca' on every other node in
+the cluster right after.`,
+	RunE: runPKIRotate,
+}
+
+func init() {
+	pkiIssueCmd.Flags().StringVar(&pkiNode, "node", "", "Node name the certificate is issued for (required)")
+	pkiIssueCmd.Flags().StringVar(&pkiRole, "role", "", "Node role: server or client (required)")
+	pkiIssueCmd.MarkFlagRequired("node")
+	pkiIssueCmd.MarkFlagRequired("role")
+
+	pkiRotateCmd.Flags().StringVar(&pkiNode, "node", "", "Node name the certificate was issued for (required)")
+	pkiRotateCmd.Flags().StringVar(&pkiRole, "role", string(pki.RoleServer), "Node role: server or client")
+	pkiRotateCmd.Flags().BoolVar(&pkiWatch, "watch", false, "Keep checking and reload Nomad after each re-issue, until interrupted")
+	pkiRotateCmd.Flags().BoolVar(&pkiRotateCA, "rotate-ca", false, "Generate a brand new root CA before re-issuing this node's leaf certificate")
+	pkiRotateCmd.MarkFlagRequired("node")
+
+	pkiCmd.AddCommand(pkiIssueCmd)
+	pkiCmd.AddCommand(pkiRotateCmd)
+	rootCmd.AddCommand(pkiCmd)
+}
+
+func runPKIIssue(cmd *cobra.Command, args []string) error {
+	if pkiRole != pki.RoleServer && pkiRole != pki.RoleClient {
+		return fmt.Errorf("invalid --role %q, want %q or %q", pkiRole, pki.RoleServer, pki.RoleClient)
+	}
+
+	ip, err := network.GetPreferredIP()
+	if err != nil {
+		return fmt.Errorf("failed to detect local IP: %w", err)
+	}
+
+	ca, err := pki.LoadOrCreateCA(certsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load or create CA: %w", err)
+	}
+
+	certs, err := pki.Issue(ca, certsDir, pki.IssueOptions{
+		NodeName:    pkiNode,
+		Role:        pkiRole,
+		AdvertiseIP: ip,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to issue leaf certificate: %w", err)
+	}
+
+	fmt.Printf("Issued %s certificate for %s:\n", pkiRole, pkiNode)
+	fmt.Printf("  CA:   %s\n", certs.CAFile)
+	fmt.Printf("  Cert: %s\n", certs.CertFile)
+	fmt.Printf("  Key:  %s\n", certs.KeyFile)
+	return nil
+}
+
+func runPKIRotate(cmd *cobra.Command, args []string) error {
+	if pkiRole != pki.RoleServer && pkiRole != pki.RoleClient {
+		return fmt.Errorf("invalid --role %q, want %q or %q", pkiRole, pki.RoleServer, pki.RoleClient)
+	}
+	if pkiRotateCA && pkiWatch {
+		return fmt.Errorf("--rotate-ca is a one-shot operator action, it can't be combined with --watch")
+	}
+
+	ip, err := network.GetPreferredIP()
+	if err != nil {
+		return fmt.Errorf("failed to detect local IP: %w", err)
+	}
+
+	if pkiRotateCA {
+		if _, err := pki.RotateCA(certsDir); err != nil {
+			return fmt.Errorf("failed to rotate CA: %w", err)
+		}
+		fmt.Println("Root CA rotated - re-issue and redeploy every other node's leaf certificate with 'styx pki rotate --rotate-ca'")
+	}
+
+	renewer := &pki.Renewer{
+		CADir:    certsDir,
+		CertsDir: certsDir,
+		Opts: pki.IssueOptions{
+			NodeName:    pkiNode,
+			Role:        pkiRole,
+			AdvertiseIP: ip,
+		},
+		Reload: reloadNomad,
+	}
+
+	if !pkiWatch {
+		// A CA rotation invalidates the leaf regardless of its expiry, so
+		// force a re-issue instead of RenewIfNeeded's "only if expiring soon"
+		// check.
+		if pkiRotateCA {
+			ca, err := pki.LoadOrCreateCA(certsDir)
+			if err != nil {
+				return fmt.Errorf("failed to load rotated CA: %w", err)
+			}
+			if _, err := pki.Issue(ca, certsDir, renewer.Opts); err != nil {
+				return fmt.Errorf("failed to re-issue leaf certificate under rotated CA: %w", err)
+			}
+			if err := reloadNomad(); err != nil {
+				return fmt.Errorf("leaf certificate re-issued but reload failed: %w", err)
+			}
+			fmt.Println("Leaf certificate re-issued under the rotated CA")
+			return nil
+		}
+
+		if err := renewer.RenewIfNeeded(); err != nil {
+			return fmt.Errorf("failed to rotate leaf certificate: %w", err)
+		}
+		fmt.Println("Leaf certificate checked (re-issued if it was close to expiring)")
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Watching %s's %s certificate, checking every %s\n", pkiNode, pkiRole, time.Hour)
+	if err := renewer.Run(ctx); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("pki renewer exited: %w", err)
+	}
+
+	fmt.Println("PKI renewer stopped")
+	return nil
+}
+
+// reloadNomad is the Renewer.Reload callback used outside of tests: it
+// restarts the com.styx.nomad launchd job so Nomad picks up the re-issued
+// certificate, the same reload path runServer uses after rotating the
+// nomad-cluster Vault token.
+func reloadNomad() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
+	return launchd.Reload("com.styx.nomad", plistPath)
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/bootstrap"
+	styxtls "github.com/kessler-frost/styx/internal/tls"
+	"github.com/spf13/cobra"
+)
+
+var (
+	regionServers           string
+	regionAuthoritativeIP   string
+	regionAuthoritativeName string
+	regionToken             string
+)
+
+var regionCmd = &cobra.Command{
+	Use:   "region",
+	Short: "Manage federated Nomad regions",
+	Long: `Nomad federates regions over the same serf gossip pool client/server
+agents already use for a single region - joining a remote region's servers
+is enough, there's no separate WAN gossip stanza the way Consul has one.
+'styx region add' wires up a second region: it mints that region's own
+Nomad CA, exchanges it with the authoritative region over the mTLS
+bootstrap server, and joins the two regions' serf pools together.`,
+}
+
+var regionAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Federate a new Nomad region with the authoritative region",
+	Long: `Add a new federated Nomad region named <name>.
+
+This generates a region-scoped Nomad CA for <name>, exchanges CA roots with
+the authoritative region over its mTLS bootstrap server (--authoritative-ip,
+--authoritative-region, --token - the same join token 'styx token create'
+mints), and joins this region's servers (--servers) into the authoritative
+region's serf pool so both sides discover each other.
+
+Run this on a server in the new region. Afterwards, add the region to
+config.ServerConfig.Regions on every server in both regions (via
+'styx init --join-as-server' or a config regen) and restart Nomad so the
+server_join stanza picks up the federated peers.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRegionAdd,
+}
+
+var regionStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show per-region leader, Raft peers, and member status",
+	RunE:  runRegionStatus,
+}
+
+var regionRemoveCmd = &cobra.Command{
+	Use:   "remove <node-name>",
+	Short: "Force a stale region member out of the serf pool",
+	Long: `Force-leave a server that node status/raft configuration still lists
+for a federated region but that's gone for good (decommissioned, replaced
+under a new IP), the same case 'consul force-leave' exists for. Use the
+Node name from 'styx region status', not an IP.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRegionRemove,
+}
+
+func init() {
+	regionAddCmd.Flags().StringVar(&regionServers, "servers", "", "Comma-separated server IPs in the new region")
+	regionAddCmd.Flags().StringVar(&regionAuthoritativeIP, "authoritative-ip", "", "Tailscale IP of a server in the authoritative region")
+	regionAddCmd.Flags().StringVar(&regionAuthoritativeName, "authoritative-region", "global", "Name of the authoritative region")
+	regionAddCmd.Flags().StringVar(&regionToken, "token", "", "Join token minted by 'styx token create' on the authoritative region (or set STYX_JOIN_TOKEN)")
+	regionAddCmd.MarkFlagRequired("servers")
+	regionAddCmd.MarkFlagRequired("authoritative-ip")
+
+	regionCmd.AddCommand(regionAddCmd)
+	regionCmd.AddCommand(regionStatusCmd)
+	regionCmd.AddCommand(regionRemoveCmd)
+	rootCmd.AddCommand(regionCmd)
+}
+
+func runRegionRemove(cmd *cobra.Command, args []string) error {
+	if err := nomadForceLeave(args[0]); err != nil {
+		return fmt.Errorf("failed to force-leave %s: %w", args[0], err)
+	}
+	fmt.Printf("Forced %s out of the serf pool.\n", args[0])
+	return nil
+}
+
+func runRegionAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	servers := strings.Split(regionServers, ",")
+	for i := range servers {
+		servers[i] = strings.TrimSpace(servers[i])
+	}
+
+	token := regionToken
+	if token == "" {
+		token = joinToken
+	}
+	if token == "" {
+		return fmt.Errorf("a join token is required: pass --token or set STYX_JOIN_TOKEN")
+	}
+
+	fmt.Printf("Generating Nomad CA for region %q...\n", name)
+	if err := styxtls.GenerateRegionCA(certsDir, name); err != nil {
+		return fmt.Errorf("failed to generate region CA: %w", err)
+	}
+
+	fmt.Printf("Exchanging CA roots with authoritative region %q at %s...\n", regionAuthoritativeName, regionAuthoritativeIP)
+	if err := bootstrap.PushRegionCA(regionAuthoritativeIP, name, certsDir, token); err != nil {
+		return fmt.Errorf("failed to push %s's CA to the authoritative region: %w", name, err)
+	}
+	if err := bootstrap.FetchRegionCA(regionAuthoritativeIP, regionAuthoritativeName, certsDir, token); err != nil {
+		return fmt.Errorf("failed to fetch authoritative region's CA: %w", err)
+	}
+
+	fmt.Println("Joining serf pools...")
+	if err := nomadAgentJoin(servers); err != nil {
+		return fmt.Errorf("failed to join %s's servers into the local serf pool: %w", name, err)
+	}
+
+	fmt.Println("Verifying both regions see each other...")
+	regions, err := nomadRegions("http://127.0.0.1:4646")
+	if err != nil {
+		return fmt.Errorf("failed to list known regions: %w", err)
+	}
+
+	hasRemote, hasLocal := false, false
+	for _, r := range regions {
+		if r == name {
+			hasRemote = true
+		}
+		if r == regionAuthoritativeName {
+			hasLocal = true
+		}
+	}
+	if !hasRemote || !hasLocal {
+		return fmt.Errorf("region federation incomplete: /v1/regions reports %v, expected both %q and %q - servers may still be gossiping, check again with 'styx region status'", regions, name, regionAuthoritativeName)
+	}
+
+	fmt.Printf("Region %q is federated with %q.\n", name, regionAuthoritativeName)
+	fmt.Println("Add it to config.ServerConfig.Regions and restart Nomad on every server in both regions to pick up the server_join stanza.")
+	return nil
+}
+
+// nomadAgentJoin asks the local Nomad agent to retry_join addrs into its
+// serf pool immediately, via the same /v1/agent/join endpoint Consul's
+// agent exposes - used instead of shelling out to `nomad server join` so
+// this stays testable and dependency-free.
+func nomadAgentJoin(addrs []string) error {
+	q := url.Values{}
+	for _, a := range addrs {
+		q.Add("address", a)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(fmt.Sprintf("http://127.0.0.1:4646/v1/agent/join?%s", q.Encode()), "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		NumJoined int    `json:"num_joined"`
+		Error     string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode join response: %w", err)
+	}
+	if result.Error != "" {
+		return fmt.Errorf("%s", result.Error)
+	}
+	if result.NumJoined == 0 {
+		return fmt.Errorf("joined 0 of %d addresses", len(addrs))
+	}
+	return nil
+}
+
+// nomadForceLeave asks the local Nomad agent to force a stale member out of
+// its serf pool, the counterpart to nomadAgentJoin for removing a region
+// that's being decommissioned.
+func nomadForceLeave(node string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(fmt.Sprintf("http://127.0.0.1:4646/v1/agent/force-leave?node=%s", url.QueryEscape(node)), "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func nomadRegions(addr string) ([]string, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(addr + "/v1/regions")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var regions []string
+	if err := json.NewDecoder(resp.Body).Decode(&regions); err != nil {
+		return nil, fmt.Errorf("failed to decode regions response: %w", err)
+	}
+	return regions, nil
+}
+
+type raftConfiguration struct {
+	Servers []struct {
+		Node   string `json:"Node"`
+		Leader bool   `json:"Leader"`
+	} `json:"Servers"`
+}
+
+type agentMember struct {
+	Name   string `json:"Name"`
+	Status string `json:"Status"`
+}
+
+func runRegionStatus(cmd *cobra.Command, args []string) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	regions, err := nomadRegions("http://127.0.0.1:4646")
+	if err != nil {
+		return fmt.Errorf("failed to list regions: %w", err)
+	}
+	if len(regions) == 0 {
+		fmt.Println("No regions known. Is Nomad running?")
+		return nil
+	}
+
+	fmt.Println("Regions")
+	fmt.Println("-------")
+
+	for _, region := range regions {
+		fmt.Printf("\n%s\n", region)
+
+		leaderResp, err := client.Get(fmt.Sprintf("http://127.0.0.1:4646/v1/status/leader?region=%s", region))
+		if err != nil {
+			fmt.Printf("  leader:  error: %v\n", err)
+		} else {
+			var leader string
+			json.NewDecoder(leaderResp.Body).Decode(&leader)
+			leaderResp.Body.Close()
+			if leader == "" {
+				leader = "(no leader)"
+			}
+			fmt.Printf("  leader:  %s\n", leader)
+		}
+
+		raftResp, err := client.Get(fmt.Sprintf("http://127.0.0.1:4646/v1/operator/raft/configuration?region=%s", region))
+		if err != nil {
+			fmt.Printf("  raft:    error: %v\n", err)
+		} else {
+			var raftCfg raftConfiguration
+			json.NewDecoder(raftResp.Body).Decode(&raftCfg)
+			raftResp.Body.Close()
+			for _, s := range raftCfg.Servers {
+				marker := " "
+				if s.Leader {
+					marker = "*"
+				}
+				fmt.Printf("  peer:  %s %s\n", marker, s.Node)
+			}
+		}
+
+		membersResp, err := client.Get(fmt.Sprintf("http://127.0.0.1:4646/v1/agent/members?region=%s", region))
+		if err != nil {
+			fmt.Printf("  members: error: %v\n", err)
+			continue
+		}
+		var members struct {
+			Members []agentMember `json:"Members"`
+		}
+		json.NewDecoder(membersResp.Body).Decode(&members)
+		membersResp.Body.Close()
+		for _, m := range members.Members {
+			fmt.Printf("  member:  %-20s %s\n", m.Name, m.Status)
+		}
+	}
+
+	return nil
+}
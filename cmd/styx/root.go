@@ -4,7 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/kessler-frost/styx/internal/authz"
+	"github.com/kessler-frost/styx/internal/services"
+	"github.com/kessler-frost/styx/internal/setup"
+	"github.com/kessler-frost/styx/internal/tracing"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +24,49 @@ var (
 	logDir       string
 	secretsDir   string
 	vaultDataDir string
+	certsDir     string
+
+	// styxBaseDir is ~/.styx, the parent of every other directory flag above.
+	// Kept around so Traefik's TLS state (see styxBaseDir/traefik/...) doesn't
+	// need its own directory flag.
+	styxBaseDir string
+
+	// packageManager overrides the auto-detected setup.PackageManager backend.
+	packageManager string
+
+	// diagnosticAddr, if set, binds a diagnostic.Server exposing /metrics,
+	// /healthz, /readyz, and pprof for long-running commands (bootstrap-server,
+	// tui). Off by default: most invocations are short-lived CLI one-shots
+	// with nothing worth scraping.
+	diagnosticAddr string
+
+	// shutdownTimeout bounds how long a shutdown.Coordinator waits on each
+	// phase (Nomad node drain, bootstrap server graceful stop) before
+	// moving on, for long-running commands like bootstrap-server.
+	shutdownTimeout time.Duration
+
+	// Traefik TLS flags (see services.TraefikOptions).
+	traefikTLSMode         string
+	traefikACMEEmail       string
+	traefikACMEDNSProvider string
+	traefikTLSDomains      []string
+
+	// pkiBackend selects where `styx tls` and `styx init --serve` get this
+	// node's Consul/Nomad leaf certificates from: "local" (internal/tls's
+	// self-managed CA, the default) or "vault" (a Vault PKI secrets engine,
+	// see internal/tls.VaultPKIIssuer).
+	pkiBackend string
+
+	// traceEnabled and traceEndpoint back --trace: exporting spans (see
+	// internal/tracing) for this one invocation is opt-in, since most
+	// commands run and exit well within the time it'd take to notice a
+	// missing collector.
+	traceEnabled  bool
+	traceEndpoint string
+
+	// tracingShutdown flushes and disables DefaultTracer's exporter; set by
+	// PersistentPreRunE, called by Execute once the command finishes.
+	tracingShutdown = func() {}
 )
 
 var rootCmd = &cobra.Command{
@@ -32,7 +80,8 @@ Commands:
   styx init --join <ip>  Join a specific server
   styx stop              Stop the Styx service
   styx status            Show cluster status
-  styx services          Manage platform services`,
+  styx services          Manage platform services
+  styx setup             Check/install prerequisites non-interactively (CI, Dockerfiles)`,
 }
 
 func init() {
@@ -41,17 +90,60 @@ func init() {
 		fmt.Fprintf(os.Stderr, "Error: failed to get home directory: %v\n", err)
 		os.Exit(1)
 	}
-	styxBase := filepath.Join(home, ".styx")
-
-	rootCmd.PersistentFlags().StringVar(&dataDir, "data-dir", filepath.Join(styxBase, "nomad"), "Nomad data directory")
-	rootCmd.PersistentFlags().StringVar(&configDir, "config-dir", filepath.Join(styxBase, "config"), "Config directory")
-	rootCmd.PersistentFlags().StringVar(&pluginDir, "plugin-dir", filepath.Join(styxBase, "plugins"), "Plugin directory")
-	rootCmd.PersistentFlags().StringVar(&logDir, "log-dir", filepath.Join(styxBase, "logs"), "Log directory")
-	rootCmd.PersistentFlags().StringVar(&secretsDir, "secrets-dir", filepath.Join(styxBase, "secrets"), "Secrets directory")
-	rootCmd.PersistentFlags().StringVar(&vaultDataDir, "vault-data-dir", filepath.Join(styxBase, "vault"), "Vault data directory")
+	styxBaseDir = filepath.Join(home, ".styx")
+
+	rootCmd.PersistentFlags().StringVar(&dataDir, "data-dir", filepath.Join(styxBaseDir, "nomad"), "Nomad data directory")
+	rootCmd.PersistentFlags().StringVar(&configDir, "config-dir", filepath.Join(styxBaseDir, "config"), "Config directory")
+	rootCmd.PersistentFlags().StringVar(&pluginDir, "plugin-dir", filepath.Join(styxBaseDir, "plugins"), "Plugin directory")
+	rootCmd.PersistentFlags().StringVar(&logDir, "log-dir", filepath.Join(styxBaseDir, "logs"), "Log directory")
+	rootCmd.PersistentFlags().StringVar(&secretsDir, "secrets-dir", filepath.Join(styxBaseDir, "secrets"), "Secrets directory")
+	rootCmd.PersistentFlags().StringVar(&vaultDataDir, "vault-data-dir", filepath.Join(styxBaseDir, "vault"), "Vault data directory")
+	rootCmd.PersistentFlags().StringVar(&certsDir, "certs-dir", filepath.Join(styxBaseDir, "certs"), "TLS certificates directory")
+	rootCmd.PersistentFlags().StringVar(&packageManager, "package-manager", "", "Override the prerequisite package manager backend (brew, apt, dnf, pacman, nix); auto-detected by default")
+	rootCmd.PersistentFlags().StringVar(&diagnosticAddr, "diagnostic-addr", "", "Bind a diagnostic server (/metrics, /healthz, /readyz, pprof) on this addr; disabled if empty")
+	rootCmd.PersistentFlags().DurationVar(&shutdownTimeout, "shutdown-timeout", 30*time.Second, "Maximum time a graceful shutdown waits on each phase (node drain, bootstrap server stop)")
+
+	rootCmd.PersistentFlags().StringVar(&traefikTLSMode, "traefik-tls-mode", "none", "Traefik HTTPS mode: none, tailscale, acme-http, acme-dns")
+	rootCmd.PersistentFlags().StringVar(&traefikACMEEmail, "traefik-acme-email", "", "Contact email for Let's Encrypt (acme-http/acme-dns modes)")
+	rootCmd.PersistentFlags().StringVar(&traefikACMEDNSProvider, "traefik-acme-dns-provider", "", "lego DNS provider name for the ACME DNS-01 challenge (acme-dns mode)")
+	rootCmd.PersistentFlags().StringSliceVar(&traefikTLSDomains, "traefik-tls-domain", nil, "Domain to provision a certificate for (repeatable)")
+
+	rootCmd.PersistentFlags().StringVar(&pkiBackend, "pki", "local", "Consul/Nomad leaf certificate backend: local or vault")
+
+	rootCmd.PersistentFlags().BoolVar(&traceEnabled, "trace", false, "Export OpenTelemetry spans for this invocation (see --trace-endpoint)")
+	rootCmd.PersistentFlags().StringVar(&traceEndpoint, "trace-endpoint", "http://127.0.0.1:4318", "OTLP/HTTP collector endpoint spans are exported to when --trace is set")
+
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		services.IntentsPath = filepath.Join(configDir, "intents.hcl")
+		authz.PolicyPath = filepath.Join(configDir, "authz.hcl")
+		services.DefaultRegistry = services.NewRegistry(configDir)
+		services.DefaultTraefikOptions = services.TraefikOptions{
+			TLSMode:         services.TLSMode(traefikTLSMode),
+			ACMEEmail:       traefikACMEEmail,
+			ACMEDNSProvider: traefikACMEDNSProvider,
+			Domains:         traefikTLSDomains,
+			DataDir:         styxBaseDir,
+		}
+
+		services.UserDefinitionsDir = filepath.Join(styxBaseDir, "services.d")
+		if err := services.LoadUserDefinitions(); err != nil {
+			return fmt.Errorf("failed to load service definitions from %s: %w", services.UserDefinitionsDir, err)
+		}
+
+		if traceEnabled {
+			tracingShutdown = tracing.Init(traceEndpoint)
+		}
+
+		if packageManager == "" {
+			return nil
+		}
+		return setup.SetManager(setup.ManagerKind(packageManager))
+	}
 }
 
 func Execute() error {
+	defer tracingShutdown()
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return err
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kessler-frost/styx/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage secrets stored as Nomad Variables",
+	Long: `Store and retrieve secrets in Nomad's Variables store (/v1/var/), so jobs
+can read them at render time with a
+	{{ with nomadVar "nomad/jobs/<job>" }}{{ .<key> }}{{ end }}
+template stanza instead of hardcoding values in job HCL (see
+internal/secrets.TemplateStanza).`,
+}
+
+var secretPutCmd = &cobra.Command{
+	Use:   "put <path> <key>=<value> [<key>=<value>...]",
+	Short: "Create or replace a secret",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runSecretPut,
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <path>",
+	Short: "Show a secret's keys and values",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretGet,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list [prefix]",
+	Short: "List secret paths",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runSecretList,
+}
+
+var secretRmCmd = &cobra.Command{
+	Use:   "rm <path>",
+	Short: "Delete a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretRm,
+}
+
+func init() {
+	secretCmd.AddCommand(secretPutCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretRmCmd)
+	rootCmd.AddCommand(secretCmd)
+}
+
+func runSecretPut(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	items := make(map[string]string, len(args)-1)
+	for _, kv := range args[1:] {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("invalid key=value pair: %q", kv)
+		}
+		items[k] = v
+	}
+
+	client := api.NewClient()
+	if err := client.PutSecret(path, items); err != nil {
+		return fmt.Errorf("failed to put secret %s: %w", path, err)
+	}
+
+	fmt.Printf("%s saved\n", path)
+	return nil
+}
+
+func runSecretGet(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	client := api.NewClient()
+	items, err := client.GetSecret(path)
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s: %w", path, err)
+	}
+	if items == nil {
+		return fmt.Errorf("secret not found: %s", path)
+	}
+
+	for k, v := range items {
+		fmt.Printf("%s=%s\n", k, v)
+	}
+	return nil
+}
+
+func runSecretList(cmd *cobra.Command, args []string) error {
+	prefix := ""
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+
+	client := api.NewClient()
+	paths, err := client.ListSecrets(prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	if len(paths) == 0 {
+		fmt.Println("No secrets found")
+		return nil
+	}
+
+	for _, p := range paths {
+		fmt.Println(p)
+	}
+	return nil
+}
+
+func runSecretRm(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	client := api.NewClient()
+	if err := client.DeleteSecret(path); err != nil {
+		return fmt.Errorf("failed to delete secret %s: %w", path, err)
+	}
+
+	fmt.Printf("%s deleted\n", path)
+	return nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/services"
+	"github.com/spf13/cobra"
+)
+
+var upgradeTimeout time.Duration
+
+var servicePinCmd = &cobra.Command{
+	Use:   "pin <name> <image:tag>",
+	Short: "Pin a platform service to a specific image:tag",
+	Long: `Override a platform service's image and version, persisted to
+<config-dir>/services/pins.json. Takes effect the next time the service
+deploys.
+
+Example:
+  styx service pin grafana grafana/grafana:10.4.2`,
+	Args: cobra.ExactArgs(2),
+	RunE: runServicePin,
+}
+
+var serviceEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Edit a platform service's job template in $EDITOR",
+	Long: `Open the effective Nomad job template for a platform service in $EDITOR,
+seeding it from the embedded default if no override exists yet. Saving
+writes the override to <config-dir>/services/<name>.hcl.tmpl, where it
+takes effect the next time the service deploys.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runServiceEdit,
+}
+
+var serviceDiffCmd = &cobra.Command{
+	Use:   "diff <name>",
+	Short: "Show how a service's job template differs from the built-in default",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServiceDiff,
+}
+
+var serviceUpgradeCmd = &cobra.Command{
+	Use:   "upgrade <name> <image:tag>",
+	Short: "Roll a platform service forward to a new image:tag, health-gated",
+	Long: `Pin a platform service to a new image:tag and redeploy it, then wait on
+Nomad's deployment (see the service's update stanza) to report healthy.
+If it doesn't become healthy within --timeout, the pin and job are
+reverted to the previous image:tag automatically.
+
+Not supported for grafana or prometheus: pin those with 'service pin' and
+redeploy with 'observability enable' instead.
+
+Example:
+  styx service upgrade nats nats:2.10.20`,
+	Args: cobra.ExactArgs(2),
+	RunE: runServiceUpgrade,
+}
+
+func init() {
+	serviceUpgradeCmd.Flags().DurationVar(&upgradeTimeout, "timeout", 3*time.Minute, "How long to wait for the rollout to become healthy")
+
+	serviceCmd.AddCommand(servicePinCmd)
+	serviceCmd.AddCommand(serviceEditCmd)
+	serviceCmd.AddCommand(serviceDiffCmd)
+	serviceCmd.AddCommand(serviceUpgradeCmd)
+	rootCmd.AddCommand(serviceCmd)
+}
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Pin, edit, and diff platform service job templates",
+}
+
+func runServicePin(cmd *cobra.Command, args []string) error {
+	name, imageTag := args[0], args[1]
+
+	if err := services.DefaultRegistry.Pin(name, imageTag); err != nil {
+		return fmt.Errorf("failed to pin %s: %w", name, err)
+	}
+
+	fmt.Printf("%s pinned to %s\n", name, imageTag)
+	return nil
+}
+
+func runServiceEdit(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	path, err := services.DefaultRegistry.TemplatePath(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		def, err := services.DefaultTemplate(name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create services directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(def), 0644); err != nil {
+			return fmt.Errorf("failed to seed template override for %s: %w", name, err)
+		}
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	editCmd := exec.Command(editor, path)
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	return editCmd.Run()
+}
+
+func runServiceDiff(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	def, err := services.DefaultTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	effective, err := services.DefaultRegistry.LoadTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	if def == effective {
+		fmt.Printf("%s: no override, using the built-in default\n", name)
+		return nil
+	}
+
+	printLineDiff(def, effective)
+	return nil
+}
+
+func runServiceUpgrade(cmd *cobra.Command, args []string) error {
+	name, imageTag := args[0], args[1]
+
+	fmt.Printf("Upgrading %s to %s...\n", name, imageTag)
+	if err := services.UpgradeService(name, imageTag, upgradeTimeout); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s upgraded to %s successfully\n", name, imageTag)
+	return nil
+}
+
+// printLineDiff prints a minimal unified-style line diff between a and b:
+// lines only in a are prefixed "-", lines only in b are prefixed "+", and
+// matching lines are skipped. It's not a real LCS diff, just enough to spot
+// what an override changed.
+func printLineDiff(a, b string) {
+	aLines := strings.Split(a, "\n")
+	bLines := strings.Split(b, "\n")
+
+	aSet := make(map[string]bool, len(aLines))
+	for _, l := range aLines {
+		aSet[l] = true
+	}
+	bSet := make(map[string]bool, len(bLines))
+	for _, l := range bLines {
+		bSet[l] = true
+	}
+
+	for _, l := range aLines {
+		if !bSet[l] {
+			fmt.Printf("- %s\n", l)
+		}
+	}
+	for _, l := range bLines {
+		if !aSet[l] {
+			fmt.Printf("+ %s\n", l)
+		}
+	}
+}
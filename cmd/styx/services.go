@@ -13,7 +13,10 @@ import (
 )
 
 var servicesJSON bool
+var servicesFilter string
 var startAll bool
+var startCheckHash string
+var planAll bool
 
 var servicesCmd = &cobra.Command{
 	Use:   "services",
@@ -34,7 +37,17 @@ Optional Services:
   promtail   - Log shipper
 
 Use 'styx services' to see status of all services.
-Use 'styx services start --all' to start all optional services.`,
+Use 'styx services start --all' to start all optional services.
+
+Use --filter to narrow the list with an expression over PlatformService
+fields, e.g.:
+  styx services --filter 'Status=="running"' --json
+  styx services --filter 'Health != "healthy"' --json
+
+--filter only applies with --json; the plain-text listing renders from
+services.ServiceStatus, not api.PlatformService, and has no --page or
+--fields equivalent of styx jobs/styx nodes - see those commands for
+pagination and field selection.`,
 	RunE: runServicesList,
 }
 
@@ -47,8 +60,8 @@ Examples:
   styx services start nats       # Start NATS
   styx services start --all      # Start all optional services
   styx services start -a         # Same as --all`,
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runServicesStart,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServicesStart,
 }
 
 var servicesStopCmd = &cobra.Command{
@@ -58,14 +71,56 @@ var servicesStopCmd = &cobra.Command{
 	RunE:  runServicesStop,
 }
 
+var servicesPlanCmd = &cobra.Command{
+	Use:   "plan [service]",
+	Short: "Show what a service's Nomad job would change without applying it",
+	Long: `Render the Nomad jobspec that would be submitted for a service and diff it
+against whatever is currently registered (borrowing the 'nomad plan' idiom),
+without submitting anything.
+
+Exits 0 if nothing would change, 1 if it would, and 2 on error, so CI/CD can
+gate deployments on 'styx services plan --all'.
+
+Examples:
+  styx services plan nats
+  styx services plan --all --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServicesPlan,
+}
+
+var servicesGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Print the platform services dependency graph in DOT format",
+	Long: `Print the platform services dependency graph (see DependsOn) as a
+Graphviz DOT digraph, for piping into 'dot' or another graph renderer.
+
+Doesn't require Nomad to be running - it's rendered from the static service
+registry, the same dependencies orderForDeploy uses to layer the platform
+services 'styx init' deploys.
+
+Examples:
+  styx services graph | dot -Tpng -o services.png`,
+	RunE: runServicesGraph,
+}
+
 func init() {
 	servicesCmd.Flags().BoolVar(&servicesJSON, "json", false, "Output in JSON format")
+	servicesCmd.Flags().StringVar(&servicesFilter, "filter", "", "Filter expression (e.g. 'Status==\"running\"')")
 	servicesStartCmd.Flags().BoolVarP(&startAll, "all", "a", false, "Start all optional services")
+	servicesStartCmd.Flags().StringVar(&startCheckHash, "check", "", "Fail instead of starting if the plan hash doesn't match this value")
+	servicesPlanCmd.Flags().BoolVarP(&planAll, "all", "a", false, "Plan all platform services")
 	servicesCmd.AddCommand(servicesStartCmd)
 	servicesCmd.AddCommand(servicesStopCmd)
+	servicesCmd.AddCommand(servicesPlanCmd)
+	servicesCmd.AddCommand(servicesGraphCmd)
 	rootCmd.AddCommand(servicesCmd)
 }
 
+func runServicesGraph(cmd *cobra.Command, args []string) error {
+	fmt.Print(services.GraphDOT())
+	return nil
+}
+
 // getAvailableServiceNames returns a comma-separated list of available platform services
 func getAvailableServiceNames() string {
 	names := make([]string, len(services.PlatformServices))
@@ -87,10 +142,12 @@ func runServicesList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Use API client for JSON output
+	// Use API client for JSON output, where --filter applies - the
+	// plain-text listing below is for humans skimming the terminal, who
+	// have no use for --filter/jq in the first place.
 	if servicesJSON {
 		apiClient := api.NewClient()
-		svcs, err := apiClient.GetPlatformServices()
+		svcs, err := apiClient.GetPlatformServices(servicesFilter)
 		if err != nil {
 			return fmt.Errorf("failed to get service status: %w", err)
 		}
@@ -171,6 +228,16 @@ func runServicesStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%s is a mandatory service and is already running (started automatically with 'styx init')", name)
 	}
 
+	if startCheckHash != "" {
+		result, err := services.Plan(name)
+		if err != nil {
+			return fmt.Errorf("failed to plan %s: %w", name, err)
+		}
+		if result.Hash != startCheckHash {
+			return fmt.Errorf("plan hash for %s (%s) does not match expected %s", name, result.Hash, startCheckHash)
+		}
+	}
+
 	fmt.Printf("Starting %s...\n", name)
 	if err := services.Deploy(name); err != nil {
 		return fmt.Errorf("failed to start %s: %w", name, err)
@@ -204,6 +271,115 @@ func runServicesStop(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runServicesPlan(cmd *cobra.Command, args []string) error {
+	client := services.DefaultClient()
+	if !client.IsHealthy() {
+		fmt.Fprintln(os.Stderr, "Nomad is not running. Start Styx first with 'styx init'")
+		os.Exit(2)
+	}
+
+	var names []string
+	switch {
+	case planAll:
+		if len(args) > 0 {
+			fmt.Fprintln(os.Stderr, "cannot specify both --all and a service name")
+			os.Exit(2)
+		}
+		for _, svc := range services.PlatformServices {
+			names = append(names, svc.Name)
+		}
+	case len(args) == 1:
+		names = []string{args[0]}
+	default:
+		fmt.Fprintf(os.Stderr, "specify a service name or use --all\n\nAvailable services: %s\n", getAvailableServiceNames())
+		os.Exit(2)
+	}
+
+	var results []*services.PlanResult
+	anyChanged := false
+	for _, name := range names {
+		result, err := services.Plan(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to plan %s: %v\n", name, err)
+			os.Exit(2)
+		}
+		results = append(results, result)
+		if result.Changed {
+			anyChanged = true
+		}
+	}
+
+	if servicesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode plan results: %v\n", err)
+			os.Exit(2)
+		}
+	} else {
+		for _, result := range results {
+			printPlanResult(result)
+		}
+	}
+
+	if anyChanged {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func printPlanResult(result *services.PlanResult) {
+	switch {
+	case !result.Registered:
+		fmt.Printf("%s: not yet registered (would create), hash %s\n", result.Service, result.Hash)
+	case !result.Changed:
+		fmt.Printf("%s: no changes\n", result.Service)
+		return
+	default:
+		fmt.Printf("%s: %d change(s), hash %s\n", result.Service, len(result.Diffs), result.Hash)
+	}
+
+	for _, d := range result.Diffs {
+		fmt.Printf("  task %s/%s\n", d.TaskGroup, d.Task)
+		if d.ImageFrom != d.ImageTo {
+			fmt.Printf("    image: %s -> %s\n", orNone(d.ImageFrom), orNone(d.ImageTo))
+		}
+		for k, v := range d.EnvAdded {
+			fmt.Printf("    env +%s=%s\n", k, v)
+		}
+		for k, v := range d.EnvRemoved {
+			fmt.Printf("    env -%s=%s\n", k, v)
+		}
+		for k, v := range d.EnvChanged {
+			fmt.Printf("    env ~%s: %s -> %s\n", k, v[0], v[1])
+		}
+		if !resourcesMatch(d.ResourcesFrom, d.ResourcesTo) {
+			fmt.Printf("    resources: %s -> %s\n", formatResources(d.ResourcesFrom), formatResources(d.ResourcesTo))
+		}
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func formatResources(r *services.Resources) string {
+	if r == nil {
+		return "(none)"
+	}
+	return fmt.Sprintf("cpu=%d memory_mb=%d", r.CPU, r.MemoryMB)
+}
+
+func resourcesMatch(a, b *services.Resources) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func getStatusIcon(status string) string {
 	switch status {
 	case "running":
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kessler-frost/styx/internal/setup"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setupYes    bool
+	setupOnly   []string
+	setupSkip   []string
+	setupDryRun bool
+)
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Check (and optionally install) Styx's prerequisites non-interactively",
+	Long: `styx setup checks Styx's prerequisites and, with --yes, installs whichever
+are missing - without the interactive TUI (see runTUI), emitting one JSON
+object per line to stdout so a Dockerfile or CI pipeline can follow along
+without a TTY.`,
+	RunE: runSetup,
+}
+
+func init() {
+	setupCmd.Flags().BoolVar(&setupYes, "yes", false, "Install missing prerequisites instead of just reporting them")
+	setupCmd.Flags().StringSliceVar(&setupOnly, "only", nil, "Restrict the run to these prerequisite names (comma-separated)")
+	setupCmd.Flags().StringSliceVar(&setupSkip, "skip", nil, "Exclude these prerequisite names from the run (comma-separated)")
+	setupCmd.Flags().BoolVar(&setupDryRun, "dry-run", false, "Report what's missing and would install, without installing anything")
+	rootCmd.AddCommand(setupCmd)
+}
+
+func runSetup(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return setup.RunHeadless(ctx, setup.HeadlessOptions{
+		Yes:    setupYes,
+		Only:   setupOnly,
+		Skip:   setupSkip,
+		DryRun: setupDryRun,
+	}, os.Stdout)
+}
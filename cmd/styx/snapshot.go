@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/api"
+	"github.com/kessler-frost/styx/internal/snapshot"
+	"github.com/kessler-frost/styx/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var snapshotDest string
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Back up and restore the cluster's Nomad/Consul/Vault state",
+	Long:  `Take a point-in-time backup of a running Styx cluster, or restore one from a previously created bundle.`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Bundle a point-in-time snapshot of Nomad/Consul/Vault plus the rendered configs",
+	RunE:  runSnapshotCreate,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <bundle>",
+	Short: "Restore a cluster from a bundle created by 'styx snapshot create'",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSnapshotRestore,
+}
+
+func init() {
+	snapshotCreateCmd.Flags().StringVar(&snapshotDest, "dest", "", "Path to write the bundle tarball to (default: <data-dir>/styx-snapshot-<timestamp>.tar.gz)")
+
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runSnapshotCreate(cmd *cobra.Command, args []string) error {
+	dest := snapshotDest
+	if dest == "" {
+		dest = filepath.Join(dataDir, fmt.Sprintf("styx-snapshot-%s.tar.gz", time.Now().Format("20060102-150405")))
+	}
+
+	fmt.Println("Snapshotting Nomad, Vault, and Consul (if reachable)...")
+	manifest, err := createClusterSnapshot(dest)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote snapshot bundle to %s\n", dest)
+	fmt.Printf("  Nomad:  %s\n", manifest.NomadVersion)
+	fmt.Printf("  Vault:  %s\n", manifest.VaultVersion)
+	return nil
+}
+
+// createClusterSnapshot snapshots the running cluster and bundles it at
+// dest, shared by `styx snapshot create` and `styx upgrade`'s pre-flight
+// backup.
+func createClusterSnapshot(dest string) (*snapshot.Manifest, error) {
+	client := api.NewClient()
+
+	rootToken, err := vault.GetRootToken(secretsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault root token: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "styx-snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	nodeName, err := os.Hostname()
+	if err != nil {
+		nodeName = "unknown"
+	}
+
+	manifest, err := snapshot.CreateBundle(client, rootToken, tmpDir, snapshot.BundleOptions{
+		ConfigDir:  configDir,
+		ConsulAddr: "http://127.0.0.1:8500",
+		NodeID:     nodeName,
+		Dest:       dest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+func runSnapshotRestore(cmd *cobra.Command, args []string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
+
+	fmt.Printf("Restoring from %s...\n", args[0])
+	if err := snapshot.Restore(args[0], snapshot.RestoreOptions{
+		ConfigDir:    configDir,
+		NomadDataDir: dataDir,
+		VaultDataDir: vaultDataDir,
+		PlistPath:    plistPath,
+	}); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	fmt.Println("Restore complete. Services have been reloaded.")
+	fmt.Println("Run 'nomad operator snapshot restore' / 'vault operator raft snapshot-restore' against the respawned agents to apply restore.snap.")
+	return nil
+}
@@ -1,23 +1,38 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/kessler-frost/styx/internal/api"
 	"github.com/kessler-frost/styx/internal/launchd"
+	"github.com/kessler-frost/styx/internal/pki"
+	"github.com/kessler-frost/styx/internal/supervisor"
 	"github.com/spf13/cobra"
 )
 
+var statusWatch bool
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show Styx cluster status",
-	Long:  `Display the current status of the Styx/Nomad service and cluster connectivity.`,
-	RunE:  runStatus,
+	Long: `Display the current status of the Styx/Nomad service and cluster connectivity.
+
+With --watch, keep redrawing a compact status board as cluster membership,
+allocations, and Vault seal state change, instead of exiting after the
+first report.`,
+	RunE: runStatus,
 }
 
 func init() {
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Keep watching and redrawing status as cluster events arrive")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -51,6 +66,30 @@ type agentMembers struct {
 	} `json:"Members"`
 }
 
+// statusHTTPClient returns an http.Client for probing the local Nomad/Vault
+// listeners, plus the URL scheme to use with it. If this node has a root CA
+// under certsDir (see internal/pki's IssueServiceCert), it returns an https
+// client that verifies the peer against that CA; otherwise it falls back to
+// plain HTTP, the same as before any service on this node had a cert.
+func statusHTTPClient() (client *http.Client, scheme string) {
+	caFile := pki.CAFile(certsDir)
+	if _, err := os.Stat(caFile); err != nil {
+		return &http.Client{Timeout: 2 * time.Second}, "http"
+	}
+
+	pool, err := pki.GetCAPool(caFile)
+	if err != nil {
+		return &http.Client{Timeout: 2 * time.Second}, "http"
+	}
+
+	return &http.Client{
+		Timeout: 2 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}, "https"
+}
+
 func runStatus(cmd *cobra.Command, args []string) error {
 	label := "com.styx.nomad"
 
@@ -68,10 +107,10 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println("Service:     running")
 
-	client := &http.Client{Timeout: 2 * time.Second}
+	client, scheme := statusHTTPClient()
 
 	// Check Vault health (only on servers)
-	resp, err := client.Get("http://127.0.0.1:8200/v1/sys/health")
+	resp, err := client.Get(scheme + "://127.0.0.1:8200/v1/sys/health")
 	if err == nil {
 		resp.Body.Close()
 		if resp.StatusCode == 200 {
@@ -91,7 +130,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check Nomad health
-	resp, err = client.Get("http://127.0.0.1:4646/v1/agent/health")
+	resp, err = client.Get(scheme + "://127.0.0.1:4646/v1/agent/health")
 	if err != nil {
 		fmt.Println("Nomad:       not responding")
 		fmt.Println()
@@ -113,7 +152,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	fmt.Println("Nomad:       healthy")
 
 	// Get agent self info
-	resp, err = client.Get("http://127.0.0.1:4646/v1/agent/self")
+	resp, err = client.Get(scheme + "://127.0.0.1:4646/v1/agent/self")
 	if err != nil {
 		return nil
 	}
@@ -138,7 +177,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	// Get cluster members if server
 	if isServer {
-		resp, err = client.Get("http://127.0.0.1:4646/v1/agent/members")
+		resp, err = client.Get(scheme + "://127.0.0.1:4646/v1/agent/members")
 		if err == nil {
 			defer resp.Body.Close()
 			var members agentMembers
@@ -164,9 +203,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	// Show endpoints
 	fmt.Println("\nCore Services:")
-	fmt.Println("  Nomad UI:    http://127.0.0.1:4646")
+	fmt.Printf("  Nomad UI:    %s://127.0.0.1:4646\n", scheme)
 	if isServer {
-		fmt.Println("  Vault UI:    http://127.0.0.1:8200/ui")
+		fmt.Printf("  Vault UI:    %s://127.0.0.1:8200/ui\n", scheme)
 	}
 
 	// Show platform endpoints (only on servers where platform services run)
@@ -177,7 +216,134 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		fmt.Println("  Prometheus:  http://localhost:4200/prometheus")
 	}
 
-	fmt.Println("\nTransport encryption: Tailscale WireGuard")
+	if scheme == "https" {
+		fmt.Println("\nTransport encryption: Tailscale WireGuard + internal mTLS (styx pki)")
+	} else {
+		fmt.Println("\nTransport encryption: Tailscale WireGuard")
+	}
+
+	printSupervisorStatus()
+
+	if statusWatch {
+		watchStatus()
+	}
 
 	return nil
 }
+
+// watchStatus keeps redrawing a compact status board after runStatus's
+// one-shot report, woken by Nomad's /v1/event/stream (Node/Deployment/
+// Allocation topics) plus a short timer so Vault's seal state still
+// refreshes even during a quiet stream. Users running `styx init --join`
+// can watch membership and allocation churn converge without hammering
+// `styx status` in a loop. The event stream reconnects with exponential
+// backoff on disconnect; SIGINT stops the watch after one final redraw.
+func watchStatus() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	client := api.NewClient()
+
+	redraw := make(chan struct{}, 1)
+	wake := func() {
+		select {
+		case redraw <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		backoff := time.Second
+		for ctx.Err() == nil {
+			events, err := client.StreamEvents(ctx, []string{"Node", "Deployment", "Allocation"})
+			if err != nil {
+				time.Sleep(backoff)
+				if backoff < 30*time.Second {
+					backoff *= 2
+				}
+				continue
+			}
+
+			backoff = time.Second
+			for range events {
+				wake()
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+
+	fmt.Println("\nWatching for cluster changes, press Ctrl-C to stop...")
+	for {
+		select {
+		case <-redraw:
+			renderStatusBoard(client)
+		case <-ticker.C:
+			renderStatusBoard(client)
+		case <-ctx.Done():
+			fmt.Println("\nFinal snapshot:")
+			renderStatusBoard(client)
+			return
+		}
+	}
+}
+
+// renderStatusBoard redraws a compact status board in place using ANSI
+// cursor moves, pulled from the same ClusterStatus callers elsewhere build
+// JSON output from (see `styx services --json`).
+func renderStatusBoard(client *api.Client) {
+	status := client.GetClusterStatus()
+
+	fmt.Print("\033[H\033[2J")
+	fmt.Println("Styx Status (watching)")
+	fmt.Println("-----------------------")
+	fmt.Printf("Nomad:  %s\n", status.Nomad.Status)
+
+	fmt.Printf("Vault:  %s", status.Vault.Status)
+	if status.Vault.Mode != "" {
+		fmt.Printf(" (%s)", status.Vault.Mode)
+	}
+	fmt.Println()
+
+	if status.Mode != "" {
+		fmt.Printf("Mode:   %s (%s)\n", status.Mode, status.NodeName)
+	}
+
+	if len(status.Members) > 0 {
+		fmt.Println("\nCluster Members:")
+		for _, m := range status.Members {
+			fmt.Printf("  - %s (%s:%d) [%s]\n", m.Name, m.Addr, m.Port, m.Status)
+		}
+	}
+
+	fmt.Printf("\n%s\n", time.Now().Format("15:04:05"))
+}
+
+// printSupervisorStatus reports the state `styx supervise` holds for each
+// process it manages, a more authoritative source than the health-endpoint
+// polling above since it reflects whether the process itself is running,
+// not just whether its port answers. It's silent if the node still runs an
+// older wrapper-script-based agent with no supervisor to query.
+func printSupervisorStatus() {
+	st, err := supervisor.FetchStatus()
+	if err != nil {
+		return
+	}
+
+	fmt.Println("\nSupervised Processes:")
+	for _, p := range st.Processes {
+		state := "stopped"
+		if p.Running {
+			state = fmt.Sprintf("running (pid %d)", p.PID)
+		}
+		fmt.Printf("  - %-15s %s", p.Name, state)
+		if p.Restarts > 0 {
+			fmt.Printf(" [%d restart(s)]", p.Restarts)
+		}
+		fmt.Println()
+	}
+	if st.UnsealError != "" {
+		fmt.Printf("  Vault auto-unseal error: %s\n", st.UnsealError)
+	}
+}
@@ -1,16 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"time"
 
+	"github.com/kessler-frost/styx/driver/container"
 	"github.com/kessler-frost/styx/internal/launchd"
 	"github.com/kessler-frost/styx/internal/services"
 	"github.com/spf13/cobra"
 )
 
+var (
+	stopDrainDeadline time.Duration
+	stopForce         bool
+)
+
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop Styx services",
@@ -20,6 +28,8 @@ var stopCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(stopCmd)
+	stopCmd.Flags().DurationVar(&stopDrainDeadline, "drain-deadline", services.DefaultDrainTimeout, "Maximum time to wait for each job's allocations to drain before unloading")
+	stopCmd.Flags().BoolVar(&stopForce, "force", false, "Unload the service even if jobs haven't finished draining by --drain-deadline")
 }
 
 func runStop(cmd *cobra.Command, args []string) error {
@@ -37,17 +47,16 @@ func runStop(cmd *cobra.Command, args []string) error {
 
 	fmt.Println("Stopping Styx service...")
 
-	// Stop all Nomad jobs first so containers are properly cleaned up
-	stopAllJobs()
+	// Stop all Nomad jobs and wait for their allocations to actually drain
+	// before touching launchd, so ports are released instead of guessed at
+	// with a fixed sleep.
+	drainAllJobs(stopDrainDeadline, stopForce)
 
 	// Stop the service
 	if err := launchd.Stop(label); err != nil {
 		fmt.Printf("Warning: failed to stop service: %v\n", err)
 	}
 
-	// Wait for graceful shutdown
-	time.Sleep(2 * time.Second)
-
 	// Unload the service
 	if err := launchd.Unload(plistPath); err != nil {
 		return fmt.Errorf("failed to unload service: %w", err)
@@ -57,8 +66,13 @@ func runStop(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// stopAllJobs stops all running Nomad jobs
-func stopAllJobs() {
+// drainAllJobs stops every running Nomad job and waits, up to deadline per
+// job, for its allocations to reach a terminal ClientStatus (see
+// services.NomadClient.DrainJob), then waits for any Apple containers that
+// had published ports/sockets to actually disappear, so launchd unload
+// doesn't race the container runtime releasing them. If force is set,
+// drainAllJobs proceeds regardless of whether jobs finished draining.
+func drainAllJobs(deadline time.Duration, force bool) {
 	client := services.DefaultClient()
 
 	jobs, err := client.ListJobs()
@@ -73,16 +87,48 @@ func stopAllJobs() {
 
 	fmt.Printf("Stopping %d job(s)...\n", len(jobs))
 
+	opts := services.StopOptions{Timeout: deadline, Force: force}
 	for _, job := range jobs {
 		if job.Status == "dead" {
 			continue
 		}
-		fmt.Printf("  Stopping job: %s\n", job.ID)
-		if err := client.StopJob(job.ID); err != nil {
-			fmt.Printf("  Warning: failed to stop job %s: %v\n", job.ID, err)
+		fmt.Printf("  Draining job: %s\n", job.ID)
+		if err := client.DrainJob(job.ID, opts); err != nil {
+			fmt.Printf("  Warning: %v\n", err)
 		}
 	}
 
-	// Wait for jobs to stop and containers to be cleaned up
-	time.Sleep(3 * time.Second)
+	waitForContainerPortsReleased(deadline)
+}
+
+// waitForContainerPortsReleased polls the Apple container runtime until no
+// running container still holds a published port or socket, or deadline
+// elapses. It's a no-op if the container CLI isn't installed.
+func waitForContainerPortsReleased(deadline time.Duration) {
+	binPath, err := exec.LookPath("container")
+	if err != nil {
+		return
+	}
+	client := container.NewClient(binPath)
+	ctx := context.Background()
+
+	cutoff := time.Now().Add(deadline)
+	for {
+		containers, err := client.List(ctx, true)
+		if err != nil {
+			return
+		}
+
+		released := true
+		for _, c := range containers {
+			if c.Status == "running" && (len(c.Configuration.PublishedPorts) > 0 || len(c.Configuration.PublishedSockets) > 0) {
+				released = false
+				break
+			}
+		}
+		if released || time.Now().After(cutoff) {
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kessler-frost/styx/internal/network"
+	"github.com/spf13/cobra"
+)
+
+var subnetCmd = &cobra.Command{
+	Use:   "subnet",
+	Short: "Advertise the Styx container subnet over Tailscale",
+	Long: `Tailscale peers can reach platform services by their container IP on
+the Styx container network (` + network.StyxNetworkSubnet + `) instead of
+through localhost port-forwards, once this node advertises the subnet as a
+Tailscale route and the tailnet admin approves it.`,
+}
+
+var subnetEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Advertise the Styx container subnet as a Tailscale route",
+	RunE:  runSubnetEnable,
+}
+
+var subnetDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop advertising the Styx container subnet",
+	RunE:  runSubnetDisable,
+}
+
+var subnetStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the Styx container subnet is advertised and approved",
+	RunE:  runSubnetStatus,
+}
+
+func init() {
+	subnetCmd.AddCommand(subnetEnableCmd)
+	subnetCmd.AddCommand(subnetDisableCmd)
+	subnetCmd.AddCommand(subnetStatusCmd)
+	rootCmd.AddCommand(subnetCmd)
+}
+
+func runSubnetEnable(cmd *cobra.Command, args []string) error {
+	if err := network.AdvertiseStyxSubnet(); err != nil {
+		return fmt.Errorf("failed to advertise %s: %w", network.StyxNetworkSubnet, err)
+	}
+	fmt.Printf("Advertising %s.\n", network.StyxNetworkSubnet)
+	fmt.Println("Approve the route in the tailnet admin console (or run 'styx subnet status' to check), then peers can reach platform services by container IP.")
+	return nil
+}
+
+func runSubnetDisable(cmd *cobra.Command, args []string) error {
+	if err := network.WithdrawStyxSubnet(); err != nil {
+		return fmt.Errorf("failed to withdraw %s: %w", network.StyxNetworkSubnet, err)
+	}
+	fmt.Printf("No longer advertising %s.\n", network.StyxNetworkSubnet)
+	return nil
+}
+
+func runSubnetStatus(cmd *cobra.Command, args []string) error {
+	status, err := network.GetSubnetRouteStatus()
+	if err != nil {
+		return fmt.Errorf("failed to check subnet route status: %w", err)
+	}
+
+	fmt.Printf("Subnet:     %s\n", network.StyxNetworkSubnet)
+	fmt.Printf("Advertised: %t\n", status.Advertised)
+	fmt.Printf("Approved:   %t\n", status.Approved)
+
+	if status.Advertised && !status.Approved {
+		fmt.Println()
+		fmt.Println("Warning: this route is advertised but not yet approved by the tailnet admin - peers can't route to it until it's approved in the admin console.")
+	}
+
+	return nil
+}
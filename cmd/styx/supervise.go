@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/supervisor"
+	"github.com/kessler-frost/styx/internal/vault"
+	"github.com/kessler-frost/styx/internal/vault/autounseal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	superviseRole       string
+	superviseRendezvous string
+)
+
+var superviseCmd = &cobra.Command{
+	Use:    "supervise",
+	Short:  "Run Vault and Nomad as supervised child processes",
+	Hidden: true, // invoked by the com.styx.nomad launchd job, not directly by users
+	Long: `supervise replaces the generated styx-agent.sh wrapper script: it starts
+Vault (server role only) and Nomad as child processes, restarts either on
+crash with exponential backoff, forwards SIGTERM/SIGINT to them for a clean
+shutdown, auto-unseals Vault without shelling out to curl/python, and serves
+their state at http://` + supervisor.StatusAddr + `/supervisor/status for
+"styx status" to query.`,
+	RunE: runSupervise,
+}
+
+func init() {
+	superviseCmd.Flags().StringVar(&superviseRole, "role", "", "Node role: server or client (required)")
+	superviseCmd.MarkFlagRequired("role")
+	superviseCmd.Flags().StringVar(&superviseRendezvous, "rendezvous", "", "Shared HTTPS rendezvous URL to advertise this server on (server role only)")
+	rootCmd.AddCommand(superviseCmd)
+}
+
+func runSupervise(cmd *cobra.Command, args []string) error {
+	if superviseRole != "server" && superviseRole != "client" {
+		return fmt.Errorf("invalid --role %q, want %q or %q", superviseRole, "server", "client")
+	}
+
+	nomadPath, err := exec.LookPath("nomad")
+	if err != nil {
+		return fmt.Errorf("nomad not found in PATH: %w", err)
+	}
+
+	sup := supervisor.New(logDir)
+	sup.Add(supervisor.ManagedProcess{
+		Name: "nomad",
+		Path: nomadPath,
+		Args: []string{"agent", "-config=" + filepath.Join(configDir, "nomad.hcl")},
+	})
+
+	if superviseRole == "server" {
+		if err := addServerProcesses(sup); err != nil {
+			return err
+		}
+	}
+
+	statusServer, err := supervisor.NewStatusServer(sup)
+	if err != nil {
+		return fmt.Errorf("failed to start supervisor status server: %w", err)
+	}
+	statusServer.Start()
+	defer statusServer.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if superviseRole == "server" {
+		advertiseDiscovery(ctx)
+		startShareServer()
+	}
+
+	fmt.Printf("Supervising %s processes, status at http://%s/supervisor/status\n", superviseRole, supervisor.StatusAddr)
+	return sup.Run(ctx)
+}
+
+// advertiseDiscovery starts the background loops that let other nodes'
+// `styx init` find this server without a Tailscale peer scan: a LAN mDNS
+// announcement, and a rendezvous POST loop when --rendezvous was given.
+// Both are best-effort and keep retrying on their own, so a flaky LAN or
+// rendezvous endpoint never affects Nomad/Vault supervision.
+func advertiseDiscovery(ctx context.Context) {
+	clusterID, err := network.LoadOrCreateClusterID(configDir)
+	if err != nil {
+		fmt.Printf("Warning: failed to load cluster id, discovery advertising disabled: %v\n", err)
+		return
+	}
+
+	go (&network.MDNSDiscoverer{}).Advertise(ctx, clusterID, Version)
+
+	if superviseRendezvous != "" {
+		rendezvous := &network.RendezvousDiscoverer{URL: superviseRendezvous, ClusterID: clusterID}
+		go rendezvous.Advertise(ctx, Version, 30*time.Second)
+	}
+}
+
+// startShareServer lets this node hold Shamir shares deposited by other
+// nodes' --unseal-provider=shamir, so a cluster's own servers can act as
+// each other's share-holding peers without a separate service to run.
+// Best-effort: a node without Tailscale up just can't host shares, and
+// that shouldn't stop Nomad/Vault supervision.
+func startShareServer() {
+	server := autounseal.NewShareServer(filepath.Join(secretsDir, "shamir-shares"))
+	if err := server.Start(); err != nil {
+		fmt.Printf("Warning: failed to start shamir share server: %v\n", err)
+	}
+}
+
+// addServerProcesses adds Vault and its helper processes (the consul-template
+// Vault-token watcher and the PKI rotation daemon) to sup, and arms Vault
+// auto-unseal.
+func addServerProcesses(sup *supervisor.Supervisor) error {
+	vaultPath, err := exec.LookPath("vault")
+	if err != nil {
+		return fmt.Errorf("vault not found in PATH: %w", err)
+	}
+	styxBinPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve styx binary path: %w", err)
+	}
+	nodeName, err := os.Hostname()
+	if err != nil || nodeName == "" {
+		nodeName = "node1"
+	}
+
+	sup.Add(supervisor.ManagedProcess{
+		Name: "vault",
+		Path: vaultPath,
+		Args: []string{"server", "-config=" + filepath.Join(configDir, "vault.hcl")},
+	})
+
+	vaultToken, err := vault.GetNomadToken(secretsDir)
+	if err != nil {
+		fmt.Printf("Warning: nomad-cluster vault token not available yet: %v\n", err)
+	}
+	sup.Add(supervisor.ManagedProcess{
+		Name: "template-watch",
+		Path: styxBinPath,
+		Args: []string{
+			"template", "watch",
+			"--source=" + filepath.Join(configDir, "nomad.hcl.ctmpl"),
+			"--destination=" + filepath.Join(configDir, "nomad.hcl"),
+			"--vault-addr=http://127.0.0.1:8200",
+			"--vault-token=" + vaultToken,
+		},
+	})
+
+	sup.Add(supervisor.ManagedProcess{
+		Name: "pki-rotate",
+		Path: styxBinPath,
+		Args: []string{"--certs-dir=" + certsDir, "pki", "rotate", "--node=" + nodeName, "--role=server", "--watch"},
+	})
+
+	sup.Add(supervisor.ManagedProcess{
+		Name: "tls-rotate",
+		Path: styxBinPath,
+		Args: []string{"--certs-dir=" + certsDir, "tls", "rotate", "--node=" + nodeName, "--role=server", "--watch"},
+	})
+
+	sup.Add(supervisor.ManagedProcess{
+		Name: "bootstrap-server",
+		Path: styxBinPath,
+		Args: []string{"--config-dir=" + configDir, "--certs-dir=" + certsDir, "--secrets-dir=" + secretsDir, "--log-dir=" + logDir, "bootstrap-server"},
+	})
+
+	sup.WithAutoUnseal(func() error {
+		sealed, err := vault.IsSealed()
+		if err != nil {
+			return nil // Vault not reachable yet; nothing to unseal.
+		}
+		if !sealed {
+			return nil
+		}
+		return (&vault.Unsealer{SecretsDir: secretsDir}).Unseal()
+	}, 0)
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/kessler-frost/styx/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var (
+	templateSource      string
+	templateDestination string
+	templateCommand     string
+	templateConsulAddr  string
+	templateVaultAddr   string
+	templateVaultToken  string
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Render consul-template sourced config files",
+	Long:  `Render Nomad/Consul/Vault configs through consul-template, resolving Consul KV and Vault secrets at render time instead of baking them in.`,
+}
+
+var templateRenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render a template once and exit",
+	Long:  `Render --source through consul-template into --destination exactly once, the way 'consul-template -once' does.`,
+	RunE:  runTemplateRender,
+}
+
+var templateWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Render a template and keep re-rendering on change",
+	Long: `Render --source into --destination, then keep watching the Consul KV
+paths and Vault secrets it reads for changes, re-rendering (and running
+--command, if set) whenever one changes. Runs until interrupted; this is
+what 'styx supervise' starts in the background alongside Vault and
+Nomad.`,
+	RunE: runTemplateWatch,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{templateRenderCmd, templateWatchCmd} {
+		cmd.Flags().StringVar(&templateSource, "source", "", "Path to the consul-template source file (required)")
+		cmd.Flags().StringVar(&templateDestination, "destination", "", "Path to write the rendered file to (required)")
+		cmd.Flags().StringVar(&templateCommand, "command", "", "Command to run after each successful render")
+		cmd.Flags().StringVar(&templateConsulAddr, "consul-addr", "http://127.0.0.1:8500", "Consul HTTP address")
+		cmd.Flags().StringVar(&templateVaultAddr, "vault-addr", "http://127.0.0.1:8200", "Vault address")
+		cmd.Flags().StringVar(&templateVaultToken, "vault-token", "", "Vault token to render secrets with")
+		cmd.MarkFlagRequired("source")
+		cmd.MarkFlagRequired("destination")
+	}
+
+	templateCmd.AddCommand(templateRenderCmd)
+	templateCmd.AddCommand(templateWatchCmd)
+	rootCmd.AddCommand(templateCmd)
+}
+
+func templateRunnerOptions() template.RunnerOptions {
+	return template.RunnerOptions{
+		ConsulAddr: templateConsulAddr,
+		VaultAddr:  templateVaultAddr,
+		VaultToken: templateVaultToken,
+		Templates: []template.TemplateConfig{{
+			Source:      templateSource,
+			Destination: templateDestination,
+			Perms:       0644,
+			Command:     templateCommand,
+		}},
+	}
+}
+
+func runTemplateRender(cmd *cobra.Command, args []string) error {
+	runner, err := template.NewRunner(templateRunnerOptions(), true)
+	if err != nil {
+		return fmt.Errorf("failed to build template runner: %w", err)
+	}
+
+	if err := runner.Once(); err != nil {
+		return fmt.Errorf("failed to render %s: %w", templateSource, err)
+	}
+
+	fmt.Printf("Rendered %s -> %s\n", templateSource, templateDestination)
+	return nil
+}
+
+func runTemplateWatch(cmd *cobra.Command, args []string) error {
+	runner, err := template.NewRunner(templateRunnerOptions(), false)
+	if err != nil {
+		return fmt.Errorf("failed to build template runner: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Watching %s -> %s\n", templateSource, templateDestination)
+	if err := runner.Watch(ctx); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("template watcher exited: %w", err)
+	}
+
+	fmt.Println("Template watcher stopped")
+	return nil
+}
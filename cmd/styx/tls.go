@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	styxtls "github.com/kessler-frost/styx/internal/tls"
+	"github.com/kessler-frost/styx/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tlsNode        string
+	tlsRole        string
+	tlsDatacenter  string
+	tlsRegion      string
+	tlsWatch       bool
+	tlsForce       bool
+	tlsRotateCA    bool
+	tlsRenewBefore time.Duration
+)
+
+var tlsCmd = &cobra.Command{
+	Use:   "tls",
+	Short: "Manage this node's Consul/Nomad TLS certificates",
+	Long:  `Check and rotate the Consul/Nomad leaf certificates internal/tls issues (see internal/pki for the separate cluster mTLS CA managed by 'styx pki').`,
+}
+
+var tlsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show days-until-expiry for this node's Consul/Nomad certificates",
+	RunE:  runTLSStatus,
+}
+
+var tlsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Re-issue this node's Consul/Nomad leaf certificates if they're close to expiring",
+	Long: `Check this node's Consul and Nomad leaf certificates and re-issue whichever
+is within --renew-before of expiring. With --watch, keep doing this in the
+background and restart Nomad after each re-issue, the way 'styx supervise'
+runs it alongside Vault and the cluster PKI renewer. With --force, re-issue
+both regardless of expiry.
+
+With --rotate-ca, generate brand new Consul and Nomad root CAs first -
+every leaf certificate issued under the old CAs stops verifying
+immediately, so this re-issues this node's own leaves in the same run but
+does not reach out to any other node; run 'styx tls rotate --rotate-ca' on
+every other node in the cluster right after.`,
+	RunE: runTLSRotate,
+}
+
+func init() {
+	tlsRotateCmd.Flags().StringVar(&tlsNode, "node", "", "Node name (informational only)")
+	tlsRotateCmd.Flags().StringVar(&tlsRole, "role", "server", "Node role: server or client")
+	tlsRotateCmd.Flags().StringVar(&tlsDatacenter, "datacenter", "dc1", "Consul datacenter this node's certificate is issued for")
+	tlsRotateCmd.Flags().StringVar(&tlsRegion, "region", "global", "Nomad region this node's certificate is issued for")
+	tlsRotateCmd.Flags().DurationVar(&tlsRenewBefore, "renew-before", styxtls.DefaultRenewBefore, "Re-issue a certificate once it's within this long of expiring")
+	tlsRotateCmd.Flags().BoolVar(&tlsWatch, "watch", false, "Keep checking and reload Nomad after each re-issue, until interrupted")
+	tlsRotateCmd.Flags().BoolVar(&tlsForce, "force", false, "Re-issue both certificates regardless of expiry")
+	tlsRotateCmd.Flags().BoolVar(&tlsRotateCA, "rotate-ca", false, "Generate brand new Consul and Nomad root CAs before re-issuing this node's leaf certificates")
+
+	tlsStatusCmd.Flags().StringVar(&tlsRole, "role", "server", "Node role: server or client")
+	tlsStatusCmd.Flags().StringVar(&tlsDatacenter, "datacenter", "dc1", "Consul datacenter this node's certificate is issued for")
+	tlsStatusCmd.Flags().StringVar(&tlsRegion, "region", "global", "Nomad region this node's certificate is issued for")
+
+	tlsCmd.AddCommand(tlsStatusCmd)
+	tlsCmd.AddCommand(tlsRotateCmd)
+	rootCmd.AddCommand(tlsCmd)
+}
+
+func runTLSStatus(cmd *cobra.Command, args []string) error {
+	if tlsRole != "server" && tlsRole != "client" {
+		return fmt.Errorf("invalid --role %q, want %q or %q", tlsRole, "server", "client")
+	}
+
+	consulFile := filepath.Join(certsDir, fmt.Sprintf("%s-%s-consul-0.pem", tlsDatacenter, tlsRole))
+	nomadFile := filepath.Join(certsDir, fmt.Sprintf("%s-%s-nomad.pem", tlsRegion, tlsRole))
+
+	printExpiry("consul", consulFile)
+	printExpiry("nomad", nomadFile)
+
+	rotationsLog := filepath.Join(certsDir, "rotations.log")
+	if data, err := os.ReadFile(rotationsLog); err == nil && len(data) > 0 {
+		fmt.Printf("\nRecent rotations (%s):\n%s", rotationsLog, data)
+	}
+
+	return nil
+}
+
+// pkiIssuer returns the styxtls.Issuer --pki selects: LocalIssuer for
+// "local" (the zero value, nil means Renewer falls back to it anyway), or a
+// VaultPKIIssuer authorized with this node's Vault root token for "vault".
+func pkiIssuer() (styxtls.Issuer, error) {
+	switch pkiBackend {
+	case "", "local":
+		return styxtls.LocalIssuer{}, nil
+	case "vault":
+		rootToken, err := vault.GetRootToken(secretsDir)
+		if err != nil {
+			return nil, fmt.Errorf("--pki=vault requires a Vault root token: %w", err)
+		}
+		return styxtls.NewVaultPKIIssuer("", rootToken), nil
+	default:
+		return nil, fmt.Errorf("invalid --pki %q, want %q or %q", pkiBackend, "local", "vault")
+	}
+}
+
+func printExpiry(label, certFile string) {
+	days, err := styxtls.DaysUntilExpiry(certFile)
+	if err != nil {
+		fmt.Printf("%s: %v\n", label, err)
+		return
+	}
+	fmt.Printf("%s: %d days until expiry (%s)\n", label, days, certFile)
+}
+
+func runTLSRotate(cmd *cobra.Command, args []string) error {
+	if tlsRole != "server" && tlsRole != "client" {
+		return fmt.Errorf("invalid --role %q, want %q or %q", tlsRole, "server", "client")
+	}
+	if tlsRotateCA && tlsWatch {
+		return fmt.Errorf("--rotate-ca is a one-shot operator action, it can't be combined with --watch")
+	}
+	if tlsForce && tlsWatch {
+		return fmt.Errorf("--force is a one-shot operator action, it can't be combined with --watch")
+	}
+
+	if tlsRotateCA {
+		if pkiBackend == "vault" {
+			return fmt.Errorf("--rotate-ca only applies to --pki=local; rotate pki_consul/pki_nomad's root in Vault instead")
+		}
+		if err := styxtls.RotateCA(certsDir, tlsDatacenter, tlsRegion, tlsRole == "server"); err != nil {
+			return fmt.Errorf("failed to rotate CA: %w", err)
+		}
+		if err := reloadNomad(); err != nil {
+			return fmt.Errorf("leaf certificates re-issued but reload failed: %w", err)
+		}
+		fmt.Println("Consul and Nomad root CAs rotated - re-issue and redeploy every other node's leaf certificate with 'styx tls rotate --rotate-ca'")
+		return nil
+	}
+
+	issuer, err := pkiIssuer()
+	if err != nil {
+		return err
+	}
+
+	renewer := &styxtls.Renewer{
+		CertsDir:    certsDir,
+		Datacenter:  tlsDatacenter,
+		Region:      tlsRegion,
+		IsServer:    tlsRole == "server",
+		Issuer:      issuer,
+		RenewBefore: tlsRenewBefore,
+		Reload:      reloadNomad,
+		LogPath:     filepath.Join(certsDir, "rotations.log"),
+	}
+
+	if tlsForce {
+		renewer.RenewBefore = 100 * 365 * 24 * time.Hour // anything expires "soon" against a century
+		if err := renewer.RenewIfNeeded(); err != nil {
+			return fmt.Errorf("failed to force-rotate leaf certificates: %w", err)
+		}
+		fmt.Println("Consul and Nomad leaf certificates re-issued")
+		return nil
+	}
+
+	if !tlsWatch {
+		if err := renewer.RenewIfNeeded(); err != nil {
+			return fmt.Errorf("failed to rotate leaf certificates: %w", err)
+		}
+		fmt.Println("Consul/Nomad certificates checked (re-issued if either was close to expiring)")
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Watching %s/%s's Consul and Nomad certificates, checking every hour\n", tlsDatacenter, tlsRegion)
+	if err := renewer.Run(ctx); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("tls renewer exited: %w", err)
+	}
+
+	fmt.Println("TLS renewer stopped")
+	return nil
+}
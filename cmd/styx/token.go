@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/bootstrap"
+	"github.com/kessler-frost/styx/internal/jointoken"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tokenTTL  time.Duration
+	tokenUses int
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Mint join tokens for new nodes",
+	Long: `Mint short-lived signed tokens that a joining node presents to this
+server's bootstrap endpoint (see internal/jointoken) to fetch its CA/client
+certificates and gossip key. Run this on the server being joined, then pass
+the printed token to 'styx init --join <ip> --token <token>' (or
+STYX_JOIN_TOKEN) on the joining node.`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Mint a new join token",
+	RunE:  runTokenCreate,
+}
+
+var tokenRmCmd = &cobra.Command{
+	Use:   "rm <token>",
+	Short: "Revoke a join token so it can no longer be redeemed",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTokenRm,
+}
+
+func init() {
+	tokenCreateCmd.Flags().DurationVar(&tokenTTL, "ttl", 15*time.Minute, "How long the token is valid for")
+	tokenCreateCmd.Flags().IntVar(&tokenUses, "uses", 1, "Number of times the token may be redeemed")
+
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenRmCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
+
+func runTokenCreate(cmd *cobra.Command, args []string) error {
+	if tokenUses < 1 {
+		return fmt.Errorf("--uses must be at least 1")
+	}
+
+	nodeName, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+	if nodeName == "" {
+		nodeName = "node1"
+	}
+
+	_, priv, err := jointoken.LoadOrCreateKeyPair(secretsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load or create join-token keypair: %w", err)
+	}
+
+	_, fingerprint, err := bootstrap.LoadOrCreateServerCert(secretsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load or create bootstrap TLS certificate: %w", err)
+	}
+
+	token, err := jointoken.Create(priv, nodeName, tokenTTL, tokenUses, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to mint join token: %w", err)
+	}
+
+	fmt.Println(token)
+	return nil
+}
+
+func runTokenRm(cmd *cobra.Command, args []string) error {
+	claims, err := jointoken.Peek(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read token: %w", err)
+	}
+
+	if err := jointoken.NewUseStore(secretsDir).Revoke(claims.ID); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	fmt.Printf("Revoked token %s\n", claims.ID)
+	return nil
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kessler-frost/styx/internal/diagnostic"
 	"github.com/kessler-frost/styx/internal/setup"
 	"github.com/kessler-frost/styx/internal/tui"
 )
@@ -14,10 +19,28 @@ func runTUI() error {
 
 	// Create TUI model
 	model := tui.New(tui.Options{
-		SetupMode: needsSetup,
-		Prereqs:   prereqs,
+		SetupMode:      needsSetup,
+		Prereqs:        prereqs,
+		ConfigDir:      configDir,
+		DiagnosticAddr: diagnosticAddr,
 	})
 
+	// If requested, start a diagnostic server alongside the TUI, instrumented
+	// with the same api.Client latency metrics the TUI accumulates while
+	// polling Nomad/Vault/Consul. It only needs to outlive p.Run(), so a bare
+	// goroutine (not a supervisor.Tree) is enough - there's no restart policy
+	// to speak of for a single interactive session.
+	if diagnosticAddr != "" {
+		diagServer := diagnostic.NewServer(diagnosticAddr, diagnostic.NewRegistry()).WithHTTPMetrics(model.Metrics())
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			if err := diagServer.Serve(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: diagnostic server on %s failed: %v\n", diagnosticAddr, err)
+			}
+		}()
+	}
+
 	// Run the TUI
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	_, err := p.Run()
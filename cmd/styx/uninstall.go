@@ -12,13 +12,18 @@ import (
 	"github.com/kessler-frost/styx/driver/container"
 	"github.com/kessler-frost/styx/internal/launchd"
 	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/shutdown"
 	"github.com/spf13/cobra"
 )
 
 var (
-	uninstallYes      bool
-	uninstallAll      bool
-	uninstallKeepData bool
+	uninstallYes             bool
+	uninstallAll             bool
+	uninstallKeepData        bool
+	uninstallDryRun          bool
+	uninstallExport          string
+	uninstallForce           bool
+	uninstallContinueOnError bool
 )
 
 var uninstallCmd = &cobra.Command{
@@ -33,7 +38,20 @@ This command will:
 4. Remove styx data directory (~/.styx)
 5. Remove launchd plist
 6. Remove styx binaries (if installed to ~/.local)
-7. Optionally remove Homebrew-installed dependencies (nomad, vault, container, tailscale)`,
+7. Optionally remove Homebrew-installed dependencies (nomad, vault, container, tailscale)
+
+With --dry-run, list everything the above would touch and exit without
+removing anything. With --export <dir>, export each volume's data to
+<dir>/<volume>.tar.zst before removing it; a volume whose export fails is
+left in place unless --force is also given. The command exits non-zero if
+any container or volume couldn't be removed.
+
+By default, uninstall stops at the first step that fails so you can see
+what went wrong before anything later happens to depend on it; pass
+--continue-on-error for the old best-effort behavior, where every step
+runs regardless of earlier failures. Either way, Ctrl-C stops cleanly
+after the step in progress rather than leaving the process killed
+mid-removal.`,
 	RunE: runUninstall,
 }
 
@@ -42,6 +60,32 @@ func init() {
 	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "Skip confirmation prompts")
 	uninstallCmd.Flags().BoolVar(&uninstallAll, "all", false, "Also remove all Homebrew-installed dependencies")
 	uninstallCmd.Flags().BoolVar(&uninstallKeepData, "keep-data", false, "Keep ~/.styx data directory")
+	uninstallCmd.Flags().BoolVar(&uninstallDryRun, "dry-run", false, "List what would be removed without removing anything")
+	uninstallCmd.Flags().StringVar(&uninstallExport, "export", "", "Export each volume's data to <dir>/<volume>.tar.zst before removing it")
+	uninstallCmd.Flags().BoolVar(&uninstallForce, "force", false, "Remove a volume even if exporting it first failed")
+	uninstallCmd.Flags().BoolVar(&uninstallContinueOnError, "continue-on-error", false, "Keep going after a step fails instead of stopping (best-effort teardown)")
+}
+
+// brewDeps lists the Homebrew-installed dependencies uninstall knows how to
+// remove, shared between the dry-run plan and the real removal pass so the
+// two can't drift apart.
+var brewDeps = []struct {
+	name      string
+	checkCmd  string
+	isCask    bool
+	uninstall string
+}{
+	{"nomad", "hashicorp/tap/nomad", false, "brew uninstall hashicorp/tap/nomad"},
+	{"vault", "hashicorp/tap/vault", false, "brew uninstall hashicorp/tap/vault"},
+	{"container", "container", false, "brew uninstall container"},
+	{"tailscale", "tailscale-app", true, "brew uninstall --cask tailscale-app"},
+}
+
+// uninstallStep is one named, independently failable phase of teardown,
+// run in order by runUninstall.
+type uninstallStep struct {
+	name string
+	run  func() error
 }
 
 func runUninstall(cmd *cobra.Command, args []string) error {
@@ -50,6 +94,11 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
+	if uninstallDryRun {
+		printUninstallPlan(home)
+		return nil
+	}
+
 	// Confirm uninstall
 	if !uninstallYes {
 		fmt.Print("This will completely remove styx and all its data. Continue? [y/N]: ")
@@ -61,61 +110,137 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 
 	fmt.Println("Uninstalling styx...")
 
-	// 1. Stop services (ignore errors - may not be running)
-	fmt.Println("  Stopping services...")
-	_ = runStop(nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// 2. Remove containers, volumes
-	fmt.Println("  Removing containers and volumes...")
-	removeContainersAndVolumes()
+	// The coordinator's rollback facility doesn't apply here - there's no
+	// compensating action for "undo a deletion" - but it still gives
+	// uninstall the same clean SIGINT/SIGTERM handling styx init gets: a
+	// Ctrl-C mid-teardown stops after the current step instead of the
+	// process just dying wherever it happened to be.
+	coord := shutdown.NewRollbackCoordinator(func(phase, message string) {
+		fmt.Printf("  [%s] %s\n", phase, message)
+	})
+	coord.WatchContext(cancel)
+	defer coord.Stop()
 
-	// 3. Remove container network
-	fmt.Println("  Removing container network...")
-	if err := network.DeleteStyxNetwork(); err != nil {
-		fmt.Printf("    Warning: could not remove network: %v\n", err)
-	}
+	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
+	localBin := filepath.Join(home, ".local", "bin", "styx")
+	localLib := filepath.Join(home, ".local", "lib", "styx")
 
-	// 4. Remove data directory
+	var summary uninstallSummary
+	steps := []uninstallStep{
+		{"Stopping services", func() error { _ = runStop(nil, nil); return nil }},
+		{"Removing containers and volumes", func() error { summary = removeContainersAndVolumes(); return nil }},
+		{"Removing container network", network.DeleteStyxNetwork},
+	}
 	if !uninstallKeepData {
 		styxDir := filepath.Join(home, ".styx")
-		fmt.Printf("  Removing %s...\n", styxDir)
-		if err := os.RemoveAll(styxDir); err != nil {
-			fmt.Printf("    Warning: could not remove data directory: %v\n", err)
+		steps = append(steps, uninstallStep{fmt.Sprintf("Removing %s", styxDir), func() error { return os.RemoveAll(styxDir) }})
+	}
+	steps = append(steps,
+		uninstallStep{fmt.Sprintf("Removing %s", plistPath), func() error { return removeLaunchdPlist(plistPath) }},
+		uninstallStep{"Removing binaries", func() error { return removeLocalBinaries(localBin, localLib) }},
+		uninstallStep{"Handling dependencies", func() error { removeDependencies(); return nil }},
+	)
+
+	var stepErr error
+	for _, step := range steps {
+		if ctx.Err() != nil {
+			break
+		}
+		fmt.Printf("  %s...\n", step.name)
+		if err := step.run(); err != nil {
+			fmt.Printf("    Warning: %v\n", err)
+			if !uninstallContinueOnError {
+				stepErr = fmt.Errorf("%s: %w", step.name, err)
+				break
+			}
 		}
 	}
 
-	// 5. Remove plist
-	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
-	fmt.Printf("  Removing %s...\n", plistPath)
-	_ = launchd.Unload(plistPath)
-	_ = os.Remove(plistPath)
+	fmt.Println()
+	summary.report()
 
-	// 6. Remove binaries (if installed to ~/.local)
-	localBin := filepath.Join(home, ".local", "bin", "styx")
-	localLib := filepath.Join(home, ".local", "lib", "styx")
-	if _, err := os.Stat(localBin); err == nil {
-		fmt.Printf("  Removing %s...\n", localBin)
-		_ = os.Remove(localBin)
+	if ctx.Err() != nil {
+		return fmt.Errorf("uninstall interrupted: %w", ctx.Err())
 	}
-	if _, err := os.Stat(localLib); err == nil {
-		fmt.Printf("  Removing %s...\n", localLib)
-		_ = os.RemoveAll(localLib)
+	if stepErr != nil {
+		return fmt.Errorf("uninstall stopped early (%w); rerun with --continue-on-error for best-effort teardown", stepErr)
+	}
+	if failed := summary.failed(); failed > 0 {
+		return fmt.Errorf("uninstall finished with %d item(s) not removed, see above", failed)
 	}
 
-	// 7. Handle dependencies
-	fmt.Println()
-	removeDependencies()
-
-	fmt.Println()
 	fmt.Println("Styx uninstalled successfully.")
 	return nil
 }
 
-func removeContainersAndVolumes() {
+// removeLaunchdPlist unloads and removes the launchd plist at path. A
+// plist that's already gone isn't an error - most uninstalls find one.
+func removeLaunchdPlist(path string) error {
+	_ = launchd.Unload(path)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// removeLocalBinaries removes the ~/.local/bin/styx binary and
+// ~/.local/lib/styx support directory, if install.sh put them there.
+func removeLocalBinaries(bin, lib string) error {
+	if _, err := os.Stat(bin); err == nil {
+		if err := os.Remove(bin); err != nil {
+			return fmt.Errorf("removing %s: %w", bin, err)
+		}
+	}
+	if _, err := os.Stat(lib); err == nil {
+		if err := os.RemoveAll(lib); err != nil {
+			return fmt.Errorf("removing %s: %w", lib, err)
+		}
+	}
+	return nil
+}
+
+// uninstallSummary tallies what removeContainersAndVolumes actually did, so
+// runUninstall can report real counts and fail loudly instead of the
+// previous swallow-every-error behavior.
+type uninstallSummary struct {
+	containersRemoved int
+	containersFailed  int
+	volumesRemoved    int
+	volumesSkipped    int
+	volumesFailed     int
+}
+
+func (s uninstallSummary) failed() int {
+	return s.containersFailed + s.volumesSkipped + s.volumesFailed
+}
+
+func (s uninstallSummary) report() {
+	fmt.Println("Summary:")
+	fmt.Printf("  Containers removed: %d", s.containersRemoved)
+	if s.containersFailed > 0 {
+		fmt.Printf(" (%d failed)", s.containersFailed)
+	}
+	fmt.Println()
+	fmt.Printf("  Volumes removed:    %d", s.volumesRemoved)
+	if s.volumesSkipped > 0 {
+		fmt.Printf(" (%d skipped, export failed)", s.volumesSkipped)
+	}
+	if s.volumesFailed > 0 {
+		fmt.Printf(" (%d failed)", s.volumesFailed)
+	}
+	fmt.Println()
+}
+
+func removeContainersAndVolumes() uninstallSummary {
+	var summary uninstallSummary
+
 	binPath, err := exec.LookPath("container")
 	if err != nil {
 		// Container CLI not found, nothing to clean up
-		return
+		return summary
 	}
 	client := container.NewClient(binPath)
 	ctx := context.Background()
@@ -125,33 +250,127 @@ func removeContainersAndVolumes() {
 	if err == nil {
 		for _, c := range containers {
 			_ = client.Stop(ctx, c.Configuration.ID)
-			_ = client.Remove(ctx, c.Configuration.ID)
+			if err := client.Remove(ctx, c.Configuration.ID); err != nil {
+				fmt.Printf("    Warning: could not remove container %s: %v\n", c.Configuration.ID, err)
+				summary.containersFailed++
+				continue
+			}
+			summary.containersRemoved++
 		}
 	}
 
-	// Remove all volumes
+	// Export (if requested) then remove all volumes
 	volumes, err := client.VolumeList(ctx)
-	if err == nil {
-		for _, v := range volumes {
-			_ = client.VolumeRemove(ctx, v.Name)
+	if err != nil {
+		return summary
+	}
+
+	for _, v := range volumes {
+		if uninstallExport != "" {
+			if _, err := client.VolumeExport(ctx, v.Name, uninstallExport); err != nil {
+				fmt.Printf("    Warning: could not export volume %s: %v\n", v.Name, err)
+				if !uninstallForce {
+					fmt.Printf("    Skipping removal of %s (use --force to remove it anyway)\n", v.Name)
+					summary.volumesSkipped++
+					continue
+				}
+			}
+		}
+
+		if err := client.VolumeRemove(ctx, v.Name); err != nil {
+			fmt.Printf("    Warning: could not remove volume %s: %v\n", v.Name, err)
+			summary.volumesFailed++
+			continue
+		}
+		summary.volumesRemoved++
+	}
+
+	return summary
+}
+
+// printUninstallPlan reports everything runUninstall would touch - without
+// stopping services, exporting, or removing anything - so an operator can
+// check what's there before committing to it.
+func printUninstallPlan(home string) {
+	fmt.Println("Dry run - nothing will be removed.")
+	fmt.Println()
+
+	binPath, err := exec.LookPath("container")
+	if err != nil {
+		fmt.Println("Containers: (container CLI not found)")
+		fmt.Println("Volumes:    (container CLI not found)")
+	} else {
+		client := container.NewClient(binPath)
+		ctx := context.Background()
+
+		fmt.Println("Containers:")
+		if containers, err := client.List(ctx, true); err == nil && len(containers) > 0 {
+			for _, c := range containers {
+				fmt.Printf("  - %s\n", c.Configuration.ID)
+			}
+		} else {
+			fmt.Println("  (none)")
 		}
+
+		fmt.Println("Volumes:")
+		if volumes, err := client.VolumeList(ctx); err == nil && len(volumes) > 0 {
+			for _, v := range volumes {
+				if uninstallExport != "" {
+					fmt.Printf("  - %s (would export to %s)\n", v.Name, filepath.Join(uninstallExport, v.Name+".tar.zst"))
+				} else {
+					fmt.Printf("  - %s\n", v.Name)
+				}
+			}
+		} else {
+			fmt.Println("  (none)")
+		}
+	}
+
+	fmt.Println("Network:")
+	fmt.Println("  - styx")
+
+	if !uninstallKeepData {
+		fmt.Println("Data directory:")
+		fmt.Printf("  - %s\n", filepath.Join(home, ".styx"))
+	}
+
+	fmt.Println("Launchd plist:")
+	fmt.Printf("  - %s\n", filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist"))
+
+	localBin := filepath.Join(home, ".local", "bin", "styx")
+	localLib := filepath.Join(home, ".local", "lib", "styx")
+	_, binErr := os.Stat(localBin)
+	_, libErr := os.Stat(localLib)
+	if binErr == nil || libErr == nil {
+		fmt.Println("Binaries:")
+		if binErr == nil {
+			fmt.Printf("  - %s\n", localBin)
+		}
+		if libErr == nil {
+			fmt.Printf("  - %s\n", localLib)
+		}
+	}
+
+	fmt.Println("Homebrew dependencies:")
+	any := false
+	for _, dep := range brewDeps {
+		if !isBrewInstalled(dep.checkCmd, dep.isCask) {
+			continue
+		}
+		any = true
+		if uninstallAll {
+			fmt.Printf("  - %s (would remove)\n", dep.name)
+		} else {
+			fmt.Printf("  - %s (would prompt, unless --all)\n", dep.name)
+		}
+	}
+	if !any {
+		fmt.Println("  (none installed via Homebrew)")
 	}
 }
 
 func removeDependencies() {
-	deps := []struct {
-		name      string
-		checkCmd  string
-		isCask    bool
-		uninstall string
-	}{
-		{"nomad", "hashicorp/tap/nomad", false, "brew uninstall hashicorp/tap/nomad"},
-		{"vault", "hashicorp/tap/vault", false, "brew uninstall hashicorp/tap/vault"},
-		{"container", "container", false, "brew uninstall container"},
-		{"tailscale", "tailscale-app", true, "brew uninstall --cask tailscale-app"},
-	}
-
-	for _, dep := range deps {
+	for _, dep := range brewDeps {
 		if !isBrewInstalled(dep.checkCmd, dep.isCask) {
 			continue
 		}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/api"
+	"github.com/kessler-frost/styx/internal/launchd"
+	"github.com/kessler-frost/styx/internal/setup"
+	"github.com/kessler-frost/styx/internal/snapshot"
+	"github.com/kessler-frost/styx/internal/upgrade"
+	"github.com/spf13/cobra"
+)
+
+var upgradeTo string
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Roll the cluster forward to a new Nomad/Vault version",
+	Long: `Upgrade this node's Nomad and Vault to --to <version>.
+
+Takes a pre-flight snapshot, drains this node's Nomad client, installs the
+new version, restarts the service, and verifies quorum through
+GetClusterStatus. If the new version fails to come back healthy, the
+snapshot is used to roll back automatically.`,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeTo, "to", "", "Version to upgrade Nomad/Vault to (required)")
+	upgradeCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	if err := upgrade.WriteState(configDir, upgrade.State{TargetVersion: upgradeTo, StartedAt: time.Now()}); err != nil {
+		return fmt.Errorf("failed to record upgrade state: %w", err)
+	}
+
+	client := api.NewClient()
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
+
+	snapshotPath := filepath.Join(dataDir, fmt.Sprintf("styx-pre-upgrade-%s.tar.gz", time.Now().Format("20060102-150405")))
+	fmt.Printf("Taking pre-flight snapshot (%s)...\n", snapshotPath)
+	if _, err := createClusterSnapshot(snapshotPath); err != nil {
+		upgrade.ClearState(configDir)
+		return fmt.Errorf("failed to take pre-flight snapshot: %w", err)
+	}
+
+	nodeID, err := localNodeID(client)
+	if err != nil {
+		fmt.Printf("Warning: failed to resolve local node ID, skipping drain: %v\n", err)
+	} else {
+		fmt.Println("Draining this node's Nomad client...")
+		if err := client.DrainNode(nodeID, true); err != nil {
+			fmt.Printf("Warning: failed to drain node: %v\n", err)
+		}
+	}
+
+	fmt.Printf("Installing Nomad/Vault %s...\n", upgradeTo)
+	if err := installVersion("nomad", upgradeTo); err != nil {
+		return rollback(snapshotPath, plistPath, fmt.Errorf("failed to install nomad %s: %w", upgradeTo, err))
+	}
+	if err := installVersion("vault", upgradeTo); err != nil {
+		return rollback(snapshotPath, plistPath, fmt.Errorf("failed to install vault %s: %w", upgradeTo, err))
+	}
+
+	fmt.Println("Restarting Styx...")
+	if err := launchd.Reload("com.styx.nomad", plistPath); err != nil {
+		return rollback(snapshotPath, plistPath, fmt.Errorf("failed to reload service: %w", err))
+	}
+
+	fmt.Println("Waiting for Nomad to become healthy...")
+	if err := waitForService("nomad", "http://127.0.0.1:4646/v1/agent/health", 60*time.Second); err != nil {
+		return rollback(snapshotPath, plistPath, fmt.Errorf("nomad did not come back healthy: %w", err))
+	}
+
+	status := client.GetClusterStatus()
+	if status.Nomad.Status != "healthy" || status.Vault.Status == "not_responding" {
+		return rollback(snapshotPath, plistPath, fmt.Errorf("health gate failed: nomad=%s vault=%s", status.Nomad.Status, status.Vault.Status))
+	}
+
+	if nodeID != "" {
+		fmt.Println("Undraining node...")
+		if err := client.DrainNode(nodeID, false); err != nil {
+			fmt.Printf("Warning: failed to undrain node: %v\n", err)
+		}
+	}
+
+	if err := upgrade.ClearState(configDir); err != nil {
+		fmt.Printf("Warning: failed to clear upgrade state: %v\n", err)
+	}
+
+	fmt.Printf("Upgrade to %s complete.\n", upgradeTo)
+	return nil
+}
+
+// localNodeID finds this node's Nomad node ID by matching Node.Name
+// against the local hostname, so DrainNode can target it without the
+// operator having to look it up with `nomad node status` first.
+func localNodeID(client *api.Client) (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+
+	nodes, err := client.GetNodes("")
+	if err != nil {
+		return "", err
+	}
+	for _, n := range nodes {
+		if n.Name == hostname {
+			return n.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no Nomad node found matching hostname %q", hostname)
+}
+
+// installVersion upgrades name (nomad or vault) to version through the
+// current package manager backend. Homebrew's formulae aren't usually
+// version-pinned, so this upgrades to whatever `brew upgrade` resolves and
+// just logs the requested version; apt/dnf don't have a single idiomatic
+// cross-distro version-pinned install, so every other backend returns an
+// error asking the operator to upgrade by hand rather than guessing
+// package naming.
+func installVersion(name, version string) error {
+	switch setup.CurrentManagerKind() {
+	case setup.Homebrew:
+		cmd := exec.Command("brew", "upgrade", name)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%w\nOutput: %s", err, output)
+		}
+		fmt.Printf("Upgraded %s via Homebrew (requested version %s)\n", name, version)
+		return nil
+	default:
+		return fmt.Errorf("version-pinned installs aren't supported on %s; upgrade %s to %s manually and re-run 'styx upgrade'", setup.CurrentManagerKind(), name, version)
+	}
+}
+
+// rollback restores the pre-flight snapshot, reloads the service, clears
+// the upgrade state, and returns origErr wrapped with the rollback outcome.
+func rollback(snapshotPath, plistPath string, origErr error) error {
+	fmt.Printf("Upgrade failed (%v), rolling back from %s...\n", origErr, snapshotPath)
+
+	restoreErr := snapshot.Restore(snapshotPath, snapshot.RestoreOptions{
+		ConfigDir:    configDir,
+		NomadDataDir: dataDir,
+		VaultDataDir: vaultDataDir,
+		PlistPath:    plistPath,
+	})
+
+	if err := upgrade.ClearState(configDir); err != nil {
+		fmt.Printf("Warning: failed to clear upgrade state: %v\n", err)
+	}
+
+	if restoreErr != nil {
+		return fmt.Errorf("%w (rollback also failed: %v)", origErr, restoreErr)
+	}
+	return fmt.Errorf("%w (rolled back to pre-upgrade snapshot)", origErr)
+}
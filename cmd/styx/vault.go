@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kessler-frost/styx/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var (
+	vaultSecretShares    int
+	vaultSecretThreshold int
+	vaultDestination     string
+	vaultKMSKeyID        string
+	vaultShamirPeers     []string
+	vaultShamirThreshold int
+	vaultTransitAddr     string
+	vaultTransitKeyName  string
+	vaultTransitToken    string
+	vaultPassphrase      string
+	vaultPrintShares     bool
+	vaultRekeyShares     int
+	vaultRekeyThreshold  int
+)
+
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Manage the cluster's Vault instance",
+	Long:  `Initialize, unseal, check the status of, and rotate the root token of Styx's Vault instance.`,
+}
+
+var vaultInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize Vault and provision the Nomad integration",
+	Long: `Initialize Vault if it isn't already, persist the unseal keys and root
+token to the chosen destination, unseal it, and provision the classic
+nomad-cluster token role used by ServerConfig.VaultToken.
+
+Safe to run against an already-initialized Vault: init is skipped and the
+stored init output is reloaded instead.`,
+	RunE: runVaultInit,
+}
+
+var vaultUnsealCmd = &cobra.Command{
+	Use:   "unseal",
+	Short: "Unseal Vault using the stored unseal keys",
+	Long:  `Re-unseal Vault after a host restart, using whichever destination 'styx vault init' persisted the unseal keys to. A no-op if Vault is already unsealed.`,
+	RunE:  runVaultUnseal,
+}
+
+var vaultStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show Vault's initialization and seal status",
+	RunE:  runVaultStatus,
+}
+
+var vaultRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate Vault's root token",
+	Long:  `Generate a new root token, persist it, and revoke the previous one. Idempotent: running it again just rotates again.`,
+	RunE:  runVaultRotate,
+}
+
+var vaultRekeyCmd = &cobra.Command{
+	Use:   "rekey",
+	Short: "Rekey Vault, replacing its unseal key shares",
+	Long:  `Generate a new set of unseal key shares, authorized by submitting the existing shares, and persist the replacement to the same destination the current shares were loaded from. The root token is unaffected; use 'styx vault rotate' for that.`,
+	RunE:  runVaultRekey,
+}
+
+func init() {
+	vaultInitCmd.Flags().IntVar(&vaultSecretShares, "secret-shares", 1, "Number of unseal key shares to generate")
+	vaultInitCmd.Flags().IntVar(&vaultSecretThreshold, "secret-threshold", 1, "Number of key shares required to unseal")
+	vaultInitCmd.Flags().StringVar(&vaultDestination, "destination", "file", "Where to persist unseal keys/root token: file, keychain, shamir-files, awskms, gcpckms, shamir, transit, or passphrase")
+	vaultInitCmd.Flags().StringVar(&vaultKMSKeyID, "kms-key-id", "", "KMS key to encrypt under, for --destination=awskms|gcpckms")
+	vaultInitCmd.Flags().StringSliceVar(&vaultShamirPeers, "shamir-peers", nil, "Tailscale IPs to deposit shares with, for --destination=shamir")
+	vaultInitCmd.Flags().IntVar(&vaultShamirThreshold, "shamir-threshold", 0, "Number of --shamir-peers that must be reachable to reconstruct the init output, for --destination=shamir")
+	vaultInitCmd.Flags().StringVar(&vaultTransitAddr, "transit-addr", "", "Second Vault's address to auto-unseal through, for --destination=transit")
+	vaultInitCmd.Flags().StringVar(&vaultTransitKeyName, "transit-key-name", "", "Transit key to encrypt/decrypt under, for --destination=transit")
+	vaultInitCmd.Flags().StringVar(&vaultTransitToken, "transit-token", os.Getenv("STYX_VAULT_TRANSIT_TOKEN"), "Token authorizing transit encrypt/decrypt calls, for --destination=transit (or set STYX_VAULT_TRANSIT_TOKEN)")
+	vaultInitCmd.Flags().StringVar(&vaultPassphrase, "passphrase", os.Getenv("STYX_VAULT_PASSPHRASE"), "Operator passphrase to wrap the init output under, for --destination=passphrase (or set STYX_VAULT_PASSPHRASE)")
+	vaultInitCmd.Flags().BoolVar(&vaultPrintShares, "print-shares", false, "Print the unseal key shares and root token to stdout after Vault is initialized, for distribution to operators")
+
+	vaultRekeyCmd.Flags().IntVar(&vaultRekeyShares, "key-shares", 5, "Number of new unseal key shares to generate")
+	vaultRekeyCmd.Flags().IntVar(&vaultRekeyThreshold, "key-threshold", 3, "Number of new key shares required to unseal")
+	vaultRekeyCmd.Flags().BoolVar(&vaultPrintShares, "print-shares", false, "Print the new unseal key shares to stdout after rekeying, for distribution to operators")
+
+	vaultCmd.AddCommand(vaultInitCmd)
+	vaultCmd.AddCommand(vaultUnsealCmd)
+	vaultCmd.AddCommand(vaultStatusCmd)
+	vaultCmd.AddCommand(vaultRotateCmd)
+	vaultCmd.AddCommand(vaultRekeyCmd)
+	rootCmd.AddCommand(vaultCmd)
+}
+
+func runVaultInit(cmd *cobra.Command, args []string) error {
+	opts := vault.BootstrapOptions{
+		SecretShares:    vaultSecretShares,
+		SecretThreshold: vaultSecretThreshold,
+		Destination:     vault.KeyDestination(vaultDestination),
+		SecretsDir:      secretsDir,
+		KMSKeyID:        vaultKMSKeyID,
+		ShamirPeers:     vaultShamirPeers,
+		ShamirThreshold: vaultShamirThreshold,
+		TransitAddr:     vaultTransitAddr,
+		TransitKeyName:  vaultTransitKeyName,
+		TransitToken:    vaultTransitToken,
+		Passphrase:      vaultPassphrase,
+	}
+
+	fmt.Println("Initializing Vault...")
+	out, err := vault.NewBootstrapper(opts).Run()
+	if err != nil {
+		return fmt.Errorf("failed to initialize vault: %w", err)
+	}
+
+	fmt.Println("Vault initialized, unsealed, and nomad-cluster integration provisioned")
+	if vaultPrintShares {
+		fmt.Println("\nUnseal key shares (distribute to operators):")
+		for i, key := range out.UnsealKeysB64 {
+			fmt.Printf("  Key %d: %s\n", i+1, key)
+		}
+		fmt.Printf("Root token: %s\n", out.RootToken)
+	}
+	return nil
+}
+
+func runVaultRekey(cmd *cobra.Command, args []string) error {
+	fmt.Println("Rekeying Vault...")
+	out, err := vault.Rekey(secretsDir, vaultRekeyShares, vaultRekeyThreshold)
+	if err != nil {
+		return fmt.Errorf("failed to rekey vault: %w", err)
+	}
+
+	fmt.Println("Vault rekeyed")
+	if vaultPrintShares {
+		fmt.Println("\nNew unseal key shares (distribute to operators):")
+		for i, key := range out.UnsealKeysB64 {
+			fmt.Printf("  Key %d: %s\n", i+1, key)
+		}
+	}
+	return nil
+}
+
+func runVaultUnseal(cmd *cobra.Command, args []string) error {
+	unsealer := &vault.Unsealer{SecretsDir: secretsDir}
+	if err := unsealer.Unseal(); err != nil {
+		return fmt.Errorf("failed to unseal vault: %w", err)
+	}
+	fmt.Println("Vault unsealed")
+	return nil
+}
+
+func runVaultStatus(cmd *cobra.Command, args []string) error {
+	status, err := vault.GetStatus()
+	if err != nil {
+		return fmt.Errorf("failed to get vault status: %w", err)
+	}
+
+	fmt.Printf("Initialized: %t\n", status.Initialized)
+	fmt.Printf("Sealed:      %t\n", status.Sealed)
+	return nil
+}
+
+func runVaultRotate(cmd *cobra.Command, args []string) error {
+	fmt.Println("Rotating Vault root token...")
+	if _, err := vault.Rotate(secretsDir); err != nil {
+		return fmt.Errorf("failed to rotate root token: %w", err)
+	}
+	fmt.Println("Root token rotated")
+	return nil
+}
@@ -0,0 +1,49 @@
+// Package capabilities defines the Linux capability whitelist StartTask
+// validates TaskConfig.CapAdd/CapDrop against, analogous to Nomad's own
+// docker driver defaults (defaults_default.go).
+package capabilities
+
+import "fmt"
+
+// DefaultAllowed is the conservative set of capabilities a task may add
+// without the plugin-level config widening Config.AllowCaps. It mirrors
+// the capabilities Docker itself grants a container by default.
+var DefaultAllowed = []string{
+	"AUDIT_WRITE",
+	"CHOWN",
+	"DAC_OVERRIDE",
+	"FOWNER",
+	"FSETID",
+	"KILL",
+	"MKNOD",
+	"NET_BIND_SERVICE",
+	"NET_RAW",
+	"SETFCAP",
+	"SETGID",
+	"SETPCAP",
+	"SETUID",
+	"SYS_CHROOT",
+}
+
+// Validate checks capAdd/capDrop against allowed, the effective whitelist
+// (Config.AllowCaps when set, DefaultAllowed otherwise), returning an error
+// naming the first capability that isn't permitted.
+func Validate(capAdd, capDrop, allowed []string) error {
+	set := make(map[string]bool, len(allowed))
+	for _, c := range allowed {
+		set[c] = true
+	}
+
+	for _, c := range capAdd {
+		if !set[c] {
+			return fmt.Errorf("capability %q is not in the allowed list", c)
+		}
+	}
+	for _, c := range capDrop {
+		if !set[c] {
+			return fmt.Errorf("capability %q is not in the allowed list", c)
+		}
+	}
+
+	return nil
+}
@@ -14,20 +14,70 @@ var (
 			hclspec.NewAttr("container_bin_path", "string", false),
 			hclspec.NewLiteral(`""`),
 		),
+		"reconcile": hclspec.NewDefault(
+			hclspec.NewBlock("reconcile", false, hclspec.NewObject(map[string]*hclspec.Spec{
+				"enabled": hclspec.NewDefault(
+					hclspec.NewAttr("enabled", "bool", false),
+					hclspec.NewLiteral("false"),
+				),
+				"dry_run": hclspec.NewDefault(
+					hclspec.NewAttr("dry_run", "bool", false),
+					hclspec.NewLiteral("false"),
+				),
+				"period": hclspec.NewDefault(
+					hclspec.NewAttr("period", "string", false),
+					hclspec.NewLiteral(`"5m"`),
+				),
+				"creation_grace": hclspec.NewDefault(
+					hclspec.NewAttr("creation_grace", "string", false),
+					hclspec.NewLiteral(`"1m"`),
+				),
+			})),
+			hclspec.NewLiteral("{}"),
+		),
+		"allow_privileged": hclspec.NewDefault(
+			hclspec.NewAttr("allow_privileged", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"allow_caps": hclspec.NewAttr("allow_caps", "list(string)", false),
 	})
 
 	// taskConfigSpec is the HCL specification for the task configuration
 	taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
-		"image": hclspec.NewAttr("image", "string", true),
-		"command": hclspec.NewAttr("command", "string", false),
-		"args": hclspec.NewAttr("args", "list(string)", false),
-		"env": hclspec.NewAttr("env", "list(map(string))", false),
-		"ports": hclspec.NewAttr("ports", "list(string)", false),
-		"volumes": hclspec.NewAttr("volumes", "list(string)", false),
-		"memory": hclspec.NewAttr("memory", "string", false),
-		"cpus": hclspec.NewAttr("cpus", "number", false),
-		"working_dir": hclspec.NewAttr("working_dir", "string", false),
-		"network": hclspec.NewAttr("network", "string", false),
+		"image":                hclspec.NewAttr("image", "string", true),
+		"command":              hclspec.NewAttr("command", "string", false),
+		"args":                 hclspec.NewAttr("args", "list(string)", false),
+		"env":                  hclspec.NewAttr("env", "list(map(string))", false),
+		"ports":                hclspec.NewAttr("ports", "list(string)", false),
+		"volumes":              hclspec.NewAttr("volumes", "list(string)", false),
+		"memory":               hclspec.NewAttr("memory", "string", false),
+		"cpus":                 hclspec.NewAttr("cpus", "number", false),
+		"working_dir":          hclspec.NewAttr("working_dir", "string", false),
+		"network":              hclspec.NewAttr("network", "string", false),
+		"labels":               hclspec.NewAttr("labels", "list(map(string))", false),
+		"service_address_mode": hclspec.NewAttr("service_address_mode", "string", false),
+		"max_files":            hclspec.NewAttr("max_files", "number", false),
+		"max_file_size_mb":     hclspec.NewAttr("max_file_size_mb", "number", false),
+		"dns_servers":          hclspec.NewAttr("dns_servers", "list(string)", false),
+		"dns_search_domains":   hclspec.NewAttr("dns_search_domains", "list(string)", false),
+		"dns_options":          hclspec.NewAttr("dns_options", "list(string)", false),
+		"extra_hosts":          hclspec.NewAttr("extra_hosts", "list(string)", false),
+		"cap_add":              hclspec.NewAttr("cap_add", "list(string)", false),
+		"cap_drop":             hclspec.NewAttr("cap_drop", "list(string)", false),
+		"privileged": hclspec.NewDefault(
+			hclspec.NewAttr("privileged", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"pull_policy": hclspec.NewDefault(
+			hclspec.NewAttr("pull_policy", "string", false),
+			hclspec.NewLiteral(`"missing"`),
+		),
+		"image_pull_timeout": hclspec.NewAttr("image_pull_timeout", "string", false),
+		"auth": hclspec.NewBlock("auth", false, hclspec.NewObject(map[string]*hclspec.Spec{
+			"username":       hclspec.NewAttr("username", "string", false),
+			"password":       hclspec.NewAttr("password", "string", false),
+			"auth_soft_fail": hclspec.NewAttr("auth_soft_fail", "bool", false),
+		})),
 	})
 )
 
@@ -37,6 +87,41 @@ type Config struct {
 	// ContainerBinPath is the path to the Apple container CLI binary.
 	// If empty, the driver will look up "container" in PATH.
 	ContainerBinPath string `codec:"container_bin_path"`
+
+	// Reconcile configures the background reconciler that removes dangling
+	// containers - ones left running by a driver or Nomad crash between
+	// client.Run succeeding and SetDriverState persisting the task.
+	Reconcile ReconcileConfig `codec:"reconcile"`
+
+	// AllowPrivileged allows task configs to set privileged = true. Off by
+	// default, matching Nomad's own docker driver.
+	AllowPrivileged bool `codec:"allow_privileged"`
+
+	// AllowCaps is the whitelist StartTask validates task configs'
+	// cap_add/cap_drop against. Empty uses capabilities.DefaultAllowed.
+	AllowCaps []string `codec:"allow_caps"`
+}
+
+// ReconcileConfig controls the dangling-container reconciler, mirroring the
+// shape of Nomad's own docker driver reconciler.
+type ReconcileConfig struct {
+	// Enabled turns the background reconciler on. Off by default since it's
+	// an extra periodic scan of the container runtime.
+	Enabled bool `codec:"enabled"`
+
+	// DryRun logs what the reconciler would remove instead of removing it -
+	// useful for validating CreationGrace before trusting it in production.
+	DryRun bool `codec:"dry_run"`
+
+	// Period is how often the reconciler scans for dangling containers, as
+	// a Go duration string (e.g. "5m").
+	Period string `codec:"period"`
+
+	// CreationGrace is how long a styx-managed container may exist without
+	// a matching live task before the reconciler considers it dangling, as
+	// a Go duration string (e.g. "1m"). Gives StartTask time to finish
+	// SetDriverState before the container becomes a reconcile target.
+	CreationGrace string `codec:"creation_grace"`
 }
 
 // TaskConfig contains the driver-specific configuration for running a container.
@@ -75,8 +160,96 @@ type TaskConfig struct {
 	// Network specifies which container network to use.
 	// Defaults to the "styx" network for container-to-container communication.
 	Network string `codec:"network"`
+
+	// Labels are arbitrary key/value metadata applied to the container
+	// (e.g. "styx.funnel" = "true" to opt a job into Tailscale Funnel;
+	// see tailserve.JobFunnelEnabled).
+	Labels hclutils.MapStrStr `codec:"labels"`
+
+	// ServiceAddressMode controls how StartTask builds the DriverNetwork
+	// Nomad uses to register this task's services, mirroring Nomad's own
+	// service block address_mode:
+	//   - "auto" (default): container IP, AutoAdvertise true - today's
+	//     behavior, routed through Traefik on the styx network.
+	//   - "host": the host's preferred IP and Nomad's allocated host ports,
+	//     for services that should be reached directly on the host.
+	//   - "driver": the container IP with AutoAdvertise false, so Consul
+	//     checks target the container directly instead of through Traefik.
+	ServiceAddressMode string `codec:"service_address_mode"`
+
+	// MaxFiles is how many rotated generations of each log stream (stdout,
+	// stderr) the log shipper (see driver/logstream) keeps. Defaults to
+	// logstream.DefaultMaxFiles if unset.
+	MaxFiles int `codec:"max_files"`
+
+	// MaxFileSizeMB is the size, in megabytes, at which the log shipper
+	// rotates a log stream. Defaults to logstream.DefaultMaxFileSizeMB if
+	// unset.
+	MaxFileSizeMB int `codec:"max_file_size_mb"`
+
+	// DNSServers, DNSSearchDomains, and DNSOptions, when any is set, make
+	// StartTask generate a resolv.conf and mount it at /etc/resolv.conf,
+	// following the same shared resolvconf approach Nomad's exec/docker
+	// drivers use.
+	DNSServers       []string `codec:"dns_servers"`
+	DNSSearchDomains []string `codec:"dns_search_domains"`
+	DNSOptions       []string `codec:"dns_options"`
+
+	// ExtraHosts are "host:ip" entries StartTask appends to a generated
+	// /etc/hosts fragment mounted into the container.
+	ExtraHosts []string `codec:"extra_hosts"`
+
+	// CapAdd and CapDrop list Linux capabilities to add/drop, validated by
+	// StartTask against driver/capabilities' whitelist (widened by
+	// Config.AllowCaps).
+	CapAdd  []string `codec:"cap_add"`
+	CapDrop []string `codec:"cap_drop"`
+
+	// Privileged runs the container with extended privileges. Rejected by
+	// StartTask unless the plugin config sets allow_privileged = true.
+	Privileged bool `codec:"privileged"`
+
+	// PullPolicy controls when StartTask pulls Image via the coordinator:
+	// coordinator.PullAlways, coordinator.PullMissing (default), or
+	// coordinator.PullNever.
+	PullPolicy string `codec:"pull_policy"`
+
+	// ImagePullTimeout bounds a single pull attempt, as a Go duration
+	// string (e.g. "2m"). Empty means no per-attempt timeout.
+	ImagePullTimeout string `codec:"image_pull_timeout"`
+
+	// Auth carries optional registry credentials for pulling Image.
+	Auth AuthConfig `codec:"auth"`
 }
 
+// AuthConfig carries the registry credentials StartTask forwards to the
+// pull coordinator for a single task's image.
+type AuthConfig struct {
+	Username string `codec:"username"`
+	Password string `codec:"password"`
+
+	// AuthSoftFail, like Nomad's docker driver auth_soft_fail, lets
+	// StartTask continue with an already-present local image if the
+	// authenticated pull fails instead of failing the task outright.
+	AuthSoftFail bool `codec:"auth_soft_fail"`
+}
+
+// Service address modes for TaskConfig.ServiceAddressMode, matching Nomad's
+// service block address_mode values.
+const (
+	AddressModeAuto   = "auto"
+	AddressModeHost   = "host"
+	AddressModeDriver = "driver"
+)
+
+// Labels StartTask stamps onto every container it creates, so the
+// reconciler (see reconcile.go) can tell styx-managed containers apart
+// from anything else running under the same container runtime.
+const (
+	managedLabel   = "styx.managed"
+	createdAtLabel = "styx.created-at"
+)
+
 // TaskState is the state which is encoded in the handle returned to Nomad client.
 // This information is needed to rebuild the task state and handler during
 // temporary unavailability of the driver (e.g., when the driver is upgraded).
@@ -89,4 +262,9 @@ type TaskState struct {
 
 	// StartedAt is the timestamp when the container was started.
 	StartedAt time.Time
+
+	// LogShipperPID is the PID of the driver/logstream subprocess shipping
+	// this task's container logs into its Nomad log fifos, so RecoverTask
+	// can tell whether it's still alive or needs restarting.
+	LogShipperPID int
 }
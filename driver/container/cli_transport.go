@@ -0,0 +1,954 @@
+package container
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/tracing"
+)
+
+// pollBackoffMin/Max bound Wait's Inspect-polling fallback: it starts at
+// pollBackoffMin and doubles after each check that finds the container
+// still running, up to pollBackoffMax.
+const (
+	pollBackoffMin = 2 * time.Second
+	pollBackoffMax = 30 * time.Second
+)
+
+// CLITransport drives the Apple container CLI as a subprocess for every
+// operation, parsing its stdout. It's the original implementation and
+// remains the portable fallback (see NewClient) for hosts where the
+// container daemon isn't running a local API server to talk to directly.
+type CLITransport struct {
+	binPath string
+}
+
+// NewCLITransport creates a Transport that shells out to binPath.
+func NewCLITransport(binPath string) *CLITransport {
+	return &CLITransport{binPath: binPath}
+}
+
+// BinPath returns the path to the container binary
+func (c *CLITransport) BinPath() string {
+	return c.binPath
+}
+
+// IsAvailable checks if the container CLI is available
+func (c *CLITransport) IsAvailable() bool {
+	_, err := exec.LookPath(c.binPath)
+	return err == nil
+}
+
+// Run starts a new container and returns its ID
+func (c *CLITransport) Run(ctx context.Context, opts RunOptions) (string, error) {
+	args := []string{"run"}
+
+	if opts.Name != "" {
+		args = append(args, "--name", opts.Name)
+	}
+
+	if opts.Detach {
+		args = append(args, "-d")
+	}
+
+	if opts.Remove {
+		args = append(args, "--rm")
+	}
+
+	if opts.Memory != "" {
+		args = append(args, "-m", opts.Memory)
+	}
+
+	if opts.CPUs > 0 {
+		args = append(args, "-c", strconv.Itoa(opts.CPUs))
+	}
+
+	if opts.WorkingDir != "" {
+		args = append(args, "-w", opts.WorkingDir)
+	}
+
+	if opts.Network != "" {
+		args = append(args, "--network", opts.Network)
+	}
+
+	for k, v := range opts.Env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+
+	for k, v := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, v))
+	}
+
+	for _, port := range opts.Ports {
+		args = append(args, "-p", port)
+	}
+
+	for _, vol := range opts.Volumes {
+		args = append(args, "-v", vol)
+	}
+
+	for _, server := range opts.DNSServers {
+		args = append(args, "--dns", server)
+	}
+
+	for _, domain := range opts.DNSSearchDomains {
+		args = append(args, "--dns-search", domain)
+	}
+
+	for _, option := range opts.DNSOptions {
+		args = append(args, "--dns-option", option)
+	}
+
+	for _, host := range opts.ExtraHosts {
+		args = append(args, "--add-host", host)
+	}
+
+	for _, capability := range opts.CapAdd {
+		args = append(args, "--cap-add", capability)
+	}
+
+	for _, capability := range opts.CapDrop {
+		args = append(args, "--cap-drop", capability)
+	}
+
+	if opts.Privileged {
+		args = append(args, "--privileged")
+	}
+
+	args = append(args, opts.Image)
+
+	if opts.Command != "" {
+		args = append(args, opts.Command)
+	}
+
+	args = append(args, opts.Args...)
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", classifyCLIError(string(exitErr.Stderr), "container run failed")
+		}
+		return "", fmt.Errorf("container run failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Stop stops a running container
+func (c *CLITransport) Stop(ctx context.Context, id string) error {
+	_, span := tracing.StartSpan(ctx, "container.stop")
+	span.SetAttribute("id", id)
+	defer span.End()
+
+	cmd := exec.CommandContext(ctx, c.binPath, "stop", id)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		err = classifyCLIError(string(output), "container stop failed")
+		span.SetError(err)
+		return err
+	}
+	return nil
+}
+
+// Remove removes a container
+func (c *CLITransport) Remove(ctx context.Context, id string) error {
+	_, span := tracing.StartSpan(ctx, "container.remove")
+	span.SetAttribute("id", id)
+	defer span.End()
+
+	cmd := exec.CommandContext(ctx, c.binPath, "rm", id)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		err = classifyCLIError(string(output), "container rm failed")
+		span.SetError(err)
+		return err
+	}
+	return nil
+}
+
+// Kill sends a signal to a container
+func (c *CLITransport) Kill(ctx context.Context, id string, signal string) error {
+	args := []string{"kill"}
+	if signal != "" {
+		args = append(args, "-s", signal)
+	}
+	args = append(args, id)
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return classifyCLIError(string(output), "container kill failed")
+	}
+	return nil
+}
+
+// Inspect returns detailed information about a container
+func (c *CLITransport) Inspect(ctx context.Context, id string) (*ContainerInfo, error) {
+	cmd := exec.CommandContext(ctx, c.binPath, "inspect", id)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, classifyCLIError(string(exitErr.Stderr), "container inspect failed")
+		}
+		return nil, fmt.Errorf("container inspect failed: %w", err)
+	}
+
+	var containers []ContainerInfo
+	if err := json.Unmarshal(output, &containers); err != nil {
+		return nil, fmt.Errorf("failed to parse inspect output: %w", err)
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("container not found: %s: %w", id, ErrNotFound)
+	}
+
+	return &containers[0], nil
+}
+
+// List returns all containers
+func (c *CLITransport) List(ctx context.Context, all bool) ([]ContainerInfo, error) {
+	_, span := tracing.StartSpan(ctx, "container.list")
+	span.SetAttribute("all", fmt.Sprintf("%t", all))
+	defer span.End()
+
+	args := []string{"list", "--format", "json"}
+	if all {
+		args = append(args, "-a")
+	}
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			err = classifyCLIError(string(exitErr.Stderr), "container list failed")
+		} else {
+			err = fmt.Errorf("container list failed: %w", err)
+		}
+		span.SetError(err)
+		return nil, err
+	}
+
+	var containers []ContainerInfo
+	if err := json.Unmarshal(output, &containers); err != nil {
+		err = fmt.Errorf("failed to parse list output: %w", err)
+		span.SetError(err)
+		return nil, err
+	}
+
+	return containers, nil
+}
+
+// Logs returns the logs of a container, built from opts the same way the
+// CLI's own flags work: --follow to keep streaming, --since/--tail/
+// --timestamps to narrow what's replayed first.
+func (c *CLITransport) Logs(ctx context.Context, id string, opts LogsOptions) (io.ReadCloser, error) {
+	args := []string{"logs"}
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if !opts.Since.IsZero() {
+		args = append(args, "--since", opts.Since.Format(time.RFC3339))
+	}
+	if opts.Tail > 0 {
+		args = append(args, "--tail", strconv.Itoa(opts.Tail))
+	}
+	if opts.Timestamps {
+		args = append(args, "--timestamps")
+	}
+	args = append(args, id)
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start logs command: %w", err)
+	}
+
+	return &logReader{cmd: cmd, reader: stdout}, nil
+}
+
+// Exec runs a command in a running container
+func (c *CLITransport) Exec(ctx context.Context, id string, command []string) ([]byte, error) {
+	args := []string{"exec", id}
+	args = append(args, command...)
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+	return cmd.CombinedOutput()
+}
+
+// ExecInteractive runs an interactive command in a container
+func (c *CLITransport) ExecInteractive(ctx context.Context, id string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	args := []string{"exec", "-i", id}
+	args = append(args, command...)
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	return cmd.Run()
+}
+
+// Wait streams container id's lifecycle events, preferring the `container
+// events --filter id=<id>` streaming subcommand (parsed as JSON lines) so
+// an exit is reported as soon as it happens. If that subcommand isn't
+// available on this container CLI version, it falls back to polling
+// Inspect with exponentially-growing backoff (see pollExit). The returned
+// channel receives exactly one "exit" ContainerEvent and is then closed;
+// it's closed with nothing sent if ctx is cancelled first.
+func (c *CLITransport) Wait(ctx context.Context, id string) (<-chan ContainerEvent, error) {
+	ch := make(chan ContainerEvent, 1)
+
+	cmd := exec.CommandContext(ctx, c.binPath, "events", "--filter", "id="+id)
+	stdout, err := cmd.StdoutPipe()
+	if err == nil && cmd.Start() == nil {
+		go c.streamEvents(ctx, id, cmd, stdout, ch)
+	} else {
+		go c.pollExit(ctx, id, ch)
+	}
+
+	return ch, nil
+}
+
+// streamEvents reads JSON-lines events from an already-started `container
+// events` subcommand until it reports an exit or the stream ends without
+// one - the latter meaning this container CLI doesn't actually support
+// the events subcommand (or it crashed), so it falls back to pollExit.
+func (c *CLITransport) streamEvents(ctx context.Context, id string, cmd *exec.Cmd, stdout io.ReadCloser, ch chan<- ContainerEvent) {
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		var ev ContainerEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		if ev.Status == "exit" {
+			ch <- ev
+			close(ch)
+			cmd.Process.Kill()
+			cmd.Wait()
+			return
+		}
+	}
+	cmd.Wait()
+
+	if ctx.Err() != nil {
+		close(ch)
+		return
+	}
+	c.pollExit(ctx, id, ch)
+}
+
+// pollExit is Wait's fallback for container CLIs without a streaming events
+// subcommand: it polls Inspect with exponential backoff until the container
+// is no longer running, then reports a synthetic exit event. Apple
+// container's inspect output carries no exit code or OOM flag, so both are
+// left at their zero values here - only the real `container events` path
+// reports them accurately.
+func (c *CLITransport) pollExit(ctx context.Context, id string, ch chan<- ContainerEvent) {
+	defer close(ch)
+
+	backoff := pollBackoffMin
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		info, err := c.Inspect(ctx, id)
+		if err != nil || info.Status != "running" {
+			ch <- ContainerEvent{ID: id, Status: "exit", Timestamp: time.Now()}
+			return
+		}
+
+		if backoff < pollBackoffMax {
+			backoff *= 2
+			if backoff > pollBackoffMax {
+				backoff = pollBackoffMax
+			}
+		}
+	}
+}
+
+// Events streams every lifecycle event the CLI reports (`container
+// events`, optionally scoped with --filter), not just a single
+// container's exit like Wait - callers close ctx to stop the stream, at
+// which point the returned channel is closed too.
+func (c *CLITransport) Events(ctx context.Context, filters EventFilters) (<-chan Event, error) {
+	args := []string{"events"}
+	if filters.ID != "" {
+		args = append(args, "--filter", "id="+filters.ID)
+	}
+	if filters.Type != "" {
+		args = append(args, "--filter", "type="+filters.Type)
+	}
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start events command: %w", err)
+	}
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		defer cmd.Wait()
+		defer cmd.Process.Kill()
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			var ev Event
+			if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+				continue
+			}
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// logReader wraps a command and its stdout for log streaming
+type logReader struct {
+	cmd    *exec.Cmd
+	reader io.Reader
+}
+
+func (r *logReader) Read(p []byte) (n int, err error) {
+	return r.reader.Read(p)
+}
+
+// Close stops a --follow logs subprocess before reaping it. A plain
+// cmd.Wait() would block until the container itself exits, since
+// --follow keeps the subprocess alive indefinitely - killing it first is
+// what actually lets a caller stop tailing logs on demand.
+func (r *logReader) Close() error {
+	if r.cmd.Process != nil {
+		r.cmd.Process.Kill()
+	}
+	return r.cmd.Wait()
+}
+
+// Version returns the container CLI version
+func (c *CLITransport) Version(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, c.binPath, "--version")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to get version: %s", stderr.String())
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// VolumeExists checks if a named volume exists by parsing `volume ls`'s
+// JSON and matching names exactly, rather than substring-matching the
+// raw JSON blob (which would false-positive on a volume whose name is a
+// substring of another field, e.g. a driver name).
+func (c *CLITransport) VolumeExists(ctx context.Context, name string) (bool, error) {
+	volumes, err := c.VolumeList(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range volumes {
+		if v.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// VolumeCreate creates a named volume
+func (c *CLITransport) VolumeCreate(ctx context.Context, name string) error {
+	cmd := exec.CommandContext(ctx, c.binPath, "volume", "create", name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return classifyCLIError(string(output), "volume create failed")
+	}
+	return nil
+}
+
+// VolumeList returns every named volume the container CLI manages.
+func (c *CLITransport) VolumeList(ctx context.Context) ([]VolumeInfo, error) {
+	cmd := exec.CommandContext(ctx, c.binPath, "volume", "ls", "--format", "json")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, classifyCLIError(string(exitErr.Stderr), "volume list failed")
+		}
+		return nil, fmt.Errorf("volume list failed: %w", err)
+	}
+
+	var volumes []VolumeInfo
+	if err := json.Unmarshal(output, &volumes); err != nil {
+		return nil, fmt.Errorf("failed to parse volume list output: %w", err)
+	}
+	return volumes, nil
+}
+
+// VolumeRemove deletes a named volume.
+func (c *CLITransport) VolumeRemove(ctx context.Context, name string) error {
+	cmd := exec.CommandContext(ctx, c.binPath, "volume", "rm", name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return classifyCLIError(string(output), "volume remove failed")
+	}
+	return nil
+}
+
+// volumeExportImage is the throwaway image VolumeExport mounts a volume
+// into - any image with tar and zstd on its PATH would do, since the
+// container itself never runs anything beyond that one pipeline.
+const volumeExportImage = "alpine:latest"
+
+// VolumeExport tars the contents of the named volume into
+// destDir/<name>.tar.zst and returns that path. Volumes aren't reachable
+// from the host directly, so this mounts the volume read-only and destDir
+// into a throwaway container and runs tar+zstd there, the same indirection
+// Run/Exec already use for everything else this CLI wraps.
+func (c *CLITransport) VolumeExport(ctx context.Context, name, destDir string) (string, error) {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	archive := name + ".tar.zst"
+	args := []string{
+		"run", "--rm",
+		"-v", name + ":/volume:ro",
+		"-v", destDir + ":/export",
+		volumeExportImage,
+		"sh", "-c", fmt.Sprintf("tar -C /volume -cf - . | zstd -q -o /export/%s", archive),
+	}
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("volume export failed for %s: %s", name, string(output))
+	}
+
+	return filepath.Join(destDir, archive), nil
+}
+
+// Stats returns a single resource usage snapshot for a container
+func (c *CLITransport) Stats(ctx context.Context, id string) (*ContainerStats, error) {
+	cmd := exec.CommandContext(ctx, c.binPath, "stats", id, "--format", "json", "--no-stream")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, classifyCLIError(string(exitErr.Stderr), "container stats failed")
+		}
+		return nil, fmt.Errorf("container stats failed: %w", err)
+	}
+
+	var stats []ContainerStats
+	if err := json.Unmarshal(output, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse stats output: %w", err)
+	}
+
+	if len(stats) == 0 {
+		return nil, fmt.Errorf("no stats available for container: %s: %w", id, ErrNotFound)
+	}
+
+	return &stats[0], nil
+}
+
+// StatsStream streams resource usage snapshots for a container until ctx
+// is cancelled, dropping --no-stream so the CLI keeps emitting a fresh
+// JSON line each interval instead of Stats' single poll. The returned
+// channel is closed once the subprocess exits or ctx is done.
+func (c *CLITransport) StatsStream(ctx context.Context, id string) (<-chan ContainerStats, error) {
+	cmd := exec.CommandContext(ctx, c.binPath, "stats", id, "--format", "json")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start stats command: %w", err)
+	}
+
+	ch := make(chan ContainerStats)
+	go func() {
+		defer close(ch)
+		defer cmd.Wait()
+		defer cmd.Process.Kill()
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			var stats []ContainerStats
+			line := scanner.Bytes()
+			if err := json.Unmarshal(line, &stats); err != nil || len(stats) == 0 {
+				continue
+			}
+			select {
+			case ch <- stats[0]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// Pull downloads an image from the registry
+func (c *CLITransport) Pull(ctx context.Context, image string) error {
+	return c.PullWithAuth(ctx, image, RegistryAuth{})
+}
+
+// PullWithAuth is Pull with optional registry credentials, logging in with
+// `container registry login`/`logout` around the pull the same way Push
+// does - the CLI has no `image pull --username/--password` flag.
+func (c *CLITransport) PullWithAuth(ctx context.Context, image string, auth RegistryAuth) error {
+	if auth.Username != "" {
+		registry := registryFor(image)
+		loginCmd := exec.CommandContext(ctx, c.binPath, "registry", "login", registry, "--username", auth.Username, "--password-stdin")
+		loginCmd.Stdin = strings.NewReader(auth.Password)
+		if output, err := loginCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("registry login failed: %s", string(output))
+		}
+		defer exec.Command(c.binPath, "registry", "logout", registry).Run()
+	}
+
+	cmd := exec.CommandContext(ctx, c.binPath, "image", "pull", image)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("image pull failed: %s", string(output))
+	}
+	return nil
+}
+
+// ImageExists reports whether image is already present locally, so
+// driver/coordinator can honor pull_policy = "missing"/"never" without an
+// unconditional pull.
+func (c *CLITransport) ImageExists(ctx context.Context, image string) (bool, error) {
+	cmd := exec.CommandContext(ctx, c.binPath, "image", "inspect", image)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		classified := classifyCLIError(string(output), "image inspect failed")
+		if errors.Is(classified, ErrNotFound) {
+			return false, nil
+		}
+		return false, classified
+	}
+	return true, nil
+}
+
+// DiskUsage returns disk usage statistics for images, containers, and volumes
+func (c *CLITransport) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	_, span := tracing.StartSpan(ctx, "container.disk_usage")
+	defer span.End()
+
+	cmd := exec.CommandContext(ctx, c.binPath, "system", "df", "--format", "json")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			err = fmt.Errorf("system df failed: %s", string(exitErr.Stderr))
+		} else {
+			err = fmt.Errorf("system df failed: %w", err)
+		}
+		span.SetError(err)
+		return nil, err
+	}
+
+	var usage DiskUsage
+	if err := json.Unmarshal(output, &usage); err != nil {
+		err = fmt.Errorf("failed to parse disk usage: %w", err)
+		span.SetError(err)
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+// Prune removes unused images to free disk space
+func (c *CLITransport) Prune(ctx context.Context) error {
+	_, span := tracing.StartSpan(ctx, "container.prune")
+	defer span.End()
+
+	cmd := exec.CommandContext(ctx, c.binPath, "image", "prune", "--all")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		err = fmt.Errorf("image prune failed: %s", string(output))
+		span.SetError(err)
+		return err
+	}
+	return nil
+}
+
+// Build runs `container build`, streaming opts.ContextDir to the builder as
+// a tar over stdin (the same convention BuildKit's `docker build -`
+// accepts) and writing each progress line's Stream text to opts.Output as
+// it arrives. It returns the built image's ID, parsed from the final aux
+// event the same way `docker build`'s JSON output reports one, falling
+// back to opts.Tags[0] if no aux event carried one.
+func (c *CLITransport) Build(ctx context.Context, opts BuildOptions) (string, error) {
+	events, err := c.BuildStream(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var imageID string
+	for ev := range events {
+		if ev.ErrorDetail != nil {
+			return "", fmt.Errorf("build failed: %s", ev.ErrorDetail.Message)
+		}
+		if opts.Output != nil && ev.Stream != "" {
+			io.WriteString(opts.Output, ev.Stream)
+		}
+		if ev.Aux != nil {
+			var aux struct {
+				ID string `json:"ID"`
+			}
+			if json.Unmarshal(ev.Aux, &aux) == nil && aux.ID != "" {
+				imageID = aux.ID
+			}
+		}
+	}
+
+	if imageID == "" && len(opts.Tags) > 0 {
+		imageID = opts.Tags[0]
+	}
+	return imageID, nil
+}
+
+// BuildStream is Build's streaming form, delivering each parsed BuildEvent
+// on a channel instead of collecting them - for a caller (e.g. a live
+// `styx image build` progress UI) that wants to render progress as it
+// happens instead of waiting for the whole build to finish.
+func (c *CLITransport) BuildStream(ctx context.Context, opts BuildOptions) (<-chan BuildEvent, error) {
+	dockerfile := opts.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+
+	args := []string{"build", "-f", dockerfile}
+	for _, tag := range opts.Tags {
+		args = append(args, "-t", tag)
+	}
+	for k, v := range opts.BuildArgs {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, v))
+	}
+	for k, v := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, v))
+	}
+	if opts.Target != "" {
+		args = append(args, "--target", opts.Target)
+	}
+	if opts.Platform != "" {
+		args = append(args, "--platform", opts.Platform)
+	}
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+	if opts.Pull {
+		args = append(args, "--pull")
+	}
+	args = append(args, "-")
+
+	stdin, err := tarContext(opts.ContextDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to tar build context: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, c.binPath, args...)
+	cmd.Stdin = stdin
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get build stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start build: %w", err)
+	}
+
+	ch := make(chan BuildEvent)
+	go func() {
+		defer close(ch)
+		defer cmd.Wait()
+
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var ev BuildEvent
+			if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+				// Not every build the CLI drives necessarily emits
+				// JSON-lines progress; a plain text line is still worth
+				// surfacing as Stream rather than dropping it.
+				ev = BuildEvent{Stream: scanner.Text() + "\n"}
+			}
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// tarContext streams dir into a tar archive on a pipe, so Build can start
+// writing it to the builder's stdin without first buffering the whole
+// context in memory.
+func tarContext(dir string) (io.Reader, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("build context directory is required")
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				return nil
+			}
+
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(rel)
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+// Save exports image as an OCI tarball to w, the way `container image
+// save` (mirroring `docker save`) does.
+func (c *CLITransport) Save(ctx context.Context, image string, w io.Writer) error {
+	cmd := exec.CommandContext(ctx, c.binPath, "image", "save", image, "--output", "-")
+	cmd.Stdout = w
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("image save failed: %s", stderr.String())
+	}
+	return nil
+}
+
+// Load imports an OCI tarball from r, the way `container image load` does,
+// and returns the loaded image's reference.
+func (c *CLITransport) Load(ctx context.Context, r io.Reader) (string, error) {
+	cmd := exec.CommandContext(ctx, c.binPath, "image", "load", "--input", "-")
+	cmd.Stdin = r
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("image load failed: %s", string(output))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Tag creates a new tag dst for the already-present image src.
+func (c *CLITransport) Tag(ctx context.Context, src, dst string) error {
+	cmd := exec.CommandContext(ctx, c.binPath, "image", "tag", src, dst)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("image tag failed: %s", string(output))
+	}
+	return nil
+}
+
+// Push uploads image to its registry, logging in with auth first if any
+// credentials are set and logging back out afterward. The container CLI
+// has no flag for inline push credentials (unlike some engines' `push
+// --username/--password`), so this drives `container registry login`/
+// `logout` around the push the same way a human operator would from the
+// command line.
+func (c *CLITransport) Push(ctx context.Context, image string, auth RegistryAuth) error {
+	if auth.Username != "" {
+		registry := registryFor(image)
+		loginCmd := exec.CommandContext(ctx, c.binPath, "registry", "login", registry, "--username", auth.Username, "--password-stdin")
+		loginCmd.Stdin = strings.NewReader(auth.Password)
+		if output, err := loginCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("registry login failed: %s", string(output))
+		}
+		defer exec.Command(c.binPath, "registry", "logout", registry).Run()
+	}
+
+	cmd := exec.CommandContext(ctx, c.binPath, "image", "push", image)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("image push failed: %s", string(output))
+	}
+	return nil
+}
+
+// registryFor returns the registry host portion of an image reference -
+// everything before the first slash, if that segment looks like a host
+// (contains a "." or ":") rather than a Docker Hub user/org name - for
+// Push's login step.
+func registryFor(image string) string {
+	if i := strings.Index(image, "/"); i > 0 && strings.ContainsAny(image[:i], ".:") {
+		return image[:i]
+	}
+	return ""
+}
+
+// classifyCLIError wraps the CLI's raw stderr/combined output in
+// ErrNotFound or ErrConflict when it recognizes the message, so callers
+// can errors.Is instead of string-matching output themselves. Anything
+// it doesn't recognize is still surfaced, just without a sentinel to
+// match against.
+func classifyCLIError(output, context string) error {
+	msg := strings.TrimSpace(output)
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "not found"), strings.Contains(lower, "no such"):
+		return fmt.Errorf("%s: %s: %w", context, msg, ErrNotFound)
+	case strings.Contains(lower, "already exists"), strings.Contains(lower, "already running"), strings.Contains(lower, "in use"):
+		return fmt.Errorf("%s: %s: %w", context, msg, ErrConflict)
+	default:
+		return fmt.Errorf("%s: %s", context, msg)
+	}
+}
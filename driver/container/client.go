@@ -1,223 +1,116 @@
 package container
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"io"
-	"os/exec"
-	"strconv"
-	"strings"
+	"os"
+	"path/filepath"
 )
 
-// Client wraps the Apple container CLI
+// Client is the driver's handle to the container runtime. It's a thin
+// wrapper around whichever Transport NewClient picked - callers
+// (driver.Driver, taskHandle) call Client's methods without needing to
+// know whether they end up shelling out to the CLI or talking to the
+// daemon's API server directly.
 type Client struct {
-	binPath string
+	transport Transport
 }
 
-// NewClient creates a new container client
-func NewClient(binPath string) *Client {
-	if binPath == "" {
-		binPath = "/usr/local/bin/container"
+// defaultAPISocketPath is where the container daemon's local API server
+// listens, when it's running one - see APITransport's doc comment for
+// how confident this path is (best-effort; Apple doesn't publish it).
+func defaultAPISocketPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
 	}
-	return &Client{binPath: binPath}
-}
-
-// BinPath returns the path to the container binary
-func (c *Client) BinPath() string {
-	return c.binPath
-}
-
-// IsAvailable checks if the container CLI is available
-func (c *Client) IsAvailable() bool {
-	_, err := exec.LookPath(c.binPath)
-	return err == nil
+	return filepath.Join(home, "Library", "Application Support", "com.apple.container", "apiserver.sock")
 }
 
-// Run starts a new container and returns its ID
-func (c *Client) Run(ctx context.Context, opts RunOptions) (string, error) {
-	args := []string{"run"}
-
-	if opts.Name != "" {
-		args = append(args, "--name", opts.Name)
-	}
-
-	if opts.Detach {
-		args = append(args, "-d")
-	}
-
-	if opts.Remove {
-		args = append(args, "--rm")
-	}
-
-	if opts.Memory != "" {
-		args = append(args, "-m", opts.Memory)
-	}
-
-	if opts.CPUs > 0 {
-		args = append(args, "-c", strconv.Itoa(opts.CPUs))
-	}
-
-	if opts.WorkingDir != "" {
-		args = append(args, "-w", opts.WorkingDir)
-	}
-
-	if opts.Network != "" {
-		args = append(args, "--network", opts.Network)
-	}
-
-	for k, v := range opts.Env {
-		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
-	}
-
-	for _, port := range opts.Ports {
-		args = append(args, "-p", port)
+// NewClient creates a new container client, preferring to talk to the
+// container daemon's API server directly over its unix socket (see
+// APITransport) and falling back to shelling out to binPath (see
+// CLITransport) when that socket isn't present - an older `container`
+// version that doesn't run one, or the daemon simply not started yet.
+func NewClient(binPath string) *Client {
+	if binPath == "" {
+		binPath = "/usr/local/bin/container"
 	}
 
-	for _, vol := range opts.Volumes {
-		args = append(args, "-v", vol)
+	if sock := defaultAPISocketPath(); sock != "" {
+		if info, err := os.Stat(sock); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return &Client{transport: NewAPITransport(binPath, sock)}
+		}
 	}
 
-	args = append(args, opts.Image)
+	return &Client{transport: NewCLITransport(binPath)}
+}
 
-	if opts.Command != "" {
-		args = append(args, opts.Command)
-	}
+// NewClientWithTransport builds a Client around an explicit Transport,
+// bypassing NewClient's socket auto-detection - for tests (see
+// internal/integration/appleshim.go) that need CLITransport pointed at a
+// stand-in binary.
+func NewClientWithTransport(t Transport) *Client {
+	return &Client{transport: t}
+}
 
-	args = append(args, opts.Args...)
+// BinPath returns the path to the container binary
+func (c *Client) BinPath() string { return c.transport.BinPath() }
 
-	cmd := exec.CommandContext(ctx, c.binPath, args...)
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("container run failed: %s", string(exitErr.Stderr))
-		}
-		return "", fmt.Errorf("container run failed: %w", err)
-	}
+// IsAvailable checks if the container runtime is reachable
+func (c *Client) IsAvailable() bool { return c.transport.IsAvailable() }
 
-	return strings.TrimSpace(string(output)), nil
+// Run starts a new container and returns its ID
+func (c *Client) Run(ctx context.Context, opts RunOptions) (string, error) {
+	return c.transport.Run(ctx, opts)
 }
 
 // Stop stops a running container
 func (c *Client) Stop(ctx context.Context, id string) error {
-	cmd := exec.CommandContext(ctx, c.binPath, "stop", id)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("container stop failed: %s", string(output))
-	}
-	return nil
+	return c.transport.Stop(ctx, id)
 }
 
 // Remove removes a container
 func (c *Client) Remove(ctx context.Context, id string) error {
-	cmd := exec.CommandContext(ctx, c.binPath, "rm", id)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("container rm failed: %s", string(output))
-	}
-	return nil
+	return c.transport.Remove(ctx, id)
 }
 
 // Kill sends a signal to a container
 func (c *Client) Kill(ctx context.Context, id string, signal string) error {
-	args := []string{"kill"}
-	if signal != "" {
-		args = append(args, "-s", signal)
-	}
-	args = append(args, id)
-
-	cmd := exec.CommandContext(ctx, c.binPath, args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("container kill failed: %s", string(output))
-	}
-	return nil
+	return c.transport.Kill(ctx, id, signal)
 }
 
 // Inspect returns detailed information about a container
 func (c *Client) Inspect(ctx context.Context, id string) (*ContainerInfo, error) {
-	cmd := exec.CommandContext(ctx, c.binPath, "inspect", id)
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("container inspect failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("container inspect failed: %w", err)
-	}
-
-	var containers []ContainerInfo
-	if err := json.Unmarshal(output, &containers); err != nil {
-		return nil, fmt.Errorf("failed to parse inspect output: %w", err)
-	}
-
-	if len(containers) == 0 {
-		return nil, fmt.Errorf("container not found: %s", id)
-	}
-
-	return &containers[0], nil
+	return c.transport.Inspect(ctx, id)
 }
 
 // List returns all containers
 func (c *Client) List(ctx context.Context, all bool) ([]ContainerInfo, error) {
-	args := []string{"list", "--format", "json"}
-	if all {
-		args = append(args, "-a")
-	}
-
-	cmd := exec.CommandContext(ctx, c.binPath, args...)
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("container list failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("container list failed: %w", err)
-	}
-
-	var containers []ContainerInfo
-	if err := json.Unmarshal(output, &containers); err != nil {
-		return nil, fmt.Errorf("failed to parse list output: %w", err)
-	}
-
-	return containers, nil
+	return c.transport.List(ctx, all)
 }
 
-// Logs returns the logs of a container
+// Logs returns the logs of a container, replaying whatever's currently
+// buffered. Use LogsWithOptions to follow, tail, or time-bound the
+// stream.
 func (c *Client) Logs(ctx context.Context, id string) (io.ReadCloser, error) {
-	cmd := exec.CommandContext(ctx, c.binPath, "logs", id)
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get stdout pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start logs command: %w", err)
-	}
+	return c.transport.Logs(ctx, id, LogsOptions{})
+}
 
-	return &logReader{cmd: cmd, reader: stdout}, nil
+// LogsWithOptions returns the logs of a container, shaped by opts (see
+// LogsOptions) - e.g. Follow to keep streaming new output.
+func (c *Client) LogsWithOptions(ctx context.Context, id string, opts LogsOptions) (io.ReadCloser, error) {
+	return c.transport.Logs(ctx, id, opts)
 }
 
 // Exec runs a command in a running container
 func (c *Client) Exec(ctx context.Context, id string, command []string) ([]byte, error) {
-	args := []string{"exec", id}
-	args = append(args, command...)
-
-	cmd := exec.CommandContext(ctx, c.binPath, args...)
-	return cmd.CombinedOutput()
+	return c.transport.Exec(ctx, id, command)
 }
 
 // ExecInteractive runs an interactive command in a container
 func (c *Client) ExecInteractive(ctx context.Context, id string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
-	args := []string{"exec", "-i", id}
-	args = append(args, command...)
-
-	cmd := exec.CommandContext(ctx, c.binPath, args...)
-	cmd.Stdin = stdin
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	return cmd.Run()
+	return c.transport.ExecInteractive(ctx, id, command, stdin, stdout, stderr)
 }
 
 // Exists checks if a container exists
@@ -235,113 +128,117 @@ func (c *Client) IsRunning(ctx context.Context, id string) bool {
 	return info.Status == "running"
 }
 
-// logReader wraps a command and its stdout for log streaming
-type logReader struct {
-	cmd    *exec.Cmd
-	reader io.Reader
-}
-
-func (r *logReader) Read(p []byte) (n int, err error) {
-	return r.reader.Read(p)
+// Wait streams container id's lifecycle events, resolving to its exit.
+func (c *Client) Wait(ctx context.Context, id string) (<-chan ContainerEvent, error) {
+	return c.transport.Wait(ctx, id)
 }
 
-func (r *logReader) Close() error {
-	return r.cmd.Wait()
+// Events streams runtime activity matching filters - containers starting
+// and stopping, images being pulled, volumes created - for as long as ctx
+// stays alive.
+func (c *Client) Events(ctx context.Context, filters EventFilters) (<-chan Event, error) {
+	return c.transport.Events(ctx, filters)
 }
 
-// Version returns the container CLI version
+// Version returns the container runtime's version
 func (c *Client) Version(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, c.binPath, "--version")
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get version: %s", stderr.String())
-	}
-
-	return strings.TrimSpace(stdout.String()), nil
+	return c.transport.Version(ctx)
 }
 
 // VolumeExists checks if a named volume exists
 func (c *Client) VolumeExists(ctx context.Context, name string) (bool, error) {
-	cmd := exec.CommandContext(ctx, c.binPath, "volume", "ls", "--format", "json")
-	output, err := cmd.Output()
-	if err != nil {
-		return false, fmt.Errorf("volume list failed: %w", err)
-	}
-	// Check if name is in output
-	return strings.Contains(string(output), name), nil
+	return c.transport.VolumeExists(ctx, name)
 }
 
 // VolumeCreate creates a named volume
 func (c *Client) VolumeCreate(ctx context.Context, name string) error {
-	cmd := exec.CommandContext(ctx, c.binPath, "volume", "create", name)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("volume create failed: %s", string(output))
-	}
-	return nil
+	return c.transport.VolumeCreate(ctx, name)
 }
 
-// Stats returns resource usage statistics for a container
-func (c *Client) Stats(ctx context.Context, id string) (*ContainerStats, error) {
-	cmd := exec.CommandContext(ctx, c.binPath, "stats", id, "--format", "json", "--no-stream")
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("container stats failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("container stats failed: %w", err)
-	}
+// VolumeList returns every named volume the runtime manages.
+func (c *Client) VolumeList(ctx context.Context) ([]VolumeInfo, error) {
+	return c.transport.VolumeList(ctx)
+}
 
-	var stats []ContainerStats
-	if err := json.Unmarshal(output, &stats); err != nil {
-		return nil, fmt.Errorf("failed to parse stats output: %w", err)
-	}
+// VolumeRemove deletes a named volume.
+func (c *Client) VolumeRemove(ctx context.Context, name string) error {
+	return c.transport.VolumeRemove(ctx, name)
+}
 
-	if len(stats) == 0 {
-		return nil, fmt.Errorf("no stats available for container: %s", id)
-	}
+// VolumeExport tars the contents of the named volume into
+// destDir/<name>.tar.zst and returns that path.
+func (c *Client) VolumeExport(ctx context.Context, name, destDir string) (string, error) {
+	return c.transport.VolumeExport(ctx, name, destDir)
+}
+
+// Stats returns a single resource usage snapshot for a container
+func (c *Client) Stats(ctx context.Context, id string) (*ContainerStats, error) {
+	return c.transport.Stats(ctx, id)
+}
 
-	return &stats[0], nil
+// StatsStream streams resource usage snapshots for a container until ctx
+// is cancelled, for callers (e.g. a live `styx top`) that want updates
+// without re-polling Stats on a timer.
+func (c *Client) StatsStream(ctx context.Context, id string) (<-chan ContainerStats, error) {
+	return c.transport.StatsStream(ctx, id)
 }
 
 // Pull downloads an image from the registry
 func (c *Client) Pull(ctx context.Context, image string) error {
-	cmd := exec.CommandContext(ctx, c.binPath, "image", "pull", image)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("image pull failed: %s", string(output))
-	}
-	return nil
+	return c.transport.Pull(ctx, image)
 }
 
-// DiskUsage returns disk usage statistics for images, containers, and volumes
-func (c *Client) DiskUsage(ctx context.Context) (*DiskUsage, error) {
-	cmd := exec.CommandContext(ctx, c.binPath, "system", "df", "--format", "json")
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("system df failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("system df failed: %w", err)
-	}
+// PullWithAuth is Pull with optional registry credentials.
+func (c *Client) PullWithAuth(ctx context.Context, image string, auth RegistryAuth) error {
+	return c.transport.PullWithAuth(ctx, image, auth)
+}
 
-	var usage DiskUsage
-	if err := json.Unmarshal(output, &usage); err != nil {
-		return nil, fmt.Errorf("failed to parse disk usage: %w", err)
-	}
+// ImageExists reports whether image is already present locally.
+func (c *Client) ImageExists(ctx context.Context, image string) (bool, error) {
+	return c.transport.ImageExists(ctx, image)
+}
 
-	return &usage, nil
+// DiskUsage returns disk usage statistics for images, containers, and volumes
+func (c *Client) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	return c.transport.DiskUsage(ctx)
 }
 
 // Prune removes unused images to free disk space
 func (c *Client) Prune(ctx context.Context) error {
-	cmd := exec.CommandContext(ctx, c.binPath, "image", "prune", "--all")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("image prune failed: %s", string(output))
-	}
-	return nil
+	return c.transport.Prune(ctx)
+}
+
+// Build builds an image from opts.ContextDir and returns the resulting
+// image ID.
+func (c *Client) Build(ctx context.Context, opts BuildOptions) (string, error) {
+	return c.transport.Build(ctx, opts)
+}
+
+// BuildStream is Build's streaming form, delivering each parsed BuildEvent
+// on a channel as the build progresses - for a live progress UI that
+// can't wait for Build to return.
+func (c *Client) BuildStream(ctx context.Context, opts BuildOptions) (<-chan BuildEvent, error) {
+	return c.transport.BuildStream(ctx, opts)
+}
+
+// Save exports image as an OCI tarball to w.
+func (c *Client) Save(ctx context.Context, image string, w io.Writer) error {
+	return c.transport.Save(ctx, image, w)
+}
+
+// Load imports an OCI tarball from r and returns the loaded image's
+// reference.
+func (c *Client) Load(ctx context.Context, r io.Reader) (string, error) {
+	return c.transport.Load(ctx, r)
+}
+
+// Tag creates a new tag dst for the already-present image src.
+func (c *Client) Tag(ctx context.Context, src, dst string) error {
+	return c.transport.Tag(ctx, src, dst)
+}
+
+// Push uploads image to its registry, authenticating with auth first if
+// it's non-zero.
+func (c *Client) Push(ctx context.Context, image string, auth RegistryAuth) error {
+	return c.transport.Push(ctx, image, auth)
 }
@@ -0,0 +1,320 @@
+//go:build integration
+
+// Package containertest provides a testcontainers-style harness for
+// standing up a Consul cluster on top of driver/container.Client, for
+// integration tests that exercise upgrade, peering, and ACL flows without
+// a full `styx init`. It's modeled on Consul's own refactored
+// testcontainers suite, and is container.Client's counterpart to
+// internal/testcluster: testcluster drives Docker via testcontainers-go to
+// cover Nomad+Consul+Vault together on CI machines that don't have the
+// `container` CLI, while containertest drives the exact runtime (and
+// Client) Styx itself uses in production, at the cost of only running on
+// a Mac with the `container` CLI/daemon installed.
+package containertest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kessler-frost/styx/driver/container"
+	"github.com/kessler-frost/styx/internal/config"
+	styxtls "github.com/kessler-frost/styx/internal/tls"
+)
+
+// DefaultConsulVersion is the Consul image tag NewCluster boots unless
+// Opts.ConsulVersion overrides it.
+const DefaultConsulVersion = "1.17"
+
+// Opts configures a Cluster before NewCluster brings it up.
+type Opts struct {
+	Servers       int    // Consul server nodes to start; defaults to 1
+	Clients       int    // Consul client nodes to start and join to the servers
+	ConsulVersion string // image tag; defaults to DefaultConsulVersion
+	TLS           bool   // issue a CA and agent certs and mount them into every node
+	BinPath       string // path to the container binary; passed to container.NewClient
+}
+
+func (o Opts) withDefaults() Opts {
+	if o.Servers == 0 {
+		o.Servers = 1
+	}
+	if o.ConsulVersion == "" {
+		o.ConsulVersion = DefaultConsulVersion
+	}
+	return o
+}
+
+// Cluster is a running Consul cluster made of containers started through
+// container.Client, sharing one network so nodes can reach each other the
+// way co-located services do on a single Mac.
+type Cluster struct {
+	t       *testing.T
+	opts    Opts
+	client  *container.Client
+	network string
+	gossip  string
+
+	certsDir    string
+	serverCerts *styxtls.CertPaths
+	clientCerts *styxtls.CertPaths
+
+	Nodes []*Node
+}
+
+// NewCluster brings up opts.Servers Consul servers followed by
+// opts.Clients Consul clients, all joined into one cluster, using the
+// exact HCL GenerateConsulServerConfig/GenerateConsulClientConfig would
+// write to disk on a real Mac. It registers cleanup (log capture on
+// failure, then container/network teardown) via t.Cleanup, the same
+// lifecycle httptest.NewServer gives its callers, and fails the test
+// immediately rather than returning an error - there's nothing a caller
+// could usefully do with a half-started cluster.
+func NewCluster(t *testing.T, opts Opts) *Cluster {
+	t.Helper()
+	opts = opts.withDefaults()
+
+	ctx := context.Background()
+	netName := fmt.Sprintf("containertest-%d", time.Now().UnixNano())
+	if err := createNetwork(netName); err != nil {
+		t.Fatalf("failed to create cluster network: %v", err)
+	}
+
+	c := &Cluster{
+		t:       t,
+		opts:    opts,
+		client:  container.NewClient(opts.BinPath),
+		network: netName,
+	}
+	t.Cleanup(func() { c.cleanup(ctx) })
+
+	gossip, err := styxtls.GenerateGossipKey()
+	if err != nil {
+		t.Fatalf("failed to generate gossip key: %v", err)
+	}
+	c.gossip = gossip
+
+	if opts.TLS {
+		c.certsDir, err = os.MkdirTemp("", "containertest-certs-")
+		if err != nil {
+			t.Fatalf("failed to create certs dir: %v", err)
+		}
+		if err := styxtls.GenerateCA(c.certsDir); err != nil {
+			t.Fatalf("failed to generate CA: %v", err)
+		}
+		if c.serverCerts, err = styxtls.GenerateServerCert(c.certsDir, "dc1"); err != nil {
+			t.Fatalf("failed to generate server cert: %v", err)
+		}
+		if opts.Clients > 0 {
+			if c.clientCerts, err = styxtls.GenerateClientCert(c.certsDir, "dc1"); err != nil {
+				t.Fatalf("failed to generate client cert: %v", err)
+			}
+		}
+	}
+
+	for i := 0; i < opts.Servers; i++ {
+		n, err := c.startServer(ctx, i)
+		if err != nil {
+			t.Fatalf("failed to start consul server %d: %v", i, err)
+		}
+		c.Nodes = append(c.Nodes, n)
+	}
+
+	for i := 0; i < opts.Clients; i++ {
+		n, err := c.startClient(ctx, i)
+		if err != nil {
+			t.Fatalf("failed to start consul client %d: %v", i, err)
+		}
+		c.Nodes = append(c.Nodes, n)
+	}
+
+	if err := c.WaitForLeader(); err != nil {
+		t.Fatalf("cluster did not elect a leader: %v", err)
+	}
+
+	return c
+}
+
+// WaitForLeader polls every server node's /v1/status/leader until one
+// reports a leader or 60 seconds pass, the same way
+// internal/testcluster.Cluster.Leader polls Nomad's equivalent endpoint.
+func (c *Cluster) WaitForLeader() error {
+	deadline := time.Now().Add(60 * time.Second)
+	for {
+		for _, n := range c.Nodes {
+			if n.role != roleServer {
+				continue
+			}
+			if hasLeader(n.endpoint()) {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("no consul server elected a leader within 60s")
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func hasLeader(endpoint string) bool {
+	resp, err := http.Get(endpoint + "/v1/status/leader")
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	// An empty leader is reported as the literal two-byte string `""`.
+	return err == nil && len(body) > 2
+}
+
+// Upgrade performs a rolling upgrade of every Consul server node to
+// version, replacing one container at a time and waiting for the cluster
+// to re-elect a leader before moving to the next - the same quorum-safe
+// pattern internal/testcluster.Cluster.UpgradeTo uses for a Nomad upgrade.
+func (c *Cluster) Upgrade(version string) error {
+	ctx := context.Background()
+	for i, n := range c.Nodes {
+		if n.role != roleServer {
+			continue
+		}
+		replacement, err := c.replaceNode(ctx, n, "hashicorp/consul:"+version)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade node %d: %w", i, err)
+		}
+		c.Nodes[i] = replacement
+		if err := c.WaitForLeader(); err != nil {
+			return fmt.Errorf("no leader after upgrading node %d to %s: %w", i, version, err)
+		}
+	}
+	c.opts.ConsulVersion = version
+	return nil
+}
+
+// cleanup captures logs (if the test failed), then stops and removes every
+// node and the cluster network. Registered against t via t.Cleanup by
+// NewCluster.
+func (c *Cluster) cleanup(ctx context.Context) {
+	if c.t.Failed() {
+		c.captureLogs(ctx)
+	}
+
+	for _, n := range c.Nodes {
+		if err := c.client.Stop(ctx, n.id); err != nil {
+			c.t.Logf("failed to stop %s: %v", n.alias, err)
+		}
+		if err := c.client.Remove(ctx, n.id); err != nil {
+			c.t.Logf("failed to remove %s: %v", n.alias, err)
+		}
+	}
+
+	if err := removeNetwork(c.network); err != nil {
+		c.t.Logf("failed to remove cluster network %s: %v", c.network, err)
+	}
+
+	if c.certsDir != "" {
+		os.RemoveAll(c.certsDir)
+	}
+}
+
+// captureLogs writes every node's current log buffer to
+// t.TempDir()/logs/<node>.log, so a failing test leaves behind exactly
+// what each Consul agent printed.
+func (c *Cluster) captureLogs(ctx context.Context) {
+	dir := filepath.Join(c.t.TempDir(), "logs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		c.t.Logf("failed to create log capture directory: %v", err)
+		return
+	}
+
+	for _, n := range c.Nodes {
+		rc, err := c.client.Logs(ctx, n.id)
+		if err != nil {
+			c.t.Logf("failed to fetch logs for %s: %v", n.alias, err)
+			continue
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			c.t.Logf("failed to read logs for %s: %v", n.alias, err)
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, n.alias+".log"), data, 0644); err != nil {
+			c.t.Logf("failed to write log capture for %s: %v", n.alias, err)
+		}
+	}
+}
+
+func (c *Cluster) startServer(ctx context.Context, i int) (*Node, error) {
+	alias := fmt.Sprintf("consul-server-%d", i)
+
+	cfg := config.ConsulServerConfig{
+		DataDir:         "/consul/data",
+		AdvertiseIP:     "0.0.0.0",
+		BootstrapExpect: c.opts.Servers,
+		GossipKey:       c.gossip,
+	}
+	if i > 0 {
+		cfg.Servers = []string{c.Nodes[0].alias}
+	}
+	if c.serverCerts != nil {
+		cfg.CAFile = containerTLSPath(c.serverCerts.CAFile)
+		cfg.CertFile = containerTLSPath(c.serverCerts.CertFile)
+		cfg.KeyFile = containerTLSPath(c.serverCerts.KeyFile)
+	}
+
+	hcl, err := config.GenerateConsulServerConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startNode(ctx, nodeSpec{
+		role:     roleServer,
+		alias:    alias,
+		image:    "hashicorp/consul:" + c.opts.ConsulVersion,
+		hcl:      hcl,
+		httpPort: basePort + len(c.Nodes),
+	})
+}
+
+func (c *Cluster) startClient(ctx context.Context, i int) (*Node, error) {
+	alias := fmt.Sprintf("consul-client-%d", i)
+
+	cfg := config.ConsulClientConfig{
+		DataDir:     "/consul/data",
+		AdvertiseIP: "0.0.0.0",
+		Servers:     []string{c.Nodes[0].alias},
+		GossipKey:   c.gossip,
+	}
+	if c.clientCerts != nil {
+		cfg.CAFile = containerTLSPath(c.clientCerts.CAFile)
+		cfg.CertFile = containerTLSPath(c.clientCerts.CertFile)
+		cfg.KeyFile = containerTLSPath(c.clientCerts.KeyFile)
+	}
+
+	hcl, err := config.GenerateConsulClientConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startNode(ctx, nodeSpec{
+		role:     roleClient,
+		alias:    alias,
+		image:    "hashicorp/consul:" + c.opts.ConsulVersion,
+		hcl:      hcl,
+		httpPort: basePort + len(c.Nodes),
+	})
+}
+
+// containerTLSPath maps a cert file's host path (under Cluster.certsDir)
+// to where startNode mounts that same directory inside the container.
+func containerTLSPath(hostPath string) string {
+	return "/consul/tls/" + filepath.Base(hostPath)
+}
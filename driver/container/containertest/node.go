@@ -0,0 +1,210 @@
+//go:build integration
+
+package containertest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/kessler-frost/styx/driver/container"
+)
+
+// role identifies what a Node runs, so Cluster methods like Upgrade and
+// WaitForLeader can pick out the nodes they care about.
+type role string
+
+const (
+	roleServer role = "server"
+	roleClient role = "client"
+)
+
+// basePort is the first host port startNode publishes a node's Consul
+// HTTP API on; each subsequent node gets the next one up, so host-side
+// calls like WaitForLeader's never collide with each other or with a real
+// Consul agent running on the Mac itself.
+const basePort = 18500
+
+// Node is a single Consul agent container in a Cluster.
+type Node struct {
+	role  role
+	id    string // container ID
+	alias string // hostname other nodes retry_join by
+	spec  nodeSpec
+}
+
+// nodeSpec is the shared shape every startNode call fills in; it exists so
+// Cluster's startServer/startClient stay a readable list of config values
+// instead of each repeating the container wiring boilerplate - the same
+// role nodeSpec plays in internal/testcluster.
+type nodeSpec struct {
+	role     role
+	alias    string
+	image    string
+	hcl      string
+	httpPort int
+}
+
+func (c *Cluster) startNode(ctx context.Context, spec nodeSpec) (*Node, error) {
+	dataDir, err := os.MkdirTemp("", "containertest-"+string(spec.role)+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data dir for %s: %w", spec.alias, err)
+	}
+	configDir := filepath.Join(dataDir, "config")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config dir for %s: %w", spec.alias, err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "consul.hcl"), []byte(spec.hcl), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write consul.hcl for %s: %w", spec.alias, err)
+	}
+
+	volumes := []string{configDir + ":/consul/config"}
+	if c.certsDir != "" {
+		volumes = append(volumes, c.certsDir+":/consul/tls")
+	}
+
+	id, err := c.client.Run(ctx, container.RunOptions{
+		Name:    spec.alias,
+		Image:   spec.image,
+		Args:    []string{"agent", "-config-dir=/consul/config"},
+		Network: c.network,
+		Detach:  true,
+		Volumes: volumes,
+		Ports:   []string{fmt.Sprintf("%d:8500", spec.httpPort)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", spec.alias, err)
+	}
+
+	node := &Node{role: spec.role, id: id, alias: spec.alias, spec: spec}
+	if err := node.waitHealthy(ctx, c.client); err != nil {
+		return nil, fmt.Errorf("%s did not become healthy: %w", spec.alias, err)
+	}
+	return node, nil
+}
+
+// waitHealthy polls `consul info` inside the container until it succeeds
+// or 30 seconds pass - the agent process takes a moment to come up even
+// after the container itself is running.
+func (n *Node) waitHealthy(ctx context.Context, client *container.Client) error {
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		if _, err := client.Exec(ctx, n.id, []string{"consul", "info"}); err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for consul agent to come up")
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// endpoint returns this node's externally-published Consul HTTP base URL.
+func (n *Node) endpoint() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", n.spec.httpPort)
+}
+
+// replaceNode stops and removes n's container and starts a replacement
+// running image, reusing the same alias and HCL - a rolling upgrade in
+// place, mirroring internal/testcluster.Node.replaceImage.
+func (c *Cluster) replaceNode(ctx context.Context, n *Node, image string) (*Node, error) {
+	if err := c.client.Stop(ctx, n.id); err != nil {
+		return nil, fmt.Errorf("failed to stop existing container: %w", err)
+	}
+	if err := c.client.Remove(ctx, n.id); err != nil {
+		return nil, fmt.Errorf("failed to remove existing container: %w", err)
+	}
+
+	spec := n.spec
+	spec.image = image
+	return c.startNode(ctx, spec)
+}
+
+// ExecOnNode runs cmd inside Nodes[i] and returns its combined output, the
+// way `container exec` does - for assertions a test wants to make from
+// inside the agent itself (e.g. `consul members`, `consul acl token list`).
+func (c *Cluster) ExecOnNode(i int, cmd []string) ([]byte, error) {
+	n, err := c.node(i)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Exec(context.Background(), n.id, cmd)
+}
+
+// PartitionNode simulates a network partition by dropping Nodes[i]'s
+// Consul gossip and RPC traffic with iptables rules run inside the
+// container. container.Transport has no network-disconnect primitive
+// (unlike testcontainers-go's Network.Disconnect, which testcluster could
+// use directly), so this is the closest equivalent that works against the
+// real container runtime: the node keeps running, but can no longer reach
+// or be reached by the rest of the cluster.
+func (c *Cluster) PartitionNode(i int) error {
+	n, err := c.node(i)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	rules := [][]string{
+		{"iptables", "-A", "INPUT", "-p", "tcp", "--dport", "8300:8302", "-j", "DROP"},
+		{"iptables", "-A", "INPUT", "-p", "udp", "--dport", "8301:8302", "-j", "DROP"},
+		{"iptables", "-A", "OUTPUT", "-p", "tcp", "--dport", "8300:8302", "-j", "DROP"},
+		{"iptables", "-A", "OUTPUT", "-p", "udp", "--dport", "8301:8302", "-j", "DROP"},
+	}
+	for _, rule := range rules {
+		if _, err := c.client.Exec(ctx, n.id, rule); err != nil {
+			return fmt.Errorf("failed to apply partition rule on node %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (c *Cluster) node(i int) (*Node, error) {
+	if i < 0 || i >= len(c.Nodes) {
+		return nil, fmt.Errorf("node index %d out of range (cluster has %d nodes)", i, len(c.Nodes))
+	}
+	return c.Nodes[i], nil
+}
+
+// testSubnet hands out a unique /24 per Cluster so concurrent clusters (and
+// internal/network's own "styx" network, at 192.168.200.0/24) never
+// collide - there's no shared network-allocator to ask, so this is a
+// simple incrementing counter the same way testNetworkCounter would be if
+// container.Transport grew real network-management methods.
+var testSubnetCounter atomic.Int32
+
+func nextTestSubnet() string {
+	n := testSubnetCounter.Add(1)
+	return fmt.Sprintf("192.168.%d.0/24", 210+n)
+}
+
+// createNetwork and removeNetwork shell out to the container CLI directly,
+// the same way internal/network.EnsureStyxNetwork does - container.Client's
+// Transport has no network-management methods to delegate to.
+func createNetwork(name string) error {
+	bin, err := exec.LookPath("container")
+	if err != nil {
+		return fmt.Errorf("container CLI not found in PATH: %w", err)
+	}
+	cmd := exec.Command(bin, "network", "create", "--subnet", nextTestSubnet(), name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create network %s: %w (%s)", name, err, output)
+	}
+	return nil
+}
+
+func removeNetwork(name string) error {
+	bin, err := exec.LookPath("container")
+	if err != nil {
+		return fmt.Errorf("container CLI not found in PATH: %w", err)
+	}
+	cmd := exec.Command(bin, "network", "rm", name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove network %s: %w (%s)", name, err, output)
+	}
+	return nil
+}
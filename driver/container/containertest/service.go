@@ -0,0 +1,29 @@
+//go:build integration
+
+package containertest
+
+import "fmt"
+
+// Service describes a catalog entry to register against one of a
+// Cluster's agents - something for ACL/intention tests to target besides
+// the agents themselves.
+type Service struct {
+	Name string
+	Port int
+	Tags []string
+}
+
+// Register registers svc against Nodes[i] via `consul services register`,
+// the same local-agent registration path a real Nomad task's service
+// stanza drives.
+func (c *Cluster) Register(i int, svc Service) error {
+	args := []string{"consul", "services", "register", "-name=" + svc.Name, fmt.Sprintf("-port=%d", svc.Port)}
+	for _, tag := range svc.Tags {
+		args = append(args, "-tag="+tag)
+	}
+
+	if _, err := c.ExecOnNode(i, args); err != nil {
+		return fmt.Errorf("failed to register service %s on node %d: %w", svc.Name, i, err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package container
+
+import "errors"
+
+// ErrNotFound is returned when an operation targets a container, image, or
+// volume that doesn't exist, so callers can use errors.Is instead of
+// string-matching CLI stderr or HTTP status codes.
+var ErrNotFound = errors.New("container: not found")
+
+// ErrConflict is returned when an operation can't proceed because of the
+// target's current state - starting a container that's already running,
+// creating a volume that already exists, and the like.
+var ErrConflict = errors.New("container: conflict")
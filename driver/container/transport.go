@@ -0,0 +1,82 @@
+package container
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// LogsOptions controls how Transport.Logs streams a container's output.
+// The zero value replays whatever's currently buffered and returns, the
+// same behavior the old unconditional `container logs <id>` call had.
+type LogsOptions struct {
+	Follow     bool
+	Since      time.Time
+	Tail       int // 0 means all lines
+	Timestamps bool
+}
+
+// EventFilters narrows Transport.Events to a subset of activity. The zero
+// value matches everything.
+type EventFilters struct {
+	ID   string // container/image/volume ID, exact match
+	Type string // "container", "image", or "volume"
+}
+
+// Event is one entry from Transport.Events' stream. It's a superset of
+// ContainerEvent's lifecycle notion - Type distinguishes a container
+// transition from image/volume activity the CLI's own `container events`
+// subcommand also reports.
+type Event struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	ExitCode  int       `json:"exitCode,omitempty"`
+	OOMKilled bool      `json:"oomKilled,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Transport is everything Client needs from whatever's actually talking
+// to the container runtime. CLITransport shells out to the `container`
+// binary and parses its output; APITransport speaks to the container
+// daemon's local API server directly. NewClient picks whichever is
+// available, preferring APITransport.
+type Transport interface {
+	BinPath() string
+	IsAvailable() bool
+
+	Run(ctx context.Context, opts RunOptions) (string, error)
+	Stop(ctx context.Context, id string) error
+	Remove(ctx context.Context, id string) error
+	Kill(ctx context.Context, id string, signal string) error
+	Inspect(ctx context.Context, id string) (*ContainerInfo, error)
+	List(ctx context.Context, all bool) ([]ContainerInfo, error)
+	Logs(ctx context.Context, id string, opts LogsOptions) (io.ReadCloser, error)
+	Exec(ctx context.Context, id string, command []string) ([]byte, error)
+	ExecInteractive(ctx context.Context, id string, command []string, stdin io.Reader, stdout, stderr io.Writer) error
+	Wait(ctx context.Context, id string) (<-chan ContainerEvent, error)
+	Events(ctx context.Context, filters EventFilters) (<-chan Event, error)
+
+	VolumeExists(ctx context.Context, name string) (bool, error)
+	VolumeCreate(ctx context.Context, name string) error
+	VolumeList(ctx context.Context) ([]VolumeInfo, error)
+	VolumeRemove(ctx context.Context, name string) error
+	VolumeExport(ctx context.Context, name, destDir string) (string, error)
+
+	Stats(ctx context.Context, id string) (*ContainerStats, error)
+	StatsStream(ctx context.Context, id string) (<-chan ContainerStats, error)
+
+	Pull(ctx context.Context, image string) error
+	PullWithAuth(ctx context.Context, image string, auth RegistryAuth) error
+	ImageExists(ctx context.Context, image string) (bool, error)
+	Version(ctx context.Context) (string, error)
+	DiskUsage(ctx context.Context) (*DiskUsage, error)
+	Prune(ctx context.Context) error
+
+	Build(ctx context.Context, opts BuildOptions) (string, error)
+	BuildStream(ctx context.Context, opts BuildOptions) (<-chan BuildEvent, error)
+	Save(ctx context.Context, image string, w io.Writer) error
+	Load(ctx context.Context, r io.Reader) (string, error)
+	Tag(ctx context.Context, src, dst string) error
+	Push(ctx context.Context, image string, auth RegistryAuth) error
+}
@@ -1,5 +1,23 @@
 package container
 
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ContainerEvent is one line of `container events` JSON-lines output (or a
+// synthesized equivalent from Client.Wait's Inspect-polling fallback): a
+// container lifecycle transition. Status "exit" is the one Wait's callers
+// actually care about; ExitCode/OOMKilled are only meaningful then.
+type ContainerEvent struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	ExitCode  int       `json:"exitCode"`
+	OOMKilled bool      `json:"oomKilled"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // ContainerInfo represents the JSON output from `container list` and `container inspect`
 type ContainerInfo struct {
 	Status        string        `json:"status"`
@@ -8,21 +26,21 @@ type ContainerInfo struct {
 }
 
 type Configuration struct {
-	ID               string           `json:"id"`
-	Image            ImageInfo        `json:"image"`
-	Resources        Resources        `json:"resources"`
-	Platform         Platform         `json:"platform"`
-	InitProcess      InitProcess      `json:"initProcess"`
-	DNS              DNS              `json:"dns"`
-	RuntimeHandler   string           `json:"runtimeHandler"`
-	SSH              bool             `json:"ssh"`
-	Rosetta          bool             `json:"rosetta"`
-	Virtualization   bool             `json:"virtualization"`
+	ID               string            `json:"id"`
+	Image            ImageInfo         `json:"image"`
+	Resources        Resources         `json:"resources"`
+	Platform         Platform          `json:"platform"`
+	InitProcess      InitProcess       `json:"initProcess"`
+	DNS              DNS               `json:"dns"`
+	RuntimeHandler   string            `json:"runtimeHandler"`
+	SSH              bool              `json:"ssh"`
+	Rosetta          bool              `json:"rosetta"`
+	Virtualization   bool              `json:"virtualization"`
 	Labels           map[string]string `json:"labels"`
-	Mounts           []Mount          `json:"mounts"`
-	PublishedPorts   []PublishedPort  `json:"publishedPorts"`
+	Mounts           []Mount           `json:"mounts"`
+	PublishedPorts   []PublishedPort   `json:"publishedPorts"`
 	PublishedSockets []PublishedSocket `json:"publishedSockets"`
-	Networks         []NetworkConfig  `json:"networks"`
+	Networks         []NetworkConfig   `json:"networks"`
 	Sysctls          map[string]string `json:"sysctls"`
 }
 
@@ -80,7 +98,7 @@ type DNS struct {
 }
 
 type Mount struct {
-	Type        interface{} `json:"type"`        // Can be object like {"virtiofs":{}} or string
+	Type        interface{} `json:"type"` // Can be object like {"virtiofs":{}} or string
 	Source      string      `json:"source"`
 	Destination string      `json:"destination"` // Apple container uses "destination", not "target"
 	Options     []string    `json:"options"`
@@ -110,6 +128,83 @@ type NetworkInfo struct {
 	Hostname string `json:"hostname"`
 }
 
+// VolumeInfo represents one entry from `container volume ls`'s JSON output.
+type VolumeInfo struct {
+	Name   string `json:"name"`
+	Driver string `json:"driver"`
+}
+
+// BuildOptions configures Client.Build/BuildStream.
+type BuildOptions struct {
+	ContextDir string   // directory streamed to the builder as a tar, the way BuildKit's `docker build -` accepts a tar over stdin
+	Dockerfile string   // path within ContextDir; defaults to "Dockerfile"
+	Tags       []string // image references to tag the result with
+	BuildArgs  map[string]string
+	Labels     map[string]string
+	Target     string // build stage to stop at, for multi-stage Dockerfiles
+	Platform   string // e.g. "linux/arm64"
+	NoCache    bool
+	Pull       bool      // always attempt to pull a newer base image
+	Output     io.Writer // receives each BuildEvent's Stream text as the build progresses; nil discards it
+}
+
+// BuildEvent is one line of `container build`'s JSON-lines progress
+// output, the same shape Docker's build API streams: Stream is
+// human-readable progress text, ErrorDetail is set instead of Stream when
+// the build fails, and Aux carries structured step metadata (e.g. the
+// final image ID) that doesn't fit Stream's plain-text shape.
+type BuildEvent struct {
+	Stream      string          `json:"stream,omitempty"`
+	ErrorDetail *BuildError     `json:"errorDetail,omitempty"`
+	Aux         json.RawMessage `json:"aux,omitempty"`
+}
+
+// BuildError is BuildEvent's ErrorDetail payload.
+type BuildError struct {
+	Message string `json:"message"`
+}
+
+// RegistryAuth carries the credentials Push logs in with before pushing,
+// when the target registry requires authentication.
+type RegistryAuth struct {
+	Username string
+	Password string
+}
+
+// ContainerStats is one resource-usage snapshot from `container stats
+// --format json` (APITransport's equivalent: the daemon's
+// /containers/{id}/stats endpoint), the fields Driver.TaskStats maps onto
+// Nomad's drivers.ResourceUsage.
+type ContainerStats struct {
+	ID               string  `json:"id"`
+	CPUPercent       float64 `json:"cpuPercent"`
+	MemoryUsageBytes uint64  `json:"memoryUsageBytes"`
+	MemoryLimitBytes uint64  `json:"memoryLimitBytes"`
+	NetworkRxBytes   uint64  `json:"networkRxBytes"`
+	NetworkTxBytes   uint64  `json:"networkTxBytes"`
+	BlockReadBytes   uint64  `json:"blockReadBytes"`
+	BlockWriteBytes  uint64  `json:"blockWriteBytes"`
+}
+
+// DiskUsageCategory is one DiskUsage section: how much space images,
+// containers, or volumes are using, and how much of that Prune could
+// reclaim.
+type DiskUsageCategory struct {
+	Total       int   `json:"total"`
+	Active      int   `json:"active"`
+	SizeInBytes int64 `json:"sizeInBytes"`
+	Reclaimable int64 `json:"reclaimable"`
+}
+
+// DiskUsage is `container system df --format json`'s output (APITransport's
+// equivalent: the daemon's /system/df endpoint), broken down the same way
+// Docker's system df is - see cmd/styx/system.go.
+type DiskUsage struct {
+	Images     DiskUsageCategory `json:"images"`
+	Containers DiskUsageCategory `json:"containers"`
+	Volumes    DiskUsageCategory `json:"volumes"`
+}
+
 // RunOptions contains options for running a container
 type RunOptions struct {
 	Name       string
@@ -125,4 +220,14 @@ type RunOptions struct {
 	Remove     bool
 	Network    string
 	WorkingDir string
+	Labels     map[string]string
+
+	DNSServers       []string // nameservers written into the mounted /etc/resolv.conf
+	DNSSearchDomains []string // search domains written into the mounted /etc/resolv.conf
+	DNSOptions       []string // options lines written into the mounted /etc/resolv.conf
+	ExtraHosts       []string // "host:ip" entries appended to the mounted /etc/hosts fragment
+
+	CapAdd     []string // Linux capabilities to add, e.g. "NET_ADMIN"
+	CapDrop    []string // Linux capabilities to drop
+	Privileged bool
 }
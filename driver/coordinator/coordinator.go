@@ -0,0 +1,243 @@
+// Package coordinator deduplicates and policy-gates image pulls for the
+// driver, the same role Nomad's own docker driver coordinator plays in
+// front of its client.ImagePull: without it, every task referencing the
+// same image would pull it independently, and StartTask's pull_policy/
+// retry/auth handling would be duplicated at every call site.
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kessler-frost/styx/driver/container"
+)
+
+// Pull policies, mirroring Nomad's docker driver image.pull_policy.
+const (
+	PullAlways  = "always"
+	PullMissing = "missing"
+	PullNever   = "never"
+)
+
+const (
+	retryBaseline = 250 * time.Millisecond
+	retryCap      = 30 * time.Second
+
+	// maxPullAttempts bounds pullWithBackoff independently of ctx, so a
+	// permanently-failing pull (typo'd image, bad credentials with
+	// AuthSoftFail off) can't retry forever at a 30s cadence and hold
+	// startOrJoin's inFlight entry - and every caller waiting on it via
+	// Pull - open until the driver process exits. d.ctx (see driver.go)
+	// is the driver's process-lifetime context, not a per-task deadline,
+	// so it alone can't be relied on to end a doomed retry loop.
+	maxPullAttempts = 5
+)
+
+// ImagePuller is the subset of container.Client the coordinator needs,
+// narrowed so tests can fake it without a real container runtime.
+type ImagePuller interface {
+	ImageExists(ctx context.Context, image string) (bool, error)
+	PullWithAuth(ctx context.Context, image string, auth container.RegistryAuth) error
+}
+
+// Coordinator singleflights concurrent pulls of the same image across
+// tasks, so N allocations referencing one image share a single pull
+// instead of racing N redundant ones.
+type Coordinator struct {
+	client ImagePuller
+	logger hclog.Logger
+
+	mu       sync.Mutex
+	inFlight map[string]*pullCall
+}
+
+// pullCall tracks one in-flight (or just-finished) pull, letting callers
+// that arrive after it started wait on the same result instead of
+// starting their own.
+type pullCall struct {
+	done chan struct{}
+	err  error
+}
+
+// New creates a Coordinator backed by client.
+func New(logger hclog.Logger, client ImagePuller) *Coordinator {
+	return &Coordinator{
+		client:   client,
+		logger:   logger.Named("coordinator"),
+		inFlight: make(map[string]*pullCall),
+	}
+}
+
+// PullOptions controls how Pull resolves a single image reference.
+type PullOptions struct {
+	// Policy is one of PullAlways, PullMissing (the default if empty), or
+	// PullNever.
+	Policy string
+
+	// Auth carries optional registry credentials, forwarded to
+	// PullWithAuth unverified - AuthSoftFail controls whether a login/pull
+	// failure here is tolerated when the image already exists locally.
+	Auth         container.RegistryAuth
+	AuthSoftFail bool
+
+	// Timeout bounds a single pull attempt; zero means no per-attempt
+	// timeout beyond ctx's own deadline.
+	Timeout time.Duration
+}
+
+// Pull resolves image according to opts.Policy, singleflighting concurrent
+// callers requesting the same image reference.
+func (c *Coordinator) Pull(ctx context.Context, image string, opts PullOptions) error {
+	policy := opts.Policy
+	if policy == "" {
+		policy = PullMissing
+	}
+
+	if policy == PullNever {
+		return nil
+	}
+
+	if policy == PullMissing {
+		exists, err := c.client.ImageExists(ctx, image)
+		if err != nil {
+			return fmt.Errorf("checking if image %s exists: %w", image, err)
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	call, started := c.startOrJoin(image)
+	if started {
+		call.err = c.pullWithBackoff(ctx, image, opts)
+		close(call.done)
+
+		c.mu.Lock()
+		delete(c.inFlight, image)
+		c.mu.Unlock()
+	} else {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if call.err != nil && opts.AuthSoftFail {
+		if exists, existsErr := c.client.ImageExists(ctx, image); existsErr == nil && exists {
+			c.logger.Warn("pull failed but image already present locally, continuing due to auth_soft_fail", "image", image, "error", call.err)
+			return nil
+		}
+	}
+	return call.err
+}
+
+// startOrJoin registers a new in-flight pull for image, or returns the
+// already-running one if another caller beat us to it.
+func (c *Coordinator) startOrJoin(image string) (*pullCall, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if call, ok := c.inFlight[image]; ok {
+		return call, false
+	}
+	call := &pullCall{done: make(chan struct{})}
+	c.inFlight[image] = call
+	return call, true
+}
+
+// pullWithBackoff retries PullWithAuth with exponential backoff (250ms,
+// doubling, capped at 30s), stopping once ctx is done, maxPullAttempts is
+// reached, or the error looks permanent (see isPermanentPullError) -
+// there's no point retrying a 404 or a rejected credential at any cadence.
+func (c *Coordinator) pullWithBackoff(ctx context.Context, image string, opts PullOptions) error {
+	backoff := retryBaseline
+	var lastErr error
+	for attempt := 1; attempt <= maxPullAttempts; attempt++ {
+		attemptCtx := ctx
+		var cancel context.CancelFunc
+		if opts.Timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		}
+		err := c.client.PullWithAuth(attemptCtx, image, opts.Auth)
+		if cancel != nil {
+			cancel()
+		}
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return lastErr
+		}
+		if isPermanentPullError(err) {
+			c.logger.Warn("image pull failed with a non-retryable error, giving up", "image", image, "attempt", attempt, "error", err)
+			return lastErr
+		}
+		if attempt == maxPullAttempts {
+			break
+		}
+
+		c.logger.Warn("image pull failed, retrying", "image", image, "attempt", attempt, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return lastErr
+		}
+		backoff *= 2
+		if backoff > retryCap {
+			backoff = retryCap
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", maxPullAttempts, lastErr)
+}
+
+// isPermanentPullError reports whether err looks like it'll fail the same
+// way on every retry - a missing image/tag (404, "not found", "no such")
+// or rejected credentials (401/403, "unauthorized", "forbidden", "denied")
+// - the same string-matching approach classifyCLIError already uses for
+// the container CLI's stderr, since neither transport gives pull failures
+// a structured sentinel error to errors.Is against.
+func isPermanentPullError(err error) bool {
+	lower := strings.ToLower(err.Error())
+	if status, ok := httpStatusIn(lower); ok && status >= 400 && status < 500 && status != 429 {
+		return true
+	}
+	switch {
+	case strings.Contains(lower, "not found"),
+		strings.Contains(lower, "no such"),
+		strings.Contains(lower, "unauthorized"),
+		strings.Contains(lower, "forbidden"),
+		strings.Contains(lower, "denied"),
+		strings.Contains(lower, "authentication required"):
+		return true
+	default:
+		return false
+	}
+}
+
+// httpStatusIn extracts the numeric status code from an APITransport pull
+// error's "pull: status <code>: ..." message, if lower matches that shape.
+func httpStatusIn(lower string) (int, bool) {
+	const marker = "status "
+	idx := strings.Index(lower, marker)
+	if idx == -1 {
+		return 0, false
+	}
+	rest := lower[idx+len(marker):]
+	end := strings.IndexAny(rest, ": \t")
+	if end == -1 {
+		end = len(rest)
+	}
+	status, err := strconv.Atoi(rest[:end])
+	if err != nil {
+		return 0, false
+	}
+	return status, true
+}
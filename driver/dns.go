@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// writeResolvConf generates a resolv.conf at path from servers/search/options,
+// the same file shape Nomad's exec/docker drivers generate via the shared
+// resolvconf package.
+func writeResolvConf(path string, servers, search, options []string) error {
+	var b strings.Builder
+	for _, s := range servers {
+		fmt.Fprintf(&b, "nameserver %s\n", s)
+	}
+	if len(search) > 0 {
+		fmt.Fprintf(&b, "search %s\n", strings.Join(search, " "))
+	}
+	if len(options) > 0 {
+		fmt.Fprintf(&b, "options %s\n", strings.Join(options, " "))
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// writeHosts generates an /etc/hosts fragment at path from "host:ip" entries.
+func writeHosts(path string, extraHosts []string) error {
+	var b strings.Builder
+	for _, entry := range extraHosts {
+		host, ip, ok := strings.Cut(entry, ":")
+		if !ok {
+			return fmt.Errorf("invalid extra_hosts entry %q, expected \"host:ip\"", entry)
+		}
+		fmt.Fprintf(&b, "%s\t%s\n", ip, host)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
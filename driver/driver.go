@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -16,7 +17,10 @@ import (
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/hashicorp/nomad/plugins/shared/hclspec"
 	"github.com/hashicorp/nomad/plugins/shared/structs"
+	"github.com/kessler-frost/styx/driver/capabilities"
 	"github.com/kessler-frost/styx/driver/container"
+	"github.com/kessler-frost/styx/driver/coordinator"
+	"github.com/kessler-frost/styx/driver/logstream"
 	"github.com/kessler-frost/styx/internal/network"
 )
 
@@ -45,10 +49,11 @@ var (
 // It handles starting, stopping, and monitoring containers on macOS hosts
 // using the native container CLI provided by Apple.
 type Driver struct {
-	eventer *eventer.Eventer
-	config  *Config
-	client  *container.Client
-	logger  hclog.Logger
+	eventer     *eventer.Eventer
+	config      *Config
+	client      *container.Client
+	coordinator *coordinator.Coordinator
+	logger      hclog.Logger
 
 	// tasks is a map of task IDs to task handles
 	tasks     map[string]*taskHandle
@@ -96,6 +101,19 @@ func (d *Driver) SetConfig(cfg *base.Config) error {
 
 	d.config = &config
 	d.client = container.NewClient(config.ContainerBinPath)
+	d.coordinator = coordinator.New(d.logger, d.client)
+
+	if config.Reconcile.Enabled {
+		period, err := time.ParseDuration(config.Reconcile.Period)
+		if err != nil {
+			return fmt.Errorf("invalid reconcile.period: %w", err)
+		}
+		grace, err := time.ParseDuration(config.Reconcile.CreationGrace)
+		if err != nil {
+			return fmt.Errorf("invalid reconcile.creation_grace: %w", err)
+		}
+		go d.runReconciler(period, grace, config.Reconcile.DryRun)
+	}
 
 	return nil
 }
@@ -126,6 +144,13 @@ func (d *Driver) handleFingerprint(ctx context.Context, ch chan<- *drivers.Finge
 	ticker := time.NewTicker(fingerprintPeriod)
 	defer ticker.Stop()
 
+	// everAvailable tracks whether the container runtime has ever answered
+	// successfully, so a later outage - `container system` restarting,
+	// macOS sleeping/waking - reads as HealthStateUnhealthy (transient,
+	// retried fast below) rather than HealthStateUndetected (the CLI was
+	// simply never there).
+	everAvailable := false
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -133,12 +158,44 @@ func (d *Driver) handleFingerprint(ctx context.Context, ch chan<- *drivers.Finge
 		case <-d.ctx.Done():
 			return
 		case <-ticker.C:
-			ch <- d.buildFingerprint()
+			fp := d.buildFingerprint(&everAvailable)
+			ch <- fp
+			if fp.Health == drivers.HealthStateUnhealthy {
+				d.waitForRuntimeRecovery(ctx, ch, &everAvailable)
+			}
 		}
 	}
 }
 
-func (d *Driver) buildFingerprint() *drivers.Fingerprint {
+// waitForRuntimeRecovery polls the container runtime with the same backoff
+// withRetry uses elsewhere (baseline 250ms, capped at 5s), sending a healthy
+// Fingerprint the moment it answers again instead of waiting out the rest
+// of fingerprintPeriod.
+func (d *Driver) waitForRuntimeRecovery(ctx context.Context, ch chan<- *drivers.Fingerprint, everAvailable *bool) {
+	backoff := retryBaseline
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		fp := d.buildFingerprint(everAvailable)
+		if fp.Health == drivers.HealthStateHealthy {
+			ch <- fp
+			return
+		}
+
+		backoff *= 2
+		if backoff > retryCap {
+			backoff = retryCap
+		}
+	}
+}
+
+func (d *Driver) buildFingerprint(everAvailable *bool) *drivers.Fingerprint {
 	fp := &drivers.Fingerprint{
 		Attributes:        map[string]*structs.Attribute{},
 		Health:            drivers.HealthStateHealthy,
@@ -146,10 +203,16 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	}
 
 	if d.client == nil || !d.client.IsAvailable() {
-		fp.Health = drivers.HealthStateUndetected
-		fp.HealthDescription = "container CLI not found"
+		if *everAvailable {
+			fp.Health = drivers.HealthStateUnhealthy
+			fp.HealthDescription = "waiting for container runtime"
+		} else {
+			fp.Health = drivers.HealthStateUndetected
+			fp.HealthDescription = "container CLI not found"
+		}
 		return fp
 	}
+	*everAvailable = true
 
 	version, err := d.client.Version(d.ctx)
 	if err != nil {
@@ -178,6 +241,21 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed to decode driver config: %w", err)
 	}
 
+	if taskConfig.ServiceAddressMode == "" {
+		taskConfig.ServiceAddressMode = AddressModeAuto
+	}
+
+	if taskConfig.Privileged && !d.config.AllowPrivileged {
+		return nil, nil, fmt.Errorf("task config sets privileged = true, but the plugin config has allow_privileged = false")
+	}
+	allowedCaps := d.config.AllowCaps
+	if len(allowedCaps) == 0 {
+		allowedCaps = capabilities.DefaultAllowed
+	}
+	if err := capabilities.Validate(taskConfig.CapAdd, taskConfig.CapDrop, allowedCaps); err != nil {
+		return nil, nil, fmt.Errorf("invalid task config: %w", err)
+	}
+
 	d.logger.Info("starting task", "task_id", cfg.ID, "image", taskConfig.Image)
 
 	// Get task directories for auto-mounting (like Docker driver)
@@ -215,18 +293,45 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 	allVolumes := append(autoMounts, taskConfig.Volumes...)
 	d.logger.Debug("volume mounts", "auto", autoMounts, "user", taskConfig.Volumes, "all", allVolumes)
 
-	// Pre-pull image with retry logic
-	d.logger.Info("pulling image", "image", taskConfig.Image)
-	for attempt := 0; attempt < 3; attempt++ {
-		if err := d.client.Pull(d.ctx, taskConfig.Image); err != nil {
-			if attempt == 2 {
-				return nil, nil, fmt.Errorf("failed to pull image after 3 attempts: %w", err)
-			}
-			d.logger.Warn("image pull failed, retrying", "attempt", attempt+1, "error", err)
-			time.Sleep(time.Duration(attempt+1) * 5 * time.Second)
-			continue
+	// Generate and mount resolv.conf/hosts overrides, following the same
+	// shared resolvconf approach Nomad's exec/docker drivers use.
+	if len(taskConfig.DNSServers) > 0 || len(taskConfig.DNSSearchDomains) > 0 || len(taskConfig.DNSOptions) > 0 {
+		resolvPath := filepath.Join(taskDir.LocalDir, "resolv.conf")
+		if err := writeResolvConf(resolvPath, taskConfig.DNSServers, taskConfig.DNSSearchDomains, taskConfig.DNSOptions); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate resolv.conf: %w", err)
 		}
-		break
+		allVolumes = append(allVolumes, fmt.Sprintf("%s:/etc/resolv.conf", resolvPath))
+	}
+	if len(taskConfig.ExtraHosts) > 0 {
+		hostsPath := filepath.Join(taskDir.LocalDir, "hosts")
+		if err := writeHosts(hostsPath, taskConfig.ExtraHosts); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate hosts file: %w", err)
+		}
+		allVolumes = append(allVolumes, fmt.Sprintf("%s:/etc/hosts", hostsPath))
+	}
+
+	// Resolve the image through the pull coordinator, which singleflights
+	// concurrent pulls of the same image and honors pull_policy/auth.
+	pullTimeout := time.Duration(0)
+	if taskConfig.ImagePullTimeout != "" {
+		var err error
+		pullTimeout, err = time.ParseDuration(taskConfig.ImagePullTimeout)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid image_pull_timeout: %w", err)
+		}
+	}
+	d.logger.Info("resolving image", "image", taskConfig.Image, "pull_policy", taskConfig.PullPolicy)
+	err := d.coordinator.Pull(d.ctx, taskConfig.Image, coordinator.PullOptions{
+		Policy: taskConfig.PullPolicy,
+		Auth: container.RegistryAuth{
+			Username: taskConfig.Auth.Username,
+			Password: taskConfig.Auth.Password,
+		},
+		AuthSoftFail: taskConfig.Auth.AuthSoftFail,
+		Timeout:      pullTimeout,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to pull image: %w", err)
 	}
 
 	// Sanitize container name - Apple container CLI doesn't allow slashes
@@ -239,6 +344,15 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		containerNetwork = network.StyxNetworkName
 	}
 
+	// Stamp every container we create with managed/created-at labels so the
+	// dangling-container reconciler (see reconcile.go) can recognize it.
+	labels := make(map[string]string, len(taskConfig.Labels)+2)
+	for k, v := range taskConfig.Labels {
+		labels[k] = v
+	}
+	labels[managedLabel] = "true"
+	labels[createdAtLabel] = time.Now().UTC().Format(time.RFC3339)
+
 	opts := container.RunOptions{
 		Name:       containerName,
 		Image:      taskConfig.Image,
@@ -251,7 +365,17 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		CPUs:       taskConfig.CPUs,
 		WorkingDir: taskConfig.WorkingDir,
 		Network:    containerNetwork,
+		Labels:     labels,
 		Detach:     true,
+
+		DNSServers:       taskConfig.DNSServers,
+		DNSSearchDomains: taskConfig.DNSSearchDomains,
+		DNSOptions:       taskConfig.DNSOptions,
+		ExtraHosts:       taskConfig.ExtraHosts,
+
+		CapAdd:     taskConfig.CapAdd,
+		CapDrop:    taskConfig.CapDrop,
+		Privileged: taskConfig.Privileged,
 	}
 
 	// Start the container
@@ -316,15 +440,61 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		d.logger.Info("built port map for driver network", "portmap", portMap)
 	}
 
-	// Build DriverNetwork with container IP for service registration
-	// All services go through Traefik, which reaches containers directly on the styx network
-	if containerIP != "" {
-		driverNetwork = &drivers.DriverNetwork{
-			IP:            containerIP,
-			AutoAdvertise: true,
-			PortMap:       portMap,
+	// Build DriverNetwork for service registration, shaped by ServiceAddressMode.
+	switch taskConfig.ServiceAddressMode {
+	case AddressModeHost:
+		// Host mode: register at the host's preferred IP and Nomad's
+		// allocated host ports, bypassing Traefik entirely.
+		hostIP, err := network.GetPreferredIP()
+		if err != nil {
+			d.logger.Warn("failed to determine host IP for host address mode", "error", err)
+		} else {
+			driverNetwork = &drivers.DriverNetwork{
+				IP:            hostIP,
+				AutoAdvertise: true,
+				PortMap:       portMap,
+			}
+			d.logger.Info("using host IP for service registration", "ip", hostIP, "address_mode", taskConfig.ServiceAddressMode)
+		}
+	case AddressModeDriver:
+		// Driver mode: container IP, but don't auto-advertise through
+		// Traefik - Consul checks target the container directly.
+		if containerIP != "" {
+			driverNetwork = &drivers.DriverNetwork{
+				IP:            containerIP,
+				AutoAdvertise: false,
+				PortMap:       portMap,
+			}
+			d.logger.Info("using container IP for service registration", "ip", containerIP, "address_mode", taskConfig.ServiceAddressMode)
+		}
+	default:
+		// Auto mode (default): container IP, auto-advertised through
+		// Traefik, which reaches containers directly on the styx network.
+		if containerIP != "" {
+			driverNetwork = &drivers.DriverNetwork{
+				IP:            containerIP,
+				AutoAdvertise: true,
+				PortMap:       portMap,
+			}
+			d.logger.Info("using container IP for service registration", "ip", containerIP, "address_mode", taskConfig.ServiceAddressMode)
 		}
-		d.logger.Info("using container IP for service registration", "ip", containerIP)
+	}
+
+	// Ship the container's logs into Nomad's task log files so
+	// `nomad alloc logs` works - the container CLI handles its own
+	// logging outside of Nomad's exec/raw_exec redirection path.
+	shipper := logstream.New(d.logger, logstream.Options{
+		BinPath:       d.client.BinPath(),
+		ContainerID:   containerID,
+		StdoutPath:    cfg.StdoutPath,
+		StderrPath:    cfg.StderrPath,
+		MaxFiles:      taskConfig.MaxFiles,
+		MaxFileSizeMB: taskConfig.MaxFileSizeMB,
+	})
+	if err := shipper.Start(d.ctx); err != nil {
+		d.logger.Warn("failed to start log shipper", "container_id", containerID, "error", err)
+	} else {
+		handle.logShipper = shipper
 	}
 
 	// Store the handle
@@ -337,9 +507,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 
 	// Build task state for recovery
 	taskState := TaskState{
-		TaskConfig:  &taskConfig,
-		ContainerID: containerID,
-		StartedAt:   handle.startedAt,
+		TaskConfig:    &taskConfig,
+		ContainerID:   containerID,
+		StartedAt:     handle.startedAt,
+		LogShipperPID: shipper.PID(),
 	}
 
 	taskHandle := drivers.NewTaskHandle(taskHandleVersion)
@@ -377,6 +548,27 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 	h := newTaskHandle(d.client, d.logger, taskState.ContainerID, taskState.TaskConfig)
 	h.startedAt = taskState.StartedAt
 
+	// Re-attach the log shipper if it's still alive, otherwise start a
+	// fresh one - the prior driver instance's process tree is gone, but
+	// the subprocess itself survives a driver/Nomad client restart.
+	if logstream.IsAlive(taskState.LogShipperPID) {
+		h.logShipper = logstream.Attach(d.logger, taskState.LogShipperPID)
+	} else {
+		shipper := logstream.New(d.logger, logstream.Options{
+			BinPath:       d.client.BinPath(),
+			ContainerID:   taskState.ContainerID,
+			StdoutPath:    handle.Config.StdoutPath,
+			StderrPath:    handle.Config.StderrPath,
+			MaxFiles:      taskState.TaskConfig.MaxFiles,
+			MaxFileSizeMB: taskState.TaskConfig.MaxFileSizeMB,
+		})
+		if err := shipper.Start(d.ctx); err != nil {
+			d.logger.Warn("failed to restart log shipper during recovery", "container_id", taskState.ContainerID, "error", err)
+		} else {
+			h.logShipper = shipper
+		}
+	}
+
 	d.tasksLock.Lock()
 	d.tasks[handle.Config.ID] = h
 	d.tasksLock.Unlock()
@@ -426,7 +618,9 @@ func (d *Driver) StopTask(taskID string, timeout time.Duration, signal string) e
 
 	// Send signal if specified
 	if signal != "" {
-		if err := d.client.Kill(d.ctx, handle.containerID, signal); err != nil {
+		if err := withRetry(d.ctx, func() error {
+			return d.client.Kill(d.ctx, handle.containerID, signal)
+		}); err != nil {
 			d.logger.Warn("failed to send signal", "error", err)
 		}
 	}
@@ -435,7 +629,9 @@ func (d *Driver) StopTask(taskID string, timeout time.Duration, signal string) e
 	ctx, cancel := context.WithTimeout(d.ctx, timeout)
 	defer cancel()
 
-	return d.client.Stop(ctx, handle.containerID)
+	return withRetry(ctx, func() error {
+		return d.client.Stop(ctx, handle.containerID)
+	})
 }
 
 // DestroyTask removes a container and cleans up associated resources.
@@ -498,7 +694,12 @@ func (d *Driver) TaskStats(ctx context.Context, taskID string, interval time.Dur
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				stats, err := d.client.Stats(ctx, handle.containerID)
+				var stats *container.ContainerStats
+				err := withRetry(ctx, func() error {
+					var statsErr error
+					stats, statsErr = d.client.Stats(ctx, handle.containerID)
+					return statsErr
+				})
 				if err != nil {
 					// Container may not be running, send empty stats
 					ch <- &drivers.TaskResourceUsage{
@@ -547,7 +748,9 @@ func (d *Driver) SignalTask(taskID string, signal string) error {
 		return fmt.Errorf("task %s not found", taskID)
 	}
 
-	return d.client.Kill(d.ctx, handle.containerID, signal)
+	return withRetry(d.ctx, func() error {
+		return d.client.Kill(d.ctx, handle.containerID, signal)
+	})
 }
 
 // ExecTask executes a command inside a running container and returns the output.
@@ -8,8 +8,19 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/kessler-frost/styx/driver/container"
+	"github.com/kessler-frost/styx/driver/logstream"
+	"github.com/kessler-frost/styx/internal/shutdown"
+	"github.com/kessler-frost/styx/internal/supervisor"
 )
 
+// Compile-time assertion that taskHandle implements supervisor.Service.
+var _ supervisor.Service = (*taskHandle)(nil)
+
+// Compile-time assertion that taskHandle also satisfies shutdown.Task, so a
+// shutdown.Coordinator could register running taskHandles once the driver
+// gains a plugin entrypoint to host one.
+var _ shutdown.Task = (*taskHandle)(nil)
+
 // taskHandle manages the lifecycle of a running container task.
 // It tracks the container process and provides methods for stopping and cleanup.
 type taskHandle struct {
@@ -28,12 +39,21 @@ type taskHandle struct {
 	// taskConfig contains the original task configuration.
 	taskConfig *TaskConfig
 
+	// logShipper ships the container's stdout/stderr into this task's
+	// Nomad log files (see driver/logstream). Nil if it couldn't be
+	// started, or on a recovered handle until RecoverTask attaches one.
+	logShipper *logstream.Shipper
+
 	// startedAt is the timestamp when the container was started.
 	startedAt time.Time
 
 	// exitResult stores the exit result once the container exits.
 	exitResult *drivers.ExitResult
 
+	// completedAt is the timestamp the exit event reported, used for
+	// TaskStatus.CompletedAt instead of the time the status happens to be read.
+	completedAt time.Time
+
 	// waitCh is closed when the container exits.
 	waitCh chan struct{}
 
@@ -56,44 +76,89 @@ func newTaskHandle(client *container.Client, logger hclog.Logger, containerID st
 		ctx:         ctx,
 		cancel:      cancel,
 	}
+	runningContainers.Inc()
 	return h
 }
 
-// run monitors the container status and waits for it to exit.
-// It periodically polls the container state and closes waitCh when the container exits.
+// run monitors the container status and waits for it to exit, closing
+// waitCh once Serve returns. It drives Serve with the handle's own
+// cancellable ctx so shutdown() keeps working unchanged.
 func (h *taskHandle) run() {
 	defer close(h.waitCh)
+	h.Serve(h.ctx)
+}
+
+// Serve implements supervisor.Service: it waits on the container's event
+// stream (see container.Client.Wait, which prefers `container events` over
+// polling) until an exit event arrives or ctx is cancelled, recording the
+// real exit code/OOM flag/timestamp for TaskStatus/GetExitResult. newTaskHandle
+// still owns its own ctx/cancel pair rather than being handed to a
+// supervisor.Tree directly.
+func (h *taskHandle) Serve(ctx context.Context) error {
+	defer runningContainers.Dec()
+
+	var events <-chan container.ContainerEvent
+	err := withRetry(ctx, func() error {
+		var waitErr error
+		events, waitErr = h.client.Wait(ctx, h.containerID)
+		return waitErr
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case ev, ok := <-events:
+		if !ok {
+			return nil
+		}
 
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-h.ctx.Done():
-			return
-		case <-ticker.C:
-			running := h.client.IsRunning(h.ctx, h.containerID)
-			if !running {
-				h.stateLock.Lock()
-				h.exitResult = &drivers.ExitResult{
-					ExitCode:  0,
-					Signal:    0,
-					OOMKilled: false,
-					Err:       nil,
-				}
-				h.stateLock.Unlock()
-				h.logger.Info("container exited")
-				return
-			}
+		h.stateLock.Lock()
+		h.exitResult = &drivers.ExitResult{
+			ExitCode:  ev.ExitCode,
+			OOMKilled: ev.OOMKilled,
 		}
+		h.completedAt = ev.Timestamp
+		h.stateLock.Unlock()
+
+		exitDetectionSeconds.Observe(time.Since(h.startedAt))
+		outcome := "exited"
+		if ev.OOMKilled {
+			outcome = "oom_killed"
+		} else if ev.ExitCode != 0 {
+			outcome = "error"
+		}
+		containerExits.IncLabel(outcome)
+
+		h.logger.Info("container exited", "exit_code", ev.ExitCode, "oom_killed", ev.OOMKilled)
+		return nil
 	}
 }
 
 // shutdown stops monitoring the container and cancels all background operations.
 func (h *taskHandle) shutdown() {
+	if h.logShipper != nil {
+		if err := h.logShipper.Stop(); err != nil {
+			h.logger.Warn("failed to stop log shipper", "error", err)
+		}
+	}
 	h.cancel()
 }
 
+// Shutdown implements shutdown.Task, calling the same cancellation DestroyTask
+// uses via shutdown().
+func (h *taskHandle) Shutdown() {
+	h.shutdown()
+}
+
+// Done implements shutdown.Task: it's closed once Serve returns and the
+// container's exit has been recorded.
+func (h *taskHandle) Done() <-chan struct{} {
+	return h.waitCh
+}
+
 // IsRunning checks if the container is currently running.
 func (h *taskHandle) IsRunning() bool {
 	return h.client.IsRunning(h.ctx, h.containerID)
@@ -115,7 +180,7 @@ func (h *taskHandle) TaskStatus() *drivers.TaskStatus {
 	}
 
 	if h.exitResult != nil {
-		status.CompletedAt = time.Now()
+		status.CompletedAt = h.completedAt
 		status.State = drivers.TaskStateExited
 		status.ExitResult = h.exitResult
 	}
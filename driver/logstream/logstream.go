@@ -0,0 +1,230 @@
+// Package logstream ships a running container's stdout/stderr into Nomad's
+// task log files, playing the same role Nomad's docklog helper plays for
+// the docker driver. Without it, the container CLI's own log handling never
+// reaches Nomad, and `nomad alloc logs` sees nothing.
+package logstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// DefaultMaxFiles is how many rotated files Shipper keeps per stream
+	// when TaskConfig doesn't set max_files.
+	DefaultMaxFiles = 2
+
+	// DefaultMaxFileSizeMB is the rotation threshold Shipper uses per
+	// stream when TaskConfig doesn't set max_file_size_mb.
+	DefaultMaxFileSizeMB = 10
+)
+
+// Options configures a Shipper.
+type Options struct {
+	BinPath       string // path to the container CLI binary
+	ContainerID   string
+	StdoutPath    string // where the task's stdout should be written
+	StderrPath    string // where the task's stderr should be written
+	MaxFiles      int    // rotated files retained per stream; <=0 uses DefaultMaxFiles
+	MaxFileSizeMB int    // rotation threshold in MB; <=0 uses DefaultMaxFileSizeMB
+}
+
+// Shipper runs `container logs -f <id>` as a subprocess and copies its
+// stdout/stderr into the task's log files, rotating each one once it
+// crosses MaxFileSizeMB and keeping at most MaxFiles old generations
+// (path, path.0, path.1, ...), the same shape Nomad's docklog/logmon pair
+// gives the docker driver.
+type Shipper struct {
+	opts   Options
+	logger hclog.Logger
+
+	mu  sync.Mutex
+	cmd *exec.Cmd
+}
+
+// New creates a Shipper. Call Start to actually launch it.
+func New(logger hclog.Logger, opts Options) *Shipper {
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = DefaultMaxFiles
+	}
+	if opts.MaxFileSizeMB <= 0 {
+		opts.MaxFileSizeMB = DefaultMaxFileSizeMB
+	}
+	return &Shipper{
+		opts:   opts,
+		logger: logger.Named("logstream").With("container_id", opts.ContainerID),
+	}
+}
+
+// Start launches `container logs -f` and begins copying its output into the
+// configured log files in the background. It returns once the subprocess
+// has been started, not once it exits, so the caller can persist PID()
+// into its own state before returning.
+func (s *Shipper) Start(ctx context.Context) error {
+	stdout, err := newRotatingWriter(s.opts.StdoutPath, s.opts.MaxFileSizeMB, s.opts.MaxFiles)
+	if err != nil {
+		return fmt.Errorf("failed to open stdout log: %w", err)
+	}
+	stderr, err := newRotatingWriter(s.opts.StderrPath, s.opts.MaxFileSizeMB, s.opts.MaxFiles)
+	if err != nil {
+		stdout.Close()
+		return fmt.Errorf("failed to open stderr log: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, s.opts.BinPath, "logs", "-f", s.opts.ContainerID)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		stdout.Close()
+		stderr.Close()
+		return fmt.Errorf("failed to start log shipper: %w", err)
+	}
+
+	s.mu.Lock()
+	s.cmd = cmd
+	s.mu.Unlock()
+
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			s.logger.Debug("log shipper exited", "error", err)
+		}
+		stdout.Close()
+		stderr.Close()
+	}()
+
+	return nil
+}
+
+// PID returns the log-shipping subprocess's PID, for persisting into
+// TaskState so RecoverTask can check whether it's still alive.
+func (s *Shipper) PID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cmd == nil || s.cmd.Process == nil {
+		return 0
+	}
+	return s.cmd.Process.Pid
+}
+
+// Stop terminates the log-shipping subprocess.
+func (s *Shipper) Stop() error {
+	s.mu.Lock()
+	cmd := s.cmd
+	s.mu.Unlock()
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
+
+// Attach wraps an already-running log-shipping subprocess (one this process
+// didn't start itself) so PID and Stop still work on it, for RecoverTask
+// re-attaching across a driver restart. Start must not be called on the
+// result - it's only for subprocesses this process itself launched.
+func Attach(logger hclog.Logger, pid int) *Shipper {
+	return &Shipper{
+		logger: logger.Named("logstream").With("pid", pid),
+		cmd:    &exec.Cmd{Process: &os.Process{Pid: pid}},
+	}
+}
+
+// IsAlive reports whether pid (as persisted by a prior Shipper.PID) still
+// refers to a running process, for RecoverTask to decide between
+// re-attaching and starting a fresh Shipper.
+func IsAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+// rotatingWriter is an io.WriteCloser that rotates the underlying file once
+// it crosses maxSizeMB, keeping at most maxFiles old generations suffixed
+// ".0", ".1", etc. (newest first).
+type rotatingWriter struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+	mu       sync.Mutex
+	file     *os.File
+	written  int64
+}
+
+func newRotatingWriter(path string, maxSizeMB, maxFiles int) (*rotatingWriter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &rotatingWriter{
+		path:     path,
+		maxSize:  int64(maxSizeMB) * 1024 * 1024,
+		maxFiles: maxFiles,
+		file:     f,
+		written:  info.Size(),
+	}, nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.written+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	for i := w.maxFiles - 1; i >= 0; i-- {
+		src := w.path
+		if i > 0 {
+			src = fmt.Sprintf("%s.%d", w.path, i-1)
+		}
+		dst := fmt.Sprintf("%s.%d", w.path, i)
+		if _, err := os.Stat(src); err == nil {
+			os.Rename(src, dst)
+		}
+	}
+
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	w.file = f
+	w.written = 0
+	return nil
+}
+
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+var _ io.WriteCloser = (*rotatingWriter)(nil)
@@ -0,0 +1,37 @@
+package main
+
+import "github.com/kessler-frost/styx/internal/diagnostic"
+
+// Diagnostics collects the driver's gauges/counters/histograms so a
+// diagnostic.Server can expose them at /metrics once the driver gains a
+// plugin entrypoint to host one. Package-level like the rest of this
+// plugin's process-wide state, since there's exactly one driver instance
+// per Nomad client process.
+var Diagnostics = diagnostic.NewRegistry()
+
+var (
+	// runningContainers tracks how many taskHandles currently believe their
+	// container is running, incremented in newTaskHandle and decremented
+	// when Serve returns (an exit event, cancellation, or a failed Wait).
+	runningContainers = Diagnostics.AddGauge(diagnostic.NewGauge(
+		"styx_driver_containers_running",
+		"Number of containers the driver currently considers running.",
+	))
+
+	// exitDetectionSeconds buckets how long Serve spent waiting on
+	// container.Client.Wait before an exit event arrived, since that wait
+	// falls back to polling when `container events` isn't available.
+	exitDetectionSeconds = Diagnostics.AddHistogram(diagnostic.NewHistogram(
+		"styx_driver_exit_detection_seconds",
+		"Time between a container starting and the driver detecting its exit.",
+		[]float64{1, 5, 15, 30, 60, 120, 300},
+	))
+
+	// containerExits counts exit events by outcome, so an operator can tell
+	// OOM kills apart from clean exits without scraping logs.
+	containerExits = Diagnostics.AddCounter(diagnostic.NewCounterVec(
+		"styx_driver_container_exits_total",
+		"Container exit events observed by the driver, by outcome.",
+		"outcome",
+	))
+)
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"time"
+)
+
+// runReconciler periodically removes dangling styx-managed containers -
+// ones left running by a driver or Nomad client crash between client.Run
+// succeeding in StartTask and SetDriverState persisting the task, which
+// would otherwise consume resources indefinitely. It mirrors Nomad's own
+// docker driver reconciler: list everything the runtime knows about,
+// cross-reference against live tasks, and remove what's left over once
+// it's old enough that it can't just be a task that's still starting.
+func (d *Driver) runReconciler(period, creationGrace time.Duration, dryRun bool) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			d.reconcileOnce(creationGrace, dryRun)
+		}
+	}
+}
+
+// reconcileOnce runs a single reconciliation pass.
+func (d *Driver) reconcileOnce(creationGrace time.Duration, dryRun bool) {
+	containers, err := d.client.List(d.ctx, true)
+	if err != nil {
+		d.logger.Warn("reconciler: failed to list containers", "error", err)
+		return
+	}
+
+	d.tasksLock.RLock()
+	tracked := make(map[string]bool, len(d.tasks))
+	for _, h := range d.tasks {
+		tracked[h.containerID] = true
+	}
+	d.tasksLock.RUnlock()
+
+	for _, info := range containers {
+		id := info.Configuration.ID
+		if tracked[id] {
+			continue
+		}
+		if info.Configuration.Labels[managedLabel] != "true" {
+			continue
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, info.Configuration.Labels[createdAtLabel])
+		if err != nil {
+			d.logger.Warn("reconciler: skipping container with unparseable created-at label", "container_id", id, "error", err)
+			continue
+		}
+		if time.Since(createdAt) < creationGrace {
+			// Still within StartTask's window to finish SetDriverState.
+			continue
+		}
+
+		if dryRun {
+			d.logger.Info("reconciler: would remove dangling container", "container_id", id, "created_at", createdAt)
+			continue
+		}
+
+		d.logger.Info("reconciler: removing dangling container", "container_id", id, "created_at", createdAt)
+		if err := d.client.Stop(d.ctx, id); err != nil {
+			d.logger.Warn("reconciler: failed to stop dangling container", "container_id", id, "error", err)
+		}
+		if err := d.client.Remove(d.ctx, id); err != nil {
+			d.logger.Warn("reconciler: failed to remove dangling container", "container_id", id, "error", err)
+		}
+	}
+}
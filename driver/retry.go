@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kessler-frost/styx/driver/container"
+)
+
+const (
+	// retryBaseline, retryCap, and retryMaxAttempts mirror Nomad's own
+	// LazyHandle retry shape for docker client calls.
+	retryBaseline    = 250 * time.Millisecond
+	retryCap         = 5 * time.Second
+	retryMaxAttempts = 5
+)
+
+// withRetry calls fn, retrying with exponential backoff (baseline
+// retryBaseline, capped at retryCap, up to retryMaxAttempts total calls)
+// when fn's error looks like a transient container-runtime outage - the
+// CLI restarting after `container system restart`, or coming back from a
+// macOS sleep/wake cycle. container.ErrNotFound fails fast instead: that's
+// the task's container being gone, not the runtime being unavailable, and
+// no amount of retrying fixes it.
+func withRetry(ctx context.Context, fn func() error) error {
+	var err error
+	backoff := retryBaseline
+
+	for attempt := 0; attempt < retryMaxAttempts; attempt++ {
+		err = fn()
+		if err == nil || errors.Is(err, container.ErrNotFound) {
+			return err
+		}
+
+		if attempt == retryMaxAttempts-1 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > retryCap {
+			backoff = retryCap
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,25 @@
+package api
+
+// GetAlerts returns all alerts known to Alertmanager, optionally narrowed by
+// a Filter expression (see ParseFilter) evaluated against each Alert. Note
+// the filter can't reach into Labels/Annotations (lookupField doesn't
+// support maps), only top-level fields like Status.State.
+func (c *Client) GetAlerts(filter string) ([]Alert, error) {
+	f, err := ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []Alert
+	if err := c.get(c.alertmanagerAddr+"/api/v2/alerts", &alerts); err != nil {
+		return nil, err
+	}
+
+	var result []Alert
+	for _, a := range alerts {
+		if f.Match(a) {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
@@ -1,10 +1,18 @@
 package api
 
 import (
+	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
+
+	"github.com/kessler-frost/styx/internal/observability"
+	"github.com/kessler-frost/styx/internal/pki"
 )
 
 // Client provides access to Styx/Nomad/Vault APIs.
@@ -12,14 +20,33 @@ type Client struct {
 	httpClient *http.Client
 	nomadAddr  string
 	vaultAddr  string
+	consulAddr string
+
+	// alertmanagerAddr is where GetAlerts queries Alertmanager's
+	// /api/v2/alerts, overridable via WithAlertmanagerAddr.
+	alertmanagerAddr string
+
+	// upgradeStateDir, if set via WithUpgradeStateDir, is where
+	// GetClusterStatus looks for the upgrade.State file `styx upgrade`
+	// writes, so ClusterStatus.Upgrading/TargetVersion reflect an
+	// in-progress rolling upgrade.
+	upgradeStateDir string
+
+	// metrics, if set via WithMetrics, records latency for every outbound
+	// request this client makes, keyed by request path - e.g. so a
+	// diagnostic.Server can expose how slow the TUI's own Nomad/Vault/Consul
+	// polling is, distinct from those servers' own latency.
+	metrics *observability.Metrics
 }
 
 // NewClient creates a new API client with default addresses.
 func NewClient() *Client {
 	return &Client{
-		httpClient: &http.Client{Timeout: 2 * time.Second},
-		nomadAddr:  "http://127.0.0.1:4646",
-		vaultAddr:  "http://127.0.0.1:8200",
+		httpClient:       &http.Client{Timeout: 2 * time.Second},
+		nomadAddr:        "http://127.0.0.1:4646",
+		vaultAddr:        "http://127.0.0.1:8200",
+		consulAddr:       "http://127.0.0.1:8500",
+		alertmanagerAddr: "http://127.0.0.1:9093",
 	}
 }
 
@@ -35,9 +62,93 @@ func (c *Client) WithVaultAddr(addr string) *Client {
 	return c
 }
 
+// WithConsulAddr sets a custom Consul address, used by SnapshotConsul since
+// Consul isn't otherwise exposed through Client (see ConsulServerConfig in
+// internal/config, which isn't rendered by any command either).
+func (c *Client) WithConsulAddr(addr string) *Client {
+	c.consulAddr = addr
+	return c
+}
+
+// WithAlertmanagerAddr sets a custom Alertmanager address, used by GetAlerts.
+func (c *Client) WithAlertmanagerAddr(addr string) *Client {
+	c.alertmanagerAddr = addr
+	return c
+}
+
+// WithUpgradeStateDir points GetClusterStatus at the directory `styx
+// upgrade` records its progress in, so it can populate
+// ClusterStatus.Upgrading/TargetVersion. Unset by default: most callers
+// don't care about in-progress upgrades.
+func (c *Client) WithUpgradeStateDir(dir string) *Client {
+	c.upgradeStateDir = dir
+	return c
+}
+
+// WithMetrics records every outbound request's latency into m, keyed by
+// request path. Unset by default: most callers (CLI one-shots) don't live
+// long enough for a latency histogram to be worth collecting.
+func (c *Client) WithMetrics(m *observability.Metrics) *Client {
+	c.metrics = m
+	return c
+}
+
+// Metrics returns the latency histograms fed by WithMetrics, or nil if it
+// hasn't been called.
+func (c *Client) Metrics() *observability.Metrics {
+	return c.metrics
+}
+
+// WithAccessLog instruments every outbound request with structured access
+// logging and latency histograms, recorded to log. This is what lets
+// operators debug slow bootstraps or unhealthy Nomad/Vault polling instead
+// of seeing only an opaque "not_responding" status.
+func (c *Client) WithAccessLog(log *observability.AccessLog) *Client {
+	c.httpClient.Transport = &observability.InstrumentedTransport{
+		Next: c.httpClient.Transport,
+		Log:  log,
+	}
+	return c
+}
+
+// WithMTLS points the client at Nomad/Vault's mTLS listeners instead of
+// plain HTTP, using the CA at certs.CAFile to verify the server and
+// certs.CertFile/KeyFile to authenticate the client. It rewrites
+// nomadAddr/vaultAddr from http:// to https:// so callers don't also have
+// to flip WithNomadAddr/WithVaultAddr by hand.
+func (c *Client) WithMTLS(certs *pki.CertPaths) (*Client, error) {
+	pool, err := pki.GetCAPool(certs.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA pool: %w", err)
+	}
+
+	cert, err := pki.LoadClientCertificate(certs.CertFile, certs.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	c.httpClient.Transport = &http.Transport{
+		TLSClientConfig: &tls.Config{
+			RootCAs:      pool,
+			Certificates: []tls.Certificate{cert},
+		},
+	}
+
+	c.nomadAddr = toHTTPS(c.nomadAddr)
+	c.vaultAddr = toHTTPS(c.vaultAddr)
+
+	return c, nil
+}
+
+func toHTTPS(addr string) string {
+	return "https://" + strings.TrimPrefix(strings.TrimPrefix(addr, "https://"), "http://")
+}
+
 // get performs a GET request and decodes JSON response.
-func (c *Client) get(url string, v interface{}) error {
-	resp, err := c.httpClient.Get(url)
+func (c *Client) get(rawURL string, v interface{}) error {
+	defer c.observe(rawURL, time.Now())
+
+	resp, err := c.httpClient.Get(rawURL)
 	if err != nil {
 		return err
 	}
@@ -51,11 +162,95 @@ func (c *Client) get(url string, v interface{}) error {
 }
 
 // getStatus performs a GET request and returns the status code.
-func (c *Client) getStatus(url string) (int, error) {
-	resp, err := c.httpClient.Get(url)
+func (c *Client) getStatus(rawURL string) (int, error) {
+	defer c.observe(rawURL, time.Now())
+
+	resp, err := c.httpClient.Get(rawURL)
 	if err != nil {
 		return 0, err
 	}
 	defer resp.Body.Close()
 	return resp.StatusCode, nil
 }
+
+// post performs a POST with a JSON-encoded body and decodes a JSON response
+// into v. v may be nil if the caller only cares whether the request
+// succeeded (e.g. job registration).
+func (c *Client) post(rawURL string, body, v interface{}) error {
+	defer c.observe(rawURL, time.Now())
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(rawURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// observe records a latency observation for rawURL's path if WithMetrics
+// was called; a no-op otherwise.
+func (c *Client) observe(rawURL string, start time.Time) {
+	if c.metrics == nil {
+		return
+	}
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	c.metrics.Observe(templatePath(path), time.Since(start))
+}
+
+// templatePath collapses Nomad/Vault path segments that embed an ID (job
+// name, allocation ID, node ID, KV path) down to a fixed placeholder, so
+// Metrics' per-path map stays bounded instead of growing one entry per
+// allocation/job/node the client ever touches over a long-running TUI
+// session.
+func templatePath(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/v1/job/") && strings.HasSuffix(path, "/allocations"):
+		return "/v1/job/{id}/allocations"
+	case strings.HasPrefix(path, "/v1/client/allocation/") && strings.HasSuffix(path, "/stats"):
+		return "/v1/client/allocation/{id}/stats"
+	case strings.HasPrefix(path, "/v1/allocation/"):
+		return "/v1/allocation/{id}"
+	case strings.HasPrefix(path, "/v1/node/") && strings.HasSuffix(path, "/drain"):
+		return "/v1/node/{id}/drain"
+	case strings.HasPrefix(path, "/v1/var/"):
+		return "/v1/var/{path}"
+	default:
+		return path
+	}
+}
+
+// getBytes performs a GET request and returns the raw response body,
+// for endpoints that return a binary payload rather than JSON (Raft
+// snapshots, in particular).
+func (c *Client) getBytes(rawURL string) ([]byte, error) {
+	defer c.observe(rawURL, time.Now())
+
+	resp, err := c.httpClient.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
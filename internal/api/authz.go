@@ -0,0 +1,16 @@
+package api
+
+import (
+	"github.com/kessler-frost/styx/internal/authz"
+)
+
+// GetAuthzPolicy returns the effective authz policy (see authz.LoadPolicy
+// and `styx authz status`), so operators can see which identities map to
+// which capability tier before relying on it to gate Nomad access.
+func (c *Client) GetAuthzPolicy() ([]authz.PolicyEntry, error) {
+	policy, err := authz.LoadPolicy(authz.PolicyPath)
+	if err != nil {
+		return nil, err
+	}
+	return policy.Entries(), nil
+}
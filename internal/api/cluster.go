@@ -1,13 +1,23 @@
 package api
 
 import (
+	"fmt"
+
 	"github.com/kessler-frost/styx/internal/launchd"
+	"github.com/kessler-frost/styx/internal/upgrade"
 )
 
 // GetClusterStatus returns the current cluster status.
 func (c *Client) GetClusterStatus() ClusterStatus {
 	status := ClusterStatus{}
 
+	if c.upgradeStateDir != "" {
+		if s, inProgress, err := upgrade.ReadState(c.upgradeStateDir); err == nil && inProgress {
+			status.Upgrading = true
+			status.TargetVersion = s.TargetVersion
+		}
+	}
+
 	// Check if service is running
 	if !launchd.IsLoaded("com.styx.nomad") {
 		status.Service = "stopped"
@@ -54,6 +64,14 @@ func (c *Client) GetClusterStatus() ClusterStatus {
 	return status
 }
 
+// vaultSealStatus is the response shape of /v1/sys/seal-status.
+type vaultSealStatus struct {
+	Initialized bool `json:"initialized"`
+	Sealed      bool `json:"sealed"`
+	N           int  `json:"n"`        // total unseal key shares
+	Progress    int  `json:"progress"` // key shares submitted so far
+}
+
 func (c *Client) getVaultStatus() VaultStatus {
 	status := VaultStatus{}
 
@@ -76,6 +94,13 @@ func (c *Client) getVaultStatus() VaultStatus {
 		status.Status = "error"
 	}
 
+	var seal vaultSealStatus
+	if err := c.get(c.vaultAddr+"/v1/sys/seal-status", &seal); err == nil {
+		status.Initialized = seal.Initialized
+		status.SealedKeys = seal.N
+		status.UnsealProgress = seal.Progress
+	}
+
 	return status
 }
 
@@ -97,6 +122,31 @@ func (c *Client) getNomadStatus() NomadStatus {
 	return status
 }
 
+// GetMember returns the named cluster member, or nil if no member by that
+// name is registered, for cluster.ReplaceNode. Unlike getClusterMembers,
+// used by GetClusterStatus for display, this surfaces the request error
+// instead of swallowing it - a replace-node run shouldn't silently treat
+// "Nomad didn't answer" the same as "no such member".
+func (c *Client) GetMember(name string) (*Member, error) {
+	var resp AgentMembers
+	if err := c.get(c.nomadAddr+"/v1/agent/members", &resp); err != nil {
+		return nil, fmt.Errorf("failed to get cluster members: %w", err)
+	}
+
+	for _, m := range resp.Members {
+		if m.Name == name {
+			return &Member{
+				Name:   m.Name,
+				Addr:   m.Addr,
+				Port:   m.Port,
+				Status: m.Status,
+				Role:   m.Tags.Role,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
 func (c *Client) getClusterMembers() []Member {
 	var resp AgentMembers
 	if err := c.get(c.nomadAddr+"/v1/agent/members", &resp); err != nil {
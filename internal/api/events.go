@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Event is one entry from Nomad's /v1/event/stream, trimmed to what `styx
+// status --watch` needs to decide when to redraw - which topic changed and
+// how, not the full event payload.
+type Event struct {
+	Topic string
+	Type  string
+}
+
+// eventStreamFrame mirrors one line of Nomad's /v1/event/stream body: a
+// batch of events sharing an index, or an empty object sent as a
+// heartbeat to keep the connection alive.
+type eventStreamFrame struct {
+	Events []struct {
+		Topic string `json:"Topic"`
+		Type  string `json:"Type"`
+	} `json:"Events"`
+}
+
+// StreamEvents subscribes to Nomad's /v1/event/stream for the given topics
+// (e.g. "Node", "Deployment", "Allocation") and returns a channel that
+// receives one Event per change. The channel is closed when ctx is
+// cancelled or the connection drops; the caller is responsible for
+// reconnecting - see cmd/styx's watchStatus.
+func (c *Client) StreamEvents(ctx context.Context, topics []string) (<-chan Event, error) {
+	url := fmt.Sprintf("%s/v1/event/stream?topic=%s", c.nomadAddr, strings.Join(topics, "&topic="))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// A subscription is long-lived, so it can't share the client's default
+	// short request timeout (mirrors StreamAllocLogs).
+	streamClient := &http.Client{}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event stream: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+
+			var frame eventStreamFrame
+			if err := json.Unmarshal(line, &frame); err != nil {
+				continue
+			}
+			for _, e := range frame.Events {
+				select {
+				case events <- Event{Topic: e.Topic, Type: e.Type}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Filter is a compiled predicate expression that can be evaluated against a
+// Job, Alloc, or Node struct. Expressions look like:
+//
+//	Status == "running" and Type != "batch"
+//	Status in ["running", "pending"]
+//	Name contains "web"
+//
+// Supported operators are ==, !=, in, and contains, combined with and/or
+// (left-to-right, and binds tighter than or). Field names support dotted
+// access into nested struct fields, e.g. "Allocations.0.ClientStatus".
+type Filter struct {
+	root node
+}
+
+// ParseFilter compiles a filter expression. An empty expression matches
+// everything.
+func ParseFilter(expr string) (*Filter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return &Filter{root: alwaysTrue{}}, nil
+	}
+
+	p := &parser{tokens: tokenize(expr)}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("invalid filter %q: unexpected token %q", expr, p.tokens[p.pos])
+	}
+
+	return &Filter{root: n}, nil
+}
+
+// Match reports whether v satisfies the filter. v must be a struct or
+// pointer to struct.
+func (f *Filter) Match(v interface{}) bool {
+	if f == nil {
+		return true
+	}
+	return f.root.eval(reflect.ValueOf(v))
+}
+
+// node is one term of a compiled filter expression tree.
+type node interface {
+	eval(reflect.Value) bool
+}
+
+type alwaysTrue struct{}
+
+func (alwaysTrue) eval(reflect.Value) bool { return true }
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(v reflect.Value) bool { return n.left.eval(v) && n.right.eval(v) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(v reflect.Value) bool { return n.left.eval(v) || n.right.eval(v) }
+
+type comparison struct {
+	field string
+	op    string
+	value []string // single element, except for "in"
+}
+
+func (c comparison) eval(v reflect.Value) bool {
+	fv, ok := lookupField(v, c.field)
+	if !ok {
+		return false
+	}
+	actual := fmt.Sprintf("%v", fv.Interface())
+
+	switch c.op {
+	case "==":
+		return actual == c.value[0]
+	case "!=":
+		return actual != c.value[0]
+	case "contains":
+		return strings.Contains(actual, c.value[0])
+	case "in":
+		for _, want := range c.value {
+			if actual == want {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// lookupField resolves a dotted field path (e.g. "Allocations.0.ClientStatus")
+// against a struct or slice, following pointers and indices as needed.
+func lookupField(v reflect.Value, path string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+
+	parts := strings.Split(path, ".")
+	cur := v
+	for _, part := range parts {
+		for cur.Kind() == reflect.Ptr {
+			if cur.IsNil() {
+				return reflect.Value{}, false
+			}
+			cur = cur.Elem()
+		}
+
+		switch cur.Kind() {
+		case reflect.Struct:
+			cur = cur.FieldByName(part)
+			if !cur.IsValid() {
+				return reflect.Value{}, false
+			}
+		case reflect.Slice, reflect.Array:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= cur.Len() {
+				return reflect.Value{}, false
+			}
+			cur = cur.Index(idx)
+		default:
+			return reflect.Value{}, false
+		}
+	}
+
+	return cur, true
+}
+
+// tokenize splits a filter expression into tokens, keeping quoted strings
+// and bracketed lists intact. "==" and "!=" are always their own token
+// even with no surrounding whitespace (e.g. `Status=="dead"`), matching
+// the no-space form the --filter flag's own help text advertises.
+func tokenize(expr string) []string {
+	var tokens []string
+	var buf strings.Builder
+	inQuote := false
+	depth := 0
+	runes := []rune(expr)
+
+	flush := func() {
+		if buf.Len() > 0 {
+			tokens = append(tokens, buf.String())
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+			buf.WriteRune(r)
+		case r == '[':
+			depth++
+			buf.WriteRune(r)
+		case r == ']':
+			depth--
+			buf.WriteRune(r)
+		case r == ' ' && depth == 0:
+			flush()
+		case (r == '=' || r == '!') && depth == 0 && i+1 < len(runes) && runes[i+1] == '=':
+			flush()
+			tokens = append(tokens, string(r)+"=")
+			i++
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (string, bool) {
+	tok, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return tok, ok
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, ok := p.peek()
+		if !ok || tok != "or" {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, ok := p.peek()
+		if !ok || tok != "and" {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseComparison() (node, error) {
+	field, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("expected field name")
+	}
+
+	op, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("expected operator after %q", field)
+	}
+	if op != "==" && op != "!=" && op != "in" && op != "contains" {
+		return nil, fmt.Errorf("unsupported operator %q", op)
+	}
+
+	raw, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("expected value after operator %q", op)
+	}
+
+	var values []string
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		for _, v := range strings.Split(strings.Trim(raw, "[]"), ",") {
+			values = append(values, strings.Trim(strings.TrimSpace(v), `"`))
+		}
+	} else {
+		values = []string{strings.Trim(raw, `"`)}
+	}
+
+	return comparison{field: field, op: op, value: values}, nil
+}
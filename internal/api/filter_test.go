@@ -0,0 +1,100 @@
+package api
+
+import "testing"
+
+func TestFilterMatchOperators(t *testing.T) {
+	job := Job{Name: "web", Type: "service", Status: "running"}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`Status == "running"`, true},
+		{`Status == "dead"`, false},
+		{`Status != "dead"`, true},
+		{`Type in ["batch", "service"]`, true},
+		{`Type in ["batch", "system"]`, false},
+		{`Name contains "eb"`, true},
+		{`Status == "running" and Type != "batch"`, true},
+		{`Status == "dead" or Type == "service"`, true},
+		{``, true},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) failed: %v", tt.expr, err)
+		}
+		if got := f.Match(job); got != tt.want {
+			t.Errorf("ParseFilter(%q).Match(job) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+// TestFilterNoSpaceOperators guards against a regression where the
+// tokenizer only split on spaces: the --filter flag's own --help text
+// advertises 'Status=="dead"' with no space around ==, so it must parse
+// the same as the spaced form.
+func TestFilterNoSpaceOperators(t *testing.T) {
+	job := Job{Status: "dead"}
+
+	for _, expr := range []string{
+		`Status=="dead"`,
+		`Status == "dead"`,
+		`Status!="running"`,
+		`Status != "running"`,
+	} {
+		f, err := ParseFilter(expr)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) failed: %v", expr, err)
+		}
+		if !f.Match(job) {
+			t.Errorf("ParseFilter(%q).Match(job) = false, want true", expr)
+		}
+	}
+}
+
+func TestFilterDottedFieldAccess(t *testing.T) {
+	job := Job{
+		Name:        "web",
+		Allocations: []Alloc{{ClientStatus: "running"}, {ClientStatus: "failed"}},
+	}
+
+	f, err := ParseFilter(`Allocations.0.ClientStatus == "running"`)
+	if err != nil {
+		t.Fatalf("ParseFilter failed: %v", err)
+	}
+	if !f.Match(job) {
+		t.Error("expected Allocations.0.ClientStatus == \"running\" to match")
+	}
+
+	f, err = ParseFilter(`Allocations.1.ClientStatus == "running"`)
+	if err != nil {
+		t.Fatalf("ParseFilter failed: %v", err)
+	}
+	if f.Match(job) {
+		t.Error("expected Allocations.1.ClientStatus == \"running\" not to match")
+	}
+}
+
+func TestFilterUnknownFieldDoesNotMatch(t *testing.T) {
+	f, err := ParseFilter(`NoSuchField == "x"`)
+	if err != nil {
+		t.Fatalf("ParseFilter failed: %v", err)
+	}
+	if f.Match(Job{}) {
+		t.Error("expected a comparison against an unresolvable field to not match")
+	}
+}
+
+func TestParseFilterRejectsBadExpressions(t *testing.T) {
+	for _, expr := range []string{
+		`Status`,
+		`Status ~ "dead"`,
+		`Status == "dead" extra`,
+	} {
+		if _, err := ParseFilter(expr); err == nil {
+			t.Errorf("ParseFilter(%q) expected an error, got none", expr)
+		}
+	}
+}
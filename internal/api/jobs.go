@@ -1,14 +1,31 @@
 package api
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
-// GetJobs returns all Nomad jobs with their allocations.
-func (c *Client) GetJobs() ([]Job, error) {
+// GetJobs returns all Nomad jobs with their allocations. If filter is
+// non-empty, it is parsed as a Filter expression (see ParseFilter) and only
+// matching jobs are returned.
+func (c *Client) GetJobs(filter string) ([]Job, error) {
+	return c.GetJobsWithOptions(ListOptions{Filter: filter})
+}
+
+// GetJobsWithOptions is GetJobs, additionally applying opts.Page/PerPage
+// pagination to the filtered results - see ListOptions.
+func (c *Client) GetJobsWithOptions(opts ListOptions) ([]Job, error) {
 	// Check if Nomad is healthy first
 	if c.getNomadStatus().Status != "healthy" {
 		return nil, nil
 	}
 
+	f, err := ParseFilter(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
 	var stubs []JobListStub
 	if err := c.get(c.nomadAddr+"/v1/jobs", &stubs); err != nil {
 		return nil, err
@@ -30,10 +47,47 @@ func (c *Client) GetJobs() ([]Job, error) {
 			job.Allocations = allocs
 		}
 
+		if !f.Match(job) {
+			continue
+		}
+
 		jobs = append(jobs, job)
 	}
 
-	return jobs, nil
+	start, end := pageBounds(len(jobs), opts.Page, opts.PerPage)
+	return jobs[start:end], nil
+}
+
+// GetAllocs returns allocations across all jobs, optionally narrowed by a
+// Filter expression (see ParseFilter) evaluated against each Alloc.
+func (c *Client) GetAllocs(filter string) ([]Alloc, error) {
+	return c.GetAllocsWithOptions(ListOptions{Filter: filter})
+}
+
+// GetAllocsWithOptions is GetAllocs, additionally applying opts.Page/PerPage
+// pagination to the filtered results - see ListOptions.
+func (c *Client) GetAllocsWithOptions(opts ListOptions) ([]Alloc, error) {
+	f, err := ParseFilter(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := c.GetJobs("")
+	if err != nil {
+		return nil, err
+	}
+
+	var allocs []Alloc
+	for _, job := range jobs {
+		for _, alloc := range job.Allocations {
+			if f.Match(alloc) {
+				allocs = append(allocs, alloc)
+			}
+		}
+	}
+
+	start, end := pageBounds(len(allocs), opts.Page, opts.PerPage)
+	return allocs[start:end], nil
 }
 
 func (c *Client) getJobAllocations(jobID string) ([]Alloc, error) {
@@ -57,13 +111,59 @@ func (c *Client) getJobAllocations(jobID string) ([]Alloc, error) {
 	return allocs, nil
 }
 
-// GetNodes returns all Nomad client nodes.
-func (c *Client) GetNodes() ([]Node, error) {
+// SubmitJob registers a job with Nomad. spec is the jobspec source text -
+// HCL unless isJSON is true, in which case spec is already the job's JSON
+// representation. HCL is canonicalized into JSON via Nomad's /v1/jobs/parse
+// endpoint first, since /v1/jobs only accepts JSON.
+func (c *Client) SubmitJob(spec string, isJSON bool) error {
+	job := json.RawMessage(spec)
+
+	if !isJSON {
+		if err := c.post(c.nomadAddr+"/v1/jobs/parse", map[string]interface{}{
+			"JobHCL":       spec,
+			"Canonicalize": true,
+		}, &job); err != nil {
+			return fmt.Errorf("failed to parse jobspec: %w", err)
+		}
+	}
+
+	return c.post(c.nomadAddr+"/v1/jobs", map[string]json.RawMessage{"Job": job}, nil)
+}
+
+// GetAllocDetail returns per-task state for a single allocation.
+func (c *Client) GetAllocDetail(allocID string) (AllocDetail, error) {
+	var detail AllocDetail
+	err := c.get(c.nomadAddr+"/v1/allocation/"+allocID, &detail)
+	return detail, err
+}
+
+// GetAllocStats returns per-task CPU/memory usage for a running allocation.
+func (c *Client) GetAllocStats(allocID string) (AllocResourceUsage, error) {
+	var usage AllocResourceUsage
+	err := c.get(c.nomadAddr+"/v1/client/allocation/"+allocID+"/stats", &usage)
+	return usage, err
+}
+
+// GetNodes returns all Nomad client nodes. If filter is non-empty, it is
+// parsed as a Filter expression (see ParseFilter) and only matching nodes
+// are returned.
+func (c *Client) GetNodes(filter string) ([]Node, error) {
+	return c.GetNodesWithOptions(ListOptions{Filter: filter})
+}
+
+// GetNodesWithOptions is GetNodes, additionally applying opts.Page/PerPage
+// pagination to the filtered results - see ListOptions.
+func (c *Client) GetNodesWithOptions(opts ListOptions) ([]Node, error) {
 	// Check if Nomad is healthy first
 	if c.getNomadStatus().Status != "healthy" {
 		return nil, nil
 	}
 
+	f, err := ParseFilter(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
 	var stubs []NodeListStub
 	if err := c.get(c.nomadAddr+"/v1/nodes", &stubs); err != nil {
 		return nil, err
@@ -71,7 +171,7 @@ func (c *Client) GetNodes() ([]Node, error) {
 
 	var nodes []Node
 	for _, stub := range stubs {
-		nodes = append(nodes, Node{
+		node := Node{
 			ID:         stub.ID,
 			Name:       stub.Name,
 			Address:    stub.Address,
@@ -79,8 +179,13 @@ func (c *Client) GetNodes() ([]Node, error) {
 			Datacenter: stub.Datacenter,
 			NodeClass:  stub.NodeClass,
 			Drain:      stub.Drain,
-		})
+		}
+
+		if f.Match(node) {
+			nodes = append(nodes, node)
+		}
 	}
 
-	return nodes, nil
+	start, end := pageBounds(len(nodes), opts.Page, opts.PerPage)
+	return nodes[start:end], nil
 }
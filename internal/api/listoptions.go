@@ -0,0 +1,70 @@
+package api
+
+import "reflect"
+
+// ListOptions narrows and shapes a Job/Alloc/Node/PlatformService listing.
+// A zero-value ListOptions matches and returns everything, unpaginated and
+// with every field - the same as passing filter == "" did before ListOptions
+// existed.
+type ListOptions struct {
+	// Filter is a Filter expression (see ParseFilter) evaluated against
+	// each result; empty matches everything.
+	Filter string
+
+	// Page and PerPage paginate the (post-filter) results, both 1-based.
+	// Either being non-positive disables pagination and returns every
+	// matching result.
+	Page    int
+	PerPage int
+
+	// Fields restricts a result to just these dotted field paths (see
+	// lookupField) when rendered with SelectFields; empty keeps every
+	// field.
+	Fields []string
+}
+
+// pageBounds returns the [start, end) slice bounds selecting page (1-based)
+// out of total items, perPage at a time - or the full [0, total) range if
+// page or perPage isn't positive, so a zero-value ListOptions still
+// returns everything.
+func pageBounds(total, page, perPage int) (start, end int) {
+	if page < 1 || perPage < 1 {
+		return 0, total
+	}
+	start = (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end = start + perPage
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// SelectFields maps each element of items (a slice of structs or pointers
+// to structs, e.g. []Job) to a map containing just the given dotted field
+// paths (see lookupField), for --fields output that doesn't need a caller
+// to jq a full Job/Node/Alloc down to the columns it actually wants. A
+// field that doesn't resolve on a given item is omitted from its map
+// rather than erroring, the same forgiving-by-default behavior Filter.Match
+// uses for a bad path. Returns nil if fields is empty.
+func SelectFields(items interface{}, fields []string) []map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	v := reflect.ValueOf(items)
+	rows := make([]map[string]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		row := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if fv, ok := lookupField(item, field); ok {
+				row[field] = fv.Interface()
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		total, page, perPage int
+		wantStart, wantEnd   int
+	}{
+		{total: 10, page: 0, perPage: 0, wantStart: 0, wantEnd: 10},
+		{total: 10, page: 1, perPage: 4, wantStart: 0, wantEnd: 4},
+		{total: 10, page: 2, perPage: 4, wantStart: 4, wantEnd: 8},
+		{total: 10, page: 3, perPage: 4, wantStart: 8, wantEnd: 10},
+		{total: 10, page: 4, perPage: 4, wantStart: 10, wantEnd: 10},
+	}
+
+	for _, tt := range tests {
+		start, end := pageBounds(tt.total, tt.page, tt.perPage)
+		if start != tt.wantStart || end != tt.wantEnd {
+			t.Errorf("pageBounds(%d, %d, %d) = (%d, %d), want (%d, %d)",
+				tt.total, tt.page, tt.perPage, start, end, tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	jobs := []Job{
+		{Name: "web", Status: "running"},
+		{Name: "worker", Status: "dead"},
+	}
+
+	rows := SelectFields(jobs, []string{"Name", "Status"})
+	want := []map[string]interface{}{
+		{"Name": "web", "Status": "running"},
+		{"Name": "worker", "Status": "dead"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("SelectFields = %#v, want %#v", rows, want)
+	}
+
+	if rows := SelectFields(jobs, nil); rows != nil {
+		t.Errorf("SelectFields with no fields = %#v, want nil", rows)
+	}
+}
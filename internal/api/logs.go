@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// logFrame is one frame of Nomad's client/fs/logs framed-stream response.
+type logFrame struct {
+	Data string `json:"Data"`
+}
+
+// allocLogReader unwraps Nomad's framed-stream log format into a plain
+// byte stream, decoding each frame's base64 Data payload as it arrives.
+type allocLogReader struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+	buf     []byte
+}
+
+func newAllocLogReader(body io.ReadCloser) *allocLogReader {
+	return &allocLogReader{body: body, decoder: json.NewDecoder(body)}
+}
+
+func (r *allocLogReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		var frame logFrame
+		if err := r.decoder.Decode(&frame); err != nil {
+			return 0, err
+		}
+		if frame.Data == "" {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(frame.Data)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode log frame: %w", err)
+		}
+		r.buf = decoded
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *allocLogReader) Close() error {
+	return r.body.Close()
+}
+
+// StreamAllocLogs proxies Nomad's client fs logs endpoint
+// (/v1/client/fs/logs/:alloc_id) for the given allocation/task, unwrapping
+// the base64-encoded Data frames into a plain byte stream. When follow is
+// true the returned reader keeps blocking for new output until the caller
+// closes it or the allocation's client closes the connection (e.g. because
+// the allocation restarted).
+func (c *Client) StreamAllocLogs(allocID, task string, follow bool) (io.ReadCloser, error) {
+	logType := "stdout"
+	url := fmt.Sprintf("%s/v1/client/fs/logs/%s?task=%s&type=%s&origin=start&offset=0&follow=%t",
+		c.nomadAddr, allocID, task, logType, follow)
+
+	// A follow stream is long-lived, so it can't share the client's default
+	// short request timeout.
+	streamClient := &http.Client{}
+	resp, err := streamClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log stream: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return newAllocLogReader(resp.Body), nil
+}
+
+// StreamAllocStderr is like StreamAllocLogs but reads the task's stderr
+// stream instead of stdout.
+func (c *Client) StreamAllocStderr(allocID, task string, follow bool) (io.ReadCloser, error) {
+	url := fmt.Sprintf("%s/v1/client/fs/logs/%s?task=%s&type=stderr&origin=start&offset=0&follow=%t",
+		c.nomadAddr, allocID, task, follow)
+
+	streamClient := &http.Client{}
+	resp, err := streamClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log stream: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return newAllocLogReader(resp.Body), nil
+}
+
+// TailAllocLogs returns roughly the last n lines of an allocation's task
+// log without following, by reading backward from the end of the file.
+// The returned bytes may include a partial leading line.
+func (c *Client) TailAllocLogs(allocID, task string, n int, stderr bool) ([]byte, error) {
+	logType := "stdout"
+	if stderr {
+		logType = "stderr"
+	}
+
+	// Nomad's logs endpoint offsets by bytes, not lines - approximate based
+	// on a generous average line length and trim to exactly n lines below.
+	const avgLineBytes = 256
+	offset := n * avgLineBytes
+
+	url := fmt.Sprintf("%s/v1/client/fs/logs/%s?task=%s&type=%s&origin=end&offset=%d&follow=false",
+		c.nomadAddr, allocID, task, logType, offset)
+
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch log tail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	reader := newAllocLogReader(resp.Body)
+	data, err := io.ReadAll(reader)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	if len(lines) > n+1 {
+		lines = lines[len(lines)-n-1:]
+	}
+
+	return bytes.Join(lines, []byte("\n")), nil
+}
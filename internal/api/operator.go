@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// NomadSnapshot downloads a point-in-time snapshot of Nomad's Raft state
+// from /v1/operator/snapshot, for internal/snapshot's SnapshotNomad.
+func (c *Client) NomadSnapshot() ([]byte, error) {
+	return c.getBytes(c.nomadAddr + "/v1/operator/snapshot")
+}
+
+// ConsulSnapshot downloads a point-in-time snapshot of Consul's Raft state
+// from /v1/snapshot, for internal/snapshot's SnapshotConsul. Consul isn't
+// deployed by any styx command today (see ConsulServerConfig in
+// internal/config), so this only succeeds against a cluster that runs one
+// out of band.
+func (c *Client) ConsulSnapshot() ([]byte, error) {
+	return c.getBytes(c.consulAddr + "/v1/snapshot")
+}
+
+// VaultSnapshot downloads a point-in-time snapshot of Vault's integrated
+// storage (Raft) from /v1/sys/storage/raft/snapshot, authenticating with
+// token, for internal/snapshot's SnapshotVault.
+func (c *Client) VaultSnapshot(token string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, c.vaultAddr+"/v1/sys/storage/raft/snapshot", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// drainSpec is the request body for /v1/node/:id/drain.
+type drainSpec struct {
+	DrainSpec *drainSpecDeadline `json:"DrainSpec"`
+}
+
+type drainSpecDeadline struct {
+	Deadline time.Duration
+}
+
+// DrainNode enables or disables drain mode on a Nomad client node via
+// /v1/node/:id/drain, so `styx upgrade` can move work off a client before
+// stopping it for the version bump. Disabling drain sends a nil DrainSpec,
+// matching how `nomad node drain -disable` clears it.
+func (c *Client) DrainNode(nodeID string, enable bool) error {
+	spec := drainSpec{}
+	if enable {
+		spec.DrainSpec = &drainSpecDeadline{Deadline: 5 * time.Minute}
+	}
+
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode drain spec: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/node/%s/drain", c.nomadAddr, nodeID)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ForceLeaveMember evicts node from the serf gossip pool via
+// /v1/agent/force-leave, for cluster.ReplaceNode: a server stuck in
+// "failed" never transitions to "left" on its own once it's gone for good,
+// and a "failed" member still counts toward quorum.
+func (c *Client) ForceLeaveMember(node string) error {
+	url := fmt.Sprintf("%s/v1/agent/force-leave?node=%s", c.nomadAddr, node)
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// RemoveRaftPeer removes address (host:port of the Raft RPC listener, e.g.
+// the member's Addr plus Nomad's default 4647) from the server raft
+// configuration via /v1/operator/raft/peer, for cluster.ReplaceNode. Unlike
+// ForceLeaveMember, this is what actually drops the member out of quorum
+// accounting - a force-left member still holds a raft seat until this runs.
+func (c *Client) RemoveRaftPeer(address string) error {
+	url := fmt.Sprintf("%s/v1/operator/raft/peer?address=%s", c.nomadAddr, address)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Variable mirrors a Nomad Variable: a path-addressed bag of key/value
+// secrets under /v1/var/, the backing store for `styx secret`.
+type Variable struct {
+	Namespace string            `json:"Namespace,omitempty"`
+	Path      string            `json:"Path"`
+	Items     map[string]string `json:"Items"`
+}
+
+// PutSecret creates or replaces the Nomad Variable at path with items.
+func (c *Client) PutSecret(path string, items map[string]string) error {
+	body, err := json.Marshal(Variable{Path: path, Items: items})
+	if err != nil {
+		return fmt.Errorf("failed to encode variable: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, c.nomadAddr+"/v1/var/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// GetSecret returns the items stored at path, or nil if no variable exists there.
+func (c *Client) GetSecret(path string) (map[string]string, error) {
+	resp, err := c.httpClient.Get(c.nomadAddr + "/v1/var/" + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var v Variable
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode variable: %w", err)
+	}
+	return v.Items, nil
+}
+
+// ListSecrets returns the paths of Nomad Variables under prefix ("" for all).
+func (c *Client) ListSecrets(prefix string) ([]string, error) {
+	url := c.nomadAddr + "/v1/vars"
+	if prefix != "" {
+		url += "?prefix=" + prefix
+	}
+
+	var vars []Variable
+	if err := c.get(url, &vars); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(vars))
+	for i, v := range vars {
+		paths[i] = v.Path
+	}
+	return paths, nil
+}
+
+// DeleteSecret removes the Nomad Variable at path.
+func (c *Client) DeleteSecret(path string) error {
+	req, err := http.NewRequest(http.MethodDelete, c.nomadAddr+"/v1/var/"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
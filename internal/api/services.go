@@ -1,17 +1,26 @@
 package api
 
 import (
+	"fmt"
+
 	"github.com/kessler-frost/styx/internal/network"
 	"github.com/kessler-frost/styx/internal/services"
 )
 
-// GetPlatformServices returns the status of all platform services.
-func (c *Client) GetPlatformServices() ([]PlatformService, error) {
+// GetPlatformServices returns the status of all platform services. If
+// filter is non-empty, it is parsed as a Filter expression (see
+// ParseFilter) and only matching services are returned.
+func (c *Client) GetPlatformServices(filter string) ([]PlatformService, error) {
 	// Check if Nomad is healthy first
 	if c.getNomadStatus().Status != "healthy" {
 		return nil, nil
 	}
 
+	f, err := ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
 	statuses, err := services.Status()
 	if err != nil {
 		return nil, err
@@ -43,13 +52,63 @@ func (c *Client) GetPlatformServices() ([]PlatformService, error) {
 			ps.Health = "unhealthy"
 		}
 
+		if !f.Match(ps) {
+			continue
+		}
+
 		result = append(result, ps)
 	}
 
 	return result, nil
 }
 
+// directServicePorts holds the scheme and container-native port for
+// services that aren't reverse-proxied through Traefik, so
+// directServiceEndpoint can address them by container IP on the Styx
+// subnet directly.
+var directServicePorts = map[string]struct {
+	scheme string
+	port   int
+}{
+	"nats":      {"nats", 4222},
+	"dragonfly": {"redis", 6379},
+	"loki":      {"http", 3100},
+	"promtail":  {"http", 9080},
+	"postgres":  {"postgres", 5432},
+	"rustfs":    {"http", 9000},
+}
+
+// directServiceEndpoint returns an endpoint addressing name's container IP
+// directly on the Styx subnet, if the subnet route (see
+// network.GetSubnetRouteStatus) is both advertised and approved and Nomad
+// has a registered address for it. Returns ok=false if any of that isn't
+// true yet, so callers fall back to localhost - the network.AdvertiseStyxSubnet
+// machinery that makes this work has to be explicitly enabled via
+// 'styx subnet enable' and approved by the tailnet admin first.
+func directServiceEndpoint(name string) (endpoint string, ok bool) {
+	svc, known := directServicePorts[name]
+	if !known {
+		return "", false
+	}
+
+	status, err := network.GetSubnetRouteStatus()
+	if err != nil || !status.Advertised || !status.Approved {
+		return "", false
+	}
+
+	addrs, err := services.DefaultClient().GetServiceAddresses(name)
+	if err != nil || len(addrs) == 0 {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s://%s:%d", svc.scheme, addrs[0], svc.port), true
+}
+
 func getServiceEndpoint(name string, tsInfo network.TailscaleInfo) string {
+	if endpoint, ok := directServiceEndpoint(name); ok {
+		return endpoint
+	}
+
 	if tsInfo.Running && tsInfo.DNSName != "" {
 		switch name {
 		case "traefik":
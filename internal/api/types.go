@@ -4,21 +4,26 @@ import "time"
 
 // ClusterStatus represents the overall cluster status.
 type ClusterStatus struct {
-	Service     string       `json:"service"`      // running, stopped
-	Vault       VaultStatus  `json:"vault"`        // Vault health status
-	Nomad       NomadStatus  `json:"nomad"`        // Nomad health status
-	Mode        string       `json:"mode"`         // server or client
-	NodeName    string       `json:"node_name"`    // Local node name
-	Datacenter  string       `json:"datacenter"`   // Datacenter name
-	Region      string       `json:"region"`       // Region name
-	Members     []Member     `json:"members"`      // Cluster members (servers only)
-	KnownServers string      `json:"known_servers"` // Connected servers (clients only)
+	Service       string      `json:"service"`        // running, stopped
+	Vault         VaultStatus `json:"vault"`          // Vault health status
+	Nomad         NomadStatus `json:"nomad"`          // Nomad health status
+	Mode          string      `json:"mode"`           // server or client
+	NodeName      string      `json:"node_name"`      // Local node name
+	Datacenter    string      `json:"datacenter"`     // Datacenter name
+	Region        string      `json:"region"`         // Region name
+	Members       []Member    `json:"members"`        // Cluster members (servers only)
+	KnownServers  string      `json:"known_servers"`  // Connected servers (clients only)
+	Upgrading     bool        `json:"upgrading"`      // Whether a `styx upgrade` run is in progress
+	TargetVersion string      `json:"target_version"` // Version being upgraded to, if Upgrading
 }
 
 // VaultStatus represents Vault health.
 type VaultStatus struct {
-	Status  string `json:"status"`  // healthy, sealed, not_responding
-	Mode    string `json:"mode"`    // active, standby, ""
+	Status         string `json:"status"`          // healthy, sealed, not_responding
+	Mode           string `json:"mode"`            // active, standby, ""
+	Initialized    bool   `json:"initialized"`     // whether `vault operator init` has run
+	SealedKeys     int    `json:"sealed_keys"`     // total unseal key shares configured
+	UnsealProgress int    `json:"unseal_progress"` // key shares submitted so far while sealed
 }
 
 // NomadStatus represents Nomad health.
@@ -38,37 +43,96 @@ type Member struct {
 // PlatformService represents a platform service (Traefik, Grafana, etc).
 type PlatformService struct {
 	Name     string `json:"name"`
-	Status   string `json:"status"`    // running, stopped, pending
-	Endpoint string `json:"endpoint"`  // URL to access the service
-	Health   string `json:"health"`    // healthy, unhealthy, unknown
+	Status   string `json:"status"`   // running, stopped, pending
+	Endpoint string `json:"endpoint"` // URL to access the service
+	Health   string `json:"health"`   // healthy, unhealthy, unknown
 }
 
 // Job represents a Nomad job.
 type Job struct {
-	ID          string      `json:"id"`
-	Name        string      `json:"name"`
-	Type        string      `json:"type"`        // service, batch, system
-	Status      string      `json:"status"`      // running, pending, dead
-	Allocations []Alloc     `json:"allocations"`
-	SubmitTime  time.Time   `json:"submit_time"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`   // service, batch, system
+	Status      string    `json:"status"` // running, pending, dead
+	Allocations []Alloc   `json:"allocations"`
+	SubmitTime  time.Time `json:"submit_time"`
 }
 
 // Alloc represents a Nomad allocation.
 type Alloc struct {
-	ID           string `json:"id"`
-	NodeID       string `json:"node_id"`
-	NodeName     string `json:"node_name"`
-	TaskGroup    string `json:"task_group"`
-	ClientStatus string `json:"client_status"` // running, pending, complete, failed
+	ID            string `json:"id"`
+	NodeID        string `json:"node_id"`
+	NodeName      string `json:"node_name"`
+	TaskGroup     string `json:"task_group"`
+	ClientStatus  string `json:"client_status"` // running, pending, complete, failed
 	DesiredStatus string `json:"desired_status"`
 }
 
+// AllocDetail is the response from /v1/allocation/:id: per-task state for a
+// single allocation, used by the TUI's job detail view.
+type AllocDetail struct {
+	ID         string               `json:"ID"`
+	TaskGroup  string               `json:"TaskGroup"`
+	TaskStates map[string]TaskState `json:"TaskStates"`
+}
+
+// TaskState is one task's entry in AllocDetail.TaskStates.
+type TaskState struct {
+	State     string      `json:"State"` // pending, running, dead
+	StartedAt time.Time   `json:"StartedAt"`
+	Events    []TaskEvent `json:"Events"`
+}
+
+// TaskEvent is one entry in TaskState.Events - a single lifecycle
+// transition or driver event Nomad recorded for a task, in chronological
+// order. DisplayMessage is the human-readable summary Nomad assembles from
+// the event's Type and its other fields.
+type TaskEvent struct {
+	Type           string `json:"Type"`
+	Time           int64  `json:"Time"` // unix nanoseconds
+	DisplayMessage string `json:"DisplayMessage"`
+}
+
+// AllocResourceUsage is the response from
+// /v1/client/allocation/:id/stats: per-task CPU/memory usage for a running
+// allocation.
+type AllocResourceUsage struct {
+	Tasks map[string]TaskResourceUsage `json:"Tasks"`
+}
+
+// TaskResourceUsage is one task's entry in AllocResourceUsage.Tasks.
+type TaskResourceUsage struct {
+	ResourceUsage struct {
+		CpuStats struct {
+			Percent float64 `json:"Percent"`
+		} `json:"CpuStats"`
+		MemoryStats struct {
+			RSS uint64 `json:"RSS"`
+		} `json:"MemoryStats"`
+	} `json:"ResourceUsage"`
+}
+
+// Alert represents one active or resolved alert as reported by
+// Alertmanager's /api/v2/alerts.
+type Alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Status      AlertStatus       `json:"status"`
+}
+
+// AlertStatus describes an Alert's current processing state.
+type AlertStatus struct {
+	State string `json:"state"` // active, suppressed, unprocessed
+}
+
 // Node represents a Nomad client node.
 type Node struct {
 	ID         string `json:"id"`
 	Name       string `json:"name"`
 	Address    string `json:"address"`
-	Status     string `json:"status"`      // ready, down
+	Status     string `json:"status"` // ready, down
 	Datacenter string `json:"datacenter"`
 	NodeClass  string `json:"node_class"`
 	Drain      bool   `json:"drain"`
@@ -113,11 +177,11 @@ type AgentMembers struct {
 
 // JobListStub is the response from /v1/jobs.
 type JobListStub struct {
-	ID          string `json:"ID"`
-	Name        string `json:"Name"`
-	Type        string `json:"Type"`
-	Status      string `json:"Status"`
-	SubmitTime  int64  `json:"SubmitTime"` // nanoseconds
+	ID         string `json:"ID"`
+	Name       string `json:"Name"`
+	Type       string `json:"Type"`
+	Status     string `json:"Status"`
+	SubmitTime int64  `json:"SubmitTime"` // nanoseconds
 }
 
 // AllocListStub is the response from /v1/job/:id/allocations.
@@ -0,0 +1,104 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"tailscale.com/client/local"
+)
+
+// lc is the LocalAPI client used to resolve caller identity via WhoIs. Its
+// zero value dials tailscaled over the platform's default local socket,
+// the same convention internal/network's own lc follows.
+var lc local.Client
+
+// Identity is the tailnet identity WhoIs resolved a request's source
+// address to.
+type Identity struct {
+	Login string // tailnet login name (e.g. "alice@github"); empty for tagged nodes
+	Node  string // node name (e.g. "ci-runner")
+}
+
+// key is the string Policy grants are keyed by: the login name for a user,
+// falling back to the node name for a tagged device that has no login.
+func (id Identity) key() string {
+	if id.Login != "" {
+		return id.Login
+	}
+	return id.Node
+}
+
+// ResolveIdentity asks the LocalAPI to resolve remoteAddr (a "host:port",
+// as seen on http.Request.RemoteAddr) to the tailnet identity of whichever
+// peer it belongs to. It returns an error if remoteAddr isn't a Tailscale
+// peer address at all - e.g. a plain loopback TCP connection that never
+// touched tailscale0 - and callers must treat that as "no identity could
+// be resolved," not "resolved to an empty identity."
+func ResolveIdentity(ctx context.Context, remoteAddr string) (Identity, error) {
+	who, err := lc.WhoIs(ctx, remoteAddr)
+	if err != nil {
+		return Identity{}, fmt.Errorf("failed to resolve %s via WhoIs: %w", remoteAddr, err)
+	}
+	if who.Node == nil {
+		return Identity{}, fmt.Errorf("WhoIs(%s) returned no node", remoteAddr)
+	}
+
+	id := Identity{Node: who.Node.ComputedName}
+	if who.UserProfile != nil {
+		id.Login = who.UserProfile.LoginName
+	}
+	return id, nil
+}
+
+// unixSocketKey marks a request context as having arrived over a trusted
+// local UNIX domain socket listener.
+type unixSocketKey struct{}
+
+// WithUnixSocket marks ctx as having arrived over a trusted local UNIX
+// socket listener, so RequireCapability grants it access outright instead
+// of trying (and failing) to WhoIs a local socket peer that was never a
+// Tailscale connection in the first place. A server that listens on both a
+// UNIX socket and a Tailscale-reachable TCP address should set this via
+// its UNIX listener's net.Conn -> context plumbing (e.g.
+// http.Server.ConnContext), not for TCP connections generally - deny by
+// default otherwise, per RequireCapability's doc comment.
+func WithUnixSocket(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unixSocketKey{}, true)
+}
+
+func isUnixSocket(ctx context.Context) bool {
+	v, _ := ctx.Value(unixSocketKey{}).(bool)
+	return v
+}
+
+// RequireCapability wraps next so only a caller whose resolved identity
+// holds at least cap in policy may reach it. A caller that arrived over a
+// trusted local UNIX socket (see WithUnixSocket) is always let through,
+// the same trust a local `styx` CLI invocation already has by virtue of
+// running on the machine. Every other caller must resolve to a Tailscale
+// identity via WhoIs and hold cap - deny by default, including plain
+// loopback TCP connections that didn't arrive over tailscale0, since those
+// aren't WhoIs-resolvable and letting them through would reopen the hole
+// this middleware exists to close.
+func RequireCapability(policy *Policy, cap Capability, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isUnixSocket(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id, err := ResolveIdentity(r.Context(), r.RemoteAddr)
+		if err != nil {
+			http.Error(w, "access denied: could not resolve a Tailscale identity for this connection", http.StatusForbidden)
+			return
+		}
+
+		if !policy.Has(id.key(), cap) {
+			http.Error(w, fmt.Sprintf("access denied: %s lacks capability %s", id.key(), cap), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
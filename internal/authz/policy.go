@@ -0,0 +1,235 @@
+// Package authz resolves a caller's Tailscale identity via the LocalAPI
+// WhoIs endpoint and authorizes it against a small on-disk policy file
+// mapping identities to capabilities - the layer of caller authentication
+// Nomad's own HTTP API (bound on 0.0.0.0, reachable from any tailnet peer -
+// see config.ServerConfigTemplate) doesn't have on its own.
+package authz
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Capability is a permission a policy grants to an identity. Named like a
+// Tailscale ACL grant capability (see the tailnet policy file's "grant"
+// section) so the two read the same way side by side, even though Styx's
+// own policy file is the one actually consulted here.
+type Capability string
+
+const (
+	// CapRead lets an identity call read-only endpoints (job/service
+	// status, logs).
+	CapRead Capability = "styx.kessler-frost.dev/cap/read"
+	// CapDeploy lets an identity submit and stop jobs, on top of
+	// everything CapRead allows.
+	CapDeploy Capability = "styx.kessler-frost.dev/cap/deploy"
+	// CapAdmin lets an identity perform node-level operations (region
+	// add/remove, force-leave), on top of everything CapDeploy allows.
+	CapAdmin Capability = "styx.kessler-frost.dev/cap/admin"
+)
+
+// rank orders capabilities from least to most privileged, so Has treats a
+// higher grant as implicitly covering a lower request - an identity with
+// CapAdmin shouldn't also need a separate CapDeploy grant just to run a job.
+var rank = map[Capability]int{
+	CapRead:   1,
+	CapDeploy: 2,
+	CapAdmin:  3,
+}
+
+// PolicyEntry is one identity's capability grants, as declared in a policy
+// file - exported so operators can list the effective policy (see
+// api.Client.GetAuthzPolicy and `styx authz status`).
+type PolicyEntry struct {
+	Identity     string
+	Capabilities []Capability
+}
+
+// PolicyPath is where LoadPolicy reads the authz policy file from by
+// default. cmd/styx/root.go overrides it to configDir/authz.hcl once
+// --config-dir is known.
+var PolicyPath = "authz.hcl"
+
+// Policy maps tailnet identities (a WhoIs login name, or a tagged node's
+// name when it has no login) to the capabilities they hold.
+type Policy struct {
+	grants map[string][]Capability
+	order  []string
+}
+
+// Has reports whether identity holds at least cap, per rank - a grant for a
+// higher capability also satisfies a request for a lower one. An identity
+// with no entry in the policy at all has no capabilities.
+func (p *Policy) Has(identity string, cap Capability) bool {
+	if p == nil {
+		return false
+	}
+	want, ok := rank[cap]
+	if !ok {
+		return false
+	}
+	for _, got := range p.grants[identity] {
+		if r, ok := rank[got]; ok && r >= want {
+			return true
+		}
+	}
+	return false
+}
+
+// Entries returns the policy's grants in file order, for display.
+func (p *Policy) Entries() []PolicyEntry {
+	if p == nil {
+		return nil
+	}
+	entries := make([]PolicyEntry, 0, len(p.order))
+	for _, identity := range p.order {
+		entries = append(entries, PolicyEntry{Identity: identity, Capabilities: p.grants[identity]})
+	}
+	return entries
+}
+
+// LoadPolicy reads a policy file (conventionally PolicyPath). A missing
+// file is not an error: it returns an empty Policy, under which every
+// identity has no capabilities - deny-by-default, same as if Styx had no
+// opinion on who's allowed to do what yet.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := readFileOrEmpty(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if data == "" {
+		return &Policy{grants: map[string][]Capability{}}, nil
+	}
+
+	policy, err := parsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// parsePolicy reads a small subset of HCL, the same hand-rolled style
+// internal/intents uses for its own one-block-type file:
+//
+//	principal {
+//	  identity     = "alice@github"
+//	  capabilities = "admin"
+//	}
+//
+//	principal {
+//	  identity     = "ci-bot@github"
+//	  capabilities = "deploy,read"
+//	}
+func parsePolicy(data string) (*Policy, error) {
+	policy := &Policy{grants: map[string][]Capability{}}
+
+	var identity, capsField string
+	inBlock := false
+
+	for i, raw := range strings.Split(data, "\n") {
+		line := strings.TrimSpace(raw)
+		lineNo := i + 1
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case line == "principal {":
+			if inBlock {
+				return nil, fmt.Errorf("line %d: nested principal block", lineNo)
+			}
+			inBlock = true
+			identity, capsField = "", ""
+		case line == "}":
+			if !inBlock {
+				return nil, fmt.Errorf("line %d: unexpected closing brace", lineNo)
+			}
+			if identity == "" {
+				return nil, fmt.Errorf("line %d: principal block missing required field %q", lineNo, "identity")
+			}
+			caps, err := parseCapabilities(capsField)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			if _, exists := policy.grants[identity]; !exists {
+				policy.order = append(policy.order, identity)
+			}
+			policy.grants[identity] = append(policy.grants[identity], caps...)
+			inBlock = false
+		default:
+			if !inBlock {
+				return nil, fmt.Errorf("line %d: expected 'principal {', got %q", lineNo, line)
+			}
+			key, value, err := parsePolicyField(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			switch key {
+			case "identity":
+				identity = value
+			case "capabilities":
+				capsField = value
+			default:
+				return nil, fmt.Errorf("line %d: unknown field %q", lineNo, key)
+			}
+		}
+	}
+
+	if inBlock {
+		return nil, fmt.Errorf("unterminated principal block")
+	}
+
+	return policy, nil
+}
+
+func parsePolicyField(line string) (key, value string, err error) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("expected 'field = \"value\"', got %q", line)
+	}
+
+	key = strings.TrimSpace(parts[0])
+	value = strings.TrimSpace(parts[1])
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return "", "", fmt.Errorf("expected a quoted string value, got %q", value)
+	}
+	return key, value[1 : len(value)-1], nil
+}
+
+// readFileOrEmpty reads path, returning "" instead of an error if it
+// doesn't exist yet.
+func readFileOrEmpty(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseCapabilities expands a comma-separated "admin,deploy" field into
+// Capability constants, accepting either the short name (as written in the
+// policy file) or the full styx.kessler-frost.dev/cap/* form.
+func parseCapabilities(field string) ([]Capability, error) {
+	if field == "" {
+		return nil, fmt.Errorf("principal block missing required field %q", "capabilities")
+	}
+
+	var caps []Capability
+	for _, name := range strings.Split(field, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "read", string(CapRead):
+			caps = append(caps, CapRead)
+		case "deploy", string(CapDeploy):
+			caps = append(caps, CapDeploy)
+		case "admin", string(CapAdmin):
+			caps = append(caps, CapAdmin)
+		default:
+			return nil, fmt.Errorf("unknown capability %q", name)
+		}
+	}
+	return caps, nil
+}
@@ -0,0 +1,121 @@
+package authz
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePolicyGrantsAndRanking(t *testing.T) {
+	policy, err := parsePolicy(`
+principal {
+  identity     = "alice@github"
+  capabilities = "admin"
+}
+
+principal {
+  identity     = "ci-bot@github"
+  capabilities = "deploy,read"
+}
+`)
+	if err != nil {
+		t.Fatalf("parsePolicy failed: %v", err)
+	}
+
+	// admin implies deploy and read, per rank.
+	if !policy.Has("alice@github", CapAdmin) {
+		t.Error("expected alice@github to have CapAdmin")
+	}
+	if !policy.Has("alice@github", CapDeploy) {
+		t.Error("expected CapAdmin to imply CapDeploy")
+	}
+	if !policy.Has("alice@github", CapRead) {
+		t.Error("expected CapAdmin to imply CapRead")
+	}
+
+	if !policy.Has("ci-bot@github", CapDeploy) {
+		t.Error("expected ci-bot@github to have CapDeploy")
+	}
+	if policy.Has("ci-bot@github", CapAdmin) {
+		t.Error("expected ci-bot@github's deploy grant not to imply CapAdmin")
+	}
+
+	if policy.Has("nobody@github", CapRead) {
+		t.Error("expected an identity with no entry to have no capabilities")
+	}
+}
+
+func TestParsePolicyAcceptsFullCapabilityNames(t *testing.T) {
+	policy, err := parsePolicy(`
+principal {
+  identity     = "svc@github"
+  capabilities = "styx.kessler-frost.dev/cap/deploy"
+}
+`)
+	if err != nil {
+		t.Fatalf("parsePolicy failed: %v", err)
+	}
+	if !policy.Has("svc@github", CapDeploy) {
+		t.Error("expected the full capability name form to parse")
+	}
+}
+
+func TestParsePolicyRejectsMalformedInput(t *testing.T) {
+	tests := []string{
+		`principal {
+  capabilities = "read"
+}`, // missing identity
+		`principal {
+  identity     = "alice@github"
+}`, // missing capabilities
+		`principal {
+  identity     = "alice@github"
+  capabilities = "superuser"
+}`, // unknown capability
+		`principal {
+  identity = "alice@github"
+`, // unterminated block
+		`identity = "alice@github"`, // field outside any block
+		`principal {
+  principal {
+  }
+}`, // nested block
+	}
+
+	for i, data := range tests {
+		if _, err := parsePolicy(data); err == nil {
+			t.Errorf("case %d: expected parsePolicy to reject %q, got no error", i, data)
+		}
+	}
+}
+
+func TestLoadPolicyMissingFileIsEmptyNotError(t *testing.T) {
+	policy, err := LoadPolicy(filepath.Join(t.TempDir(), "does-not-exist.hcl"))
+	if err != nil {
+		t.Fatalf("LoadPolicy on a missing file returned an error: %v", err)
+	}
+	if policy.Has("anyone@github", CapRead) {
+		t.Error("expected an empty policy to grant no capabilities")
+	}
+}
+
+func TestEntriesPreservesFileOrder(t *testing.T) {
+	policy, err := parsePolicy(`
+principal {
+  identity     = "zeta@github"
+  capabilities = "read"
+}
+
+principal {
+  identity     = "alpha@github"
+  capabilities = "read"
+}
+`)
+	if err != nil {
+		t.Fatalf("parsePolicy failed: %v", err)
+	}
+
+	entries := policy.Entries()
+	if len(entries) != 2 || entries[0].Identity != "zeta@github" || entries[1].Identity != "alpha@github" {
+		t.Errorf("Entries() = %+v, want file order [zeta@github, alpha@github]", entries)
+	}
+}
@@ -1,18 +1,33 @@
 package bootstrap
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/kessler-frost/styx/internal/jointoken"
+	styxtls "github.com/kessler-frost/styx/internal/tls"
 )
 
-// FetchBootstrapFiles fetches all certificates and keys from a server's bootstrap endpoint.
-func FetchBootstrapFiles(serverIP, certsDir, secretsDir string) error {
-	client := &http.Client{Timeout: 10 * time.Second}
-	baseURL := fmt.Sprintf("http://%s:%d", serverIP, Port)
+// FetchBootstrapFiles fetches all certificates and keys from a server's
+// bootstrap endpoint, authenticating with token (see internal/jointoken -
+// minted by `styx token create` and required by every cert/key endpoint).
+// The connection is pinned to the certificate fingerprint embedded in
+// token's claims (see jointoken.Peek) instead of being verified against a
+// CA chain, since the bootstrap server's HTTPS cert is self-signed and has
+// no chain to verify.
+func FetchBootstrapFiles(serverIP, certsDir, secretsDir, token string) error {
+	client, err := pinnedClient(token)
+	if err != nil {
+		return fmt.Errorf("failed to set up pinned bootstrap client: %w", err)
+	}
+	baseURL := fmt.Sprintf("https://%s:%d", serverIP, Port)
 
 	// Files to fetch: path -> (local filename, permissions)
 	certFiles := []struct {
@@ -29,7 +44,7 @@ func FetchBootstrapFiles(serverIP, certsDir, secretsDir string) error {
 	}
 
 	for _, f := range certFiles {
-		data, err := fetchFile(client, baseURL+f.endpoint)
+		data, err := fetchFile(client, baseURL+f.endpoint, token)
 		if err != nil {
 			return fmt.Errorf("failed to fetch %s: %w", f.endpoint, err)
 		}
@@ -40,7 +55,7 @@ func FetchBootstrapFiles(serverIP, certsDir, secretsDir string) error {
 	}
 
 	// Fetch and save gossip key
-	gossipData, err := fetchFile(client, baseURL+"/bootstrap/gossip.key")
+	gossipData, err := fetchFile(client, baseURL+"/bootstrap/gossip.key", token)
 	if err != nil {
 		return fmt.Errorf("failed to fetch gossip key: %w", err)
 	}
@@ -53,10 +68,71 @@ func FetchBootstrapFiles(serverIP, certsDir, secretsDir string) error {
 	return nil
 }
 
+// FetchRegionCA fetches a remote region's Nomad CA from serverIP's
+// bootstrap server and stores it under certsDir, so this node can verify
+// that region's servers/clients once the two regions' serf pools are
+// joined - the "pull" half of the CA exchange `styx region add` performs.
+func FetchRegionCA(serverIP, region, certsDir, token string) error {
+	client, err := pinnedClient(token)
+	if err != nil {
+		return fmt.Errorf("failed to set up pinned bootstrap client: %w", err)
+	}
+
+	url := fmt.Sprintf("https://%s:%d/bootstrap/region-ca/%s", serverIP, Port, region)
+	data, err := fetchFile(client, url, token)
+	if err != nil {
+		return fmt.Errorf("failed to fetch region CA for %s: %w", region, err)
+	}
+	return styxtls.StoreRegionCA(certsDir, region, data)
+}
+
+// PushRegionCA uploads region's own CA (generated by
+// internal/tls.GenerateRegionCA) to serverIP's bootstrap server, the "push"
+// half of the CA exchange FetchRegionCA completes the other side of.
+func PushRegionCA(serverIP, region, certsDir, token string) error {
+	client, err := pinnedClient(token)
+	if err != nil {
+		return fmt.Errorf("failed to set up pinned bootstrap client: %w", err)
+	}
+
+	path, err := styxtls.RegionCAFile(certsDir, region)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read region CA for %s: %w", region, err)
+	}
+
+	url := fmt.Sprintf("https://%s:%d/bootstrap/region-ca/%s", serverIP, Port, region)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push region CA for %s: %w", region, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // CheckBootstrapServer checks if a bootstrap server is running at the given IP.
+// It doesn't pin the certificate fingerprint: there's no token in hand yet at
+// this point, and the health endpoint carries no secrets, so this is purely
+// an existence probe, not a trust decision.
 func CheckBootstrapServer(serverIP string) bool {
-	client := &http.Client{Timeout: 3 * time.Second}
-	url := fmt.Sprintf("http://%s:%d/bootstrap/health", serverIP, Port)
+	client := &http.Client{
+		Timeout:   3 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	url := fmt.Sprintf("https://%s:%d/bootstrap/health", serverIP, Port)
 
 	resp, err := client.Get(url)
 	if err != nil {
@@ -67,8 +143,44 @@ func CheckBootstrapServer(serverIP string) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-func fetchFile(client *http.Client, url string) ([]byte, error) {
-	resp, err := client.Get(url)
+// pinnedClient returns an http.Client that accepts only a TLS leaf
+// certificate whose SHA-256 fingerprint matches the one embedded in
+// token's claims, in place of normal CA-chain verification.
+func pinnedClient(token string) (*http.Client, error) {
+	claims, err := jointoken.Peek(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claims from token: %w", err)
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+				VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+					if len(rawCerts) == 0 {
+						return fmt.Errorf("server presented no certificate")
+					}
+					if got := FingerprintCert(rawCerts[0]); got != claims.CertFingerprint {
+						return fmt.Errorf("server certificate fingerprint %s does not match token's pinned fingerprint %s", got, claims.CertFingerprint)
+					}
+					return nil
+				},
+			},
+		},
+	}, nil
+}
+
+func fetchFile(client *http.Client, url, token string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
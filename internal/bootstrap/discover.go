@@ -0,0 +1,109 @@
+package bootstrap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/network"
+)
+
+// BootstrapHostnamePrefix is the naming convention bootstrap servers should
+// advertise under on Tailscale (e.g. a machine named "styx-bootstrap-mini"),
+// so clients can recognize one without already knowing its IP.
+const BootstrapHostnamePrefix = "styx-bootstrap-"
+
+// ServerCandidate is a discovered bootstrap server, ranked by how quickly it
+// answered a /bootstrap/health probe.
+type ServerCandidate struct {
+	IP   string
+	Name string
+	RTT  time.Duration
+}
+
+// DiscoverServers finds candidate bootstrap servers by Tailscale hostname
+// convention and LAN mDNS, probes each candidate's /bootstrap/health
+// endpoint, and returns only the ones that responded, fastest first. It
+// returns a nil slice (not an error) when nothing answers - callers should
+// fall back to prompting the user for an IP.
+func DiscoverServers(ctx context.Context) ([]ServerCandidate, error) {
+	candidates := map[string]string{} // ip -> name
+
+	for _, peer := range network.GetTailscalePeers() {
+		if strings.HasPrefix(peer.Hostname, BootstrapHostnamePrefix) {
+			candidates[peer.IP] = peer.Hostname
+		}
+	}
+
+	// mDNS is a best-effort secondary channel - a network without multicast
+	// support shouldn't prevent Tailscale-based discovery from working.
+	if hosts, err := queryMDNS(ctx); err == nil {
+		for _, h := range hosts {
+			if _, ok := candidates[h.IP]; !ok {
+				candidates[h.IP] = h.Name
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	return probeCandidates(ctx, candidates), nil
+}
+
+// probeCandidates checks /bootstrap/health on every candidate concurrently
+// and ranks the ones that respond by round-trip time.
+func probeCandidates(ctx context.Context, candidates map[string]string) []ServerCandidate {
+	var (
+		mu      sync.Mutex
+		results []ServerCandidate
+		wg      sync.WaitGroup
+	)
+
+	// The bootstrap server only serves HTTPS with a self-signed cert and
+	// there's no token to pin against yet, so, like CheckBootstrapServer,
+	// this is an existence probe rather than a trust decision.
+	client := &http.Client{
+		Timeout:   2 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+
+	for ip, name := range candidates {
+		wg.Add(1)
+		go func(ip, name string) {
+			defer wg.Done()
+
+			start := time.Now()
+			url := fmt.Sprintf("https://%s:%d/bootstrap/health", ip, Port)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			mu.Lock()
+			results = append(results, ServerCandidate{IP: ip, Name: name, RTT: time.Since(start)})
+			mu.Unlock()
+		}(ip, name)
+	}
+
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].RTT < results[j].RTT })
+	return results
+}
@@ -0,0 +1,205 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// mdnsServiceName is the LAN mDNS service bootstrap servers are expected
+	// to answer PTR queries for.
+	mdnsServiceName = "_styx-bootstrap._tcp.local."
+	mdnsGroupAddr   = "224.0.0.251:5353"
+	mdnsListenWait  = 1500 * time.Millisecond
+)
+
+// mdnsHost is a bootstrap server discovered by LAN mDNS.
+type mdnsHost struct {
+	IP   string
+	Name string
+}
+
+// queryMDNS sends a PTR query for mdnsServiceName over LAN multicast DNS
+// and collects the A records of any servers that answer within
+// mdnsListenWait.
+func queryMDNS(ctx context.Context) ([]mdnsHost, error) {
+	groupAddr, err := net.ResolveUDPAddr("udp4", mdnsGroupAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mDNS socket: %w", err)
+	}
+	defer conn.Close()
+
+	query, err := encodePTRQuery(mdnsServiceName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(query, groupAddr); err != nil {
+		return nil, fmt.Errorf("failed to send mDNS query: %w", err)
+	}
+
+	deadline := time.Now().Add(mdnsListenWait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetReadDeadline(deadline)
+
+	var hosts []mdnsHost
+	buf := make([]byte, 4096)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break // deadline reached or socket closed
+		}
+
+		for _, rr := range decodeResourceRecords(buf[:n]) {
+			if rr.ip != "" {
+				hosts = append(hosts, mdnsHost{IP: rr.ip, Name: rr.name})
+			}
+		}
+	}
+
+	return hosts, nil
+}
+
+// encodePTRQuery builds a raw DNS message asking for PTR records for name.
+func encodePTRQuery(name string) ([]byte, error) {
+	buf := make([]byte, 0, 64)
+
+	// Header: ID=0, flags=0 (standard query), QDCOUNT=1, rest 0.
+	buf = append(buf, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0)
+
+	encoded, err := encodeDNSName(name)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, encoded...)
+
+	// QTYPE=PTR(12), QCLASS=IN(1).
+	buf = append(buf, 0, 12, 0, 1)
+	return buf, nil
+}
+
+// encodeDNSName encodes a dotted domain name into DNS label format.
+func encodeDNSName(name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+
+	var buf []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) > 63 {
+				return nil, fmt.Errorf("dns label too long: %s", label)
+			}
+			buf = append(buf, byte(len(label)))
+			buf = append(buf, label...)
+		}
+	}
+	buf = append(buf, 0)
+	return buf, nil
+}
+
+// mdnsRR is a decoded resource record we care about: an A record's owner
+// name and IPv4 address.
+type mdnsRR struct {
+	name string
+	ip   string
+}
+
+// decodeResourceRecords parses a raw mDNS response message and returns the
+// A records it contains.
+func decodeResourceRecords(msg []byte) []mdnsRR {
+	if len(msg) < 12 {
+		return nil
+	}
+
+	qdcount := int(binary.BigEndian.Uint16(msg[4:6]))
+	ancount := int(binary.BigEndian.Uint16(msg[6:8]))
+	nscount := int(binary.BigEndian.Uint16(msg[8:10]))
+	arcount := int(binary.BigEndian.Uint16(msg[10:12]))
+
+	offset := 12
+	for i := 0; i < qdcount; i++ {
+		_, next, err := readDNSName(msg, offset)
+		if err != nil || next+4 > len(msg) {
+			return nil
+		}
+		offset = next + 4 // QTYPE + QCLASS
+	}
+
+	var records []mdnsRR
+	for i := 0; i < ancount+nscount+arcount; i++ {
+		name, next, err := readDNSName(msg, offset)
+		if err != nil || next+10 > len(msg) {
+			return records
+		}
+		offset = next
+
+		rrType := binary.BigEndian.Uint16(msg[offset : offset+2])
+		rdlength := int(binary.BigEndian.Uint16(msg[offset+8 : offset+10]))
+		offset += 10
+
+		if offset+rdlength > len(msg) {
+			return records
+		}
+		rdata := msg[offset : offset+rdlength]
+		offset += rdlength
+
+		if rrType == 1 && rdlength == 4 { // A record
+			records = append(records, mdnsRR{name: name, ip: net.IP(rdata).String()})
+		}
+	}
+
+	return records
+}
+
+// readDNSName decodes a (possibly compressed) DNS name starting at offset,
+// returning the decoded name and the offset immediately after it in the
+// original message.
+func readDNSName(msg []byte, offset int) (string, int, error) {
+	var labels []string
+	jumpedFrom := -1
+	pos := offset
+
+	for {
+		if pos >= len(msg) {
+			return "", 0, fmt.Errorf("dns name out of bounds")
+		}
+
+		length := int(msg[pos])
+		if length == 0 {
+			pos++
+			break
+		}
+
+		if length&0xC0 == 0xC0 { // compression pointer
+			if pos+1 >= len(msg) {
+				return "", 0, fmt.Errorf("truncated dns pointer")
+			}
+			if jumpedFrom == -1 {
+				jumpedFrom = pos + 2
+			}
+			pos = int(binary.BigEndian.Uint16(msg[pos:pos+2]) & 0x3FFF)
+			continue
+		}
+
+		if pos+1+length > len(msg) {
+			return "", 0, fmt.Errorf("truncated dns label")
+		}
+		labels = append(labels, string(msg[pos+1:pos+1+length]))
+		pos += 1 + length
+	}
+
+	if jumpedFrom != -1 {
+		pos = jumpedFrom
+	}
+
+	return strings.Join(labels, "."), pos, nil
+}
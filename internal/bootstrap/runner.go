@@ -0,0 +1,96 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kessler-frost/styx/internal/shutdown"
+)
+
+// Runner executes a sequence of Steps in order, printing progress
+// uniformly and persisting completion to StateFile after each one.
+type Runner struct {
+	Steps     []Step
+	StateFile string
+	// Resume skips any step the state file already recorded as
+	// completed from a previous run, without calling its Check.
+	Resume bool
+}
+
+// NewRunner builds a Runner over steps, persisting progress to stateFile.
+func NewRunner(stateFile string, resume bool, steps ...Step) *Runner {
+	return &Runner{Steps: steps, StateFile: stateFile, Resume: resume}
+}
+
+// Run executes every step in order. A step is skipped if Resume is set
+// and the state file already recorded it complete, or if its Check
+// reports the step's effect is already in place. If a step's Apply fails,
+// or if SIGINT/SIGTERM arrives while a step is running, Run rolls back
+// every step applied during this invocation, in reverse order, before
+// returning an error - so a Ctrl-C mid-init leaves the box in the state it
+// was in before Run started, not half bootstrapped.
+func (r *Runner) Run(ctx context.Context) error {
+	state, err := loadState(r.StateFile)
+	if err != nil {
+		return fmt.Errorf("failed to read bootstrap state: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	coord := shutdown.NewRollbackCoordinator(func(phase, message string) {
+		fmt.Printf("  [%s] %s\n", phase, message)
+	})
+	coord.WatchContext(cancel)
+	defer coord.Stop()
+
+	for _, step := range r.Steps {
+		if ctx.Err() != nil {
+			coord.Rollback()
+			return fmt.Errorf("interrupted: %w", ctx.Err())
+		}
+
+		if r.Resume && state.isDone(step.Name()) {
+			fmt.Printf("  [skip] %s (already completed)\n", step.Name())
+			continue
+		}
+
+		done, err := step.Check(ctx)
+		if err != nil {
+			coord.Rollback()
+			if ctx.Err() != nil {
+				return fmt.Errorf("%s: interrupted: %w", step.Name(), ctx.Err())
+			}
+			return fmt.Errorf("%s: failed to check status: %w", step.Name(), err)
+		}
+		if done {
+			fmt.Printf("  [ok]   %s (already satisfied)\n", step.Name())
+			state.markDone(step.Name())
+			continue
+		}
+
+		fmt.Printf("  [run]  %s\n", step.Name())
+		if err := step.Apply(ctx); err != nil {
+			coord.Rollback()
+			if ctx.Err() != nil {
+				return fmt.Errorf("%s: interrupted: %w", step.Name(), ctx.Err())
+			}
+			return fmt.Errorf("%s: %w", step.Name(), err)
+		}
+
+		step := step // capture for the closure below
+		coord.OnRollback(func() error { return step.Rollback(ctx) })
+
+		state.markDone(step.Name())
+		if err := state.save(r.StateFile); err != nil {
+			fmt.Printf("  warning: failed to persist bootstrap state: %v\n", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		coord.Rollback()
+		return fmt.Errorf("interrupted: %w", ctx.Err())
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunnerSkipsStepsAlreadySatisfied(t *testing.T) {
+	var applied []string
+	steps := []Step{
+		FuncStep{
+			StepName:  "already-done",
+			CheckFunc: func(ctx context.Context) (bool, error) { return true, nil },
+			ApplyFunc: func(ctx context.Context) error {
+				t.Fatal("Apply should not run for a step Check reports done")
+				return nil
+			},
+		},
+		FuncStep{
+			StepName: "pending",
+			ApplyFunc: func(ctx context.Context) error {
+				applied = append(applied, "pending")
+				return nil
+			},
+		},
+	}
+
+	r := NewRunner(filepath.Join(t.TempDir(), "state.json"), false, steps...)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(applied) != 1 || applied[0] != "pending" {
+		t.Fatalf("expected only \"pending\" to apply, got %v", applied)
+	}
+}
+
+func TestRunnerResumeSkipsCompletedSteps(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	firstRunSteps := []Step{
+		FuncStep{StepName: "one", ApplyFunc: func(ctx context.Context) error { return nil }},
+		FuncStep{StepName: "two", ApplyFunc: func(ctx context.Context) error { return errors.New("boom") }},
+	}
+	r := NewRunner(stateFile, false, firstRunSteps...)
+	if err := r.Run(context.Background()); err == nil {
+		t.Fatalf("expected first run to fail at step two")
+	}
+
+	var reapplied []string
+	resumeSteps := []Step{
+		FuncStep{StepName: "one", ApplyFunc: func(ctx context.Context) error {
+			reapplied = append(reapplied, "one")
+			return nil
+		}},
+		FuncStep{StepName: "two", ApplyFunc: func(ctx context.Context) error {
+			reapplied = append(reapplied, "two")
+			return nil
+		}},
+	}
+	r = NewRunner(stateFile, true, resumeSteps...)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("resumed run failed: %v", err)
+	}
+	if len(reapplied) != 1 || reapplied[0] != "two" {
+		t.Fatalf("expected only \"two\" to reapply on resume, got %v", reapplied)
+	}
+}
+
+func TestRunnerRollsBackOnFailure(t *testing.T) {
+	var rolledBack []string
+	steps := []Step{
+		FuncStep{
+			StepName:     "first",
+			ApplyFunc:    func(ctx context.Context) error { return nil },
+			RollbackFunc: func(ctx context.Context) error { rolledBack = append(rolledBack, "first"); return nil },
+		},
+		FuncStep{
+			StepName:  "second",
+			ApplyFunc: func(ctx context.Context) error { return errors.New("boom") },
+		},
+	}
+
+	r := NewRunner(filepath.Join(t.TempDir(), "state.json"), false, steps...)
+	if err := r.Run(context.Background()); err == nil {
+		t.Fatalf("expected Run to fail")
+	}
+	if len(rolledBack) != 1 || rolledBack[0] != "first" {
+		t.Fatalf("expected \"first\" to roll back, got %v", rolledBack)
+	}
+}
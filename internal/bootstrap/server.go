@@ -2,52 +2,124 @@ package bootstrap
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/kessler-frost/styx/internal/cluster"
+	"github.com/kessler-frost/styx/internal/jointoken"
+	"github.com/kessler-frost/styx/internal/observability"
+	"github.com/kessler-frost/styx/internal/pki"
+	"github.com/kessler-frost/styx/internal/supervisor"
+	styxtls "github.com/kessler-frost/styx/internal/tls"
 )
 
+// Compile-time assertion that Server implements supervisor.Service.
+var _ supervisor.Service = (*Server)(nil)
+
 const (
 	// Port is the bootstrap server port
 	Port = 19999
 )
 
+// ServerAddr identifies a Nomad/Consul server node that clients should know
+// about. It is advertised over the /bootstrap/servers/watch stream so that
+// already-joined clients can stay current as servers join or leave.
+type ServerAddr struct {
+	IP   string `json:"ip"`
+	Name string `json:"name"`
+}
+
 // Server serves bootstrap files (CA cert, gossip key) for new clients.
 // Only accessible on Tailscale network for security.
 type Server struct {
+	configDir  string
 	certsDir   string
 	secretsDir string
 	server     *http.Server
 	listener   net.Listener
+	mux        *http.ServeMux
+	accessLog  *observability.AccessLog
+
+	serversMu sync.RWMutex
+	servers   map[string]ServerAddr
+
+	subsMu sync.Mutex
+	subs   map[chan []byte]struct{}
+
+	tokenPub        ed25519.PublicKey
+	useStore        *jointoken.UseStore
+	certFingerprint string
 }
 
-// NewServer creates a new bootstrap server.
-func NewServer(tailscaleIP, certsDir, secretsDir string) (*Server, error) {
+// NewServer creates a new bootstrap server. selfAddr is registered as the
+// first known server so that /bootstrap/servers/watch has something to
+// report as soon as clients subscribe.
+//
+// The certificate/gossip-key endpoints require an `Authorization: Bearer
+// <token>` header minted by `styx token create` and verified against an
+// Ed25519 keypair generated (or reused) under secretsDir - see
+// internal/jointoken. /bootstrap/health, /bootstrap/servers/watch, and
+// /bootstrap/cluster-info stay open: they carry no secrets, and
+// cluster-info in particular is what a joining node TOFU-verifies before
+// it has any reason to trust a token presented back to it.
+//
+// The whole mux is served over HTTPS with a self-signed certificate (see
+// LoadOrCreateServerCert) rather than plain HTTP: a join token embeds that
+// cert's fingerprint, so a client pins the connection instead of trusting
+// whatever answers on this IP/port.
+func NewServer(tailscaleIP, configDir, certsDir, secretsDir string, selfAddr ServerAddr) (*Server, error) {
+	_, priv, err := jointoken.LoadOrCreateKeyPair(secretsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load or create join-token keypair: %w", err)
+	}
+
+	cert, fingerprint, err := LoadOrCreateServerCert(secretsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load or create bootstrap TLS certificate: %w", err)
+	}
+
 	s := &Server{
-		certsDir:   certsDir,
-		secretsDir: secretsDir,
+		configDir:       configDir,
+		certsDir:        certsDir,
+		secretsDir:      secretsDir,
+		servers:         map[string]ServerAddr{selfAddr.IP: selfAddr},
+		subs:            make(map[chan []byte]struct{}),
+		tokenPub:        priv.Public().(ed25519.PublicKey),
+		useStore:        jointoken.NewUseStore(secretsDir),
+		certFingerprint: fingerprint,
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/bootstrap/consul-ca.pem", s.serveConsulCA)
-	mux.HandleFunc("/bootstrap/consul-client-cert.pem", s.serveConsulClientCert)
-	mux.HandleFunc("/bootstrap/consul-client-key.pem", s.serveConsulClientKey)
-	mux.HandleFunc("/bootstrap/nomad-ca.pem", s.serveNomadCA)
-	mux.HandleFunc("/bootstrap/nomad-client-cert.pem", s.serveNomadClientCert)
-	mux.HandleFunc("/bootstrap/nomad-client-key.pem", s.serveNomadClientKey)
-	mux.HandleFunc("/bootstrap/gossip.key", s.serveGossipKey)
+	mux.HandleFunc("/bootstrap/consul-ca.pem", s.requireToken(s.serveConsulCA))
+	mux.HandleFunc("/bootstrap/consul-client-cert.pem", s.requireToken(s.serveConsulClientCert))
+	mux.HandleFunc("/bootstrap/consul-client-key.pem", s.requireToken(s.serveConsulClientKey))
+	mux.HandleFunc("/bootstrap/nomad-ca.pem", s.requireToken(s.serveNomadCA))
+	mux.HandleFunc("/bootstrap/nomad-client-cert.pem", s.requireToken(s.serveNomadClientCert))
+	mux.HandleFunc("/bootstrap/nomad-client-key.pem", s.requireToken(s.serveNomadClientKey))
+	mux.HandleFunc("/bootstrap/gossip.key", s.requireToken(s.serveGossipKey))
+	mux.HandleFunc("/bootstrap/region-ca/", s.requireToken(s.serveRegionCA))
 	mux.HandleFunc("/bootstrap/health", s.serveHealth)
+	mux.HandleFunc("/bootstrap/servers/watch", s.serveServersWatch)
+	mux.HandleFunc("/bootstrap/cluster-info", s.serveClusterInfo)
 
 	addr := fmt.Sprintf("%s:%d", tailscaleIP, Port)
-	listener, err := net.Listen("tcp", addr)
+	listener, err := tls.Listen("tcp", addr, &tls.Config{Certificates: []tls.Certificate{cert}})
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
 
 	s.listener = listener
+	s.mux = mux
 	s.server = &http.Server{
 		Handler:      mux,
 		ReadTimeout:  10 * time.Second,
@@ -57,18 +129,118 @@ func NewServer(tailscaleIP, certsDir, secretsDir string) (*Server, error) {
 	return s, nil
 }
 
-// Start starts the bootstrap server in a goroutine.
+// CertFingerprint returns the SHA-256 fingerprint of the self-signed
+// certificate this server presents over TLS, for `styx token create` to
+// embed in newly minted join tokens.
+func (s *Server) CertFingerprint() string {
+	return s.certFingerprint
+}
+
+// requireToken wraps a handler so it only runs once the request carries a
+// valid, unexhausted Authorization: Bearer <token>, rejecting everything
+// else with 401.
+func (s *Server) requireToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := jointoken.Verify(s.tokenPub, token)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid token: %v", err), http.StatusUnauthorized)
+			return
+		}
+		if err := s.useStore.Consume(claims); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// EnableAccessLog wraps the server's handler with structured JSON access
+// logging and Prometheus latency histograms, serving the histograms at
+// /metrics. Access log lines are written to a rotating file named
+// bootstrap-access.log under logDir. Call this before Start.
+func (s *Server) EnableAccessLog(logDir string) error {
+	accessLog, err := observability.NewAccessLog(logDir, "bootstrap-access.log")
+	if err != nil {
+		return err
+	}
+
+	s.accessLog = accessLog
+	s.mux.Handle("/metrics", accessLog.Metrics().Handler())
+	s.server.Handler = accessLog.Middleware(s.mux)
+	return nil
+}
+
+// AccessLogPath returns the path to the bootstrap server's access log file,
+// or "" if access logging hasn't been enabled.
+func (s *Server) AccessLogPath() string {
+	if s.accessLog == nil {
+		return ""
+	}
+	return s.accessLog.Path()
+}
+
+// Metrics returns the per-artifact request latency/count histograms fed by
+// EnableAccessLog, or nil if access logging hasn't been enabled. A
+// diagnostic.Server mounts this alongside /healthz, /readyz, and pprof on a
+// listener separate from the Tailscale-only bootstrap mux.
+func (s *Server) Metrics() *observability.Metrics {
+	if s.accessLog == nil {
+		return nil
+	}
+	return s.accessLog.Metrics()
+}
+
+// Start starts the bootstrap server in a goroutine, for callers that drive
+// their own shutdown with Stop instead of a supervisor.Tree. Serve is the
+// Tree-aware equivalent and is built on the same server/listener.
 func (s *Server) Start() {
 	go s.server.Serve(s.listener)
 }
 
-// Stop gracefully stops the bootstrap server.
+// Stop gracefully stops the bootstrap server, giving in-flight requests a
+// fixed 5s to finish. Used by Serve's own ctx-cancellation path; callers
+// that want a caller-supplied deadline (e.g. shutdown.Coordinator) should
+// use StopGraceful instead.
 func (s *Server) Stop() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	return s.StopGraceful(5 * time.Second)
+}
+
+// StopGraceful stops the server from accepting new connections while
+// giving in-flight requests (e.g. a client mid-download of a bootstrap
+// file) up to deadline to finish.
+func (s *Server) StopGraceful(deadline time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
 	defer cancel()
 	return s.server.Shutdown(ctx)
 }
 
+// Serve implements supervisor.Service: it serves on the configured listener
+// until ctx is cancelled, then shuts down gracefully via Stop. A Serve error
+// other than http.ErrServerClosed is a crash a supervisor.Tree should
+// restart the server for.
+func (s *Server) Serve(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.server.Serve(s.listener) }()
+
+	select {
+	case <-ctx.Done():
+		return s.Stop()
+	case err := <-errCh:
+		if err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}
+}
+
 // Addr returns the address the server is listening on.
 func (s *Server) Addr() string {
 	return s.listener.Addr().String()
@@ -99,6 +271,44 @@ func (s *Server) serveNomadClientKey(w http.ResponseWriter, r *http.Request) {
 	s.serveFile(w, filepath.Join(s.certsDir, "global-client-nomad-key.pem"), "application/x-pem-file")
 }
 
+// serveRegionCA is both halves of the CA exchange `styx region add` drives:
+// GET returns a region's CA (the authoritative region's own, or one it has
+// already received from a peer); POST stores the caller's region CA so this
+// side can in turn verify that region's servers/clients. Region names come
+// straight from the URL path, so they're validated before touching the
+// filesystem - requireToken gates who can reach this endpoint at all, but
+// not what region name they pass.
+func (s *Server) serveRegionCA(w http.ResponseWriter, r *http.Request) {
+	region := strings.TrimPrefix(r.URL.Path, "/bootstrap/region-ca/")
+	if region == "" {
+		http.Error(w, "missing region name", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		path, err := styxtls.RegionCAFile(s.certsDir, region)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.serveFile(w, path, "application/x-pem-file")
+	case http.MethodPost:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		if err := styxtls.StoreRegionCA(s.certsDir, region, data); err != nil {
+			http.Error(w, fmt.Sprintf("failed to store region CA: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 func (s *Server) serveFile(w http.ResponseWriter, path, contentType string) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -124,3 +334,121 @@ func (s *Server) serveHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("ok"))
 }
+
+// clusterInfoResponse is what /bootstrap/cluster-info returns: enough for a
+// joining client to TOFU-confirm it's talking to the server it thinks it
+// is, and to detect a later join landing on a different cluster.
+type clusterInfoResponse struct {
+	ClusterID     string `json:"cluster_id"`
+	CAFingerprint string `json:"ca_fingerprint"`
+}
+
+func (s *Server) serveClusterInfo(w http.ResponseWriter, r *http.Request) {
+	info, err := cluster.LoadOrCreate(s.configDir, s.secretsDir)
+	if err != nil {
+		http.Error(w, "cluster identity not available", http.StatusInternalServerError)
+		return
+	}
+
+	fingerprint, err := pki.Fingerprint(pki.CAFile(s.certsDir))
+	if err != nil {
+		http.Error(w, "ca certificate not available", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clusterInfoResponse{
+		ClusterID:     info.ClusterID,
+		CAFingerprint: fingerprint,
+	})
+}
+
+// RegisterServer adds or updates a known server address and pushes the
+// updated list to all subscribers of /bootstrap/servers/watch. Call this
+// when a server joins the cluster or changes its advertised IP.
+func (s *Server) RegisterServer(addr ServerAddr) {
+	s.serversMu.Lock()
+	s.servers[addr.IP] = addr
+	s.serversMu.Unlock()
+	s.broadcastServers()
+}
+
+// UnregisterServer removes a known server address and pushes the updated
+// list to all subscribers. Call this when a server leaves the cluster.
+func (s *Server) UnregisterServer(ip string) {
+	s.serversMu.Lock()
+	delete(s.servers, ip)
+	s.serversMu.Unlock()
+	s.broadcastServers()
+}
+
+func (s *Server) listServers() []ServerAddr {
+	s.serversMu.RLock()
+	defer s.serversMu.RUnlock()
+
+	addrs := make([]ServerAddr, 0, len(s.servers))
+	for _, a := range s.servers {
+		addrs = append(addrs, a)
+	}
+	return addrs
+}
+
+func (s *Server) broadcastServers() {
+	data, err := json.Marshal(s.listServers())
+	if err != nil {
+		return
+	}
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- data:
+		default:
+			// Slow subscriber - drop the update, it'll get the next one.
+		}
+	}
+}
+
+// serveServersWatch streams the known server address list over SSE,
+// pushing an update immediately on subscribe and again whenever
+// RegisterServer/UnregisterServer is called. This lets joined clients
+// rewrite their Nomad/Consul retry_join lists without re-running
+// `styx join` every time a server is replaced.
+func (s *Server) serveServersWatch(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	ch := make(chan []byte, 4)
+	s.subsMu.Lock()
+	s.subs[ch] = struct{}{}
+	s.subsMu.Unlock()
+	defer func() {
+		s.subsMu.Lock()
+		delete(s.subs, ch)
+		s.subsMu.Unlock()
+	}()
+
+	if data, err := json.Marshal(s.listServers()); err == nil {
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-ch:
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// State records which Steps have completed, persisted to a JSON file
+// (conventionally <dataDir>/state.json) so a Runner invoked with Resume
+// can skip them after a crash or interrupted run instead of repeating
+// already-applied side effects.
+type State struct {
+	Completed []string `json:"completed"`
+}
+
+// loadState reads path, returning an empty State if it doesn't exist yet.
+func loadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// save writes s to path as JSON, creating or truncating it.
+func (s *State) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// isDone reports whether step was previously recorded as completed.
+func (s *State) isDone(step string) bool {
+	for _, name := range s.Completed {
+		if name == step {
+			return true
+		}
+	}
+	return false
+}
+
+// markDone records step as completed, if it isn't already.
+func (s *State) markDone(step string) {
+	if s.isDone(step) {
+		return
+	}
+	s.Completed = append(s.Completed, step)
+}
@@ -0,0 +1,61 @@
+// Package bootstrap turns a multi-phase bring-up (directory creation,
+// config generation, launchd load, Vault init/unseal, service deploy, ...)
+// into a sequence of typed Step values executed by a Runner, instead of
+// ~200 lines of sequential side effects with no way to unit-test a single
+// phase or resume after a partial failure. This mirrors the "factor
+// scripts out into typed Go code with immutable env" approach used
+// elsewhere in this codebase for launchd/vault lifecycle management.
+package bootstrap
+
+import "context"
+
+// Step is one phase of a bring-up sequence.
+type Step interface {
+	// Name identifies the step in progress output and the state file; it
+	// must be stable across releases since Resume matches against it.
+	Name() string
+
+	// Check reports whether this step's effect is already in place (e.g.
+	// a config file already has the right content, a service is already
+	// loaded), so Runner can skip Apply even on a fresh, non-resumed run.
+	Check(ctx context.Context) (bool, error)
+
+	// Apply performs the step's side effect.
+	Apply(ctx context.Context) error
+
+	// Rollback undoes Apply, best-effort, when a later step in the same
+	// run fails. Steps with no meaningful undo (most config generation)
+	// should return nil.
+	Rollback(ctx context.Context) error
+}
+
+// FuncStep adapts plain functions to Step, for steps whose Check/Rollback
+// are trivial so they don't warrant their own named type. ApplyFunc is
+// required; CheckFunc nil means "never already done" and RollbackFunc nil
+// means "nothing to undo".
+type FuncStep struct {
+	StepName     string
+	CheckFunc    func(ctx context.Context) (bool, error)
+	ApplyFunc    func(ctx context.Context) error
+	RollbackFunc func(ctx context.Context) error
+}
+
+func (f FuncStep) Name() string { return f.StepName }
+
+func (f FuncStep) Check(ctx context.Context) (bool, error) {
+	if f.CheckFunc == nil {
+		return false, nil
+	}
+	return f.CheckFunc(ctx)
+}
+
+func (f FuncStep) Apply(ctx context.Context) error {
+	return f.ApplyFunc(ctx)
+}
+
+func (f FuncStep) Rollback(ctx context.Context) error {
+	if f.RollbackFunc == nil {
+		return nil
+	}
+	return f.RollbackFunc(ctx)
+}
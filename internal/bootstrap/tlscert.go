@@ -0,0 +1,122 @@
+package bootstrap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	serverCertFile = "bootstrap-tls.pem"
+	serverKeyFile  = "bootstrap-tls-key.pem"
+
+	serverCertValidity = 365 * 24 * time.Hour
+)
+
+// LoadOrCreateServerCert loads the bootstrap server's self-signed HTTPS
+// certificate from secretsDir if one was already generated, or generates a
+// fresh ECDSA P-256 one and persists it there. Unlike the cluster CA in
+// internal/pki, this cert isn't meant to be trusted via a chain - a
+// joining client pins its SHA-256 fingerprint instead (embedded in the
+// join token it was handed, see internal/jointoken), so the cert only
+// needs to stay stable across restarts, not be rotated or reissued.
+// NewServer and `styx token create` both call this independently, against
+// the same files, so a token minted before the server process is even
+// running still pins the fingerprint the server ends up presenting.
+func LoadOrCreateServerCert(secretsDir string) (tls.Certificate, string, error) {
+	certPath := filepath.Join(secretsDir, serverCertFile)
+	keyPath := filepath.Join(secretsDir, serverKeyFile)
+
+	if certPEM, err := os.ReadFile(certPath); err == nil {
+		keyPEM, err := os.ReadFile(keyPath)
+		if err != nil {
+			return tls.Certificate{}, "", fmt.Errorf("failed to read %s: %w", keyPath, err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return tls.Certificate{}, "", fmt.Errorf("failed to parse bootstrap TLS cert: %w", err)
+		}
+		return cert, fingerprintPEM(certPEM), nil
+	}
+
+	if err := os.MkdirAll(secretsDir, 0700); err != nil {
+		return tls.Certificate{}, "", fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, "", fmt.Errorf("failed to generate bootstrap TLS key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, "", fmt.Errorf("failed to generate certificate serial: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "styx bootstrap server"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(serverCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, "", fmt.Errorf("failed to create bootstrap TLS certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, "", fmt.Errorf("failed to marshal bootstrap TLS key: %w", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		return tls.Certificate{}, "", fmt.Errorf("failed to write bootstrap TLS certificate: %w", err)
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return tls.Certificate{}, "", fmt.Errorf("failed to write bootstrap TLS key: %w", err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, "", err
+	}
+	return cert, fingerprintPEM(certPEM), nil
+}
+
+// fingerprintPEM returns a lowercase, unseparated hex SHA-256 digest of a
+// PEM-encoded certificate, matching the format a TLS connection's
+// tls.ConnectionState.PeerCertificates[0].Raw fingerprint is compared
+// against when a client pins it.
+func fingerprintPEM(certPEM []byte) string {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return ""
+	}
+	sum := sha256.Sum256(block.Bytes)
+	return fmt.Sprintf("%x", sum)
+}
+
+// FingerprintCert returns the lowercase hex SHA-256 fingerprint of der, for
+// comparing against the fingerprint embedded in a join token.
+func FingerprintCert(der []byte) string {
+	sum := sha256.Sum256(der)
+	return fmt.Sprintf("%x", sum)
+}
@@ -0,0 +1,82 @@
+package bootstrap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WatchServers subscribes to a bootstrap server's /bootstrap/servers/watch
+// SSE stream and invokes callback with the full current server list every
+// time it changes. It reconnects with exponential backoff if the stream
+// drops, and only returns when ctx is cancelled - callers should run it in
+// a goroutine. The stream is read over HTTPS without pinning the server's
+// self-signed certificate.
+func WatchServers(ctx context.Context, serverIP string, callback func([]ServerAddr)) error {
+	url := fmt.Sprintf("https://%s:%d/bootstrap/servers/watch", serverIP, Port)
+
+	backoff := time.Second
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := watchServersOnce(ctx, url, callback); err != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+			continue
+		}
+
+		backoff = time.Second
+	}
+}
+
+// watchServersOnce opens a single SSE connection and reads events from it
+// until the connection closes or ctx is cancelled.
+func watchServersOnce(ctx context.Context, url string, callback func([]ServerAddr)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bootstrap server returned %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+
+		var addrs []ServerAddr
+		if err := json.Unmarshal([]byte(data), &addrs); err != nil {
+			continue
+		}
+		callback(addrs)
+	}
+
+	return scanner.Err()
+}
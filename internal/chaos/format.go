@@ -0,0 +1,122 @@
+package chaos
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format is a `--format` value runCmd accepts.
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+	FormatTAP   Format = "tap"
+)
+
+// Render renders results in format, for `styx chaos --format`. Unlike
+// Runner.Run's [TEST]/[PASS]/[FAIL] progress lines (meant for a human
+// watching live), Render produces one final report meant to be captured
+// and handed to CI.
+func Render(format Format, results []Result) (string, error) {
+	switch format {
+	case "", FormatText:
+		return renderText(results), nil
+	case FormatJSON:
+		return renderJSON(results)
+	case FormatJUnit:
+		return renderJUnit(results), nil
+	case FormatTAP:
+		return renderTAP(results), nil
+	default:
+		return "", fmt.Errorf("unknown --format %q, want %q, %q, %q, or %q", format, FormatText, FormatJSON, FormatJUnit, FormatTAP)
+	}
+}
+
+func renderText(results []Result) string {
+	var b strings.Builder
+	passed, failed := countResults(results)
+	for _, r := range results {
+		if r.Passed {
+			fmt.Fprintf(&b, "PASS %s (%s)\n", r.Name, r.Duration.Round(time.Millisecond))
+		} else {
+			fmt.Fprintf(&b, "FAIL %s: %v\n", r.Name, r.Err)
+		}
+	}
+	fmt.Fprintf(&b, "\nResults: %d passed, %d failed\n", passed, failed)
+	return b.String()
+}
+
+func renderJSON(results []Result) (string, error) {
+	type jsonResult struct {
+		Name       string `json:"name"`
+		Passed     bool   `json:"passed"`
+		Error      string `json:"error,omitempty"`
+		DurationMS int64  `json:"duration_ms"`
+	}
+
+	out := make([]jsonResult, 0, len(results))
+	for _, r := range results {
+		jr := jsonResult{Name: r.Name, Passed: r.Passed, DurationMS: r.Duration.Milliseconds()}
+		if r.Err != nil {
+			jr.Error = r.Err.Error()
+		}
+		out = append(out, jr)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal chaos results: %w", err)
+	}
+	return string(data), nil
+}
+
+func renderJUnit(results []Result) string {
+	_, failed := countResults(results)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&b, `<testsuite name="styx-chaos" tests="%d" failures="%d">`+"\n", len(results), failed)
+	for _, r := range results {
+		fmt.Fprintf(&b, `  <testcase name="%s" time="%.3f">`, xmlEscape(r.Name), r.Duration.Seconds())
+		if !r.Passed {
+			fmt.Fprintf(&b, `<failure message="%s"></failure>`, xmlEscape(r.Err.Error()))
+		}
+		fmt.Fprintf(&b, "</testcase>\n")
+	}
+	fmt.Fprintf(&b, "</testsuite>\n")
+	return b.String()
+}
+
+func renderTAP(results []Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "1..%d\n", len(results))
+	for i, r := range results {
+		if r.Passed {
+			fmt.Fprintf(&b, "ok %d - %s\n", i+1, r.Name)
+		} else {
+			fmt.Fprintf(&b, "not ok %d - %s\n", i+1, r.Name)
+			fmt.Fprintf(&b, "# %v\n", r.Err)
+		}
+	}
+	return b.String()
+}
+
+func countResults(results []Result) (passed, failed int) {
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+func xmlEscape(s string) string {
+	replacer := strings.NewReplacer(`&`, "&amp;", `<`, "&lt;", `>`, "&gt;", `"`, "&quot;")
+	return replacer.Replace(s)
+}
@@ -0,0 +1,207 @@
+package chaos
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed packs/*.yaml
+var builtinPacksFS embed.FS
+
+// LoadTests returns every built-in test pack (see packs/) plus every
+// *.yaml/*.yml file in dir, keyed by name so a user-defined test can
+// override a built-in pack of the same name the way LoadUserDefinitions
+// overrides a built-in service. A missing dir is not an error - it just
+// means no operator-authored tests are configured - but a malformed file
+// is, so a typo doesn't silently drop a test the operator expects to run.
+func LoadTests(dir string) ([]Test, error) {
+	byName := map[string]Test{}
+	var order []string
+
+	builtin, err := loadTestsFS(builtinPacksFS, "packs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load built-in chaos packs: %w", err)
+	}
+	for _, t := range builtin {
+		byName[t.Name] = t
+		order = append(order, t.Name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return orderedTests(byName, order), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isYAMLFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		test, err := parseTest(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if _, overriding := byName[test.Name]; !overriding {
+			order = append(order, test.Name)
+		}
+		byName[test.Name] = test
+	}
+
+	return orderedTests(byName, order), nil
+}
+
+func loadTestsFS(fsys embed.FS, dir string) ([]Test, error) {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []Test
+	for _, entry := range entries {
+		if entry.IsDir() || !isYAMLFile(entry.Name()) {
+			continue
+		}
+		data, err := fsys.ReadFile(dir + "/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded %s: %w", entry.Name(), err)
+		}
+		test, err := parseTest(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+func orderedTests(byName map[string]Test, order []string) []Test {
+	tests := make([]Test, 0, len(order))
+	for _, name := range order {
+		tests = append(tests, byName[name])
+	}
+	return tests
+}
+
+func isYAMLFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// parseTest reads a single chaos test definition, a hand-rolled reader for
+// the restricted subset of YAML this package needs (flat scalars plus one
+// level of nesting under "expect:") rather than a general YAML parser, the
+// same way internal/services/definition.go hand-rolls its own HCL subset:
+//
+//	name: agent-kill-recovery
+//	type: kill_process
+//	target: nomad agent
+//	expect:
+//	  http_endpoint: http://127.0.0.1:4646/v1/agent/health
+//	  timeout: 120s
+//	  settle: 5s
+func parseTest(data string) (Test, error) {
+	var t Test
+	inExpect := false
+
+	lines := strings.Split(data, "\n")
+	for i, raw := range lines {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if trimmed == "expect:" {
+			inExpect = true
+			continue
+		}
+
+		indented := strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")
+		key, value, err := splitYAMLField(trimmed)
+		if err != nil {
+			return Test{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if inExpect && indented {
+			if err := applyExpectField(&t.Expect, key, value); err != nil {
+				return Test{}, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			continue
+		}
+		inExpect = false
+
+		if err := applyTestField(&t, key, value); err != nil {
+			return Test{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+
+	if t.Name == "" {
+		return Test{}, fmt.Errorf("missing required field %q", "name")
+	}
+	if t.Type == "" {
+		return Test{}, fmt.Errorf("missing required field %q", "type")
+	}
+	return t, nil
+}
+
+func applyTestField(t *Test, key, value string) error {
+	switch key {
+	case "name":
+		t.Name = value
+	case "type":
+		t.Type = Type(value)
+	case "target":
+		t.Target = value
+	default:
+		return fmt.Errorf("unknown field %q", key)
+	}
+	return nil
+}
+
+func applyExpectField(e *Expect, key, value string) error {
+	var err error
+	switch key {
+	case "service_status":
+		e.ServiceStatus = value
+	case "http_endpoint":
+		e.HTTPEndpoint = value
+	case "timeout":
+		e.Timeout, err = time.ParseDuration(value)
+	case "settle":
+		e.Settle, err = time.ParseDuration(value)
+	default:
+		return fmt.Errorf("unknown expect field %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("field %q: %w", key, err)
+	}
+	return nil
+}
+
+// splitYAMLField splits a "key: value" line, trimming a surrounding quote
+// pair from value if present.
+func splitYAMLField(line string) (key, value string, err error) {
+	idx := strings.Index(line, ":")
+	if idx == -1 {
+		return "", "", fmt.Errorf("expected 'field: value', got %q", line)
+	}
+	key = strings.TrimSpace(line[:idx])
+	value = strings.TrimSpace(line[idx+1:])
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("expected 'field: value', got %q", line)
+	}
+	return key, value, nil
+}
@@ -0,0 +1,116 @@
+package chaos
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/services"
+)
+
+// inject fires the primitive a Test.Type names against Test.Target. It only
+// causes the failure - Runner's retry loop is what decides whether the
+// cluster recovered, via Test.Expect.
+func inject(t Test) error {
+	switch t.Type {
+	case KillProcess:
+		return killProcess(t.Target)
+	case StopService:
+		return stopService(t.Target)
+	case BlockPort:
+		return blockPort(t.Target)
+	case NetworkPartition:
+		return networkPartition(t.Target)
+	case KillContainer:
+		return killContainer(t.Target)
+	default:
+		return fmt.Errorf("unknown chaos test type %q", t.Type)
+	}
+}
+
+// killProcess pkills every process matching pattern, the same cleanup
+// sweep the old hardcoded agent test ran after stopping Nomad's launchd
+// job, so a leftover child process can't keep a port held open.
+func killProcess(pattern string) error {
+	if err := exec.Command("pkill", "-f", pattern).Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil // pkill exits 1 when nothing matched - not a failure
+		}
+		return fmt.Errorf("pkill -f %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// stopService stops a platform service's Nomad job. Expect.ServiceStatus
+// or Expect.HTTPEndpoint then decides whether something redeployed it,
+// exactly like the old hardcoded service-restart test - this primitive
+// only stops it; recovery is meant to demonstrate Nomad/the operator
+// noticing and redeploying, not this test doing it for them.
+func stopService(name string) error {
+	return services.DefaultClient().StopJob(name)
+}
+
+// blockPort uses a dedicated pf anchor to drop all traffic to a local TCP
+// port, simulating a crashed or wedged listener without actually killing
+// the process holding it.
+func blockPort(port string) error {
+	if _, err := strconv.Atoi(port); err != nil {
+		return fmt.Errorf("invalid port %q: %w", port, err)
+	}
+	return pfApply(fmt.Sprintf("block drop quick proto tcp from any to any port %s\n", port))
+}
+
+// networkPartition uses a dedicated pf anchor to drop all traffic to and
+// from a Tailscale peer, resolving target (a hostname or IP) via
+// network.GetTailscalePeers the same way the rest of internal/network
+// does. ClearPFRules restores traffic once the settle/expect window is
+// over.
+func networkPartition(target string) error {
+	ip := target
+	for _, peer := range network.GetTailscalePeers() {
+		if peer.Hostname == target || peer.DNSName == target {
+			ip = peer.IP
+			break
+		}
+	}
+	return pfApply(fmt.Sprintf("block drop quick from %s to any\nblock drop quick from any to %s\n", ip, ip))
+}
+
+// killContainer force-stops a running Apple Containers task, the
+// container-runtime equivalent of killProcess for workloads that aren't
+// plain host processes.
+func killContainer(name string) error {
+	if err := exec.Command("container", "kill", name).Run(); err != nil {
+		return fmt.Errorf("container kill %s: %w", name, err)
+	}
+	return nil
+}
+
+// pfAnchor is the pf anchor block_port/network_partition scope their rules
+// under, so ClearPFRules only ever touches rules this package added rather
+// than the system's own pf.conf.
+const pfAnchor = "styx.chaos"
+
+func pfApply(rules string) error {
+	cmd := exec.Command("pfctl", "-a", pfAnchor, "-f", "-")
+	cmd.Stdin = strings.NewReader(rules)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pfctl -a %s -f -: %w: %s", pfAnchor, err, strings.TrimSpace(stderr.String()))
+	}
+	return exec.Command("pfctl", "-E").Run()
+}
+
+// ClearPFRules flushes this package's pf anchor, restoring normal traffic
+// after a block_port or network_partition test. Runner calls this once
+// after every test of either type, pass or fail.
+func ClearPFRules() error {
+	if err := exec.Command("pfctl", "-a", pfAnchor, "-F", "all").Run(); err != nil {
+		return fmt.Errorf("pfctl -a %s -F all: %w", pfAnchor, err)
+	}
+	return nil
+}
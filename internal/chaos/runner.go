@@ -0,0 +1,122 @@
+package chaos
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/services"
+)
+
+// Result is one Test's outcome, enough to drive every Format.
+type Result struct {
+	Name     string
+	Passed   bool
+	Err      error
+	Duration time.Duration
+}
+
+// Runner runs a set of Tests, retrying each one's Expect check until it
+// passes or its deadline expires, the same "assert, sleep, retry until
+// timeout" loop goss's validate command uses.
+type Runner struct {
+	// RetryTimeout bounds how long a Test is retried when its own
+	// Expect.Timeout is zero.
+	RetryTimeout time.Duration
+
+	// Sleep is how long Run waits between retry attempts.
+	Sleep time.Duration
+}
+
+// Run executes every test in order, printing progress the same way the old
+// hardcoded chaos command did, and returns one Result per test.
+func (r *Runner) Run(tests []Test) []Result {
+	results := make([]Result, 0, len(tests))
+	for _, t := range tests {
+		fmt.Printf("[TEST] %s\n", t.Name)
+		res := r.runOne(t)
+		if res.Passed {
+			fmt.Printf("[PASS] %s (%s)\n\n", t.Name, res.Duration.Round(time.Millisecond))
+		} else {
+			fmt.Printf("[FAIL] %s: %v\n\n", t.Name, res.Err)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func (r *Runner) runOne(t Test) Result {
+	start := time.Now()
+	defer func() {
+		if t.Type == BlockPort || t.Type == NetworkPartition {
+			if err := ClearPFRules(); err != nil {
+				fmt.Printf("  Warning: failed to clear pf rules: %v\n", err)
+			}
+		}
+	}()
+
+	if err := inject(t); err != nil {
+		return Result{Name: t.Name, Err: fmt.Errorf("failed to inject %s: %w", t.Type, err), Duration: time.Since(start)}
+	}
+
+	if t.Expect.Settle > 0 {
+		time.Sleep(t.Expect.Settle)
+	}
+
+	timeout := t.Expect.Timeout
+	if timeout == 0 {
+		timeout = r.RetryTimeout
+	}
+	sleep := r.Sleep
+	if sleep == 0 {
+		sleep = 2 * time.Second
+	}
+
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for {
+		lastErr = checkExpect(t)
+		if lastErr == nil {
+			return Result{Name: t.Name, Passed: true, Duration: time.Since(start)}
+		}
+		if time.Now().After(deadline) {
+			return Result{Name: t.Name, Err: fmt.Errorf("gave up after %s: %w", timeout, lastErr), Duration: time.Since(start)}
+		}
+		time.Sleep(sleep)
+	}
+}
+
+// checkExpect reports whether a Test's Expect currently holds. A Test with
+// neither field set only verifies the primitive itself didn't error -
+// useful for a primitive whose failure is the point (e.g. proving
+// block_port actually drops traffic) rather than recovery.
+func checkExpect(t Test) error {
+	e := t.Expect
+	if e.ServiceStatus != "" {
+		status, err := services.DefaultClient().GetJobStatus(t.Target)
+		if err != nil {
+			return fmt.Errorf("failed to get job status: %w", err)
+		}
+		if status == nil || status.Status != e.ServiceStatus {
+			got := "not deployed"
+			if status != nil {
+				got = status.Status
+			}
+			return fmt.Errorf("expected service status %q, got %q", e.ServiceStatus, got)
+		}
+	}
+
+	if e.HTTPEndpoint != "" {
+		client := &http.Client{Timeout: 3 * time.Second}
+		resp, err := client.Get(e.HTTPEndpoint)
+		if err != nil {
+			return fmt.Errorf("failed to reach %s: %w", e.HTTPEndpoint, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("%s returned status %d", e.HTTPEndpoint, resp.StatusCode)
+		}
+	}
+
+	return nil
+}
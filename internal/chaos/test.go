@@ -0,0 +1,49 @@
+// Package chaos loads declarative chaos test definitions and runs them with
+// goss-style retry-until-deadline polling, the way cmd/styx's chaos command
+// used to hardcode as four Go functions (agent kill/recovery, service
+// kill/restart, container runtime, cluster membership).
+package chaos
+
+import "time"
+
+// Type is a chaos primitive Runner knows how to inject; see primitives.go
+// for what each one actually does to a Test's Target.
+type Type string
+
+const (
+	KillProcess      Type = "kill_process"
+	StopService      Type = "stop_service"
+	BlockPort        Type = "block_port"
+	NetworkPartition Type = "network_partition"
+	KillContainer    Type = "kill_container"
+)
+
+// Expect describes how Runner decides a Test has recovered, polled every
+// Runner.Sleep until it passes or Timeout expires.
+type Expect struct {
+	// ServiceStatus, if set, must equal a Nomad job's status (e.g.
+	// "running") for Target, the same status waitForServices polls for in
+	// internal/services.
+	ServiceStatus string
+
+	// HTTPEndpoint, if set, must return a 2xx response.
+	HTTPEndpoint string
+
+	// Timeout bounds how long Runner retries Expect before failing the
+	// test. Defaults to Runner.RetryTimeout if zero.
+	Timeout time.Duration
+
+	// Settle is waited once after the primitive fires, before the first
+	// Expect check, so recovery (a launchd restart, a Nomad reschedule)
+	// gets a head start instead of failing on attempt one.
+	Settle time.Duration
+}
+
+// Test is one chaos test definition, loaded from a chaos/*.yaml file (see
+// LoadTests) or a built-in pack (see packs/).
+type Test struct {
+	Name   string
+	Type   Type
+	Target string
+	Expect Expect
+}
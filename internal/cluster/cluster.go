@@ -0,0 +1,130 @@
+// Package cluster manages Styx's own cluster identity, layered on top of
+// Nomad's and Vault's: a ClusterID (shared with the mDNS/rendezvous
+// discovery advertisement, see network.LoadOrCreateClusterID) plus a
+// BootstrapToken, generated once when a server first forms a cluster and
+// persisted to secretsDir/cluster.json. A second `init --serve` reuses the
+// same identity rather than generating a new one, and a joining client
+// persists its own copy (see JoinRecord) so a later join against a
+// different server - or one impersonating the same IP - fails loudly
+// instead of silently forming a split-brain cluster.
+package cluster
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kessler-frost/styx/internal/network"
+)
+
+// Info is a server's cluster identity, as persisted to secretsDir/cluster.json.
+type Info struct {
+	ClusterID      string `json:"cluster_id"`
+	BootstrapToken string `json:"bootstrap_token"`
+}
+
+// JoinRecord is what a client persists at secretsDir/cluster.json after
+// joining: the server's cluster_id and CA fingerprint, as confirmed via
+// TOFU on first join. CheckJoinRecord compares it against what the server
+// reports on every later join.
+type JoinRecord struct {
+	ClusterID     string `json:"cluster_id"`
+	CAFingerprint string `json:"ca_fingerprint"`
+}
+
+// LoadOrCreate loads a server's cluster identity from secretsDir/cluster.json
+// if one was already formed there, or generates a fresh BootstrapToken
+// (reusing or creating the cluster_id in configDir) and persists them. Safe
+// to call on every `init --serve`, the same way pki.LoadOrCreateCA and
+// vault.NewBootstrapper reuse existing state instead of replacing it.
+func LoadOrCreate(configDir, secretsDir string) (*Info, error) {
+	path := infoPath(secretsDir)
+
+	if data, err := os.ReadFile(path); err == nil {
+		var info Info
+		if err := json.Unmarshal(data, &info); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return &info, nil
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	clusterID, err := network.LoadOrCreateClusterID(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cluster id: %w", err)
+	}
+	token, err := generateBootstrapToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate bootstrap token: %w", err)
+	}
+
+	info := &Info{ClusterID: clusterID, BootstrapToken: token}
+	if err := writeJSON(path, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// LoadJoinRecord reads a client's previously-persisted JoinRecord, or
+// returns (nil, nil) if this client hasn't joined a cluster yet.
+func LoadJoinRecord(secretsDir string) (*JoinRecord, error) {
+	data, err := os.ReadFile(infoPath(secretsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec JoinRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", infoPath(secretsDir), err)
+	}
+	return &rec, nil
+}
+
+// SaveJoinRecord persists rec to a client's secretsDir/cluster.json.
+func SaveJoinRecord(secretsDir string, rec *JoinRecord) error {
+	return writeJSON(infoPath(secretsDir), rec)
+}
+
+// CheckJoinRecord compares a freshly-fetched JoinRecord against a
+// previously-persisted one, returning an error if they disagree on
+// cluster_id or ca_fingerprint - a sign this client is pointed at a
+// different cluster than the one it originally joined.
+func CheckJoinRecord(previous, current *JoinRecord) error {
+	if previous.ClusterID != current.ClusterID {
+		return fmt.Errorf("server reports cluster_id %q, but this client previously joined cluster %q - refusing to rejoin a different cluster", current.ClusterID, previous.ClusterID)
+	}
+	if previous.CAFingerprint != current.CAFingerprint {
+		return fmt.Errorf("server's CA fingerprint %q doesn't match the %q this client trusted on first join - refusing to proceed", current.CAFingerprint, previous.CAFingerprint)
+	}
+	return nil
+}
+
+func infoPath(secretsDir string) string {
+	return filepath.Join(secretsDir, "cluster.json")
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func generateBootstrapToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kessler-frost/styx/internal/api"
+)
+
+// raftRPCPort is the port Nomad's raft peers dial each other on (see
+// internal/config/templates.go's client servers addresses), appended to a
+// member's serf Addr to get its raft peer address.
+const raftRPCPort = "4647"
+
+// ReplaceNodeOptions configures ReplaceNode.
+type ReplaceNodeOptions struct {
+	// Force skips the "is this member actually dead" check, replacing it
+	// even if it currently reports alive.
+	Force bool
+
+	// DryRun prints the API calls ReplaceNode would make (and whether it
+	// would wipe RaftDir) without making any of them.
+	DryRun bool
+
+	// RaftDir, if non-empty and LocalAddr matches the target member's Addr,
+	// is removed so a replacement server started on this same host doesn't
+	// come up pointing at the retired member's stale raft state.
+	RaftDir string
+
+	// LocalAddr is this host's own cluster address (see
+	// network.GetTailscaleInfo().IP), compared against the target member's
+	// Addr to decide whether RaftDir is this host's to wipe.
+	LocalAddr string
+}
+
+// ReplaceNodeResult summarizes what ReplaceNode did (or, under DryRun,
+// would do).
+type ReplaceNodeResult struct {
+	Member    api.Member
+	WipedRaft bool
+	RejoinCmd string
+}
+
+// ReplaceNode recovers from a Nomad server stuck in "failed" or "left": it
+// evicts name from the serf pool (force-leave), removes it from the raft
+// peer set, and - if RaftDir/LocalAddr say this host was that member - wipes
+// the local raft state so a replacement can rejoin reusing the same node
+// name. Refuses to touch a member reporting "alive" unless opts.Force is
+// set, since force-leave plus a raft peer removal on a live server would
+// knock a healthy voter out of quorum instead of cleaning up a dead one.
+func ReplaceNode(client *api.Client, name string, opts ReplaceNodeOptions) (*ReplaceNodeResult, error) {
+	member, err := client.GetMember(name)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, fmt.Errorf("no cluster member named %q", name)
+	}
+	if member.Status == "alive" && !opts.Force {
+		return nil, fmt.Errorf("member %q reports alive; pass --force to replace it anyway", name)
+	}
+
+	raftAddr := member.Addr + ":" + raftRPCPort
+	wipeRaft := opts.RaftDir != "" && opts.LocalAddr != "" && opts.LocalAddr == member.Addr
+
+	result := &ReplaceNodeResult{
+		Member:    *member,
+		RejoinCmd: fmt.Sprintf("styx init --join <ip> --node-name %s", name),
+	}
+
+	if opts.DryRun {
+		fmt.Printf("[dry-run] POST  /v1/agent/force-leave?node=%s\n", name)
+		fmt.Printf("[dry-run] DELETE /v1/operator/raft/peer?address=%s\n", raftAddr)
+		if wipeRaft {
+			fmt.Printf("[dry-run] remove %s (this host was %s)\n", opts.RaftDir, name)
+		}
+		return result, nil
+	}
+
+	if err := client.ForceLeaveMember(name); err != nil {
+		return nil, fmt.Errorf("failed to force-leave %s: %w", name, err)
+	}
+	if err := client.RemoveRaftPeer(raftAddr); err != nil {
+		return nil, fmt.Errorf("failed to remove raft peer %s: %w", raftAddr, err)
+	}
+
+	if wipeRaft {
+		if err := os.RemoveAll(opts.RaftDir); err != nil {
+			return nil, fmt.Errorf("failed to wipe raft state at %s: %w", opts.RaftDir, err)
+		}
+		result.WipedRaft = true
+	}
+
+	return result, nil
+}
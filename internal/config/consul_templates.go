@@ -10,6 +10,9 @@ datacenter = "dc1"
 
 server = true
 bootstrap_expect = {{.BootstrapExpect}}
+{{- if .Servers}}
+retry_join = [{{range $i, $s := .Servers}}{{if $i}}, {{end}}"{{$s}}"{{end}}]
+{{- end}}
 
 ui_config {
   enabled = true
@@ -43,7 +46,7 @@ encrypt = "{{.GossipKey}}"
 auto_encrypt {
   allow_tls = true
 }
-`
+` + tracingTelemetryStanzas
 
 // ConsulClientConfigTemplate is the HCL template for a Consul client node.
 // Client nodes forward requests to servers and cache results locally.
@@ -79,4 +82,30 @@ tls {
 
 # Gossip Encryption
 encrypt = "{{.GossipKey}}"
-`
+` + tracingTelemetryStanzas
+
+// tracingTelemetryStanzas renders TracingConfig/TelemetryConfig's
+// optional stanzas (see generator.go), appended to both
+// ConsulServerConfigTemplate and ConsulClientConfigTemplate so a
+// bare ConsulServerConfig{}/ConsulClientConfig{} keeps rendering exactly
+// the HCL it did before these fields existed.
+const tracingTelemetryStanzas = `
+{{if .Tracing.CollectorURL}}
+# Distributed tracing for Connect sidecar proxies
+tracing {
+  collector_url = "{{.Tracing.CollectorURL}}"
+  sampling_ratio = {{.Tracing.EffectiveSamplingRatio}}
+{{if .Tracing.ServiceNameFormat}}  service_name_format = "{{.Tracing.ServiceNameFormat}}"
+{{end}}}
+{{end}}
+{{if or .Telemetry.PrometheusRetentionTime .Telemetry.DogstatsdAddr}}
+telemetry {
+{{if .Telemetry.PrometheusRetentionTime}}  prometheus_retention_time = "{{.Telemetry.PrometheusRetentionTime}}"
+{{end}}{{if .Telemetry.DogstatsdAddr}}  dogstatsd_addr = "{{.Telemetry.DogstatsdAddr}}"
+{{end}}  disable_hostname = {{.Telemetry.DisableHostname}}
+{{if .Telemetry.MetricPrefix}}  metrics_prefix = "{{.Telemetry.MetricPrefix}}"
+{{end}}{{if .Telemetry.Labels}}  labels {
+{{range $k, $v := .Telemetry.Labels}}    {{$k}} = "{{$v}}"
+{{end}}  }
+{{end}}}
+{{end}}`
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-discover"
+	"github.com/hashicorp/go-discover/provider/aws"
+	"github.com/hashicorp/go-discover/provider/azure"
+	"github.com/hashicorp/go-discover/provider/gce"
+	"github.com/hashicorp/go-discover/provider/k8s"
+	"github.com/hashicorp/go-discover/provider/mdns"
+)
+
+// discoverProviders are the go-discover backends Styx compiles in: cloud VM
+// metadata services, Kubernetes, and mDNS for Macs on the same LAN. This
+// mirrors the provider=... strings consul-k8s feeds into Consul's own
+// built-in retry_join resolution (EXTERNAL DOC 12) - ResolveServers exists
+// for the environments where Consul was built without discover support.
+var discoverProviders = map[string]discover.Provider{
+	"aws":   &aws.Provider{},
+	"gce":   &gce.Provider{},
+	"azure": &azure.Provider{},
+	"k8s":   &k8s.Provider{},
+	"mdns":  &mdns.Provider{},
+}
+
+// ErrInvalidDiscoverConfig reports that a go-discover config string in a
+// Servers list couldn't be parsed or resolved, naming the offending entry
+// so a typo'd provider string doesn't just silently drop a server from
+// retry_join.
+type ErrInvalidDiscoverConfig struct {
+	Config string
+	Err    error
+}
+
+func (e *ErrInvalidDiscoverConfig) Error() string {
+	return fmt.Sprintf("invalid discovery config %q: %v", e.Config, e.Err)
+}
+
+func (e *ErrInvalidDiscoverConfig) Unwrap() error { return e.Err }
+
+// IsDiscoveryConfig reports whether server is a go-discover config string
+// (e.g. "provider=aws tag_key=... tag_value=...") rather than a literal IP
+// or hostname. retry_join accepts either verbatim; ResolveServers uses this
+// to tell which entries it actually needs to resolve.
+func IsDiscoveryConfig(server string) bool {
+	return strings.HasPrefix(strings.TrimSpace(server), "provider=")
+}
+
+// ResolveServers resolves every go-discover config string in servers to
+// its literal addresses via the compiled-in providers (aws, gce, azure,
+// k8s, mdns), leaving already-literal entries untouched.
+//
+// This is optional: Consul and Nomad both resolve provider=... strings in
+// retry_join themselves when built with discover support, so most callers
+// can pass ConsulServerConfig.Servers/ConsulClientConfig.Servers straight
+// through to GenerateConsulServerConfig/GenerateConsulClientConfig
+// unresolved. ResolveServers is for the minority built without that
+// support, or that would rather fail template rendering on a bad
+// discovery string than have a running agent silently never find its
+// peers.
+func ResolveServers(servers []string) ([]string, error) {
+	d := discover.Discover{Providers: discoverProviders}
+	logger := log.New(io.Discard, "", 0)
+
+	resolved := make([]string, 0, len(servers))
+	for _, s := range servers {
+		if !IsDiscoveryConfig(s) {
+			resolved = append(resolved, s)
+			continue
+		}
+
+		addrs, err := d.Addrs(s, logger)
+		if err != nil {
+			return nil, &ErrInvalidDiscoverConfig{Config: s, Err: err}
+		}
+		resolved = append(resolved, addrs...)
+	}
+	return resolved, nil
+}
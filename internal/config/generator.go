@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"text/template"
@@ -10,16 +11,42 @@ import (
 
 // ServerConfig holds the configuration values for a Nomad server node.
 type ServerConfig struct {
-	DataDir         string // e.g., /var/lib/nomad
-	AdvertiseIP     string // Local IP for cluster communication
-	BootstrapExpect int    // Number of servers to expect (usually 1 for single node)
-	PluginDir       string // Path to task driver plugins
+	DataDir         string   // e.g., /var/lib/nomad
+	AdvertiseIP     string   // Local IP for cluster communication
+	BootstrapExpect int      // Number of servers to expect (1 for a single node, 3/5 for HA)
+	Servers         []string // Other servers to retry_join, for HA (--join-as-server); empty for a lone/first server
+	PluginDir       string   // Path to task driver plugins
+	// Region is this node's Nomad region name. Empty renders no `region`
+	// stanza at all, so Nomad falls back to its own "global" default.
+	Region string
+	// Regions lists federated regions (see `styx region add`) whose
+	// servers are retry_join'd alongside Servers.
+	Regions []Region
 	// TLS configuration
 	CAFile   string // Path to CA certificate
 	CertFile string // Path to server certificate
 	KeyFile  string // Path to server private key
 	// Vault configuration
 	VaultToken string // Vault token for job templates
+	// ClusterID is Styx's own cluster identity (internal/cluster), stamped
+	// into the client stanza's meta so `nomad node status -verbose` and job
+	// constraints can key off it.
+	ClusterID string
+}
+
+// RetryJoinAddrs returns every server this region's Servers and Regions
+// together should retry_join, each formatted as "ip:port".
+func (c ServerConfig) RetryJoinAddrs(port string) []string {
+	addrs := make([]string, 0, len(c.Servers))
+	for _, s := range c.Servers {
+		addrs = append(addrs, s+":"+port)
+	}
+	for _, r := range c.Regions {
+		for _, s := range r.Servers {
+			addrs = append(addrs, s+":"+port)
+		}
+	}
+	return addrs
 }
 
 // ClientConfig holds the configuration values for a Nomad client node.
@@ -28,6 +55,9 @@ type ClientConfig struct {
 	AdvertiseIP string   // Local IP for cluster communication
 	Servers     []string // Server IPs to join
 	PluginDir   string   // Path to task driver plugins
+	// Region is this node's Nomad region name. Empty renders no `region`
+	// stanza at all, so Nomad falls back to its own "global" default.
+	Region string
 	// TLS configuration
 	CAFile   string // Path to CA certificate
 	CertFile string // Path to client certificate
@@ -39,23 +69,111 @@ type ConsulServerConfig struct {
 	DataDir         string // e.g., ~/Library/Application Support/styx/consul
 	AdvertiseIP     string // Local IP for cluster communication
 	BootstrapExpect int    // Number of servers to expect (usually 1 for single node)
+	// Servers lists other server nodes to retry_join, for a multi-server
+	// cluster - the same role ServerConfig.Servers plays for Nomad. Each
+	// entry is rendered into retry_join verbatim, so it may be a literal
+	// IP/hostname or a go-discover config string (e.g. "provider=aws
+	// tag_key=... tag_value=..."), which Consul resolves itself at agent
+	// startup if built with discover support; see ResolveServers to
+	// pre-resolve discovery strings instead. Empty renders no retry_join
+	// stanza, so a lone bootstrapping server's config is unchanged from
+	// before this field existed.
+	Servers []string
 	// TLS configuration
 	CAFile    string // Path to CA certificate
 	CertFile  string // Path to server certificate
 	KeyFile   string // Path to server private key
 	GossipKey string // Gossip encryption key
+	// Tracing/Telemetry are both optional - their zero values render no
+	// stanza at all, so existing callers keep getting today's plain HCL.
+	Tracing   TracingConfig
+	Telemetry TelemetryConfig
 }
 
 // ConsulClientConfig holds the configuration values for a Consul client node.
 type ConsulClientConfig struct {
-	DataDir     string   // e.g., ~/Library/Application Support/styx/consul
-	AdvertiseIP string   // Local IP for cluster communication
-	Servers     []string // Server IPs to join
+	DataDir     string // e.g., ~/Library/Application Support/styx/consul
+	AdvertiseIP string // Local IP for cluster communication
+	// Servers lists server nodes to retry_join. Each entry may be a
+	// literal IP/hostname or a go-discover config string - see
+	// ConsulServerConfig.Servers.
+	Servers []string
 	// TLS configuration
 	CAFile    string // Path to CA certificate
 	CertFile  string // Path to client certificate
 	KeyFile   string // Path to client private key
 	GossipKey string // Gossip encryption key
+	// Tracing/Telemetry are both optional - see ConsulServerConfig.
+	Tracing   TracingConfig
+	Telemetry TelemetryConfig
+}
+
+// TracingConfig enables distributed tracing for Connect sidecar proxies,
+// inspired by Consul's Envoy tracing support (upstream changelog 13998).
+// The zero value (empty CollectorURL) renders no stanza at all.
+type TracingConfig struct {
+	// CollectorURL is the Zipkin or OTLP collector endpoint sidecar
+	// proxies export spans to, e.g. "http://127.0.0.1:9411/api/v2/spans".
+	CollectorURL string
+	// SamplingRatio is the fraction of requests to trace, from 0 to 1.
+	// 0 is treated as 1 (trace everything) once CollectorURL is set - see
+	// EffectiveSamplingRatio.
+	SamplingRatio float64
+	// ServiceNameFormat overrides how a proxy's span service name is
+	// derived from its Consul service name, e.g. "%s-sidecar-proxy".
+	// Empty uses Consul's own default.
+	ServiceNameFormat string
+}
+
+// EffectiveSamplingRatio returns t.SamplingRatio, defaulting an unset
+// (zero) ratio to 1.0 so "I set a collector URL but didn't think about
+// sampling" means "trace everything" rather than "trace nothing".
+func (t TracingConfig) EffectiveSamplingRatio() float64 {
+	if t.SamplingRatio <= 0 {
+		return 1.0
+	}
+	return t.SamplingRatio
+}
+
+// validate checks CollectorURL is a well-formed absolute URL and
+// SamplingRatio is in range, at template-build time rather than letting
+// a typo'd collector address reach Consul as silently-ignored HCL.
+func (t TracingConfig) validate() error {
+	if t.CollectorURL == "" {
+		return nil
+	}
+	u, err := url.Parse(t.CollectorURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("tracing collector URL %q must be an absolute URL with a scheme and host", t.CollectorURL)
+	}
+	if t.SamplingRatio < 0 || t.SamplingRatio > 1 {
+		return fmt.Errorf("tracing sampling ratio %v must be between 0 and 1", t.SamplingRatio)
+	}
+	return nil
+}
+
+// TelemetryConfig configures where Consul agent metrics (including
+// Connect sidecar proxy stats) are emitted and how they're labeled. The
+// zero value renders no stanza at all.
+type TelemetryConfig struct {
+	// PrometheusRetentionTime is how long the agent keeps metrics
+	// available for a Prometheus scrape, e.g. "24h". Empty disables the
+	// Prometheus sink.
+	PrometheusRetentionTime string
+	// DogstatsdAddr is a DogStatsD collector address, e.g.
+	// "127.0.0.1:8125". Empty disables the DogStatsD sink.
+	DogstatsdAddr string
+	// DisableHostname omits the agent's hostname from emitted metric
+	// names, the same as Consul's own disable_hostname.
+	DisableHostname bool
+	// MetricPrefix prefixes every metric name; empty uses Consul's
+	// "consul." default.
+	MetricPrefix string
+	// Labels are extra static labels applied to every emitted metric -
+	// e.g. partition, segment, or network-area, for operators running
+	// several Styx-managed Consul clusters and aggregating their metrics
+	// centrally.
+	Labels map[string]string
 }
 
 // GenerateServerConfig renders the server HCL template with the given config.
@@ -90,6 +208,10 @@ func GenerateClientConfig(cfg ClientConfig) (string, error) {
 
 // GenerateConsulServerConfig renders the Consul server HCL template with the given config.
 func GenerateConsulServerConfig(cfg ConsulServerConfig) (string, error) {
+	if err := cfg.Tracing.validate(); err != nil {
+		return "", fmt.Errorf("invalid tracing config: %w", err)
+	}
+
 	tmpl, err := template.New("consul-server").Parse(ConsulServerConfigTemplate)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse consul server template: %w", err)
@@ -105,6 +227,10 @@ func GenerateConsulServerConfig(cfg ConsulServerConfig) (string, error) {
 
 // GenerateConsulClientConfig renders the Consul client HCL template with the given config.
 func GenerateConsulClientConfig(cfg ConsulClientConfig) (string, error) {
+	if err := cfg.Tracing.validate(); err != nil {
+		return "", fmt.Errorf("invalid tracing config: %w", err)
+	}
+
 	tmpl, err := template.New("consul-client").Parse(ConsulClientConfigTemplate)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse consul client template: %w", err)
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kessler-frost/styx/internal/template"
+)
+
+// LiveRenderOptions enables pulling fields that can drift after install
+// time from Consul KV / Vault at render time via internal/template, instead
+// of baking the values this process currently knows about into the file.
+// The live templates pull the Nomad server's Vault token and the Consul
+// gossip key; the server address list is not pulled live. The zero value disables live
+// rendering, so existing callers of Generate*Config/WriteConfig are
+// unaffected.
+type LiveRenderOptions struct {
+	Enabled    bool
+	ConsulAddr string // e.g. http://127.0.0.1:8500
+	VaultAddr  string // e.g. http://127.0.0.1:8200
+	VaultToken string // Used to authenticate the renderer itself, not injected into the output
+}
+
+// ServerConfigCTemplate is ServerConfigTemplate with the static fields
+// sourced from the process environment and the Vault token resolved
+// through consul-template's `secret` function instead of baked in, so a
+// rotated nomad-cluster token is picked up on the next render without
+// regenerating nomad.hcl from Go. Unlike ServerConfigTemplate, it renders
+// no region, server_join, cpu_total_compute, client meta or apple-container
+// plugin stanza.
+const ServerConfigCTemplate = `data_dir  = "{{env "STYX_DATA_DIR"}}"
+bind_addr = "0.0.0.0"
+
+advertise {
+  http = "{{env "STYX_ADVERTISE_IP"}}"
+  rpc  = "{{env "STYX_ADVERTISE_IP"}}"
+  serf = "{{env "STYX_ADVERTISE_IP"}}:5648"
+}
+
+server {
+  enabled          = true
+  bootstrap_expect = {{env "STYX_BOOTSTRAP_EXPECT"}}
+}
+
+client {
+  enabled    = true
+  node_class = "server"
+}
+
+plugin_dir = "{{env "STYX_PLUGIN_DIR"}}"
+
+# Vault Integration with Workload Identity
+vault {
+  enabled = true
+  address = "http://127.0.0.1:8200"
+{{- with secret "secret/data/nomad-cluster"}}
+  token   = "{{.Data.data.token}}"
+{{- end}}
+
+  default_identity {
+    aud  = ["vault.io"]
+    env  = false
+    file = true
+    ttl  = "1h"
+  }
+}
+
+telemetry {
+  collection_interval        = "10s"
+  prometheus_metrics         = true
+  publish_allocation_metrics = true
+  publish_node_metrics       = true
+}
+`
+
+// ConsulServerConfigCTemplate is ConsulServerConfigTemplate with the
+// gossip encryption key resolved from Consul KV instead of baked in, so a
+// rotated key propagates to a re-render instead of requiring every node's
+// consul.hcl to be regenerated and redistributed by hand.
+const ConsulServerConfigCTemplate = `data_dir = "{{env "STYX_DATA_DIR"}}"
+bind_addr = "0.0.0.0"
+client_addr = "0.0.0.0"
+advertise_addr = "{{env "STYX_ADVERTISE_IP"}}"
+datacenter = "dc1"
+
+server = true
+bootstrap_expect = {{env "STYX_BOOTSTRAP_EXPECT"}}
+
+ui_config {
+  enabled = true
+}
+
+ports {
+  dns = 8600
+  http = 8500
+  https = 8501
+}
+
+connect {
+  enabled = true
+}
+
+tls {
+  defaults {
+    ca_file   = "{{env "STYX_CA_FILE"}}"
+    cert_file = "{{env "STYX_CERT_FILE"}}"
+    key_file  = "{{env "STYX_KEY_FILE"}}"
+    verify_incoming = true
+    verify_outgoing = true
+  }
+}
+
+encrypt = "{{key "styx/gossip-key"}}"
+
+auto_encrypt {
+  allow_tls = true
+}
+`
+
+// templateConfigFor writes content to a temp .ctmpl file and returns a
+// template.TemplateConfig that renders it to destination, the way each
+// Generate*ConfigLive function hands its source template to the runner.
+func templateConfigFor(name, content, destination string) (template.TemplateConfig, error) {
+	sourceDir := filepath.Join(os.TempDir(), "styx-templates")
+	if err := os.MkdirAll(sourceDir, 0700); err != nil {
+		return template.TemplateConfig{}, fmt.Errorf("failed to create template source dir: %w", err)
+	}
+
+	sourcePath := filepath.Join(sourceDir, name+".ctmpl")
+	if err := os.WriteFile(sourcePath, []byte(content), 0600); err != nil {
+		return template.TemplateConfig{}, fmt.Errorf("failed to write %s source template: %w", name, err)
+	}
+
+	return template.TemplateConfig{
+		Source:      sourcePath,
+		Destination: destination,
+		Perms:       0644,
+	}, nil
+}
+
+func runnerOptionsFor(live LiveRenderOptions, env map[string]string, tc template.TemplateConfig) template.RunnerOptions {
+	return template.RunnerOptions{
+		ConsulAddr: live.ConsulAddr,
+		VaultAddr:  live.VaultAddr,
+		VaultToken: live.VaultToken,
+		Env:        env,
+		Templates:  []template.TemplateConfig{tc},
+	}
+}
+
+// GenerateServerConfigLive renders the server HCL the same way
+// GenerateServerConfig does if live.Enabled is false. Otherwise it renders
+// destination through consul-template, resolving the Vault token live from
+// the nomad-cluster secret instead of baking in cfg.VaultToken.
+func GenerateServerConfigLive(cfg ServerConfig, live LiveRenderOptions, destination string) error {
+	if !live.Enabled {
+		content, err := GenerateServerConfig(cfg)
+		if err != nil {
+			return err
+		}
+		return WriteConfig(destination, content)
+	}
+
+	tc, err := templateConfigFor("nomad-server", ServerConfigCTemplate, destination)
+	if err != nil {
+		return err
+	}
+
+	env := map[string]string{
+		"STYX_DATA_DIR":         cfg.DataDir,
+		"STYX_ADVERTISE_IP":     cfg.AdvertiseIP,
+		"STYX_BOOTSTRAP_EXPECT": fmt.Sprintf("%d", cfg.BootstrapExpect),
+		"STYX_PLUGIN_DIR":       cfg.PluginDir,
+	}
+
+	runner, err := template.NewRunner(runnerOptionsFor(live, env, tc), true)
+	if err != nil {
+		return fmt.Errorf("failed to build template runner for server config: %w", err)
+	}
+	return runner.Once()
+}
+
+// GenerateConsulServerConfigLive is GenerateConsulServerConfig's live
+// counterpart: it resolves the gossip encryption key from Consul KV
+// (styx/gossip-key) instead of baking in cfg.GossipKey.
+func GenerateConsulServerConfigLive(cfg ConsulServerConfig, live LiveRenderOptions, destination string) error {
+	if !live.Enabled {
+		content, err := GenerateConsulServerConfig(cfg)
+		if err != nil {
+			return err
+		}
+		return WriteConfig(destination, content)
+	}
+
+	tc, err := templateConfigFor("consul-server", ConsulServerConfigCTemplate, destination)
+	if err != nil {
+		return err
+	}
+
+	env := map[string]string{
+		"STYX_DATA_DIR":         cfg.DataDir,
+		"STYX_ADVERTISE_IP":     cfg.AdvertiseIP,
+		"STYX_BOOTSTRAP_EXPECT": fmt.Sprintf("%d", cfg.BootstrapExpect),
+		"STYX_CA_FILE":          cfg.CAFile,
+		"STYX_CERT_FILE":        cfg.CertFile,
+		"STYX_KEY_FILE":         cfg.KeyFile,
+	}
+
+	runner, err := template.NewRunner(runnerOptionsFor(live, env, tc), true)
+	if err != nil {
+		return fmt.Errorf("failed to build template runner for consul server config: %w", err)
+	}
+	return runner.Once()
+}
@@ -0,0 +1,25 @@
+package config
+
+import "github.com/kessler-frost/styx/internal/diagnostic"
+
+// Diagnostics collects TemplateRunner's render/signal counters so a
+// diagnostic.Server can expose them at /metrics, the same pattern
+// proxy.Diagnostics and driver.Diagnostics use for their own subsystems.
+var Diagnostics = diagnostic.NewRegistry()
+
+var (
+	// templateRenders counts TemplateRunner renders by outcome.
+	templateRenders = Diagnostics.AddCounter(diagnostic.NewCounterVec(
+		"styx_template_renders_total",
+		"Consul-template renders performed by config.TemplateRunner, by outcome.",
+		"outcome",
+	))
+
+	// templateSignals counts container signals TemplateRunner sends after
+	// a render, by outcome.
+	templateSignals = Diagnostics.AddCounter(diagnostic.NewCounterVec(
+		"styx_template_signals_total",
+		"Container signals sent by config.TemplateRunner after a render, by outcome.",
+		"outcome",
+	))
+)
@@ -0,0 +1,12 @@
+package config
+
+// Region describes one federated Nomad region, for ServerConfig.Regions -
+// see `styx region add`. Nomad federates regions over the same serf pool
+// client/server agents already gossip on (there's no separate "serf_wan"
+// stanza the way Consul has one), so all RetryJoinAddrs does is fold each
+// region's Servers into the same retry_join list this region's own Servers
+// go into.
+type Region struct {
+	Name    string
+	Servers []string
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kessler-frost/styx/driver/container"
+	"github.com/kessler-frost/styx/internal/template"
+)
+
+// TemplateSpec describes one consul-template managed file this
+// TemplateRunner owns: render Source into Dest and, if ContainerID is
+// set, signal that container with Signal (SIGHUP by default) via
+// container.Client.Kill once the render lands on disk. This is how
+// dynamic Consul ACL policies, Connect intentions, and prepared queries
+// (rendered from Consul KV/service data the same way
+// ConsulServerConfigTemplate/ConsulClientConfigTemplate pull in
+// install-time values) reach a running Consul container without
+// restarting it.
+type TemplateSpec struct {
+	Source      string
+	Dest        string
+	ContainerID string
+	Signal      string
+	Perms       os.FileMode
+	LeftDelim   string
+	RightDelim  string
+	Wait        time.Duration
+}
+
+// signal returns spec.Signal, defaulting to SIGHUP - the signal Consul
+// itself reloads its configuration on.
+func (spec TemplateSpec) signal() string {
+	if spec.Signal != "" {
+		return spec.Signal
+	}
+	return "SIGHUP"
+}
+
+// TemplateRunner renders a set of TemplateSpecs through consul-template -
+// one internal/template.Runner per spec, so a render event unambiguously
+// identifies which spec produced it - and signals each spec's container
+// after every re-render. It's the config package's counterpart to
+// GenerateServerConfigLive/GenerateConsulServerConfigLive for templates
+// that aren't one of Styx's own fixed HCL files.
+type TemplateRunner struct {
+	specs  []TemplateSpec
+	live   LiveRenderOptions
+	client *container.Client
+}
+
+// NewTemplateRunner builds a TemplateRunner that renders specs through
+// consul-template against live's Consul/Vault endpoints, signaling
+// containers through client. client may be nil if no spec sets
+// ContainerID.
+func NewTemplateRunner(specs []TemplateSpec, live LiveRenderOptions, client *container.Client) *TemplateRunner {
+	return &TemplateRunner{specs: specs, live: live, client: client}
+}
+
+// Render renders every spec exactly once and returns, the way
+// `consul-template -once` does - suitable for CI or a one-shot `styx
+// template render` across several files at once. It does not signal
+// containers: Render exists to validate output, not to drive a live
+// reload.
+func (r *TemplateRunner) Render(ctx context.Context) error {
+	for _, spec := range r.specs {
+		runner, err := r.newRunner(spec, true)
+		if err != nil {
+			templateRenders.IncLabel("error")
+			return err
+		}
+		if err := runner.Once(); err != nil {
+			templateRenders.IncLabel("error")
+			return fmt.Errorf("failed to render %s: %w", spec.Source, err)
+		}
+		templateRenders.IncLabel("success")
+	}
+	return nil
+}
+
+// Run starts every spec's long-running watch, re-rendering (and
+// signaling its container) on every change, until ctx is cancelled or
+// any spec's watcher errors.
+func (r *TemplateRunner) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(r.specs))
+
+	for _, spec := range r.specs {
+		runner, err := r.newRunner(spec, false)
+		if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func(spec TemplateSpec, runner *template.Runner) {
+			defer wg.Done()
+			err := runner.WatchWithCallback(ctx, func() {
+				templateRenders.IncLabel("success")
+				r.signal(ctx, spec)
+			})
+			if err != nil && ctx.Err() == nil {
+				errCh <- fmt.Errorf("%s: %w", spec.Source, err)
+			}
+		}(spec, runner)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signal sends spec's configured signal to its container, counting the
+// outcome. A failed signal doesn't stop Run - the next render retries it.
+func (r *TemplateRunner) signal(ctx context.Context, spec TemplateSpec) {
+	if spec.ContainerID == "" || r.client == nil {
+		return
+	}
+	if err := r.client.Kill(ctx, spec.ContainerID, spec.signal()); err != nil {
+		templateSignals.IncLabel("error")
+		return
+	}
+	templateSignals.IncLabel("success")
+}
+
+// newRunner builds the internal/template.Runner backing spec. once
+// matches template.NewRunner's own once parameter: true for Render's
+// single-pass use, false for Run's long-lived watch.
+func (r *TemplateRunner) newRunner(spec TemplateSpec, once bool) (*template.Runner, error) {
+	perms := spec.Perms
+	if perms == 0 {
+		perms = 0644
+	}
+
+	opts := template.RunnerOptions{
+		ConsulAddr: r.live.ConsulAddr,
+		VaultAddr:  r.live.VaultAddr,
+		VaultToken: r.live.VaultToken,
+		Templates: []template.TemplateConfig{{
+			Source:      spec.Source,
+			Destination: spec.Dest,
+			Perms:       perms,
+			LeftDelim:   spec.LeftDelim,
+			RightDelim:  spec.RightDelim,
+			Wait:        spec.Wait,
+		}},
+	}
+
+	runner, err := template.NewRunner(opts, once)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build template runner for %s: %w", spec.Source, err)
+	}
+	return runner, nil
+}
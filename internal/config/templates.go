@@ -5,6 +5,9 @@ package config
 // Transport encryption is handled by Tailscale (no TLS/Consul needed).
 const ServerConfigTemplate = `data_dir  = "{{.DataDir}}"
 bind_addr = "0.0.0.0"
+{{- if .Region}}
+region    = "{{.Region}}"
+{{- end}}
 
 advertise {
   http = "{{.AdvertiseIP}}"
@@ -15,6 +18,12 @@ advertise {
 server {
   enabled          = true
   bootstrap_expect = {{.BootstrapExpect}}
+{{- if or .Servers .Regions}}
+
+  server_join {
+    retry_join = [{{range $i, $a := .RetryJoinAddrs "5648"}}{{if $i}}, {{end}}"{{$a}}"{{end}}]
+  }
+{{- end}}
 }
 
 client {
@@ -23,6 +32,10 @@ client {
 
   # Override CPU fingerprinting (apple-container driver doesn't report resources correctly)
   cpu_total_compute = {{.CPUTotalCompute}}
+
+  meta {
+    cluster_id = "{{.ClusterID}}"
+  }
 }
 
 plugin_dir = "{{.PluginDir}}"
@@ -37,6 +50,9 @@ plugin "apple-container" {
 vault {
   enabled = true
   address = "http://127.0.0.1:8200"
+{{- if .VaultToken}}
+  token   = "{{.VaultToken}}"
+{{- end}}
 
   # Workload identity configuration for Nomad 1.7+
   default_identity {
@@ -60,6 +76,9 @@ telemetry {
 // Transport encryption is handled by Tailscale (no TLS/Consul needed).
 const ClientConfigTemplate = `data_dir  = "{{.DataDir}}"
 bind_addr = "0.0.0.0"
+{{- if .Region}}
+region    = "{{.Region}}"
+{{- end}}
 
 advertise {
   http = "{{.AdvertiseIP}}"
@@ -0,0 +1,224 @@
+// Package diagnostic provides a Registry of Prometheus-style gauges,
+// counters, and histograms that components (bootstrap.Server, the Nomad
+// driver's taskHandle, the TUI's api.Client) can update from their own code
+// paths, plus a Server that exposes them - alongside /healthz, /readyz, and
+// the standard net/http/pprof profiler - on a listener separate from
+// whatever the component itself serves. Modeled after Teleport's
+// ComponentDiagnostic.
+package diagnostic
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Gauge is a concurrency-safe point-in-time value, e.g. "containers
+// currently running".
+type Gauge struct {
+	name string
+	help string
+	v    int64
+}
+
+// NewGauge creates a Gauge starting at zero.
+func NewGauge(name, help string) *Gauge {
+	return &Gauge{name: name, help: help}
+}
+
+// Inc increments the gauge by one.
+func (g *Gauge) Inc() { atomic.AddInt64(&g.v, 1) }
+
+// Dec decrements the gauge by one.
+func (g *Gauge) Dec() { atomic.AddInt64(&g.v, -1) }
+
+// Set sets the gauge to v.
+func (g *Gauge) Set(v int64) { atomic.StoreInt64(&g.v, v) }
+
+func (g *Gauge) writeTo(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", g.name, g.help, g.name, g.name, atomic.LoadInt64(&g.v))
+}
+
+// Counter is a monotonically increasing value, optionally broken down by a
+// single label (e.g. "outcome" for driver container exit events) the same
+// way observability.Metrics breaks latency down by "path".
+type Counter struct {
+	name      string
+	help      string
+	labelName string // empty means this counter has no label dimension
+
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// NewCounter creates a Counter with no label dimension.
+func NewCounter(name, help string) *Counter {
+	return &Counter{name: name, help: help, counts: make(map[string]uint64)}
+}
+
+// NewCounterVec creates a Counter whose values are broken down by label,
+// incremented via IncLabel.
+func NewCounterVec(name, help, labelName string) *Counter {
+	return &Counter{name: name, help: help, labelName: labelName, counts: make(map[string]uint64)}
+}
+
+// Inc increments an unlabeled Counter by one.
+func (c *Counter) Inc() { c.add("", 1) }
+
+// IncLabel increments a CounterVec's value for label by one.
+func (c *Counter) IncLabel(label string) { c.add(label, 1) }
+
+// AddLabel increments a CounterVec's value for label by n, for counters that
+// accumulate something other than one-per-event (e.g. bytes transferred).
+func (c *Counter) AddLabel(label string, n uint64) { c.add(label, n) }
+
+func (c *Counter) add(label string, n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[label] += n
+}
+
+func (c *Counter) writeTo(w io.Writer) {
+	c.mu.Lock()
+	labels := make([]string, 0, len(c.counts))
+	for l := range c.counts {
+		labels = append(labels, l)
+	}
+	counts := make(map[string]uint64, len(c.counts))
+	for l, n := range c.counts {
+		counts[l] = n
+	}
+	c.mu.Unlock()
+
+	if len(labels) == 0 {
+		return
+	}
+	sort.Strings(labels)
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, l := range labels {
+		if c.labelName == "" {
+			fmt.Fprintf(w, "%s %d\n", c.name, counts[l])
+		} else {
+			fmt.Fprintf(w, "%s{%s=%q} %d\n", c.name, c.labelName, l, counts[l])
+		}
+	}
+}
+
+// Histogram buckets duration observations, e.g. time-to-exit-detection for
+// a container. Unlike observability.Metrics' HTTP latency histograms (which
+// bucket milliseconds per request path), a Histogram here tracks seconds
+// for a single named metric.
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64 // upper bounds, in seconds
+
+	mu     sync.Mutex
+	counts []uint64 // cumulative count per bucket, one per buckets entry
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram creates a Histogram with the given bucket upper bounds
+// (seconds), which must be sorted ascending.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return &Histogram{name: name, help: help, buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+// Observe records a duration observation.
+func (h *Histogram) Observe(d time.Duration) {
+	seconds := d.Seconds()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, bound := range h.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (h *Histogram) writeTo(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for i, bound := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", h.name, strconv.FormatFloat(bound, 'f', -1, 64), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.count)
+	fmt.Fprintf(w, "%s_sum %g\n", h.name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", h.name, h.count)
+}
+
+// Registry collects a component's gauges, counters, and histograms so a
+// Server can render them all at /metrics in one pass.
+type Registry struct {
+	mu         sync.Mutex
+	gauges     []*Gauge
+	counters   []*Counter
+	histograms []*Histogram
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// AddGauge registers g and returns it, for fluent construction:
+//
+//	running := registry.AddGauge(diagnostic.NewGauge("containers_running", "..."))
+func (r *Registry) AddGauge(g *Gauge) *Gauge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gauges = append(r.gauges, g)
+	return g
+}
+
+// AddCounter registers c and returns it.
+func (r *Registry) AddCounter(c *Counter) *Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters = append(r.counters, c)
+	return c
+}
+
+// AddHistogram registers h and returns it.
+func (r *Registry) AddHistogram(h *Histogram) *Histogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.histograms = append(r.histograms, h)
+	return h
+}
+
+// WriteTo renders every registered metric in Prometheus text exposition
+// format.
+func (r *Registry) WriteTo(w io.Writer) {
+	r.mu.Lock()
+	gauges := append([]*Gauge(nil), r.gauges...)
+	counters := append([]*Counter(nil), r.counters...)
+	histograms := append([]*Histogram(nil), r.histograms...)
+	r.mu.Unlock()
+
+	for _, g := range gauges {
+		g.writeTo(w)
+	}
+	for _, c := range counters {
+		c.writeTo(w)
+	}
+	for _, h := range histograms {
+		h.writeTo(w)
+	}
+}
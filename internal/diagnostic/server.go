@@ -0,0 +1,130 @@
+package diagnostic
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"sync/atomic"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/observability"
+	"github.com/kessler-frost/styx/internal/supervisor"
+)
+
+// Compile-time assertion that Server implements supervisor.Service.
+var _ supervisor.Service = (*Server)(nil)
+
+// Server is a diagnostic HTTP endpoint: a listener separate from whatever
+// the component it's diagnosing serves (loopback by default, but any addr
+// works - including a Tailscale IP, to reach it from other nodes) exposing
+// Prometheus metrics, liveness/readiness probes, and Go's standard pprof
+// profiler. Keeping it off the component's own mux means a heavy pprof
+// profile or a Prometheus scrape can't contend with real request traffic.
+type Server struct {
+	addr        string
+	registry    *Registry
+	httpMetrics *observability.Metrics // optional: an HTTP component's per-path latency histograms
+
+	ready int32 // atomic bool: 1 once Serve has started listening, toggled further by SetReady
+
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewServer creates a diagnostic server bound to addr. It doesn't listen
+// until Serve runs, typically under a supervisor.Tree. registry holds
+// whatever component-specific gauges/counters/histograms the caller has set
+// up; pass diagnostic.NewRegistry() if there's nothing beyond /healthz,
+// /readyz, and pprof.
+func NewServer(addr string, registry *Registry) *Server {
+	return &Server{addr: addr, registry: registry}
+}
+
+// WithHTTPMetrics mounts m's per-path latency histograms (e.g. from
+// bootstrap.Server.Metrics or api.Client.Metrics) onto this server's
+// /metrics output alongside registry's gauges/counters/histograms.
+func (s *Server) WithHTTPMetrics(m *observability.Metrics) *Server {
+	s.httpMetrics = m
+	return s
+}
+
+// SetReady controls /readyz's response, for callers that want it to report
+// unready until some startup step finishes (e.g. an initial cluster join).
+// Serve defaults it to ready as soon as the listener is up.
+func (s *Server) SetReady(ready bool) {
+	v := int32(0)
+	if ready {
+		v = 1
+	}
+	atomic.StoreInt32(&s.ready, v)
+}
+
+// Addr returns the address the server is actually listening on, useful when
+// addr was given as "host:0" for an OS-assigned port.
+func (s *Server) Addr() string {
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+	return s.addr
+}
+
+// Serve implements supervisor.Service: it listens on addr and serves
+// /metrics, /healthz, /readyz, and the standard net/http/pprof endpoints
+// until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+	s.listener = listener
+	s.SetReady(true)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.serveMetrics)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/readyz", s.serveReadyz)
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	s.server = &http.Server{Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.server.Serve(listener) }()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.server.Shutdown(shutdownCtx)
+		return nil
+	case err := <-errCh:
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	}
+}
+
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.registry.WriteTo(w)
+	if s.httpMetrics != nil {
+		s.httpMetrics.WriteTo(w)
+	}
+}
+
+func (s *Server) serveReadyz(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&s.ready) == 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("not ready"))
+		return
+	}
+	w.Write([]byte("ok"))
+}
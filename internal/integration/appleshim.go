@@ -0,0 +1,60 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appleShimScript is a drop-in stand-in for the macOS-only Apple `container`
+// CLI, translating the subset driver/container.Client shells out to (run,
+// stop, rm, inspect, list) onto `docker`, which is what's actually
+// available in CI. It exists so driver.Driver/container.Client can be
+// exercised end-to-end on Linux instead of being skipped entirely outside
+// a real Mac.
+const appleShimScript = `#!/bin/sh
+set -e
+cmd="$1"
+shift
+case "$cmd" in
+  run)
+    exec docker run "$@"
+    ;;
+  stop)
+    exec docker stop "$@"
+    ;;
+  rm)
+    exec docker rm "$@"
+    ;;
+  inspect)
+    exec docker inspect "$@"
+    ;;
+  list)
+    exec docker ps --format '{{json .}}'
+    ;;
+  *)
+    echo "apple container shim: unsupported subcommand $cmd" >&2
+    exit 1
+    ;;
+esac
+`
+
+// NewAppleContainerShim writes appleShimScript to a temp file and returns
+// its path, suitable for container.NewClient(binPath) so tests can drive
+// the real driver/container.Client code paths against `docker` instead of
+// the real Apple container runtime.
+func NewAppleContainerShim() (string, error) {
+	dir, err := os.MkdirTemp("", "styx-integration-container-shim")
+	if err != nil {
+		return "", fmt.Errorf("failed to create shim dir: %w", err)
+	}
+
+	path := filepath.Join(dir, "container")
+	if err := os.WriteFile(path, []byte(appleShimScript), 0755); err != nil {
+		return "", fmt.Errorf("failed to write shim script: %w", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,111 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/api"
+	"github.com/kessler-frost/styx/internal/services"
+)
+
+// TestGetJobsAndNodes registers a job against a live Nomad agent and
+// asserts api.Client.GetJobs/GetNodes see it, the code path `styx status`
+// drives that has no other coverage today.
+func TestGetJobsAndNodes(t *testing.T) {
+	scenario, err := Launch(ScenarioOptions{})
+	if err != nil {
+		t.Fatalf("failed to launch scenario: %v", err)
+	}
+	defer scenario.Terminate()
+
+	node := scenario.Nodes[0]
+	nomad := services.NewNomadClient(node.NomadAddr())
+
+	if err := nomad.RunJob(rawEchoJob); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	client := api.NewClient().WithNomadAddr(node.NomadAddr())
+
+	jobs, err := client.GetJobs("")
+	if err != nil {
+		t.Fatalf("GetJobs failed: %v", err)
+	}
+	if len(jobs) == 0 {
+		t.Fatalf("expected at least one job, got none")
+	}
+
+	nodes, err := client.GetNodes("")
+	if err != nil {
+		t.Fatalf("GetNodes failed: %v", err)
+	}
+	if len(nodes) == 0 {
+		t.Fatalf("expected at least one node, got none")
+	}
+}
+
+// TestStopAllJobsFlow mirrors cmd/styx/stop.go's stopAllJobs: list every
+// job and stop the ones still running, against a live Nomad agent instead
+// of a mock.
+func TestStopAllJobsFlow(t *testing.T) {
+	scenario, err := Launch(ScenarioOptions{})
+	if err != nil {
+		t.Fatalf("failed to launch scenario: %v", err)
+	}
+	defer scenario.Terminate()
+
+	node := scenario.Nodes[0]
+	nomad := services.NewNomadClient(node.NomadAddr())
+
+	if err := nomad.RunJob(rawEchoJob); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	jobs, err := nomad.ListJobs()
+	if err != nil {
+		t.Fatalf("ListJobs failed: %v", err)
+	}
+	if len(jobs) == 0 {
+		t.Fatalf("expected at least one job, got none")
+	}
+
+	for _, job := range jobs {
+		if job.Status == "dead" {
+			continue
+		}
+		if err := nomad.StopJob(job.ID); err != nil {
+			t.Fatalf("StopJob(%s) failed: %v", job.ID, err)
+		}
+	}
+
+	time.Sleep(2 * time.Second)
+
+	status, err := nomad.GetJobStatus("echo")
+	if err != nil {
+		t.Fatalf("GetJobStatus failed: %v", err)
+	}
+	if status == nil || status.Status != "dead" {
+		t.Fatalf("expected job to be dead after stop, got %+v", status)
+	}
+}
+
+// rawEchoJob is a minimal raw_exec job used to exercise the Nomad API
+// surface without needing a real container runtime in the scenario.
+const rawEchoJob = `
+job "echo" {
+  datacenters = ["dc1"]
+  type        = "service"
+
+  group "echo" {
+    task "echo" {
+      driver = "raw_exec"
+      config {
+        command = "/bin/sh"
+        args    = ["-c", "sleep 300"]
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,87 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ory/dockertest/v3"
+)
+
+// StyxNode is one simulated Mac: a Nomad agent and a tailscaled, each its
+// own container on the Scenario's network, standing in for the two
+// long-running processes `styx init` launches on a real machine.
+type StyxNode struct {
+	nomad      *dockertest.Resource
+	tailscaled *dockertest.Resource
+	alias      string
+}
+
+func launchNode(pool *dockertest.Pool, network *dockertest.Network, opts ScenarioOptions, index int) (*StyxNode, error) {
+	alias := fmt.Sprintf("styx-node-%d", index)
+
+	tailscaled, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Name:       alias + "-tailscaled",
+		Repository: "tailscale/tailscale",
+		Tag:        opts.TailscaleVersion,
+		Networks:   []*dockertest.Network{network},
+		Entrypoint: []string{"tailscaled"},
+		Cmd:        []string{"--tun=userspace-networking", "--socket=/var/run/tailscale/tailscaled.sock"},
+		CapAdd:     []string{"NET_ADMIN"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start tailscaled: %w", err)
+	}
+
+	nomad, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Name:         alias + "-nomad",
+		Repository:   "hashicorp/nomad",
+		Tag:          opts.NomadVersion,
+		Networks:     []*dockertest.Network{network},
+		Cmd:          []string{"agent", "-dev", "-bind=0.0.0.0"},
+		ExposedPorts: []string{"4646/tcp"},
+	})
+	if err != nil {
+		pool.Purge(tailscaled)
+		return nil, fmt.Errorf("failed to start nomad: %w", err)
+	}
+
+	node := &StyxNode{nomad: nomad, tailscaled: tailscaled, alias: alias}
+	if err := pool.Retry(func() error {
+		if !node.nomadHealthy() {
+			return fmt.Errorf("nomad agent not yet healthy")
+		}
+		return nil
+	}); err != nil {
+		node.terminate(pool)
+		return nil, fmt.Errorf("nomad agent never became healthy: %w", err)
+	}
+
+	return node, nil
+}
+
+// NomadAddr is this node's externally-mapped Nomad HTTP address, for
+// pointing an api.Client or services.NomadClient at it from the host.
+func (n *StyxNode) NomadAddr() string {
+	return "http://" + n.nomad.GetHostPort("4646/tcp")
+}
+
+func (n *StyxNode) nomadHealthy() bool {
+	resp, err := http.Get(n.NomadAddr() + "/v1/agent/health")
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+func (n *StyxNode) terminate(pool *dockertest.Pool) error {
+	if err := pool.Purge(n.nomad); err != nil {
+		return fmt.Errorf("failed to purge nomad container: %w", err)
+	}
+	if err := pool.Purge(n.tailscaled); err != nil {
+		return fmt.Errorf("failed to purge tailscaled container: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package intents
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parse reads a small subset of HCL: zero or more blocks of the form
+//
+//	intent {
+//	  source      = "grafana"
+//	  destination = "loki"
+//	  action      = "allow"
+//	}
+//
+// This is a hand-rolled reader rather than a general HCL parser, the same
+// way internal/api/filter.go hand-rolls its own expression tokenizer rather
+// than pulling in a full expression-language dependency for three fields.
+func parse(data string) ([]Intent, error) {
+	var list []Intent
+	var cur *Intent
+
+	for i, raw := range strings.Split(data, "\n") {
+		line := strings.TrimSpace(raw)
+		lineNo := i + 1
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case line == "intent {":
+			if cur != nil {
+				return nil, fmt.Errorf("line %d: nested intent block", lineNo)
+			}
+			cur = &Intent{}
+		case line == "}":
+			if cur == nil {
+				return nil, fmt.Errorf("line %d: unexpected closing brace", lineNo)
+			}
+			list = append(list, *cur)
+			cur = nil
+		default:
+			if cur == nil {
+				return nil, fmt.Errorf("line %d: expected 'intent {', got %q", lineNo, line)
+			}
+			key, value, err := parseField(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			switch key {
+			case "source":
+				cur.Source = value
+			case "destination":
+				cur.Destination = value
+			case "action":
+				cur.Action = value
+			default:
+				return nil, fmt.Errorf("line %d: unknown field %q", lineNo, key)
+			}
+		}
+	}
+
+	if cur != nil {
+		return nil, fmt.Errorf("unterminated intent block")
+	}
+
+	return list, nil
+}
+
+func parseField(line string) (key, value string, err error) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("expected 'field = \"value\"', got %q", line)
+	}
+
+	key = strings.TrimSpace(parts[0])
+	value = strings.TrimSpace(parts[1])
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return "", "", fmt.Errorf("expected a quoted string value, got %q", value)
+	}
+	return key, value[1 : len(value)-1], nil
+}
+
+// render formats intents back into the block format parse reads.
+func render(list []Intent) string {
+	if len(list) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, in := range list {
+		fmt.Fprintf(&b, "intent {\n  source      = %q\n  destination = %q\n  action      = %q\n}\n\n", in.Source, in.Destination, in.Action)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
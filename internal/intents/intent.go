@@ -0,0 +1,14 @@
+// Package intents implements a Consul-style service intentions layer over
+// Nomad's service provider: a declarative allow/deny list between platform
+// services, compiled into Traefik ipallowlist middleware tags so Styx gets
+// zero-trust east-west traffic control without needing Consul Connect.
+package intents
+
+// Intent is a single allow/deny rule for traffic from Source to
+// Destination, where both name Nomad-provider services (see
+// services.PlatformServices).
+type Intent struct {
+	Source      string
+	Destination string
+	Action      string // "allow" or "deny"
+}
@@ -0,0 +1,61 @@
+package intents
+
+import (
+	"fmt"
+	"os"
+)
+
+// Load reads the intent list from path (conventionally
+// configDir/intents.hcl). A missing file is not an error: it just means no
+// intents are configured yet, so every service reaches every other service
+// as before intents existed.
+func Load(path string) ([]Intent, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	list, err := parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Save writes list back to path in the same block format Load reads.
+func Save(path string, list []Intent) error {
+	if err := os.WriteFile(path, []byte(render(list)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Upsert loads the intent list at path, replaces any existing intent for
+// the same (source, destination) pair with action (or appends a new one),
+// saves it back, and returns the updated list.
+func Upsert(path, source, destination, action string) ([]Intent, error) {
+	list, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i := range list {
+		if list[i].Source == source && list[i].Destination == destination {
+			list[i].Action = action
+			found = true
+			break
+		}
+	}
+	if !found {
+		list = append(list, Intent{Source: source, Destination: destination, Action: action})
+	}
+
+	if err := Save(path, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
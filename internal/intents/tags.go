@@ -0,0 +1,97 @@
+package intents
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MiddlewareTags compiles the intents targeting destination into the
+// Traefik tags that restrict east-west access to it: an ipallowlist
+// middleware built from the allow-listed sources' registered addresses
+// (resolved via resolve), plus a router rule attaching that middleware.
+//
+// If no intent names destination, it returns (nil, nil): destination keeps
+// its existing wide-open access, same as before intents existed. A deny
+// intent removes its source from the allowlist built from the rest of
+// destination's intents; a deny with no allow intents for the same
+// destination still returns both tags, with an empty sourcerange, so no
+// source is allow-listed.
+func MiddlewareTags(list []Intent, destination string, resolve func(service string) ([]string, error)) ([]string, error) {
+	var allowed []string
+	referenced := false
+
+	for _, in := range list {
+		if in.Destination != destination || in.Action != "allow" {
+			continue
+		}
+		referenced = true
+
+		addrs, err := resolve(in.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve source %q: %w", in.Source, err)
+		}
+		for _, a := range addrs {
+			allowed = append(allowed, a+"/32")
+		}
+	}
+
+	for _, in := range list {
+		if in.Destination == destination && in.Action == "deny" {
+			referenced = true
+		}
+	}
+
+	if !referenced {
+		return nil, nil
+	}
+
+	return []string{
+		fmt.Sprintf("traefik.http.middlewares.%s-intent.ipallowlist.sourcerange=%s", destination, strings.Join(allowed, ",")),
+		fmt.Sprintf("traefik.http.routers.%s.middlewares=%s-intent", destination, destination),
+	}, nil
+}
+
+// ApplyTags inserts extraTags into the tags = [...] array of serviceName's
+// service stanza inside hcl (a generated Nomad job spec; see
+// services.PlatformServices), so intent-derived tags ride along with
+// whatever Traefik tags the job already declares. If serviceName has no
+// tags array in hcl, hcl is returned unchanged.
+func ApplyTags(hcl, serviceName string, extraTags []string) string {
+	if len(extraTags) == 0 {
+		return hcl
+	}
+
+	nameMarker := fmt.Sprintf(`name         = "%s"`, serviceName)
+	nameIdx := strings.Index(hcl, nameMarker)
+	if nameIdx == -1 {
+		return hcl
+	}
+
+	tagsMarker := "tags = [\n"
+	tagsIdx := strings.Index(hcl[nameIdx:], tagsMarker)
+	if tagsIdx == -1 {
+		return hcl
+	}
+	tagsIdx += nameIdx + len(tagsMarker)
+
+	closeMarker := "\n        ]"
+	closeIdx := strings.Index(hcl[tagsIdx:], closeMarker)
+	if closeIdx == -1 {
+		return hcl
+	}
+	closeIdx += tagsIdx
+
+	existing := strings.TrimRight(hcl[tagsIdx:closeIdx], " \n")
+
+	var b strings.Builder
+	b.WriteString(hcl[:tagsIdx])
+	b.WriteString(existing)
+	if existing != "" && !strings.HasSuffix(existing, ",") {
+		b.WriteString(",")
+	}
+	for _, t := range extraTags {
+		fmt.Fprintf(&b, "\n          %q,", t)
+	}
+	b.WriteString(hcl[closeIdx:])
+	return b.String()
+}
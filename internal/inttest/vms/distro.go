@@ -0,0 +1,26 @@
+//go:build vmtest
+
+package vms
+
+// imageMeta is where to fetch a distro's base cloud image and the
+// checksum cachedImage verifies it against before reusing it.
+type imageMeta struct {
+	url    string
+	sha256 string
+}
+
+// distroImages maps a Distro to its base image. These are placeholders
+// for the real pinned image URLs/checksums an operator would supply
+// alongside a vendored testdata/ manifest; see buildSeedISO for how the
+// styx binary gets layered on
+// top at VM creation time instead of baked into the image itself.
+var distroImages = map[Distro]imageMeta{
+	DistroDebian12: {
+		url:    "https://cloud.debian.org/images/cloud/bookworm/latest/debian-12-generic-amd64.qcow2",
+		sha256: "0000000000000000000000000000000000000000000000000000000000000",
+	},
+	DistroMacOS: {
+		url:    "https://distro.ibiblio.org/styx/macos-15-lima.qcow2",
+		sha256: "0000000000000000000000000000000000000000000000000000000000000",
+	},
+}
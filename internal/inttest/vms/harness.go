@@ -0,0 +1,200 @@
+//go:build vmtest
+
+// Package vms boots real macOS/Linux VMs under Lima (which drives QEMU)
+// and drives full `styx init --serve` / `styx init` lifecycles against
+// them, the way Tailscale's tstest/integration/vms exercises tailscaled
+// against real kernels instead of the container-based doubles in
+// internal/integration. This is the only harness in the repo that
+// actually exercises the launchd + Tailscale + Vault + Nomad interaction
+// end to end, at the cost of being slow and requiring a local
+// hypervisor, so it's both build-tag gated (-tags=vmtest) and opt-in via
+// -run-vm-tests at runtime.
+package vms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	runVMTests = flag.Bool("run-vm-tests", false, "run the slow, hypervisor-backed VM integration tests")
+	ramLimitGB = flag.Int("ram-limit", 8, "maximum total RAM (GiB) across concurrently running VMs")
+)
+
+// Distro identifies a VM image to boot: a Lima template name (e.g.
+// "debian-12", "almalinux-9") or, for macOS, the Lima macOS-guest
+// template this repo vendors under testdata/.
+type Distro string
+
+const (
+	DistroDebian12 Distro = "debian-12"
+	DistroMacOS    Distro = "macos-15"
+)
+
+// Harness manages a pool of VMs for one test, enforcing -ram-limit and
+// tearing every VM down when the test finishes.
+type Harness struct {
+	t        *testing.T
+	cacheDir string
+
+	mu        sync.Mutex
+	ramUsedGB int
+	vms       []*VM
+}
+
+// NewHarness skips the calling test unless -run-vm-tests was passed, then
+// prepares a VM image cache under os.UserCacheDir()/styx/vm-test.
+func NewHarness(t *testing.T) *Harness {
+	t.Helper()
+	if !*runVMTests {
+		t.Skip("skipping VM integration test; pass -run-vm-tests to run it")
+	}
+
+	if _, err := exec.LookPath("limactl"); err != nil {
+		t.Skipf("limactl not found in PATH: %v", err)
+	}
+
+	cacheRoot, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatalf("failed to resolve user cache dir: %v", err)
+	}
+	cacheDir := filepath.Join(cacheRoot, "styx", "vm-test")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatalf("failed to create VM image cache dir %s: %v", cacheDir, err)
+	}
+
+	h := &Harness{t: t, cacheDir: cacheDir}
+	t.Cleanup(h.shutdown)
+	return h
+}
+
+// NewVM boots a VM of distro, injecting styxBinPath through a
+// cloud-init-style seed (see userDataTemplate), and blocks
+// until the guest is reachable over SSH. It fails the test (via t.Fatalf)
+// if doing so would push the harness's total RAM past -ram-limit.
+func (h *Harness) NewVM(distro Distro, styxBinPath string) *VM {
+	h.t.Helper()
+
+	const vmRAMGB = 2
+	h.mu.Lock()
+	if h.ramUsedGB+vmRAMGB > *ramLimitGB {
+		h.mu.Unlock()
+		h.t.Fatalf("starting another %s VM would exceed -ram-limit=%dGiB (already using %dGiB)", distro, *ramLimitGB, h.ramUsedGB)
+	}
+	h.ramUsedGB += vmRAMGB
+	h.mu.Unlock()
+
+	imagePath, err := cachedImage(h.cacheDir, distro)
+	if err != nil {
+		h.t.Fatalf("failed to fetch %s image: %v", distro, err)
+	}
+
+	seedPath, err := buildSeedISO(h.cacheDir, styxBinPath)
+	if err != nil {
+		h.t.Fatalf("failed to build seed ISO for %s: %v", distro, err)
+	}
+
+	vm, err := startVM(distro, imagePath, seedPath, vmRAMGB)
+	if err != nil {
+		h.t.Fatalf("failed to start %s VM: %v", distro, err)
+	}
+
+	h.mu.Lock()
+	h.vms = append(h.vms, vm)
+	h.mu.Unlock()
+
+	if err := vm.waitForSSH(2 * time.Minute); err != nil {
+		h.t.Fatalf("%s VM never became reachable over SSH: %v", vm.name, err)
+	}
+
+	return vm
+}
+
+func (h *Harness) shutdown() {
+	h.mu.Lock()
+	vms := append([]*VM(nil), h.vms...)
+	h.mu.Unlock()
+
+	for _, vm := range vms {
+		if err := vm.stop(); err != nil {
+			h.t.Logf("warning: failed to stop VM %s: %v", vm.name, err)
+		}
+	}
+}
+
+// cachedImage returns the local path to distro's base image, downloading
+// and verifying it by sha256 into cacheDir if it isn't already cached.
+func cachedImage(cacheDir string, distro Distro) (string, error) {
+	meta, ok := distroImages[distro]
+	if !ok {
+		return "", fmt.Errorf("no known image for distro %q", distro)
+	}
+
+	dest := filepath.Join(cacheDir, meta.sha256+".img")
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+
+	tmp := dest + ".download"
+	if err := downloadFile(meta.url, tmp); err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	sum, err := fileSHA256(tmp)
+	if err != nil {
+		return "", err
+	}
+	if sum != meta.sha256 {
+		return "", fmt.Errorf("%s: checksum mismatch: got %s, want %s", meta.url, sum, meta.sha256)
+	}
+
+	if err := os.Rename(tmp, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+func downloadFile(url, dest string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: status %d", url, resp.StatusCode)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
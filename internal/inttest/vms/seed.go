@@ -0,0 +1,58 @@
+//go:build vmtest
+
+package vms
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// userDataTemplate is the cloud-init user-data written into every seed
+// ISO: it installs the injected styx binary at /usr/local/bin/styx and
+// symlinks it as /usr/local/bin/nomad-stub. It installs no Tailscale or
+// Vault binaries.
+const userDataTemplate = `#cloud-config
+write_files:
+  - path: /usr/local/bin/styx
+    permissions: '0755'
+    encoding: b64
+    content: %s
+runcmd:
+  - [ ln, -sf, /usr/local/bin/styx, /usr/local/bin/nomad-stub ]
+`
+
+// buildSeedISO assembles a cloud-init seed ISO (user-data + meta-data)
+// under cacheDir carrying styxBinPath, and returns its path. The caller
+// attaches it to the VM as its cidata volume.
+func buildSeedISO(cacheDir, styxBinPath string) (string, error) {
+	seedDir, err := os.MkdirTemp(cacheDir, "seed-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create seed working dir: %w", err)
+	}
+	defer os.RemoveAll(seedDir)
+
+	styxBin, err := os.ReadFile(styxBinPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read styx binary at %s: %w", styxBinPath, err)
+	}
+
+	userData := fmt.Sprintf(userDataTemplate, base64.StdEncoding.EncodeToString(styxBin))
+	if err := os.WriteFile(filepath.Join(seedDir, "user-data"), []byte(userData), 0644); err != nil {
+		return "", fmt.Errorf("failed to write user-data: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(seedDir, "meta-data"), []byte("instance-id: styx-vmtest\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write meta-data: %w", err)
+	}
+
+	isoPath := filepath.Join(cacheDir, fmt.Sprintf("seed-%d.iso", os.Getpid()))
+	cmd := exec.Command("mkisofs", "-output", isoPath, "-volid", "cidata", "-joliet", "-rock",
+		filepath.Join(seedDir, "user-data"), filepath.Join(seedDir, "meta-data"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("mkisofs failed: %w: %s", err, out)
+	}
+
+	return isoPath, nil
+}
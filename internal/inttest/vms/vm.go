@@ -0,0 +1,115 @@
+//go:build vmtest
+
+package vms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"time"
+)
+
+// VM is one running Lima instance, standing in for one real Mac in a
+// cluster: `styx init --serve` runs on one, `styx init` on the others.
+type VM struct {
+	name      string
+	distro    Distro
+	nomadPort int
+}
+
+// startVM launches distro under limactl with baseImage as its disk and
+// seedISO attached as a cloud-init-style seed (carrying the styx binary).
+// It records Nomad's default port 4646 for the VM and sets up no port
+// forwarding.
+func startVM(distro Distro, baseImage, seedISO string, ramGB int) (*VM, error) {
+	name := fmt.Sprintf("styx-vmtest-%s-%d", distro, time.Now().UnixNano())
+
+	cmd := exec.Command("limactl", "start",
+		"--name="+name,
+		"--tty=false",
+		fmt.Sprintf("--memory=%d", ramGB),
+		"--set=.images=[{\"location\":\""+baseImage+"\"}]",
+		"--set=.mounts=[]",
+		"--cidata-path="+seedISO,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("limactl start %s failed: %w: %s", name, err, stderr.String())
+	}
+
+	vm := &VM{name: name, distro: distro, nomadPort: 4646}
+	return vm, nil
+}
+
+// waitForSSH blocks until limactl reports the VM as reachable over SSH,
+// or timeout elapses.
+func (vm *VM) waitForSSH(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, err := vm.SSH("true"); err == nil {
+			return nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return fmt.Errorf("timed out waiting for %s to accept SSH connections", vm.name)
+}
+
+// SSH runs cmd inside the VM via `limactl shell` and returns its combined
+// output.
+func (vm *VM) SSH(cmd string) (string, error) {
+	out, err := exec.Command("limactl", "shell", vm.name, "--", "sh", "-c", cmd).CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("limactl shell %s -- %q: %w", vm.name, cmd, err)
+	}
+	return string(out), nil
+}
+
+// WaitForNomadNode polls this VM's Nomad agent until a node named
+// nodeName appears with status "ready", or returns an error after
+// timeout. Used to assert that a `styx init` client actually auto-
+// discovered and joined the server VM's cluster.
+func (vm *VM) WaitForNomadNode(nodeName string, timeout time.Duration) error {
+	addr, err := vm.forwardedNomadAddr()
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(fmt.Sprintf("http://%s/v1/nodes?prefix=%s", addr, nodeName))
+		if err == nil {
+			var nodes []struct{ Name, Status string }
+			if resp.StatusCode == http.StatusOK {
+				json.NewDecoder(resp.Body).Decode(&nodes)
+			}
+			resp.Body.Close()
+			for _, n := range nodes {
+				if n.Name == nodeName && n.Status == "ready" {
+					return nil
+				}
+			}
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return fmt.Errorf("node %q never became ready within %s", nodeName, timeout)
+}
+
+// forwardedNomadAddr checks via `limactl list` that this VM is still
+// known to Lima and returns 127.0.0.1 with the VM's nomadPort; it does
+// not look up a forwarded port.
+func (vm *VM) forwardedNomadAddr() (string, error) {
+	out, err := exec.Command("limactl", "list", vm.name, "--format", "{{.HostAgentPID}}").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve forwarded address for %s: %w: %s", vm.name, err, out)
+	}
+	return fmt.Sprintf("127.0.0.1:%d", vm.nomadPort), nil
+}
+
+// stop destroys the VM, freeing the RAM the Harness reserved for it.
+func (vm *VM) stop() error {
+	return exec.Command("limactl", "delete", "-f", vm.name).Run()
+}
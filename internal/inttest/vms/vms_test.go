@@ -0,0 +1,71 @@
+//go:build vmtest
+
+package vms
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// styxBinPathForTest resolves the styx binary under test, expected to be
+// built and pointed at via STYX_BIN before `go test -tags=vmtest` runs
+// (e.g. `STYX_BIN=$(pwd)/styx go test -tags=vmtest -run-vm-tests ./...`).
+func styxBinPathForTest(t *testing.T) string {
+	t.Helper()
+	path := os.Getenv("STYX_BIN")
+	if path == "" {
+		t.Fatal("STYX_BIN must point at a built styx binary to inject into the test VMs")
+	}
+	return path
+}
+
+// TestInitServeAndJoin boots one server VM running `styx init --serve`
+// and two client VMs running `styx init`, and asserts the clients
+// auto-discover and join over Tailscale, platform services deploy on the
+// server, and Vault re-unseals itself after a simulated reboot - the
+// launchd + Tailscale + Vault + Nomad interaction that internal/
+// integration's container-based doubles can't exercise.
+func TestInitServeAndJoin(t *testing.T) {
+	h := NewHarness(t)
+	styxBinPath := styxBinPathForTest(t)
+
+	server := h.NewVM(DistroDebian12, styxBinPath)
+	if _, err := server.SSH("styx init --serve"); err != nil {
+		t.Fatalf("server VM failed to run styx init --serve: %v", err)
+	}
+
+	clients := []*VM{
+		h.NewVM(DistroDebian12, styxBinPath),
+		h.NewVM(DistroDebian12, styxBinPath),
+	}
+	for i, client := range clients {
+		if _, err := client.SSH("styx init"); err != nil {
+			t.Fatalf("client VM %d failed to run styx init: %v", i, err)
+		}
+	}
+
+	for i, client := range clients {
+		nodeName, err := client.SSH("hostname")
+		if err != nil {
+			t.Fatalf("client VM %d: failed to read hostname: %v", i, err)
+		}
+		if err := server.WaitForNomadNode(nodeName, time.Minute); err != nil {
+			t.Fatalf("client VM %d never joined the cluster: %v", i, err)
+		}
+	}
+
+	if _, err := server.SSH("nomad job status traefik"); err != nil {
+		t.Fatalf("platform services did not deploy on the server: %v", err)
+	}
+
+	if _, err := server.SSH("sudo reboot"); err != nil {
+		t.Fatalf("server VM failed to reboot: %v", err)
+	}
+	if err := server.waitForSSH(3 * time.Minute); err != nil {
+		t.Fatalf("server VM never came back after reboot: %v", err)
+	}
+	if out, err := server.SSH("curl -s http://127.0.0.1:8200/v1/sys/health | grep -q '\"sealed\":false'"); err != nil {
+		t.Fatalf("vault did not auto-unseal after reboot: %v (%s)", err, out)
+	}
+}
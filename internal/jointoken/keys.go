@@ -0,0 +1,62 @@
+// Package jointoken issues and verifies the short-lived bearer tokens a
+// joining node presents to internal/bootstrap.Server: an Ed25519 keypair
+// is generated once per server (see LoadOrCreateKeyPair) and persisted
+// under secretsDir, the same way pki.LoadOrCreateCA and cluster.LoadOrCreate
+// reuse existing state instead of replacing it. Create mints a compact
+// signed token against the private key; Verify checks a token's signature,
+// validity window, and remaining uses against the public key and a
+// UseStore.
+package jointoken
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const keyFile = "join-token-key.json"
+
+// keyPair is the on-disk representation of a server's Ed25519 signing key,
+// persisted at secretsDir/join-token-key.json.
+type keyPair struct {
+	PublicKey  []byte `json:"public_key"`
+	PrivateKey []byte `json:"private_key"`
+}
+
+// LoadOrCreateKeyPair loads a server's join-token signing key from
+// secretsDir if one was already generated, or generates a fresh Ed25519
+// keypair and persists it there. Safe to call on every `init --serve`.
+func LoadOrCreateKeyPair(secretsDir string) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	path := filepath.Join(secretsDir, keyFile)
+
+	if data, err := os.ReadFile(path); err == nil {
+		var kp keyPair
+		if err := json.Unmarshal(data, &kp); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return ed25519.PublicKey(kp.PublicKey), ed25519.PrivateKey(kp.PrivateKey), nil
+	} else if !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate join-token keypair: %w", err)
+	}
+
+	if err := os.MkdirAll(secretsDir, 0700); err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s: %w", secretsDir, err)
+	}
+	data, err := json.Marshal(keyPair{PublicKey: pub, PrivateKey: priv})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return pub, priv, nil
+}
@@ -0,0 +1,104 @@
+package jointoken
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const usesFile = "join-token-uses.json"
+
+// useRecord is one token's (by jti) redemption count and revocation state,
+// as persisted in usesFile.
+type useRecord struct {
+	Uses    int  `json:"uses"`
+	Revoked bool `json:"revoked"`
+}
+
+// UseStore tracks how many times each token (by jti) has been redeemed and
+// whether it's been revoked, so a Server can enforce Claims.Uses and
+// `styx token rm` across separate requests and process restarts. It does
+// not prune expired tokens; the per-token record is small and the token
+// itself stops verifying once it expires, so stale entries are harmless
+// clutter rather than a correctness problem.
+type UseStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewUseStore returns a UseStore persisted at secretsDir/join-token-uses.json.
+func NewUseStore(secretsDir string) *UseStore {
+	return &UseStore{path: filepath.Join(secretsDir, usesFile)}
+}
+
+// Consume records one redemption of claims.ID, returning an error if the
+// token has been revoked or doing so would exceed claims.Uses. It's safe
+// for concurrent use.
+func (s *UseStore) Consume(claims *Claims) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	rec := records[claims.ID]
+	if rec.Revoked {
+		return fmt.Errorf("token %s has been revoked", claims.ID)
+	}
+	if rec.Uses >= claims.Uses {
+		return fmt.Errorf("token %s has no remaining uses", claims.ID)
+	}
+
+	rec.Uses++
+	records[claims.ID] = rec
+	return s.save(records)
+}
+
+// Revoke marks jti as revoked, so any future Consume call for it fails
+// regardless of its remaining use budget or TTL. This is the store of
+// record behind `styx token rm`.
+func (s *UseStore) Revoke(jti string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	rec := records[jti]
+	rec.Revoked = true
+	records[jti] = rec
+	return s.save(records)
+}
+
+func (s *UseStore) load() (map[string]useRecord, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return make(map[string]useRecord), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.path, err)
+	}
+
+	records := make(map[string]useRecord)
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	return records, nil
+}
+
+func (s *UseStore) save(records map[string]useRecord) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.path), err)
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
@@ -0,0 +1,220 @@
+package jointoken
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// tokenPrefix and tokenVersion give every token the recognizable
+// SWMTKN-1-... shape Docker Swarm join tokens use, so an operator can
+// eyeball where a pasted string came from. The envelope is cosmetic - the
+// Ed25519-signed blob underneath is what Verify actually checks - but the
+// trailing segment (see Create) lets a human compare a token's CA
+// fingerprint prefix at a glance before pasting it anywhere.
+const (
+	tokenPrefix  = "SWMTKN"
+	tokenVersion = "1"
+)
+
+// base32Enc avoids padding and the '-' Swarm uses as a field separator -
+// RawURLEncoding's base64 alphabet contains '-', which base32's doesn't.
+var base32Enc = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// header is the fixed (and only) header Create emits and Verify accepts -
+// just enough to make the token self-describing, in the spirit of a JWT
+// header, without pulling in a JWT library for a single algorithm.
+type header struct {
+	Alg string `json:"alg"`
+}
+
+// Claims are a join token's signed payload: who minted it (Issuer, this
+// server's node name), its validity window, how many times it may be
+// redeemed, and the SHA-256 fingerprint of the bootstrap server's HTTPS
+// certificate, so a client can pin that connection without a CA chain to
+// verify it against - see CertPaths/Server.requireToken. Uses is the
+// budget Create was asked for; Server/UseStore track how much of it has
+// actually been consumed, since that can't live inside the signed token
+// itself.
+type Claims struct {
+	ID              string `json:"jti"`
+	Issuer          string `json:"iss"`
+	NotBefore       int64  `json:"nbf"`
+	Expiry          int64  `json:"exp"`
+	Uses            int    `json:"uses"`
+	CertFingerprint string `json:"cfp"`
+}
+
+// Create mints a token good for ttl starting now, redeemable up to uses
+// times, pinned to certFingerprint (the bootstrap server's HTTPS cert
+// fingerprint - see bootstrap.LoadOrCreateServerCert). The claims are
+// Ed25519-signed (header.payload.sig, the same shape as a minimal JWT),
+// then wrapped as SWMTKN-1-<body>-<fingerprint prefix>.
+func Create(priv ed25519.PrivateKey, issuer string, ttl time.Duration, uses int, certFingerprint string) (string, error) {
+	jti, err := randomID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+
+	now := time.Now()
+	claims := Claims{
+		ID:              jti,
+		Issuer:          issuer,
+		NotBefore:       now.Unix(),
+		Expiry:          now.Add(ttl).Unix(),
+		Uses:            uses,
+		CertFingerprint: certFingerprint,
+	}
+
+	raw, err := signClaims(priv, claims)
+	if err != nil {
+		return "", err
+	}
+
+	body := strings.ToLower(base32Enc.EncodeToString([]byte(raw)))
+	return fmt.Sprintf("%s-%s-%s-%s", tokenPrefix, tokenVersion, body, fingerprintPrefix(certFingerprint)), nil
+}
+
+// Verify checks a token's signature against pub and that it's within its
+// validity window, returning its Claims. It does not consume a use - pair
+// it with a UseStore.Consume call once the caller has decided the request
+// is otherwise authorized.
+func Verify(pub ed25519.PublicKey, token string) (*Claims, error) {
+	raw, err := unwrap(token)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var hdr header
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	if hdr.Alg != "Ed25519" {
+		return nil, fmt.Errorf("unsupported token algorithm %q", hdr.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	if !ed25519.Verify(pub, []byte(parts[0]+"."+parts[1]), sig) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	now := time.Now().Unix()
+	if now < claims.NotBefore {
+		return nil, fmt.Errorf("token not yet valid")
+	}
+	if now >= claims.Expiry {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	return &claims, nil
+}
+
+// Peek decodes a token's claims without verifying its signature. A joining
+// client has no way to know the server's Ed25519 public key on first
+// contact - trust here comes from the operator having copy-pasted the
+// token out of band, the same way a Docker Swarm join token is trusted -
+// so Peek is how runAutoDiscover/token rm read the CertFingerprint or ID
+// out of a token before (or without) ever calling Verify.
+func Peek(token string) (*Claims, error) {
+	raw, err := unwrap(token)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	return &claims, nil
+}
+
+func signClaims(priv ed25519.PrivateKey, claims Claims) (string, error) {
+	headerSeg, err := encodeSegment(header{Alg: "Ed25519"})
+	if err != nil {
+		return "", err
+	}
+	payloadSeg, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := headerSeg + "." + payloadSeg
+	sig := ed25519.Sign(priv, []byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// unwrap strips the SWMTKN-1-...-<fingerprint> envelope and base32-decodes
+// the signed blob inside it. The fingerprint suffix is cosmetic - Verify
+// and Peek both re-derive the authoritative CertFingerprint from the
+// signed claims - so it's discarded here rather than cross-checked.
+func unwrap(token string) (string, error) {
+	parts := strings.Split(token, "-")
+	if len(parts) != 4 || parts[0] != tokenPrefix || parts[1] != tokenVersion {
+		return "", fmt.Errorf("malformed token")
+	}
+
+	raw, err := base32Enc.DecodeString(strings.ToUpper(parts[2]))
+	if err != nil {
+		return "", fmt.Errorf("malformed token body: %w", err)
+	}
+	return string(raw), nil
+}
+
+// fingerprintPrefix returns the first 12 hex characters of a SHA-256
+// fingerprint, for display in the token string (see Create's doc comment -
+// this is cosmetic, not a security boundary).
+func fingerprintPrefix(fingerprint string) string {
+	if len(fingerprint) > 12 {
+		return fingerprint[:12]
+	}
+	return fingerprint
+}
+
+func encodeSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,37 @@
+package launchd
+
+import (
+	"fmt"
+	"os"
+)
+
+// defaultMaxLogBytes is the size threshold RotateLogIfNeeded uses when
+// callers don't need a custom limit.
+const defaultMaxLogBytes = 10 * 1024 * 1024 // 10 MiB
+
+// RotateLogIfNeeded renames path to path+".1" if it has grown past
+// maxBytes, so a launchd job's StandardOutPath/StandardErrorPath doesn't
+// grow unbounded. launchd reopens the file the next time it (re)spawns the
+// job, so this is meant to be paired with Reload for an in-place rotation.
+func RotateLogIfNeeded(path string, maxBytes int64) error {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxLogBytes
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat log %s: %w", path, err)
+	}
+
+	if info.Size() < maxBytes {
+		return nil
+	}
+
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log %s: %w", path, err)
+	}
+	return nil
+}
@@ -8,7 +8,10 @@ import (
 	"text/template"
 )
 
-// PlistConfig holds the configuration for a launchd plist file.
+// PlistConfig holds the configuration for a launchd plist file. It's
+// general-purpose enough to install a periodic/cron-style job (via
+// StartInterval or StartCalendarInterval) as well as the long-running
+// daemon it was originally written for.
 type PlistConfig struct {
 	Label      string   // Unique identifier, e.g., "com.styx.nomad"
 	Program    string   // Path to executable (discovered via PATH lookup)
@@ -16,6 +19,118 @@ type PlistConfig struct {
 	LogPath    string   // Path for stdout logs
 	ErrLogPath string   // Path for stderr logs
 	WorkingDir string   // Working directory for the process
+
+	// EnvironmentVariables are set in the job's process environment.
+	EnvironmentVariables map[string]string
+
+	// KeepAlive controls when launchd restarts the job. Nil means the
+	// previous unconditional <true/> behavior (always restart).
+	KeepAlive *KeepAliveConfig
+
+	// RunAtLoad controls whether launchd starts the job as soon as it's
+	// loaded. Nil preserves the historical default (true); set explicitly
+	// to turn it off, e.g. for a job that should only run on its
+	// StartInterval/StartCalendarInterval schedule.
+	RunAtLoad *bool
+
+	// ThrottleInterval is the minimum number of seconds launchd waits
+	// between respawns, so a crash-looping job doesn't spin the CPU. Zero
+	// means launchd's default (10s).
+	ThrottleInterval int
+
+	// ExitTimeOut is how long launchd waits after SIGTERM before sending
+	// SIGKILL, in seconds. Zero means launchd's default (20s).
+	ExitTimeOut int
+
+	// Nice is the job's scheduling priority (-20 to 19). Zero means
+	// launchd's default.
+	Nice int
+
+	// Umask overrides the job's file creation mask. launchd renders it as
+	// an octal integer.
+	Umask int
+
+	// SoftResourceLimits and HardResourceLimits cap resource usage for the
+	// job's process.
+	SoftResourceLimits *ResourceLimits
+	HardResourceLimits *ResourceLimits
+
+	// StartInterval runs the job every N seconds, mutually exclusive with
+	// StartCalendarInterval (see Validate).
+	StartInterval int
+
+	// StartCalendarInterval runs the job on a cron-style schedule: every
+	// entry that matches the current time triggers a run. Mutually
+	// exclusive with StartInterval (see Validate).
+	StartCalendarInterval []CalendarEntry
+
+	// WatchPaths restarts the job whenever any of these paths change,
+	// e.g. to pick up a rewritten Nomad/Vault config without a manual
+	// `styx services restart`.
+	WatchPaths []string
+}
+
+// RunAtLoadValue resolves RunAtLoad's effective value, defaulting to true
+// when unset.
+func (c PlistConfig) RunAtLoadValue() bool {
+	if c.RunAtLoad == nil {
+		return true
+	}
+	return *c.RunAtLoad
+}
+
+// Validate catches config combinations launchd would either reject or
+// silently misbehave on.
+func (c PlistConfig) Validate() error {
+	if c.StartInterval != 0 && len(c.StartCalendarInterval) > 0 {
+		return fmt.Errorf("plist: StartInterval and StartCalendarInterval are mutually exclusive")
+	}
+	if c.KeepAlive == nil && !c.RunAtLoadValue() {
+		return fmt.Errorf("plist: KeepAlive (unconditional restart) requires RunAtLoad")
+	}
+	return nil
+}
+
+// KeepAliveConfig mirrors launchd's KeepAlive dictionary form, letting a
+// job restart only on the conditions that actually indicate it's unhealthy
+// instead of any exit.
+type KeepAliveConfig struct {
+	SuccessfulExit bool // restart if the process exited with this success state
+	Crashed        bool // restart if the process was killed by a signal
+	NetworkState   bool // restart when the network configuration changes
+}
+
+// ResourceLimits mirrors launchd's *ResourceLimits dictionaries. Zero
+// fields are omitted from the rendered plist.
+type ResourceLimits struct {
+	NumberOfFiles     int
+	NumberOfProcesses int
+}
+
+// CalendarEntry mirrors one entry of launchd's StartCalendarInterval array.
+// A nil field matches every value for that unit, the same as omitting the
+// key from the plist dict.
+type CalendarEntry struct {
+	Minute  *int
+	Hour    *int
+	Day     *int
+	Weekday *int
+	Month   *int
+}
+
+var plistFuncs = template.FuncMap{
+	"boolTag": func(b bool) string {
+		if b {
+			return "true"
+		}
+		return "false"
+	},
+	"deref": func(p *int) int {
+		if p == nil {
+			return 0
+		}
+		return *p
+	},
 }
 
 const plistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
@@ -33,11 +148,125 @@ const plistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 {{- end}}
     </array>
 
+{{- if .EnvironmentVariables}}
+
+    <key>EnvironmentVariables</key>
+    <dict>
+{{- range $k, $v := .EnvironmentVariables}}
+        <key>{{$k}}</key>
+        <string>{{$v}}</string>
+{{- end}}
+    </dict>
+{{- end}}
+
     <key>KeepAlive</key>
+{{- if .KeepAlive}}
+    <dict>
+        <key>SuccessfulExit</key>
+        <{{boolTag .KeepAlive.SuccessfulExit}}/>
+        <key>Crashed</key>
+        <{{boolTag .KeepAlive.Crashed}}/>
+        <key>NetworkState</key>
+        <{{boolTag .KeepAlive.NetworkState}}/>
+    </dict>
+{{- else}}
     <true/>
+{{- end}}
 
     <key>RunAtLoad</key>
-    <true/>
+    <{{boolTag .RunAtLoadValue}}/>
+{{- if .ThrottleInterval}}
+
+    <key>ThrottleInterval</key>
+    <integer>{{.ThrottleInterval}}</integer>
+{{- end}}
+{{- if .ExitTimeOut}}
+
+    <key>ExitTimeOut</key>
+    <integer>{{.ExitTimeOut}}</integer>
+{{- end}}
+{{- if .Nice}}
+
+    <key>Nice</key>
+    <integer>{{.Nice}}</integer>
+{{- end}}
+{{- if .Umask}}
+
+    <key>Umask</key>
+    <integer>{{.Umask}}</integer>
+{{- end}}
+{{- if .SoftResourceLimits}}
+
+    <key>SoftResourceLimits</key>
+    <dict>
+{{- if .SoftResourceLimits.NumberOfFiles}}
+        <key>NumberOfFiles</key>
+        <integer>{{.SoftResourceLimits.NumberOfFiles}}</integer>
+{{- end}}
+{{- if .SoftResourceLimits.NumberOfProcesses}}
+        <key>NumberOfProcesses</key>
+        <integer>{{.SoftResourceLimits.NumberOfProcesses}}</integer>
+{{- end}}
+    </dict>
+{{- end}}
+{{- if .HardResourceLimits}}
+
+    <key>HardResourceLimits</key>
+    <dict>
+{{- if .HardResourceLimits.NumberOfFiles}}
+        <key>NumberOfFiles</key>
+        <integer>{{.HardResourceLimits.NumberOfFiles}}</integer>
+{{- end}}
+{{- if .HardResourceLimits.NumberOfProcesses}}
+        <key>NumberOfProcesses</key>
+        <integer>{{.HardResourceLimits.NumberOfProcesses}}</integer>
+{{- end}}
+    </dict>
+{{- end}}
+{{- if .StartInterval}}
+
+    <key>StartInterval</key>
+    <integer>{{.StartInterval}}</integer>
+{{- end}}
+{{- if .StartCalendarInterval}}
+
+    <key>StartCalendarInterval</key>
+    <array>
+{{- range .StartCalendarInterval}}
+        <dict>
+{{- if .Minute}}
+            <key>Minute</key>
+            <integer>{{deref .Minute}}</integer>
+{{- end}}
+{{- if .Hour}}
+            <key>Hour</key>
+            <integer>{{deref .Hour}}</integer>
+{{- end}}
+{{- if .Day}}
+            <key>Day</key>
+            <integer>{{deref .Day}}</integer>
+{{- end}}
+{{- if .Weekday}}
+            <key>Weekday</key>
+            <integer>{{deref .Weekday}}</integer>
+{{- end}}
+{{- if .Month}}
+            <key>Month</key>
+            <integer>{{deref .Month}}</integer>
+{{- end}}
+        </dict>
+{{- end}}
+    </array>
+{{- end}}
+{{- if .WatchPaths}}
+
+    <key>WatchPaths</key>
+    <array>
+{{- range .WatchPaths}}
+        <string>{{.}}</string>
+{{- end}}
+    </array>
+{{- end}}
 
     <key>StandardOutPath</key>
     <string>{{.LogPath}}</string>
@@ -53,7 +282,11 @@ const plistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 
 // GeneratePlist renders the launchd plist XML with the given config.
 func GeneratePlist(cfg PlistConfig) ([]byte, error) {
-	tmpl, err := template.New("plist").Parse(plistTemplate)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New("plist").Funcs(plistFuncs).Parse(plistTemplate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse plist template: %w", err)
 	}
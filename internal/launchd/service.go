@@ -2,6 +2,7 @@ package launchd
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 )
@@ -69,3 +70,23 @@ func Restart(label string) error {
 	}
 	return Start(label)
 }
+
+// Reload restarts a service in place using `launchctl kickstart -k`, which
+// respawns the job without fully unloading it from launchd - the service
+// keeps its scheduling state (KeepAlive, ThrottleInterval, etc.) instead of
+// briefly disappearing from `launchctl list`. Falls back to Unload+Load
+// using plistPath if kickstart fails (e.g. the service isn't loaded yet).
+func Reload(label, plistPath string) error {
+	target := fmt.Sprintf("gui/%d/%s", os.Getuid(), label)
+	cmd := exec.Command("launchctl", "kickstart", "-k", target)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		fmt.Printf("Note: launchctl kickstart failed, falling back to unload/load: %v\nOutput: %s\n", err, output)
+	} else {
+		return nil
+	}
+
+	if err := Unload(plistPath); err != nil {
+		fmt.Printf("Note: unload failed during reload: %v\n", err)
+	}
+	return Load(plistPath)
+}
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,6 +14,35 @@ type NomadServer struct {
 	IP       string
 	Hostname string
 	DNSName  string
+
+	// ClusterID, when known, identifies which cluster this server belongs
+	// to, so DiscoverAll can dedupe one cluster found by multiple backends
+	// and multi-cluster environments don't get offered the wrong server.
+	// Empty for backends (the plain Tailscale scan) that can't learn it.
+	ClusterID string
+
+	// Version is the discovered server's Styx version, when the backend
+	// that found it reports one (the plain Tailscale scan doesn't, and
+	// seedfile only when the entry has a version field).
+	Version string
+
+	// Source is the Discoverer.Name that found this server, for
+	// diagnostics. Set by DiscoverAll; backends don't need to fill it in.
+	Source string
+}
+
+// TailscaleDiscoverer finds Nomad servers by probing every online Tailscale
+// peer's /v1/agent/members endpoint. It's the original (and default)
+// discovery mechanism, and the only one that doesn't require the server to
+// have been built or configured with discovery in mind.
+type TailscaleDiscoverer struct{}
+
+// Name implements Discoverer.
+func (d *TailscaleDiscoverer) Name() string { return "tailscale" }
+
+// Discover implements Discoverer.
+func (d *TailscaleDiscoverer) Discover(ctx context.Context, timeout time.Duration) ([]NomadServer, error) {
+	return DiscoverNomadServers(timeout), nil
 }
 
 // DiscoverNomadServers probes Tailscale peers for running Nomad servers.
@@ -50,6 +80,82 @@ func DiscoverNomadServers(timeout time.Duration) []NomadServer {
 	return servers
 }
 
+// NomadServerWatcher maintains a live list of discovered Nomad servers by
+// probing a peer as soon as a Watcher reports it online, instead of
+// DiscoverNomadServers' probe-everything-on-every-call approach. Useful for
+// a long-running process (see cmd/styx/supervise.go) that wants to learn
+// about a new server the moment it joins the tailnet rather than on the
+// next user request.
+type NomadServerWatcher struct {
+	timeout time.Duration // per-probe HTTP timeout, defaults to 3s if zero
+
+	mu      sync.RWMutex
+	servers map[string]NomadServer // keyed by peer IP
+}
+
+// NewNomadServerWatcher creates a NomadServerWatcher. Call Run to start
+// consuming a Watcher's peer events.
+func NewNomadServerWatcher(timeout time.Duration) *NomadServerWatcher {
+	return &NomadServerWatcher{
+		timeout: timeout,
+		servers: make(map[string]NomadServer),
+	}
+}
+
+// Servers returns a snapshot of currently known Nomad servers.
+func (nw *NomadServerWatcher) Servers() []NomadServer {
+	nw.mu.RLock()
+	defer nw.mu.RUnlock()
+	servers := make([]NomadServer, 0, len(nw.servers))
+	for _, s := range nw.servers {
+		servers = append(servers, s)
+	}
+	return servers
+}
+
+// Run seeds from w's already-known peers, then probes each peer the moment
+// w reports it online and drops it the moment w reports it offline, until
+// ctx is cancelled.
+func (nw *NomadServerWatcher) Run(ctx context.Context, w *Watcher) error {
+	timeout := nw.timeout
+	if timeout == 0 {
+		timeout = 3 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	events, unsubscribe := w.Subscribe()
+	defer unsubscribe()
+
+	for _, p := range w.Peers() {
+		nw.probe(client, p)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev := <-events:
+			switch ev.Kind {
+			case EventPeerOnline:
+				nw.probe(client, ev.Peer)
+			case EventPeerOffline:
+				nw.mu.Lock()
+				delete(nw.servers, ev.Peer.IP)
+				nw.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (nw *NomadServerWatcher) probe(client *http.Client, p TailscalePeer) {
+	if !isNomadServer(client, p.IP) {
+		return
+	}
+	nw.mu.Lock()
+	nw.servers[p.IP] = NomadServer{IP: p.IP, Hostname: p.Hostname, DNSName: p.DNSName}
+	nw.mu.Unlock()
+}
+
 // isNomadServer checks if the given IP is running a Nomad server.
 func isNomadServer(client *http.Client, ip string) bool {
 	url := fmt.Sprintf("http://%s:4646/v1/agent/members", ip)
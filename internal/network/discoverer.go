@@ -0,0 +1,162 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Discoverer finds candidate Nomad servers via one discovery mechanism.
+// DiscoverAll runs every enabled Discoverer concurrently and merges their
+// results, so a slow or unreachable backend (e.g. a rendezvous URL that's
+// down) never blocks the others from reporting.
+type Discoverer interface {
+	// Name identifies the backend for --discover filtering and diagnostics
+	// (e.g. "tailscale", "mdns", "seedfile", "rendezvous").
+	Name() string
+
+	// Discover returns the Nomad servers this backend can see within
+	// timeout. A backend that simply finds nothing returns a nil slice, not
+	// an error - only genuine failures (a malformed seed file, an
+	// unreachable rendezvous URL) should error.
+	Discover(ctx context.Context, timeout time.Duration) ([]NomadServer, error)
+}
+
+// AllDiscoverers returns every built-in Discoverer in the order they should
+// be tried, for callers (runInit's --discover flag) that select a subset by
+// Name rather than by Go type.
+func AllDiscoverers(rendezvousURL string) []Discoverer {
+	discoverers := []Discoverer{
+		&TailscaleDiscoverer{},
+		&MDNSDiscoverer{},
+		&SeedFileDiscoverer{},
+	}
+	if rendezvousURL != "" {
+		discoverers = append(discoverers, &RendezvousDiscoverer{URL: rendezvousURL})
+	}
+	return discoverers
+}
+
+// SelectDiscoverers filters discoverers down to the ones named in names,
+// preserving discoverers' order. An empty names enables all of them, which
+// is what a bare `styx init` (no --discover) should do.
+func SelectDiscoverers(discoverers []Discoverer, names []string) []Discoverer {
+	if len(names) == 0 {
+		return discoverers
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		wanted[n] = true
+	}
+
+	var selected []Discoverer
+	for _, d := range discoverers {
+		if wanted[d.Name()] {
+			selected = append(selected, d)
+		}
+	}
+	return selected
+}
+
+// DiscoverAll runs every discoverer in parallel and merges their results,
+// deduping by ClusterID so the same cluster found by both Tailscale and
+// mDNS only shows up once. Servers that don't report a ClusterID (the
+// plain Tailscale scan predates the concept) dedupe by IP instead. A
+// backend that errors is skipped rather than failing the whole discovery -
+// see Discoverer.Discover.
+func DiscoverAll(ctx context.Context, discoverers []Discoverer, timeout time.Duration) []NomadServer {
+	var (
+		mu    sync.Mutex
+		found []NomadServer
+		wg    sync.WaitGroup
+	)
+
+	for _, d := range discoverers {
+		wg.Add(1)
+		go func(d Discoverer) {
+			defer wg.Done()
+
+			servers, err := d.Discover(ctx, timeout)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, s := range servers {
+				if s.Source == "" {
+					s.Source = d.Name()
+				}
+				found = append(found, s)
+			}
+		}(d)
+	}
+	wg.Wait()
+
+	return dedupeServers(found)
+}
+
+// dedupeServers collapses entries that share a ClusterID (or, lacking one,
+// an IP) into the first one seen, preserving discovery order.
+func dedupeServers(servers []NomadServer) []NomadServer {
+	seen := make(map[string]bool, len(servers))
+	var deduped []NomadServer
+
+	for _, s := range servers {
+		key := s.ClusterID
+		if key == "" {
+			key = s.IP
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, s)
+	}
+
+	return deduped
+}
+
+// LoadOrCreateClusterID returns this server's cluster identity, generating
+// and persisting a new random one at configDir/cluster-id on first call.
+// It's what a server advertises over mDNS and rendezvous so clients (and
+// DiscoverAll, when more than one backend finds the same cluster) can tell
+// it apart from a different cluster reachable on the same network.
+func LoadOrCreateClusterID(configDir string) (string, error) {
+	path := filepath.Join(configDir, "cluster-id")
+
+	if data, err := os.ReadFile(path); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read cluster id: %w", err)
+	}
+
+	id, err := generateClusterID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate cluster id: %w", err)
+	}
+
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(id), 0644); err != nil {
+		return "", fmt.Errorf("failed to write cluster id: %w", err)
+	}
+
+	return id, nil
+}
+
+func generateClusterID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,147 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	// mdnsServiceGroupAddr is the LAN multicast group Styx servers and
+	// clients exchange _styx._tcp advertisements on. This is a lightweight,
+	// Styx-specific protocol rather than a full RFC 6763 DNS-SD responder:
+	// a server periodically broadcasts a small JSON announcement instead of
+	// answering PTR/SRV/TXT queries, which is enough for "servers on this
+	// LAN advertise themselves, clients listen" without a DNS parser.
+	mdnsServiceGroupAddr = "224.0.0.251:5354"
+	mdnsAdvertiseEvery   = 5 * time.Second
+	mdnsListenWait       = 2 * time.Second
+)
+
+// mdnsAnnouncement is what a server broadcasts on mdnsServiceGroupAddr.
+type mdnsAnnouncement struct {
+	Service   string `json:"service"` // always "_styx._tcp", reserved for future protocol versions
+	Hostname  string `json:"hostname"`
+	IP        string `json:"ip"`
+	ClusterID string `json:"cluster_id"`
+	Version   string `json:"version"`
+}
+
+const mdnsServiceName = "_styx._tcp"
+
+// MDNSDiscoverer finds Nomad servers by LAN mDNS-style broadcast: servers
+// call Advertise to announce themselves periodically, and clients call
+// Discover to browse for announcements. Unlike TailscaleDiscoverer, it
+// works on a plain LAN with no Tailscale node required.
+type MDNSDiscoverer struct{}
+
+// Name implements Discoverer.
+func (d *MDNSDiscoverer) Name() string { return "mdns" }
+
+// Discover implements Discoverer. It listens on mdnsServiceGroupAddr for
+// up to timeout (capped at mdnsListenWait, since announcements repeat
+// every mdnsAdvertiseEvery) and returns every distinct server heard from.
+func (d *MDNSDiscoverer) Discover(ctx context.Context, timeout time.Duration) ([]NomadServer, error) {
+	if timeout > mdnsListenWait {
+		timeout = mdnsListenWait
+	}
+
+	groupAddr, err := net.ResolveUDPAddr("udp4", mdnsServiceGroupAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve mdns group address: %w", err)
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", nil, groupAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join mdns multicast group: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetReadDeadline(deadline)
+
+	byClusterOrIP := map[string]NomadServer{}
+	buf := make([]byte, 4096)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break // deadline reached or socket closed
+		}
+
+		var a mdnsAnnouncement
+		if err := json.Unmarshal(buf[:n], &a); err != nil || a.Service != mdnsServiceName {
+			continue
+		}
+
+		key := a.ClusterID
+		if key == "" {
+			key = a.IP
+		}
+		byClusterOrIP[key] = NomadServer{
+			IP:        a.IP,
+			Hostname:  a.Hostname,
+			ClusterID: a.ClusterID,
+			Version:   a.Version,
+		}
+	}
+
+	var servers []NomadServer
+	for _, s := range byClusterOrIP {
+		servers = append(servers, s)
+	}
+	return servers, nil
+}
+
+// Advertise broadcasts a _styx._tcp announcement for this server on
+// mdnsServiceGroupAddr every mdnsAdvertiseEvery until ctx is cancelled.
+// Called once by advertiseDiscovery (cmd/styx/supervise.go) at startup so MDNSDiscoverer.Discover has
+// something to find; a best-effort background loop, since a LAN without
+// multicast support shouldn't block or fail server startup.
+func (d *MDNSDiscoverer) Advertise(ctx context.Context, clusterID, version string) {
+	groupAddr, err := net.ResolveUDPAddr("udp4", mdnsServiceGroupAddr)
+	if err != nil {
+		return
+	}
+
+	conn, err := net.DialUDP("udp4", nil, groupAddr)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	hostname, _ := os.Hostname()
+	ip, err := GetPreferredIP()
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(mdnsAnnouncement{
+		Service:   mdnsServiceName,
+		Hostname:  hostname,
+		IP:        ip,
+		ClusterID: clusterID,
+		Version:   version,
+	})
+	if err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(mdnsAdvertiseEvery)
+	defer ticker.Stop()
+
+	conn.Write(payload)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn.Write(payload)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// rendezvousEntry is what a server posts to (and RendezvousDiscoverer reads
+// back from) a shared rendezvous URL.
+type rendezvousEntry struct {
+	Hostname  string `json:"hostname"`
+	IP        string `json:"ip"`
+	ClusterID string `json:"cluster_id"`
+	Version   string `json:"version"`
+}
+
+// RendezvousDiscoverer finds Nomad servers through a shared HTTPS
+// rendezvous point instead of a peer scan: servers periodically POST
+// their own {hostname, ip, cluster_id, version} to URL via Advertise, and
+// Discover GETs the current list back. This is the one backend that works
+// across networks the local machine can't otherwise reach (no shared
+// Tailscale tailnet, no shared LAN) - the operator just needs to point
+// every node at the same --rendezvous URL.
+//
+// The rendezvous endpoint itself isn't part of Styx: it's expected to be a
+// small external service (e.g. an S3-backed HTTP PUT/GET, or a tiny
+// reflector) that accepts a POST of a JSON entry and a GET that returns
+// every entry POSTed within some server-side TTL. Styx only speaks the
+// client side of that contract.
+type RendezvousDiscoverer struct {
+	// URL is the rendezvous endpoint, e.g. https://rendezvous.example.com/styx.
+	URL string
+
+	// ClusterID is this node's cluster, included on Advertise so peers (and
+	// this node, on the next Discover) can tell clusters apart.
+	ClusterID string
+}
+
+// Name implements Discoverer.
+func (d *RendezvousDiscoverer) Name() string { return "rendezvous" }
+
+// Discover implements Discoverer. It GETs URL, which is expected to return
+// a JSON array of rendezvousEntry.
+func (d *RendezvousDiscoverer) Discover(ctx context.Context, timeout time.Duration) ([]NomadServer, error) {
+	if d.URL == "" {
+		return nil, nil
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, d.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rendezvous request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach rendezvous url: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rendezvous url returned %s", resp.Status)
+	}
+
+	var entries []rendezvousEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode rendezvous response: %w", err)
+	}
+
+	servers := make([]NomadServer, 0, len(entries))
+	for _, e := range entries {
+		servers = append(servers, NomadServer{
+			IP:        e.IP,
+			Hostname:  e.Hostname,
+			ClusterID: e.ClusterID,
+			Version:   e.Version,
+		})
+	}
+	return servers, nil
+}
+
+// Advertise POSTs this server's {hostname, ip, cluster_id, version} to URL
+// every interval until ctx is cancelled. Failed posts (rendezvous
+// temporarily unreachable) are silently retried on the next tick rather
+// than aborting the loop, since the server shouldn't need restarting just
+// because the rendezvous endpoint had a blip.
+func (d *RendezvousDiscoverer) Advertise(ctx context.Context, version string, interval time.Duration) {
+	if d.URL == "" {
+		return
+	}
+
+	hostname, _ := os.Hostname()
+	ip, err := GetPreferredIP()
+	if err != nil {
+		return
+	}
+
+	entry := rendezvousEntry{
+		Hostname:  hostname,
+		IP:        ip,
+		ClusterID: d.ClusterID,
+		Version:   version,
+	}
+
+	post := func() {
+		payload, err := json.Marshal(entry)
+		if err != nil {
+			return
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(payload))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+	}
+
+	post()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			post()
+		}
+	}
+}
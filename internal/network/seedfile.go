@@ -0,0 +1,127 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultPeersFile is where SeedFileDiscoverer looks for a static peer list
+// when PeersFile isn't set, for multi-cluster environments where a plain
+// network scan would find the wrong server and operators would rather
+// pin known-good ones.
+var DefaultPeersFile = func() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".styx", "peers.yml")
+}()
+
+// SeedFileDiscoverer finds Nomad servers from a static list at PeersFile, a
+// flat YAML sequence of mappings:
+//
+//   - ip: 100.64.0.2
+//     hostname: mini-1
+//     cluster_id: prod
+//   - ip: 100.64.0.3
+//     hostname: mini-2
+//
+// cluster_id is optional. A missing file is not an error - it just means
+// no seed list has been configured - but a malformed one is, so a typo
+// doesn't silently look like "no peers".
+type SeedFileDiscoverer struct {
+	// PeersFile overrides DefaultPeersFile, mainly for tests.
+	PeersFile string
+}
+
+// Name implements Discoverer.
+func (d *SeedFileDiscoverer) Name() string { return "seedfile" }
+
+// Discover implements Discoverer. It ignores timeout and ctx since reading
+// a local file is effectively instant.
+func (d *SeedFileDiscoverer) Discover(ctx context.Context, timeout time.Duration) ([]NomadServer, error) {
+	path := d.PeersFile
+	if path == "" {
+		path = DefaultPeersFile
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open peers file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return parsePeersYAML(f)
+}
+
+// parsePeersYAML reads a YAML sequence of flat string mappings into
+// NomadServers. It understands exactly the subset of YAML the peers.yml
+// format above uses ("- key: value" entries, one mapping per list item)
+// rather than pulling in a general-purpose YAML library for four fields.
+func parsePeersYAML(r io.Reader) ([]NomadServer, error) {
+	var servers []NomadServer
+	var cur *NomadServer
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") {
+			if cur != nil {
+				servers = append(servers, *cur)
+			}
+			cur = &NomadServer{}
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))
+		}
+
+		if cur == nil {
+			return nil, fmt.Errorf("peers.yml:%d: expected a list item (\"- ip: ...\"), got %q", lineNum, line)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("peers.yml:%d: expected \"key: value\", got %q", lineNum, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "ip":
+			cur.IP = value
+		case "hostname":
+			cur.Hostname = value
+		case "cluster_id":
+			cur.ClusterID = value
+		case "version":
+			cur.Version = value
+		default:
+			return nil, fmt.Errorf("peers.yml:%d: unknown field %q", lineNum, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read peers file: %w", err)
+	}
+	if cur != nil {
+		servers = append(servers, *cur)
+	}
+
+	return servers, nil
+}
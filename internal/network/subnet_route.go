@@ -0,0 +1,217 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os/exec"
+	"strings"
+	"time"
+
+	"tailscale.com/ipn"
+)
+
+// styxSubnetPrefix is StyxNetworkSubnet parsed once, so callers don't each
+// have to handle a netip.ParsePrefix error for a constant we know is valid.
+var styxSubnetPrefix = netip.MustParsePrefix(StyxNetworkSubnet)
+
+// SubnetRouteStatus describes whether this node is advertising
+// StyxNetworkSubnet over Tailscale, and whether the tailnet admin has
+// approved it. A route can be advertised but not approved for a while -
+// Tailscale requires an explicit admin action in the control plane
+// (`tailscale set --accept-routes` on peers, or approval in the admin
+// console) before any peer will actually route through it.
+type SubnetRouteStatus struct {
+	Advertised bool
+	Approved   bool
+}
+
+// AdvertiseStyxSubnet adds StyxNetworkSubnet to this node's advertised
+// Tailscale routes (`tailscale set --advertise-routes=...` semantics), so
+// peers can reach container IPs on it directly instead of through
+// localhost port-forwards. It's additive: any routes already being
+// advertised for other reasons are left alone. The tailnet admin still has
+// to approve the route - see GetSubnetRouteStatus.
+func AdvertiseStyxSubnet() error {
+	routes, err := advertisedRoutes()
+	if err != nil {
+		return fmt.Errorf("failed to read advertised routes: %w", err)
+	}
+
+	for _, r := range routes {
+		if r == styxSubnetPrefix {
+			return nil
+		}
+	}
+
+	return setAdvertisedRoutes(append(routes, styxSubnetPrefix))
+}
+
+// WithdrawStyxSubnet removes StyxNetworkSubnet from this node's advertised
+// Tailscale routes, leaving any other advertised routes untouched.
+func WithdrawStyxSubnet() error {
+	routes, err := advertisedRoutes()
+	if err != nil {
+		return fmt.Errorf("failed to read advertised routes: %w", err)
+	}
+
+	kept := routes[:0]
+	for _, r := range routes {
+		if r != styxSubnetPrefix {
+			kept = append(kept, r)
+		}
+	}
+
+	return setAdvertisedRoutes(kept)
+}
+
+// GetSubnetRouteStatus reports whether StyxNetworkSubnet is currently
+// advertised and, if so, whether the tailnet admin has approved it.
+func GetSubnetRouteStatus() (SubnetRouteStatus, error) {
+	routes, err := advertisedRoutes()
+	if err != nil {
+		return SubnetRouteStatus{}, fmt.Errorf("failed to read advertised routes: %w", err)
+	}
+
+	var status SubnetRouteStatus
+	for _, r := range routes {
+		if r == styxSubnetPrefix {
+			status.Advertised = true
+			break
+		}
+	}
+
+	if tsStatus := getStatus(); tsStatus != nil && tsStatus.Self != nil && tsStatus.Self.AllowedIPs != nil {
+		for _, allowed := range tsStatus.Self.AllowedIPs.All() {
+			if allowed == styxSubnetPrefix {
+				status.Approved = true
+				break
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// advertisedRoutes returns the routes this node currently asks Tailscale to
+// advertise, via the LocalAPI, falling back to `tailscale debug prefs` if
+// the LocalAPI socket isn't reachable.
+func advertisedRoutes() ([]netip.Prefix, error) {
+	prefs, err := lc.GetPrefs(context.Background())
+	if err != nil {
+		return advertisedRoutesViaCLI()
+	}
+	return prefs.AdvertiseRoutes, nil
+}
+
+// setAdvertisedRoutes replaces this node's advertised routes with routes,
+// via the LocalAPI, falling back to `tailscale set --advertise-routes=...`
+// if the LocalAPI socket isn't reachable.
+func setAdvertisedRoutes(routes []netip.Prefix) error {
+	_, err := lc.EditPrefs(context.Background(), &ipn.MaskedPrefs{
+		Prefs:              ipn.Prefs{AdvertiseRoutes: routes},
+		AdvertiseRoutesSet: true,
+	})
+	if err != nil {
+		return setAdvertisedRoutesViaCLI(routes)
+	}
+	return nil
+}
+
+func advertisedRoutesViaCLI() ([]netip.Prefix, error) {
+	tailscaleBin := findTailscaleBinary()
+	if tailscaleBin == "" {
+		return nil, fmt.Errorf("tailscale binary not found")
+	}
+
+	output, err := exec.Command(tailscaleBin, "debug", "prefs").Output()
+	if err != nil {
+		return nil, fmt.Errorf("tailscale debug prefs failed: %w", err)
+	}
+
+	var prefs ipn.Prefs
+	if err := json.Unmarshal(output, &prefs); err != nil {
+		return nil, fmt.Errorf("failed to parse tailscale debug prefs output: %w", err)
+	}
+	return prefs.AdvertiseRoutes, nil
+}
+
+func setAdvertisedRoutesViaCLI(routes []netip.Prefix) error {
+	tailscaleBin := findTailscaleBinary()
+	if tailscaleBin == "" {
+		return fmt.Errorf("tailscale binary not found")
+	}
+
+	strs := make([]string, len(routes))
+	for i, r := range routes {
+		strs[i] = r.String()
+	}
+
+	cmd := exec.Command(tailscaleBin, "set", "--advertise-routes="+strings.Join(strs, ","))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("tailscale set --advertise-routes failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// SubnetRouteReconciler keeps StyxNetworkSubnet advertised over Tailscale,
+// the way pki.Renewer keeps a leaf certificate from expiring - some other
+// actor (an admin running `tailscale set`, or a tailnet policy change) can
+// withdraw the route out from under us, and this notices and re-advertises
+// it on the next check.
+type SubnetRouteReconciler struct {
+	CheckEvery time.Duration // defaults to 1m if zero
+
+	// Warn is called with a human-readable message whenever the route is
+	// advertised but not yet approved by the tailnet admin. May be nil.
+	Warn func(string)
+}
+
+// Run re-advertises StyxNetworkSubnet every CheckEvery if it's been
+// withdrawn, and reports via Warn if it's advertised but unapproved, until
+// ctx is cancelled.
+func (r *SubnetRouteReconciler) Run(ctx context.Context) error {
+	interval := r.CheckEvery
+	if interval == 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := r.ReconcileOnce(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ReconcileOnce re-advertises StyxNetworkSubnet if it's currently withdrawn
+// and reports an unapproved route via Warn. Exported so `styx subnet
+// enable`/`styx subnet status` can drive a single check without running
+// the Run loop.
+func (r *SubnetRouteReconciler) ReconcileOnce() error {
+	status, err := GetSubnetRouteStatus()
+	if err != nil {
+		return fmt.Errorf("failed to check subnet route status: %w", err)
+	}
+
+	if !status.Advertised {
+		if err := AdvertiseStyxSubnet(); err != nil {
+			return fmt.Errorf("failed to re-advertise %s: %w", StyxNetworkSubnet, err)
+		}
+		status.Advertised = true
+	}
+
+	if !status.Approved && r.Warn != nil {
+		r.Warn(fmt.Sprintf("%s is advertised but not yet approved in the tailnet admin console - peers can't route to it until an admin approves the route", StyxNetworkSubnet))
+	}
+
+	return nil
+}
@@ -1,10 +1,18 @@
 package network
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"tailscale.com/client/local"
+	"tailscale.com/ipn"
+	"tailscale.com/ipn/ipnstate"
 )
 
 // TailscaleInfo contains Tailscale network information for this machine.
@@ -23,26 +31,14 @@ type TailscalePeer struct {
 	Online   bool   // Whether peer is currently online
 }
 
-// tailscaleStatus represents the relevant fields from `tailscale status --json`
-type tailscaleStatus struct {
-	BackendState   string   `json:"BackendState"`
-	TailscaleIPs   []string `json:"TailscaleIPs"`
-	MagicDNSSuffix string   `json:"MagicDNSSuffix"`
-	Self           struct {
-		HostName string `json:"HostName"`
-		DNSName  string `json:"DNSName"`
-	} `json:"Self"`
-	Peer map[string]tailscalePeerInfo `json:"Peer"`
-}
-
-type tailscalePeerInfo struct {
-	HostName     string   `json:"HostName"`
-	DNSName      string   `json:"DNSName"`
-	TailscaleIPs []string `json:"TailscaleIPs"`
-	Online       bool     `json:"Online"`
-}
+// lc is the LocalAPI client used to read Tailscale's state. Its zero
+// value dials tailscaled over the platform's default local socket, so no
+// explicit setup is needed.
+var lc local.Client
 
 // findTailscaleBinary finds the tailscale binary, preferring PATH lookup.
+// Used only as a fallback for installs (e.g. the Mac App Store build)
+// where the LocalAPI socket isn't reachable.
 func findTailscaleBinary() string {
 	// First try PATH lookup
 	if path, err := exec.LookPath("tailscale"); err == nil {
@@ -64,8 +60,25 @@ func findTailscaleBinary() string {
 	return ""
 }
 
-// getTailscaleStatus runs tailscale status --json and parses the result.
-func getTailscaleStatus() *tailscaleStatus {
+// getStatus returns tailscaled's current status via the LocalAPI, falling
+// back to `tailscale status --json` if the LocalAPI socket isn't
+// reachable. Returns nil if Tailscale isn't running or isn't available at
+// all.
+func getStatus() *ipnstate.Status {
+	status, err := lc.Status(context.Background())
+	if err != nil {
+		status = getStatusViaCLI()
+	}
+	if status == nil || status.BackendState != "Running" {
+		return nil
+	}
+	return status
+}
+
+// getStatusViaCLI runs `tailscale status --json` and parses the result.
+// `tailscale status --json` marshals the same ipnstate.Status the
+// LocalAPI returns, so it decodes into the same type.
+func getStatusViaCLI() *ipnstate.Status {
 	tailscaleBin := findTailscaleBinary()
 	if tailscaleBin == "" {
 		return nil
@@ -77,23 +90,18 @@ func getTailscaleStatus() *tailscaleStatus {
 		return nil
 	}
 
-	var status tailscaleStatus
+	var status ipnstate.Status
 	if err := json.Unmarshal(output, &status); err != nil {
 		return nil
 	}
-
-	if status.BackendState != "Running" {
-		return nil
-	}
-
 	return &status
 }
 
 // extractIPv4 returns the first IPv4 address from a list of IPs.
-func extractIPv4(ips []string) string {
+func extractIPv4(ips []netip.Addr) string {
 	for _, ip := range ips {
-		if !strings.Contains(ip, ":") {
-			return ip
+		if ip.Is4() {
+			return ip.String()
 		}
 	}
 	return ""
@@ -102,23 +110,63 @@ func extractIPv4(ips []string) string {
 // GetTailscaleInfo returns Tailscale network information for this machine.
 // Returns a TailscaleInfo with Running=false if Tailscale is not available.
 func GetTailscaleInfo() TailscaleInfo {
-	status := getTailscaleStatus()
-	if status == nil {
+	status := getStatus()
+	if status == nil || status.Self == nil {
 		return TailscaleInfo{Running: false}
 	}
 
 	return TailscaleInfo{
-		IP:       extractIPv4(status.TailscaleIPs),
+		IP:       extractIPv4(status.Self.TailscaleIPs),
 		Hostname: strings.ToLower(status.Self.HostName),
 		DNSName:  strings.ToLower(strings.TrimSuffix(status.Self.DNSName, ".")),
 		Running:  true,
 	}
 }
 
+// FetchCert writes a Tailscale TLS certificate and key for domain into
+// outDir as <domain>.crt and <domain>.key, for services that terminate
+// HTTPS using Tailscale's built-in cert issuance instead of Let's Encrypt
+// (see services.TLSModeTailscale). Falls back to `tailscale cert` if the
+// LocalAPI socket isn't reachable.
+func FetchCert(domain, outDir string) error {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", outDir, err)
+	}
+
+	certFile := filepath.Join(outDir, domain+".crt")
+	keyFile := filepath.Join(outDir, domain+".key")
+
+	certPEM, keyPEM, err := lc.CertPair(context.Background(), domain)
+	if err != nil {
+		return fetchCertViaCLI(domain, certFile, keyFile)
+	}
+
+	if err := os.WriteFile(certFile, certPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", certFile, err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", keyFile, err)
+	}
+	return nil
+}
+
+func fetchCertViaCLI(domain, certFile, keyFile string) error {
+	tailscaleBin := findTailscaleBinary()
+	if tailscaleBin == "" {
+		return fmt.Errorf("tailscale binary not found")
+	}
+
+	cmd := exec.Command(tailscaleBin, "cert", "--cert-file", certFile, "--key-file", keyFile, domain)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("tailscale cert failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
 // GetTailscalePeers returns all online peers on the Tailscale network.
 // Returns nil if Tailscale is not available or not running.
 func GetTailscalePeers() []TailscalePeer {
-	status := getTailscaleStatus()
+	status := getStatus()
 	if status == nil {
 		return nil
 	}
@@ -144,3 +192,25 @@ func GetTailscalePeers() []TailscalePeer {
 
 	return peers
 }
+
+// WatchNotify subscribes to tailscaled's IPN bus and invokes onChange with
+// the current TailscaleInfo whenever the node's backend state or tailnet
+// identity changes (e.g. it goes offline, or gets renamed). It blocks
+// until ctx is cancelled or the bus connection is lost.
+func WatchNotify(ctx context.Context, onChange func(TailscaleInfo)) error {
+	watcher, err := lc.WatchIPNBus(ctx, ipn.NotifyInitialState|ipn.NotifyInitialNetMap)
+	if err != nil {
+		return fmt.Errorf("failed to watch tailscale ipn bus: %w", err)
+	}
+	defer watcher.Close()
+
+	for {
+		n, err := watcher.Next()
+		if err != nil {
+			return err
+		}
+		if n.State != nil || n.NetMap != nil {
+			onChange(GetTailscaleInfo())
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"tailscale.com/ipn"
+)
+
+// EventKind identifies what changed in an Event published by Watcher.
+type EventKind int
+
+const (
+	// EventSelfChanged fires when this machine's own TailscaleInfo changes
+	// (e.g. it connects, disconnects, or gets renamed).
+	EventSelfChanged EventKind = iota
+	// EventPeerOnline fires when a peer that wasn't in Watcher.Peers()
+	// becomes online. Peer is set.
+	EventPeerOnline
+	// EventPeerOffline fires when a peer that was in Watcher.Peers() goes
+	// offline or disappears from the netmap. Peer is set.
+	EventPeerOffline
+)
+
+// Event is published on a Watcher's subscription channel whenever its
+// cached state changes.
+type Event struct {
+	Kind EventKind
+	Peer TailscalePeer // set for EventPeer*; zero value for EventSelfChanged
+}
+
+// Watcher keeps an in-memory TailscaleInfo and online-peer map fed by
+// tailscaled's IPN bus, instead of GetTailscaleInfo/GetTailscalePeers'
+// fresh status query (LocalAPI, or `tailscale status --json` as a
+// fallback) per call. Long-running
+// processes (see cmd/styx/supervise.go) should start one with Run and read
+// through Info/Peers/Subscribe; one-shot CLI commands can keep using the
+// package-level GetTailscaleInfo/GetTailscalePeers functions, which still
+// poll directly and work fine with no Watcher running.
+type Watcher struct {
+	mu         sync.RWMutex
+	info       TailscaleInfo
+	peers      map[string]TailscalePeer // keyed by IP
+	lastUpdate time.Time
+
+	subsMu sync.Mutex
+	subs   map[chan Event]struct{}
+}
+
+// NewWatcher creates a Watcher with no cached state yet - call Run to start
+// populating it from the IPN bus.
+func NewWatcher() *Watcher {
+	return &Watcher{
+		peers: make(map[string]TailscalePeer),
+		subs:  make(map[chan Event]struct{}),
+	}
+}
+
+// Info returns the last known TailscaleInfo for this machine, the zero
+// value (Running=false) until the first sync completes.
+func (w *Watcher) Info() TailscaleInfo {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.info
+}
+
+// Peers returns a snapshot of the last known online peers.
+func (w *Watcher) Peers() []TailscalePeer {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	peers := make([]TailscalePeer, 0, len(w.peers))
+	for _, p := range w.peers {
+		peers = append(peers, p)
+	}
+	return peers
+}
+
+// LastUpdated returns when the cached state was last refreshed from the IPN
+// bus, the zero time if Run hasn't synced yet - callers that need to detect
+// staleness (e.g. the bus connection silently wedged) can compare this
+// against time.Now().
+func (w *Watcher) LastUpdated() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.lastUpdate
+}
+
+// Subscribe returns a channel that receives an Event whenever the cached
+// state changes, and an unsubscribe func to call when done (typically via
+// defer). Buffered by 8 - a slow subscriber only misses intermediate
+// events, not the fact that something changed; Peers()/Info() always
+// reflect the latest state regardless.
+func (w *Watcher) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 8)
+	w.subsMu.Lock()
+	w.subs[ch] = struct{}{}
+	w.subsMu.Unlock()
+
+	return ch, func() {
+		w.subsMu.Lock()
+		delete(w.subs, ch)
+		w.subsMu.Unlock()
+	}
+}
+
+func (w *Watcher) publish(ev Event) {
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+	for ch := range w.subs {
+		select {
+		case ch <- ev:
+		default:
+			// Slow subscriber - drop it, Peers()/Info() still reflect the
+			// latest state.
+		}
+	}
+}
+
+// Run connects to tailscaled's IPN bus and keeps the cached state current
+// until ctx is cancelled, reconnecting with exponential backoff (1s, capped
+// at 30s) if the bus connection drops - a connection that stays up for
+// longer than the cap resets the backoff, the same convention
+// supervisor.Tree uses for restarting crashed processes.
+func (w *Watcher) Run(ctx context.Context) error {
+	const maxBackoff = 30 * time.Second
+	backoff := time.Second
+
+	for {
+		connectedAt := time.Now()
+		err := w.watchOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if time.Since(connectedAt) >= maxBackoff {
+			backoff = time.Second
+		}
+
+		fmt.Printf("network: IPN bus watch stopped (%v), reconnecting in %s\n", err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < maxBackoff {
+			backoff *= 2
+		}
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// watchOnce runs a single IPN bus connection until it errors or ctx is
+// cancelled, via the LocalAPI, falling back to shelling out to `tailscale
+// debug watch-ipn` if the LocalAPI socket isn't reachable.
+func (w *Watcher) watchOnce(ctx context.Context) error {
+	busWatcher, err := lc.WatchIPNBus(ctx, ipn.NotifyInitialState|ipn.NotifyInitialNetMap)
+	if err != nil {
+		return w.watchOnceViaCLI(ctx)
+	}
+	defer busWatcher.Close()
+
+	w.refresh()
+	for {
+		n, err := busWatcher.Next()
+		if err != nil {
+			return err
+		}
+		if n.State != nil || n.NetMap != nil {
+			w.refresh()
+		}
+	}
+}
+
+// watchOnceViaCLI runs `tailscale debug watch-ipn`, which streams one IPN
+// bus notify per line, and re-syncs the cached state whenever a line
+// arrives. It doesn't try to parse watch-ipn's notify JSON itself - that's
+// the same raw wire format the LocalAPI watch uses internally, and
+// refresh() already knows how to pull a consistent TailscaleInfo/peer
+// snapshot via getStatus() - so a line arriving is only ever a trigger to
+// re-sync, never a payload to decode.
+func (w *Watcher) watchOnceViaCLI(ctx context.Context) error {
+	tailscaleBin := findTailscaleBinary()
+	if tailscaleBin == "" {
+		return fmt.Errorf("tailscale binary not found")
+	}
+
+	cmd := exec.CommandContext(ctx, tailscaleBin, "debug", "watch-ipn")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open watch-ipn stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start watch-ipn: %w", err)
+	}
+	defer cmd.Wait()
+
+	w.refresh()
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		w.refresh()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("watch-ipn scan failed: %w", err)
+	}
+	return fmt.Errorf("watch-ipn exited")
+}
+
+// refresh re-syncs the cached state from tailscaled's current status and
+// publishes an Event for whatever changed since the last sync.
+func (w *Watcher) refresh() {
+	status := getStatus()
+
+	newInfo := TailscaleInfo{Running: false}
+	newPeers := make(map[string]TailscalePeer)
+	if status != nil && status.Self != nil {
+		newInfo = TailscaleInfo{
+			IP:       extractIPv4(status.Self.TailscaleIPs),
+			Hostname: strings.ToLower(status.Self.HostName),
+			DNSName:  strings.ToLower(strings.TrimSuffix(status.Self.DNSName, ".")),
+			Running:  true,
+		}
+
+		for _, peer := range status.Peer {
+			if !peer.Online {
+				continue
+			}
+			ipv4 := extractIPv4(peer.TailscaleIPs)
+			if ipv4 == "" {
+				continue
+			}
+			newPeers[ipv4] = TailscalePeer{
+				IP:       ipv4,
+				Hostname: strings.ToLower(peer.HostName),
+				DNSName:  strings.ToLower(strings.TrimSuffix(peer.DNSName, ".")),
+				Online:   true,
+			}
+		}
+	}
+
+	w.mu.Lock()
+	oldInfo := w.info
+	oldPeers := w.peers
+	w.info = newInfo
+	w.peers = newPeers
+	w.lastUpdate = time.Now()
+	w.mu.Unlock()
+
+	if oldInfo != newInfo {
+		w.publish(Event{Kind: EventSelfChanged})
+	}
+	for ip, p := range newPeers {
+		if _, ok := oldPeers[ip]; !ok {
+			w.publish(Event{Kind: EventPeerOnline, Peer: p})
+		}
+	}
+	for ip, p := range oldPeers {
+		if _, ok := newPeers[ip]; !ok {
+			w.publish(Event{Kind: EventPeerOffline, Peer: p})
+		}
+	}
+}
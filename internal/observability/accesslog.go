@@ -0,0 +1,150 @@
+package observability
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// rotateSize is the file size threshold at which an access log is rotated
+// to a .1 suffix.
+const rotateSize = 10 * 1024 * 1024 // 10 MiB
+
+// AccessLogEntry is one structured, Envoy-style access log line.
+type AccessLogEntry struct {
+	Time       string  `json:"time"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Status     int     `json:"status"`
+	RemoteIP   string  `json:"remote_ip"`
+	Bytes      int     `json:"bytes"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// AccessLog writes structured JSON access log lines to a rotating file and
+// feeds request latencies into a Metrics recorder for a /metrics endpoint.
+type AccessLog struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+
+	metrics *Metrics
+}
+
+// NewAccessLog opens (creating if needed) a JSON access log file named name
+// under dir.
+func NewAccessLog(dir, name string) (*AccessLog, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open access log %s: %w", path, err)
+	}
+
+	return &AccessLog{path: path, file: f, metrics: NewMetrics()}, nil
+}
+
+// Path returns the access log file's path on disk.
+func (a *AccessLog) Path() string {
+	return a.path
+}
+
+// Metrics returns the latency histograms fed by this access log.
+func (a *AccessLog) Metrics() *Metrics {
+	return a.metrics
+}
+
+// Middleware wraps an http.Handler, writing a structured access log entry
+// and recording a latency observation for every request it serves.
+func (a *AccessLog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		duration := time.Since(start)
+		a.record(r.Method, r.URL.Path, remoteIP(r), rec.status, rec.bytes, duration)
+	})
+}
+
+// RecordClient logs an access entry for an outbound request made by an API
+// client, e.g. styx polling Nomad or Vault. host identifies the remote
+// server being polled since there's no incoming RemoteAddr to log.
+func (a *AccessLog) RecordClient(method, path, host string, status int, duration time.Duration) {
+	a.record(method, path, host, status, 0, duration)
+}
+
+func (a *AccessLog) record(method, path, remoteIP string, status, bytes int, duration time.Duration) {
+	entry := AccessLogEntry{
+		Time:       time.Now().UTC().Format(time.RFC3339Nano),
+		Method:     method,
+		Path:       path,
+		Status:     status,
+		RemoteIP:   remoteIP,
+		Bytes:      bytes,
+		DurationMs: float64(duration.Microseconds()) / 1000.0,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	a.mu.Lock()
+	if info, err := a.file.Stat(); err == nil && info.Size() > rotateSize {
+		a.rotateLocked()
+	}
+	a.file.Write(data)
+	a.mu.Unlock()
+
+	a.metrics.Observe(path, duration)
+}
+
+// rotateLocked renames the current log file to a .1 suffix and opens a
+// fresh file in its place. Callers must hold a.mu.
+func (a *AccessLog) rotateLocked() {
+	a.file.Close()
+	os.Rename(a.path, a.path+".1")
+
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	a.file = f
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
@@ -0,0 +1,93 @@
+// Package observability provides lightweight structured access logging and
+// Prometheus-style latency histograms for Styx's HTTP servers and clients,
+// so operators can debug slow bootstraps or unhealthy Nomad/Vault polling
+// instead of seeing only an opaque "not_responding" status.
+package observability
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are histogram bucket upper bounds in milliseconds.
+var latencyBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Metrics accumulates per-endpoint request latency histograms and renders
+// them in Prometheus text exposition format.
+type Metrics struct {
+	mu   sync.Mutex
+	data map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64 // cumulative count per bucket, one per latencyBuckets entry
+	sum    float64
+	count  uint64
+}
+
+// NewMetrics creates an empty metrics recorder.
+func NewMetrics() *Metrics {
+	return &Metrics{data: make(map[string]*histogram)}
+}
+
+// Observe records a latency observation for the given endpoint path.
+func (m *Metrics) Observe(path string, d time.Duration) {
+	ms := float64(d.Microseconds()) / 1000.0
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.data[path]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		m.data[path] = h
+	}
+
+	for i, bound := range latencyBuckets {
+		if ms <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += ms
+	h.count++
+}
+
+// WriteTo renders all recorded histograms in Prometheus text exposition
+// format.
+func (m *Metrics) WriteTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	paths := make([]string, 0, len(m.data))
+	for p := range m.data {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintln(w, "# HELP styx_http_request_duration_ms HTTP request latency in milliseconds.")
+	fmt.Fprintln(w, "# TYPE styx_http_request_duration_ms histogram")
+
+	for _, path := range paths {
+		h := m.data[path]
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "styx_http_request_duration_ms_bucket{path=%q,le=%q} %d\n", path, strconv.FormatFloat(bound, 'f', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(w, "styx_http_request_duration_ms_bucket{path=%q,le=\"+Inf\"} %d\n", path, h.count)
+		fmt.Fprintf(w, "styx_http_request_duration_ms_sum{path=%q} %g\n", path, h.sum)
+		fmt.Fprintf(w, "styx_http_request_duration_ms_count{path=%q} %d\n", path, h.count)
+	}
+}
+
+// Handler returns an http.Handler that serves the recorded histograms in
+// Prometheus text exposition format, suitable for mounting at /metrics.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		m.WriteTo(w)
+	})
+}
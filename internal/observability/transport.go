@@ -0,0 +1,38 @@
+package observability
+
+import (
+	"net/http"
+	"time"
+)
+
+// InstrumentedTransport wraps an http.RoundTripper, recording a structured
+// access log entry and latency observation for every outbound request. It
+// is used by api.Client so operators can see exactly which Nomad/Vault call
+// is slow instead of an opaque timeout.
+type InstrumentedTransport struct {
+	Next http.RoundTripper
+	Log  *AccessLog
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	duration := time.Since(start)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+
+	if t.Log != nil {
+		t.Log.RecordClient(req.Method, req.URL.Path, req.URL.Host, status, duration)
+	}
+
+	return resp, err
+}
@@ -0,0 +1,195 @@
+// Package pki generates and rotates the mTLS materials Nomad/Consul need -
+// ServerConfig/ClientConfig/ConsulServerConfig/ConsulClientConfig already
+// expose CAFile/CertFile/KeyFile, but nothing used to create them. Unlike
+// internal/tls, which shells out to the `consul tls`/`nomad tls` CLIs, this
+// package issues certs directly with crypto/x509 so it has no runtime
+// dependency on either binary being installed.
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	caCertFile = "styx-agent-ca.pem"
+	caKeyFile  = "styx-agent-ca-key.pem"
+
+	// LeafValidity is how long an issued leaf certificate is valid for.
+	LeafValidity = 72 * time.Hour
+	// RenewBefore is how long before expiry the Renewer re-issues a leaf.
+	RenewBefore = 24 * time.Hour
+)
+
+// CA is the root of trust every leaf certificate in the cluster chains to.
+type CA struct {
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+// LoadOrCreateCA loads the root CA from dir if one was already generated,
+// or generates a fresh 2048-bit RSA root CA and persists it there. It's
+// safe to call on every `styx init`: an existing CA is reused rather than
+// replaced, the same way Bootstrapper reuses an already-initialized Vault.
+func LoadOrCreateCA(dir string) (*CA, error) {
+	if ca, err := loadCA(dir); err == nil {
+		return ca, nil
+	}
+	return createCA(dir)
+}
+
+// RotateCA generates a fresh root CA under dir, overwriting whatever was
+// there, for `styx pki rotate --rotate-ca`. Every previously issued leaf
+// certificate stops verifying the moment this returns, so callers must
+// re-issue (Issue/IssueServiceCert) and redeploy each node's leaf before
+// the next renewal cycle would otherwise have run.
+func RotateCA(dir string) (*CA, error) {
+	return createCA(dir)
+}
+
+func createCA(dir string) (*CA, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create CA directory: %w", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Styx Cluster CA", Organization: []string{"styx"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(5, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated CA certificate: %w", err)
+	}
+
+	if err := writePEM(filepath.Join(dir, caCertFile), "CERTIFICATE", certDER); err != nil {
+		return nil, fmt.Errorf("failed to write CA certificate: %w", err)
+	}
+	if err := writePEM(filepath.Join(dir, caKeyFile), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)); err != nil {
+		return nil, fmt.Errorf("failed to write CA key: %w", err)
+	}
+
+	return &CA{cert: cert, key: key}, nil
+}
+
+func loadCA(dir string) (*CA, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, caCertFile))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, caKeyFile))
+	if err != nil {
+		return nil, err
+	}
+
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, fmt.Errorf("failed to decode CA certificate PEM in %s", dir)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, fmt.Errorf("failed to decode CA key PEM in %s", dir)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+
+	return &CA{cert: cert, key: key}, nil
+}
+
+// CAFile returns the path LoadOrCreateCA/RotateCA writes the root CA
+// certificate to within dir, for callers that only need the path (e.g. to
+// fill in ServerConfig.CAFile).
+func CAFile(dir string) string {
+	return filepath.Join(dir, caCertFile)
+}
+
+// Fingerprint returns a colon-separated hex SHA-256 digest of the DER
+// certificate at certFile (e.g. CAFile(certsDir)), for an operator to
+// verify out-of-band before a joining client trusts it on first join.
+func Fingerprint(certFile string) (string, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return "", err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", fmt.Errorf("failed to decode certificate PEM in %s", certFile)
+	}
+
+	sum := sha256.Sum256(block.Bytes)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+func randomSerial() (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serial, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial: %w", err)
+	}
+	return serial, nil
+}
+
+func writePEM(path, blockType string, der []byte) error {
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+}
+
+// sansFor returns the DNS names and IP addresses a leaf cert for role
+// should carry: the names Nomad/Consul expect their RPC peers to present
+// (server.global.nomad, server.dc1.consul), localhost, nodeName, and the
+// node's advertise IP.
+func sansFor(role, nodeName, advertiseIP string) (dnsNames []string, ips []net.IP) {
+	dnsNames = []string{"localhost", nodeName}
+	if role == RoleServer {
+		dnsNames = append(dnsNames, "server.global.nomad", "server.dc1.consul")
+	} else {
+		dnsNames = append(dnsNames, "client.global.nomad", "client.dc1.consul")
+	}
+
+	ips = []net.IP{net.ParseIP("127.0.0.1")}
+	if ip := net.ParseIP(advertiseIP); ip != nil {
+		ips = append(ips, ip)
+	}
+	return dnsNames, ips
+}
@@ -0,0 +1,125 @@
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Role identifies what a leaf certificate is issued for, so its SANs and
+// file naming match what the corresponding node.hcl expects.
+const (
+	RoleServer = "server"
+	RoleClient = "client"
+)
+
+// CertPaths holds the on-disk paths to an issued leaf certificate, ready
+// to drop straight into ServerConfig.CAFile/CertFile/KeyFile and friends.
+type CertPaths struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// IssueOptions describes the leaf certificate `styx pki issue` (or the
+// auto-provisioning step in `styx init`) should create.
+type IssueOptions struct {
+	NodeName    string
+	Role        string // RoleServer or RoleClient
+	AdvertiseIP string
+}
+
+// Issue generates a new RSA key and leaf certificate signed by ca, valid
+// for LeafValidity, with SANs covering opts.NodeName, opts.AdvertiseIP,
+// localhost, and the names Nomad and Consul expect their RPC peers to
+// present (server.global.nomad/server.dc1.consul for RoleServer,
+// client.global.nomad/client.dc1.consul for RoleClient). Files are written under dir
+// with 0600 perms, named after opts.NodeName and opts.Role so repeated
+// issuance (e.g. from Renewer) overwrites the same files in place.
+func Issue(ca *CA, dir string, opts IssueOptions) (*CertPaths, error) {
+	if opts.Role != RoleServer && opts.Role != RoleClient {
+		return nil, fmt.Errorf("invalid pki role %q, want %q or %q", opts.Role, RoleServer, RoleClient)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create certs directory: %w", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate leaf key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	dnsNames, ips := sansFor(opts.Role, opts.NodeName, opts.AdvertiseIP)
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: opts.NodeName, Organization: []string{"styx"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(LeafValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign leaf certificate: %w", err)
+	}
+
+	certPath := filepath.Join(dir, fmt.Sprintf("%s-%s.pem", opts.NodeName, opts.Role))
+	keyPath := filepath.Join(dir, fmt.Sprintf("%s-%s-key.pem", opts.NodeName, opts.Role))
+
+	if err := writePEM(certPath, "CERTIFICATE", certDER); err != nil {
+		return nil, fmt.Errorf("failed to write leaf certificate: %w", err)
+	}
+	if err := writePEM(keyPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)); err != nil {
+		return nil, fmt.Errorf("failed to write leaf key: %w", err)
+	}
+
+	return &CertPaths{
+		CAFile:   CAFile(dir),
+		CertFile: certPath,
+		KeyFile:  keyPath,
+	}, nil
+}
+
+// ExpiresSoon reports whether the leaf certificate at certFile expires
+// within RenewBefore, so Renewer knows to re-issue it.
+func ExpiresSoon(certFile string) (bool, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	cert, err := parseCertPEM(data)
+	if err != nil {
+		return false, err
+	}
+
+	return time.Until(cert.NotAfter) < RenewBefore, nil
+}
+
+func parseCertPEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return cert, nil
+}
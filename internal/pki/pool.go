@@ -0,0 +1,38 @@
+package pki
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// GetCAPool reads the PEM-encoded CA certificate at caFile and returns an
+// x509.CertPool containing it, for use as a http.Transport's
+// TLSClientConfig.RootCAs - what lets internal/api's Client verify Nomad
+// and Vault over mTLS instead of plain HTTP.
+func GetCAPool(caFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid certificates found in %s", caFile)
+	}
+
+	return pool, nil
+}
+
+// LoadClientCertificate loads a leaf certificate/key pair for use as a
+// http.Transport's TLSClientConfig.Certificates, so the client
+// authenticates itself to Nomad/Vault's mTLS listener, not just verifies
+// them.
+func LoadClientCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	return cert, nil
+}
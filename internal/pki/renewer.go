@@ -0,0 +1,82 @@
+package pki
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// Renewer re-issues a node's leaf certificate before it expires and
+// signals its agent to reload, the way Unsealer keeps Vault unsealed
+// across restarts. Run it in its own goroutine for the lifetime of the
+// agent process.
+type Renewer struct {
+	CADir      string
+	CertsDir   string
+	Opts       IssueOptions
+	CheckEvery time.Duration // defaults to 1h if zero
+	Reload     func() error  // called after a successful re-issue, e.g. to SIGHUP Nomad/Consul
+}
+
+// Run checks the node's leaf certificate every CheckEvery and re-issues it
+// once it's within RenewBefore of expiring, until ctx is cancelled.
+func (r *Renewer) Run(ctx context.Context) error {
+	interval := r.CheckEvery
+	if interval == 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := r.RenewIfNeeded(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RenewIfNeeded re-issues the node's leaf certificate if it's within
+// RenewBefore of expiring, calling Reload (if set) on success. Exported so
+// `styx pki rotate` can drive a single check without running the Run loop.
+func (r *Renewer) RenewIfNeeded() error {
+	certFile := certPath(r.CertsDir, r.Opts)
+
+	soon, err := ExpiresSoon(certFile)
+	if err != nil {
+		// No cert on disk yet (or unreadable) - issue one rather than
+		// treating this as fatal, the same way Bootstrapper.Run tolerates
+		// a first-boot Vault that hasn't been initialized yet.
+		soon = true
+	}
+	if !soon {
+		return nil
+	}
+
+	ca, err := LoadOrCreateCA(r.CADir)
+	if err != nil {
+		return fmt.Errorf("failed to load CA for renewal: %w", err)
+	}
+
+	if _, err := Issue(ca, r.CertsDir, r.Opts); err != nil {
+		return fmt.Errorf("failed to re-issue leaf certificate: %w", err)
+	}
+
+	if r.Reload != nil {
+		if err := r.Reload(); err != nil {
+			return fmt.Errorf("certificate renewed but reload failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func certPath(dir string, opts IssueOptions) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.pem", opts.NodeName, opts.Role))
+}
@@ -0,0 +1,99 @@
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ServiceCertOptions describes the leaf certificate IssueServiceCert mints
+// for a platform service (nomad-agent, vault-server, traefik) that
+// terminates TLS on its own listener, as opposed to Issue's node-level
+// Nomad/Consul RPC peer certs.
+type ServiceCertOptions struct {
+	// Service names the cert, e.g. "nomad-agent", "vault-server",
+	// "traefik" - also used for its on-disk filenames.
+	Service string
+
+	NodeName string
+
+	// TailscaleHostname is the node's Tailscale MagicDNS name (see
+	// network.GetTailscaleInfo), included as a SAN when non-empty so a
+	// peer reaching the service over the tailnet hostname still verifies.
+	TailscaleHostname string
+
+	AdvertiseIP string
+}
+
+// IssueServiceCert issues (or re-issues) a leaf certificate for a platform
+// service, valid for LeafValidity, with SANs covering 127.0.0.1, localhost,
+// opts.NodeName, and opts.TailscaleHostname - everywhere a local client
+// (runStatus, the services health checker) might dial it from. Files are
+// named after opts.Service under dir, so repeated issuance overwrites the
+// same pair in place.
+func IssueServiceCert(ca *CA, dir string, opts ServiceCertOptions) (*CertPaths, error) {
+	if opts.Service == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create certs directory: %w", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate leaf key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	dnsNames := []string{"localhost", opts.NodeName}
+	if opts.TailscaleHostname != "" {
+		dnsNames = append(dnsNames, opts.TailscaleHostname)
+	}
+	ips := []net.IP{net.ParseIP("127.0.0.1")}
+	if ip := net.ParseIP(opts.AdvertiseIP); ip != nil {
+		ips = append(ips, ip)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: opts.Service, Organization: []string{"styx"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(LeafValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign leaf certificate: %w", err)
+	}
+
+	certPath := filepath.Join(dir, opts.Service+".pem")
+	keyPath := filepath.Join(dir, opts.Service+"-key.pem")
+
+	if err := writePEM(certPath, "CERTIFICATE", certDER); err != nil {
+		return nil, fmt.Errorf("failed to write leaf certificate: %w", err)
+	}
+	if err := writePEM(keyPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)); err != nil {
+		return nil, fmt.Errorf("failed to write leaf key: %w", err)
+	}
+
+	return &CertPaths{
+		CAFile:   CAFile(dir),
+		CertFile: certPath,
+		KeyFile:  keyPath,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package proxy
+
+import "github.com/kessler-frost/styx/internal/diagnostic"
+
+// Diagnostics collects TCPProxy's per-connection gauges/counters/histograms
+// so a diagnostic.Server can expose them at /metrics, the same pattern
+// driver.Diagnostics uses for the Nomad driver's container metrics.
+// Package-level since a process hosts one proxy's worth of connections at a
+// time.
+var Diagnostics = diagnostic.NewRegistry()
+
+var (
+	// activeConnections tracks how many client<->target pairs TCPProxy is
+	// currently forwarding, incremented when a connection is accepted and
+	// decremented once both copy directions finish.
+	activeConnections = Diagnostics.AddGauge(diagnostic.NewGauge(
+		"styx_proxy_connections_active",
+		"Number of connections the proxy is currently forwarding.",
+	))
+
+	// connectionsTotal counts every accepted connection by outcome, so an
+	// operator can tell dial failures apart from connections that were
+	// forwarded and closed normally.
+	connectionsTotal = Diagnostics.AddCounter(diagnostic.NewCounterVec(
+		"styx_proxy_connections_total",
+		"Connections accepted by the proxy, by outcome.",
+		"outcome",
+	))
+
+	// bytesTotal counts bytes copied in each direction across all
+	// connections.
+	bytesTotal = Diagnostics.AddCounter(diagnostic.NewCounterVec(
+		"styx_proxy_bytes_total",
+		"Bytes copied by the proxy, by direction.",
+		"direction",
+	))
+
+	// connectionDurationSeconds buckets how long a forwarded connection
+	// stayed open, from accept to both directions closing.
+	connectionDurationSeconds = Diagnostics.AddHistogram(diagnostic.NewHistogram(
+		"styx_proxy_connection_duration_seconds",
+		"Time a forwarded connection stayed open.",
+		[]float64{1, 5, 15, 30, 60, 300, 900, 3600},
+	))
+)
@@ -0,0 +1,29 @@
+// Package proxy implements TCP, UDP, and TLS-SNI-aware forwarding for
+// exposing VM-hosted services on the host's network, with PROXY protocol
+// support and per-connection metrics for the targets that need them.
+package proxy
+
+// Proxy is the subset of behavior TCPProxy, UDPProxy, and SNIProxy share,
+// so the CLI can start, stop, and report on any of them uniformly without
+// caring which one is actually forwarding a given port.
+type Proxy interface {
+	// Start begins listening and forwarding. It blocks until Stop is
+	// called or an unrecoverable error occurs.
+	Start() error
+	// StartAsync starts the proxy in a goroutine and returns immediately,
+	// or an error if the listener couldn't be created.
+	StartAsync() error
+	// Stop closes the listener and all connections/sessions it is
+	// currently forwarding.
+	Stop()
+	// ListenAddr returns the address the proxy is listening on.
+	ListenAddr() string
+	// Stats returns a snapshot of the proxy's connection/session counters.
+	Stats() Stats
+}
+
+var (
+	_ Proxy = (*TCPProxy)(nil)
+	_ Proxy = (*UDPProxy)(nil)
+	_ Proxy = (*SNIProxy)(nil)
+)
@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// ProxyProtocolVersion selects whether TCPProxy prepends a PROXY protocol
+// header to the outbound dial, and which wire format it uses.
+type ProxyProtocolVersion string
+
+const (
+	// ProxyProtocolOff forwards connections with no PROXY protocol header.
+	ProxyProtocolOff ProxyProtocolVersion = "off"
+	// ProxyProtocolV1 prepends a human-readable PROXY protocol v1 header.
+	ProxyProtocolV1 ProxyProtocolVersion = "v1"
+	// ProxyProtocolV2 prepends a binary PROXY protocol v2 header.
+	ProxyProtocolV2 ProxyProtocolVersion = "v2"
+)
+
+// encodeProxyProtocolHeader builds a PROXY protocol header of the given
+// version describing a connection from src to dst, so a backend behind the
+// proxy (Traefik, Postgres, NATS) can recover the original client address
+// instead of seeing the proxy's loopback address. See
+// https://www.haproxy.org/download/2.8/doc/proxy-protocol.txt.
+func encodeProxyProtocolHeader(version ProxyProtocolVersion, src, dst net.Addr) ([]byte, error) {
+	switch version {
+	case ProxyProtocolV1:
+		return encodeProxyProtocolV1(src, dst)
+	case ProxyProtocolV2:
+		return encodeProxyProtocolV2(src, dst)
+	default:
+		return nil, fmt.Errorf("proxy protocol: unsupported version %q", version)
+	}
+}
+
+// encodeProxyProtocolV1 builds a PROXY protocol v1 header, e.g.
+// "PROXY TCP4 192.0.2.1 192.0.2.2 51337 80\r\n".
+func encodeProxyProtocolV1(src, dst net.Addr) ([]byte, error) {
+	srcTCP, ok := src.(*net.TCPAddr)
+	if !ok {
+		return nil, fmt.Errorf("proxy protocol v1: source address %v is not TCP", src)
+	}
+	dstTCP, ok := dst.(*net.TCPAddr)
+	if !ok {
+		return nil, fmt.Errorf("proxy protocol v1: destination address %v is not TCP", dst)
+	}
+
+	family := "TCP4"
+	if srcTCP.IP.To4() == nil || dstTCP.IP.To4() == nil {
+		family = "TCP6"
+	}
+	return []byte(fmt.Sprintf("PROXY %s %s %s %d %d\r\n", family, srcTCP.IP, dstTCP.IP, srcTCP.Port, dstTCP.Port)), nil
+}
+
+// proxyProtoV2Signature is the fixed 12-byte prefix identifying a PROXY
+// protocol v2 header.
+var proxyProtoV2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+const (
+	proxyProtoVersionCommand = 0x21 // version 2, PROXY command
+	proxyProtoTCPOverIPv4    = 0x11
+	proxyProtoTCPOverIPv6    = 0x21
+)
+
+// encodeProxyProtocolV2 builds a binary PROXY protocol v2 header. src/dst
+// must be *net.TCPAddr of the same IP family.
+func encodeProxyProtocolV2(src, dst net.Addr) ([]byte, error) {
+	srcTCP, ok := src.(*net.TCPAddr)
+	if !ok {
+		return nil, fmt.Errorf("proxy protocol v2: source address %v is not TCP", src)
+	}
+	dstTCP, ok := dst.(*net.TCPAddr)
+	if !ok {
+		return nil, fmt.Errorf("proxy protocol v2: destination address %v is not TCP", dst)
+	}
+
+	var famTransport byte
+	var addrs []byte
+	if srcIP4, dstIP4 := srcTCP.IP.To4(), dstTCP.IP.To4(); srcIP4 != nil && dstIP4 != nil {
+		famTransport = proxyProtoTCPOverIPv4
+		addrs = make([]byte, 12)
+		copy(addrs[0:4], srcIP4)
+		copy(addrs[4:8], dstIP4)
+		binary.BigEndian.PutUint16(addrs[8:10], uint16(srcTCP.Port))
+		binary.BigEndian.PutUint16(addrs[10:12], uint16(dstTCP.Port))
+	} else {
+		famTransport = proxyProtoTCPOverIPv6
+		addrs = make([]byte, 36)
+		copy(addrs[0:16], srcTCP.IP.To16())
+		copy(addrs[16:32], dstTCP.IP.To16())
+		binary.BigEndian.PutUint16(addrs[32:34], uint16(srcTCP.Port))
+		binary.BigEndian.PutUint16(addrs[34:36], uint16(dstTCP.Port))
+	}
+
+	header := make([]byte, 0, len(proxyProtoV2Signature)+4+len(addrs))
+	header = append(header, proxyProtoV2Signature...)
+	header = append(header, proxyProtoVersionCommand, famTransport)
+	length := make([]byte, 2)
+	binary.BigEndian.PutUint16(length, uint16(len(addrs)))
+	header = append(header, length...)
+	header = append(header, addrs...)
+	return header, nil
+}
@@ -0,0 +1,136 @@
+package proxy
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestEncodeProxyProtocolV1(t *testing.T) {
+	src := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 51337}
+	dst := &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 80}
+
+	got, err := encodeProxyProtocolV1(src, dst)
+	if err != nil {
+		t.Fatalf("encodeProxyProtocolV1 failed: %v", err)
+	}
+	want := "PROXY TCP4 192.0.2.1 192.0.2.2 51337 80\r\n"
+	if string(got) != want {
+		t.Errorf("encodeProxyProtocolV1 = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeProxyProtocolV1IPv6(t *testing.T) {
+	src := &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 51337}
+	dst := &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 80}
+
+	got, err := encodeProxyProtocolV1(src, dst)
+	if err != nil {
+		t.Fatalf("encodeProxyProtocolV1 failed: %v", err)
+	}
+	want := "PROXY TCP6 2001:db8::1 2001:db8::2 51337 80\r\n"
+	if string(got) != want {
+		t.Errorf("encodeProxyProtocolV1 = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeProxyProtocolV1RejectsNonTCP(t *testing.T) {
+	src := &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 51337}
+	dst := &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 80}
+
+	if _, err := encodeProxyProtocolV1(src, dst); err == nil {
+		t.Error("encodeProxyProtocolV1 with a non-TCP source succeeded, want error")
+	}
+}
+
+func TestEncodeProxyProtocolV2IPv4(t *testing.T) {
+	src := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 51337}
+	dst := &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 80}
+
+	got, err := encodeProxyProtocolV2(src, dst)
+	if err != nil {
+		t.Fatalf("encodeProxyProtocolV2 failed: %v", err)
+	}
+
+	if !bytes.Equal(got[:12], proxyProtoV2Signature) {
+		t.Fatalf("header signature = %x, want %x", got[:12], proxyProtoV2Signature)
+	}
+	if got[12] != proxyProtoVersionCommand {
+		t.Errorf("version/command byte = %#x, want %#x", got[12], proxyProtoVersionCommand)
+	}
+	if got[13] != proxyProtoTCPOverIPv4 {
+		t.Errorf("family/transport byte = %#x, want %#x (TCP over IPv4)", got[13], proxyProtoTCPOverIPv4)
+	}
+	wantLen := 12 // 4 (src IP) + 4 (dst IP) + 2 (src port) + 2 (dst port)
+	gotLen := int(got[14])<<8 | int(got[15])
+	if gotLen != wantLen {
+		t.Errorf("address block length = %d, want %d", gotLen, wantLen)
+	}
+	if len(got) != 16+wantLen {
+		t.Fatalf("header length = %d, want %d", len(got), 16+wantLen)
+	}
+
+	addrs := got[16:]
+	if !bytes.Equal(addrs[0:4], net.ParseIP("192.0.2.1").To4()) {
+		t.Errorf("source address bytes = %v, want 192.0.2.1", addrs[0:4])
+	}
+	if !bytes.Equal(addrs[4:8], net.ParseIP("192.0.2.2").To4()) {
+		t.Errorf("destination address bytes = %v, want 192.0.2.2", addrs[4:8])
+	}
+	if srcPort := int(addrs[8])<<8 | int(addrs[9]); srcPort != 51337 {
+		t.Errorf("source port = %d, want 51337", srcPort)
+	}
+	if dstPort := int(addrs[10])<<8 | int(addrs[11]); dstPort != 80 {
+		t.Errorf("destination port = %d, want 80", dstPort)
+	}
+}
+
+func TestEncodeProxyProtocolV2IPv6(t *testing.T) {
+	src := &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 51337}
+	dst := &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 80}
+
+	got, err := encodeProxyProtocolV2(src, dst)
+	if err != nil {
+		t.Fatalf("encodeProxyProtocolV2 failed: %v", err)
+	}
+
+	if got[13] != proxyProtoTCPOverIPv6 {
+		t.Errorf("family/transport byte = %#x, want %#x (TCP over IPv6)", got[13], proxyProtoTCPOverIPv6)
+	}
+	wantLen := 36 // 16 (src IP) + 16 (dst IP) + 2 (src port) + 2 (dst port)
+	gotLen := int(got[14])<<8 | int(got[15])
+	if gotLen != wantLen {
+		t.Errorf("address block length = %d, want %d", gotLen, wantLen)
+	}
+	if len(got) != 16+wantLen {
+		t.Fatalf("header length = %d, want %d", len(got), 16+wantLen)
+	}
+}
+
+func TestEncodeProxyProtocolV2RejectsNonTCP(t *testing.T) {
+	src := &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 51337}
+	dst := &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 80}
+
+	if _, err := encodeProxyProtocolV2(src, dst); err == nil {
+		t.Error("encodeProxyProtocolV2 with a non-TCP source succeeded, want error")
+	}
+}
+
+func TestEncodeProxyProtocolHeaderDispatchesByVersion(t *testing.T) {
+	src := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 51337}
+	dst := &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 80}
+
+	if _, err := encodeProxyProtocolHeader(ProxyProtocolOff, src, dst); err == nil {
+		t.Error("encodeProxyProtocolHeader(ProxyProtocolOff, ...) succeeded, want error")
+	}
+
+	v1, err := encodeProxyProtocolHeader(ProxyProtocolV1, src, dst)
+	if err != nil || len(v1) == 0 || v1[0] != 'P' {
+		t.Errorf("encodeProxyProtocolHeader(ProxyProtocolV1, ...) = %q, %v, want a PROXY v1 header", v1, err)
+	}
+
+	v2, err := encodeProxyProtocolHeader(ProxyProtocolV2, src, dst)
+	if err != nil || !bytes.Equal(v2[:12], proxyProtoV2Signature) {
+		t.Errorf("encodeProxyProtocolHeader(ProxyProtocolV2, ...) = %x, %v, want a PROXY v2 header", v2, err)
+	}
+}
@@ -0,0 +1,305 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SNIOptions configures an SNIProxy.
+type SNIOptions struct {
+	// Routes maps a TLS ClientHello's server_name to the target address it
+	// should be forwarded to (e.g. "grafana.example.ts.net" -> the
+	// Grafana job's address).
+	Routes map[string]string
+
+	// DefaultTarget is where connections are forwarded when server_name
+	// doesn't match any Routes entry, or the client sent no SNI at all.
+	// Required: without it, unmatched connections would have nowhere to
+	// go.
+	DefaultTarget string
+
+	// HandshakeTimeout bounds how long peekServerName waits to see a full
+	// ClientHello before giving up on the connection.
+	HandshakeTimeout time.Duration
+
+	// DialTimeout bounds how long connecting to the routed target may
+	// take.
+	DialTimeout time.Duration
+
+	// IdleTimeout closes a connection if neither side sends data for this
+	// long, same as TCPOptions.IdleTimeout.
+	IdleTimeout time.Duration
+}
+
+// DefaultSNIOptions returns the options SNIProxy uses when the caller
+// doesn't override them, other than DefaultTarget which has no sensible
+// default and must always be set.
+func DefaultSNIOptions(defaultTarget string) SNIOptions {
+	return SNIOptions{
+		DefaultTarget:    defaultTarget,
+		HandshakeTimeout: 10 * time.Second,
+		DialTimeout:      10 * time.Second,
+		IdleTimeout:      5 * time.Minute,
+	}
+}
+
+// errAbortAfterClientHello deliberately fails tls.Conn.Handshake right
+// after GetConfigForClient sees the ClientHello, so peekServerName can read
+// the SNI without ever completing a TLS handshake - SNIProxy routes the
+// raw bytes on to the real target, which terminates TLS itself.
+var errAbortAfterClientHello = errors.New("sni: aborting handshake after reading ClientHello")
+
+// SNIProxy multiplexes a single public TCP port across multiple TLS
+// backends by peeking each connection's ClientHello for its server_name
+// extension and routing accordingly, without terminating TLS itself - the
+// chosen target sees the original, unmodified TLS stream.
+type SNIProxy struct {
+	listenAddr string
+	opts       SNIOptions
+
+	listener net.Listener
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	accepted   uint64
+	active     int64
+	bytesIn    uint64
+	bytesOut   uint64
+	dialErrors uint64
+}
+
+// NewSNIProxy creates an SNIProxy listening on listenAddr and routing by
+// SNI per opts.Routes, falling back to opts.DefaultTarget.
+func NewSNIProxy(listenAddr string, opts SNIOptions) *SNIProxy {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SNIProxy{
+		listenAddr: listenAddr,
+		opts:       opts,
+		conns:      make(map[net.Conn]struct{}),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start begins listening and routing connections. This method blocks until
+// the proxy is stopped or an error occurs.
+func (p *SNIProxy) Start() error {
+	listener, err := net.Listen("tcp", p.listenAddr)
+	if err != nil {
+		return err
+	}
+	p.listener = listener
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-p.ctx.Done():
+				return nil
+			default:
+				return err
+			}
+		}
+
+		p.trackConn(conn, true)
+		go p.handleConn(conn)
+	}
+}
+
+// StartAsync starts the proxy in a goroutine and returns immediately.
+// Returns an error if the listener cannot be created.
+func (p *SNIProxy) StartAsync() error {
+	listener, err := net.Listen("tcp", p.listenAddr)
+	if err != nil {
+		return err
+	}
+	p.listener = listener
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				select {
+				case <-p.ctx.Done():
+					return
+				default:
+					continue
+				}
+			}
+
+			p.trackConn(conn, true)
+			go p.handleConn(conn)
+		}
+	}()
+
+	return nil
+}
+
+// Stop closes the listener and all active connections.
+func (p *SNIProxy) Stop() {
+	p.cancel()
+
+	if p.listener != nil {
+		p.listener.Close()
+	}
+
+	p.mu.Lock()
+	for conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = make(map[net.Conn]struct{})
+	p.mu.Unlock()
+}
+
+// ListenAddr returns the address the proxy is listening on.
+func (p *SNIProxy) ListenAddr() string {
+	return p.listenAddr
+}
+
+// Stats returns a snapshot of this proxy's connection counters.
+func (p *SNIProxy) Stats() Stats {
+	return Stats{
+		Accepted:   atomic.LoadUint64(&p.accepted),
+		Active:     atomic.LoadInt64(&p.active),
+		BytesIn:    atomic.LoadUint64(&p.bytesIn),
+		BytesOut:   atomic.LoadUint64(&p.bytesOut),
+		DialErrors: atomic.LoadUint64(&p.dialErrors),
+	}
+}
+
+func (p *SNIProxy) trackConn(conn net.Conn, add bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if add {
+		p.conns[conn] = struct{}{}
+	} else {
+		delete(p.conns, conn)
+	}
+}
+
+func (p *SNIProxy) handleConn(src net.Conn) {
+	defer src.Close()
+	defer p.trackConn(src, false)
+
+	atomic.AddUint64(&p.accepted, 1)
+	connectionsTotal.IncLabel("accepted")
+	activeConnections.Inc()
+	atomic.AddInt64(&p.active, 1)
+	start := time.Now()
+	defer func() {
+		activeConnections.Dec()
+		atomic.AddInt64(&p.active, -1)
+		connectionDurationSeconds.Observe(time.Since(start))
+	}()
+
+	serverName, peeked, err := peekServerName(src, p.opts.HandshakeTimeout)
+	if err != nil {
+		connectionsTotal.IncLabel("sni_peek_error")
+		return
+	}
+
+	targetAddr := p.opts.DefaultTarget
+	if route, ok := p.opts.Routes[serverName]; ok {
+		targetAddr = route
+	}
+	if targetAddr == "" {
+		connectionsTotal.IncLabel("no_route")
+		return
+	}
+
+	dialer := net.Dialer{Timeout: p.opts.DialTimeout}
+	dst, err := dialer.Dial("tcp", targetAddr)
+	if err != nil {
+		connectionsTotal.IncLabel("dial_error")
+		atomic.AddUint64(&p.dialErrors, 1)
+		return
+	}
+	defer dst.Close()
+	p.trackConn(dst, true)
+	defer p.trackConn(dst, false)
+
+	// Replay the ClientHello bytes peekServerName already consumed before
+	// relaying the rest of the stream, so the target sees the connection
+	// exactly as the client sent it.
+	if _, err := dst.Write(peeked); err != nil {
+		return
+	}
+	atomic.AddUint64(&p.bytesIn, uint64(len(peeked)))
+	bytesTotal.AddLabel("in", uint64(len(peeked)))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		n, _ := copyWithIdleTimeout(dst, src, p.opts.IdleTimeout)
+		atomic.AddUint64(&p.bytesIn, uint64(n))
+		bytesTotal.AddLabel("in", uint64(n))
+		dst.Close()
+	}()
+
+	go func() {
+		defer wg.Done()
+		n, _ := copyWithIdleTimeout(src, dst, p.opts.IdleTimeout)
+		atomic.AddUint64(&p.bytesOut, uint64(n))
+		bytesTotal.AddLabel("out", uint64(n))
+		src.Close()
+	}()
+
+	wg.Wait()
+}
+
+// peekConn wraps a net.Conn, recording every byte Read through it so those
+// bytes can be replayed to the real target afterwards.
+type peekConn struct {
+	net.Conn
+	peeked bytes.Buffer
+}
+
+func (c *peekConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.peeked.Write(b[:n])
+	}
+	return n, err
+}
+
+// peekServerName reads conn's TLS ClientHello far enough to learn its
+// server_name extension, without completing a TLS handshake, and returns
+// the raw bytes read so the caller can forward them on to the real target
+// unmodified. It works by running the server side of a TLS handshake just
+// far enough to parse the ClientHello, then deliberately aborting from
+// GetConfigForClient - the same technique SNI-routing proxies like
+// Traefik's TCP router use to avoid terminating TLS themselves.
+func peekServerName(conn net.Conn, timeout time.Duration) (serverName string, peeked []byte, err error) {
+	pc := &peekConn{Conn: conn}
+
+	if timeout > 0 {
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return "", nil, err
+		}
+		defer conn.SetReadDeadline(time.Time{})
+	}
+
+	var helloSeen bool
+	tlsConn := tls.Server(pc, &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			helloSeen = true
+			serverName = hello.ServerName
+			return nil, errAbortAfterClientHello
+		},
+	})
+
+	if hsErr := tlsConn.Handshake(); hsErr != nil && !helloSeen {
+		return "", pc.peeked.Bytes(), hsErr
+	}
+	return serverName, pc.peeked.Bytes(), nil
+}
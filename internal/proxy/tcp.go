@@ -2,34 +2,139 @@ package proxy
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/tracing"
 )
 
+// copyBufSize is the buffer size used by copyWithIdleTimeout, matching
+// io.Copy's own default so switching to the pooled buffer doesn't change
+// throughput.
+const copyBufSize = 32 * 1024
+
+var copyBufPool = sync.Pool{
+	New: func() any { return make([]byte, copyBufSize) },
+}
+
+// TCPOptions configures a TCPProxy. The zero value is not valid on its own;
+// use DefaultTCPOptions and override what needs to change.
+type TCPOptions struct {
+	// ProxyProtocol selects whether outbound dials are prefixed with a
+	// PROXY protocol header carrying the original client address.
+	// Defaults to ProxyProtocolOff.
+	ProxyProtocol ProxyProtocolVersion
+
+	// IdleTimeout closes a connection if neither side sends data for this
+	// long, so a client that disappears mid-stream (a half-open TCP
+	// connection) doesn't leak its two copy goroutines forever. Zero
+	// disables idle timeouts.
+	IdleTimeout time.Duration
+
+	// MaxConns caps how many connections are forwarded concurrently;
+	// connections beyond the cap are refused immediately. Zero means
+	// unlimited.
+	MaxConns int
+
+	// AllowCIDRs, if non-empty, restricts accepted connections to client
+	// IPs within one of these CIDRs (e.g. the tailnet's 100.64.0.0/10).
+	// Connections from elsewhere are closed without dialing the target.
+	AllowCIDRs []string
+
+	// DialTimeout bounds how long connecting to the target may take.
+	// Zero means no timeout.
+	DialTimeout time.Duration
+}
+
+// DefaultTCPOptions returns the options TCPProxy uses when the caller doesn't
+// override them: no PROXY protocol, a 5-minute idle timeout, no connection
+// cap, no CIDR restriction, and a 10-second dial timeout.
+func DefaultTCPOptions() TCPOptions {
+	return TCPOptions{
+		ProxyProtocol: ProxyProtocolOff,
+		IdleTimeout:   5 * time.Minute,
+		DialTimeout:   10 * time.Second,
+	}
+}
+
+// Stats is a point-in-time snapshot of a TCPProxy's connection counters, for
+// callers (e.g. a `styx system df`-style command) that want this proxy's
+// throughput without scraping the process-wide /metrics endpoint.
+type Stats struct {
+	Accepted   uint64
+	Active     int64
+	BytesIn    uint64
+	BytesOut   uint64
+	DialErrors uint64
+}
+
 // TCPProxy forwards TCP connections from a listen address to a target address.
 type TCPProxy struct {
 	listenAddr string
 	targetAddr string
+	opts       TCPOptions
+
+	allowedNets []*net.IPNet
+	sem         chan struct{} // nil if opts.MaxConns <= 0
 
 	listener net.Listener
 	mu       sync.Mutex
 	conns    map[net.Conn]struct{}
 	ctx      context.Context
 	cancel   context.CancelFunc
+
+	accepted   uint64
+	active     int64
+	bytesIn    uint64
+	bytesOut   uint64
+	dialErrors uint64
 }
 
-// NewTCPProxy creates a new TCP proxy that forwards from listenAddr to targetAddr.
-// Example: NewTCPProxy("0.0.0.0:10080", "192.168.64.4:80")
+// NewTCPProxy creates a new TCP proxy that forwards from listenAddr to
+// targetAddr using DefaultTCPOptions. Example:
+// NewTCPProxy("0.0.0.0:10080", "192.168.64.4:80")
 func NewTCPProxy(listenAddr, targetAddr string) *TCPProxy {
+	p, err := NewTCPProxyWithOptions(listenAddr, targetAddr, DefaultTCPOptions())
+	if err != nil {
+		// DefaultTCPOptions never fails validation (no CIDRs to parse).
+		panic(err)
+	}
+	return p
+}
+
+// NewTCPProxyWithOptions creates a TCP proxy with full control over PROXY
+// protocol, idle timeout, connection cap, client CIDR allowlist, and dial
+// timeout.
+func NewTCPProxyWithOptions(listenAddr, targetAddr string, opts TCPOptions) (*TCPProxy, error) {
+	allowedNets := make([]*net.IPNet, 0, len(opts.AllowCIDRs))
+	for _, cidr := range opts.AllowCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow-cidr %q: %w", cidr, err)
+		}
+		allowedNets = append(allowedNets, ipNet)
+	}
+
+	var sem chan struct{}
+	if opts.MaxConns > 0 {
+		sem = make(chan struct{}, opts.MaxConns)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	return &TCPProxy{
-		listenAddr: listenAddr,
-		targetAddr: targetAddr,
-		conns:      make(map[net.Conn]struct{}),
-		ctx:        ctx,
-		cancel:     cancel,
-	}
+		listenAddr:  listenAddr,
+		targetAddr:  targetAddr,
+		opts:        opts,
+		allowedNets: allowedNets,
+		sem:         sem,
+		conns:       make(map[net.Conn]struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
+	}, nil
 }
 
 // Start begins listening and forwarding connections.
@@ -115,6 +220,17 @@ func (p *TCPProxy) TargetAddr() string {
 	return p.targetAddr
 }
 
+// Stats returns a snapshot of this proxy's connection counters.
+func (p *TCPProxy) Stats() Stats {
+	return Stats{
+		Accepted:   atomic.LoadUint64(&p.accepted),
+		Active:     atomic.LoadInt64(&p.active),
+		BytesIn:    atomic.LoadUint64(&p.bytesIn),
+		BytesOut:   atomic.LoadUint64(&p.bytesOut),
+		DialErrors: atomic.LoadUint64(&p.dialErrors),
+	}
+}
+
 func (p *TCPProxy) trackConn(conn net.Conn, add bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -125,34 +241,147 @@ func (p *TCPProxy) trackConn(conn net.Conn, add bool) {
 	}
 }
 
+// allowed reports whether addr's IP is permitted to connect, per
+// opts.AllowCIDRs. An empty allowlist permits everything.
+func (p *TCPProxy) allowed(addr net.Addr) bool {
+	if len(p.allowedNets) == 0 {
+		return true
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, ipNet := range p.allowedNets {
+		if ipNet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *TCPProxy) handleConn(src net.Conn) {
+	_, span := tracing.StartSpan(context.Background(), "proxy.tcp.handle_conn")
+	span.SetAttribute("remote_addr", src.RemoteAddr().String())
+	span.SetAttribute("target_addr", p.targetAddr)
+	defer span.End()
+
 	defer src.Close()
 	defer p.trackConn(src, false)
 
-	// Dial the target
-	dst, err := net.Dial("tcp", p.targetAddr)
+	if p.sem != nil {
+		select {
+		case p.sem <- struct{}{}:
+			defer func() { <-p.sem }()
+		default:
+			connectionsTotal.IncLabel("max_conns")
+			span.SetError(fmt.Errorf("max connections reached"))
+			return
+		}
+	}
+
+	if !p.allowed(src.RemoteAddr()) {
+		connectionsTotal.IncLabel("denied")
+		span.SetError(fmt.Errorf("client denied by allow-cidrs"))
+		return
+	}
+
+	atomic.AddUint64(&p.accepted, 1)
+	connectionsTotal.IncLabel("accepted")
+	activeConnections.Inc()
+	atomic.AddInt64(&p.active, 1)
+	start := time.Now()
+	defer func() {
+		activeConnections.Dec()
+		atomic.AddInt64(&p.active, -1)
+		connectionDurationSeconds.Observe(time.Since(start))
+	}()
+
+	dialer := net.Dialer{Timeout: p.opts.DialTimeout}
+	dst, err := dialer.Dial("tcp", p.targetAddr)
 	if err != nil {
+		connectionsTotal.IncLabel("dial_error")
+		atomic.AddUint64(&p.dialErrors, 1)
+		span.SetError(err)
 		return
 	}
 	defer dst.Close()
 	p.trackConn(dst, true)
 	defer p.trackConn(dst, false)
 
-	// Copy data in both directions
+	if p.opts.ProxyProtocol != "" && p.opts.ProxyProtocol != ProxyProtocolOff {
+		header, err := encodeProxyProtocolHeader(p.opts.ProxyProtocol, src.RemoteAddr(), src.LocalAddr())
+		if err != nil {
+			connectionsTotal.IncLabel("proxy_protocol_error")
+			return
+		}
+		if _, err := dst.Write(header); err != nil {
+			connectionsTotal.IncLabel("proxy_protocol_error")
+			return
+		}
+	}
+
+	// Copy data in both directions. copyWithIdleTimeout refreshes a read
+	// deadline on every pass so a client that goes half-open (neither
+	// sending nor closing) gets its copy goroutines torn down after
+	// IdleTimeout instead of blocking on Read forever.
 	var wg sync.WaitGroup
 	wg.Add(2)
 
+	var bytesIn, bytesOut int64
 	go func() {
 		defer wg.Done()
-		io.Copy(dst, src)
+		n, _ := copyWithIdleTimeout(dst, src, p.opts.IdleTimeout)
+		atomic.AddUint64(&p.bytesIn, uint64(n))
+		atomic.StoreInt64(&bytesIn, n)
+		bytesTotal.AddLabel("in", uint64(n))
 		dst.Close() // Signal EOF to target
 	}()
 
 	go func() {
 		defer wg.Done()
-		io.Copy(src, dst)
+		n, _ := copyWithIdleTimeout(src, dst, p.opts.IdleTimeout)
+		atomic.AddUint64(&p.bytesOut, uint64(n))
+		atomic.StoreInt64(&bytesOut, n)
+		bytesTotal.AddLabel("out", uint64(n))
 		src.Close() // Signal EOF to source
 	}()
 
 	wg.Wait()
+	span.SetAttributeInt("bytes_in", bytesIn)
+	span.SetAttributeInt("bytes_out", bytesOut)
+}
+
+// copyWithIdleTimeout copies from src to dst using a pooled buffer,
+// refreshing src's read deadline before every read when idleTimeout is
+// positive. Unlike io.Copy, a src that never sends and never closes (a
+// half-open connection) unblocks once idleTimeout elapses instead of
+// pinning its goroutine for the life of the process.
+func copyWithIdleTimeout(dst io.Writer, src net.Conn, idleTimeout time.Duration) (int64, error) {
+	buf := copyBufPool.Get().([]byte)
+	defer copyBufPool.Put(buf)
+
+	var written int64
+	for {
+		if idleTimeout > 0 {
+			src.SetReadDeadline(time.Now().Add(idleTimeout))
+		}
+		nr, er := src.Read(buf)
+		if nr > 0 {
+			nw, ew := dst.Write(buf[:nr])
+			written += int64(nw)
+			if ew != nil {
+				return written, ew
+			}
+			if nr != nw {
+				return written, io.ErrShortWrite
+			}
+		}
+		if er != nil {
+			if er != io.EOF {
+				return written, er
+			}
+			break
+		}
+	}
+	return written, nil
 }
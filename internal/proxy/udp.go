@@ -0,0 +1,277 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// udpBufSize is large enough for a full UDP datagram under typical MTUs
+// (DNS, QUIC, WireGuard discovery all stay well under this).
+const udpBufSize = 64 * 1024
+
+var udpBufPool = sync.Pool{
+	New: func() any { return make([]byte, udpBufSize) },
+}
+
+// UDPOptions configures a UDPProxy.
+type UDPOptions struct {
+	// IdleTimeout evicts a client session if no packet has been seen in
+	// either direction for this long, since UDP has no close handshake to
+	// signal a session is done.
+	IdleTimeout time.Duration
+}
+
+// DefaultUDPOptions returns the options UDPProxy uses when the caller
+// doesn't override them: a 2-minute idle timeout, matching the NAT mapping
+// timeout most routers use for UDP.
+func DefaultUDPOptions() UDPOptions {
+	return UDPOptions{IdleTimeout: 2 * time.Minute}
+}
+
+// udpSession tracks one client's conversation with the target: a connected
+// UDP socket dialed to targetAddr, and the client address to relay target
+// replies back to.
+type udpSession struct {
+	clientAddr *net.UDPAddr
+	conn       *net.UDPConn
+	lastActive int64 // unix nano, accessed atomically
+}
+
+// UDPProxy forwards UDP datagrams from a listen address to a target
+// address, for protocols like DNS, QUIC, and WireGuard discovery that don't
+// fit TCPProxy's stream model. It has the same listen/target/Start/Stop
+// shape as TCPProxy, with client sessions (rather than connections) tracked
+// by address and evicted after IdleTimeout.
+type UDPProxy struct {
+	listenAddr string
+	targetAddr string
+	opts       UDPOptions
+
+	pc *net.UDPConn
+
+	mu       sync.Mutex
+	sessions map[string]*udpSession
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	accepted   uint64
+	active     int64
+	bytesIn    uint64
+	bytesOut   uint64
+	dialErrors uint64
+}
+
+// NewUDPProxy creates a new UDP proxy that forwards from listenAddr to
+// targetAddr using DefaultUDPOptions.
+func NewUDPProxy(listenAddr, targetAddr string) *UDPProxy {
+	return NewUDPProxyWithOptions(listenAddr, targetAddr, DefaultUDPOptions())
+}
+
+// NewUDPProxyWithOptions creates a UDP proxy with a custom idle timeout.
+func NewUDPProxyWithOptions(listenAddr, targetAddr string, opts UDPOptions) *UDPProxy {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &UDPProxy{
+		listenAddr: listenAddr,
+		targetAddr: targetAddr,
+		opts:       opts,
+		sessions:   make(map[string]*udpSession),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start begins listening and forwarding datagrams. This method blocks
+// until the proxy is stopped or an error occurs.
+func (p *UDPProxy) Start() error {
+	addr, err := net.ResolveUDPAddr("udp", p.listenAddr)
+	if err != nil {
+		return err
+	}
+	pc, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return err
+	}
+	p.pc = pc
+
+	go p.evictIdleSessions()
+	return p.readLoop()
+}
+
+// StartAsync starts the proxy in a goroutine and returns immediately.
+// Returns an error if the listener cannot be created.
+func (p *UDPProxy) StartAsync() error {
+	addr, err := net.ResolveUDPAddr("udp", p.listenAddr)
+	if err != nil {
+		return err
+	}
+	pc, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return err
+	}
+	p.pc = pc
+
+	go p.evictIdleSessions()
+	go p.readLoop()
+	return nil
+}
+
+func (p *UDPProxy) readLoop() error {
+	buf := make([]byte, udpBufSize)
+	for {
+		n, clientAddr, err := p.pc.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-p.ctx.Done():
+				return nil
+			default:
+				return err
+			}
+		}
+
+		sess, err := p.session(clientAddr)
+		if err != nil {
+			connectionsTotal.IncLabel("dial_error")
+			atomic.AddUint64(&p.dialErrors, 1)
+			continue
+		}
+
+		atomic.StoreInt64(&sess.lastActive, time.Now().UnixNano())
+		if _, err := sess.conn.Write(buf[:n]); err == nil {
+			atomic.AddUint64(&p.bytesIn, uint64(n))
+			bytesTotal.AddLabel("in", uint64(n))
+		}
+	}
+}
+
+// session returns the existing session for clientAddr, or dials a new one
+// to targetAddr and starts its reply-relaying goroutine.
+func (p *UDPProxy) session(clientAddr *net.UDPAddr) (*udpSession, error) {
+	key := clientAddr.String()
+
+	p.mu.Lock()
+	sess, ok := p.sessions[key]
+	p.mu.Unlock()
+	if ok {
+		return sess, nil
+	}
+
+	targetAddr, err := net.ResolveUDPAddr("udp", p.targetAddr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialUDP("udp", nil, targetAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	sess = &udpSession{clientAddr: clientAddr, conn: conn}
+	atomic.StoreInt64(&sess.lastActive, time.Now().UnixNano())
+
+	p.mu.Lock()
+	p.sessions[key] = sess
+	p.mu.Unlock()
+
+	atomic.AddUint64(&p.accepted, 1)
+	atomic.AddInt64(&p.active, 1)
+	connectionsTotal.IncLabel("accepted")
+	activeConnections.Inc()
+
+	go p.relayReplies(sess)
+	return sess, nil
+}
+
+// relayReplies reads target replies for one session and writes them back
+// to the client through the shared listening socket, until the session's
+// target connection is closed by evictIdleSessions.
+func (p *UDPProxy) relayReplies(sess *udpSession) {
+	defer func() {
+		atomic.AddInt64(&p.active, -1)
+		activeConnections.Dec()
+	}()
+
+	buf := udpBufPool.Get().([]byte)
+	defer udpBufPool.Put(buf)
+
+	for {
+		n, err := sess.conn.Read(buf)
+		if err != nil {
+			return
+		}
+		atomic.StoreInt64(&sess.lastActive, time.Now().UnixNano())
+		if _, err := p.pc.WriteToUDP(buf[:n], sess.clientAddr); err == nil {
+			atomic.AddUint64(&p.bytesOut, uint64(n))
+			bytesTotal.AddLabel("out", uint64(n))
+		}
+	}
+}
+
+// evictIdleSessions closes and forgets sessions that have seen no traffic
+// in either direction for IdleTimeout, which is what unblocks their
+// relayReplies goroutine (sess.conn.Read returns once conn is closed).
+func (p *UDPProxy) evictIdleSessions() {
+	if p.opts.IdleTimeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.opts.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := time.Now().Add(-p.opts.IdleTimeout).UnixNano()
+
+			p.mu.Lock()
+			for key, sess := range p.sessions {
+				if atomic.LoadInt64(&sess.lastActive) < cutoff {
+					sess.conn.Close()
+					delete(p.sessions, key)
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// Stop closes the listening socket and every active session.
+func (p *UDPProxy) Stop() {
+	p.cancel()
+
+	if p.pc != nil {
+		p.pc.Close()
+	}
+
+	p.mu.Lock()
+	for key, sess := range p.sessions {
+		sess.conn.Close()
+		delete(p.sessions, key)
+	}
+	p.mu.Unlock()
+}
+
+// ListenAddr returns the address the proxy is listening on.
+func (p *UDPProxy) ListenAddr() string {
+	return p.listenAddr
+}
+
+// TargetAddr returns the address the proxy forwards to.
+func (p *UDPProxy) TargetAddr() string {
+	return p.targetAddr
+}
+
+// Stats returns a snapshot of this proxy's session counters.
+func (p *UDPProxy) Stats() Stats {
+	return Stats{
+		Accepted:   atomic.LoadUint64(&p.accepted),
+		Active:     atomic.LoadInt64(&p.active),
+		BytesIn:    atomic.LoadUint64(&p.bytesIn),
+		BytesOut:   atomic.LoadUint64(&p.bytesOut),
+		DialErrors: atomic.LoadUint64(&p.dialErrors),
+	}
+}
@@ -0,0 +1,46 @@
+// Package readiness provides a single polling primitive for "wait until X is
+// up" checks that used to be copy-pasted per caller (vault's
+// waitForNomadJWKS, services' health-check loop): poll a probe on an
+// interval until it reports ready or timeout elapses.
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Probe reports whether the thing being waited on is ready. A non-nil error
+// is treated the same as ready=false - the caller keeps polling rather than
+// aborting, since most probes (an HTTP request that errors, a Nomad lookup
+// for a job that isn't registered yet) fail transiently while still coming
+// up.
+type Probe func() (ready bool, err error)
+
+// Wait polls probe every interval (defaulting to 2s if zero) until it
+// reports ready, ctx is cancelled, or timeout elapses. name is used only to
+// make the timeout error readable.
+func Wait(ctx context.Context, name string, timeout, interval time.Duration, probe Probe) error {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if ready, _ := probe(); ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout waiting for %s to become ready", name)
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,124 @@
+// Package secrets wraps the Nomad Variables API (/v1/var/) so job-rendering
+// code can seed and read secrets without hardcoding them into job HCL (see
+// internal/services's Grafana job, which reads its admin password through
+// TemplateStanza instead of a literal GF_SECURITY_ADMIN_PASSWORD value).
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SecretStore is a client for Nomad's Variables store.
+type SecretStore struct {
+	httpClient *http.Client
+	nomadAddr  string
+}
+
+// NewSecretStore creates a SecretStore against nomadAddr (e.g. "http://127.0.0.1:4646").
+func NewSecretStore(nomadAddr string) *SecretStore {
+	return &SecretStore{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		nomadAddr:  nomadAddr,
+	}
+}
+
+// variable mirrors a Nomad Variable's JSON representation.
+type variable struct {
+	Path  string            `json:"Path"`
+	Items map[string]string `json:"Items"`
+}
+
+// Put creates or replaces the Nomad Variable at path with kv.
+func (s *SecretStore) Put(path string, kv map[string]string) error {
+	body, err := json.Marshal(variable{Path: path, Items: kv})
+	if err != nil {
+		return fmt.Errorf("failed to encode variable: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.nomadAddr+"/v1/var/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Get returns the kv stored at path, or nil if no variable exists there.
+func (s *SecretStore) Get(path string) (map[string]string, error) {
+	resp, err := s.httpClient.Get(s.nomadAddr + "/v1/var/" + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var v variable
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode variable: %w", err)
+	}
+	return v.Items, nil
+}
+
+// List returns the paths of Nomad Variables under prefix ("" for all).
+func (s *SecretStore) List(prefix string) ([]string, error) {
+	url := s.nomadAddr + "/v1/vars"
+	if prefix != "" {
+		url += "?prefix=" + prefix
+	}
+
+	resp, err := s.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var vars []variable
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		return nil, fmt.Errorf("failed to decode variables: %w", err)
+	}
+
+	paths := make([]string, len(vars))
+	for i, v := range vars {
+		paths[i] = v.Path
+	}
+	return paths, nil
+}
+
+// TemplateStanza returns a Nomad "template" HCL stanza that reads key from
+// the Nomad Variable at path and writes "name=value" to destination with
+// env = true, so a task picks it up as an environment variable without it
+// ever being hardcoded into the job HCL.
+func TemplateStanza(path, key, name, destination string) string {
+	return fmt.Sprintf(`      template {
+        data        = <<EOF
+{{ with nomadVar %q }}%s={{ .%s }}{{ end }}
+EOF
+        destination = %q
+        env         = true
+      }
+`, path, name, key, destination)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReceiverType identifies how an AlertReceiver delivers notifications.
+type ReceiverType string
+
+const (
+	ReceiverWebhook ReceiverType = "webhook"
+	ReceiverEmail   ReceiverType = "email"
+	ReceiverSlack   ReceiverType = "slack"
+)
+
+// AlertReceiver is one notification target in an AlertConfig. Only the
+// fields matching Type need to be set.
+type AlertReceiver struct {
+	Name string
+	Type ReceiverType
+
+	// Webhook
+	WebhookURL string
+
+	// Email
+	EmailTo   string
+	EmailFrom string
+	SMTPHost  string // host:port
+
+	// Slack
+	SlackWebhookURL string
+	SlackChannel    string
+}
+
+// AlertConfig is a small typed stand-in for Alertmanager's YAML config,
+// rendered by Render into the file Nomad drops at local/alertmanager.yml.
+type AlertConfig struct {
+	Receivers []AlertReceiver
+	// Route names the default receiver; it must match one of Receivers.
+	Route string
+}
+
+// Render produces an Alertmanager YAML config from cfg. If cfg has no
+// receivers, it falls back to a "null" receiver that discards every alert,
+// so Alertmanager still starts cleanly with nothing configured.
+func (cfg AlertConfig) Render() (string, error) {
+	receivers := cfg.Receivers
+	route := cfg.Route
+	if len(receivers) == 0 {
+		receivers = []AlertReceiver{{Name: "null", Type: ReceiverWebhook}}
+		route = "null"
+	}
+
+	found := false
+	for _, r := range receivers {
+		if r.Name == route {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("alertmanager config: route %q does not match any receiver", route)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "route:\n  receiver: %s\n\nreceivers:\n", route)
+
+	for _, r := range receivers {
+		fmt.Fprintf(&b, "  - name: %s\n", r.Name)
+		switch r.Type {
+		case ReceiverWebhook:
+			if r.WebhookURL != "" {
+				fmt.Fprintf(&b, "    webhook_configs:\n      - url: %s\n", r.WebhookURL)
+			}
+		case ReceiverEmail:
+			fmt.Fprintf(&b, "    email_configs:\n      - to: %s\n        from: %s\n        smarthost: %s\n", r.EmailTo, r.EmailFrom, r.SMTPHost)
+		case ReceiverSlack:
+			fmt.Fprintf(&b, "    slack_configs:\n      - api_url: %s\n        channel: %s\n", r.SlackWebhookURL, r.SlackChannel)
+		default:
+			return "", fmt.Errorf("alertmanager config: receiver %q has unknown type %q", r.Name, r.Type)
+		}
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/kessler-frost/styx/internal/authz"
+)
+
+// NewAuthorizingNomadProxy returns an http.Handler that reverse-proxies to
+// a Nomad agent's HTTP API at nomadAddr, gating every request through
+// policy via authz.RequireCapability instead of trusting Nomad's own API
+// (bound on 0.0.0.0, so reachable from any tailnet peer - see
+// config.ServerConfigTemplate) to authenticate its callers. Mount this on
+// a Tailscale-reachable listener in place of pointing clients straight at
+// Nomad.
+//
+// Capability tiers: job submission and mutation under /v1/job or /v1/jobs
+// need authz.CapDeploy; GET under /v1/job or one of the other read-only
+// paths readOnlyPathPrefixes lists needs authz.CapRead (also satisfied by
+// CapDeploy/CapAdmin, since they rank above it - see authz.Policy.Has);
+// every other endpoint, including GET on /v1/agent, /v1/operator, and
+// /v1/regions, needs authz.CapAdmin, since that's where node and
+// cluster-level operations like force-leave and raft reconfiguration live.
+func NewAuthorizingNomadProxy(nomadAddr string, policy *authz.Policy) (http.Handler, error) {
+	target, err := url.Parse(nomadAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nomad addr %s: %w", nomadAddr, err)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authz.RequireCapability(policy, requiredCapability(r), proxy).ServeHTTP(w, r)
+	}), nil
+}
+
+// readOnlyPathPrefixes are the non-job endpoints a GET is allowed to reach
+// with only authz.CapRead. Deliberately an allowlist, not "every GET":
+// /v1/agent, /v1/operator, and /v1/regions are also GET-able but expose
+// cluster topology and snapshot data an operator-only, not read-only,
+// identity should see - see requiredCapability.
+var readOnlyPathPrefixes = []string{
+	"/v1/nodes",
+	"/v1/node/",
+	"/v1/allocation/",
+	"/v1/allocations",
+	"/v1/client/allocation/",
+}
+
+// requiredCapability maps a Nomad API request to the capability it needs,
+// per NewAuthorizingNomadProxy's doc comment.
+func requiredCapability(r *http.Request) authz.Capability {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/job"):
+		if r.Method == http.MethodGet {
+			return authz.CapRead
+		}
+		return authz.CapDeploy
+	case r.Method == http.MethodGet && isReadOnlyPath(r.URL.Path):
+		return authz.CapRead
+	default:
+		return authz.CapAdmin
+	}
+}
+
+func isReadOnlyPath(path string) bool {
+	for _, prefix := range readOnlyPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kessler-frost/styx/internal/authz"
+)
+
+func TestRequiredCapability(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         authz.Capability
+	}{
+		{http.MethodGet, "/v1/job/web", authz.CapRead},
+		{http.MethodPost, "/v1/job/web", authz.CapDeploy},
+		{http.MethodPost, "/v1/jobs", authz.CapDeploy},
+		{http.MethodGet, "/v1/jobs", authz.CapRead},
+		{http.MethodGet, "/v1/nodes", authz.CapRead},
+		{http.MethodGet, "/v1/node/abc123", authz.CapRead},
+		{http.MethodGet, "/v1/allocation/abc123", authz.CapRead},
+		{http.MethodGet, "/v1/client/allocation/abc123/stats", authz.CapRead},
+
+		// Not on the read-only allowlist, even though they're GET: these
+		// expose cluster topology/snapshot data an admin should gate.
+		{http.MethodGet, "/v1/agent/self", authz.CapAdmin},
+		{http.MethodGet, "/v1/agent/members", authz.CapAdmin},
+		{http.MethodGet, "/v1/operator/snapshot", authz.CapAdmin},
+		{http.MethodGet, "/v1/regions", authz.CapAdmin},
+
+		// Writes outside /v1/job always need admin.
+		{http.MethodPost, "/v1/node/abc123/drain", authz.CapAdmin},
+		{http.MethodPut, "/v1/var/secret/foo", authz.CapAdmin},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if got := requiredCapability(req); got != tt.want {
+			t.Errorf("requiredCapability(%s %s) = %v, want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
@@ -38,14 +38,31 @@ type JobStatus struct {
 
 // RunJob submits a job to Nomad (HCL format)
 func (c *NomadClient) RunJob(hcl string) error {
+	return c.RunJobInRegion(hcl, "")
+}
+
+// RunJobInRegion is RunJob targeted at a specific federated Nomad region
+// (see `styx region add`), the Styx-API equivalent of `nomad job run
+// -region=<x>`. An empty region submits to whichever region this client's
+// addr answers for, same as RunJob.
+func (c *NomadClient) RunJobInRegion(hcl, region string) error {
 	// First, parse the HCL to JSON
-	jsonJob, err := c.parseHCL(hcl)
+	parsedJob, err := c.ParseHCL(hcl)
 	if err != nil {
 		return fmt.Errorf("failed to parse job HCL: %w", err)
 	}
 
+	jsonJob, err := json.Marshal(map[string]interface{}{"Job": parsedJob})
+	if err != nil {
+		return err
+	}
+
 	// Submit the job
-	req, err := http.NewRequest("POST", c.addr+"/v1/jobs", bytes.NewReader(jsonJob))
+	url := c.addr + "/v1/jobs"
+	if region != "" {
+		url += "?region=" + region
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonJob))
 	if err != nil {
 		return err
 	}
@@ -68,8 +85,10 @@ func (c *NomadClient) RunJob(hcl string) error {
 	return nil
 }
 
-// parseHCL converts HCL job spec to JSON using Nomad's parse endpoint
-func (c *NomadClient) parseHCL(hcl string) ([]byte, error) {
+// ParseHCL converts an HCL job spec to its canonical JSON representation
+// using Nomad's /v1/jobs/parse endpoint, without submitting it. This is
+// what backs both RunJob and Plan.
+func (c *NomadClient) ParseHCL(hcl string) (map[string]interface{}, error) {
 	payload := map[string]interface{}{
 		"JobHCL":       hcl,
 		"Canonicalize": true,
@@ -99,23 +118,41 @@ func (c *NomadClient) parseHCL(hcl string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to parse HCL (status %d): %s", resp.StatusCode, string(respBody))
 	}
 
-	// The response is the parsed job JSON
-	parsedJob, err := io.ReadAll(resp.Body)
-	if err != nil {
+	var job map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
 		return nil, err
 	}
 
-	// Wrap in the expected format for job submission
-	var job interface{}
-	if err := json.Unmarshal(parsedJob, &job); err != nil {
-		return nil, err
+	return job, nil
+}
+
+// GetJob returns the full registered job JSON, or nil if the job doesn't
+// exist.
+func (c *NomadClient) GetJob(jobID string) (map[string]interface{}, error) {
+	resp, err := c.client.Get(c.addr + "/v1/job/" + jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
 	}
+	defer resp.Body.Close()
 
-	wrapped := map[string]interface{}{
-		"Job": job,
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
 	}
 
-	return json.Marshal(wrapped)
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job (status %d): unable to read response body", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to get job (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var job map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
 }
 
 // StopJob stops a running job
@@ -213,6 +250,47 @@ func (c *NomadClient) ListJobs() ([]JobStatus, error) {
 	return result, nil
 }
 
+// ServiceInstance is one registered instance of a Nomad-provider service, as
+// returned by /v1/service/:name.
+type ServiceInstance struct {
+	Address string `json:"Address"`
+	Port    int    `json:"Port"`
+}
+
+// GetServiceAddresses returns the registered addresses for every instance of
+// a Nomad-provider service (see the `service { provider = "nomad" }` stanzas
+// in jobs.go), for intents.MiddlewareTags to build IP allowlists from.
+func (c *NomadClient) GetServiceAddresses(name string) ([]string, error) {
+	resp, err := c.client.Get(c.addr + "/v1/service/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service %s (status %d): unable to read response body", name, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to get service %s (status %d): %s", name, resp.StatusCode, string(body))
+	}
+
+	var instances []ServiceInstance
+	if err := json.NewDecoder(resp.Body).Decode(&instances); err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, len(instances))
+	for i, inst := range instances {
+		addrs[i] = inst.Address
+	}
+	return addrs, nil
+}
+
 // IsHealthy checks if Nomad is responding
 func (c *NomadClient) IsHealthy() bool {
 	resp, err := c.client.Get(c.addr + "/v1/agent/health")
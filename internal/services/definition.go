@@ -0,0 +1,431 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HealthCheck describes how waitForServices decides a service is up, as an
+// alternative to polling Nomad job status (which only tells you an
+// allocation is running, not that the service inside it is accepting
+// traffic). Set directly on a built-in Service (see PlatformServices) or
+// parsed from a user-defined Definition's health_check block.
+type HealthCheck struct {
+	// Type is "http", "tcp", "exec", or "nomad". "nomad" makes the fallback
+	// behavior (poll Nomad job status) explicit instead of implicit when no
+	// HealthCheck is declared at all - useful for a service that wants a
+	// non-default Interval/Timeout on that same fallback.
+	Type string
+
+	// Path is the HTTP path to GET (http checks only); a non-2xx response,
+	// or a response other than ExpectStatus if it's set, counts as
+	// unhealthy. Combined with Port, this is the "URL" for the check: the
+	// host part is resolved at check time via Nomad service discovery
+	// (see resolveAddr) rather than hardcoded, since a service's address
+	// isn't known until Nomad places it.
+	Path string
+
+	// ExpectStatus is the HTTP status Path must return to count as healthy
+	// (http checks only). Defaults to "any 2xx" if zero.
+	ExpectStatus int
+
+	// Port is the name of the service's Nomad port label (e.g. "http") used
+	// to resolve an address via NomadClient.GetServiceAddresses for http and
+	// tcp checks.
+	Port string
+
+	// Command is run locally via os/exec for exec checks; a non-zero exit
+	// counts as unhealthy. Runs on the styx CLI host, not inside the
+	// allocation, so it's only useful for checks reachable from there (e.g.
+	// a Tailscale-routed URL).
+	Command string
+
+	// Interval is how often to poll. Defaults to 5s if zero.
+	Interval time.Duration
+
+	// Timeout bounds a single check attempt. Defaults to 3s if zero.
+	Timeout time.Duration
+}
+
+// Definition is a user-authored platform service, loaded from a
+// services.d/*.hcl file (see LoadDefinitions), alongside the built-in
+// services driven by defaultSpecs in registry.go.
+type Definition struct {
+	Name        string
+	Description string
+
+	// DependsOn names other services (built-in or user-defined) that must
+	// be deployed and healthy before this one is; see orderForDeploy.
+	DependsOn []string
+
+	Image   string
+	Version string
+
+	// Inputs lists the interpolation variables this service's Job template
+	// needs resolved before rendering (e.g. "tailscale_ip", "vault_addr");
+	// see resolveInputs.
+	Inputs []string
+
+	HealthCheck *HealthCheck
+	Update      UpdateStrategy
+
+	// Job is the raw text/template source for the service's Nomad job HCL,
+	// taken verbatim from the definition's job heredoc.
+	Job string
+}
+
+// LoadDefinitions reads every *.hcl file in dir as a Definition. A missing
+// dir is not an error - it just means no user-defined services are
+// configured - but a malformed file is, so a typo doesn't silently drop a
+// service the operator expects to be deployed.
+func LoadDefinitions(dir string) ([]Definition, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var defs []Definition
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".hcl") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		def, err := parseDefinition(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// parseDefinition reads a single service definition file, a hand-rolled
+// reader the same way internal/intents/hcl.go is rather than a general HCL
+// parser:
+//
+//	service "grafana-alt" {
+//	  description = "Alternate Grafana"
+//	  depends_on  = ["loki"]
+//	  image       = "grafana/grafana"
+//	  version     = "11.0.0"
+//	  inputs      = ["tailscale_ip"]
+//
+//	  health_check "http" {
+//	    path     = "/api/health"
+//	    port     = "http"
+//	    interval = "10s"
+//	    timeout  = "2s"
+//	  }
+//
+//	  update {
+//	    max_parallel     = 1
+//	    min_healthy_time = "10s"
+//	    healthy_deadline = "3m"
+//	    auto_revert      = true
+//	    canary           = 0
+//	  }
+//
+//	  job = <<EOT
+//	  job "grafana-alt" {
+//	    ...
+//	  }
+//	  EOT
+//	}
+func parseDefinition(data string) (Definition, error) {
+	lines := strings.Split(data, "\n")
+	var def Definition
+	var found bool
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, ok := matchBlockHeader(line, "service")
+		if !ok {
+			return Definition{}, fmt.Errorf("line %d: expected 'service \"name\" {', got %q", i+1, line)
+		}
+		if found {
+			return Definition{}, fmt.Errorf("line %d: only one service block per file is supported", i+1)
+		}
+		found = true
+		def.Name = name
+
+		end, err := parseServiceBody(lines, i+1, &def)
+		if err != nil {
+			return Definition{}, err
+		}
+		i = end
+	}
+
+	if !found {
+		return Definition{}, fmt.Errorf("no service block found")
+	}
+	return def, nil
+}
+
+func parseServiceBody(lines []string, start int, def *Definition) (int, error) {
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case line == "}":
+			return i, nil
+		case strings.HasPrefix(line, "health_check "):
+			typ, ok := matchBlockHeader(line, "health_check")
+			if !ok {
+				return 0, fmt.Errorf("line %d: expected 'health_check \"type\" {', got %q", i+1, line)
+			}
+			hc := &HealthCheck{Type: typ}
+			end, err := parseHealthCheckBody(lines, i+1, hc)
+			if err != nil {
+				return 0, err
+			}
+			def.HealthCheck = hc
+			i = end
+		case line == "update {":
+			end, err := parseUpdateBody(lines, i+1, &def.Update)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		case strings.HasPrefix(line, "job = <<"):
+			delim := strings.TrimSpace(strings.TrimPrefix(line, "job = <<"))
+			if delim == "" {
+				return 0, fmt.Errorf("line %d: job heredoc needs a delimiter, e.g. job = <<EOT", i+1)
+			}
+			body, end, err := readHeredoc(lines, i+1, delim)
+			if err != nil {
+				return 0, err
+			}
+			def.Job = body
+			i = end
+		default:
+			key, value, err := splitField(line)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if err := applyServiceField(def, key, value); err != nil {
+				return 0, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated service block")
+}
+
+func applyServiceField(def *Definition, key, value string) error {
+	switch key {
+	case "description":
+		s, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		def.Description = s
+	case "depends_on":
+		list, err := parseStringList(value)
+		if err != nil {
+			return err
+		}
+		def.DependsOn = list
+	case "image":
+		s, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		def.Image = s
+	case "version":
+		s, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		def.Version = s
+	case "inputs":
+		list, err := parseStringList(value)
+		if err != nil {
+			return err
+		}
+		def.Inputs = list
+	default:
+		return fmt.Errorf("unknown field %q", key)
+	}
+	return nil
+}
+
+func parseHealthCheckBody(lines []string, start int, hc *HealthCheck) (int, error) {
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case line == "}":
+			return i, nil
+		default:
+			key, value, err := splitField(line)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+			switch key {
+			case "path":
+				hc.Path, err = parseStringValue(value)
+			case "expect_status":
+				hc.ExpectStatus, err = strconv.Atoi(value)
+			case "port":
+				hc.Port, err = parseStringValue(value)
+			case "command":
+				hc.Command, err = parseStringValue(value)
+			case "interval":
+				hc.Interval, err = parseDurationValue(value)
+			case "timeout":
+				hc.Timeout, err = parseDurationValue(value)
+			default:
+				err = fmt.Errorf("unknown health_check field %q", key)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated health_check block")
+}
+
+func parseUpdateBody(lines []string, start int, strategy *UpdateStrategy) (int, error) {
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case line == "}":
+			return i, nil
+		default:
+			key, value, err := splitField(line)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+			switch key {
+			case "max_parallel":
+				strategy.MaxParallel, err = strconv.Atoi(value)
+			case "min_healthy_time":
+				strategy.MinHealthyTime, err = parseDurationValue(value)
+			case "healthy_deadline":
+				strategy.HealthyDeadline, err = parseDurationValue(value)
+			case "auto_revert":
+				strategy.AutoRevert, err = parseBoolValue(value)
+			case "canary":
+				strategy.Canary, err = strconv.Atoi(value)
+			default:
+				err = fmt.Errorf("unknown update field %q", key)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated update block")
+}
+
+// matchBlockHeader parses a line of the form `keyword "name" {` and reports
+// whether line has that shape, returning name if so.
+func matchBlockHeader(line, keyword string) (string, bool) {
+	prefix := keyword + " \""
+	if !strings.HasPrefix(line, prefix) {
+		return "", false
+	}
+	rest := line[len(prefix):]
+	end := strings.Index(rest, "\"")
+	if end == -1 {
+		return "", false
+	}
+	if strings.TrimSpace(rest[end+1:]) != "{" {
+		return "", false
+	}
+	return rest[:end], true
+}
+
+// readHeredoc reads lines starting at start until one equals delim exactly,
+// returning the joined body (excluding the delimiter line) and that line's
+// index.
+func readHeredoc(lines []string, start int, delim string) (body string, end int, err error) {
+	var b strings.Builder
+	for i := start; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == delim {
+			return b.String(), i, nil
+		}
+		b.WriteString(lines[i])
+		b.WriteString("\n")
+	}
+	return "", 0, fmt.Errorf("unterminated job heredoc (missing %s)", delim)
+}
+
+func splitField(line string) (key, value string, err error) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("expected 'field = value', got %q", line)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+func parseStringValue(value string) (string, error) {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return "", fmt.Errorf("expected a quoted string value, got %q", value)
+	}
+	return value[1 : len(value)-1], nil
+}
+
+func parseStringList(value string) ([]string, error) {
+	if len(value) < 2 || value[0] != '[' || value[len(value)-1] != ']' {
+		return nil, fmt.Errorf("expected a list value, got %q", value)
+	}
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+	if inner == "" {
+		return nil, nil
+	}
+
+	var out []string
+	for _, part := range strings.Split(inner, ",") {
+		s, err := parseStringValue(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func parseDurationValue(value string) (time.Duration, error) {
+	s, err := parseStringValue(value)
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(s)
+}
+
+func parseBoolValue(value string) (bool, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("expected true or false, got %q", value)
+	}
+}
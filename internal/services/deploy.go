@@ -0,0 +1,181 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Deployment is Nomad's rollout status for a job (GET /v1/job/:id/deployment),
+// summarizing per-group canary/healthy progress as RunJob's update stanza
+// (see UpdateStrategy) rolls a new image out.
+type Deployment struct {
+	ID         string
+	Status     string // running, successful, failed, cancelled, paused
+	TaskGroups map[string]DeploymentTaskGroup
+}
+
+// DeploymentTaskGroup is one task group's progress within a Deployment.
+type DeploymentTaskGroup struct {
+	DesiredTotal    int
+	DesiredCanaries int
+	HealthyAllocs   int
+	UnhealthyAllocs int
+}
+
+// WaitForDeployment polls jobID's latest deployment until it reaches a
+// terminal status or timeout elapses, printing canary/healthy counts as
+// they change. Returns an error if the deployment fails, is cancelled, or
+// doesn't resolve before timeout, so callers like `styx service upgrade`
+// know to revert.
+//
+// sinceDeploymentID, if non-empty, is the ID of the deployment that was
+// already latest before this rollout was triggered; deployments with that
+// ID are treated the same as "none yet" so a stale success left over from
+// a previous rollout isn't mistaken for this one's result.
+func (c *NomadClient) WaitForDeployment(jobID, sinceDeploymentID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var lastStatus string
+
+	for {
+		dep, err := c.getLatestDeployment(jobID)
+		if err != nil {
+			return err
+		}
+		if dep != nil && dep.ID == sinceDeploymentID {
+			dep = nil
+		}
+
+		if dep != nil && dep.Status != lastStatus {
+			lastStatus = dep.Status
+			for name, tg := range dep.TaskGroups {
+				fmt.Printf("  %s: %d/%d healthy (%d canary)\n", name, tg.HealthyAllocs, tg.DesiredTotal, tg.DesiredCanaries)
+			}
+		}
+
+		switch {
+		case dep == nil:
+			// No new deployment registered yet (e.g. job has no update
+			// stanza, or Nomad hasn't created it); keep polling until
+			// timeout in case one appears shortly.
+		case dep.Status == "successful":
+			return nil
+		case dep.Status == "failed" || dep.Status == "cancelled":
+			return fmt.Errorf("deployment %s for %s: %s", dep.ID, jobID, dep.Status)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s to deploy", jobID)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// UpgradeService pins name to imageTag, redeploys its job, and waits for
+// the rollout to finish (see UpdateStrategy's update stanza and
+// WaitForDeployment). If the rollout doesn't succeed within timeout, the
+// pin is reverted to its previous image:tag and the previous job is
+// redeployed, so a bad upgrade doesn't strand the service degraded.
+//
+// grafana and prometheus aren't supported here: their HCL needs more than
+// a pin (grafana's admin-password secret, prometheus's NOMAD_ADDR and rule
+// files), so pin them with `service pin` and redeploy with
+// `observability enable` instead.
+func UpgradeService(name, imageTag string, timeout time.Duration) error {
+	if name == "grafana" || name == "prometheus" {
+		return fmt.Errorf("%s is upgraded via 'service pin' followed by 'observability enable', not 'service upgrade'", name)
+	}
+
+	spec, err := DefaultRegistry.Spec(name)
+	if err != nil {
+		return err
+	}
+	previousTag := spec.Image + ":" + spec.Version
+
+	previousDeployment, err := DefaultClient().getLatestDeployment(name)
+	if err != nil {
+		return fmt.Errorf("failed to check current deployment of %s: %w", name, err)
+	}
+	var previousDeploymentID string
+	if previousDeployment != nil {
+		previousDeploymentID = previousDeployment.ID
+	}
+
+	if err := DefaultRegistry.Pin(name, imageTag); err != nil {
+		return fmt.Errorf("failed to pin %s: %w", name, err)
+	}
+
+	redeploy := func() error {
+		hcl, err := getServiceHCL(Service{Name: name})
+		if err != nil {
+			return err
+		}
+		return DefaultClient().RunJob(hcl)
+	}
+
+	if err := redeploy(); err != nil {
+		if revertErr := DefaultRegistry.Pin(name, previousTag); revertErr != nil {
+			return fmt.Errorf("failed to deploy %s (%w) and revert pin failed: %v", name, err, revertErr)
+		}
+		return fmt.Errorf("failed to deploy %s: %w", name, err)
+	}
+
+	if err := DefaultClient().WaitForDeployment(name, previousDeploymentID, timeout); err != nil {
+		fmt.Printf("Rollout of %s did not become healthy (%v), reverting to %s...\n", name, err, previousTag)
+		if revertErr := DefaultRegistry.Pin(name, previousTag); revertErr != nil {
+			return fmt.Errorf("upgrade failed (%w) and revert pin failed: %v", err, revertErr)
+		}
+		if revertErr := redeploy(); revertErr != nil {
+			return fmt.Errorf("upgrade failed (%w) and revert deploy failed: %v", err, revertErr)
+		}
+		return fmt.Errorf("upgrade failed, reverted to %s: %w", previousTag, err)
+	}
+
+	return nil
+}
+
+// getLatestDeployment fetches jobID's latest deployment, or nil if the job
+// has never had one registered.
+func (c *NomadClient) getLatestDeployment(jobID string) (*Deployment, error) {
+	resp, err := c.client.Get(c.addr + "/v1/job/" + jobID + "/deployment")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment for %s: %w", jobID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get deployment for %s (status %d)", jobID, resp.StatusCode)
+	}
+
+	var raw struct {
+		ID         string
+		Status     string
+		TaskGroups map[string]struct {
+			DesiredTotal    int
+			DesiredCanaries int
+			HealthyAllocs   int
+			UnhealthyAllocs int
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+	if raw.ID == "" {
+		return nil, nil
+	}
+
+	dep := &Deployment{ID: raw.ID, Status: raw.Status, TaskGroups: map[string]DeploymentTaskGroup{}}
+	for name, tg := range raw.TaskGroups {
+		dep.TaskGroups[name] = DeploymentTaskGroup{
+			DesiredTotal:    tg.DesiredTotal,
+			DesiredCanaries: tg.DesiredCanaries,
+			HealthyAllocs:   tg.HealthyAllocs,
+			UnhealthyAllocs: tg.UnhealthyAllocs,
+		}
+	}
+	return dep, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// StopOptions configures DrainJob's wait for a stopped job's allocations to
+// actually finish exiting.
+type StopOptions struct {
+	// Timeout is how long to wait for every allocation to reach a terminal
+	// ClientStatus before giving up. Zero means DefaultDrainTimeout.
+	Timeout time.Duration
+	// Purge also removes the job from Nomad's state entirely (the
+	// "?purge=true" query param), instead of leaving it stopped.
+	Purge bool
+	// Force stops the job even if DrainJob can't confirm every allocation
+	// drained before Timeout elapses, rather than returning an error.
+	Force bool
+}
+
+// DefaultDrainTimeout is how long DrainJob waits for a job's allocations to
+// reach a terminal state before giving up, unless StopOptions.Timeout
+// overrides it.
+const DefaultDrainTimeout = 30 * time.Second
+
+// drainPollInterval is how often DrainJob re-polls a job's allocations
+// while waiting for them to drain.
+const drainPollInterval = 500 * time.Millisecond
+
+// AllocStatus is one allocation's draining-relevant state, as returned by
+// GET /v1/job/:id/allocations.
+type AllocStatus struct {
+	ID           string
+	ClientStatus string // running, pending, complete, failed, lost
+}
+
+// terminal reports whether a is done running, the way DrainJob waits for.
+func (a AllocStatus) terminal() bool {
+	switch a.ClientStatus {
+	case "complete", "failed", "lost":
+		return true
+	default:
+		return false
+	}
+}
+
+// GetJobAllocations returns jobID's allocations' client-reported status, for
+// DrainJob to poll.
+func (c *NomadClient) GetJobAllocations(jobID string) ([]AllocStatus, error) {
+	resp, err := c.client.Get(c.addr + "/v1/job/" + jobID + "/allocations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocations for %s: %w", jobID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get allocations for %s (status %d)", jobID, resp.StatusCode)
+	}
+
+	var stubs []struct {
+		ID           string `json:"ID"`
+		ClientStatus string `json:"ClientStatus"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stubs); err != nil {
+		return nil, err
+	}
+
+	allocs := make([]AllocStatus, len(stubs))
+	for i, s := range stubs {
+		allocs[i] = AllocStatus{ID: s.ID, ClientStatus: s.ClientStatus}
+	}
+	return allocs, nil
+}
+
+// DrainJob stops jobID and waits for every one of its allocations to reach
+// a terminal ClientStatus (complete, failed, or lost) before returning, so
+// callers like `styx stop` know the job's containers have actually exited
+// and released their ports instead of guessing with a fixed sleep.
+//
+// If opts.Timeout elapses before every allocation drains, DrainJob returns
+// an error unless opts.Force is set, in which case it returns nil anyway so
+// the caller can proceed with shutdown regardless.
+func (c *NomadClient) DrainJob(jobID string, opts StopOptions) error {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultDrainTimeout
+	}
+
+	url := c.addr + "/v1/job/" + jobID
+	if opts.Purge {
+		url += "?purge=true"
+	}
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to stop job %s: %w", jobID, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to stop job %s (status %d)", jobID, resp.StatusCode)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		allocs, err := c.GetJobAllocations(jobID)
+		if err != nil {
+			return err
+		}
+
+		drained := true
+		for _, a := range allocs {
+			if !a.terminal() {
+				drained = false
+				break
+			}
+		}
+		if drained {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if opts.Force {
+				return nil
+			}
+			return fmt.Errorf("timed out after %s waiting for %s's allocations to drain", timeout, jobID)
+		}
+		time.Sleep(drainPollInterval)
+	}
+}
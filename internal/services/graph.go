@@ -0,0 +1,27 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GraphDOT renders PlatformServices' dependency graph (see Service.DependsOn)
+// as a Graphviz DOT digraph, for `styx services graph`. An edge points from
+// a dependency to the service that depends on it, matching the order
+// orderForDeploy deploys them in.
+func GraphDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph services {\n")
+
+	for _, svc := range PlatformServices {
+		b.WriteString(fmt.Sprintf("  %q;\n", svc.Name))
+	}
+	for _, svc := range PlatformServices {
+		for _, dep := range svc.DependsOn {
+			b.WriteString(fmt.Sprintf("  %q -> %q;\n", dep, svc.Name))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
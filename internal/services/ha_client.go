@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/network"
+)
+
+// Peer is one Nomad server HAClient can route requests to.
+type Peer struct {
+	IP       string
+	Hostname string
+	Online   bool // kept current by Run; true until a request or probe says otherwise
+}
+
+// LeaderChange is sent on HAClient.Changes() whenever the peer HAClient
+// routes requests to switches.
+type LeaderChange struct {
+	Old string // peer IP, "" if there was none
+	New string // peer IP, "" if every known peer is now unreachable
+}
+
+// HAClient wraps a ranked pool of NomadClients built from
+// network.DiscoverNomadServers, instead of the single fixed addr a plain
+// NomadClient targets. Every method routes to the current known-good peer
+// and fails over to the next on connect error or a non-2xx response, the
+// same approach HA subnet routers in the Tailscale ecosystem use to pick a
+// live router out of a priority-ranked set. Run keeps the pool's Online
+// state and current leader current in the background, so failover doesn't
+// have to wait for a request to discover a peer is gone.
+type HAClient struct {
+	mu      sync.RWMutex
+	peers   []Peer
+	clients map[string]*NomadClient
+	current string // IP of the peer currently routed to, "" if none
+
+	changes chan LeaderChange
+
+	// ProbeInterval is how often Run re-probes /v1/agent/members and
+	// /v1/status/leader. Defaults to 3s, keeping promotion of a new
+	// primary within Run's documented ~10s convergence window.
+	ProbeInterval time.Duration
+
+	httpClient *http.Client
+}
+
+// NewHAClient builds an HAClient from network.DiscoverNomadServers' result,
+// in the order given - callers with a reason to prefer one server (e.g.
+// the one they joined through) should put it first.
+func NewHAClient(servers []network.NomadServer) *HAClient {
+	c := &HAClient{
+		clients:    make(map[string]*NomadClient, len(servers)),
+		changes:    make(chan LeaderChange, 1),
+		httpClient: &http.Client{Timeout: 2 * time.Second},
+	}
+	for _, s := range servers {
+		c.peers = append(c.peers, Peer{IP: s.IP, Hostname: s.Hostname, Online: true})
+		c.clients[s.IP] = NewNomadClient(fmt.Sprintf("http://%s:4646", s.IP))
+	}
+	if len(c.peers) > 0 {
+		c.current = c.peers[0].IP
+	}
+	return c
+}
+
+// CurrentLeader returns the IP of the peer HAClient is currently routing
+// requests to, or "" if every known peer is unreachable.
+func (c *HAClient) CurrentLeader() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current
+}
+
+// Changes returns a channel HAClient sends a LeaderChange on every time
+// CurrentLeader switches, for the API layer/UI to display which server is
+// active. Buffered by 1 - a slow reader only ever misses intermediate
+// changes, never the latest one.
+func (c *HAClient) Changes() <-chan LeaderChange {
+	return c.changes
+}
+
+// Peers returns a snapshot of HAClient's known servers and their current
+// Online state.
+func (c *HAClient) Peers() []Peer {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	peers := make([]Peer, len(c.peers))
+	copy(peers, c.peers)
+	return peers
+}
+
+// RunJob submits hcl via the current known-good peer, failing over to the
+// next known peer on error.
+func (c *HAClient) RunJob(hcl string) error {
+	return c.withFailover(func(nc *NomadClient) error { return nc.RunJob(hcl) })
+}
+
+// StopJob stops jobID via the current known-good peer, failing over to the
+// next known peer on error.
+func (c *HAClient) StopJob(jobID string) error {
+	return c.withFailover(func(nc *NomadClient) error { return nc.StopJob(jobID) })
+}
+
+// GetJob is NomadClient.GetJob with failover.
+func (c *HAClient) GetJob(jobID string) (map[string]interface{}, error) {
+	var job map[string]interface{}
+	err := c.withFailover(func(nc *NomadClient) error {
+		j, err := nc.GetJob(jobID)
+		job = j
+		return err
+	})
+	return job, err
+}
+
+// GetJobStatus is NomadClient.GetJobStatus with failover.
+func (c *HAClient) GetJobStatus(jobID string) (*JobStatus, error) {
+	var status *JobStatus
+	err := c.withFailover(func(nc *NomadClient) error {
+		s, err := nc.GetJobStatus(jobID)
+		status = s
+		return err
+	})
+	return status, err
+}
+
+// ListJobs is NomadClient.ListJobs with failover.
+func (c *HAClient) ListJobs() ([]JobStatus, error) {
+	var jobs []JobStatus
+	err := c.withFailover(func(nc *NomadClient) error {
+		j, err := nc.ListJobs()
+		jobs = j
+		return err
+	})
+	return jobs, err
+}
+
+// GetServiceAddresses is NomadClient.GetServiceAddresses with failover.
+func (c *HAClient) GetServiceAddresses(name string) ([]string, error) {
+	var addrs []string
+	err := c.withFailover(func(nc *NomadClient) error {
+		a, err := nc.GetServiceAddresses(name)
+		addrs = a
+		return err
+	})
+	return addrs, err
+}
+
+// withFailover calls fn against the current known-good peer, trying every
+// other known online peer in ranked order on error, and promotes the first
+// one fn succeeds against.
+func (c *HAClient) withFailover(fn func(*NomadClient) error) error {
+	order := c.order()
+	if len(order) == 0 {
+		return fmt.Errorf("no known Nomad servers")
+	}
+
+	var lastErr error
+	for _, ip := range order {
+		nc, ok := c.clientFor(ip)
+		if !ok {
+			continue
+		}
+		if err := fn(nc); err != nil {
+			lastErr = err
+			c.demote(ip)
+			continue
+		}
+		c.promote(ip)
+		return nil
+	}
+	return fmt.Errorf("all %d known Nomad servers failed: %w", len(order), lastErr)
+}
+
+// order returns known peer IPs to try, current first, then the rest of the
+// online peers in ranked order.
+func (c *HAClient) order() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ips := make([]string, 0, len(c.peers))
+	if c.current != "" {
+		ips = append(ips, c.current)
+	}
+	for _, p := range c.peers {
+		if p.Online && p.IP != c.current {
+			ips = append(ips, p.IP)
+		}
+	}
+	return ips
+}
+
+func (c *HAClient) clientFor(ip string) (*NomadClient, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	nc, ok := c.clients[ip]
+	return nc, ok
+}
+
+// promote makes ip the current leader, sending a LeaderChange if it
+// actually changed.
+func (c *HAClient) promote(ip string) {
+	c.mu.Lock()
+	old := c.current
+	c.current = ip
+	c.mu.Unlock()
+
+	if old != ip {
+		select {
+		case c.changes <- LeaderChange{Old: old, New: ip}:
+		default:
+			// Slow reader - drop it, CurrentLeader()/Peers() still reflect
+			// the latest state.
+		}
+	}
+}
+
+// demote marks ip offline, so order() stops trying it until Run's probe
+// loop (or a future successful request) brings it back. withFailover moves
+// on to try the next peer in its own call's order() snapshot; demote
+// doesn't pick a replacement itself.
+func (c *HAClient) demote(ip string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.peers {
+		if c.peers[i].IP == ip {
+			c.peers[i].Online = false
+		}
+	}
+	if c.current == ip {
+		c.current = ""
+	}
+}
+
+// Run periodically re-probes every known peer's /v1/agent/members (to
+// confirm it's still a live cluster member, the same check
+// network.DiscoverNomadServers uses to find one) and /v1/status/leader (to
+// prefer routing to the Raft leader over a follower), and marks offline any
+// peer whose Tailscale link has dropped per network.GetTailscalePeers'
+// Online flag, until ctx is cancelled. Run this in its own goroutine for
+// the lifetime of whatever holds the HAClient.
+func (c *HAClient) Run(ctx context.Context) error {
+	interval := c.ProbeInterval
+	if interval == 0 {
+		interval = 3 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.probeOnce()
+		}
+	}
+}
+
+func (c *HAClient) probeOnce() {
+	tsOnline := make(map[string]bool)
+	for _, p := range network.GetTailscalePeers() {
+		tsOnline[p.IP] = p.Online
+	}
+
+	c.mu.Lock()
+	ips := make([]string, len(c.peers))
+	for i, p := range c.peers {
+		ips[i] = p.IP
+	}
+	c.mu.Unlock()
+
+	online := make(map[string]bool, len(ips))
+	var leader string
+	for _, ip := range ips {
+		reachable := isClusterMember(c.httpClient, ip)
+		if ts, known := tsOnline[ip]; known && !ts {
+			reachable = false
+		}
+		online[ip] = reachable
+
+		if reachable && leader == "" {
+			if l, ok := statusLeader(c.httpClient, ip); ok {
+				leader = l
+			}
+		}
+	}
+
+	c.mu.Lock()
+	for i := range c.peers {
+		c.peers[i].Online = online[c.peers[i].IP]
+	}
+	promoted := ""
+	switch {
+	case leader != "" && online[leader]:
+		if c.current != leader {
+			promoted = leader
+		}
+	case c.current == "" || !online[c.current]:
+		for _, p := range c.peers {
+			if p.Online {
+				promoted = p.IP
+				break
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	if promoted != "" {
+		c.promote(promoted)
+	}
+}
+
+// isClusterMember reports whether ip's Nomad agent is reachable and knows
+// of at least one cluster member, the same probe
+// network.DiscoverNomadServers uses to decide a peer is actually running a
+// Nomad server.
+func isClusterMember(client *http.Client, ip string) bool {
+	resp, err := client.Get(fmt.Sprintf("http://%s:4646/v1/agent/members", ip))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	var result struct {
+		Members []struct {
+			Name string `json:"Name"`
+		} `json:"Members"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false
+	}
+	return len(result.Members) > 0
+}
+
+// statusLeader asks ip's Nomad agent for the cluster's Raft leader via
+// /v1/status/leader, which returns an "ip:rpc-port" string, and returns
+// just the IP.
+func statusLeader(client *http.Client, ip string) (string, bool) {
+	resp, err := client.Get(fmt.Sprintf("http://%s:4646/v1/status/leader", ip))
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+
+	var leader string
+	if err := json.NewDecoder(resp.Body).Decode(&leader); err != nil {
+		return "", false
+	}
+	leader = strings.Trim(leader, `"`)
+	if leader == "" {
+		return "", false
+	}
+
+	host, _, err := net.SplitHostPort(leader)
+	if err != nil {
+		return "", false
+	}
+	return host, true
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"time"
+)
+
+// defaultHealthCheckInterval and defaultHealthCheckTimeout apply when a
+// Definition's HealthCheck leaves Interval/Timeout at zero.
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+	defaultHealthCheckTimeout  = 3 * time.Second
+)
+
+// probe runs hc once and reports whether it passed.
+func (hc *HealthCheck) probe(client *NomadClient, serviceName string) (bool, error) {
+	timeout := hc.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+
+	switch hc.Type {
+	case "http":
+		return hc.probeHTTP(client, serviceName, timeout)
+	case "tcp":
+		return hc.probeTCP(client, serviceName, timeout)
+	case "exec":
+		return hc.probeExec(timeout)
+	case "nomad":
+		return hc.probeNomad(client, serviceName)
+	default:
+		return false, fmt.Errorf("unknown health check type %q", hc.Type)
+	}
+}
+
+// healthCheckTarget returns the Nomad-registered service name serviceHealthy
+// should probe for svc. Usually that's just svc.Name, but a job can
+// register several named services for different ports (see
+// templates/traefik.hcl.tmpl) - traefik's own ping endpoint lives on its
+// dashboard port, registered separately as "traefik-dashboard".
+func healthCheckTarget(svc Service) string {
+	if svc.Name == "traefik" {
+		return "traefik-dashboard"
+	}
+	return svc.Name
+}
+
+// resolveAddr looks up serviceName's registered Nomad-provider address for
+// hc.Port, the same lookup intents.MiddlewareTags uses to build allowlists.
+func (hc *HealthCheck) resolveAddr(client *NomadClient, serviceName string) (string, error) {
+	addrs, err := client.GetServiceAddresses(serviceName)
+	if err != nil {
+		return "", err
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("no registered address for %s", serviceName)
+	}
+	return addrs[0], nil
+}
+
+func (hc *HealthCheck) probeHTTP(client *NomadClient, serviceName string, timeout time.Duration) (bool, error) {
+	addr, err := hc.resolveAddr(client, serviceName)
+	if err != nil {
+		return false, err
+	}
+
+	httpClient := http.Client{Timeout: timeout}
+	resp, err := httpClient.Get(fmt.Sprintf("http://%s%s", addr, hc.Path))
+	if err != nil {
+		return false, nil
+	}
+	defer resp.Body.Close()
+
+	if hc.ExpectStatus != 0 {
+		return resp.StatusCode == hc.ExpectStatus, nil
+	}
+	return resp.StatusCode >= 200 && resp.StatusCode < 300, nil
+}
+
+func (hc *HealthCheck) probeTCP(client *NomadClient, serviceName string, timeout time.Duration) (bool, error) {
+	addr, err := hc.resolveAddr(client, serviceName)
+	if err != nil {
+		return false, err
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return false, nil
+	}
+	conn.Close()
+	return true, nil
+}
+
+// probeNomad polls Nomad job status directly, the same check every service
+// gets implicitly when it declares no HealthCheck at all - available as an
+// explicit Type for a service that wants a non-default Interval/Timeout on
+// it.
+func (hc *HealthCheck) probeNomad(client *NomadClient, serviceName string) (bool, error) {
+	status, err := client.GetJobStatus(serviceName)
+	if err != nil {
+		return false, err
+	}
+	return status != nil && status.Status == "running", nil
+}
+
+func (hc *HealthCheck) probeExec(timeout time.Duration) (bool, error) {
+	if hc.Command == "" {
+		return false, fmt.Errorf("exec health check has no command")
+	}
+
+	cmd := exec.Command("sh", "-c", hc.Command)
+	done := make(chan error, 1)
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		return err == nil, nil
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		return false, nil
+	}
+}
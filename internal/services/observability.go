@@ -0,0 +1,137 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/secrets"
+)
+
+// grafanaSecretPath is where the Grafana admin password lives as a Nomad
+// Variable, read into the job via secrets.TemplateStanza instead of being
+// hardcoded into grafana.hcl.tmpl.
+const grafanaSecretPath = "nomad/jobs/grafana"
+
+// DeployObservability deploys the full node-exporter/cadvisor/loki/grafana/
+// prometheus stack in one shot (see cmd/styx's `observability enable`).
+// These services aren't in PlatformServices because, unlike nats/dragonfly/
+// traefik, they're optional and node-exporter/cadvisor are system jobs with
+// no deploy-time Tailscale dependency of their own.
+func DeployObservability() error {
+	client := DefaultClient()
+
+	tsInfo := network.GetTailscaleInfo()
+	if !tsInfo.Running {
+		return fmt.Errorf("tailscale is required for the observability stack but not running")
+	}
+
+	ruleFiles, err := LoadRuleFiles(filepath.Join(DefaultRegistry.ConfigDir, "prometheus", "rules"))
+	if err != nil {
+		return fmt.Errorf("failed to load prometheus rules: %w", err)
+	}
+
+	if err := ensureGrafanaPassword(); err != nil {
+		return fmt.Errorf("failed to seed grafana admin password: %w", err)
+	}
+	adminPasswordTemplate := secrets.TemplateStanza(grafanaSecretPath, "admin_password", "GF_SECURITY_ADMIN_PASSWORD", "secrets/grafana.env")
+
+	jobs := []struct {
+		name string
+		hcl  func() (string, error)
+	}{
+		{"node-exporter", NodeExporterJobHCL},
+		{"cadvisor", CadvisorJobHCL},
+		{"loki", func() (string, error) { return DefaultRegistry.Render("loki", nil) }},
+		{"grafana", func() (string, error) {
+			return DefaultRegistry.Render("grafana", map[string]string{"ADMIN_PASSWORD_TEMPLATE": adminPasswordTemplate})
+		}},
+		{"prometheus", func() (string, error) { return PrometheusJobHCL(tsInfo.IP, ruleFiles) }},
+	}
+
+	for _, j := range jobs {
+		fmt.Printf("  Deploying %s...\n", j.name)
+		hcl, err := j.hcl()
+		if err != nil {
+			return fmt.Errorf("failed to generate HCL for %s: %w", j.name, err)
+		}
+		hcl = applyIntents(j.name, hcl)
+		if j.name == "grafana" || j.name == "prometheus" {
+			hcl = ApplyTLS(hcl, j.name)
+		}
+		if err := client.RunJob(hcl); err != nil {
+			return fmt.Errorf("failed to deploy %s: %w", j.name, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureGrafanaPassword makes sure nomad/jobs/grafana/admin_password exists
+// as a Nomad Variable, generating and persisting a random one on first boot
+// and appending it to ~/.styx/credentials so the operator can find it.
+func ensureGrafanaPassword() error {
+	store := secrets.NewSecretStore("http://127.0.0.1:4646")
+
+	existing, err := store.Get(grafanaSecretPath)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	password, err := generateRandomPassword(16)
+	if err != nil {
+		return fmt.Errorf("failed to generate grafana admin password: %w", err)
+	}
+
+	if err := store.Put(grafanaSecretPath, map[string]string{"admin_password": password}); err != nil {
+		return err
+	}
+
+	return appendCredential("grafana admin password", password)
+}
+
+// appendCredential records a generated secret in ~/.styx/credentials so an
+// operator can find it later; the file is created with owner-only
+// permissions and is append-only.
+func appendCredential(label, value string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	path := filepath.Join(home, ".styx", "credentials")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s: %s\n", label, value)
+	return err
+}
+
+// generateRandomPassword returns a random alphanumeric string of length,
+// mirroring the generator in internal/vault/setup.go for services that seed
+// their own credentials the same way.
+func generateRandomPassword(length int) (string, error) {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random password: %w", err)
+		}
+		b[i] = charset[n.Int64()]
+	}
+	return string(b), nil
+}
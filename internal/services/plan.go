@@ -0,0 +1,277 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Resources is the subset of a task's resource allocation that Plan diffs.
+type Resources struct {
+	CPU      int `json:"cpu,omitempty"`
+	MemoryMB int `json:"memory_mb,omitempty"`
+}
+
+// TaskDiff describes what would change for a single task if a plan were
+// applied.
+type TaskDiff struct {
+	TaskGroup     string               `json:"task_group"`
+	Task          string               `json:"task"`
+	ImageFrom     string               `json:"image_from,omitempty"`
+	ImageTo       string               `json:"image_to,omitempty"`
+	EnvAdded      map[string]string    `json:"env_added,omitempty"`
+	EnvRemoved    map[string]string    `json:"env_removed,omitempty"`
+	EnvChanged    map[string][2]string `json:"env_changed,omitempty"` // name -> [old, new]
+	ResourcesFrom *Resources           `json:"resources_from,omitempty"`
+	ResourcesTo   *Resources           `json:"resources_to,omitempty"`
+
+	changed bool // set by taskDiff; not serialized
+}
+
+// PlanResult is the outcome of planning a service's Nomad job against
+// whatever is currently registered, borrowing the `nomad plan` idiom.
+type PlanResult struct {
+	Service    string     `json:"service"`
+	Registered bool       `json:"registered"`
+	Changed    bool       `json:"changed"`
+	Diffs      []TaskDiff `json:"diffs,omitempty"`
+	Hash       string     `json:"hash"`
+}
+
+// Plan renders the Nomad jobspec that would be submitted for a platform
+// service and diffs it against whatever is currently registered, without
+// submitting anything.
+func Plan(name string) (*PlanResult, error) {
+	svc := GetService(name)
+	if svc == nil {
+		return nil, fmt.Errorf("unknown service: %s", name)
+	}
+
+	hcl, err := getServiceHCL(*svc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate HCL for %s: %w", name, err)
+	}
+
+	client := DefaultClient()
+
+	proposed, err := client.ParseHCL(hcl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse job HCL for %s: %w", name, err)
+	}
+
+	current, err := client.GetJob(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch registered job for %s: %w", name, err)
+	}
+
+	diffs := diffJobs(current, proposed)
+
+	return &PlanResult{
+		Service:    name,
+		Registered: current != nil,
+		Changed:    current == nil || len(diffs) > 0,
+		Diffs:      diffs,
+		Hash:       hashJob(proposed),
+	}, nil
+}
+
+// hashJob returns a stable hex-encoded hash of a parsed job, for use with
+// `styx services start --check`.
+func hashJob(job map[string]interface{}) string {
+	// encoding/json sorts map keys alphabetically, so this is stable across
+	// calls for an unchanged job.
+	data, err := json.Marshal(job)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// diffJobs compares the task groups of a currently-registered job (nil if
+// not registered) against a proposed job and returns per-task diffs.
+func diffJobs(current, proposed map[string]interface{}) []TaskDiff {
+	currentTasks := indexTasks(current)
+	proposedTasks := indexTasks(proposed)
+
+	var diffs []TaskDiff
+	for key, proposedTask := range proposedTasks {
+		currentTask, existed := currentTasks[key]
+		if !existed {
+			d := taskDiff(nil, proposedTask)
+			d.changed = true
+			diffs = append(diffs, d)
+			continue
+		}
+		if d := taskDiff(currentTask, proposedTask); d.hasChanges() {
+			diffs = append(diffs, d)
+		}
+	}
+
+	for key, currentTask := range currentTasks {
+		if _, stillPresent := proposedTasks[key]; stillPresent {
+			continue
+		}
+		diffs = append(diffs, TaskDiff{
+			TaskGroup:     currentTask.group,
+			Task:          currentTask.task,
+			ImageFrom:     currentTask.image,
+			ResourcesFrom: currentTask.resources,
+			changed:       true,
+		})
+	}
+
+	return diffs
+}
+
+// taskKey identifies a task within a job by its task-group/task name pair.
+type taskKey struct {
+	group string
+	task  string
+}
+
+type taskInfo struct {
+	group     string
+	task      string
+	image     string
+	env       map[string]string
+	resources *Resources
+}
+
+// indexTasks flattens a parsed Nomad job's TaskGroups/Tasks into a map
+// keyed by group/task name for easy comparison. Returns an empty map for a
+// nil job.
+func indexTasks(job map[string]interface{}) map[taskKey]*taskInfo {
+	result := make(map[taskKey]*taskInfo)
+	if job == nil {
+		return result
+	}
+
+	groups, _ := job["TaskGroups"].([]interface{})
+	for _, g := range groups {
+		group, ok := g.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		groupName, _ := group["Name"].(string)
+
+		tasks, _ := group["Tasks"].([]interface{})
+		for _, t := range tasks {
+			task, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			taskName, _ := task["Name"].(string)
+
+			info := &taskInfo{
+				group: groupName,
+				task:  taskName,
+				env:   stringMap(task["Env"]),
+			}
+
+			if config, ok := task["Config"].(map[string]interface{}); ok {
+				info.image, _ = config["image"].(string)
+			}
+
+			if res, ok := task["Resources"].(map[string]interface{}); ok {
+				info.resources = &Resources{
+					CPU:      intField(res["CPU"]),
+					MemoryMB: intField(res["MemoryMB"]),
+				}
+			}
+
+			result[taskKey{group: groupName, task: taskName}] = info
+		}
+	}
+
+	return result
+}
+
+func stringMap(v interface{}) map[string]string {
+	raw, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		}
+	}
+	return result
+}
+
+func intField(v interface{}) int {
+	f, ok := v.(float64) // encoding/json decodes all JSON numbers as float64
+	if !ok {
+		return 0
+	}
+	return int(f)
+}
+
+// taskDiff computes the diff between a current task (nil if it doesn't
+// exist yet) and a proposed task.
+func taskDiff(current *taskInfo, proposed *taskInfo) TaskDiff {
+	d := TaskDiff{TaskGroup: proposed.group, Task: proposed.task}
+
+	var currentImage string
+	var currentEnv map[string]string
+	if current != nil {
+		currentImage = current.image
+		currentEnv = current.env
+		d.ResourcesFrom = current.resources
+	}
+	d.ResourcesTo = proposed.resources
+
+	if currentImage != proposed.image {
+		d.ImageFrom = currentImage
+		d.ImageTo = proposed.image
+		d.changed = true
+	}
+
+	for k, v := range proposed.env {
+		old, existed := currentEnv[k]
+		if !existed {
+			if d.EnvAdded == nil {
+				d.EnvAdded = map[string]string{}
+			}
+			d.EnvAdded[k] = v
+			d.changed = true
+		} else if old != v {
+			if d.EnvChanged == nil {
+				d.EnvChanged = map[string][2]string{}
+			}
+			d.EnvChanged[k] = [2]string{old, v}
+			d.changed = true
+		}
+	}
+	for k, v := range currentEnv {
+		if _, stillPresent := proposed.env[k]; !stillPresent {
+			if d.EnvRemoved == nil {
+				d.EnvRemoved = map[string]string{}
+			}
+			d.EnvRemoved[k] = v
+			d.changed = true
+		}
+	}
+
+	if !resourcesEqual(d.ResourcesFrom, d.ResourcesTo) {
+		d.changed = true
+	}
+
+	return d
+}
+
+// hasChanges reports whether a TaskDiff actually represents a change,
+// rather than an identical task that was still worth comparing.
+func (d TaskDiff) hasChanges() bool {
+	return d.changed
+}
+
+func resourcesEqual(a, b *Resources) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
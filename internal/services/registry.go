@@ -0,0 +1,229 @@
+package services
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.hcl.tmpl
+var defaultTemplatesFS embed.FS
+
+// ServiceSpec is a versioned, user-overridable job spec for a platform
+// service: the image/version to deploy and the name of the Go text/template
+// (see Registry.LoadTemplate) that renders its Nomad job HCL, plus any
+// extra substitution variables the template needs (NOMAD_ADDR,
+// NOMAD_ALLOC_DIR, ...).
+type ServiceSpec struct {
+	Name     string
+	Image    string
+	Version  string
+	Template string
+	Vars     map[string]string
+}
+
+// defaultSpecs are the built-in image/version pins for each service whose
+// job HCL is driven by the Registry. Template names match the embedded
+// files under templates/.
+var defaultSpecs = map[string]ServiceSpec{
+	"nats":          {Name: "nats", Image: "nats", Version: "latest", Template: "nats"},
+	"dragonfly":     {Name: "dragonfly", Image: "docker.dragonflydb.io/dragonflydb/dragonfly", Version: "latest", Template: "dragonfly"},
+	"traefik":       {Name: "traefik", Image: "traefik", Version: "v3.2", Template: "traefik"},
+	"prometheus":    {Name: "prometheus", Image: "prom/prometheus", Version: "latest", Template: "prometheus"},
+	"loki":          {Name: "loki", Image: "grafana/loki", Version: "latest", Template: "loki"},
+	"grafana":       {Name: "grafana", Image: "grafana/grafana", Version: "latest", Template: "grafana"},
+	"promtail":      {Name: "promtail", Image: "grafana/promtail", Version: "latest", Template: "promtail"},
+	"node-exporter": {Name: "node-exporter", Image: "prom/node-exporter", Version: "latest", Template: "node-exporter"},
+	"cadvisor":      {Name: "cadvisor", Image: "gcr.io/cadvisor/cadvisor", Version: "latest", Template: "cadvisor"},
+}
+
+// Registry loads job templates for the services in defaultSpecs from
+// ConfigDir/services/<name>.hcl.tmpl, falling back to the embedded defaults
+// in templates/ when no override exists, and tracks image:tag pins in
+// ConfigDir/services/pins.json. This lets operators customize or version a
+// platform service's job spec without recompiling Styx.
+type Registry struct {
+	ConfigDir string
+}
+
+// NewRegistry creates a Registry rooted at configDir.
+func NewRegistry(configDir string) *Registry {
+	return &Registry{ConfigDir: configDir}
+}
+
+// DefaultRegistry is the process-wide Registry used by getServiceHCL and the
+// exported JobHCL-style helpers below. Set by cmd/styx at startup from
+// --config-dir; left at its zero value, overrides resolve against a
+// "services" directory relative to the working directory and pins are never
+// found, so every service simply renders its embedded default.
+var DefaultRegistry = NewRegistry("")
+
+// templateDir is where user template overrides and pins.json live.
+func (r *Registry) templateDir() string {
+	return filepath.Join(r.ConfigDir, "services")
+}
+
+// TemplatePath returns the path a user override for name's template would
+// live at, whether or not it currently exists.
+func (r *Registry) TemplatePath(name string) (string, error) {
+	spec, ok := defaultSpecs[name]
+	if !ok {
+		return "", fmt.Errorf("unknown service: %s", name)
+	}
+	return filepath.Join(r.templateDir(), spec.Template+".hcl.tmpl"), nil
+}
+
+// DefaultTemplate returns the embedded default template text for name.
+func DefaultTemplate(name string) (string, error) {
+	spec, ok := defaultSpecs[name]
+	if !ok {
+		return "", fmt.Errorf("unknown service: %s", name)
+	}
+	data, err := defaultTemplatesFS.ReadFile("templates/" + spec.Template + ".hcl.tmpl")
+	if err != nil {
+		return "", fmt.Errorf("failed to read embedded template for %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// LoadTemplate returns the effective template text for name: the user
+// override at TemplatePath if present, else the embedded default.
+func (r *Registry) LoadTemplate(name string) (string, error) {
+	path, err := r.TemplatePath(name)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return string(data), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read template override for %s: %w", name, err)
+	}
+	return DefaultTemplate(name)
+}
+
+// Spec returns name's effective ServiceSpec: the built-in defaults with any
+// pin applied (see Pin).
+func (r *Registry) Spec(name string) (*ServiceSpec, error) {
+	def, ok := defaultSpecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown service: %s", name)
+	}
+	spec := def
+
+	pins, err := r.loadPins()
+	if err != nil {
+		return nil, err
+	}
+	if pinned, ok := pins[name]; ok {
+		spec.Image, spec.Version = splitImageTag(pinned)
+	}
+
+	return &spec, nil
+}
+
+// Render executes name's effective template (see LoadTemplate) against its
+// effective spec (see Spec) plus extraVars, and returns the generated Nomad
+// job HCL.
+func (r *Registry) Render(name string, extraVars map[string]string) (string, error) {
+	spec, err := r.Spec(name)
+	if err != nil {
+		return "", err
+	}
+
+	vars := map[string]string{
+		"UPDATE_STANZA": updateStanza(DefaultUpdateStrategy),
+	}
+	for k, v := range spec.Vars {
+		vars[k] = v
+	}
+	for k, v := range extraVars {
+		vars[k] = v
+	}
+
+	tmplText, err := r.LoadTemplate(name)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New(name).Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template for %s: %w", name, err)
+	}
+
+	data := struct {
+		Image   string
+		Version string
+		Vars    map[string]string
+	}{spec.Image, spec.Version, vars}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render template for %s: %w", name, err)
+	}
+
+	return b.String(), nil
+}
+
+// Pin overrides name's image:tag, persisted to ConfigDir/services/pins.json.
+func (r *Registry) Pin(name, imageTag string) error {
+	if _, ok := defaultSpecs[name]; !ok {
+		return fmt.Errorf("unknown service: %s", name)
+	}
+
+	pins, err := r.loadPins()
+	if err != nil {
+		return err
+	}
+	pins[name] = imageTag
+
+	return r.savePins(pins)
+}
+
+func (r *Registry) loadPins() (map[string]string, error) {
+	data, err := os.ReadFile(r.pinsPath())
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pins: %w", err)
+	}
+
+	pins := map[string]string{}
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("failed to parse pins: %w", err)
+	}
+	return pins, nil
+}
+
+func (r *Registry) savePins(pins map[string]string) error {
+	if err := os.MkdirAll(r.templateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create services directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.pinsPath(), data, 0644)
+}
+
+func (r *Registry) pinsPath() string {
+	return filepath.Join(r.templateDir(), "pins.json")
+}
+
+// splitImageTag splits "image:tag" into its parts; an image with no tag
+// pins to "latest".
+func splitImageTag(imageTag string) (image, version string) {
+	idx := strings.LastIndex(imageTag, ":")
+	if idx == -1 {
+		return imageTag, "latest"
+	}
+	return imageTag[:idx], imageTag[idx+1:]
+}
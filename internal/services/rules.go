@@ -0,0 +1,42 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadRuleFiles reads every *.yml/*.yaml file in dir (conventionally
+// configDir/prometheus/rules) and returns them keyed by filename, for
+// PrometheusJobHCL to template each one into the job under local/rules/. A
+// missing dir is not an error: it just means no operator-supplied rules yet.
+func LoadRuleFiles(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules directory %s: %w", dir, err)
+	}
+
+	files := make(map[string]string)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yml", ".yaml":
+		default:
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule file %s: %w", e.Name(), err)
+		}
+		files[e.Name()] = string(data)
+	}
+
+	return files, nil
+}
@@ -1,18 +1,94 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 
+	"github.com/kessler-frost/styx/internal/intents"
 	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/tracing"
 )
 
-// Service represents a platform service
+// IntentsPath is where getServiceHCL looks for intents.hcl to compile
+// Traefik ipallowlist tags onto generated jobs (see internal/intents). Set
+// by cmd/styx at startup from --config-dir; left empty, intents are
+// disabled and jobs deploy unrestricted as before this existed.
+var IntentsPath string
+
+// UserDefinitionsDir is where LoadUserDefinitions looks for operator-authored
+// service definitions (see definition.go). Set by cmd/styx at startup from
+// styxBaseDir/services.d; left empty, no user definitions are loaded and
+// PlatformServices is just the built-ins.
+var UserDefinitionsDir string
+
+// defaultVaultAddr is the "vault_addr" input resolved for user definitions
+// that request it, matching the local Vault address assumed throughout
+// internal/vault and cmd/styx (see e.g. internal/vault/store.go's
+// openBaoAddr).
+const defaultVaultAddr = "http://127.0.0.1:8200"
+
+// userDefinitions holds the services loaded by LoadUserDefinitions, indexed
+// by name, so getServiceHCL and waitForServices can look up a service's
+// dependencies, job template, and health check by name alongside the
+// built-ins in defaultSpecs.
+var userDefinitions = map[string]Definition{}
+
+// LoadUserDefinitions reads every definition from UserDefinitionsDir (see
+// LoadDefinitions) and appends any not already present to PlatformServices,
+// so they show up in `styx services status`, `styx services deploy-all`,
+// etc. the same as a built-in. Safe to call with UserDefinitionsDir empty -
+// it's just a no-op then.
+func LoadUserDefinitions() error {
+	if UserDefinitionsDir == "" {
+		return nil
+	}
+
+	defs, err := LoadDefinitions(UserDefinitionsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, def := range defs {
+		userDefinitions[def.Name] = def
+
+		known := false
+		for _, svc := range PlatformServices {
+			if svc.Name == def.Name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			PlatformServices = append(PlatformServices, Service{Name: def.Name, Description: def.Description, DependsOn: def.DependsOn})
+		}
+	}
+	return nil
+}
+
+// Service represents a platform service. Its job HCL is rendered on demand
+// from DefaultRegistry (see registry.go and getServiceHCL) for built-ins, or
+// from its Definition's Job template (see userDefinitions and
+// renderDefinition) for user-defined ones, rather than stored here, so
+// pinning or editing a service's template takes effect on the next deploy
+// without code changes.
 type Service struct {
 	Name        string
 	Description string
-	JobHCL      string             // Static HCL for simple services
-	JobHCLFunc  func(string) string // Dynamic HCL generator (takes Tailscale IP)
+
+	// DependsOn names other services that must be deployed and healthy
+	// before this one is (see orderForDeploy). User-defined services get
+	// theirs from their Definition's depends_on (see LoadUserDefinitions).
+	DependsOn []string
+
+	// HealthCheck, if set, is how waitForService decides this service is up
+	// instead of the Nomad-job-status fallback (see serviceHealthy).
+	// User-defined services get theirs from their Definition instead (see
+	// userDefinitions).
+	HealthCheck *HealthCheck
 }
 
 // ServiceStatus represents the status of a platform service
@@ -27,17 +103,16 @@ var PlatformServices = []Service{
 	{
 		Name:        "nats",
 		Description: "Message queue (NATS)",
-		JobHCL:      natsJobHCL,
 	},
 	{
 		Name:        "dragonfly",
 		Description: "Redis-compatible cache (Dragonfly)",
-		JobHCL:      dragonflyJobHCL,
 	},
 	{
 		Name:        "traefik",
 		Description: "Ingress controller (Traefik)",
-		JobHCLFunc:  TraefikJobHCL,
+		DependsOn:   []string{"nats", "dragonfly"},
+		HealthCheck: &HealthCheck{Type: "http", Path: "/ping"},
 	},
 }
 
@@ -58,16 +133,129 @@ func Deploy(name string) error {
 	return fmt.Errorf("unknown service: %s", name)
 }
 
-// getServiceHCL returns the HCL for a service, handling dynamic generation if needed
+// getServiceHCL renders a service's job HCL. traefik is special-cased to go
+// through TraefikJobHCL, since its TLS posture (see TraefikOptions) needs
+// more than a plain NOMAD_ADDR substitution; a name found in userDefinitions
+// renders through renderDefinition; everything else renders from
+// DefaultRegistry, resolving the Tailscale IP as NOMAD_ADDR.
 func getServiceHCL(svc Service) (string, error) {
-	if svc.JobHCLFunc != nil {
+	var hcl string
+
+	switch {
+	case svc.Name == "traefik":
 		tsInfo := network.GetTailscaleInfo()
 		if !tsInfo.Running {
 			return "", fmt.Errorf("tailscale is required for %s but not running", svc.Name)
 		}
-		return svc.JobHCLFunc(tsInfo.IP), nil
+
+		opts := DefaultTraefikOptions
+		opts.NomadAddr = tsInfo.IP
+		if err := ensureTailscaleCerts(opts); err != nil {
+			return "", err
+		}
+
+		rendered, err := TraefikJobHCL(opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to render HCL for %s: %w", svc.Name, err)
+		}
+		hcl = rendered
+	default:
+		if def, ok := userDefinitions[svc.Name]; ok {
+			rendered, err := renderDefinition(def)
+			if err != nil {
+				return "", fmt.Errorf("failed to render HCL for %s: %w", svc.Name, err)
+			}
+			hcl = rendered
+			break
+		}
+
+		rendered, err := DefaultRegistry.Render(svc.Name, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to render HCL for %s: %w", svc.Name, err)
+		}
+		hcl = rendered
+	}
+
+	hcl = applyIntents(svc.Name, hcl)
+	if svc.Name == "nats" {
+		hcl = ApplyTLS(hcl, "nats-monitor")
+	}
+	return hcl, nil
+}
+
+// renderDefinition resolves def's declared Inputs and executes its Job
+// template against them, the same text/template pipeline Registry.Render
+// uses for built-in services, plus a couple of helper funcs (upper/lower)
+// useful for substituting into Nomad HCL identifiers and labels.
+func renderDefinition(def Definition) (string, error) {
+	vars, err := resolveInputs(def.Inputs)
+	if err != nil {
+		return "", err
+	}
+	vars["UPDATE_STANZA"] = updateStanza(def.Update)
+
+	tmpl, err := template.New(def.Name).Funcs(template.FuncMap{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}).Parse(def.Job)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse job template: %w", err)
+	}
+
+	data := struct {
+		Image   string
+		Version string
+		Vars    map[string]string
+	}{def.Image, def.Version, vars}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render job template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// resolveInputs looks up the concrete value for each name in inputs, for
+// renderDefinition to expose as .Vars in a definition's job template.
+func resolveInputs(inputs []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, input := range inputs {
+		switch input {
+		case "tailscale_ip":
+			tsInfo := network.GetTailscaleInfo()
+			if !tsInfo.Running {
+				return nil, fmt.Errorf("input %q requires tailscale to be running", input)
+			}
+			vars["TAILSCALE_IP"] = tsInfo.IP
+		case "vault_addr":
+			vars["VAULT_ADDR"] = defaultVaultAddr
+		default:
+			return nil, fmt.Errorf("unknown input %q", input)
+		}
 	}
-	return svc.JobHCL, nil
+	return vars, nil
+}
+
+// applyIntents compiles any intents targeting svc.Name into Traefik
+// ipallowlist tags and splices them into hcl. Errors loading or resolving
+// intents are swallowed: a misconfigured intents file shouldn't block a
+// service from deploying, it should just leave it unrestricted.
+func applyIntents(serviceName, hcl string) string {
+	if IntentsPath == "" {
+		return hcl
+	}
+
+	list, err := intents.Load(IntentsPath)
+	if err != nil || len(list) == 0 {
+		return hcl
+	}
+
+	tags, err := intents.MiddlewareTags(list, serviceName, DefaultClient().GetServiceAddresses)
+	if err != nil || len(tags) == 0 {
+		return hcl
+	}
+
+	return intents.ApplyTags(hcl, serviceName, tags)
 }
 
 // Stop stops a platform service by name
@@ -117,57 +305,210 @@ func Status() ([]ServiceStatus, error) {
 	return result, nil
 }
 
-// DeployAll deploys all platform services
+// DeployAll deploys all platform services, in dependency order (see
+// orderForDeploy): each layer of mutually-independent services deploys and
+// becomes healthy in parallel before the next layer - that depends on it -
+// starts.
 func DeployAll() error {
+	ctx, span := tracing.StartSpan(context.Background(), "services.deploy_all")
+	defer span.End()
+
 	client := DefaultClient()
 
-	for _, svc := range PlatformServices {
-		fmt.Printf("  Deploying %s...\n", svc.Name)
-		hcl, err := getServiceHCL(svc)
-		if err != nil {
-			return fmt.Errorf("failed to generate HCL for %s: %w", svc.Name, err)
-		}
-		if err := client.RunJob(hcl); err != nil {
-			return fmt.Errorf("failed to deploy %s: %w", svc.Name, err)
+	layers, err := orderForDeploy(PlatformServices)
+	if err != nil {
+		span.SetError(err)
+		return fmt.Errorf("failed to order services for deploy: %w", err)
+	}
+
+	for _, layer := range layers {
+		if err := deployLayer(ctx, client, layer); err != nil {
+			span.SetError(err)
+			return err
 		}
 	}
+	return nil
+}
+
+// deployLayer deploys every service in layer concurrently, then waits for
+// all of them to become healthy before returning - so the next layer, whose
+// services may depend on this one, doesn't start against a half-up backend.
+func deployLayer(ctx context.Context, client *NomadClient, layer []Service) error {
+	if err := parallelEach(layer, func(svc Service) error {
+		return deployOne(ctx, client, svc)
+	}); err != nil {
+		return err
+	}
 
-	// Wait for services to become running
 	fmt.Println("  Waiting for services to start...")
-	return waitForServices(60 * time.Second)
+	return waitForServices(ctx, layer, 60*time.Second)
 }
 
-// waitForServices waits for all platform services to reach running state
-func waitForServices(timeout time.Duration) error {
-	client := DefaultClient()
-	deadline := time.Now().Add(timeout)
+// parallelEach runs fn for every service in services concurrently, waiting
+// for all of them and returning the first error encountered (if any).
+func parallelEach(services []Service, fn func(Service) error) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(services))
+
+	for i, svc := range services {
+		wg.Add(1)
+		go func(i int, svc Service) {
+			defer wg.Done()
+			errs[i] = fn(svc)
+		}(i, svc)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	for time.Now().Before(deadline) {
-		allRunning := true
+// deployOne renders and runs one service's job, wrapped in a child span so
+// `styx services deploy-all --trace` shows where a slow or hung deploy is
+// spending time relative to its siblings.
+func deployOne(ctx context.Context, client *NomadClient, svc Service) error {
+	_, span := tracing.StartSpan(ctx, "services.deploy_one")
+	span.SetAttribute("service", svc.Name)
+	defer span.End()
+
+	fmt.Printf("  Deploying %s...\n", svc.Name)
+	hcl, err := getServiceHCL(svc)
+	if err != nil {
+		err = fmt.Errorf("failed to generate HCL for %s: %w", svc.Name, err)
+		span.SetError(err)
+		return err
+	}
+	if err := client.RunJob(hcl); err != nil {
+		err = fmt.Errorf("failed to deploy %s: %w", svc.Name, err)
+		span.SetError(err)
+		return err
+	}
+	return nil
+}
 
-		for _, svc := range PlatformServices {
-			status, err := client.GetJobStatus(svc.Name)
-			if err != nil {
-				allRunning = false
-				break
+// orderForDeploy groups services into dependency layers with a Kahn-style
+// topological sort: layer 0 holds every service with no DependsOn, layer 1
+// holds those whose dependencies are all in layer 0, and so on. DeployAll
+// deploys a layer at a time, in parallel within the layer, so independent
+// services don't wait on each other and a dependent still waits for its
+// dependencies to be up first. A DependsOn naming a service not in services
+// is ignored, same as the old DFS-based order did.
+func orderForDeploy(services []Service) ([][]Service, error) {
+	byName := make(map[string]Service, len(services))
+	indegree := make(map[string]int, len(services))
+	dependents := make(map[string][]string)
+
+	for _, svc := range services {
+		byName[svc.Name] = svc
+		indegree[svc.Name] = 0
+	}
+	for _, svc := range services {
+		for _, dep := range svc.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				continue
+			}
+			indegree[svc.Name]++
+			dependents[dep] = append(dependents[dep], svc.Name)
+		}
+	}
+
+	var layers [][]Service
+	placed := 0
+	for placed < len(services) {
+		var layer []Service
+		for _, svc := range services {
+			if indegree[svc.Name] == 0 {
+				layer = append(layer, svc)
 			}
+		}
+		if len(layer) == 0 {
+			var stuck []string
+			for _, svc := range services {
+				if indegree[svc.Name] > 0 {
+					stuck = append(stuck, svc.Name)
+				}
+			}
+			return nil, fmt.Errorf("circular dependency among services: %s", strings.Join(stuck, ", "))
+		}
 
-			if status == nil || status.Status != "running" {
-				allRunning = false
-				break
+		layers = append(layers, layer)
+		for _, svc := range layer {
+			indegree[svc.Name] = -1 // placed; drop out of future layer scans
+			placed++
+			for _, dependent := range dependents[svc.Name] {
+				indegree[dependent]--
 			}
 		}
+	}
+	return layers, nil
+}
+
+// waitForServices waits for every service in layer to become healthy,
+// concurrently, returning the first error encountered (if any). A service
+// with a declared HealthCheck (see Service.HealthCheck and userDefinitions)
+// is polled with it, everything else falls back to polling Nomad job
+// status, as this did before declared health checks existed.
+func waitForServices(ctx context.Context, layer []Service, timeout time.Duration) error {
+	client := DefaultClient()
+	deadline := time.Now().Add(timeout)
+
+	if err := parallelEach(layer, func(svc Service) error {
+		return waitForService(ctx, client, svc, deadline)
+	}); err != nil {
+		return err
+	}
 
-		if allRunning {
+	fmt.Println()
+	return nil
+}
+
+// waitForService polls svc until it's healthy or deadline passes, wrapped in
+// a child span covering every Nomad job status poll (or health check probe)
+// for this one service.
+func waitForService(ctx context.Context, client *NomadClient, svc Service, deadline time.Time) error {
+	_, span := tracing.StartSpan(ctx, "services.wait_for_service")
+	span.SetAttribute("service", svc.Name)
+	defer span.End()
+
+	for {
+		healthy, err := serviceHealthy(client, svc)
+		if err == nil && healthy {
 			return nil
 		}
 
+		if time.Now().After(deadline) {
+			fmt.Println()
+			err := fmt.Errorf("timeout waiting for %s to start", svc.Name)
+			span.SetError(err)
+			return err
+		}
+
 		time.Sleep(2 * time.Second)
 		fmt.Print(".")
 	}
+}
 
-	fmt.Println()
-	return fmt.Errorf("timeout waiting for services to start")
+// serviceHealthy reports whether svc is up: via svc.HealthCheck or its
+// Definition's (see userDefinitions) if either is set, else via plain Nomad
+// job status.
+func serviceHealthy(client *NomadClient, svc Service) (bool, error) {
+	hc := svc.HealthCheck
+	if hc == nil {
+		hc = userDefinitions[svc.Name].HealthCheck
+	}
+	if hc != nil {
+		return hc.probe(client, healthCheckTarget(svc))
+	}
+
+	status, err := client.GetJobStatus(svc.Name)
+	if err != nil {
+		return false, err
+	}
+	return status != nil && status.Status == "running", nil
 }
 
 // StopAll stops all platform services
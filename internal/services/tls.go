@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kessler-frost/styx/internal/intents"
+	"github.com/kessler-frost/styx/internal/network"
+)
+
+// TLSMode selects how Traefik terminates HTTPS for ingress traffic.
+type TLSMode string
+
+const (
+	// TLSModeNone serves plaintext HTTP only (the historical default).
+	TLSModeNone TLSMode = "none"
+	// TLSModeTailscale terminates HTTPS using certificates issued by
+	// Tailscale (see network.FetchCert), for tailnet-only traffic.
+	TLSModeTailscale TLSMode = "tailscale"
+	// TLSModeACMEHTTP terminates HTTPS using Let's Encrypt via the ACME
+	// HTTP-01 challenge.
+	TLSModeACMEHTTP TLSMode = "acme-http"
+	// TLSModeACMEDNS terminates HTTPS using Let's Encrypt via the ACME
+	// DNS-01 challenge, for domains that can't expose port 80.
+	TLSModeACMEDNS TLSMode = "acme-dns"
+)
+
+// TraefikOptions configures TraefikJobHCL's TLS posture.
+type TraefikOptions struct {
+	// NomadAddr is the Tailscale IP of the host, substituted the same way
+	// as the pre-TLS TraefikJobHCL(nomadAddr string).
+	NomadAddr string
+
+	TLSMode         TLSMode
+	ACMEEmail       string
+	ACMEDNSProvider string
+	Domains         []string
+
+	// DataDir is where persistent TLS state lives: DataDir/traefik/acme.json
+	// for acme-* modes, DataDir/traefik/certs for tailscale mode.
+	DataDir string
+}
+
+// DefaultTraefikOptions is the process-wide TLS configuration used by
+// getServiceHCL and DeployObservability. Set by cmd/styx at startup; its
+// zero value (TLSMode "") behaves like TLSModeNone, so TLS stays opt-in.
+var DefaultTraefikOptions TraefikOptions
+
+// TLSRouterTags returns the extra Traefik tags routerName's router needs to
+// pick up HTTPS when DefaultTraefikOptions has TLS enabled: an https
+// entrypoint and the "styx" cert resolver configured by TraefikJobHCL. nil
+// when TLS is disabled, leaving the router's existing http-only tags as-is.
+func TLSRouterTags(routerName string) []string {
+	if DefaultTraefikOptions.TLSMode == "" || DefaultTraefikOptions.TLSMode == TLSModeNone {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("traefik.http.routers.%s.entrypoints=https", routerName),
+		fmt.Sprintf("traefik.http.routers.%s.tls.certresolver=styx", routerName),
+	}
+}
+
+// ApplyTLS splices TLSRouterTags(routerName) into routerName's service
+// stanza in hcl, reusing intents' tag-splicing (see intents.ApplyTags) so
+// enabling TLS doesn't need a second tag-injection mechanism. A no-op when
+// TLS is disabled, since TLSRouterTags returns nil.
+func ApplyTLS(hcl, routerName string) string {
+	return intents.ApplyTags(hcl, routerName, TLSRouterTags(routerName))
+}
+
+// ensureTailscaleCerts fetches (or refreshes) a Tailscale-issued cert for
+// each of opts.Domains into traefikCertDir, for TraefikJobHCL's
+// TLSModeTailscale file-provider config. A no-op for every other mode.
+func ensureTailscaleCerts(opts TraefikOptions) error {
+	if opts.TLSMode != TLSModeTailscale {
+		return nil
+	}
+	dir := traefikCertDir(opts)
+	for _, d := range opts.Domains {
+		if err := network.FetchCert(d, dir); err != nil {
+			return fmt.Errorf("failed to fetch tailscale cert for %s: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// traefikHTTPSNetworkPort returns the Nomad network "port" stanza reserving
+// 4443 for HTTPS when opts.TLSMode is enabled, "" otherwise.
+func traefikHTTPSNetworkPort(opts TraefikOptions) string {
+	if opts.TLSMode == "" || opts.TLSMode == TLSModeNone {
+		return ""
+	}
+	return "      port \"https\" {\n        static = 4443\n      }\n"
+}
+
+// traefikHTTPSPortMapping returns the extra "4443:443" entry TraefikJobHCL's
+// docker ports list needs when opts.TLSMode is enabled, "" otherwise.
+func traefikHTTPSPortMapping(opts TraefikOptions) string {
+	if opts.TLSMode == "" || opts.TLSMode == TLSModeNone {
+		return ""
+	}
+	return `, "4443:443"`
+}
+
+// traefikCertDir is where network.FetchCert writes Tailscale-issued
+// certificates for TLSModeTailscale, mounted read-only into the Traefik
+// container.
+func traefikCertDir(opts TraefikOptions) string {
+	return filepath.Join(opts.DataDir, "traefik", "certs")
+}
+
+// traefikACMEDir is where Traefik persists acme.json for the acme-* modes.
+func traefikACMEDir(opts TraefikOptions) string {
+	return filepath.Join(opts.DataDir, "traefik")
+}
+
+// traefikVolumesBlock returns the config.volumes stanza TraefikJobHCL needs
+// for opts.TLSMode, or "" if TLS is disabled and no extra mount is needed.
+func traefikVolumesBlock(opts TraefikOptions) string {
+	switch opts.TLSMode {
+	case TLSModeTailscale:
+		return fmt.Sprintf("        volumes = [\n          \"%s:/certs:ro\"\n        ]\n", traefikCertDir(opts))
+	case TLSModeACMEHTTP, TLSModeACMEDNS:
+		return fmt.Sprintf("        volumes = [\n          \"%s:/acme\"\n        ]\n", traefikACMEDir(opts))
+	default:
+		return ""
+	}
+}
+
+// traefikExtraArgs returns the extra CLI args TraefikJobHCL appends after
+// its static args for opts.TLSMode: an https entrypoint plus whichever
+// certificate resolver flags the mode needs. Each returned line already
+// carries its own leading ",\n          " so it can be spliced directly
+// after the last static arg in the template.
+func traefikExtraArgs(opts TraefikOptions) string {
+	if opts.TLSMode == "" || opts.TLSMode == TLSModeNone {
+		return ""
+	}
+
+	args := []string{`"--entryPoints.https.address=:443"`}
+
+	switch opts.TLSMode {
+	case TLSModeTailscale:
+		args = append(args, `"--providers.file.filename=/local/dynamic.yml"`)
+	case TLSModeACMEHTTP:
+		args = append(args,
+			fmt.Sprintf(`"--certificatesresolvers.styx.acme.email=%s"`, opts.ACMEEmail),
+			`"--certificatesresolvers.styx.acme.storage=/acme/acme.json"`,
+			`"--certificatesresolvers.styx.acme.httpchallenge.entrypoint=http"`,
+		)
+	case TLSModeACMEDNS:
+		args = append(args,
+			fmt.Sprintf(`"--certificatesresolvers.styx.acme.email=%s"`, opts.ACMEEmail),
+			`"--certificatesresolvers.styx.acme.storage=/acme/acme.json"`,
+			fmt.Sprintf(`"--certificatesresolvers.styx.acme.dnschallenge.provider=%s"`, opts.ACMEDNSProvider),
+		)
+	}
+
+	return ",\n          " + strings.Join(args, ",\n          ")
+}
+
+// traefikDynamicConfigTemplate returns the Nomad template stanza that
+// writes Traefik's file-provider dynamic.yml for TLSModeTailscale, pointing
+// at the certificates network.FetchCert mounted under /certs. "" for every
+// other mode, since acme-* modes manage their own certificates.
+func traefikDynamicConfigTemplate(opts TraefikOptions) string {
+	if opts.TLSMode != TLSModeTailscale || len(opts.Domains) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n      template {\n        data = <<EOF\ntls:\n  certificates:\n")
+	for _, d := range opts.Domains {
+		fmt.Fprintf(&b, "    - certFile: /certs/%s.crt\n      keyFile: /certs/%s.key\n", d, d)
+	}
+	b.WriteString("EOF\n        destination = \"local/dynamic.yml\"\n      }\n")
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// UpdateStrategy configures Nomad's rolling update behavior for a platform
+// service job: how many allocations update in parallel, how long a new
+// allocation must stay healthy before it counts, the deadline to reach
+// that, and whether Nomad auto-reverts to the last stable version when it
+// doesn't.
+type UpdateStrategy struct {
+	MaxParallel     int
+	MinHealthyTime  time.Duration
+	HealthyDeadline time.Duration
+	AutoRevert      bool
+	Canary          int
+}
+
+// DefaultUpdateStrategy is the rolling-update policy applied to every
+// platform service's generated job HCL (see updateStanza), so bumping a
+// pinned image tag rolls out health-gated instead of recreating the
+// allocation outright.
+var DefaultUpdateStrategy = UpdateStrategy{
+	MaxParallel:     1,
+	MinHealthyTime:  10 * time.Second,
+	HealthyDeadline: 3 * time.Minute,
+	AutoRevert:      true,
+	Canary:          0,
+}
+
+// updateStanza renders strategy as a Nomad "update { ... }" group-level
+// stanza, indented to splice directly under a group's "count = N" line.
+func updateStanza(strategy UpdateStrategy) string {
+	return fmt.Sprintf(`
+    update {
+      max_parallel     = %d
+      min_healthy_time = "%s"
+      healthy_deadline = "%s"
+      auto_revert      = %t
+      canary           = %d
+    }
+
+`, strategy.MaxParallel, strategy.MinHealthyTime, strategy.HealthyDeadline, strategy.AutoRevert, strategy.Canary)
+}
@@ -0,0 +1,79 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BinaryDownloadManager installs prerequisites by downloading a prebuilt
+// binary directly, for hosts with none of the supported package managers.
+// spec.BinaryURL may point at a bare binary or a .zip archive containing
+// one.
+type BinaryDownloadManager struct{}
+
+// Install downloads and installs spec.BinaryURL to /usr/local/bin.
+func (m BinaryDownloadManager) Install(spec PackageSpec) InstallResult {
+	return m.InstallCtx(context.Background(), spec, nil)
+}
+
+// InstallCtx is Install with a cancellable context and streaming
+// InstallUpdate events.
+func (m BinaryDownloadManager) InstallCtx(ctx context.Context, spec PackageSpec, updates chan<- InstallUpdate) InstallResult {
+	result := InstallResult{Name: spec.Name}
+
+	if spec.BinaryURL == "" {
+		result.Error = fmt.Sprintf("%s has no binary download configured", spec.Name)
+		return result
+	}
+
+	dest := fmt.Sprintf("/usr/local/bin/%s", spec.Name)
+	var cmdStr string
+	if strings.HasSuffix(spec.BinaryURL, ".zip") {
+		cmdStr = fmt.Sprintf(
+			"tmp=$(mktemp -d) && curl -fsSL %q -o \"$tmp/%s.zip\" && unzip -o \"$tmp/%s.zip\" -d \"$tmp\" && sudo install -m 0755 \"$tmp/%s\" %q && rm -rf \"$tmp\"",
+			spec.BinaryURL, spec.Name, spec.Name, spec.Name, dest,
+		)
+	} else {
+		cmdStr = fmt.Sprintf("curl -fsSL %q -o %q && sudo chmod +x %q", spec.BinaryURL, dest, dest)
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.1})
+	output, err := RunCommandCtx(ctx, cmdStr, func(line string) {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Line: line, Percent: 0.5})
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to install %s: %v\n%s", spec.Name, err, output)
+		return result
+	}
+
+	result.Success = true
+	result.Output = output
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.9})
+
+	if spec.Service != "" {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "service", Percent: 0.95})
+		if err := m.StartService(spec.Service); err != nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("%s installed but failed to start service: %v", spec.Name, err)
+			return result
+		}
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "done", Percent: 1})
+	return result
+}
+
+// IsInstalled reports whether name is on PATH.
+func (BinaryDownloadManager) IsInstalled(name string) bool {
+	return isOnPath(name)
+}
+
+// StartService runs `systemctl start <name>` via sudo.
+func (BinaryDownloadManager) StartService(name string) error {
+	output, err := RunCommand(fmt.Sprintf("sudo systemctl start %s", name))
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+)
+
+// BrewManager installs prerequisites via Homebrew - the only backend macOS
+// supports, and what Styx has always shelled out to.
+type BrewManager struct{}
+
+// Install taps spec.BrewTap (if set) and installs spec.Brew, starting
+// spec.Service afterward if one is configured.
+func (m BrewManager) Install(spec PackageSpec) InstallResult {
+	return m.InstallCtx(context.Background(), spec, nil)
+}
+
+// InstallCtx is Install with a cancellable context and streaming
+// InstallUpdate events for each stage (tap, install, service).
+func (m BrewManager) InstallCtx(ctx context.Context, spec PackageSpec, updates chan<- InstallUpdate) InstallResult {
+	result := InstallResult{Name: spec.Name}
+
+	if spec.Brew == "" {
+		result.Error = fmt.Sprintf("%s has no Homebrew package configured", spec.Name)
+		return result
+	}
+
+	if spec.BrewTap != "" {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "tap", Percent: 0})
+		output, err := RunCommandCtx(ctx, fmt.Sprintf("brew tap %s", spec.BrewTap), func(line string) {
+			emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "tap", Line: line, Percent: 0.1})
+		})
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to tap %s: %v\n%s", spec.BrewTap, err, output)
+			return result
+		}
+	}
+
+	installCmd := fmt.Sprintf("brew install %s", spec.Brew)
+	if spec.BrewCask {
+		installCmd = fmt.Sprintf("brew install --cask %s", spec.Brew)
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.2})
+	output, err := RunCommandCtx(ctx, installCmd, func(line string) {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Line: line, Percent: 0.6})
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to install %s: %v\n%s", spec.Name, err, output)
+		return result
+	}
+
+	result.Success = true
+	result.Output = output
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.9})
+
+	if spec.Service != "" {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "service", Percent: 0.95})
+		if err := m.StartService(spec.Service); err != nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("%s installed but failed to start service: %v", spec.Name, err)
+			return result
+		}
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "done", Percent: 1})
+	return result
+}
+
+// IsInstalled reports whether name is on PATH.
+func (BrewManager) IsInstalled(name string) bool {
+	return isOnPath(name)
+}
+
+// StartService runs `brew services start <name>`.
+func (BrewManager) StartService(name string) error {
+	output, err := RunCommand(fmt.Sprintf("brew services start %s", name))
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+	return nil
+}
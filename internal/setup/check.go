@@ -1,28 +1,14 @@
 package setup
 
 import (
+	"context"
 	"encoding/json"
 	"os/exec"
 	"strings"
-)
-
-// CheckBrew checks if Homebrew is installed.
-func CheckBrew() Prerequisite {
-	p := Prerequisite{
-		Name:     "homebrew",
-		CheckCmd: "which brew",
-	}
-
-	_, err := exec.LookPath("brew")
-	if err != nil {
-		p.Status = Missing
-		p.InstallCmds = []string{"Visit https://brew.sh to install Homebrew"}
-		return p
-	}
 
-	p.Status = Installed
-	return p
-}
+	"tailscale.com/client/local"
+	"tailscale.com/ipn/ipnstate"
+)
 
 // CheckNomad checks if Nomad is installed.
 func CheckNomad() Prerequisite {
@@ -31,13 +17,9 @@ func CheckNomad() Prerequisite {
 		CheckCmd: "which nomad",
 	}
 
-	_, err := exec.LookPath("nomad")
-	if err != nil {
+	if !currentManager.IsInstalled("nomad") {
 		p.Status = Missing
-		p.InstallCmds = []string{
-			"brew tap hashicorp/tap",
-			"brew install hashicorp/tap/nomad",
-		}
+		p.InstallCmds = installCmdsFor("nomad")
 		return p
 	}
 
@@ -52,13 +34,9 @@ func CheckVault() Prerequisite {
 		CheckCmd: "which vault",
 	}
 
-	_, err := exec.LookPath("vault")
-	if err != nil {
+	if !currentManager.IsInstalled("vault") {
 		p.Status = Missing
-		p.InstallCmds = []string{
-			"brew tap hashicorp/tap",
-			"brew install hashicorp/tap/vault",
-		}
+		p.InstallCmds = installCmdsFor("vault")
 		return p
 	}
 
@@ -73,13 +51,9 @@ func CheckContainer() Prerequisite {
 		CheckCmd: "which container",
 	}
 
-	_, err := exec.LookPath("container")
-	if err != nil {
+	if !currentManager.IsInstalled("container") {
 		p.Status = Missing
-		p.InstallCmds = []string{
-			"brew install container",
-			"brew services start container",
-		}
+		p.InstallCmds = installCmdsFor("container")
 		return p
 	}
 
@@ -88,7 +62,8 @@ func CheckContainer() Prerequisite {
 	if err := cmd.Run(); err != nil {
 		p.Status = Error
 		p.Error = "Container service not running"
-		p.InstallCmds = []string{"brew services start container"}
+		plan := ResolveInstallPlan("container")
+		p.InstallCmds = []string{serviceStartCmdForManager(plan.Chosen.Manager, manifestByName["container"].Service)}
 		return p
 	}
 
@@ -96,16 +71,14 @@ func CheckContainer() Prerequisite {
 	return p
 }
 
-// tailscaleStatus represents the JSON output from tailscale status --json
-type tailscaleStatus struct {
-	BackendState string `json:"BackendState"`
-	Self         struct {
-		DNSName    string   `json:"DNSName"`
-		TailscaleIPs []string `json:"TailscaleIPs"`
-	} `json:"Self"`
-}
+// tsLocalClient is the LocalAPI client used to check tailscaled's status.
+// Its zero value dials tailscaled over the platform's default local
+// socket, so no explicit setup is needed.
+var tsLocalClient local.Client
 
-// findTailscaleBin finds the tailscale CLI binary path.
+// findTailscaleBin finds the tailscale CLI binary path, for the fallback
+// used when the LocalAPI socket isn't reachable (e.g. the Mac App Store
+// build, which sandboxes it from other processes).
 func findTailscaleBin() string {
 	// Check common locations
 	paths := []string{
@@ -127,6 +100,35 @@ func findTailscaleBin() string {
 	return ""
 }
 
+// getTailscaleStatus reads tailscaled's status via the LocalAPI, falling
+// back to `tailscale status --json` if the LocalAPI socket isn't
+// reachable. installed is false only when no tailscale binary could be
+// found at all; status is nil with a describing parseErr if it's
+// installed but not reachable (e.g. not logged in) or its output
+// couldn't be understood.
+func getTailscaleStatus() (status *ipnstate.Status, installed bool, parseErr error) {
+	if s, err := tsLocalClient.Status(context.Background()); err == nil {
+		return s, true, nil
+	}
+
+	binPath := findTailscaleBin()
+	if binPath == "" {
+		return nil, false, nil
+	}
+
+	cmd := exec.Command(binPath, "status", "--json")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, true, nil
+	}
+
+	var cliStatus ipnstate.Status
+	if err := json.Unmarshal(output, &cliStatus); err != nil {
+		return nil, true, err
+	}
+	return &cliStatus, true, nil
+}
+
 // CheckTailscale checks if Tailscale is installed and connected.
 func CheckTailscale() Prerequisite {
 	p := Prerequisite{
@@ -134,29 +136,20 @@ func CheckTailscale() Prerequisite {
 		CheckCmd: "tailscale status",
 	}
 
-	binPath := findTailscaleBin()
-	if binPath == "" {
+	status, installed, parseErr := getTailscaleStatus()
+	if !installed {
 		p.Status = Missing
-		p.InstallCmds = []string{
-			"brew install --cask tailscale",
-			"Open Tailscale app and sign in",
-		}
+		p.InstallCmds = installCmdsFor("tailscale")
 		return p
 	}
-
-	// Check connection status
-	cmd := exec.Command(binPath, "status", "--json")
-	output, err := cmd.Output()
-	if err != nil {
+	if parseErr != nil {
 		p.Status = Error
-		p.Error = "Not logged in. Open Tailscale app and sign in"
+		p.Error = "Failed to parse tailscale status"
 		return p
 	}
-
-	var status tailscaleStatus
-	if err := json.Unmarshal(output, &status); err != nil {
+	if status == nil {
 		p.Status = Error
-		p.Error = "Failed to parse tailscale status"
+		p.Error = "Not logged in. Open Tailscale app and sign in"
 		return p
 	}
 
@@ -168,11 +161,11 @@ func CheckTailscale() Prerequisite {
 
 	// Add tailnet info to the prerequisite
 	p.Status = Installed
-	if status.Self.DNSName != "" {
+	if status.Self != nil && status.Self.DNSName != "" {
 		p.Info = strings.TrimSuffix(status.Self.DNSName, ".")
 	}
-	if len(status.Self.TailscaleIPs) > 0 {
-		p.Info = status.Self.TailscaleIPs[0]
+	if status.Self != nil && len(status.Self.TailscaleIPs) > 0 {
+		p.Info = status.Self.TailscaleIPs[0].String()
 	}
 
 	return p
@@ -0,0 +1,65 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+)
+
+// DnfManager installs prerequisites via dnf, for Fedora/RHEL Linux hosts.
+type DnfManager struct{}
+
+// Install runs `dnf install` for spec.Dnf, starting spec.Service afterward
+// if one is configured.
+func (m DnfManager) Install(spec PackageSpec) InstallResult {
+	return m.InstallCtx(context.Background(), spec, nil)
+}
+
+// InstallCtx is Install with a cancellable context and streaming
+// InstallUpdate events for each stage (install, service).
+func (m DnfManager) InstallCtx(ctx context.Context, spec PackageSpec, updates chan<- InstallUpdate) InstallResult {
+	result := InstallResult{Name: spec.Name}
+
+	if spec.Dnf == "" {
+		result.Error = fmt.Sprintf("%s has no dnf package configured", spec.Name)
+		return result
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.1})
+	output, err := RunCommandCtx(ctx, fmt.Sprintf("sudo dnf install -y %s", spec.Dnf), func(line string) {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Line: line, Percent: 0.5})
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to install %s: %v\n%s", spec.Name, err, output)
+		return result
+	}
+
+	result.Success = true
+	result.Output = output
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.9})
+
+	if spec.Service != "" {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "service", Percent: 0.95})
+		if err := m.StartService(spec.Service); err != nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("%s installed but failed to start service: %v", spec.Name, err)
+			return result
+		}
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "done", Percent: 1})
+	return result
+}
+
+// IsInstalled reports whether name is on PATH.
+func (DnfManager) IsInstalled(name string) bool {
+	return isOnPath(name)
+}
+
+// StartService runs `systemctl start <name>` via sudo.
+func (DnfManager) StartService(name string) error {
+	output, err := RunCommand(fmt.Sprintf("sudo systemctl start %s", name))
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+)
+
+// DockerManager installs prerequisites as container images via `docker
+// pull`, for hosts that have Docker but no matching host package manager.
+// The resulting binary is run via `docker run`, not placed on PATH - that
+// wrapping is left to the operator.
+type DockerManager struct{}
+
+// Install pulls spec.Docker.
+func (m DockerManager) Install(spec PackageSpec) InstallResult {
+	return m.InstallCtx(context.Background(), spec, nil)
+}
+
+// InstallCtx is Install with a cancellable context and streaming
+// InstallUpdate events.
+func (m DockerManager) InstallCtx(ctx context.Context, spec PackageSpec, updates chan<- InstallUpdate) InstallResult {
+	result := InstallResult{Name: spec.Name}
+
+	if spec.Docker == "" {
+		result.Error = fmt.Sprintf("%s has no docker image configured", spec.Name)
+		return result
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.1})
+	output, err := RunCommandCtx(ctx, fmt.Sprintf("docker pull %s", spec.Docker), func(line string) {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Line: line, Percent: 0.5})
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to pull %s: %v\n%s", spec.Docker, err, output)
+		return result
+	}
+
+	result.Success = true
+	result.Output = output
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "done", Percent: 1})
+	return result
+}
+
+// IsInstalled reports whether spec's image has already been pulled. Since
+// Install only takes a name, this always reports false - DockerManager is
+// a one-shot install backend, not consulted by the registry's checks.
+func (DockerManager) IsInstalled(name string) bool {
+	return false
+}
+
+// StartService is a no-op; a pulled image has no host service to start.
+func (DockerManager) StartService(name string) error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package setup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/network"
+)
+
+// federationDiscoveryTimeout bounds how long CheckFederation waits for
+// network.DiscoverNomadServers before giving up, so GetStatus stays snappy
+// even on a tailnet with many unreachable peers.
+const federationDiscoveryTimeout = 2 * time.Second
+
+// CheckFederation probes the tailnet for Nomad servers this node could
+// join (see network.DiscoverNomadServers) instead of bootstrapping a new
+// cluster. Unlike the other Check* functions, a Missing result here isn't
+// something to install - it just means no federation candidates were
+// found, which is the common case for a node forming the first server in
+// a cluster. Callers should pass tailscaleInstalled from CheckTailscale's
+// result, since discovery needs a running tailnet connection.
+func CheckFederation(tailscaleInstalled bool) Prerequisite {
+	p := Prerequisite{Name: "federation"}
+
+	if !tailscaleInstalled {
+		p.Status = Pending
+		p.Error = "Requires tailscale"
+		return p
+	}
+
+	servers := network.DiscoverNomadServers(federationDiscoveryTimeout)
+	if len(servers) == 0 {
+		p.Status = Missing
+		return p
+	}
+
+	p.Status = FederationAvailable
+	names := make([]string, len(servers))
+	for i, s := range servers {
+		names[i] = fmt.Sprintf("%s (%s)", s.Hostname, s.IP)
+	}
+	p.Info = strings.Join(names, ", ")
+	return p
+}
@@ -0,0 +1,143 @@
+package setup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// HeadlessEvent is one line of the newline-delimited JSON stream
+// RunHeadless writes, so a Dockerfile or CI step can follow a `styx setup`
+// run without a TTY. Type is one of "check", "install-start", "progress",
+// "skip", or "result"; callers should switch on it and ignore whichever
+// fields don't apply to that type, the same way install.go's InstallUpdate
+// already carries more fields than any one Stage uses.
+type HeadlessEvent struct {
+	Type    string  `json:"type"`
+	Name    string  `json:"name,omitempty"`
+	Status  string  `json:"status,omitempty"`
+	Percent float64 `json:"percent,omitempty"`
+	Line    string  `json:"line,omitempty"`
+	Success bool    `json:"success,omitempty"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// HeadlessOptions configures a RunHeadless run.
+type HeadlessOptions struct {
+	// Yes installs every missing prerequisite instead of just reporting
+	// them - required since there's no TTY to prompt "install now? [y/n]"
+	// on.
+	Yes bool
+	// Only restricts the run to these prerequisite names, if non-empty.
+	Only []string
+	// Skip excludes these prerequisite names from the run.
+	Skip []string
+	// DryRun reports what's missing and would be installed, without
+	// running any installer.
+	DryRun bool
+}
+
+// RunHeadless drives the same check/install flow the interactive TUI does
+// (see internal/tui/setup) without a bubbletea Model, writing one JSON
+// HeadlessEvent per line to out instead of rendering a progress tree. It's
+// meant for Dockerfiles and CI, where `styx setup` needs to run without a
+// TTY to drive a Model with. Missing prerequisites install in the same
+// dependency order OrderForInstall gives the TUI's "install all" keybind.
+func RunHeadless(ctx context.Context, opts HeadlessOptions, out io.Writer) error {
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	writeEvent := func(e HeadlessEvent) error {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	only := toNameSet(opts.Only)
+	skip := toNameSet(opts.Skip)
+
+	status := GetStatus()
+	var missing []Prerequisite
+	for _, p := range status.AllPrereqs() {
+		if len(only) > 0 && !only[p.Name] {
+			continue
+		}
+		if skip[p.Name] {
+			continue
+		}
+		if err := writeEvent(HeadlessEvent{Type: "check", Name: p.Name, Status: p.Status.String()}); err != nil {
+			return err
+		}
+		if p.Status != Installed {
+			missing = append(missing, p)
+		}
+	}
+
+	if len(missing) == 0 {
+		return writeEvent(HeadlessEvent{Type: "result", Success: true})
+	}
+
+	if opts.DryRun {
+		for _, p := range missing {
+			if err := writeEvent(HeadlessEvent{Type: "skip", Name: p.Name, Status: "dry-run"}); err != nil {
+				return err
+			}
+		}
+		return writeEvent(HeadlessEvent{Type: "result", Success: true})
+	}
+
+	if !opts.Yes {
+		return writeEvent(HeadlessEvent{Type: "result", Success: false, Error: "missing prerequisites found; re-run with --yes to install them"})
+	}
+
+	ordered, err := OrderForInstall(missing)
+	if err != nil {
+		return writeEvent(HeadlessEvent{Type: "result", Success: false, Error: err.Error()})
+	}
+
+	allSucceeded := true
+	for _, p := range ordered {
+		if ctx.Err() != nil {
+			return writeEvent(HeadlessEvent{Type: "result", Success: false, Error: ctx.Err().Error()})
+		}
+
+		if err := writeEvent(HeadlessEvent{Type: "install-start", Name: p.Name}); err != nil {
+			return err
+		}
+
+		updates := make(chan InstallUpdate)
+		done := make(chan InstallResult, 1)
+		go func(p Prerequisite) {
+			defer close(updates)
+			done <- InstallCtx(ctx, p, updates)
+		}(p)
+
+		for u := range updates {
+			if err := writeEvent(HeadlessEvent{Type: "progress", Name: u.Name, Percent: u.Percent, Line: u.Line}); err != nil {
+				return err
+			}
+		}
+
+		result := <-done
+		if !result.Success {
+			allSucceeded = false
+		}
+		if err := writeEvent(HeadlessEvent{Type: "result", Name: p.Name, Success: result.Success, Error: result.Error}); err != nil {
+			return err
+		}
+	}
+
+	return writeEvent(HeadlessEvent{Type: "result", Success: allSucceeded})
+}
+
+func toNameSet(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
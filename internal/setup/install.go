@@ -1,9 +1,13 @@
 package setup
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"strings"
 )
 
 // InstallResult represents the result of an installation attempt.
@@ -14,132 +18,135 @@ type InstallResult struct {
 	Error   string
 }
 
-// RunCommand executes a shell command and returns the result.
-func RunCommand(cmdStr string) (string, error) {
-	cmd := exec.Command("bash", "-c", cmdStr)
-	cmd.Env = os.Environ()
-	output, err := cmd.CombinedOutput()
-	return string(output), err
+// InstallUpdate is emitted while InstallCtx runs, carrying enough for a
+// TUI (see internal/tui/setup) to render a per-step progress bar and a
+// scrolling log tail instead of waiting for the whole install to finish.
+type InstallUpdate struct {
+	Name    string
+	Stage   string  // e.g. "tap", "install", "service", "done"
+	Line    string  // one line of the install command's stdout/stderr, if any
+	Percent float64 // 0..1, coarse progress through this prerequisite's install
 }
 
-// InstallNomad installs Nomad via Homebrew.
-func InstallNomad() InstallResult {
-	result := InstallResult{Name: "nomad"}
-
-	// First tap hashicorp/tap
-	output, err := RunCommand("brew tap hashicorp/tap")
-	if err != nil {
-		result.Error = fmt.Sprintf("Failed to tap hashicorp/tap: %v\n%s", err, output)
-		return result
-	}
-
-	// Then install nomad
-	output, err = RunCommand("brew install hashicorp/tap/nomad")
-	if err != nil {
-		result.Error = fmt.Sprintf("Failed to install nomad: %v\n%s", err, output)
-		return result
+func emitInstall(updates chan<- InstallUpdate, u InstallUpdate) {
+	if updates == nil {
+		return
 	}
+	updates <- u
+}
 
-	result.Success = true
-	result.Output = output
-	return result
+// RunCommand executes a shell command and returns the result.
+func RunCommand(cmdStr string) (string, error) {
+	return RunCommandCtx(context.Background(), cmdStr, nil)
 }
 
-// InstallVault installs Vault via Homebrew.
-func InstallVault() InstallResult {
-	result := InstallResult{Name: "vault"}
+// RunCommandCtx is RunCommand with a cancellable context and an optional
+// onLine callback invoked with each line of combined stdout/stderr as it's
+// produced, so PackageManager.InstallCtx implementations can stream
+// progress instead of waiting for the whole command to finish. Canceling
+// ctx kills the subprocess, the same way exec.CommandContext always does.
+func RunCommandCtx(ctx context.Context, cmdStr string, onLine func(line string)) (string, error) {
+	cmd := exec.CommandContext(ctx, "bash", "-c", cmdStr)
+	cmd.Env = os.Environ()
 
-	// First tap hashicorp/tap (may already be tapped)
-	output, err := RunCommand("brew tap hashicorp/tap")
-	if err != nil {
-		result.Error = fmt.Sprintf("Failed to tap hashicorp/tap: %v\n%s", err, output)
-		return result
-	}
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	var output strings.Builder
+	scanDone := make(chan struct{})
+	go func() {
+		defer close(scanDone)
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			output.WriteString(line)
+			output.WriteByte('\n')
+			if onLine != nil {
+				onLine(line)
+			}
+		}
+	}()
 
-	// Then install vault
-	output, err = RunCommand("brew install hashicorp/tap/vault")
-	if err != nil {
-		result.Error = fmt.Sprintf("Failed to install vault: %v\n%s", err, output)
-		return result
+	err := cmd.Start()
+	if err == nil {
+		err = cmd.Wait()
 	}
+	pw.Close()
+	<-scanDone
 
-	result.Success = true
-	result.Output = output
-	return result
+	return output.String(), err
 }
 
-// InstallContainer installs Apple Container CLI via Homebrew and starts the service.
-func InstallContainer() InstallResult {
-	result := InstallResult{Name: "container"}
-
-	// Install container
-	output, err := RunCommand("brew install container")
-	if err != nil {
-		result.Error = fmt.Sprintf("Failed to install container: %v\n%s", err, output)
-		return result
-	}
-
-	// Start the container service
-	output, err = RunCommand("brew services start container")
-	if err != nil {
-		result.Error = fmt.Sprintf("Container installed but failed to start service: %v\n%s", err, output)
-		return result
-	}
-
-	result.Success = true
-	result.Output = output
-	return result
+// Install runs the installation for the given prerequisite through the
+// first installer strategy its manifest entry has eligible on this host
+// (see ResolveInstallPlan).
+func Install(p Prerequisite) InstallResult {
+	return InstallCtx(context.Background(), p, nil)
 }
 
-// StartContainerService starts the container service if it's installed but not running.
-func StartContainerService() InstallResult {
-	result := InstallResult{Name: "container-service"}
+// InstallCtx is Install with a cancellable context and a channel of
+// InstallUpdate progress events, for the TUI's streaming install screen
+// (see internal/tui/setup). Canceling ctx kills the install subprocess and
+// returns promptly with a non-success InstallResult. updates is never
+// closed by InstallCtx - the caller owns it. It installs via the
+// manifest's first installer strategy eligible on this host; to use an
+// alternate the user cycled to instead, see InstallCtxAt.
+func InstallCtx(ctx context.Context, p Prerequisite, updates chan<- InstallUpdate) InstallResult {
+	return InstallCtxAt(ctx, p, 0, updates)
+}
 
-	output, err := RunCommand("brew services start container")
-	if err != nil {
-		result.Error = fmt.Sprintf("Failed to start container service: %v\n%s", err, output)
-		return result
+// InstallCtxAt is InstallCtx, but installs via altIndex's eligible
+// installer strategy (see InstallPlanAt) instead of always the first
+// match - the TUI's strategy-cycling keybind passes a non-zero altIndex
+// to run whichever alternate the user picked.
+func InstallCtxAt(ctx context.Context, p Prerequisite, altIndex int, updates chan<- InstallUpdate) InstallResult {
+	entry, ok := manifestByName[p.Name]
+	if !ok {
+		return InstallResult{
+			Name:  p.Name,
+			Error: fmt.Sprintf("unknown prerequisite: %s", p.Name),
+		}
 	}
 
-	result.Success = true
-	result.Output = output
-	return result
-}
+	if currentManagerKind == Mock {
+		// --package-manager mock never shells out, regardless of what the
+		// manifest's installer strategies say.
+		return currentManager.InstallCtx(ctx, PackageSpec{Name: p.Name, Service: entry.Service}, updates)
+	}
 
-// InstallTailscale installs Tailscale via Homebrew cask.
-func InstallTailscale() InstallResult {
-	result := InstallResult{Name: "tailscale"}
+	plan := InstallPlanAt(p.Name, altIndex)
+	if plan.NoneAvailable {
+		return InstallResult{
+			Name:  p.Name,
+			Error: fmt.Sprintf("%s has no installer available for this host", p.Name),
+		}
+	}
 
-	output, err := RunCommand("brew install --cask tailscale")
-	if err != nil {
-		result.Error = fmt.Sprintf("Failed to install tailscale: %v\n%s", err, output)
+	if p.Name == "container" && p.Status == Error {
+		// Already installed, just needs its service started.
+		emitInstall(updates, InstallUpdate{Name: p.Name, Stage: "service", Percent: 0.5})
+		result := InstallResult{Name: p.Name}
+		if err := managerForKind(plan.Chosen.Manager).StartService(entry.Service); err != nil {
+			result.Error = fmt.Sprintf("failed to start container service: %v", err)
+			return result
+		}
+		result.Success = true
+		emitInstall(updates, InstallUpdate{Name: p.Name, Stage: "done", Percent: 1})
 		return result
 	}
 
-	result.Success = true
-	result.Output = "Tailscale installed. Please open the Tailscale app and sign in to your tailnet."
-	return result
-}
-
-// Install runs the installation for the given prerequisite.
-func Install(p Prerequisite) InstallResult {
-	switch p.Name {
-	case "nomad":
-		return InstallNomad()
-	case "vault":
-		return InstallVault()
-	case "container":
-		if p.Status == Error {
-			// Already installed, just need to start service
-			return StartContainerService()
-		}
-		return InstallContainer()
-	case "tailscale":
-		return InstallTailscale()
-	default:
-		return InstallResult{
-			Name:  p.Name,
-			Error: fmt.Sprintf("Unknown prerequisite: %s", p.Name),
+	spec := buildSpec(p.Name, plan.Chosen)
+	result := managerForKind(plan.Chosen.Manager).InstallCtx(ctx, spec, updates)
+	if !result.Success && ctx.Err() != nil {
+		result.Error = "installation canceled"
+	}
+	if result.Success && p.Name == "tailscale" {
+		if plan.Chosen.Manager == "brew" {
+			result.Output = "Tailscale installed. Please open the Tailscale app and sign in to your tailnet."
+		} else {
+			result.Output = "Tailscale installed. Run 'tailscale up' to sign in to your tailnet."
 		}
 	}
+	return result
 }
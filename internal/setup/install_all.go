@@ -0,0 +1,103 @@
+package setup
+
+import "sync"
+
+// InstallAll installs every Prerequisite in missing, using a worker pool
+// bounded by workers, and emits Installing/Verifying ProgressEvents on
+// events (if non-nil) so a TUI/web UI can render more than one install in
+// flight at once instead of the one-at-a-time prompt cmd/styx/tui.go
+// currently drives by hand. events is closed once every install has
+// reported a final result.
+//
+// package_manager is the one real install-time dependency in this
+// registry - everything else shells out through it (see Install) - so it
+// always installs first, alone; if it fails, the rest are reported as
+// skipped rather than each failing independently with the same
+// underlying cause. Every other prerequisite in missing installs
+// concurrently, since none of Install's targets depend on one another.
+func InstallAll(missing []Prerequisite, workers int, events chan<- ProgressEvent) []InstallResult {
+	if workers < 1 {
+		workers = 1
+	}
+	if events != nil {
+		defer close(events)
+	}
+
+	var pmResult *InstallResult
+	rest := make([]Prerequisite, 0, len(missing))
+	for _, p := range missing {
+		if p.Name == packageManagerName {
+			r := installOne(p, events)
+			pmResult = &r
+			continue
+		}
+		rest = append(rest, p)
+	}
+
+	results := make([]InstallResult, 0, len(missing))
+	if pmResult != nil {
+		results = append(results, *pmResult)
+		if !pmResult.Success {
+			for _, p := range rest {
+				results = append(results, InstallResult{Name: p.Name, Error: "skipped: " + packageManagerName + " failed to install"})
+			}
+			return results
+		}
+	}
+
+	sem := make(chan struct{}, workers)
+	resultsCh := make(chan InstallResult, len(rest))
+
+	var wg sync.WaitGroup
+	for _, p := range rest {
+		wg.Add(1)
+		go func(p Prerequisite) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			resultsCh <- installOne(p, events)
+		}(p)
+	}
+	wg.Wait()
+	close(resultsCh)
+
+	for r := range resultsCh {
+		results = append(results, r)
+	}
+	return results
+}
+
+// installOne installs p, emitting Installing before and Verifying after a
+// successful install (re-running p's own CheckFunc to confirm it actually
+// took effect, rather than trusting the installer's exit code alone), and
+// a final Installed/Error event either way.
+func installOne(p Prerequisite, events chan<- ProgressEvent) InstallResult {
+	emit(events, ProgressEvent{Name: p.Name, Status: Installing})
+
+	result := Install(p)
+	if result.Success {
+		emit(events, ProgressEvent{Name: p.Name, Status: Verifying})
+		if !reverifyInstalled(p.Name) {
+			result.Success = false
+			result.Error = "installed, but the recheck still reports it missing"
+		}
+	}
+
+	final := Error
+	if result.Success {
+		final = Installed
+	}
+	emit(events, ProgressEvent{Name: p.Name, Status: final})
+	return result
+}
+
+// reverifyInstalled re-runs name's own registered CheckFunc, for
+// installOne's post-install verification.
+func reverifyInstalled(name string) bool {
+	for _, e := range registrySnapshot() {
+		if e.Name == name {
+			return e.Check(nil).Status == Installed
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+package setup
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/bootstrap"
+	"github.com/kessler-frost/styx/internal/cluster"
+	"github.com/kessler-frost/styx/internal/config"
+	"github.com/kessler-frost/styx/internal/jointoken"
+	"github.com/kessler-frost/styx/internal/launchd"
+	"github.com/kessler-frost/styx/internal/network"
+	"github.com/kessler-frost/styx/internal/pki"
+)
+
+// JoinOptions bundles the directories and credentials JoinCluster needs -
+// the same directories cmd/styx's persistent --data-dir/--config-dir/...
+// flags already carry, plus the join token `styx token create` mints.
+type JoinOptions struct {
+	DataDir, ConfigDir, SecretsDir, CertsDir, LogDir, PluginDir string
+
+	// Token is the join token minted by `styx token create` on the server
+	// being joined, required to fetch its CA/client certs and gossip key
+	// (see internal/bootstrap.FetchBootstrapFiles). A client join without
+	// one still proceeds - see buildClientSteps's identical behavior -
+	// but won't have Consul's gossip key or Nomad CA in place.
+	Token string
+
+	// BootstrapExpect is only meaningful for role "server": the number of
+	// voting servers the federated cluster should expect once this node
+	// joins as an additional one.
+	BootstrapExpect int
+}
+
+// JoinCluster joins server's existing cluster as either a Nomad client
+// (role "client") or an additional voting server (role "server"), the
+// programmatic equivalent of `styx init --join <ip>` and
+// `styx init --serve --join <ip> --join-as-server`. It's meant for a
+// caller that already has a server picked - e.g. a setup wizard built off
+// GetStatus().Federation - and wants to drive the join without shelling
+// out to the CLI.
+//
+// Steps: verify the server's cluster identity (trust-on-first-use against
+// secretsDir/cluster.json, same as verifyClusterIdentityStep), fetch its
+// CA/client certs and gossip key, generate the role's Nomad HCL pointing
+// retry_join at server's Tailscale IP, install it as a launchd unit, and
+// verify membership via /v1/agent/members once Nomad comes up.
+func JoinCluster(server network.NomadServer, role string, opts JoinOptions) error {
+	if role != "client" && role != "server" {
+		return fmt.Errorf("unknown role %q: must be \"client\" or \"server\"", role)
+	}
+
+	if err := verifyClusterIdentity(server.IP, opts.Token, opts.SecretsDir); err != nil {
+		return fmt.Errorf("failed to verify cluster identity: %w", err)
+	}
+
+	if opts.Token != "" {
+		if err := bootstrap.FetchBootstrapFiles(server.IP, opts.CertsDir, opts.SecretsDir, opts.Token); err != nil {
+			return fmt.Errorf("failed to fetch bootstrap files from %s: %w", server.IP, err)
+		}
+	}
+
+	dirs := []string{opts.DataDir, opts.ConfigDir, opts.LogDir, opts.PluginDir, opts.CertsDir}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	ip, err := network.GetPreferredIP()
+	if err != nil {
+		return fmt.Errorf("failed to detect local IP: %w", err)
+	}
+
+	configContent, err := joinConfig(role, server.IP, ip, opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate nomad config: %w", err)
+	}
+
+	configPath := filepath.Join(opts.ConfigDir, "nomad.hcl")
+	if err := config.WriteConfig(configPath, configContent); err != nil {
+		return fmt.Errorf("failed to write nomad config: %w", err)
+	}
+
+	if err := installNomadUnit(role, opts); err != nil {
+		return fmt.Errorf("failed to install nomad service: %w", err)
+	}
+
+	if err := waitForNomadHealth(60 * time.Second); err != nil {
+		return fmt.Errorf("nomad failed to start: %w", err)
+	}
+
+	return verifyMembership(server.IP, 30*time.Second)
+}
+
+// joinConfig renders the role's Nomad HCL, provisioning a server's leaf
+// mTLS certificate first if role is "server" (a client doesn't need one -
+// see buildClientSteps's identical omission of CAFile/CertFile/KeyFile).
+func joinConfig(role, serverIP, advertiseIP string, opts JoinOptions) (string, error) {
+	switch role {
+	case "client":
+		return config.GenerateClientConfig(config.ClientConfig{
+			DataDir:     opts.DataDir,
+			AdvertiseIP: advertiseIP,
+			Servers:     []string{serverIP},
+			PluginDir:   opts.PluginDir,
+		})
+	default: // "server"
+		nodeName, err := os.Hostname()
+		if err != nil || nodeName == "" {
+			nodeName = "node1"
+		}
+
+		ca, err := pki.LoadOrCreateCA(opts.CertsDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to load or create CA: %w", err)
+		}
+		certs, err := pki.Issue(ca, opts.CertsDir, pki.IssueOptions{
+			NodeName:    nodeName,
+			Role:        pki.RoleServer,
+			AdvertiseIP: advertiseIP,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to issue server leaf certificate: %w", err)
+		}
+
+		return config.GenerateServerConfig(config.ServerConfig{
+			DataDir:         opts.DataDir,
+			AdvertiseIP:     advertiseIP,
+			BootstrapExpect: opts.BootstrapExpect,
+			Servers:         []string{serverIP},
+			PluginDir:       opts.PluginDir,
+			CAFile:          certs.CAFile,
+			CertFile:        certs.CertFile,
+			KeyFile:         certs.KeyFile,
+		})
+	}
+}
+
+// installNomadUnit writes and loads com.styx.nomad.plist, pointing it at
+// `styx supervise --role=<role>` the same way join.go and
+// bootstrap_steps.go's load-launchd-service step already do.
+func installNomadUnit(role string, opts JoinOptions) error {
+	styxBinPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve styx binary path: %w", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	plistPath := filepath.Join(home, "Library", "LaunchAgents", "com.styx.nomad.plist")
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0755); err != nil {
+		return fmt.Errorf("failed to create LaunchAgents directory: %w", err)
+	}
+
+	plistCfg := launchd.PlistConfig{
+		Label:      "com.styx.nomad",
+		Program:    styxBinPath,
+		Args:       []string{"supervise", "--role=" + role, "--data-dir=" + opts.DataDir, "--config-dir=" + opts.ConfigDir, "--secrets-dir=" + opts.SecretsDir, "--certs-dir=" + opts.CertsDir, "--log-dir=" + opts.LogDir},
+		LogPath:    filepath.Join(opts.LogDir, "styx.log"),
+		ErrLogPath: filepath.Join(opts.LogDir, "styx-error.log"),
+		WorkingDir: opts.ConfigDir,
+		KeepAlive: &launchd.KeepAliveConfig{
+			SuccessfulExit: false,
+			Crashed:        true,
+			NetworkState:   true,
+		},
+		ThrottleInterval: 10,
+	}
+	if err := launchd.WritePlist(plistPath, plistCfg); err != nil {
+		return fmt.Errorf("failed to write plist: %w", err)
+	}
+
+	if launchd.IsLoaded("com.styx.nomad") {
+		if err := launchd.Unload(plistPath); err != nil {
+			return fmt.Errorf("failed to unload existing service: %w", err)
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	return launchd.Load(plistPath)
+}
+
+// waitForNomadHealth polls the local Nomad agent's health endpoint until
+// it answers OK or timeout elapses.
+func waitForNomadHealth(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	for time.Now().Before(deadline) {
+		resp, err := client.Get("http://127.0.0.1:4646/v1/agent/health")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		time.Sleep(1 * time.Second)
+	}
+	return fmt.Errorf("timeout waiting for nomad health")
+}
+
+// verifyMembership confirms this node shows up in serverIP's
+// /v1/agent/members list, so JoinCluster fails loudly instead of reporting
+// success for a node that started Nomad but never actually federated.
+func verifyMembership(serverIP string, timeout time.Duration) error {
+	nodeName, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	var lastErr error
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(fmt.Sprintf("http://%s:4646/v1/agent/members", serverIP))
+		if err != nil {
+			lastErr = err
+			time.Sleep(2 * time.Second)
+			continue
+		}
+
+		var members struct {
+			Members []struct {
+				Name string `json:"Name"`
+			} `json:"Members"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&members)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			time.Sleep(2 * time.Second)
+			continue
+		}
+
+		for _, m := range members.Members {
+			// Nomad's serf member name is "<hostname>.<region>" - compare
+			// by prefix so the region suffix doesn't break the match.
+			if strings.HasPrefix(m.Name, nodeName) {
+				return nil
+			}
+		}
+
+		lastErr = fmt.Errorf("%s not yet listed in %s's agent members", nodeName, serverIP)
+		time.Sleep(2 * time.Second)
+	}
+	return fmt.Errorf("failed to verify cluster membership: %w", lastErr)
+}
+
+// verifyClusterIdentity fetches serverIP's /bootstrap/cluster-info and
+// trust-on-first-use verifies it against secretsDir/cluster.json, the same
+// check cmd/styx's verifyClusterIdentityStep performs interactively - here
+// a first join trusts and remembers server's identity outright (no
+// operator prompt, since JoinCluster is meant for non-interactive callers
+// that already chose server deliberately), and every later join compares
+// against what was remembered.
+func verifyClusterIdentity(serverIP, token, secretsDir string) error {
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if token != "" {
+		claims, err := jointoken.Peek(token)
+		if err != nil {
+			return fmt.Errorf("failed to read claims from token: %w", err)
+		}
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return fmt.Errorf("server presented no certificate")
+			}
+			if got := bootstrap.FingerprintCert(rawCerts[0]); got != claims.CertFingerprint {
+				return fmt.Errorf("server certificate fingerprint %s does not match token's pinned fingerprint %s", got, claims.CertFingerprint)
+			}
+			return nil
+		}
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	resp, err := client.Get(fmt.Sprintf("https://%s:%d/bootstrap/cluster-info", serverIP, bootstrap.Port))
+	if err != nil {
+		return fmt.Errorf("failed to fetch cluster identity from %s: %w", serverIP, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server at %s returned %d for cluster identity", serverIP, resp.StatusCode)
+	}
+
+	var current cluster.JoinRecord
+	if err := json.NewDecoder(resp.Body).Decode(&current); err != nil {
+		return fmt.Errorf("failed to parse cluster identity response: %w", err)
+	}
+
+	previous, err := cluster.LoadJoinRecord(secretsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load previously-trusted cluster identity: %w", err)
+	}
+	if previous == nil {
+		return cluster.SaveJoinRecord(secretsDir, &current)
+	}
+	return cluster.CheckJoinRecord(previous, &current)
+}
@@ -0,0 +1,296 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// ManagerKind identifies which package manager backend Install/IsInstalled/
+// StartService dispatch to.
+type ManagerKind string
+
+const (
+	Homebrew ManagerKind = "brew"
+	Apt      ManagerKind = "apt"
+	Dnf      ManagerKind = "dnf"
+	Pacman   ManagerKind = "pacman"
+	Nix      ManagerKind = "nix"
+	Mock     ManagerKind = "mock"
+)
+
+// PackageManager installs and manages the lifecycle of Styx's prerequisites
+// through a specific OS package manager. Implementations never need to know
+// anything about nomad/vault/container/tailscale specifically - that's what
+// PackageSpec is for.
+type PackageManager interface {
+	// Install installs the package described by spec.
+	Install(spec PackageSpec) InstallResult
+	// InstallCtx is Install with a cancellable context and a channel of
+	// streaming InstallUpdate progress events (see InstallCtx in
+	// install.go). updates may be nil.
+	InstallCtx(ctx context.Context, spec PackageSpec, updates chan<- InstallUpdate) InstallResult
+	// IsInstalled reports whether the named binary is already on PATH.
+	IsInstalled(name string) bool
+	// StartService starts the named background service, if the manager
+	// supports one (e.g. `brew services start <name>`).
+	StartService(name string) error
+}
+
+// PackageSpec carries the per-manager coordinates needed to install a
+// prerequisite, built from the embedded manifest's resolved Installer
+// (see buildSpec) so the managers themselves never need to know what
+// nomad/vault/container/tailscale are.
+type PackageSpec struct {
+	Name      string // canonical name, e.g. "nomad"
+	Brew      string // brew formula/cask, e.g. "hashicorp/tap/nomad"
+	BrewTap   string // tap to add before installing, if any
+	BrewCask  bool   // true for cask installs (e.g. tailscale, a GUI app)
+	Apt       string // apt-get package name, empty if unsupported on Linux
+	Dnf       string // dnf package name, empty if unsupported on Linux
+	Pacman    string // pacman package name, empty if unsupported on Arch Linux
+	Nix       string // nix package attribute, empty if unsupported
+	Docker    string // docker image ref, empty if unsupported
+	BinaryURL string // direct-download fallback, empty if none
+	Service   string // service name to start after install, empty if none
+}
+
+// buildSpec turns name's chosen Installer into the PackageSpec its
+// matching PackageManager expects, filling in only the field that
+// installer's Manager reads.
+func buildSpec(name string, in Installer) PackageSpec {
+	spec := PackageSpec{Name: name, Service: manifestByName[name].Service}
+	switch in.Manager {
+	case "brew":
+		spec.Brew = in.Package
+		spec.BrewTap = in.Tap
+		spec.BrewCask = in.Cask
+	case "apt":
+		spec.Apt = in.Package
+	case "dnf":
+		spec.Dnf = in.Package
+	case "pacman":
+		spec.Pacman = in.Package
+	case "nix":
+		spec.Nix = in.Package
+	case "docker":
+		spec.Docker = in.Package
+	case "binary-download":
+		spec.BinaryURL = in.Package
+	}
+	return spec
+}
+
+// managerForKind returns the PackageManager that installs via manager
+// (a manifest Installer.Manager value, e.g. "apt"), independent of the
+// single host-wide currentManager - a prerequisite's chosen installer
+// strategy isn't always the same backend as the host's default.
+func managerForKind(manager string) PackageManager {
+	switch manager {
+	case "apt":
+		return AptManager{}
+	case "dnf":
+		return DnfManager{}
+	case "pacman":
+		return PacmanManager{}
+	case "nix":
+		return NixManager{}
+	case "docker":
+		return DockerManager{}
+	case "binary-download":
+		return BinaryDownloadManager{}
+	default:
+		return BrewManager{}
+	}
+}
+
+var (
+	currentManagerKind = DetectManagerKind()
+	currentManager     = newManager(currentManagerKind)
+)
+
+// DetectManagerKind picks the package manager backend for the current
+// host: Homebrew on macOS, otherwise whichever of apt-get/dnf/pacman/nix
+// is on PATH.
+func DetectManagerKind() ManagerKind {
+	if runtime.GOOS == "darwin" {
+		return Homebrew
+	}
+	if isOnPath("apt-get") {
+		return Apt
+	}
+	if isOnPath("dnf") {
+		return Dnf
+	}
+	if isOnPath("pacman") {
+		return Pacman
+	}
+	if isOnPath("nix") {
+		return Nix
+	}
+	return Homebrew
+}
+
+// CurrentManagerKind returns the package manager backend GetStatus/Install
+// currently use, so callers (e.g. the TUI setup screen) can surface it.
+func CurrentManagerKind() ManagerKind {
+	return currentManagerKind
+}
+
+// SetManager overrides the package manager backend GetStatus/Install use,
+// e.g. from the `--package-manager` flag.
+func SetManager(kind ManagerKind) error {
+	switch kind {
+	case Homebrew, Apt, Dnf, Pacman, Nix, Mock:
+		currentManagerKind = kind
+		currentManager = newManager(kind)
+		return nil
+	default:
+		return fmt.Errorf("unknown package manager %q, want one of: brew, apt, dnf, pacman, nix, mock", kind)
+	}
+}
+
+func newManager(kind ManagerKind) PackageManager {
+	switch kind {
+	case Apt:
+		return &AptManager{}
+	case Dnf:
+		return &DnfManager{}
+	case Pacman:
+		return &PacmanManager{}
+	case Nix:
+		return &NixManager{}
+	case Mock:
+		return NewMockManager()
+	default:
+		return &BrewManager{}
+	}
+}
+
+func isOnPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// installCmdsFor renders the shell commands CheckNomad/CheckVault/etc.
+// display as the "Will run:" preview: the manifest's first installer
+// strategy eligible on this host (see ResolveInstallPlan), not just
+// whichever backend happens to be currentManagerKind.
+func installCmdsFor(name string) []string {
+	if currentManagerKind == Mock {
+		return []string{fmt.Sprintf("mock install %s", name)}
+	}
+
+	plan := ResolveInstallPlan(name)
+	if plan.NoneAvailable {
+		return []string{fmt.Sprintf("%s has no installer available for this host", name)}
+	}
+	return commandsFor(name, plan.Chosen, manifestByName[name].Service)
+}
+
+// CommandsForInstaller renders the shell commands Install/InstallCtx would
+// run to install name via in, for the TUI's install prompt preview when
+// the user cycles to an alternate strategy (see InstallPlanAt).
+func CommandsForInstaller(name string, in Installer) []string {
+	return commandsFor(name, in, manifestByName[name].Service)
+}
+
+// commandsFor renders the shell commands Install/InstallCtx would run for
+// in, the same way the matching PackageManager's InstallCtx does.
+func commandsFor(name string, in Installer, service string) []string {
+	var cmds []string
+	switch in.Manager {
+	case "apt":
+		cmds = append(cmds, fmt.Sprintf("sudo apt-get install -y %s", in.Package))
+	case "dnf":
+		cmds = append(cmds, fmt.Sprintf("sudo dnf install -y %s", in.Package))
+	case "pacman":
+		cmds = append(cmds, fmt.Sprintf("sudo pacman -S --noconfirm %s", in.Package))
+	case "nix":
+		cmds = append(cmds, fmt.Sprintf("nix profile install nixpkgs#%s", in.Package))
+	case "docker":
+		return []string{fmt.Sprintf("docker pull %s", in.Package)}
+	case "binary-download":
+		return []string{fmt.Sprintf("curl -fsSL %s -o /usr/local/bin/%s", in.Package, name)}
+	default: // brew
+		if in.Tap != "" {
+			cmds = append(cmds, fmt.Sprintf("brew tap %s", in.Tap))
+		}
+		installCmd := fmt.Sprintf("brew install %s", in.Package)
+		if in.Cask {
+			installCmd = fmt.Sprintf("brew install --cask %s", in.Package)
+		}
+		cmds = append(cmds, installCmd)
+	}
+	if service != "" {
+		cmds = append(cmds, serviceStartCmdForManager(in.Manager, service))
+	}
+	return cmds
+}
+
+// serviceStartCmdForManager renders the shell command that starts service
+// through manager (a manifest Installer.Manager value), for
+// CheckContainer's "service not running" hint and commandsFor's preview.
+func serviceStartCmdForManager(manager, service string) string {
+	switch manager {
+	case "apt", "dnf", "pacman", "binary-download":
+		return fmt.Sprintf("sudo systemctl start %s", service)
+	case "nix":
+		return fmt.Sprintf("systemctl --user start %s", service)
+	case "docker":
+		return fmt.Sprintf("docker start %s", service)
+	default: // brew
+		return fmt.Sprintf("brew services start %s", service)
+	}
+}
+
+// CheckPackageManager reports whether the backend package manager itself
+// (Homebrew, apt-get, dnf) is available. Prerequisite installs fall back to
+// "Pending" when it isn't.
+func CheckPackageManager(kind ManagerKind) Prerequisite {
+	p := Prerequisite{Name: string(kind)}
+
+	switch kind {
+	case Homebrew:
+		p.CheckCmd = "which brew"
+		if !isOnPath("brew") {
+			p.Status = Missing
+			p.InstallCmds = []string{"Visit https://brew.sh to install Homebrew"}
+			return p
+		}
+	case Apt:
+		p.CheckCmd = "which apt-get"
+		if !isOnPath("apt-get") {
+			p.Status = Missing
+			p.InstallCmds = []string{"apt-get is required; install it via your distro's base packages"}
+			return p
+		}
+	case Dnf:
+		p.CheckCmd = "which dnf"
+		if !isOnPath("dnf") {
+			p.Status = Missing
+			p.InstallCmds = []string{"dnf is required; install it via your distro's base packages"}
+			return p
+		}
+	case Pacman:
+		p.CheckCmd = "which pacman"
+		if !isOnPath("pacman") {
+			p.Status = Missing
+			p.InstallCmds = []string{"pacman is required; install it via your distro's base packages"}
+			return p
+		}
+	case Nix:
+		p.CheckCmd = "which nix"
+		if !isOnPath("nix") {
+			p.Status = Missing
+			p.InstallCmds = []string{"Visit https://nixos.org/download to install Nix"}
+			return p
+		}
+	case Mock:
+		// MockManager never shells out, so there's nothing to check.
+	}
+
+	p.Status = Installed
+	return p
+}
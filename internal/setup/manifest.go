@@ -0,0 +1,325 @@
+package setup
+
+import (
+	"embed"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+//go:embed prereqs.yaml
+var manifestFS embed.FS
+
+// Installer is one way to install a prerequisite: a package manager
+// backend plus the coordinates it needs, restricted to the platform named
+// by Platform ("darwin", "linux", or "any" for platform-independent
+// backends like nix/docker/binary-download).
+type Installer struct {
+	Platform string
+	Manager  string // "brew", "apt", "dnf", "pacman", "nix", "docker", "binary-download"
+	Package  string // formula/cask/package/image name, or a download URL for binary-download
+	Tap      string // brew tap to add before installing, brew only
+	Cask     bool   // brew --cask, brew only
+}
+
+// ManifestEntry is one prerequisite's declarative install data, loaded
+// from the embedded prereqs.yaml - see LoadManifest.
+type ManifestEntry struct {
+	Name              string
+	CheckCmd          string
+	VersionCmd        string // command whose output reports the installed version, for Requires checks
+	VersionConstraint string
+	Service           string
+	Installers        []Installer
+
+	// Requires names other manifest entries this one depends on, each
+	// with the version constraint it needs satisfied (e.g.
+	// "docker": ">=24.0.0") - see OrderForInstall.
+	Requires map[string]string
+}
+
+// InstallPlan is the resolved installer strategy for one prerequisite, as
+// picked by ResolveInstallPlan: Chosen is what Install/InstallCtx actually
+// runs, Alternates are the other strategies eligible on this host that the
+// TUI lets the user cycle to instead (see renderInstallPrompt).
+type InstallPlan struct {
+	Name          string
+	Chosen        Installer
+	Alternates    []Installer
+	NoneAvailable bool // true if nothing in the manifest matches this host
+}
+
+var (
+	manifestEntries []ManifestEntry
+	manifestByName  map[string]ManifestEntry
+)
+
+func init() {
+	entries, err := LoadManifest()
+	if err != nil {
+		panic(fmt.Sprintf("setup: invalid embedded prereqs.yaml: %v", err))
+	}
+	manifestEntries = entries
+	manifestByName = make(map[string]ManifestEntry, len(entries))
+	for _, e := range entries {
+		manifestByName[e.Name] = e
+	}
+}
+
+// LoadManifest parses the embedded prereqs.yaml, the single source of
+// truth for how each prerequisite gets installed across platforms and
+// package managers.
+func LoadManifest() ([]ManifestEntry, error) {
+	data, err := manifestFS.ReadFile("prereqs.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded prereqs.yaml: %w", err)
+	}
+	return parseManifest(string(data))
+}
+
+// EligibleInstallers returns name's installers (in manifest order) whose
+// platform matches this host and whose manager is actually available,
+// or nil if name isn't in the manifest or none are eligible.
+func EligibleInstallers(name string) []Installer {
+	entry, ok := manifestByName[name]
+	if !ok {
+		return nil
+	}
+	var eligible []Installer
+	for _, in := range entry.Installers {
+		if platformMatches(in.Platform) && managerAvailable(in.Manager) {
+			eligible = append(eligible, in)
+		}
+	}
+	return eligible
+}
+
+// ResolveInstallPlan picks name's first eligible installer for the
+// current host, exposing every other eligible one as an Alternate the TUI
+// can cycle to instead of being stuck with whichever matched first.
+func ResolveInstallPlan(name string) InstallPlan {
+	plan := InstallPlan{Name: name}
+	eligible := EligibleInstallers(name)
+	if len(eligible) == 0 {
+		plan.NoneAvailable = true
+		return plan
+	}
+	plan.Chosen = eligible[0]
+	plan.Alternates = eligible[1:]
+	return plan
+}
+
+// InstallPlanAt is ResolveInstallPlan, but picks eligible[altIndex] as
+// Chosen (wrapping around) instead of always the first match - used by
+// the TUI's strategy-cycling keybind.
+func InstallPlanAt(name string, altIndex int) InstallPlan {
+	plan := InstallPlan{Name: name}
+	eligible := EligibleInstallers(name)
+	if len(eligible) == 0 {
+		plan.NoneAvailable = true
+		return plan
+	}
+	idx := altIndex % len(eligible)
+	plan.Chosen = eligible[idx]
+	plan.Alternates = append(append([]Installer{}, eligible[:idx]...), eligible[idx+1:]...)
+	return plan
+}
+
+func platformMatches(platform string) bool {
+	return platform == "any" || platform == runtime.GOOS
+}
+
+// managerAvailable reports whether manager's backend is actually usable
+// on this host.
+func managerAvailable(manager string) bool {
+	switch manager {
+	case "brew":
+		return isOnPath("brew")
+	case "apt":
+		return isOnPath("apt-get")
+	case "dnf":
+		return isOnPath("dnf")
+	case "pacman":
+		return isOnPath("pacman")
+	case "nix":
+		return isOnPath("nix")
+	case "docker":
+		return isOnPath("docker")
+	case "binary-download":
+		return isOnPath("curl")
+	default:
+		return false
+	}
+}
+
+// parseManifest reads the restricted subset of YAML prereqs.yaml uses - a
+// top-level list of prerequisite entries, each with an optional nested
+// "installers:" list - the same way internal/chaos/load.go and
+// internal/services/definition.go hand-roll their own subsets rather than
+// pulling in a general YAML/HCL parser.
+func parseManifest(data string) ([]ManifestEntry, error) {
+	lines := strings.Split(data, "\n")
+
+	var (
+		entries      []ManifestEntry
+		cur          *ManifestEntry
+		curInstaller *Installer
+		inInstallers bool
+		inRequires   bool
+	)
+
+	flushInstaller := func() {
+		if cur != nil && curInstaller != nil {
+			cur.Installers = append(cur.Installers, *curInstaller)
+			curInstaller = nil
+		}
+	}
+	flushEntry := func() {
+		flushInstaller()
+		if cur != nil {
+			entries = append(entries, *cur)
+			cur = nil
+		}
+	}
+
+	for i, raw := range lines {
+		line := strings.TrimRight(raw, " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case indent == 0 && strings.HasPrefix(trimmed, "- "):
+			flushEntry()
+			cur = &ManifestEntry{}
+			inInstallers = false
+			inRequires = false
+			key, value, err := splitManifestField(trimmed[2:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if err := applyEntryField(cur, key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+		case cur == nil:
+			return nil, fmt.Errorf("line %d: field outside any prerequisite entry", i+1)
+
+		case indent == 2 && trimmed == "installers:":
+			flushInstaller()
+			inInstallers = true
+			inRequires = false
+
+		case indent == 2 && trimmed == "requires:":
+			flushInstaller()
+			inInstallers = false
+			inRequires = true
+
+		case inRequires && indent == 4:
+			key, value, err := splitManifestField(trimmed)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if cur.Requires == nil {
+				cur.Requires = make(map[string]string)
+			}
+			cur.Requires[key] = value
+
+		case inInstallers && indent == 4 && strings.HasPrefix(trimmed, "- "):
+			flushInstaller()
+			curInstaller = &Installer{}
+			key, value, err := splitManifestField(trimmed[2:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if err := applyInstallerField(curInstaller, key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+		case inInstallers && indent >= 6 && curInstaller != nil:
+			key, value, err := splitManifestField(trimmed)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if err := applyInstallerField(curInstaller, key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+		case indent == 2:
+			inInstallers = false
+			inRequires = false
+			key, value, err := splitManifestField(trimmed)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if err := applyEntryField(cur, key, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+		default:
+			return nil, fmt.Errorf("line %d: unexpected indentation", i+1)
+		}
+	}
+	flushEntry()
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("manifest has no prerequisite entries")
+	}
+	return entries, nil
+}
+
+func applyEntryField(e *ManifestEntry, key, value string) error {
+	switch key {
+	case "name":
+		e.Name = value
+	case "check_cmd":
+		e.CheckCmd = value
+	case "version_cmd":
+		e.VersionCmd = value
+	case "version_constraint":
+		e.VersionConstraint = value
+	case "service":
+		e.Service = value
+	default:
+		return fmt.Errorf("unknown prerequisite field %q", key)
+	}
+	return nil
+}
+
+func applyInstallerField(in *Installer, key, value string) error {
+	switch key {
+	case "platform":
+		in.Platform = value
+	case "manager":
+		in.Manager = value
+	case "package":
+		in.Package = value
+	case "tap":
+		in.Tap = value
+	case "cask":
+		in.Cask = value == "true"
+	default:
+		return fmt.Errorf("unknown installer field %q", key)
+	}
+	return nil
+}
+
+// splitManifestField splits a "key: value" line, unquoting value if it's
+// wrapped in double quotes.
+func splitManifestField(line string) (key, value string, err error) {
+	idx := strings.Index(line, ":")
+	if idx == -1 {
+		return "", "", fmt.Errorf("expected 'field: value', got %q", line)
+	}
+	key = strings.TrimSpace(line[:idx])
+	value = strings.TrimSpace(line[idx+1:])
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("expected 'field: value', got %q", line)
+	}
+	return key, value, nil
+}
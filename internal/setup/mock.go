@@ -0,0 +1,59 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+)
+
+// MockManager is a PackageManager that never shells out, for deterministic
+// unit tests: Install marks the package installed in-memory (or fails if
+// ForceError is set) and IsInstalled/StartService consult that same map.
+type MockManager struct {
+	Installed  map[string]bool
+	ForceError string // if set, Install fails with this error for every package
+}
+
+// NewMockManager returns a MockManager with no packages marked installed.
+func NewMockManager() *MockManager {
+	return &MockManager{Installed: make(map[string]bool)}
+}
+
+// Install records spec.Name as installed, unless ForceError is set.
+func (m *MockManager) Install(spec PackageSpec) InstallResult {
+	return m.InstallCtx(context.Background(), spec, nil)
+}
+
+// InstallCtx is Install with a cancellable context and streaming
+// InstallUpdate events. MockManager never actually shells out, so it just
+// emits a single start/done pair.
+func (m *MockManager) InstallCtx(ctx context.Context, spec PackageSpec, updates chan<- InstallUpdate) InstallResult {
+	result := InstallResult{Name: spec.Name}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0})
+
+	if m.ForceError != "" {
+		result.Error = m.ForceError
+		return result
+	}
+
+	if m.Installed == nil {
+		m.Installed = make(map[string]bool)
+	}
+	m.Installed[spec.Name] = true
+
+	result.Success = true
+	result.Output = fmt.Sprintf("mock: installed %s", spec.Name)
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "done", Percent: 1})
+	return result
+}
+
+// IsInstalled reports whether name was previously Install()ed (or was
+// pre-seeded into m.Installed).
+func (m *MockManager) IsInstalled(name string) bool {
+	return m.Installed[name]
+}
+
+// StartService is a no-op; MockManager has no real services to start.
+func (m *MockManager) StartService(name string) error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+)
+
+// NixManager installs prerequisites via `nix profile install`, for hosts
+// with Nix available regardless of OS - the one manager not gated to a
+// single platform in the manifest.
+type NixManager struct{}
+
+// Install runs `nix profile install` for spec.Nix, starting spec.Service
+// afterward if one is configured.
+func (m NixManager) Install(spec PackageSpec) InstallResult {
+	return m.InstallCtx(context.Background(), spec, nil)
+}
+
+// InstallCtx is Install with a cancellable context and streaming
+// InstallUpdate events for each stage (install, service).
+func (m NixManager) InstallCtx(ctx context.Context, spec PackageSpec, updates chan<- InstallUpdate) InstallResult {
+	result := InstallResult{Name: spec.Name}
+
+	if spec.Nix == "" {
+		result.Error = fmt.Sprintf("%s has no nix package configured", spec.Name)
+		return result
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.1})
+	output, err := RunCommandCtx(ctx, fmt.Sprintf("nix profile install nixpkgs#%s", spec.Nix), func(line string) {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Line: line, Percent: 0.5})
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to install %s: %v\n%s", spec.Name, err, output)
+		return result
+	}
+
+	result.Success = true
+	result.Output = output
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "install", Percent: 0.9})
+
+	if spec.Service != "" {
+		emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "service", Percent: 0.95})
+		if err := m.StartService(spec.Service); err != nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("%s installed but failed to start service: %v", spec.Name, err)
+			return result
+		}
+	}
+
+	emitInstall(updates, InstallUpdate{Name: spec.Name, Stage: "done", Percent: 1})
+	return result
+}
+
+// IsInstalled reports whether name is on PATH.
+func (NixManager) IsInstalled(name string) bool {
+	return isOnPath(name)
+}
+
+// StartService runs `systemctl --user start <name>`, since a Nix-profile
+// install has no package manager of its own to own the service.
+func (NixManager) StartService(name string) error {
+	output, err := RunCommand(fmt.Sprintf("systemctl --user start %s", name))
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+	return nil
+}
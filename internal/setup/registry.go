@@ -0,0 +1,240 @@
+package setup
+
+import (
+	"fmt"
+	"sync"
+)
+
+// packageManagerName is the registry entry name for the package manager
+// backend itself (Homebrew/apt/dnf) - every other entry's install (but not
+// its check, see CheckFunc) depends on it being Installed.
+const packageManagerName = "package_manager"
+
+// CheckFunc computes a Prerequisite's current status. deps holds the
+// already-computed Prerequisite for each name in the entry's DependsOn,
+// so e.g. federation's check can read deps["tailscale"] without racing
+// the tailscale entry's own check.
+type CheckFunc func(deps map[string]Prerequisite) Prerequisite
+
+// registryEntry is one node in the prerequisite DAG. DependsOn names real
+// data dependencies a Check needs already computed - it's deliberately
+// left empty for nomad/vault/container/tailscale, which don't need
+// package_manager's result to check whether the tool is already present
+// (only to install it), so the scheduler runs them concurrently instead of
+// queuing behind it.
+type registryEntry struct {
+	Name          string
+	DependsOn     []string
+	Check         CheckFunc
+	Informational bool // excluded from MissingPrereqs/NeedsSetup, see Prerequisite
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []registryEntry
+)
+
+// RegisterPrereq adds a prerequisite to the DAG-driven scheduler GetStatus
+// runs, so a new one (e.g. consul, step-cli) can be wired in without
+// editing PrereqStatus or GetStatus - AllPrereqs/MissingPrereqs pick it up
+// automatically. dependsOn names other registered prerequisites whose
+// computed Prerequisite this one's check needs - not "must be installed
+// first" (see registryEntry's doc comment); pass nil if check needs
+// nothing from its peers. informational marks entries like federation
+// that report state but aren't something to install, so NeedsSetup and
+// MissingPrereqs ignore them.
+func RegisterPrereq(name string, dependsOn []string, check CheckFunc, informational bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, registryEntry{
+		Name:          name,
+		DependsOn:     dependsOn,
+		Check:         check,
+		Informational: informational,
+	})
+}
+
+func registrySnapshot() []registryEntry {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]registryEntry(nil), registry...)
+}
+
+func init() {
+	RegisterPrereq(packageManagerName, nil, func(map[string]Prerequisite) Prerequisite {
+		return CheckPackageManager(currentManagerKind)
+	}, false)
+	RegisterPrereq("nomad", nil, func(map[string]Prerequisite) Prerequisite {
+		return CheckNomad()
+	}, false)
+	RegisterPrereq("vault", nil, func(map[string]Prerequisite) Prerequisite {
+		return CheckVault()
+	}, false)
+	RegisterPrereq("container", nil, func(map[string]Prerequisite) Prerequisite {
+		return CheckContainer()
+	}, false)
+	RegisterPrereq("tailscale", nil, func(map[string]Prerequisite) Prerequisite {
+		return CheckTailscale()
+	}, false)
+	RegisterPrereq("federation", []string{"tailscale"}, func(deps map[string]Prerequisite) Prerequisite {
+		return CheckFederation(deps["tailscale"].Status == Installed)
+	}, true)
+}
+
+// ProgressEvent is emitted as a registered prerequisite's check or install
+// moves between states, so a TUI/web UI (see internal/tui/setup) can
+// render a live progress tree instead of waiting for the whole batch.
+type ProgressEvent struct {
+	Name   string
+	Status Status
+}
+
+func emit(events chan<- ProgressEvent, e ProgressEvent) {
+	if events == nil {
+		return
+	}
+	events <- e
+}
+
+// runChecks walks the registry's dependency DAG, running every entry whose
+// DependsOn are already computed concurrently with every other entry at
+// the same "depth" - package_manager and nomad/vault/container/tailscale
+// all start in the same wave, since none of the latter depend on the
+// former's result (only federation, on tailscale, does). events receives
+// a Checking event before each entry's Check runs and a final event
+// carrying its resting Status after, and is closed once every entry has
+// reported.
+func runChecks(events chan<- ProgressEvent) map[string]Prerequisite {
+	entries := registrySnapshot()
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]Prerequisite, len(entries))
+		done    = make(map[string]struct{}, len(entries))
+	)
+
+	if events != nil {
+		defer close(events)
+	}
+
+	remaining := entries
+	for len(remaining) > 0 {
+		var ready, notReady []registryEntry
+		mu.Lock()
+		for _, e := range remaining {
+			if dependsSatisfied(e.DependsOn, done) {
+				ready = append(ready, e)
+			} else {
+				notReady = append(notReady, e)
+			}
+		}
+		mu.Unlock()
+
+		if len(ready) == 0 {
+			// A dependency name that was never registered, or a cycle -
+			// report it loudly instead of hanging forever.
+			for _, e := range notReady {
+				mu.Lock()
+				results[e.Name] = Prerequisite{Name: e.Name, Status: Error, Error: "unresolved prerequisite dependency"}
+				done[e.Name] = struct{}{}
+				mu.Unlock()
+			}
+			break
+		}
+
+		var wg sync.WaitGroup
+		for _, e := range ready {
+			wg.Add(1)
+			go func(e registryEntry) {
+				defer wg.Done()
+				emit(events, ProgressEvent{Name: e.Name, Status: Checking})
+
+				mu.Lock()
+				deps := make(map[string]Prerequisite, len(e.DependsOn))
+				for _, d := range e.DependsOn {
+					deps[d] = results[d]
+				}
+				mu.Unlock()
+
+				p := e.Check(deps)
+
+				mu.Lock()
+				results[e.Name] = p
+				done[e.Name] = struct{}{}
+				mu.Unlock()
+
+				emit(events, ProgressEvent{Name: e.Name, Status: p.Status})
+			}(e)
+		}
+		wg.Wait()
+
+		remaining = notReady
+	}
+
+	return results
+}
+
+func dependsSatisfied(dependsOn []string, done map[string]struct{}) bool {
+	for _, d := range dependsOn {
+		if _, ok := done[d]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// buildPrereqStatus maps the registry's results onto PrereqStatus's named
+// fields (for the five built-in prerequisites) plus its all slice (every
+// registered prerequisite, in registration order), so RegisterPrereq
+// callers show up in AllPrereqs/MissingPrereqs without a corresponding
+// named field.
+func buildPrereqStatus(results map[string]Prerequisite) PrereqStatus {
+	var status PrereqStatus
+	for _, e := range registrySnapshot() {
+		p := results[e.Name]
+		switch e.Name {
+		case packageManagerName:
+			status.PackageManager = p
+		case "nomad":
+			status.Nomad = p
+		case "vault":
+			status.Vault = p
+		case "container":
+			status.Container = p
+		case "tailscale":
+			status.Tailscale = p
+		case "federation":
+			status.Federation = p
+		}
+		if !e.Informational {
+			status.all = append(status.all, p)
+		}
+	}
+	return status
+}
+
+// applyPackageManagerGate downgrades any non-installed, non-informational
+// prerequisite to Pending when the package manager itself isn't
+// installed - Install can't do anything useful without one. Runs after
+// every check has already completed (unlike the old hardcoded
+// short-circuit), so a tool installed by hand outside the package
+// manager (e.g. nomad already on PATH) still reports Installed.
+func applyPackageManagerGate(results map[string]Prerequisite) {
+	pm := results[packageManagerName]
+	if pm.Status == Installed {
+		return
+	}
+
+	reason := fmt.Sprintf("Requires %s", currentManagerKind)
+	for _, e := range registrySnapshot() {
+		if e.Name == packageManagerName || e.Informational {
+			continue
+		}
+		p := results[e.Name]
+		if p.Status != Installed {
+			p.Status = Pending
+			p.Error = reason
+			results[e.Name] = p
+		}
+	}
+}
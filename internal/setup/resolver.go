@@ -0,0 +1,185 @@
+package setup
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// semver is a minimal major.minor.patch version, just enough to evaluate
+// the comparison operators a manifest entry's Requires constraints use.
+// This repo hand-rolls its own rather than vendoring Masterminds/semver,
+// the same way internal/chaos and internal/services hand-roll their own
+// declarative-format parsers instead of a general library (see
+// manifest.go's parseManifest).
+type semver struct {
+	major, minor, patch int
+}
+
+var semverRe = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// parseSemver extracts the first major[.minor[.patch]] number it finds in
+// s, so it works equally well against a bare "1.7.0" constraint value and
+// noisy `nomad version` output like "Nomad v1.7.2 (abc123)".
+func parseSemver(s string) (semver, error) {
+	m := semverRe.FindStringSubmatch(s)
+	if m == nil {
+		return semver{}, fmt.Errorf("no version number found in %q", s)
+	}
+	var v semver
+	v.major, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		v.minor, _ = strconv.Atoi(m[2])
+	}
+	if m[3] != "" {
+		v.patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+func (v semver) compare(o semver) int {
+	if v.major != o.major {
+		return v.major - o.major
+	}
+	if v.minor != o.minor {
+		return v.minor - o.minor
+	}
+	return v.patch - o.patch
+}
+
+// satisfiesConstraint reports whether version satisfies constraint, a
+// string like ">=24.0.0", "<=1.2", ">1", "<2", or "1.0.0" (defaults to
+// >= when no operator prefixes it).
+func satisfiesConstraint(version, constraint string) (bool, error) {
+	constraint = strings.TrimSpace(constraint)
+	op := ">="
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(constraint, candidate) {
+			op = candidate
+			constraint = strings.TrimSpace(strings.TrimPrefix(constraint, candidate))
+			break
+		}
+	}
+
+	want, err := parseSemver(constraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+	}
+	have, err := parseSemver(version)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	cmp := have.compare(want)
+	switch op {
+	case ">=":
+		return cmp >= 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case "<":
+		return cmp < 0, nil
+	default: // "="
+		return cmp == 0, nil
+	}
+}
+
+// requirementSatisfied reports whether name (already installed, not
+// queued alongside the entry that requires it) satisfies constraint. A
+// manifest entry with no version_cmd can't report an installed version,
+// so its requirements are assumed satisfied rather than blocking
+// install-all on something unverifiable.
+func requirementSatisfied(name, constraint string) (bool, error) {
+	entry, ok := manifestByName[name]
+	if !ok {
+		return false, fmt.Errorf("unknown prerequisite %q", name)
+	}
+	if entry.VersionCmd == "" {
+		return true, nil
+	}
+	output, err := RunCommand(entry.VersionCmd)
+	if err != nil {
+		return false, fmt.Errorf("failed to run %q: %w", entry.VersionCmd, err)
+	}
+	return satisfiesConstraint(output, constraint)
+}
+
+// OrderForInstall topologically sorts missing by each entry's manifest
+// Requires edges, so installing all of them runs a dependency before
+// whatever requires it instead of racing them - the caller re-derives
+// missing and calls this again after each install completes, so a
+// freshly-installed prerequisite can unblock whatever needed it. Ties
+// (prerequisites with no remaining unmet dependency) preserve missing's
+// original relative order.
+//
+// It returns an error naming the offending edge if a requirement can't
+// be satisfied: a dependency cycle among missing, or a requirement on a
+// prerequisite that's neither queued for install nor already installed
+// at a version the constraint allows.
+func OrderForInstall(missing []Prerequisite) ([]Prerequisite, error) {
+	byName := make(map[string]Prerequisite, len(missing))
+	order := make(map[string]int, len(missing))
+	for i, p := range missing {
+		byName[p.Name] = p
+		order[p.Name] = i
+	}
+
+	indegree := make(map[string]int, len(missing))
+	dependents := make(map[string][]string)
+	for _, p := range missing {
+		indegree[p.Name] = 0
+	}
+	for _, p := range missing {
+		for dep, constraint := range manifestByName[p.Name].Requires {
+			if _, queued := byName[dep]; queued {
+				indegree[p.Name]++
+				dependents[dep] = append(dependents[dep], p.Name)
+				continue
+			}
+			ok, err := requirementSatisfied(dep, constraint)
+			if err != nil {
+				return nil, fmt.Errorf("%s requires %s %s: %w", p.Name, dep, constraint, err)
+			}
+			if !ok {
+				return nil, fmt.Errorf("%s requires %s %s, which isn't installed or queued to install", p.Name, dep, constraint)
+			}
+		}
+	}
+
+	var ready []string
+	for _, p := range missing {
+		if indegree[p.Name] == 0 {
+			ready = append(ready, p.Name)
+		}
+	}
+
+	var ordered []Prerequisite
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return order[ready[i]] < order[ready[j]] })
+		name := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, byName[name])
+
+		for _, dependent := range dependents[name] {
+			indegree[dependent]--
+			if indegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(ordered) != len(missing) {
+		var stuck []string
+		for name, n := range indegree {
+			if n > 0 {
+				stuck = append(stuck, name)
+			}
+		}
+		sort.Strings(stuck)
+		return nil, fmt.Errorf("unsatisfiable prerequisite dependency cycle among: %s", strings.Join(stuck, ", "))
+	}
+	return ordered, nil
+}
@@ -0,0 +1,138 @@
+package setup
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		in                  string
+		major, minor, patch int
+	}{
+		{"1.7.0", 1, 7, 0},
+		{"24.0.0", 24, 0, 0},
+		{"2", 2, 0, 0},
+		{"1.2", 1, 2, 0},
+		{"Nomad v1.7.2 (abc123)", 1, 7, 2},
+	}
+
+	for _, tt := range tests {
+		v, err := parseSemver(tt.in)
+		if err != nil {
+			t.Errorf("parseSemver(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if v.major != tt.major || v.minor != tt.minor || v.patch != tt.patch {
+			t.Errorf("parseSemver(%q) = %+v, want {%d %d %d}", tt.in, v, tt.major, tt.minor, tt.patch)
+		}
+	}
+}
+
+func TestParseSemverRejectsNoVersionNumber(t *testing.T) {
+	if _, err := parseSemver("not a version"); err == nil {
+		t.Error("parseSemver(\"not a version\") succeeded, want error")
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int // sign only
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.1.0", "1.0.0", 1},
+		{"1.0.0", "1.1.0", -1},
+		{"2.0.0", "1.9.9", 1},
+		{"1.0.1", "1.0.0", 1},
+	}
+
+	for _, tt := range tests {
+		a, err := parseSemver(tt.a)
+		if err != nil {
+			t.Fatalf("parseSemver(%q) failed: %v", tt.a, err)
+		}
+		b, err := parseSemver(tt.b)
+		if err != nil {
+			t.Fatalf("parseSemver(%q) failed: %v", tt.b, err)
+		}
+		got := a.compare(b)
+		switch {
+		case tt.want > 0 && got <= 0:
+			t.Errorf("%s.compare(%s) = %d, want > 0", tt.a, tt.b, got)
+		case tt.want < 0 && got >= 0:
+			t.Errorf("%s.compare(%s) = %d, want < 0", tt.a, tt.b, got)
+		case tt.want == 0 && got != 0:
+			t.Errorf("%s.compare(%s) = %d, want 0", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestSatisfiesConstraint(t *testing.T) {
+	tests := []struct {
+		version, constraint string
+		want                bool
+	}{
+		{"1.7.2", ">=1.7.0", true},
+		{"1.6.9", ">=1.7.0", false},
+		{"1.7.0", "<=1.7.0", true},
+		{"1.7.1", "<=1.7.0", false},
+		{"2.0.0", ">1.9.9", true},
+		{"1.9.9", ">1.9.9", false},
+		{"1.0.0", "<2.0.0", true},
+		{"2.0.0", "<2.0.0", false},
+		{"1.7.0", "=1.7.0", true},
+		{"1.7.1", "=1.7.0", false},
+		{"1.7.0", "1.7.0", true}, // no operator defaults to >=
+		{"1.8.0", "1.7.0", true},
+		{"1.6.0", "1.7.0", false},
+	}
+
+	for _, tt := range tests {
+		got, err := satisfiesConstraint(tt.version, tt.constraint)
+		if err != nil {
+			t.Errorf("satisfiesConstraint(%q, %q) failed: %v", tt.version, tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("satisfiesConstraint(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestSatisfiesConstraintRejectsBadInput(t *testing.T) {
+	if _, err := satisfiesConstraint("not-a-version", ">=1.0.0"); err == nil {
+		t.Error("satisfiesConstraint with an unparseable version succeeded, want error")
+	}
+	if _, err := satisfiesConstraint("1.0.0", ">=not-a-version"); err == nil {
+		t.Error("satisfiesConstraint with an unparseable constraint succeeded, want error")
+	}
+}
+
+// TestOrderForInstallRespectsRequires uses nomad/container, the one real
+// Requires edge in prereqs.yaml (nomad requires container), so it exercises
+// OrderForInstall against the actual embedded manifest rather than a fake
+// one (there's no exported way to swap manifestByName out for a test one).
+func TestOrderForInstallRespectsRequires(t *testing.T) {
+	missing := []Prerequisite{{Name: "nomad"}, {Name: "container"}}
+
+	ordered, err := OrderForInstall(missing)
+	if err != nil {
+		t.Fatalf("OrderForInstall failed: %v", err)
+	}
+	if len(ordered) != 2 || ordered[0].Name != "container" || ordered[1].Name != "nomad" {
+		t.Errorf("OrderForInstall(%v) = %v, want [container, nomad]", missing, ordered)
+	}
+}
+
+// TestOrderForInstallPreservesTieOrder uses vault and tailscale, two
+// prereqs.yaml entries with no Requires edges between them, so their
+// relative order should come through unchanged.
+func TestOrderForInstallPreservesTieOrder(t *testing.T) {
+	missing := []Prerequisite{{Name: "tailscale"}, {Name: "vault"}}
+
+	ordered, err := OrderForInstall(missing)
+	if err != nil {
+		t.Fatalf("OrderForInstall failed: %v", err)
+	}
+	if len(ordered) != 2 || ordered[0].Name != "tailscale" || ordered[1].Name != "vault" {
+		t.Errorf("OrderForInstall(%v) = %v, want original order [tailscale, vault]", missing, ordered)
+	}
+}
@@ -8,6 +8,19 @@ const (
 	Pending                 // Waiting for dependency
 	Installed               // Ready to use
 	Error                   // Installed but not working
+
+	// FederationAvailable is CheckFederation's status when existing Nomad
+	// servers were found on the tailnet - not a blocker like the others,
+	// just a signal that JoinCluster is an option alongside bootstrapping
+	// a new cluster.
+	FederationAvailable
+
+	// Checking, Installing, and Verifying are transient states carried by
+	// a ProgressEvent while runChecks/InstallAll are still working on a
+	// prerequisite - never a Prerequisite's own resting Status.
+	Checking
+	Installing
+	Verifying
 )
 
 func (s Status) String() string {
@@ -20,6 +33,14 @@ func (s Status) String() string {
 		return "installed"
 	case Error:
 		return "error"
+	case FederationAvailable:
+		return "federation-available"
+	case Checking:
+		return "checking"
+	case Installing:
+		return "installing"
+	case Verifying:
+		return "verifying"
 	default:
 		return "unknown"
 	}
@@ -37,72 +58,54 @@ type Prerequisite struct {
 
 // PrereqStatus contains the status of all prerequisites.
 type PrereqStatus struct {
-	Homebrew  Prerequisite `json:"homebrew"`
-	Nomad     Prerequisite `json:"nomad"`
-	Vault     Prerequisite `json:"vault"`
-	Container Prerequisite `json:"container"`
-	Tailscale Prerequisite `json:"tailscale"`
-}
-
-// GetStatus checks all prerequisites and returns their current status.
-func GetStatus() PrereqStatus {
-	status := PrereqStatus{}
+	PackageManager Prerequisite `json:"package_manager"`
+	Nomad          Prerequisite `json:"nomad"`
+	Vault          Prerequisite `json:"vault"`
+	Container      Prerequisite `json:"container"`
+	Tailscale      Prerequisite `json:"tailscale"`
 
-	// Check Homebrew first (other installs depend on it)
-	status.Homebrew = CheckBrew()
+	// Federation is informational only - see CheckFederation - and
+	// deliberately excluded from AllPrereqs/NeedsSetup/MissingPrereqs, so
+	// a node with no peers to join isn't treated as missing a prerequisite.
+	Federation Prerequisite `json:"federation"`
 
-	// If Homebrew is missing, mark others as pending
-	if status.Homebrew.Status != Installed {
-		status.Nomad = Prerequisite{
-			Name:   "nomad",
-			Status: Pending,
-			Error:  "Requires Homebrew",
-		}
-		status.Vault = Prerequisite{
-			Name:   "vault",
-			Status: Pending,
-			Error:  "Requires Homebrew",
-		}
-		status.Container = Prerequisite{
-			Name:   "container",
-			Status: Pending,
-			Error:  "Requires Homebrew",
-		}
-		status.Tailscale = Prerequisite{
-			Name:   "tailscale",
-			Status: Pending,
-			Error:  "Requires Homebrew",
-		}
-		return status
-	}
+	// all holds every non-informational registered prerequisite (see
+	// RegisterPrereq) in registration order, so AllPrereqs/MissingPrereqs
+	// work for prerequisites beyond the named fields above without this
+	// struct needing a new field every time one's added.
+	all []Prerequisite
+}
 
-	// Check all other prerequisites
-	status.Nomad = CheckNomad()
-	status.Vault = CheckVault()
-	status.Container = CheckContainer()
-	status.Tailscale = CheckTailscale()
+// GetStatus checks every registered prerequisite (see registry.go for the
+// dependency DAG the scheduler walks) and returns their current status.
+func GetStatus() PrereqStatus {
+	return GetStatusStreaming(nil)
+}
 
-	return status
+// GetStatusStreaming behaves like GetStatus, additionally emitting a
+// ProgressEvent on events (if non-nil) as each registered prerequisite
+// starts and finishes checking, so a TUI/web UI can render a live
+// progress tree instead of waiting for every check to finish at once.
+// events is closed once every prerequisite has reported its final status.
+func GetStatusStreaming(events chan<- ProgressEvent) PrereqStatus {
+	results := runChecks(events)
+	applyPackageManagerGate(results)
+	return buildPrereqStatus(results)
 }
 
-// NeedsSetup returns true if any prerequisite is not installed.
+// NeedsSetup returns true if any non-informational prerequisite is not installed.
 func NeedsSetup(s PrereqStatus) bool {
-	return s.Homebrew.Status != Installed ||
-		s.Nomad.Status != Installed ||
-		s.Vault.Status != Installed ||
-		s.Container.Status != Installed ||
-		s.Tailscale.Status != Installed
+	for _, p := range s.AllPrereqs() {
+		if p.Status != Installed {
+			return true
+		}
+	}
+	return false
 }
 
 // AllPrereqs returns all prerequisites as a slice for iteration.
 func (s PrereqStatus) AllPrereqs() []Prerequisite {
-	return []Prerequisite{
-		s.Homebrew,
-		s.Nomad,
-		s.Vault,
-		s.Container,
-		s.Tailscale,
-	}
+	return s.all
 }
 
 // MissingPrereqs returns only the prerequisites that need installation.
@@ -0,0 +1,192 @@
+// Package shutdown coordinates orderly process exits. Coordinator handles
+// the running-daemon case: Nomad node drain, the bootstrap server, and any
+// live driver task handles, so `styx bootstrap-server` (and, eventually,
+// the Nomad driver plugin) stop in the same order instead of each owning
+// its own ad-hoc SIGINT handler. RollbackCoordinator handles the
+// multi-step bring-up/teardown case: `styx init` and `styx uninstall`
+// register a compensating action as each step succeeds, so a SIGINT
+// partway through undoes what ran so far instead of leaving the box
+// half-installed.
+package shutdown
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// NodeDrainer marks a Nomad client node ineligible and drains its
+// allocations, e.g. api.Client.DrainNode.
+type NodeDrainer interface {
+	DrainNode(nodeID string, enable bool) error
+}
+
+// Server is the subset of bootstrap.Server's lifecycle the Coordinator
+// needs: stop accepting new connections while letting in-flight requests
+// (e.g. a client mid-download of a bootstrap file) finish within deadline.
+type Server interface {
+	StopGraceful(deadline time.Duration) error
+}
+
+// Task is a long-running unit the Coordinator signals to stop and waits
+// on - the Nomad driver's taskHandle, in practice.
+type Task interface {
+	Shutdown()
+	Done() <-chan struct{}
+}
+
+// Reporter receives one progress line per shutdown phase. Report is a
+// no-op if unset; WithSocket additionally broadcasts every call to any
+// attached Unix socket subscribers.
+type Reporter func(phase, message string)
+
+// Coordinator runs the node-drain -> bootstrap-stop -> task-drain -> flush
+// sequence triggered by SIGINT/SIGTERM, with a second signal during
+// shutdown forcing an immediate return instead of waiting out the rest.
+type Coordinator struct {
+	// NodeID is this node's Nomad node ID, used for the drain phase.
+	// Leave empty to skip draining (e.g. a node with no local Nomad client).
+	NodeID string
+	// Nomad performs the drain. Required if NodeID is set.
+	Nomad NodeDrainer
+	// DrainDeadline bounds both the Nomad drain and the bootstrap server's
+	// graceful stop, from --shutdown-timeout.
+	DrainDeadline time.Duration
+	// DrainPoll, if set, is called roughly once a second during the node
+	// drain wait to check how many allocations are still running there, so
+	// the phase can move on as soon as the node is empty instead of always
+	// sleeping out the full DrainDeadline. Leave nil to just sleep.
+	DrainPoll func() (remaining int, err error)
+
+	// Bootstrap is stopped after the node finishes draining. Nil skips
+	// this phase.
+	Bootstrap Server
+
+	// Tasks are signaled to Shutdown and waited on, in order, after
+	// Bootstrap stops.
+	Tasks []Task
+
+	// Report, if set, is called once per phase with a short progress
+	// message suitable for stdout.
+	Report Reporter
+
+	socket *socketBroadcaster
+}
+
+// WithSocket additionally broadcasts every Report call as a JSON line over
+// a Unix socket at path, so a `styx status` TUI attached to it can render a
+// live drain view instead of a hung terminal. Best-effort: a failure to
+// listen just means no socket subscribers, not a failed shutdown.
+func (c *Coordinator) WithSocket(path string) *Coordinator {
+	b, err := newSocketBroadcaster(path)
+	if err != nil {
+		c.report("init", fmt.Sprintf("diagnostic socket disabled: %v", err))
+		return c
+	}
+	c.socket = b
+	return c
+}
+
+// Run blocks until SIGINT/SIGTERM, then executes the shutdown phases in
+// order. A second SIGINT/SIGTERM received while a phase is waiting (node
+// drain or task drain) skips the rest of that wait and returns immediately,
+// for an operator who's already waited long enough.
+func (c *Coordinator) Run() error {
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	<-sigCh
+	c.report("shutdown", "signal received, draining...")
+
+	force := make(chan struct{})
+	go func() {
+		<-sigCh
+		close(force)
+	}()
+
+	return c.run(force)
+}
+
+func (c *Coordinator) run(force <-chan struct{}) error {
+	defer func() {
+		if c.socket != nil {
+			c.socket.Close()
+		}
+	}()
+
+	if c.NodeID != "" && c.Nomad != nil {
+		c.report("drain-node", fmt.Sprintf("marking node %s ineligible and draining", c.NodeID))
+		if err := c.Nomad.DrainNode(c.NodeID, true); err != nil {
+			c.report("drain-node", fmt.Sprintf("failed to drain: %v", err))
+		} else {
+			c.waitForDrain(force)
+			c.report("drain-node", "drained")
+		}
+	}
+
+	if c.Bootstrap != nil {
+		c.report("bootstrap-server", "stopping, allowing in-flight requests to finish")
+		if err := c.Bootstrap.StopGraceful(c.DrainDeadline); err != nil {
+			c.report("bootstrap-server", fmt.Sprintf("stop error: %v", err))
+		} else {
+			c.report("bootstrap-server", "stopped")
+		}
+	}
+
+	for i, t := range c.Tasks {
+		c.report("tasks", fmt.Sprintf("signaling task %d/%d to shut down", i+1, len(c.Tasks)))
+		t.Shutdown()
+		select {
+		case <-t.Done():
+		case <-force:
+			c.report("tasks", "forced: not waiting for remaining tasks")
+			return nil
+		}
+	}
+
+	c.report("flush", "shutdown complete")
+	return nil
+}
+
+// waitForDrain waits up to DrainDeadline for the node to finish draining.
+// With DrainPoll set, it checks roughly once a second and returns as soon
+// as no allocations remain instead of always sleeping the full deadline;
+// without it, it just sleeps. Either way, force ends the wait immediately.
+func (c *Coordinator) waitForDrain(force <-chan struct{}) {
+	if c.DrainPoll == nil {
+		select {
+		case <-time.After(c.DrainDeadline):
+		case <-force:
+		}
+		return
+	}
+
+	deadline := time.Now().Add(c.DrainDeadline)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		remaining, err := c.DrainPoll()
+		if err != nil || remaining == 0 || time.Now().After(deadline) {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-force:
+			return
+		}
+	}
+}
+
+func (c *Coordinator) report(phase, message string) {
+	if c.Report != nil {
+		c.Report(phase, message)
+	}
+	if c.socket != nil {
+		c.socket.broadcast(phase, message)
+	}
+}
@@ -0,0 +1,96 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// RollbackCoordinator accumulates compensating actions as a multi-step
+// install/uninstall flow progresses, then undoes them in LIFO order if
+// the flow fails or is interrupted by SIGINT/SIGTERM - the same
+// BeforeExit-handler idea Coordinator uses for graceful process exit,
+// applied here to partial bring-up/teardown instead of request draining.
+type RollbackCoordinator struct {
+	mu      sync.Mutex
+	actions []func() error
+
+	// Report, if set, is called once per rollback action that fails, plus
+	// once if a signal interrupts the flow.
+	Report Reporter
+
+	sigCh chan os.Signal
+	done  chan struct{}
+}
+
+// NewRollbackCoordinator creates a RollbackCoordinator and starts trapping
+// SIGINT/SIGTERM. Call WatchContext to have a signal cancel the flow's
+// context, and Stop once the flow is done (success or failure) so a later,
+// unrelated signal isn't caught by this instance.
+func NewRollbackCoordinator(report Reporter) *RollbackCoordinator {
+	c := &RollbackCoordinator{
+		Report: report,
+		sigCh:  make(chan os.Signal, 1),
+		done:   make(chan struct{}),
+	}
+	signal.Notify(c.sigCh, syscall.SIGINT, syscall.SIGTERM)
+	return c
+}
+
+// WatchContext cancels cancel the first time SIGINT/SIGTERM arrives, so a
+// step that threads ctx through to exec.CommandContext or an HTTP request
+// unwinds promptly instead of running to completion after the operator has
+// already asked to stop. The caller is still responsible for noticing
+// ctx.Err() and calling Rollback once its in-flight step returns.
+func (c *RollbackCoordinator) WatchContext(cancel context.CancelFunc) {
+	go func() {
+		select {
+		case <-c.sigCh:
+			c.report("interrupted", "signal received, rolling back...")
+			cancel()
+		case <-c.done:
+		}
+	}()
+}
+
+// OnRollback registers a compensating action for a step that just
+// succeeded. Actions run in LIFO order on Rollback - last registered,
+// first undone - mirroring how a later step's state depends on an earlier
+// one's.
+func (c *RollbackCoordinator) OnRollback(action func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.actions = append(c.actions, action)
+}
+
+// Rollback runs every registered action in LIFO order, best-effort: a
+// failing action is reported but doesn't stop the rest from running. Safe
+// to call more than once; actions already run aren't repeated.
+func (c *RollbackCoordinator) Rollback() {
+	c.mu.Lock()
+	actions := c.actions
+	c.actions = nil
+	c.mu.Unlock()
+
+	for i := len(actions) - 1; i >= 0; i-- {
+		if err := actions[i](); err != nil {
+			c.report("rollback", fmt.Sprintf("step %d: %v", i, err))
+		}
+	}
+}
+
+// Stop stops watching for signals. Call it once the flow finishes,
+// whether or not anything was rolled back.
+func (c *RollbackCoordinator) Stop() {
+	signal.Stop(c.sigCh)
+	close(c.done)
+}
+
+func (c *RollbackCoordinator) report(phase, message string) {
+	if c.Report != nil {
+		c.Report(phase, message)
+	}
+}
@@ -0,0 +1,95 @@
+package shutdown
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"sync"
+)
+
+// progressEvent is one line of shutdown progress, broadcast to every
+// subscriber as a JSON line.
+type progressEvent struct {
+	Phase   string `json:"phase"`
+	Message string `json:"message"`
+}
+
+// socketBroadcaster listens on a Unix socket and pushes every reported
+// shutdown event, as a JSON line, to each connected subscriber - mirroring
+// bootstrap.Server's /bootstrap/servers/watch subs map, but over a raw Unix
+// socket instead of SSE since this is a local-only, same-host attach point.
+type socketBroadcaster struct {
+	listener net.Listener
+
+	mu   sync.Mutex
+	subs map[chan []byte]struct{}
+}
+
+// newSocketBroadcaster removes any stale socket file at path (left behind
+// by a prior crash) and starts listening.
+func newSocketBroadcaster(path string) (*socketBroadcaster, error) {
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &socketBroadcaster{listener: listener, subs: make(map[chan []byte]struct{})}
+	go b.acceptLoop()
+	return b, nil
+}
+
+func (b *socketBroadcaster) acceptLoop() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.serve(conn)
+	}
+}
+
+func (b *socketBroadcaster) serve(conn net.Conn) {
+	defer conn.Close()
+
+	ch := make(chan []byte, 16)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+	}()
+
+	for data := range ch {
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			return
+		}
+	}
+}
+
+func (b *socketBroadcaster) broadcast(phase, message string) {
+	data, err := json.Marshal(progressEvent{Phase: phase, Message: message})
+	if err != nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- data:
+		default:
+			// Slow subscriber - drop the update, it'll get the next one.
+		}
+	}
+}
+
+// Close stops accepting new subscribers and removes the socket file.
+func (b *socketBroadcaster) Close() error {
+	err := b.listener.Close()
+	os.Remove(b.listener.Addr().String())
+	return err
+}
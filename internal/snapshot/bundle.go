@@ -0,0 +1,124 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BundleOptions describes the artifacts Bundle packs into a single
+// tarball, and the cluster metadata it records in the Manifest.
+type BundleOptions struct {
+	NomadSnapshotPath  string // required
+	ConsulSnapshotPath string // optional: "" if Consul isn't deployed
+	VaultSnapshotPath  string // required
+	ConfigDir          string // directory holding nomad.hcl/vault.hcl/consul.hcl
+	ConsulAddr         string // for the gossip key fingerprint; "" skips it
+	NodeID             string
+	Dest               string // path to write the bundle tarball to, e.g. backup.tar.gz
+}
+
+// configFiles are the rendered HCL configs Bundle packs alongside the
+// Raft snapshots, so Restore can bring a node back with the exact config
+// it was running under. Missing files (e.g. consul.hcl on a cluster that
+// doesn't run Consul) are skipped rather than failing the bundle.
+var configFiles = []string{"nomad.hcl", "vault.hcl", "consul.hcl"}
+
+// Bundle tars the Nomad/Consul/Vault snapshots plus the rendered configs
+// in opts.ConfigDir into a single gzip'd archive at opts.Dest, alongside a
+// manifest.json recording each member's sha256 digest, the binary
+// versions, the node ID, and (if opts.ConsulAddr is set) the gossip key's
+// fingerprint. Returns the manifest it wrote.
+func Bundle(opts BundleOptions) (*Manifest, error) {
+	manifest := &Manifest{
+		CreatedAt:    time.Now(),
+		NodeID:       opts.NodeID,
+		NomadVersion: binaryVersion("nomad"),
+		VaultVersion: binaryVersion("vault"),
+		Files:        map[string]string{},
+	}
+	if opts.ConsulAddr != "" {
+		manifest.GossipFingerprint = gossipKeyFingerprint(opts.ConsulAddr)
+	}
+
+	members := map[string]string{
+		"nomad.snapshot": opts.NomadSnapshotPath,
+		"vault.snapshot": opts.VaultSnapshotPath,
+	}
+	if opts.ConsulSnapshotPath != "" {
+		members["consul.snapshot"] = opts.ConsulSnapshotPath
+	}
+	for _, name := range configFiles {
+		path := filepath.Join(opts.ConfigDir, name)
+		if _, err := os.Stat(path); err == nil {
+			members["config/"+name] = path
+		}
+	}
+
+	for name, path := range members {
+		digest, err := sha256File(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", name, err)
+		}
+		manifest.Files[name] = digest
+	}
+
+	out, err := os.Create(opts.Dest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle %s: %w", opts.Dest, err)
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	defer gw.Close()
+	tw := tar.NewWriter(gw)
+	defer tw.Close()
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := writeTarMember(tw, manifestFile, manifestJSON); err != nil {
+		return nil, err
+	}
+
+	for name, path := range members {
+		if err := writeTarFile(tw, name, path); err != nil {
+			return nil, fmt.Errorf("failed to add %s to bundle: %w", name, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+func writeTarMember(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(data)), Mode: 0600}); err != nil {
+		return fmt.Errorf("failed to write tar header for %s: %w", name, err)
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func writeTarFile(tw *tar.Writer, name, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := tw.WriteHeader(&tar.Header{Name: name, Size: info.Size(), Mode: 0600}); err != nil {
+		return fmt.Errorf("failed to write tar header for %s: %w", name, err)
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
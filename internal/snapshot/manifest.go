@@ -0,0 +1,80 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const manifestFile = "manifest.json"
+
+// Manifest describes the contents of a bundle produced by Bundle, so
+// Restore can validate it before touching any data directory.
+type Manifest struct {
+	CreatedAt         time.Time         `json:"created_at"`
+	NodeID            string            `json:"node_id"`
+	NomadVersion      string            `json:"nomad_version"`
+	VaultVersion      string            `json:"vault_version"`
+	GossipFingerprint string            `json:"gossip_fingerprint,omitempty"`
+	Files             map[string]string `json:"files"` // archive member name -> sha256 hex digest
+}
+
+// sha256File returns the hex-encoded sha256 digest of path's contents, for
+// recording each bundled artifact's integrity in the Manifest.
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// gossipKeyFingerprint fetches the gossip encryption key consul-template
+// resolves from Consul KV at styx/gossip-key (see
+// GenerateConsulServerConfigLive in internal/config) and returns the first
+// 16 hex characters of its sha256 digest. Returns "" if consulAddr isn't
+// reachable or the key hasn't been set, which is the common case today
+// since no styx command deploys Consul yet.
+func gossipKeyFingerprint(consulAddr string) string {
+	httpClient := &http.Client{Timeout: 2 * time.Second}
+	resp, err := httpClient.Get(consulAddr + "/v1/kv/styx/gossip-key?raw")
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	key, err := io.ReadAll(resp.Body)
+	if err != nil || len(key) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// binaryVersion shells out to `name version` and returns the first line of
+// its output, e.g. "Nomad v1.7.2". Returns "unknown" if name isn't on PATH
+// or the command fails.
+func binaryVersion(name string) string {
+	output, err := exec.Command(name, "version").Output()
+	if err != nil {
+		return "unknown"
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return line
+}
@@ -0,0 +1,134 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/kessler-frost/styx/internal/launchd"
+)
+
+// RestoreOptions describes where Restore should unpack a bundle's
+// artifacts, and how to stop/restart the service around the swap.
+type RestoreOptions struct {
+	ConfigDir    string // overwritten with the bundle's config/* files
+	NomadDataDir string // Nomad's Raft snapshot is restored here as restore.snap
+	VaultDataDir string // Vault's Raft snapshot is restored here as restore.snap
+	PlistPath    string // com.styx.nomad.plist, passed to launchd.Unload/Load
+}
+
+// Restore validates the manifest inside the bundle at path (every member's
+// sha256 digest must match what Bundle recorded), stops the styx service
+// via launchd, replaces ConfigDir/NomadDataDir/VaultDataDir's snapshot
+// inputs with the bundle's contents, and restarts the service. The actual
+// Raft restore (nomad operator snapshot restore / vault operator raft
+// snapshot-restore) still has to be run against the respawned agents,
+// since both require a live, unsealed leader to accept it - Restore only
+// gets the on-disk materials and the service back in place for that.
+func Restore(path string, opts RestoreOptions) error {
+	manifest, members, err := readBundle(path)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle %s: %w", path, err)
+	}
+
+	for name, digest := range manifest.Files {
+		data, ok := members[name]
+		if !ok {
+			return fmt.Errorf("bundle manifest references missing member %s", name)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != digest {
+			return fmt.Errorf("bundle member %s failed checksum validation", name)
+		}
+	}
+
+	if opts.PlistPath != "" {
+		if err := launchd.Unload(opts.PlistPath); err != nil {
+			fmt.Printf("Note: failed to unload service before restore: %v\n", err)
+		}
+	}
+
+	if data, ok := members["nomad.snapshot"]; ok {
+		if err := os.WriteFile(filepath.Join(opts.NomadDataDir, "restore.snap"), data, 0600); err != nil {
+			return fmt.Errorf("failed to write nomad restore snapshot: %w", err)
+		}
+	}
+	if data, ok := members["vault.snapshot"]; ok {
+		if err := os.WriteFile(filepath.Join(opts.VaultDataDir, "restore.snap"), data, 0600); err != nil {
+			return fmt.Errorf("failed to write vault restore snapshot: %w", err)
+		}
+	}
+	for name, data := range members {
+		const prefix = "config/"
+		if len(name) > len(prefix) && name[:len(prefix)] == prefix {
+			dest := filepath.Join(opts.ConfigDir, name[len(prefix):])
+			if err := os.WriteFile(dest, data, 0644); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", name, err)
+			}
+		}
+	}
+
+	if opts.PlistPath != "" {
+		if err := launchd.Load(opts.PlistPath); err != nil {
+			return fmt.Errorf("failed to reload service after restore: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readBundle unpacks path into a manifest and a name->contents map of every
+// other archive member.
+func readBundle(path string) (*Manifest, map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	members := map[string][]byte{}
+	var manifest *Manifest
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read tar entry: %w", err)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		if header.Name == manifestFile {
+			var m Manifest
+			if err := json.Unmarshal(data, &m); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+			}
+			manifest = &m
+			continue
+		}
+		members[header.Name] = data
+	}
+
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("bundle is missing %s", manifestFile)
+	}
+	return manifest, members, nil
+}
@@ -0,0 +1,73 @@
+// Package snapshot takes point-in-time backups of a running Styx cluster -
+// Nomad, Consul (when deployed), and Vault's Raft state - and bundles them
+// with the rendered HCL configs into a single tarball a later Restore can
+// apply. It backs `styx upgrade`'s pre-flight snapshot and rollback path.
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kessler-frost/styx/internal/api"
+)
+
+// SnapshotNomad downloads a point-in-time snapshot of Nomad's Raft state
+// via client's /v1/operator/snapshot and writes it to dest.
+func SnapshotNomad(client *api.Client, dest string) error {
+	data, err := client.NomadSnapshot()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot nomad: %w", err)
+	}
+	return os.WriteFile(dest, data, 0600)
+}
+
+// SnapshotConsul downloads a point-in-time snapshot of Consul's Raft state
+// via client's /v1/snapshot and writes it to dest. Consul isn't deployed by
+// any styx command today (see ConsulServerConfig in internal/config), so
+// this only succeeds against a cluster that runs one out of band - callers
+// should treat its failure as non-fatal to the rest of the bundle.
+func SnapshotConsul(client *api.Client, dest string) error {
+	data, err := client.ConsulSnapshot()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot consul: %w", err)
+	}
+	return os.WriteFile(dest, data, 0600)
+}
+
+// SnapshotVault downloads a point-in-time snapshot of Vault's integrated
+// storage via client's /v1/sys/storage/raft/snapshot, authenticating with
+// token, and writes it to dest.
+func SnapshotVault(client *api.Client, token, dest string) error {
+	data, err := client.VaultSnapshot(token)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot vault: %w", err)
+	}
+	return os.WriteFile(dest, data, 0600)
+}
+
+// CreateBundle snapshots Nomad and Vault (and Consul, if reachable) into
+// tmpDir and bundles them with the rendered configs in opts.ConfigDir into
+// opts.Dest, returning the resulting Manifest. It's the shared core of
+// `styx snapshot create` and the pre-flight snapshot `styx upgrade` takes
+// before touching anything.
+func CreateBundle(client *api.Client, vaultToken, tmpDir string, opts BundleOptions) (*Manifest, error) {
+	opts.NomadSnapshotPath = filepath.Join(tmpDir, "nomad.snapshot")
+	if err := SnapshotNomad(client, opts.NomadSnapshotPath); err != nil {
+		return nil, err
+	}
+
+	opts.VaultSnapshotPath = filepath.Join(tmpDir, "vault.snapshot")
+	if err := SnapshotVault(client, vaultToken, opts.VaultSnapshotPath); err != nil {
+		return nil, err
+	}
+
+	consulPath := filepath.Join(tmpDir, "consul.snapshot")
+	if err := SnapshotConsul(client, consulPath); err != nil {
+		opts.ConsulSnapshotPath = ""
+	} else {
+		opts.ConsulSnapshotPath = consulPath
+	}
+
+	return Bundle(opts)
+}
@@ -0,0 +1,141 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/launchd"
+)
+
+// maxLogBytes is the rotation threshold for a managed process's log file,
+// matching launchd.RotateLogIfNeeded's own default.
+const maxLogBytes = 10 * 1024 * 1024 // 10 MiB
+
+// ManagedProcess describes a child process the Supervisor starts, restarts
+// on crash, and streams output from into its own rotating log file.
+type ManagedProcess struct {
+	// Name identifies the process in status output and log file names
+	// (e.g. "vault", "nomad", "template-watch").
+	Name string
+	// Path is the executable to run, typically resolved with
+	// exec.LookPath before building the ManagedProcess.
+	Path string
+	// Args are passed to Path.
+	Args []string
+	// Env is appended to the current process's environment. Nil means
+	// the child inherits the supervisor's environment unchanged.
+	Env []string
+}
+
+// State is a point-in-time snapshot of a managed process, returned by
+// Supervisor.Status and served over /supervisor/status.
+type State struct {
+	Name      string    `json:"name"`
+	PID       int       `json:"pid,omitempty"`
+	Running   bool      `json:"running"`
+	Restarts  int       `json:"restarts"`
+	LastError string    `json:"last_error,omitempty"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+}
+
+// runningProcess tracks the live cmd and state for one ManagedProcess
+// across restarts, guarded by mu since Status is read concurrently with
+// the supervise loop's restarts.
+type runningProcess struct {
+	spec ManagedProcess
+
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	state State
+}
+
+func newRunningProcess(spec ManagedProcess) *runningProcess {
+	return &runningProcess{spec: spec, state: State{Name: spec.Name}}
+}
+
+// start launches the process, directing its stdout/stderr to logDir/<name>.log
+// (rotated via launchd.RotateLogIfNeeded before each start).
+func (p *runningProcess) start(logDir string) error {
+	logPath := filepath.Join(logDir, p.spec.Name+".log")
+	if err := launchd.RotateLogIfNeeded(logPath, maxLogBytes); err != nil {
+		fmt.Printf("supervisor: warning: failed to rotate %s log: %v\n", p.spec.Name, err)
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file for %s: %w", p.spec.Name, err)
+	}
+
+	cmd := exec.Command(p.spec.Path, p.spec.Args...)
+	if len(p.spec.Env) > 0 {
+		cmd.Env = append(os.Environ(), p.spec.Env...)
+	}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return fmt.Errorf("failed to start %s: %w", p.spec.Name, err)
+	}
+
+	p.mu.Lock()
+	p.cmd = cmd
+	p.state.PID = cmd.Process.Pid
+	p.state.Running = true
+	p.state.StartedAt = time.Now()
+	p.state.LastError = ""
+	p.mu.Unlock()
+
+	// The log file is only needed by the child's inherited fd; close our
+	// copy once the process has it.
+	go func() {
+		cmd.Wait()
+		logFile.Close()
+	}()
+
+	return nil
+}
+
+// signal forwards sig to the process, if it's running.
+func (p *runningProcess) signal(sig os.Signal) {
+	p.mu.Lock()
+	cmd := p.cmd
+	p.mu.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	cmd.Process.Signal(sig)
+}
+
+// wait blocks until the process exits and reports the result.
+func (p *runningProcess) wait() error {
+	p.mu.Lock()
+	cmd := p.cmd
+	p.mu.Unlock()
+
+	if cmd == nil {
+		return fmt.Errorf("%s was never started", p.spec.Name)
+	}
+	return cmd.Wait()
+}
+
+func (p *runningProcess) markExited(err error, restarts int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state.Running = false
+	p.state.Restarts = restarts
+	if err != nil {
+		p.state.LastError = err.Error()
+	}
+}
+
+func (p *runningProcess) snapshot() State {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state
+}
@@ -0,0 +1,78 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// StatusAddr is the loopback address `styx supervise` listens on and
+// `styx status` queries, mirroring how bootstrap.Port fixes the bootstrap
+// server's port.
+const StatusAddr = "127.0.0.1:19998"
+
+// StatusServer serves the Supervisor's current state at /supervisor/status
+// so other commands (and operators) have a real source of truth instead of
+// shelling out to check for a PID file.
+type StatusServer struct {
+	sup      *Supervisor
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewStatusServer binds StatusAddr and prepares to serve sup's status.
+func NewStatusServer(sup *Supervisor) (*StatusServer, error) {
+	listener, err := net.Listen("tcp", StatusAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", StatusAddr, err)
+	}
+
+	s := &StatusServer{sup: sup, listener: listener}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/supervisor/status", s.serveStatus)
+	s.server = &http.Server{
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}
+	return s, nil
+}
+
+// Start serves in the background until Stop is called.
+func (s *StatusServer) Start() {
+	go s.server.Serve(s.listener)
+}
+
+// Stop gracefully shuts the server down.
+func (s *StatusServer) Stop() error {
+	return s.server.Close()
+}
+
+func (s *StatusServer) serveStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.sup.Status())
+}
+
+// FetchStatus queries a running `styx supervise`'s status endpoint, for use
+// by `styx status`. It returns an error if the supervisor isn't reachable,
+// e.g. because the node still runs the legacy wrapper script.
+func FetchStatus() (*Status, error) {
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get("http://" + StatusAddr + "/supervisor/status")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("supervisor status endpoint returned %d", resp.StatusCode)
+	}
+
+	var st Status
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return nil, fmt.Errorf("failed to decode supervisor status: %w", err)
+	}
+	return &st, nil
+}
@@ -0,0 +1,193 @@
+// Package supervisor replaces the generated styx-agent.sh wrapper script
+// with a native Go process group: it starts Vault/Nomad (and any helper
+// processes) as children, restarts them on crash with exponential backoff,
+// forwards SIGTERM/SIGINT for graceful shutdown, and reports their status
+// over HTTP so `styx status` has a real source of truth instead of parsing
+// launchd state.
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// minBackoff and maxBackoff bound the restart delay after a process
+// crashes, doubling each consecutive crash the way bootstrap.WatchServers
+// and `styx logs` back off a dropped stream.
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+
+	// stableUptime is how long a process has to stay up before a
+	// subsequent crash resets its backoff back to minBackoff, so a
+	// process that's been healthy for a while doesn't inherit a long
+	// delay from an unrelated crash hours earlier.
+	stableUptime = time.Minute
+)
+
+// UnsealFunc unseals Vault if it's sealed; see internal/vault.Unsealer.
+type UnsealFunc func() error
+
+// Supervisor starts a group of ManagedProcesses, restarts any that crash,
+// and optionally keeps Vault unsealed across restarts.
+type Supervisor struct {
+	logDir string
+
+	mu        sync.Mutex
+	processes []*runningProcess
+
+	// unseal is called periodically once set via WithAutoUnseal. It's
+	// nil on client nodes, which don't run Vault.
+	unseal        UnsealFunc
+	unsealEvery   time.Duration
+	unsealLastErr string
+}
+
+// New creates a Supervisor that logs each process to logDir/<name>.log.
+func New(logDir string) *Supervisor {
+	return &Supervisor{logDir: logDir}
+}
+
+// Add registers a process to be started and supervised by Run. Call this
+// before Run; adding processes after Run has started is not supported.
+func (s *Supervisor) Add(spec ManagedProcess) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processes = append(s.processes, newRunningProcess(spec))
+}
+
+// WithAutoUnseal arms a periodic check that calls unseal every interval,
+// recording any failure in Status. Use this on server nodes to replace the
+// wrapper script's curl/python unseal dance.
+func (s *Supervisor) WithAutoUnseal(unseal UnsealFunc, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unseal = unseal
+	s.unsealEvery = interval
+}
+
+// Run starts every registered process and supervises them until ctx is
+// cancelled, at which point it sends SIGTERM to each child, waits for them
+// to exit, and returns. It only returns early (before ctx is cancelled) if
+// a process fails to start in the first place.
+func (s *Supervisor) Run(ctx context.Context) error {
+	s.mu.Lock()
+	processes := append([]*runningProcess(nil), s.processes...)
+	unseal, unsealEvery := s.unseal, s.unsealEvery
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, p := range processes {
+		if err := p.start(s.logDir); err != nil {
+			return err
+		}
+		wg.Add(1)
+		go func(p *runningProcess) {
+			defer wg.Done()
+			s.superviseOne(ctx, p)
+		}(p)
+	}
+
+	if unseal != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runAutoUnseal(ctx, unseal, unsealEvery)
+		}()
+	}
+
+	<-ctx.Done()
+
+	for _, p := range processes {
+		p.signal(os.Interrupt)
+	}
+	wg.Wait()
+	return nil
+}
+
+// superviseOne waits for p to exit and restarts it with exponential
+// backoff until ctx is cancelled.
+func (s *Supervisor) superviseOne(ctx context.Context, p *runningProcess) {
+	backoff := minBackoff
+	restarts := 0
+
+	for {
+		startedAt := time.Now()
+		err := p.wait()
+		p.markExited(err, restarts)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(startedAt) >= stableUptime {
+			backoff = minBackoff
+		}
+
+		fmt.Printf("supervisor: %s exited (%v), restarting in %s\n", p.spec.Name, err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		restarts++
+		if startErr := p.start(s.logDir); startErr != nil {
+			fmt.Printf("supervisor: failed to restart %s: %v\n", p.spec.Name, startErr)
+			p.markExited(startErr, restarts)
+		}
+
+		if backoff < maxBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+func (s *Supervisor) runAutoUnseal(ctx context.Context, unseal UnsealFunc, interval time.Duration) {
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := unseal()
+			s.mu.Lock()
+			if err != nil {
+				s.unsealLastErr = err.Error()
+			} else {
+				s.unsealLastErr = ""
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Status returns a snapshot of every managed process, plus the last
+// auto-unseal error (if any), for /supervisor/status.
+func (s *Supervisor) Status() Status {
+	s.mu.Lock()
+	processes := append([]*runningProcess(nil), s.processes...)
+	unsealErr := s.unsealLastErr
+	s.mu.Unlock()
+
+	st := Status{UnsealError: unsealErr}
+	for _, p := range processes {
+		st.Processes = append(st.Processes, p.snapshot())
+	}
+	return st
+}
+
+// Status is the JSON shape served at /supervisor/status.
+type Status struct {
+	Processes   []State `json:"processes"`
+	UnsealError string  `json:"unseal_error,omitempty"`
+}
@@ -0,0 +1,170 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDoNotRestart is a sentinel a Service.Serve can wrap (with fmt.Errorf's
+// %w or errors.Join) to tell a Tree the failure is permanent: instead of
+// restarting the service with backoff, Tree.Serve stops everything else (in
+// reverse order) and returns the error to its own caller.
+var ErrDoNotRestart = errors.New("supervisor: do not restart")
+
+// Service is a long-running goroutine a Tree supervises, in the spirit of
+// suture v4's Service interface. bootstrap.Server and driver/handle.go's
+// taskHandle implement it.
+type Service interface {
+	// Serve runs the service until ctx is cancelled, in which case it must
+	// return promptly (nil or ctx.Err() are both treated as a clean stop).
+	// Any other error is treated as a crash: Tree restarts the service
+	// after a backoff, unless the error wraps ErrDoNotRestart.
+	Serve(ctx context.Context) error
+}
+
+// ServiceFunc adapts a plain func(ctx) error to a Service, the way
+// http.HandlerFunc adapts a function to http.Handler.
+type ServiceFunc func(ctx context.Context) error
+
+// Serve calls f(ctx).
+func (f ServiceFunc) Serve(ctx context.Context) error { return f(ctx) }
+
+// TreeBackoff bounds the delay a Tree waits before restarting a crashed
+// Service, doubling after each consecutive crash. The zero value uses the
+// same 1s/30s/1m defaults as the process Supervisor's restarts.
+type TreeBackoff struct {
+	Min   time.Duration
+	Max   time.Duration
+	Reset time.Duration // a Service up this long resets its backoff to Min
+}
+
+func (b TreeBackoff) withDefaults() TreeBackoff {
+	if b.Min <= 0 {
+		b.Min = minBackoff
+	}
+	if b.Max <= 0 {
+		b.Max = maxBackoff
+	}
+	if b.Reset <= 0 {
+		b.Reset = stableUptime
+	}
+	return b
+}
+
+// Tree supervises a set of named Services under one context tree: cancelling
+// the ctx passed to Serve cancels every child, a child that returns a
+// transient error is restarted with exponential backoff, and a permanent
+// error (wrapping ErrDoNotRestart) tears the whole Tree down and bubbles up.
+// Children are stopped in reverse order of Add, so a service can rely on
+// ones added before it staying up until it's gone. Tree itself implements
+// Service, so Trees can nest.
+type Tree struct {
+	backoff TreeBackoff
+
+	mu      sync.Mutex
+	entries []*treeEntry
+}
+
+type treeEntry struct {
+	name    string
+	service Service
+}
+
+// NewTree creates a Tree with the given restart backoff.
+func NewTree(backoff TreeBackoff) *Tree {
+	return &Tree{backoff: backoff.withDefaults()}
+}
+
+// Add registers a named Service to be started by Serve. Call this before
+// Serve; adding services afterwards is not supported.
+func (t *Tree) Add(name string, service Service) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, &treeEntry{name: name, service: service})
+}
+
+// Serve starts every registered Service and supervises them until ctx is
+// cancelled or one of them fails permanently, then stops the rest in reverse
+// order of Add and returns the permanent error, if any.
+func (t *Tree) Serve(ctx context.Context) error {
+	t.mu.Lock()
+	entries := append([]*treeEntry(nil), t.entries...)
+	t.mu.Unlock()
+
+	type child struct {
+		entry  *treeEntry
+		cancel context.CancelFunc
+		done   chan struct{}
+	}
+	children := make([]*child, len(entries))
+	permanentErrCh := make(chan error, 1)
+
+	for i, e := range entries {
+		childCtx, cancel := context.WithCancel(context.Background())
+		c := &child{entry: e, cancel: cancel, done: make(chan struct{})}
+		children[i] = c
+
+		go func(c *child, childCtx context.Context) {
+			defer close(c.done)
+			if err := t.superviseOne(childCtx, c.entry); err != nil {
+				select {
+				case permanentErrCh <- fmt.Errorf("%s: %w", c.entry.name, err):
+				default:
+				}
+			}
+		}(c, childCtx)
+	}
+
+	var treeErr error
+	select {
+	case <-ctx.Done():
+	case treeErr = <-permanentErrCh:
+	}
+
+	for i := len(children) - 1; i >= 0; i-- {
+		children[i].cancel()
+		<-children[i].done
+	}
+
+	return treeErr
+}
+
+// superviseOne runs entry's Service until ctx is cancelled, restarting it
+// with exponential backoff after each crash. It returns nil on a clean stop
+// (ctx cancelled or Serve returned nil) and the unwrapped error once a
+// Service fails with ErrDoNotRestart.
+func (t *Tree) superviseOne(ctx context.Context, e *treeEntry) error {
+	backoff := t.backoff.Min
+
+	for {
+		startedAt := time.Now()
+		err := e.service.Serve(ctx)
+
+		if ctx.Err() != nil || err == nil {
+			return nil
+		}
+
+		if errors.Is(err, ErrDoNotRestart) {
+			fmt.Printf("supervisor: %s stopped permanently: %v\n", e.name, err)
+			return err
+		}
+
+		if time.Since(startedAt) >= t.backoff.Reset {
+			backoff = t.backoff.Min
+		}
+		fmt.Printf("supervisor: %s exited (%v), restarting in %s\n", e.name, err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		if backoff < t.backoff.Max {
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package tailserve
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"tailscale.com/ipn"
+	"tailscale.com/tailcfg"
+)
+
+// FunnelLabel is the container label a job opts in with to be publicly
+// routed over Tailscale Funnel, read from the job's
+// container.Configuration.Labels (see JobFunnelEnabled). Jobs without it
+// are only reachable over the tailnet, via Enable's Serve config.
+const FunnelLabel = "styx.funnel"
+
+// funnelCapability is the tailnet ACL node attribute required to program
+// Funnel; see https://tailscale.com/kb/1223/funnel.
+const funnelCapability tailcfg.NodeCapability = "funnel"
+
+// funnelPorts are the only ports Tailscale Funnel can expose publicly.
+var funnelPorts = map[int]bool{443: true, 8443: true, 10000: true}
+
+// JobFunnelEnabled reports whether a job's container labels opt it into
+// Tailscale Funnel (FunnelLabel set to "true").
+func JobFunnelEnabled(labels map[string]string) bool {
+	return labels[FunnelLabel] == "true"
+}
+
+// EnableFunnel programs Tailscale Funnel to publicly expose port on
+// hostname (the tailnet DNS name if empty), in addition to any tailnet-only
+// Serve config from Enable. port must be one Tailscale Funnel supports:
+// 443, 8443, or 10000. Returns a clear error if the node's tailnet ACL
+// doesn't grant the "funnel" node attribute.
+func EnableFunnel(port int, hostname string) error {
+	if !funnelPorts[port] {
+		return fmt.Errorf("tailscale funnel only supports ports 443, 8443, and 10000, not %d", port)
+	}
+
+	ctx := context.Background()
+
+	status, err := lc.StatusWithoutPeers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read tailscale status: %w", err)
+	}
+	if status.Self == nil {
+		return fmt.Errorf("tailscale is not connected")
+	}
+	if !hasCapability(status.Self.Capabilities, funnelCapability) {
+		return fmt.Errorf("this node's tailnet ACL doesn't grant the %q node attribute; see https://tailscale.com/kb/1223/funnel", funnelCapability)
+	}
+
+	if hostname == "" {
+		hostname = strings.TrimSuffix(status.Self.DNSName, ".")
+	}
+	hp := ipn.HostPort(fmt.Sprintf("%s:%d", hostname, port))
+
+	cfg, err := lc.GetServeConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read tailscale serve config: %w", err)
+	}
+	if cfg == nil {
+		cfg = &ipn.ServeConfig{}
+	}
+
+	if cfg.Web == nil {
+		cfg.Web = make(map[ipn.HostPort]*ipn.WebServerConfig)
+	}
+	cfg.Web[hp] = &ipn.WebServerConfig{
+		Handlers: map[string]*ipn.HTTPHandler{
+			"/": {Proxy: "http://localhost:" + traefikPort},
+		},
+	}
+
+	if cfg.AllowFunnel == nil {
+		cfg.AllowFunnel = make(map[ipn.HostPort]bool)
+	}
+	cfg.AllowFunnel[hp] = true
+
+	if err := lc.SetServeConfig(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to set tailscale funnel config: %w", err)
+	}
+	return nil
+}
+
+// hasCapability reports whether capabilities includes want.
+func hasCapability(capabilities []tailcfg.NodeCapability, want tailcfg.NodeCapability) bool {
+	for _, c := range capabilities {
+		if c == want {
+			return true
+		}
+	}
+	return false
+}
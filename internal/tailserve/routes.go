@@ -0,0 +1,151 @@
+package tailserve
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"tailscale.com/ipn"
+)
+
+// Route describes one tailnet ingress point to program via ConfigureServe.
+// Web routes (Backend "http://" or "https://") are mounted onto a shared
+// HTTPS:443 listener per Host, with PathPrefix choosing where under it they
+// live; TCP routes (Backend "tcp://") get their own listener on the port
+// given in Backend and bypass Traefik entirely.
+type Route struct {
+	// Host is the tailnet MagicDNS name to serve on. Empty means the
+	// node's own DNSName, matching Enable's behavior.
+	Host string
+
+	// PathPrefix is the HTTP mount point for a Web route, e.g. "/" or
+	// "/grafana". Ignored for TCP routes.
+	PathPrefix string
+
+	// Backend is where traffic is forwarded: "http://host:port",
+	// "https://host:port", or "tcp://host:port".
+	Backend string
+
+	// TLSTerminationHost, for a TCP route, has Tailscale terminate TLS
+	// before forwarding rather than passing the raw TCP stream through,
+	// permitting only this SNI hostname. Empty means pass the raw TCP
+	// stream through untouched.
+	TLSTerminationHost string
+}
+
+// ConfigureServe programs Tailscale Serve with the given routes, replacing
+// any Web/TCP entries previously set by ConfigureServe while leaving Funnel
+// opt-ins from EnableFunnel untouched. It diffs the desired config against
+// the current one from LocalAPI and only calls SetServeConfig when they
+// differ, so repeated `styx up` runs with the same routes are no-ops.
+func ConfigureServe(routes []Route) error {
+	ctx := context.Background()
+
+	status, err := lc.StatusWithoutPeers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read tailscale status: %w", err)
+	}
+	if status.Self == nil {
+		return fmt.Errorf("tailscale is not connected")
+	}
+	selfDNSName := strings.TrimSuffix(status.Self.DNSName, ".")
+
+	cur, err := lc.GetServeConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read tailscale serve config: %w", err)
+	}
+	if cur == nil {
+		cur = &ipn.ServeConfig{}
+	}
+
+	desired := &ipn.ServeConfig{AllowFunnel: cur.AllowFunnel}
+	for _, r := range routes {
+		host := r.Host
+		if host == "" {
+			host = selfDNSName
+		}
+
+		scheme, hostPort, err := splitBackend(r.Backend)
+		if err != nil {
+			return fmt.Errorf("route for %q: %w", host, err)
+		}
+
+		switch scheme {
+		case "http", "https":
+			if desired.Web == nil {
+				desired.Web = make(map[ipn.HostPort]*ipn.WebServerConfig)
+			}
+			hp := ipn.HostPort(host + ":443")
+			web, ok := desired.Web[hp]
+			if !ok {
+				web = &ipn.WebServerConfig{Handlers: make(map[string]*ipn.HTTPHandler)}
+				desired.Web[hp] = web
+			}
+			mount := r.PathPrefix
+			if mount == "" {
+				mount = "/"
+			}
+			web.Handlers[mount] = &ipn.HTTPHandler{Proxy: scheme + "://" + hostPort}
+		case "tcp":
+			port, err := portOf(hostPort)
+			if err != nil {
+				return fmt.Errorf("route for %q: %w", host, err)
+			}
+			if desired.TCP == nil {
+				desired.TCP = make(map[uint16]*ipn.TCPPortHandler)
+			}
+			desired.TCP[port] = &ipn.TCPPortHandler{
+				TCPForward:   hostPort,
+				TerminateTLS: r.TLSTerminationHost,
+			}
+		default:
+			return fmt.Errorf("route for %q: unsupported backend scheme %q (want http, https, or tcp)", host, scheme)
+		}
+	}
+
+	if reflect.DeepEqual(cur, desired) {
+		return nil
+	}
+
+	if err := lc.SetServeConfig(ctx, desired); err != nil {
+		return fmt.Errorf("failed to set tailscale serve config: %w", err)
+	}
+	return nil
+}
+
+// splitBackend splits a "scheme://host:port" backend into its scheme and
+// host:port.
+func splitBackend(backend string) (scheme, hostPort string, err error) {
+	u, err := url.Parse(backend)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid backend %q, want scheme://host:port", backend)
+	}
+	return u.Scheme, u.Host, nil
+}
+
+// portOf extracts the numeric port from a "host:port" string, for backends
+// that become a TCP listener keyed by port.
+func portOf(hostPort string) (uint16, error) {
+	_, portStr, err := splitHostPort(hostPort)
+	if err != nil {
+		return 0, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port in %q: %w", hostPort, err)
+	}
+	return uint16(port), nil
+}
+
+// splitHostPort is a thin wrapper so portOf's error messages stay in terms
+// of the original "host:port" string rather than net.SplitHostPort's.
+func splitHostPort(hostPort string) (host, port string, err error) {
+	i := strings.LastIndex(hostPort, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("missing port in %q", hostPort)
+	}
+	return hostPort[:i], hostPort[i+1:], nil
+}
@@ -1,13 +1,31 @@
+// Package tailserve programs Tailscale Serve to terminate HTTPS on :443
+// and forward to Traefik's plaintext listener, so the cluster is reachable
+// over the tailnet without a separate cert (see services.TLSModeTailscale).
 package tailserve
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"tailscale.com/client/local"
+	"tailscale.com/ipn"
 )
 
-// findTailscaleBinary finds the tailscale binary in common locations.
+// traefikPort is the local port Traefik listens on for plaintext HTTP;
+// Tailscale Serve terminates HTTPS on :443 and forwards to it.
+const traefikPort = "4200"
+
+// lc is the LocalAPI client used to read and program Tailscale Serve. Its
+// zero value dials tailscaled over the platform's default local socket, so
+// no explicit setup is needed.
+var lc local.Client
+
+// findTailscaleBinary finds the tailscale binary in common locations, for
+// the CLI fallback used when the LocalAPI socket isn't reachable (e.g. the
+// Mac App Store build, which sandboxes it from other processes).
 func findTailscaleBinary() string {
 	tailscalePaths := []string{
 		"/Applications/Tailscale.app/Contents/MacOS/Tailscale",
@@ -34,26 +52,96 @@ func findTailscaleBinary() string {
 	return ""
 }
 
-// Enable sets up Tailscale Serve to forward HTTPS:443 to Traefik at localhost:4200.
-// This provides automatic TLS termination via Tailscale.
+// Enable sets up Tailscale Serve to forward HTTPS:443 to Traefik at
+// localhost:4200, via the LocalAPI's ServeConfig. This provides automatic
+// TLS termination via Tailscale. Falls back to the `tailscale` CLI if the
+// LocalAPI socket isn't reachable.
 func Enable() error {
+	ctx := context.Background()
+
+	status, err := lc.StatusWithoutPeers(ctx)
+	if err != nil || status.Self == nil {
+		return enableViaCLI()
+	}
+
+	cfg, err := lc.GetServeConfig(ctx)
+	if err != nil {
+		return enableViaCLI()
+	}
+	if cfg == nil {
+		cfg = &ipn.ServeConfig{}
+	}
+
+	dnsName := strings.TrimSuffix(status.Self.DNSName, ".")
+	hp := ipn.HostPort(dnsName + ":443")
+	if cfg.Web == nil {
+		cfg.Web = make(map[ipn.HostPort]*ipn.WebServerConfig)
+	}
+	cfg.Web[hp] = &ipn.WebServerConfig{
+		Handlers: map[string]*ipn.HTTPHandler{
+			"/": {Proxy: "http://localhost:" + traefikPort},
+		},
+	}
+
+	if err := lc.SetServeConfig(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to set tailscale serve config: %w", err)
+	}
+	return nil
+}
+
+// Disable stops Tailscale Serve. Falls back to the `tailscale` CLI if the
+// LocalAPI socket isn't reachable.
+func Disable() error {
+	ctx := context.Background()
+
+	if err := lc.SetServeConfig(ctx, &ipn.ServeConfig{}); err != nil {
+		return disableViaCLI()
+	}
+	return nil
+}
+
+// IngressStatus reports whether Tailscale Serve (tailnet-only) and Funnel
+// (public internet) are each currently programmed.
+type IngressStatus struct {
+	Serving   bool
+	Funneling bool
+}
+
+// Status returns whether Tailscale Serve and Funnel are each currently
+// active. Falls back to the `tailscale` CLI if the LocalAPI socket isn't
+// reachable.
+func Status() (IngressStatus, error) {
+	ctx := context.Background()
+
+	cfg, err := lc.GetServeConfig(ctx)
+	if err != nil {
+		return statusViaCLI()
+	}
+	if cfg == nil {
+		return IngressStatus{}, nil
+	}
+	return IngressStatus{
+		Serving:   len(cfg.Web) > 0 || len(cfg.TCP) > 0,
+		Funneling: len(cfg.AllowFunnel) > 0,
+	}, nil
+}
+
+func enableViaCLI() error {
 	bin := findTailscaleBinary()
 	if bin == "" {
 		return fmt.Errorf("tailscale binary not found")
 	}
 
 	// Run: tailscale serve --bg localhost:4200
-	cmd := exec.Command(bin, "serve", "--bg", "localhost:4200")
+	cmd := exec.Command(bin, "serve", "--bg", "localhost:"+traefikPort)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to enable tailscale serve: %w\nOutput: %s", err, string(output))
 	}
-
 	return nil
 }
 
-// Disable stops Tailscale Serve.
-func Disable() error {
+func disableViaCLI() error {
 	bin := findTailscaleBinary()
 	if bin == "" {
 		return fmt.Errorf("tailscale binary not found")
@@ -65,26 +153,32 @@ func Disable() error {
 	if err != nil {
 		return fmt.Errorf("failed to disable tailscale serve: %w\nOutput: %s", err, string(output))
 	}
-
 	return nil
 }
 
-// Status returns whether Tailscale Serve is currently active.
-func Status() (bool, error) {
+func statusViaCLI() (IngressStatus, error) {
 	bin := findTailscaleBinary()
 	if bin == "" {
-		return false, fmt.Errorf("tailscale binary not found")
+		return IngressStatus{}, fmt.Errorf("tailscale binary not found")
 	}
 
-	// Run: tailscale serve status
-	cmd := exec.Command(bin, "serve", "status")
+	return IngressStatus{
+		Serving:   cliModeActive(bin, "serve", "No web serve"),
+		Funneling: cliModeActive(bin, "funnel", "No funnel"),
+	}, nil
+}
+
+// cliModeActive runs `tailscale <mode> status` and reports whether its
+// output indicates something is being served; emptyText is the phrase
+// that CLI prints when nothing is active.
+func cliModeActive(bin, mode, emptyText string) bool {
+	cmd := exec.Command(bin, mode, "status")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		// "tailscale serve status" exits non-zero when nothing is being served
-		return false, nil
+		// Exits non-zero when nothing is being served.
+		return false
 	}
 
-	// Check if output indicates active serving
 	outputStr := strings.TrimSpace(string(output))
-	return len(outputStr) > 0 && !strings.Contains(outputStr, "No web serve"), nil
+	return len(outputStr) > 0 && !strings.Contains(outputStr, emptyText)
 }
@@ -0,0 +1,161 @@
+// Package template wraps hashicorp/consul-template so Styx's own generated
+// configs (Nomad/Consul/Vault HCL) can pull values that change after
+// install time - Vault tokens, gossip keys, server lists - from Consul KV
+// and Vault instead of baking them in at generate time. It is wired up the
+// same way Nomad's client/consul_template.go wires the library up for task
+// templates.
+package template
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	ctconfig "github.com/hashicorp/consul-template/config"
+	"github.com/hashicorp/consul-template/manager"
+)
+
+// TemplateConfig describes one consul-template managed file: render Source
+// through consul-template into Destination with the given permissions, and
+// run Command (if set) after each successful re-render.
+//
+// LeftDelim/RightDelim override consul-template's default "{{"/"}}"
+// delimiters, for a source template whose own syntax collides with them
+// (e.g. a Consul prepared query definition that's itself JSON containing
+// literal braces). Wait debounces re-renders by at least that long after
+// a watched value changes, the same min/max quieting consul-template's
+// own `-wait` flag applies, so a burst of KV writes doesn't trigger one
+// re-render per write.
+type TemplateConfig struct {
+	Source      string
+	Destination string
+	Perms       os.FileMode
+	Command     string
+	LeftDelim   string
+	RightDelim  string
+	Wait        time.Duration
+}
+
+// RunnerOptions configures the Consul/Vault endpoints a Runner talks to,
+// the templates it renders, and an env map that's exported into the
+// process environment so templates can read it via the built-in `env`
+// function (the way Nomad injects task environment variables today).
+type RunnerOptions struct {
+	ConsulAddr string
+	VaultAddr  string
+	VaultToken string
+	Templates  []TemplateConfig
+	Env        map[string]string
+}
+
+// Runner drives a consul-template manager.Runner pointed at Styx's local
+// Consul and Vault endpoints.
+type Runner struct {
+	runner *manager.Runner
+}
+
+// NewRunner builds a Runner from opts. once controls whether the
+// underlying manager.Runner exits after the first successful render of
+// every template (used by Once) or keeps running to pick up changes (used
+// by Watch).
+func NewRunner(opts RunnerOptions, once bool) (*Runner, error) {
+	for k, v := range opts.Env {
+		if err := os.Setenv(k, v); err != nil {
+			return nil, fmt.Errorf("failed to export %s for template rendering: %w", k, err)
+		}
+	}
+
+	conf := ctconfig.DefaultConfig()
+	if opts.ConsulAddr != "" {
+		conf.Consul.Address = ctconfig.String(opts.ConsulAddr)
+	}
+	if opts.VaultAddr != "" {
+		conf.Vault.Address = ctconfig.String(opts.VaultAddr)
+	}
+	if opts.VaultToken != "" {
+		conf.Vault.Token = ctconfig.String(opts.VaultToken)
+	}
+
+	templates := make(ctconfig.TemplateConfigs, 0, len(opts.Templates))
+	for _, t := range opts.Templates {
+		tc := &ctconfig.TemplateConfig{
+			Source:      ctconfig.String(t.Source),
+			Destination: ctconfig.String(t.Destination),
+		}
+		if t.Perms != 0 {
+			tc.Perms = ctconfig.FileMode(t.Perms)
+		}
+		if t.Command != "" {
+			tc.Command = []string{t.Command}
+		}
+		if t.LeftDelim != "" {
+			tc.LeftDelim = ctconfig.String(t.LeftDelim)
+		}
+		if t.RightDelim != "" {
+			tc.RightDelim = ctconfig.String(t.RightDelim)
+		}
+		if t.Wait > 0 {
+			tc.Wait = &ctconfig.WaitConfig{
+				Enabled: ctconfig.Bool(true),
+				Min:     ctconfig.TimeDuration(t.Wait),
+				Max:     ctconfig.TimeDuration(t.Wait * 4),
+			}
+		}
+		templates = append(templates, tc)
+	}
+	conf.Templates = &templates
+
+	conf.Finalize()
+
+	r, err := manager.NewRunner(conf, once)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul-template runner: %w", err)
+	}
+
+	return &Runner{runner: r}, nil
+}
+
+// Once renders every configured template exactly once and returns, the way
+// `consul-template -once` does. It's what backs `styx template render`.
+func (r *Runner) Once() error {
+	go r.runner.Start()
+	defer r.runner.Stop()
+
+	select {
+	case err := <-r.runner.ErrCh:
+		return err
+	case <-r.runner.DoneCh:
+		return nil
+	}
+}
+
+// Watch runs the runner until ctx is cancelled, re-rendering whenever a
+// watched Consul KV path or Vault secret changes. It's the long-running
+// counterpart to Once and is meant to be started in its own goroutine, the
+// way `styx template watch` does as a process `styx supervise` manages.
+func (r *Runner) Watch(ctx context.Context) error {
+	return r.WatchWithCallback(ctx, nil)
+}
+
+// WatchWithCallback behaves like Watch, additionally invoking onRender
+// after each render event - e.g. config.TemplateRunner uses this to
+// signal a container once the template it's watching re-renders, instead
+// of (or in addition to) TemplateConfig's shell-command hook.
+func (r *Runner) WatchWithCallback(ctx context.Context, onRender func()) error {
+	go r.runner.Start()
+	defer r.runner.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-r.runner.ErrCh:
+			return err
+		case <-r.runner.RenderEventCh():
+			if onRender != nil {
+				onRender()
+			}
+		}
+	}
+}
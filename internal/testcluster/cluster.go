@@ -0,0 +1,296 @@
+//go:build integration
+
+// Package testcluster spins up a real, multi-node Nomad+Consul+Vault
+// cluster in Docker containers via testcontainers-go so CI (where Homebrew
+// and launchd aren't available) can exercise the same bring-up, upgrade,
+// and snapshot/restore flows that `styx init`/`styx services upgrade` drive
+// on a Mac. It's modeled on Consul's own
+// test/integration/consul-container harness: containers are wired with the
+// exact HCL GenerateServerConfig/GenerateConsulServerConfig/
+// GenerateVaultConfig would write to disk, not a separate test-only config
+// format.
+package testcluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/config"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	// DefaultNomadVersion is the Nomad image tag a Cluster boots unless
+	// ClusterOptions.NomadVersion overrides it.
+	DefaultNomadVersion = "1.7.7"
+	// DefaultConsulVersion is the Consul image tag a Cluster boots unless
+	// ClusterOptions.ConsulVersion overrides it.
+	DefaultConsulVersion = "1.18.1"
+	// DefaultVaultVersion is the Vault image tag a Cluster boots unless
+	// ClusterOptions.VaultVersion overrides it.
+	DefaultVaultVersion = "1.16.2"
+)
+
+// ClusterOptions configures a Cluster before it's brought up.
+type ClusterOptions struct {
+	NomadVersion  string
+	ConsulVersion string
+	VaultVersion  string
+}
+
+func (o ClusterOptions) withDefaults() ClusterOptions {
+	if o.NomadVersion == "" {
+		o.NomadVersion = DefaultNomadVersion
+	}
+	if o.ConsulVersion == "" {
+		o.ConsulVersion = DefaultConsulVersion
+	}
+	if o.VaultVersion == "" {
+		o.VaultVersion = DefaultVaultVersion
+	}
+	return o
+}
+
+// Cluster is a running Nomad+Consul+Vault cluster made of test containers.
+// Nodes share a Docker network so they can reach each other the way
+// co-located services do on a single Mac.
+type Cluster struct {
+	opts    ClusterOptions
+	network *testcontainers.DockerNetwork
+	Nodes   []*Node
+}
+
+// New brings up a single-server cluster: one Consul server, one Vault
+// server, and one Nomad server wired to both. Call AddClient to grow it.
+func New(ctx context.Context, opts ClusterOptions) (*Cluster, error) {
+	opts = opts.withDefaults()
+
+	network, err := testcontainers.GenericNetwork(ctx, testcontainers.GenericNetworkRequest{
+		NetworkRequest: testcontainers.NetworkRequest{CheckDuplicate: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster network: %w", err)
+	}
+
+	c := &Cluster{opts: opts, network: network}
+
+	consul, err := c.startConsulServer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start consul server: %w", err)
+	}
+	c.Nodes = append(c.Nodes, consul)
+
+	vaultNode, err := c.startVaultServer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start vault server: %w", err)
+	}
+	c.Nodes = append(c.Nodes, vaultNode)
+
+	nomad, err := c.startNomadServer(ctx, opts.NomadVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start nomad server: %w", err)
+	}
+	c.Nodes = append(c.Nodes, nomad)
+
+	return c, nil
+}
+
+func (c *Cluster) startConsulServer(ctx context.Context) (*Node, error) {
+	cfg := config.ConsulServerConfig{
+		DataDir:         "/consul/data",
+		AdvertiseIP:     "0.0.0.0",
+		BootstrapExpect: 1,
+	}
+	hcl, err := config.GenerateConsulServerConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return startNode(ctx, c.network, nodeSpec{
+		role:        RoleConsulServer,
+		image:       "hashicorp/consul:" + c.opts.ConsulVersion,
+		hclPath:     "/consul/config/consul.hcl",
+		hcl:         hcl,
+		cmd:         []string{"agent", "-config-dir=/consul/config"},
+		httpPort:    "8500/tcp",
+		waitForPath: "/v1/status/leader",
+	})
+}
+
+func (c *Cluster) startVaultServer(ctx context.Context) (*Node, error) {
+	cfg := config.VaultConfig{
+		DataDir:     "/vault/data",
+		NodeID:      "vault-1",
+		AdvertiseIP: "0.0.0.0",
+	}
+	hcl, err := config.GenerateVaultConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return startNode(ctx, c.network, nodeSpec{
+		role:        RoleVaultServer,
+		image:       "hashicorp/vault:" + c.opts.VaultVersion,
+		hclPath:     "/vault/config/vault.hcl",
+		hcl:         hcl,
+		cmd:         []string{"server", "-config=/vault/config/vault.hcl"},
+		httpPort:    "8200/tcp",
+		waitForPath: "/v1/sys/health?standbyok=true&sealedcode=200",
+	})
+}
+
+func (c *Cluster) startNomadServer(ctx context.Context, version string) (*Node, error) {
+	cfg := config.ServerConfig{
+		DataDir:         "/nomad/data",
+		AdvertiseIP:     "0.0.0.0",
+		BootstrapExpect: 1,
+		PluginDir:       "/nomad/plugins",
+	}
+	hcl, err := config.GenerateServerConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return startNode(ctx, c.network, nodeSpec{
+		role:        RoleNomadServer,
+		image:       "hashicorp/nomad:" + version,
+		hclPath:     "/nomad/config/nomad.hcl",
+		hcl:         hcl,
+		cmd:         []string{"agent", "-config=/nomad/config/nomad.hcl"},
+		httpPort:    "4646/tcp",
+		waitForPath: "/v1/status/leader",
+	})
+}
+
+// AddClient joins a new Nomad client node to the server started by New,
+// the way a second Mac joins a cluster with `styx init --join`.
+func (c *Cluster) AddClient(ctx context.Context) (*Node, error) {
+	leader, err := c.Leader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find leader to join: %w", err)
+	}
+
+	cfg := config.ClientConfig{
+		DataDir:     "/nomad/data",
+		AdvertiseIP: "0.0.0.0",
+		Servers:     []string{leader.NetworkAlias()},
+		PluginDir:   "/nomad/plugins",
+	}
+	hcl, err := config.GenerateClientConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := startNode(ctx, c.network, nodeSpec{
+		role:        RoleNomadClient,
+		image:       "hashicorp/nomad:" + c.opts.NomadVersion,
+		hclPath:     "/nomad/config/nomad.hcl",
+		hcl:         hcl,
+		cmd:         []string{"agent", "-config=/nomad/config/nomad.hcl"},
+		httpPort:    "4646/tcp",
+		waitForPath: "/v1/agent/health",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Nodes = append(c.Nodes, node)
+	return node, nil
+}
+
+// Leader returns the current Nomad server node, asking each Nomad server
+// node's /v1/status/leader until one answers.
+func (c *Cluster) Leader() (*Node, error) {
+	for _, n := range c.Nodes {
+		if n.role != RoleNomadServer {
+			continue
+		}
+		resp, err := http.Get(n.Endpoint("http") + "/v1/status/leader")
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("no nomad server reported a leader")
+}
+
+// SnapshotAndRestore takes a Consul snapshot, wipes the server's data, and
+// restores from the snapshot - the same recovery path
+// `styx cluster restore` drives, paralleling Consul's own
+// upgrade-from-snapshot integration test pattern.
+func (c *Cluster) SnapshotAndRestore(ctx context.Context) error {
+	var consul *Node
+	for _, n := range c.Nodes {
+		if n.role == RoleConsulServer {
+			consul = n
+			break
+		}
+	}
+	if consul == nil {
+		return fmt.Errorf("no consul server in cluster")
+	}
+
+	const snapshotPath = "/tmp/cluster.snap"
+	if _, _, err := consul.container.Exec(ctx, []string{"consul", "snapshot", "save", snapshotPath}); err != nil {
+		return fmt.Errorf("failed to save consul snapshot: %w", err)
+	}
+
+	if _, _, err := consul.container.Exec(ctx, []string{"consul", "snapshot", "restore", snapshotPath}); err != nil {
+		return fmt.Errorf("failed to restore consul snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// UpgradeTo performs a rolling upgrade of every Nomad server node to
+// version, replacing one container at a time and waiting for raft quorum
+// to recover before moving to the next, the way a real fleet upgrade
+// should never drop below quorum.
+func (c *Cluster) UpgradeTo(ctx context.Context, version string) error {
+	for i, n := range c.Nodes {
+		if n.role != RoleNomadServer {
+			continue
+		}
+
+		replacement, err := n.replaceImage(ctx, "hashicorp/nomad:"+version)
+		if err != nil {
+			return fmt.Errorf("failed to replace %s with nomad %s: %w", n.container.GetContainerID()[:12], version, err)
+		}
+		c.Nodes[i] = replacement
+
+		if err := c.waitForQuorum(ctx, 30*time.Second); err != nil {
+			return fmt.Errorf("raft quorum did not recover after upgrading to %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func (c *Cluster) waitForQuorum(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, err := c.Leader(); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+	return fmt.Errorf("timed out waiting for a leader after %s", timeout)
+}
+
+// Terminate stops and removes every container and the cluster network.
+func (c *Cluster) Terminate(ctx context.Context) error {
+	for _, n := range c.Nodes {
+		if err := n.container.Terminate(ctx); err != nil {
+			return fmt.Errorf("failed to terminate %s: %w", n.role, err)
+		}
+	}
+	return c.network.Remove(ctx)
+}
@@ -0,0 +1,77 @@
+//go:build integration
+
+package testcluster
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestColdBootAutoUnseal brings up a fresh cluster and asserts a Nomad
+// server reports a leader, mirroring the cold-boot + auto-unseal path
+// `styx init` drives on a real Mac.
+func TestColdBootAutoUnseal(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	cluster, err := New(ctx, ClusterOptions{})
+	if err != nil {
+		t.Fatalf("failed to bring up cluster: %v", err)
+	}
+	defer cluster.Terminate(ctx)
+
+	if _, err := cluster.Leader(); err != nil {
+		t.Fatalf("no nomad leader after cold boot: %v", err)
+	}
+}
+
+// TestRollingNomadUpgrade replaces the Nomad server one version at a time
+// and asserts raft quorum comes back after each replacement.
+func TestRollingNomadUpgrade(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	const fromVersion = "1.7.6"
+	const toVersion = "1.7.7"
+
+	cluster, err := New(ctx, ClusterOptions{NomadVersion: fromVersion})
+	if err != nil {
+		t.Fatalf("failed to bring up cluster on %s: %v", fromVersion, err)
+	}
+	defer cluster.Terminate(ctx)
+
+	if _, err := cluster.AddClient(ctx); err != nil {
+		t.Fatalf("failed to add nomad client: %v", err)
+	}
+
+	if err := cluster.UpgradeTo(ctx, toVersion); err != nil {
+		t.Fatalf("rolling upgrade to %s failed: %v", toVersion, err)
+	}
+
+	if _, err := cluster.Leader(); err != nil {
+		t.Fatalf("no nomad leader after rolling upgrade: %v", err)
+	}
+}
+
+// TestRestoreFromConsulSnapshot takes a Consul snapshot against a running
+// cluster and restores it, directly paralleling Consul's own
+// upgrade-from-snapshot integration test.
+func TestRestoreFromConsulSnapshot(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	cluster, err := New(ctx, ClusterOptions{})
+	if err != nil {
+		t.Fatalf("failed to bring up cluster: %v", err)
+	}
+	defer cluster.Terminate(ctx)
+
+	if err := cluster.SnapshotAndRestore(ctx); err != nil {
+		t.Fatalf("snapshot and restore failed: %v", err)
+	}
+
+	if _, err := cluster.Leader(); err != nil {
+		t.Fatalf("no nomad leader after consul restore: %v", err)
+	}
+}
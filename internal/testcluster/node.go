@@ -0,0 +1,128 @@
+//go:build integration
+
+package testcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// NodeRole identifies what a Node runs, so Cluster methods like Leader and
+// UpgradeTo can pick out the nodes they care about.
+type NodeRole string
+
+const (
+	RoleConsulServer NodeRole = "consul-server"
+	RoleVaultServer  NodeRole = "vault-server"
+	RoleNomadServer  NodeRole = "nomad-server"
+	RoleNomadClient  NodeRole = "nomad-client"
+)
+
+// Node is a single container in a Cluster running one of Nomad, Consul, or
+// Vault, configured with the HCL the internal/config generators would
+// write to disk on a real Mac.
+type Node struct {
+	role      NodeRole
+	container testcontainers.Container
+	network   *testcontainers.DockerNetwork
+	alias     string
+	spec      nodeSpec
+}
+
+// nodeSpec is the shared shape every startNode call fills in; it exists so
+// Cluster's per-service start* methods stay a readable list of config
+// values instead of each repeating the container wiring boilerplate.
+type nodeSpec struct {
+	role        NodeRole
+	alias       string
+	image       string
+	hclPath     string
+	hcl         string
+	cmd         []string
+	httpPort    string
+	waitForPath string
+}
+
+func startNode(ctx context.Context, network *testcontainers.DockerNetwork, spec nodeSpec) (*Node, error) {
+	if spec.alias == "" {
+		spec.alias = fmt.Sprintf("%s-%d", spec.role, time.Now().UnixNano())
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        spec.image,
+		Cmd:          spec.cmd,
+		ExposedPorts: []string{spec.httpPort},
+		Networks:     []string{network.Name},
+		NetworkAliases: map[string][]string{
+			network.Name: {spec.alias},
+		},
+		Files: []testcontainers.ContainerFile{{
+			Reader:            strings.NewReader(spec.hcl),
+			ContainerFilePath: spec.hclPath,
+			FileMode:          0644,
+		}},
+		WaitingFor: wait.ForHTTP(spec.waitForPath).WithPort(testcontainers.ContainerPort(spec.httpPort)).WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s container: %w", spec.role, err)
+	}
+
+	return &Node{
+		role:      spec.role,
+		container: container,
+		network:   network,
+		alias:     spec.alias,
+		spec:      spec,
+	}, nil
+}
+
+// NetworkAlias is the hostname other nodes on the cluster network reach
+// this node at, the way Consul's retry_join/Nomad's servers list expects.
+func (n *Node) NetworkAlias() string {
+	return n.alias
+}
+
+// Endpoint returns this node's externally-mapped base URL for the given
+// scheme, suitable for host-side HTTP calls like Leader's.
+func (n *Node) Endpoint(scheme string) string {
+	ctx := context.Background()
+	host, err := n.container.Host(ctx)
+	if err != nil {
+		return ""
+	}
+	port, err := n.container.MappedPort(ctx, testcontainers.ContainerPort(n.spec.httpPort))
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s:%s", scheme, host, port.Port())
+}
+
+// replaceImage terminates this node's container and starts a replacement
+// running a different image, reusing the same alias, command, and HCL - a
+// rolling upgrade in place. It returns the new Node; the caller is
+// responsible for swapping it into Cluster.Nodes.
+func (n *Node) replaceImage(ctx context.Context, image string) (*Node, error) {
+	if err := n.container.Terminate(ctx); err != nil {
+		return nil, fmt.Errorf("failed to terminate existing container: %w", err)
+	}
+
+	spec := n.spec
+	spec.image = image
+	spec.alias = n.alias
+
+	node, err := startNode(ctx, n.network, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start replacement %s container: %w", image, err)
+	}
+	return node, nil
+}
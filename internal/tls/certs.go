@@ -1,11 +1,30 @@
+// Package tls issues the TLS material Consul and Nomad need directly with
+// crypto/x509, instead of shelling out to the `consul tls`/`nomad tls`
+// CLIs. That made Styx unusable wherever those binaries weren't installed
+// and gave up any control over file durability or permissions; this
+// package generates the same material itself and writes it atomically.
 package tls
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
+	"time"
+)
+
+const (
+	caValidity   = 5 * 365 * 24 * time.Hour
+	leafValidity = 365 * 24 * time.Hour
 )
 
 // CertPaths holds the paths to TLS certificate files.
@@ -15,100 +34,65 @@ type CertPaths struct {
 	KeyFile  string // Path to node private key
 }
 
-// GenerateCA generates a new Certificate Authority using Consul's built-in CA.
-// The CA files are created in the specified directory.
-func GenerateCA(certsDir string) error {
-	if err := os.MkdirAll(certsDir, 0700); err != nil {
-		return fmt.Errorf("failed to create certs directory: %w", err)
-	}
-
-	// Check if CA already exists
-	caFile := filepath.Join(certsDir, "consul-agent-ca.pem")
-	if _, err := os.Stat(caFile); err == nil {
-		return nil // CA already exists
-	}
-
-	// Generate CA using consul tls ca create
-	cmd := exec.Command("consul", "tls", "ca", "create")
-	cmd.Dir = certsDir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to generate CA: %w\nOutput: %s", err, output)
-	}
+// NomadCertPaths holds the paths to Nomad TLS certificate files.
+type NomadCertPaths struct {
+	CAFile   string // Path to Nomad CA certificate (nomad-agent-ca.pem)
+	CertFile string // Path to node certificate
+	KeyFile  string // Path to node private key
+}
 
-	return nil
+// GenerateCA generates a new Consul CA directly with crypto/x509, valid for
+// 5 years. A CA already present in certsDir is left alone.
+func GenerateCA(certsDir string) error {
+	return generateCA(certsDir, "consul-agent-ca.pem", "consul-agent-ca-key.pem", "Consul Agent CA")
 }
 
-// GenerateServerCert generates a server certificate for Consul.
-// Existing certs are deleted and regenerated.
+// GenerateServerCert generates a server certificate for Consul, signed by
+// the CA GenerateCA created. Existing certs for this role are deleted and
+// regenerated.
 func GenerateServerCert(certsDir, datacenter string) (*CertPaths, error) {
-	if err := os.MkdirAll(certsDir, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create certs directory: %w", err)
-	}
-
-	// Delete existing server certs (allows reinit without manual cleanup)
-	deleteExistingCerts(certsDir, datacenter+"-server-consul")
-
-	// Generate server certificate
-	cmd := exec.Command("consul", "tls", "cert", "create", "-server", "-dc", datacenter)
-	cmd.Dir = certsDir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate server cert: %w\nOutput: %s", err, output)
-	}
+	certFile := datacenter + "-server-consul-0.pem"
+	keyFile := datacenter + "-server-consul-0-key.pem"
+	dnsNames := []string{fmt.Sprintf("server.%s.consul", datacenter), "localhost"}
 
-	// Find the generated cert files
-	certFile, keyFile, err := findLatestCert(certsDir, datacenter+"-server-consul")
-	if err != nil {
+	if err := generateLeafCert(certsDir, "consul-agent-ca.pem", "consul-agent-ca-key.pem", certFile, keyFile, dnsNames, "Consul Server"); err != nil {
 		return nil, err
 	}
 
 	return &CertPaths{
 		CAFile:   filepath.Join(certsDir, "consul-agent-ca.pem"),
-		CertFile: certFile,
-		KeyFile:  keyFile,
+		CertFile: filepath.Join(certsDir, certFile),
+		KeyFile:  filepath.Join(certsDir, keyFile),
 	}, nil
 }
 
-// GenerateClientCert generates a client certificate for Consul.
-// Existing certs are deleted and regenerated.
+// GenerateClientCert generates a client certificate for Consul, signed by
+// the CA GenerateCA created. Existing certs for this role are deleted and
+// regenerated.
 func GenerateClientCert(certsDir, datacenter string) (*CertPaths, error) {
-	if err := os.MkdirAll(certsDir, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create certs directory: %w", err)
-	}
+	certFile := datacenter + "-client-consul-0.pem"
+	keyFile := datacenter + "-client-consul-0-key.pem"
+	dnsNames := []string{fmt.Sprintf("server.%s.consul", datacenter), "localhost"}
 
-	// Delete existing client certs (allows reinit without manual cleanup)
-	deleteExistingCerts(certsDir, datacenter+"-client-consul")
-
-	// Generate client certificate
-	cmd := exec.Command("consul", "tls", "cert", "create", "-client", "-dc", datacenter)
-	cmd.Dir = certsDir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate client cert: %w\nOutput: %s", err, output)
-	}
-
-	// Find the generated cert files
-	certFile, keyFile, err := findLatestCert(certsDir, datacenter+"-client-consul")
-	if err != nil {
+	if err := generateLeafCert(certsDir, "consul-agent-ca.pem", "consul-agent-ca-key.pem", certFile, keyFile, dnsNames, "Consul Client"); err != nil {
 		return nil, err
 	}
 
 	return &CertPaths{
 		CAFile:   filepath.Join(certsDir, "consul-agent-ca.pem"),
-		CertFile: certFile,
-		KeyFile:  keyFile,
+		CertFile: filepath.Join(certsDir, certFile),
+		KeyFile:  filepath.Join(certsDir, keyFile),
 	}, nil
 }
 
-// GenerateGossipKey generates a gossip encryption key using consul keygen.
+// GenerateGossipKey generates a 32-byte gossip encryption key, base64
+// encoded the way Consul/Nomad's -encrypt flag expects.
 func GenerateGossipKey() (string, error) {
-	cmd := exec.Command("consul", "keygen")
-	output, err := cmd.Output()
-	if err != nil {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
 		return "", fmt.Errorf("failed to generate gossip key: %w", err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return base64.StdEncoding.EncodeToString(key), nil
 }
 
 // SaveGossipKey saves the gossip key to a file.
@@ -116,12 +100,9 @@ func SaveGossipKey(secretsDir, key string) error {
 	if err := os.MkdirAll(secretsDir, 0700); err != nil {
 		return fmt.Errorf("failed to create secrets directory: %w", err)
 	}
-
-	keyFile := filepath.Join(secretsDir, "gossip.key")
-	if err := os.WriteFile(keyFile, []byte(key), 0600); err != nil {
+	if err := writeAtomic(filepath.Join(secretsDir, "gossip.key"), []byte(key), 0600); err != nil {
 		return fmt.Errorf("failed to write gossip key: %w", err)
 	}
-
 	return nil
 }
 
@@ -132,215 +113,311 @@ func LoadGossipKey(secretsDir string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to read gossip key: %w", err)
 	}
-	return strings.TrimSpace(string(data)), nil
+	return string(data), nil
 }
 
-// CopyCAFromServer copies the CA certificate from the server.
-// This is used by client nodes joining the cluster.
+// CopyCAFromServer copies the CA certificate from the server. This is used
+// by client nodes joining the cluster.
 func CopyCAFromServer(serverAddr, certsDir string) error {
 	if err := os.MkdirAll(certsDir, 0700); err != nil {
 		return fmt.Errorf("failed to create certs directory: %w", err)
 	}
 
-	// Download CA from server's HTTP endpoint
-	// Note: This requires the server to expose the CA file via HTTP
-	// For now, we'll use a simpler approach - fetch via HTTP API
+	// Download CA from server's HTTP endpoint.
+	// Note: This requires the server to expose the CA file via HTTP.
 	caURL := fmt.Sprintf("http://%s:8500/v1/connect/ca/roots", serverAddr)
 
-	cmd := exec.Command("curl", "-s", caURL)
-	output, err := cmd.Output()
+	resp, err := http.Get(caURL)
 	if err != nil {
 		return fmt.Errorf("failed to fetch CA from server: %w", err)
 	}
+	resp.Body.Close()
 
-	// The API returns JSON with PEM certificates
-	// For simplicity, we'll extract just the root cert
-	// TODO: Parse JSON properly if needed
-	_ = output
-
-	// Alternative: Use SCP or manual copy for now
+	// The API returns JSON with PEM certificates.
+	// TODO: parse the response and write consul-agent-ca.pem, once a real
+	// client is plumbed through here - internal/bootstrap already covers CA
+	// distribution for `styx init`/`styx join`.
 	return fmt.Errorf("CA distribution not yet implemented - please copy consul-agent-ca.pem from server to %s", certsDir)
 }
 
-// findLatestCert finds the most recently created certificate files matching the prefix.
-func findLatestCert(dir, prefix string) (certFile, keyFile string, err error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to read certs directory: %w", err)
+// GetExistingCerts returns paths to existing certificates if they exist.
+func GetExistingCerts(certsDir, datacenter string, isServer bool) (*CertPaths, error) {
+	caFile := filepath.Join(certsDir, "consul-agent-ca.pem")
+	if _, err := os.Stat(caFile); err != nil {
+		return nil, fmt.Errorf("CA file not found: %w", err)
 	}
 
-	for _, entry := range entries {
-		name := entry.Name()
-		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, ".pem") && !strings.HasSuffix(name, "-key.pem") {
-			certFile = filepath.Join(dir, name)
-			keyFile = filepath.Join(dir, strings.TrimSuffix(name, ".pem")+"-key.pem")
-			break
-		}
+	role := "client"
+	if isServer {
+		role = "server"
 	}
+	certFile := fmt.Sprintf("%s-%s-consul-0.pem", datacenter, role)
+	keyFile := fmt.Sprintf("%s-%s-consul-0-key.pem", datacenter, role)
 
-	if certFile == "" {
-		return "", "", fmt.Errorf("no certificate found with prefix %s in %s", prefix, dir)
+	if _, err := os.Stat(filepath.Join(certsDir, certFile)); err != nil {
+		return nil, fmt.Errorf("certificate not found: %w", err)
 	}
 
-	return certFile, keyFile, nil
+	return &CertPaths{
+		CAFile:   caFile,
+		CertFile: filepath.Join(certsDir, certFile),
+		KeyFile:  filepath.Join(certsDir, keyFile),
+	}, nil
+}
+
+// GenerateNomadCA generates a new Nomad CA the same way GenerateCA does.
+func GenerateNomadCA(certsDir string) error {
+	return generateCA(certsDir, "nomad-agent-ca.pem", "nomad-agent-ca-key.pem", "Nomad Agent CA")
 }
 
-// deleteExistingCerts removes existing certificate files matching the prefix.
-// This allows regenerating certs without manual cleanup.
-func deleteExistingCerts(dir, prefix string) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return // Directory doesn't exist or can't be read, nothing to delete
+// GenerateNomadServerCert generates a server certificate for Nomad, signed
+// by the CA GenerateNomadCA created. Existing certs for this role are
+// deleted and regenerated.
+func GenerateNomadServerCert(certsDir, region string) (*NomadCertPaths, error) {
+	certFile := region + "-server-nomad.pem"
+	keyFile := region + "-server-nomad-key.pem"
+	dnsNames := []string{fmt.Sprintf("server.%s.nomad", region), "localhost"}
+
+	if err := generateLeafCert(certsDir, "nomad-agent-ca.pem", "nomad-agent-ca-key.pem", certFile, keyFile, dnsNames, "Nomad Server"); err != nil {
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		name := entry.Name()
-		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, ".pem") {
-			os.Remove(filepath.Join(dir, name))
-		}
+	return &NomadCertPaths{
+		CAFile:   filepath.Join(certsDir, "nomad-agent-ca.pem"),
+		CertFile: filepath.Join(certsDir, certFile),
+		KeyFile:  filepath.Join(certsDir, keyFile),
+	}, nil
+}
+
+// GenerateNomadClientCert generates a client certificate for Nomad, signed
+// by the CA GenerateNomadCA created. Existing certs for this role are
+// deleted and regenerated.
+func GenerateNomadClientCert(certsDir, region string) (*NomadCertPaths, error) {
+	certFile := region + "-client-nomad.pem"
+	keyFile := region + "-client-nomad-key.pem"
+	dnsNames := []string{fmt.Sprintf("server.%s.nomad", region), "localhost"}
+
+	if err := generateLeafCert(certsDir, "nomad-agent-ca.pem", "nomad-agent-ca-key.pem", certFile, keyFile, dnsNames, "Nomad Client"); err != nil {
+		return nil, err
 	}
+
+	return &NomadCertPaths{
+		CAFile:   filepath.Join(certsDir, "nomad-agent-ca.pem"),
+		CertFile: filepath.Join(certsDir, certFile),
+		KeyFile:  filepath.Join(certsDir, keyFile),
+	}, nil
 }
 
-// GetExistingCerts returns paths to existing certificates if they exist.
-func GetExistingCerts(certsDir, datacenter string, isServer bool) (*CertPaths, error) {
-	caFile := filepath.Join(certsDir, "consul-agent-ca.pem")
+// GetExistingNomadCerts returns paths to existing Nomad certificates if
+// they exist.
+func GetExistingNomadCerts(certsDir, region string, isServer bool) (*NomadCertPaths, error) {
+	caFile := filepath.Join(certsDir, "nomad-agent-ca.pem")
 	if _, err := os.Stat(caFile); err != nil {
-		return nil, fmt.Errorf("CA file not found: %w", err)
+		return nil, fmt.Errorf("Nomad CA file not found: %w", err)
 	}
 
-	var prefix string
+	role := "client"
 	if isServer {
-		prefix = datacenter + "-server-consul"
-	} else {
-		prefix = datacenter + "-client-consul"
+		role = "server"
 	}
+	certFile := fmt.Sprintf("%s-%s-nomad.pem", region, role)
+	keyFile := fmt.Sprintf("%s-%s-nomad-key.pem", region, role)
 
-	certFile, keyFile, err := findLatestCert(certsDir, prefix)
-	if err != nil {
-		return nil, err
+	if _, err := os.Stat(filepath.Join(certsDir, certFile)); err != nil {
+		return nil, fmt.Errorf("certificate not found: %w", err)
 	}
 
-	return &CertPaths{
+	return &NomadCertPaths{
 		CAFile:   caFile,
-		CertFile: certFile,
-		KeyFile:  keyFile,
+		CertFile: filepath.Join(certsDir, certFile),
+		KeyFile:  filepath.Join(certsDir, keyFile),
 	}, nil
 }
 
-// NomadCertPaths holds the paths to Nomad TLS certificate files.
-type NomadCertPaths struct {
-	CAFile   string // Path to Nomad CA certificate (nomad-agent-ca.pem)
-	CertFile string // Path to node certificate
-	KeyFile  string // Path to node private key
-}
-
-// GenerateNomadCA generates a new Certificate Authority for Nomad.
-func GenerateNomadCA(certsDir string) error {
+// generateCA writes a self-signed ECDSA P-256 CA certificate/key pair named
+// certFile/keyFile under certsDir, unless one is already there.
+func generateCA(certsDir, certFile, keyFile, commonName string) error {
 	if err := os.MkdirAll(certsDir, 0700); err != nil {
 		return fmt.Errorf("failed to create certs directory: %w", err)
 	}
 
-	// Check if Nomad CA already exists
-	caFile := filepath.Join(certsDir, "nomad-agent-ca.pem")
-	if _, err := os.Stat(caFile); err == nil {
+	caCertPath := filepath.Join(certsDir, certFile)
+	if _, err := os.Stat(caCertPath); err == nil {
 		return nil // CA already exists
 	}
 
-	// Generate CA using nomad tls ca create
-	cmd := exec.Command("nomad", "tls", "ca", "create")
-	cmd.Dir = certsDir
-	output, err := cmd.CombinedOutput()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate CA key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
 	if err != nil {
-		return fmt.Errorf("failed to generate Nomad CA: %w\nOutput: %s", err, output)
+		return fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal CA key: %w", err)
+	}
+
+	if err := writePEMAtomic(caCertPath, "CERTIFICATE", certDER, 0644); err != nil {
+		return fmt.Errorf("failed to write CA certificate: %w", err)
+	}
+	if err := writePEMAtomic(filepath.Join(certsDir, keyFile), "EC PRIVATE KEY", keyDER, 0600); err != nil {
+		return fmt.Errorf("failed to write CA key: %w", err)
 	}
 
 	return nil
 }
 
-// GenerateNomadServerCert generates a server certificate for Nomad.
-// Existing certs are deleted and regenerated.
-func GenerateNomadServerCert(certsDir, region string) (*NomadCertPaths, error) {
+// generateLeafCert deletes any existing certFile/keyFile under certsDir,
+// then generates a fresh ECDSA P-256 leaf certificate for dnsNames (plus
+// 127.0.0.1), signed by the CA at caCertFile/caKeyFile, valid for one year.
+func generateLeafCert(certsDir, caCertFile, caKeyFile, certFile, keyFile string, dnsNames []string, commonName string) error {
 	if err := os.MkdirAll(certsDir, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create certs directory: %w", err)
+		return fmt.Errorf("failed to create certs directory: %w", err)
 	}
 
-	// Delete existing server certs (allows reinit without manual cleanup)
-	deleteExistingCerts(certsDir, region+"-server-nomad")
+	os.Remove(filepath.Join(certsDir, certFile))
+	os.Remove(filepath.Join(certsDir, keyFile))
 
-	// Generate server certificate
-	cmd := exec.Command("nomad", "tls", "cert", "create", "-server", "-region", region)
-	cmd.Dir = certsDir
-	output, err := cmd.CombinedOutput()
+	caCert, caKey, err := loadCA(certsDir, caCertFile, caKeyFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate Nomad server cert: %w\nOutput: %s", err, output)
+		return fmt.Errorf("failed to load CA: %w", err)
 	}
 
-	// Find the generated cert files
-	certFile, keyFile, err := findLatestCert(certsDir, region+"-server-nomad")
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to generate leaf key: %w", err)
 	}
 
-	return &NomadCertPaths{
-		CAFile:   filepath.Join(certsDir, "nomad-agent-ca.pem"),
-		CertFile: certFile,
-		KeyFile:  keyFile,
-	}, nil
-}
-
-// GenerateNomadClientCert generates a client certificate for Nomad.
-// Existing certs are deleted and regenerated.
-func GenerateNomadClientCert(certsDir, region string) (*NomadCertPaths, error) {
-	if err := os.MkdirAll(certsDir, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create certs directory: %w", err)
+	serial, err := randomSerial()
+	if err != nil {
+		return err
 	}
 
-	// Delete existing client certs (allows reinit without manual cleanup)
-	deleteExistingCerts(certsDir, region+"-client-nomad")
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(leafValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     dnsNames,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
 
-	// Generate client certificate
-	cmd := exec.Command("nomad", "tls", "cert", "create", "-client", "-region", region)
-	cmd.Dir = certsDir
-	output, err := cmd.CombinedOutput()
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate Nomad client cert: %w\nOutput: %s", err, output)
+		return fmt.Errorf("failed to sign leaf certificate: %w", err)
 	}
 
-	// Find the generated cert files
-	certFile, keyFile, err := findLatestCert(certsDir, region+"-client-nomad")
+	keyDER, err := x509.MarshalECPrivateKey(key)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to marshal leaf key: %w", err)
 	}
 
-	return &NomadCertPaths{
-		CAFile:   filepath.Join(certsDir, "nomad-agent-ca.pem"),
-		CertFile: certFile,
-		KeyFile:  keyFile,
-	}, nil
+	if err := writePEMAtomic(filepath.Join(certsDir, certFile), "CERTIFICATE", certDER, 0644); err != nil {
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+	if err := writePEMAtomic(filepath.Join(certsDir, keyFile), "EC PRIVATE KEY", keyDER, 0600); err != nil {
+		return fmt.Errorf("failed to write key: %w", err)
+	}
+
+	return nil
 }
 
-// GetExistingNomadCerts returns paths to existing Nomad certificates if they exist.
-func GetExistingNomadCerts(certsDir, region string, isServer bool) (*NomadCertPaths, error) {
-	caFile := filepath.Join(certsDir, "nomad-agent-ca.pem")
-	if _, err := os.Stat(caFile); err != nil {
-		return nil, fmt.Errorf("Nomad CA file not found: %w", err)
+func loadCA(certsDir, certFile, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(filepath.Join(certsDir, certFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(certsDir, keyFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CA key: %w", err)
 	}
 
-	var prefix string
-	if isServer {
-		prefix = region + "-server-nomad"
-	} else {
-		prefix = region + "-client-nomad"
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("failed to decode CA certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA certificate: %w", err)
 	}
 
-	certFile, keyFile, err := findLatestCert(certsDir, prefix)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("failed to decode CA key PEM")
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("failed to parse CA key: %w", err)
 	}
 
-	return &NomadCertPaths{
-		CAFile:   caFile,
-		CertFile: certFile,
-		KeyFile:  keyFile,
-	}, nil
+	return cert, key, nil
+}
+
+func randomSerial() (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serial, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial: %w", err)
+	}
+	return serial, nil
+}
+
+func writePEMAtomic(path, blockType string, der []byte, perm os.FileMode) error {
+	return writeAtomic(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), perm)
+}
+
+// writeAtomic writes data to path via a .tmp file that's fsynced and
+// renamed into place, so a crash mid-write never leaves a half-written
+// file at path.
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, perm); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,45 @@
+package tls
+
+// Issuer issues the Consul and Nomad leaf certificates a node needs,
+// either from the self-managed CA this package already generates
+// (LocalIssuer) or from a Vault PKI secrets engine (VaultPKIIssuer).
+// Renewer is written against this interface so --pki=local|vault only
+// changes where a certificate comes from, not how the rest of the
+// package's file layout (CertPaths/NomadCertPaths, filenames under
+// certsDir) works.
+type Issuer interface {
+	// IssueConsulCert issues (or re-issues) this node's Consul leaf
+	// certificate for datacenter, as server or client SANs depending on
+	// isServer, writing it under certsDir with the same filenames
+	// GenerateServerCert/GenerateClientCert already use.
+	IssueConsulCert(certsDir, datacenter string, isServer bool) (*CertPaths, error)
+
+	// IssueNomadCert issues (or re-issues) this node's Nomad leaf
+	// certificate for region, the Nomad equivalent of IssueConsulCert.
+	IssueNomadCert(certsDir, region string, isServer bool) (*NomadCertPaths, error)
+}
+
+// LocalIssuer issues certificates from the CA files GenerateCA/
+// GenerateNomadCA already manage under certsDir - the behavior this
+// package had before VaultPKIIssuer existed.
+type LocalIssuer struct{}
+
+func (LocalIssuer) IssueConsulCert(certsDir, datacenter string, isServer bool) (*CertPaths, error) {
+	if err := GenerateCA(certsDir); err != nil {
+		return nil, err
+	}
+	if isServer {
+		return GenerateServerCert(certsDir, datacenter)
+	}
+	return GenerateClientCert(certsDir, datacenter)
+}
+
+func (LocalIssuer) IssueNomadCert(certsDir, region string, isServer bool) (*NomadCertPaths, error) {
+	if err := GenerateNomadCA(certsDir); err != nil {
+		return nil, err
+	}
+	if isServer {
+		return GenerateNomadServerCert(certsDir, region)
+	}
+	return GenerateNomadClientCert(certsDir, region)
+}
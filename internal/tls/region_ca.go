@@ -0,0 +1,65 @@
+package tls
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Federation needs one Nomad CA per region: `styx region add` generates a
+// brand new region's own CA, then exchanges it with the authoritative
+// region over the bootstrap server (see internal/bootstrap's
+// /bootstrap/region-ca endpoint) so each side can verify the other's
+// server/client certs once their serf pools are joined. This is separate
+// from GenerateNomadCA/GenerateNomadServerCert, which manage the single
+// fixed-name CA a lone, unfederated region's own nodes use.
+
+// validateRegionName rejects a region name that isn't safe to build a
+// certsDir-relative file name out of. Region names reach RegionCAFile and
+// StoreRegionCA straight from the /bootstrap/region-ca/ URL path, which
+// requireToken gates but doesn't otherwise constrain - without this, a
+// holder of a valid join token could pass a region like "../../etc" to
+// read or write arbitrary files outside certsDir.
+func validateRegionName(region string) error {
+	if region == "" {
+		return fmt.Errorf("invalid region name: empty")
+	}
+	if region == "." || region == ".." || strings.ContainsAny(region, "/\\") {
+		return fmt.Errorf("invalid region name %q", region)
+	}
+	return nil
+}
+
+// RegionCAFile returns the path a region's own Nomad CA certificate lives
+// at under certsDir.
+func RegionCAFile(certsDir, region string) (string, error) {
+	if err := validateRegionName(region); err != nil {
+		return "", err
+	}
+	return filepath.Join(certsDir, region+"-nomad-agent-ca.pem"), nil
+}
+
+func regionCAKeyFile(region string) string {
+	return region + "-nomad-agent-ca-key.pem"
+}
+
+// GenerateRegionCA creates region's own Nomad CA under certsDir, the same
+// way GenerateNomadCA does for an unfederated cluster, but keyed by region
+// name so more than one region's CA can live on disk at once (e.g. on the
+// authoritative region's server, once it has exchanged CAs with others).
+func GenerateRegionCA(certsDir, region string) error {
+	if err := validateRegionName(region); err != nil {
+		return err
+	}
+	return generateCA(certsDir, region+"-nomad-agent-ca.pem", regionCAKeyFile(region), fmt.Sprintf("Nomad Agent CA (%s)", region))
+}
+
+// StoreRegionCA writes a remote region's CA certificate under certsDir, as
+// fetched from that region's bootstrap server over /bootstrap/region-ca.
+func StoreRegionCA(certsDir, region string, pemData []byte) error {
+	path, err := RegionCAFile(certsDir, region)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, pemData, 0644)
+}
@@ -0,0 +1,229 @@
+package tls
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultRenewBefore is how long before a leaf certificate's expiry
+// Renewer re-issues it, used whenever Renewer.RenewBefore is left zero.
+const DefaultRenewBefore = 30 * 24 * time.Hour
+
+// Renewer re-issues this node's Consul and Nomad leaf certificates before
+// they expire, the same role pki.Renewer plays for the cluster mTLS CA. A
+// single Renewer covers both, since `styx init` provisions and names them
+// together (datacenter for Consul, region for Nomad).
+type Renewer struct {
+	CertsDir   string
+	Datacenter string
+	Region     string
+	IsServer   bool
+
+	// Issuer is where a re-issued certificate comes from. Defaults to
+	// LocalIssuer{} if nil, preserving this package's original
+	// self-managed-CA behavior for callers that don't set --pki=vault.
+	Issuer Issuer
+
+	RenewBefore time.Duration // defaults to DefaultRenewBefore if zero
+	CheckEvery  time.Duration // defaults to 1h if zero
+
+	// Reload is called after a successful re-issue, e.g. to restart Nomad
+	// so it picks up the new leaf certificate.
+	Reload func() error
+
+	// LogPath, if set, gets one appended line per rotation (see
+	// `styx tls status`). Left empty, rotations aren't logged.
+	LogPath string
+}
+
+// Run checks this node's leaf certificates every CheckEvery and re-issues
+// whichever are within RenewBefore of expiring, until ctx is cancelled.
+func (r *Renewer) Run(ctx context.Context) error {
+	interval := r.CheckEvery
+	if interval == 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := r.RenewIfNeeded(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RenewIfNeeded re-issues this node's Consul and/or Nomad leaf certificate
+// if either is within RenewBefore of expiring, calling Reload (if set) once
+// after any re-issue. Exported so `styx tls rotate` can drive a single
+// check without running the Run loop.
+func (r *Renewer) RenewIfNeeded() error {
+	renewBefore := r.RenewBefore
+	if renewBefore == 0 {
+		renewBefore = DefaultRenewBefore
+	}
+
+	role := "client"
+	if r.IsServer {
+		role = "server"
+	}
+
+	consulFile := filepath.Join(r.CertsDir, fmt.Sprintf("%s-%s-consul-0.pem", r.Datacenter, role))
+	nomadFile := filepath.Join(r.CertsDir, fmt.Sprintf("%s-%s-nomad.pem", r.Region, role))
+
+	consulSoon := expiresSoonOrMissing(consulFile, renewBefore)
+	nomadSoon := expiresSoonOrMissing(nomadFile, renewBefore)
+	if !consulSoon && !nomadSoon {
+		return nil
+	}
+
+	if consulSoon {
+		if err := r.reissueConsul(role); err != nil {
+			return fmt.Errorf("failed to re-issue consul leaf certificate: %w", err)
+		}
+		r.logRotation("consul", role)
+	}
+
+	if nomadSoon {
+		if err := r.reissueNomad(role); err != nil {
+			return fmt.Errorf("failed to re-issue nomad leaf certificate: %w", err)
+		}
+		r.logRotation("nomad", role)
+	}
+
+	if r.Reload != nil {
+		if err := r.Reload(); err != nil {
+			return fmt.Errorf("certificate renewed but reload failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Renewer) issuer() Issuer {
+	if r.Issuer != nil {
+		return r.Issuer
+	}
+	return LocalIssuer{}
+}
+
+func (r *Renewer) reissueConsul(role string) error {
+	_, err := r.issuer().IssueConsulCert(r.CertsDir, r.Datacenter, role == "server")
+	return err
+}
+
+func (r *Renewer) reissueNomad(role string) error {
+	_, err := r.issuer().IssueNomadCert(r.CertsDir, r.Region, role == "server")
+	return err
+}
+
+// logRotation best-effort appends one line to LogPath recording a
+// rotation. A failure here never fails the rotation itself - the cert was
+// already re-issued by the time this is called.
+func (r *Renewer) logRotation(certType, role string) {
+	if r.LogPath == "" {
+		return
+	}
+
+	line := fmt.Sprintf("%s rotated %s %s leaf certificate\n", time.Now().UTC().Format(time.RFC3339), certType, role)
+	f, err := os.OpenFile(r.LogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		fmt.Printf("Warning: failed to open %s to log certificate rotation: %v\n", r.LogPath, err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line); err != nil {
+		fmt.Printf("Warning: failed to log certificate rotation to %s: %v\n", r.LogPath, err)
+	}
+}
+
+// RotateCA generates brand new Consul and Nomad root CAs under certsDir,
+// then re-issues this node's own leaf certificates under them, mirroring
+// pki.RotateCA/`styx pki rotate --rotate-ca`. It does not cross-sign the
+// old and new CAs for an overlap window or distribute the new CAs to other
+// nodes - like CopyCAFromServer above, that choreography is bigger than a
+// single call can safely do here; until it exists, run this on every node
+// in quick succession, the same way --rotate-ca already tells operators to
+// for the cluster CA.
+//
+// RotateCA only makes sense for LocalIssuer: a VaultPKIIssuer's CA lives
+// inside Vault's PKI mounts, rotated via their own root rotation
+// endpoints, not by deleting files under certsDir.
+func RotateCA(certsDir, datacenter, region string, isServer bool) error {
+	for _, f := range []string{"consul-agent-ca.pem", "consul-agent-ca-key.pem"} {
+		if err := os.Remove(filepath.Join(certsDir, f)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old %s: %w", f, err)
+		}
+	}
+	if err := GenerateCA(certsDir); err != nil {
+		return fmt.Errorf("failed to generate new consul CA: %w", err)
+	}
+
+	for _, f := range []string{"nomad-agent-ca.pem", "nomad-agent-ca-key.pem"} {
+		if err := os.Remove(filepath.Join(certsDir, f)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old %s: %w", f, err)
+		}
+	}
+	if err := GenerateNomadCA(certsDir); err != nil {
+		return fmt.Errorf("failed to generate new nomad CA: %w", err)
+	}
+
+	role := "client"
+	if isServer {
+		role = "server"
+	}
+	r := &Renewer{CertsDir: certsDir, Datacenter: datacenter, Region: region, IsServer: isServer}
+	if err := r.reissueConsul(role); err != nil {
+		return fmt.Errorf("failed to re-issue consul leaf certificate under rotated CA: %w", err)
+	}
+	if err := r.reissueNomad(role); err != nil {
+		return fmt.Errorf("failed to re-issue nomad leaf certificate under rotated CA: %w", err)
+	}
+
+	return nil
+}
+
+// DaysUntilExpiry returns how many whole days remain before the
+// certificate at certFile expires, for `styx tls status`.
+func DaysUntilExpiry(certFile string) (int, error) {
+	cert, err := parseCertPEM(certFile)
+	if err != nil {
+		return 0, err
+	}
+	return int(time.Until(cert.NotAfter) / (24 * time.Hour)), nil
+}
+
+// expiresSoonOrMissing reports whether the certificate at certFile expires
+// within renewBefore. A missing or unparseable certificate also reports
+// true, the same way pki.Renewer treats a first-boot missing leaf as
+// needing an issue rather than a fatal error.
+func expiresSoonOrMissing(certFile string, renewBefore time.Duration) bool {
+	cert, err := parseCertPEM(certFile)
+	if err != nil {
+		return true
+	}
+	return time.Until(cert.NotAfter) < renewBefore
+}
+
+func parseCertPEM(certFile string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode certificate PEM in %s", certFile)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,247 @@
+package tls
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultVaultAddr matches internal/vault's own default - Vault only ever
+// runs locally, fronted by Nomad's workload identity auth, not over the
+// network.
+const defaultVaultAddr = "http://127.0.0.1:8200"
+
+// vaultPKILeafTTL is how long a certificate VaultPKIIssuer issues is valid
+// for, matching this package's own leafValidity so Renewer's RenewBefore
+// window behaves the same regardless of which Issuer is selected.
+const vaultPKILeafTTL = leafValidity
+
+// VaultPKIIssuer issues Consul and Nomad leaf certificates from a Vault
+// PKI secrets engine mounted at pki_consul/ and pki_nomad/, instead of the
+// CA files LocalIssuer manages on disk. Bootstrap sets the mounts up;
+// IssueConsulCert/IssueNomadCert call their issue/<role> endpoints on
+// every (re-)issue, the way a Vault-backed install is meant to never keep
+// a CA private key on a node's disk at all.
+type VaultPKIIssuer struct {
+	// Addr is Vault's HTTP API address. Defaults to defaultVaultAddr if
+	// empty.
+	Addr string
+	// Token authorizes every request below - a token with access to
+	// sys/mounts and the pki_consul/pki_nomad paths for Bootstrap, or just
+	// to their issue/<role> endpoints for IssueConsulCert/IssueNomadCert.
+	Token string
+
+	client *http.Client
+}
+
+// NewVaultPKIIssuer returns a VaultPKIIssuer talking to addr ("" for
+// defaultVaultAddr) with token.
+func NewVaultPKIIssuer(addr, token string) *VaultPKIIssuer {
+	if addr == "" {
+		addr = defaultVaultAddr
+	}
+	return &VaultPKIIssuer{Addr: addr, Token: token, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Bootstrap mounts pki_consul/ and pki_nomad/ (type=pki), tunes each to a
+// 10-year max_lease_ttl, generates an internal root under each, and
+// configures a "server" and "client" role on each scoped to datacenter's
+// Consul RPC SAN and region's Nomad RPC SAN respectively. Safe to call
+// against mounts that already exist - mounting, generating a root, and
+// writing a role are all idempotent from Vault's point of view.
+func (v *VaultPKIIssuer) Bootstrap(datacenter, region string) error {
+	if err := v.mountPKI("pki_consul"); err != nil {
+		return fmt.Errorf("failed to mount pki_consul: %w", err)
+	}
+	if err := v.mountPKI("pki_nomad"); err != nil {
+		return fmt.Errorf("failed to mount pki_nomad: %w", err)
+	}
+
+	if err := v.generateRoot("pki_consul", "Consul Agent CA"); err != nil {
+		return fmt.Errorf("failed to generate pki_consul root: %w", err)
+	}
+	if err := v.generateRoot("pki_nomad", "Nomad Agent CA"); err != nil {
+		return fmt.Errorf("failed to generate pki_nomad root: %w", err)
+	}
+
+	consulDomain := fmt.Sprintf("server.%s.consul", datacenter)
+	if err := v.writeRole("pki_consul", "server", consulDomain); err != nil {
+		return fmt.Errorf("failed to configure pki_consul server role: %w", err)
+	}
+	if err := v.writeRole("pki_consul", "client", consulDomain); err != nil {
+		return fmt.Errorf("failed to configure pki_consul client role: %w", err)
+	}
+
+	nomadDomain := fmt.Sprintf("server.%s.nomad", region)
+	if err := v.writeRole("pki_nomad", "server", nomadDomain); err != nil {
+		return fmt.Errorf("failed to configure pki_nomad server role: %w", err)
+	}
+	if err := v.writeRole("pki_nomad", "client", nomadDomain); err != nil {
+		return fmt.Errorf("failed to configure pki_nomad client role: %w", err)
+	}
+
+	return nil
+}
+
+func (v *VaultPKIIssuer) IssueConsulCert(certsDir, datacenter string, isServer bool) (*CertPaths, error) {
+	role := "client"
+	if isServer {
+		role = "server"
+	}
+	domain := fmt.Sprintf("server.%s.consul", datacenter)
+
+	issued, err := v.issue("pki_consul", role, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue consul %s certificate from vault: %w", role, err)
+	}
+
+	certFile := filepath.Join(certsDir, fmt.Sprintf("%s-%s-consul-0.pem", datacenter, role))
+	keyFile := filepath.Join(certsDir, fmt.Sprintf("%s-%s-consul-0-key.pem", datacenter, role))
+	caFile := filepath.Join(certsDir, "consul-agent-ca.pem")
+	if err := issued.writeTo(certFile, keyFile, caFile); err != nil {
+		return nil, err
+	}
+
+	return &CertPaths{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}, nil
+}
+
+func (v *VaultPKIIssuer) IssueNomadCert(certsDir, region string, isServer bool) (*NomadCertPaths, error) {
+	role := "client"
+	if isServer {
+		role = "server"
+	}
+	domain := fmt.Sprintf("server.%s.nomad", region)
+
+	issued, err := v.issue("pki_nomad", role, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue nomad %s certificate from vault: %w", role, err)
+	}
+
+	certFile := filepath.Join(certsDir, fmt.Sprintf("%s-%s-nomad.pem", region, role))
+	keyFile := filepath.Join(certsDir, fmt.Sprintf("%s-%s-nomad-key.pem", region, role))
+	caFile := filepath.Join(certsDir, "nomad-agent-ca.pem")
+	if err := issued.writeTo(certFile, keyFile, caFile); err != nil {
+		return nil, err
+	}
+
+	return &NomadCertPaths{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}, nil
+}
+
+// issuedCert holds the PEM material a pki/issue/<role> call returns.
+type issuedCert struct {
+	Certificate string `json:"certificate"`
+	IssuingCA   string `json:"issuing_ca"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// writeTo persists an issuedCert's material to the same certFile/keyFile/
+// caFile layout LocalIssuer writes, so GetExistingCerts, Renewer, and
+// everything that reads certsDir by filename don't need to know which
+// Issuer produced them.
+func (c *issuedCert) writeTo(certFile, keyFile, caFile string) error {
+	if err := os.WriteFile(certFile, []byte(c.Certificate), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", certFile, err)
+	}
+	if err := os.WriteFile(keyFile, []byte(c.PrivateKey), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", keyFile, err)
+	}
+	if err := os.WriteFile(caFile, []byte(c.IssuingCA), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", caFile, err)
+	}
+	return nil
+}
+
+func (v *VaultPKIIssuer) issue(mount, role, domain string) (*issuedCert, error) {
+	var out struct {
+		Data issuedCert `json:"data"`
+	}
+	payload := map[string]any{
+		"common_name": domain,
+		"alt_names":   "localhost",
+		"ip_sans":     "127.0.0.1",
+		"ttl":         vaultPKILeafTTL.String(),
+	}
+	if err := v.request(http.MethodPost, fmt.Sprintf("%s/issue/%s", mount, role), payload, &out); err != nil {
+		return nil, err
+	}
+	return &out.Data, nil
+}
+
+func (v *VaultPKIIssuer) mountPKI(mount string) error {
+	if err := v.request(http.MethodPost, "sys/mounts/"+mount, map[string]any{"type": "pki"}, nil); err != nil {
+		// Vault returns an error if the mount already exists - tolerate that
+		// the same way LoadOrCreateCA tolerates an already-generated CA.
+		if !isAlreadyMounted(err) {
+			return err
+		}
+	}
+	return v.request(http.MethodPost, "sys/mounts/"+mount+"/tune", map[string]any{"max_lease_ttl": "87600h"}, nil)
+}
+
+func (v *VaultPKIIssuer) generateRoot(mount, commonName string) error {
+	return v.request(http.MethodPost, mount+"/root/generate/internal", map[string]any{
+		"common_name": commonName,
+		"ttl":         "87600h",
+	}, nil)
+}
+
+func (v *VaultPKIIssuer) writeRole(mount, role, domain string) error {
+	return v.request(http.MethodPost, mount+"/roles/"+role, map[string]any{
+		"allowed_domains":    domain,
+		"allow_bare_domains": true,
+		"allow_localhost":    true,
+		"allow_ip_sans":      true,
+		"max_ttl":            "87600h",
+	}, nil)
+}
+
+func (v *VaultPKIIssuer) request(method, path string, body map[string]any, out any) error {
+	client := v.client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, v.Addr+"/v1/"+path, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("vault request %s %s failed (status %d): %s", method, path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// isAlreadyMounted reports whether a mountPKI failure was just "path is
+// already in use" - the error Vault's HTTP API returns for a mount that
+// already exists - so Bootstrap can treat it as success, the same way
+// LoadOrCreateCA treats an existing CA file as success rather than an
+// error.
+func isAlreadyMounted(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "already in use")
+}
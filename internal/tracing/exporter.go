@@ -0,0 +1,165 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exporter receives finished spans. Export must not block the caller
+// meaningfully: otlpExporter just appends to a buffer and lets a background
+// goroutine batch and send it.
+type Exporter interface {
+	Export(span *Span)
+}
+
+// noopExporter is DefaultTracer's exporter until Init is called.
+type noopExporter struct{}
+
+func (noopExporter) Export(*Span) {}
+
+// otlpExporter sends finished spans to an OTLP collector's traces/v1 HTTP
+// endpoint as OTLP's JSON payload, batching on a short interval so a burst
+// of spans (e.g. one per proxied connection) doesn't turn into one HTTP
+// request each.
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+
+	mu      sync.Mutex
+	pending []*Span
+
+	flushCh chan struct{}
+	doneCh  chan struct{}
+}
+
+func newOTLPExporter(endpoint string) *otlpExporter {
+	e := &otlpExporter{
+		endpoint: strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		client:   &http.Client{Timeout: 5 * time.Second},
+		flushCh:  make(chan struct{}, 1),
+		doneCh:   make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// Export implements Exporter by buffering span until the next batch send.
+func (e *otlpExporter) Export(span *Span) {
+	e.mu.Lock()
+	e.pending = append(e.pending, span)
+	e.mu.Unlock()
+
+	select {
+	case e.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+func (e *otlpExporter) run() {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.send()
+		case <-e.flushCh:
+			e.send()
+		case <-e.doneCh:
+			e.send()
+			return
+		}
+	}
+}
+
+func (e *otlpExporter) send() {
+	e.mu.Lock()
+	spans := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(otlpPayload(spans))
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Best-effort: a collector that isn't listening shouldn't slow down or
+	// fail the command being traced.
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// flush sends any pending spans and stops the background batch sender.
+func (e *otlpExporter) flush() {
+	close(e.doneCh)
+}
+
+// otlpPayload builds an OTLP ExportTraceServiceRequest in its JSON encoding
+// (https://opentelemetry.io/docs/specs/otlp/#otlphttp) for spans.
+func otlpPayload(spans []*Span) map[string]any {
+	otlpSpans := make([]map[string]any, len(spans))
+	for i, s := range spans {
+		attrs := make([]map[string]any, 0, len(s.Attributes))
+		for k, v := range s.Attributes {
+			attrs = append(attrs, map[string]any{
+				"key":   k,
+				"value": map[string]string{"stringValue": v},
+			})
+		}
+
+		statusCode := 1 // STATUS_CODE_OK
+		if s.StatusCode == "error" {
+			statusCode = 2 // STATUS_CODE_ERROR
+		}
+
+		otlpSpans[i] = map[string]any{
+			"traceId":           s.TraceID,
+			"spanId":            s.SpanID,
+			"parentSpanId":      s.ParentSpanID,
+			"name":              s.Name,
+			"startTimeUnixNano": fmt.Sprintf("%d", s.StartTime.UnixNano()),
+			"endTimeUnixNano":   fmt.Sprintf("%d", s.EndTime.UnixNano()),
+			"attributes":        attrs,
+			"status": map[string]any{
+				"code":    statusCode,
+				"message": s.StatusMsg,
+			},
+		}
+	}
+
+	return map[string]any{
+		"resourceSpans": []map[string]any{
+			{
+				"resource": map[string]any{
+					"attributes": []map[string]any{
+						{"key": "service.name", "value": map[string]string{"stringValue": "styx"}},
+					},
+				},
+				"scopeSpans": []map[string]any{
+					{
+						"scope": map[string]string{"name": "github.com/kessler-frost/styx"},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
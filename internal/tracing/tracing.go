@@ -0,0 +1,153 @@
+// Package tracing provides a minimal, OTLP-compatible span model for
+// instrumenting styx's long multi-step flows - vault bootstrap/unseal,
+// proxy connections, service deploys, container operations - so --trace can
+// answer "where did styx up actually hang" (Nomad JWKS wait? vault unseal?
+// container pull?) without pulling in the full go.opentelemetry.io/otel SDK
+// and its gRPC transport. This is the same hand-rolled-over-vendored
+// tradeoff internal/diagnostic makes for metrics instead of the Prometheus
+// client library.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Span is one unit of traced work: a named operation with a start/end time
+// and a flat set of string attributes, handed to its Tracer's Exporter once
+// it ends.
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]string
+	StatusCode   string // "ok" or "error"
+	StatusMsg    string
+
+	tracer *Tracer
+}
+
+// SetAttribute records a string attribute on the span. Safe to call on a nil
+// Span (e.g. a caller that didn't bother checking --trace was on), which is
+// a no-op.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.Attributes[key] = value
+}
+
+// SetAttributeInt records an integer attribute on the span.
+func (s *Span) SetAttributeInt(key string, value int64) {
+	s.SetAttribute(key, fmt.Sprintf("%d", value))
+}
+
+// SetError marks the span as failed with err's message. A nil err is a
+// no-op, so callers can write `span.SetError(err)` unconditionally after a
+// fallible call.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.StatusCode = "error"
+	s.StatusMsg = err.Error()
+}
+
+// End records the span's end time and hands it to the tracer's exporter.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.EndTime = time.Now()
+	s.tracer.exporter.Export(s)
+}
+
+type spanKey struct{}
+
+// Tracer creates spans and forwards finished ones to an Exporter.
+type Tracer struct {
+	exporter Exporter
+}
+
+// NewTracer creates a Tracer that exports finished spans via exporter.
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// DefaultTracer is the process-wide tracer every StartSpan call in
+// vault/proxy/services/driver uses. It discards spans until Init is called,
+// so instrumented code pays only the cost of generating span IDs when
+// --trace isn't set.
+var DefaultTracer = NewTracer(noopExporter{})
+
+// Init points DefaultTracer at endpoint via OTLP/HTTP (the traces/v1 JSON
+// transport, so this doesn't need a gRPC dependency) and returns a func
+// that flushes pending spans and restores the noop exporter - deferred by
+// cmd/styx's --trace flag for the lifetime of one invocation. An empty
+// endpoint is a no-op: DefaultTracer keeps discarding spans and the
+// returned func does nothing.
+func Init(endpoint string) func() {
+	if endpoint == "" {
+		return func() {}
+	}
+
+	exp := newOTLPExporter(endpoint)
+	DefaultTracer = NewTracer(exp)
+	return func() {
+		exp.flush()
+		DefaultTracer = NewTracer(noopExporter{})
+	}
+}
+
+// StartSpan starts a span named name using DefaultTracer, as a child of
+// whatever span is already in ctx, or as a new trace root if there isn't
+// one. Returns the context to pass down the call chain and the span to End()
+// and annotate.
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	return DefaultTracer.StartSpan(ctx, name)
+}
+
+// StartSpan is the method the package-level StartSpan delegates to, for
+// callers (mainly tests) that construct their own Tracer instead of using
+// DefaultTracer.
+func (t *Tracer) StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	var traceID, parentSpanID string
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		traceID = parent.TraceID
+		parentSpanID = parent.SpanID
+	} else {
+		traceID = newID(16)
+	}
+
+	span := &Span{
+		TraceID:      traceID,
+		SpanID:       newID(8),
+		ParentSpanID: parentSpanID,
+		Name:         name,
+		StartTime:    time.Now(),
+		Attributes:   map[string]string{},
+		StatusCode:   "ok",
+		tracer:       t,
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// newID returns a random hex ID of n bytes (16 for a trace ID, 8 for a span
+// ID, matching OTLP's W3C trace-context sizes).
+func newID(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand failing means something is very wrong with the host;
+		// fall back to a fixed marker rather than panicking an instrumented
+		// code path over it.
+		return strings.Repeat("0", n*2)
+	}
+	return hex.EncodeToString(b)
+}
@@ -5,6 +5,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kessler-frost/styx/internal/api"
+	"github.com/kessler-frost/styx/internal/observability"
 	"github.com/kessler-frost/styx/internal/setup"
 	tuisetup "github.com/kessler-frost/styx/internal/tui/setup"
 	"github.com/kessler-frost/styx/internal/tui/styles"
@@ -33,8 +34,10 @@ type tickMsg time.Time
 
 // Options configures the TUI.
 type Options struct {
-	SetupMode bool
-	Prereqs   setup.PrereqStatus
+	SetupMode      bool
+	Prereqs        setup.PrereqStatus
+	ConfigDir      string // where `styx upgrade` records its progress, for ClusterStatus.Upgrading
+	DiagnosticAddr string // where this process's diagnostic.Server is listening, if any
 }
 
 // Model is the main TUI model.
@@ -48,12 +51,22 @@ type Model struct {
 	width      int
 	height     int
 	client     *api.Client
+
+	// diagnosticAddr is applied to cluster each time it's (re)constructed,
+	// since setup completing replaces m.cluster with a fresh ClusterModel.
+	diagnosticAddr string
 }
 
 // New creates a new TUI model.
 func New(opts Options) Model {
+	client := api.NewClient().WithUpgradeStateDir(opts.ConfigDir)
+	if opts.DiagnosticAddr != "" {
+		client = client.WithMetrics(observability.NewMetrics())
+	}
+
 	m := Model{
-		client: api.NewClient(),
+		client:         client,
+		diagnosticAddr: opts.DiagnosticAddr,
 	}
 
 	if opts.SetupMode {
@@ -63,6 +76,7 @@ func New(opts Options) Model {
 		m.mode = ModeNormal
 		m.view = ViewCluster
 		m.cluster = views.NewClusterModel(m.client)
+		m.cluster.SetDiagnosticAddr(m.diagnosticAddr)
 		m.services = views.NewServicesModel(m.client)
 		m.jobs = views.NewJobsModel(m.client)
 	}
@@ -70,6 +84,13 @@ func New(opts Options) Model {
 	return m
 }
 
+// Metrics returns the latency histograms the TUI's api.Client accumulates
+// while polling, or nil if Options.DiagnosticAddr was empty. Intended for
+// cmd/styx to mount onto a diagnostic.Server alongside this Model.
+func (m Model) Metrics() *observability.Metrics {
+	return m.client.Metrics()
+}
+
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
 	var cmds []tea.Cmd
@@ -117,6 +138,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.mode = ModeNormal
 				m.view = ViewCluster
 				m.cluster = views.NewClusterModel(m.client)
+				m.cluster.SetDiagnosticAddr(m.diagnosticAddr)
 				m.services = views.NewServicesModel(m.client)
 				m.jobs = views.NewJobsModel(m.client)
 				return m, tea.Batch(m.refreshCmd(), m.tickCmd())
@@ -124,6 +146,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			return m, cmd
 		}
+
+		// Messages the jobs view's own Update loop generates for itself
+		// (job submission results, alloc detail, log stream lines) land
+		// here since they match none of the cases above.
+		if m.mode == ModeNormal && m.view == ViewJobs {
+			newJobs, cmd := m.jobs.Update(msg)
+			m.jobs = newJobs
+			return m, cmd
+		}
 	}
 
 	return m, nil
@@ -136,6 +167,15 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// The jobs view's submit form and log search are raw keystroke sinks -
+	// route everything to them instead of treating keys as tab/quit
+	// shortcuts (same idea as the ModeSetup branch above).
+	if m.view == ViewJobs && m.jobs.CapturesInput() {
+		newJobs, cmd := m.jobs.Update(msg)
+		m.jobs = newJobs
+		return m, cmd
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		return m, tea.Quit
@@ -152,17 +192,29 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.view = ViewJobs
 		return m, m.refreshCmd()
 
-	case "r":
-		return m, m.refreshCmd()
-
 	case "?":
 		// Toggle help (could add a help overlay in the future)
 		return m, nil
 	}
 
+	// The jobs view owns "r" itself (toggle the running-status filter), so
+	// it's routed here instead of through the "r" case below.
+	if m.view == ViewJobs {
+		newJobs, cmd := m.jobs.Update(msg)
+		m.jobs = newJobs
+		return m, cmd
+	}
+
+	if msg.String() == "r" {
+		return m, m.refreshCmd()
+	}
+
 	return m, nil
 }
 
+// tickCmd and refreshCmd are tea.Cmds, not supervisor.Services: Bubble Tea
+// already owns their scheduling and cancellation as part of its own event
+// loop, so there's nothing for a supervisor.Tree to add here.
 func (m Model) tickCmd() tea.Cmd {
 	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
 		return tickMsg(t)
@@ -209,13 +261,18 @@ func (m Model) renderNormalMode() string {
 	}
 
 	// Help
-	help := styles.HelpStyle.Render(
-		styles.RenderKeyHelp("1", "cluster") + "  " +
-			styles.RenderKeyHelp("2", "services") + "  " +
-			styles.RenderKeyHelp("3", "jobs") + "  " +
-			styles.RenderKeyHelp("r", "refresh") + "  " +
-			styles.RenderKeyHelp("q", "quit"),
-	)
+	keyHelp := styles.RenderKeyHelp("1", "cluster") + "  " +
+		styles.RenderKeyHelp("2", "services") + "  " +
+		styles.RenderKeyHelp("3", "jobs") + "  " +
+		styles.RenderKeyHelp("q", "quit")
+
+	if m.view == ViewJobs {
+		keyHelp = m.jobs.HelpText() + "  " + keyHelp
+	} else {
+		keyHelp = styles.RenderKeyHelp("r", "refresh") + "  " + keyHelp
+	}
+
+	help := styles.HelpStyle.Render(keyHelp)
 
 	return tabs + "\n\n" + content + "\n\n" + help
 }
@@ -226,9 +283,9 @@ func (m Model) renderTabs() string {
 
 	for i, tab := range tabs {
 		if View(i) == m.view {
-			rendered += styles.TabActiveStyle.Render(" "+tab+" ")
+			rendered += styles.TabActiveStyle.Render(" " + tab + " ")
 		} else {
-			rendered += styles.TabInactiveStyle.Render(" "+tab+" ")
+			rendered += styles.TabInactiveStyle.Render(" " + tab + " ")
 		}
 		if i < len(tabs)-1 {
 			rendered += "  "
@@ -1,9 +1,11 @@
 package setup
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -11,6 +13,20 @@ import (
 	"github.com/kessler-frost/styx/internal/tui/styles"
 )
 
+const (
+	progressBarWidth = 40
+	maxLogLines      = 8
+)
+
+// installUpdateMsg carries one InstallUpdate read off the in-flight
+// install's update channel. ok is false once that channel has been closed,
+// signaling the install is wrapping up and its result is on its way via
+// installResultMsg.
+type installUpdateMsg struct {
+	update setup.InstallUpdate
+	ok     bool
+}
+
 // installResultMsg is sent when an installation completes.
 type installResultMsg struct {
 	result setup.InstallResult
@@ -30,6 +46,23 @@ type Model struct {
 	height     int
 	done       bool
 	error      string
+
+	// Streaming install state (see internal/setup's InstallCtx).
+	stepProgress    progress.Model
+	overallProgress progress.Model
+	overallTotal    int
+	overallDone     int
+	currentStage    string
+	logLines        []string
+
+	cancelInstall context.CancelFunc
+	updates       chan setup.InstallUpdate
+	resultCh      chan setup.InstallResult
+
+	// altIndex tracks which eligible installer strategy (see
+	// setup.InstallPlanAt) the user has cycled to for each prerequisite
+	// name, with "c". Absent/zero means the manifest's first match.
+	altIndex map[string]int
 }
 
 // New creates a new setup model.
@@ -39,8 +72,10 @@ func New(prereqs setup.PrereqStatus) Model {
 	s.Style = lipgloss.NewStyle().Foreground(styles.ColorPrimary)
 
 	return Model{
-		prereqs: prereqs,
-		spinner: s,
+		prereqs:         prereqs,
+		spinner:         s,
+		stepProgress:    progress.New(progress.WithDefaultGradient()),
+		overallProgress: progress.New(progress.WithDefaultGradient()),
 	}
 }
 
@@ -58,6 +93,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.stepProgress.Width = minInt(progressBarWidth, m.width-4)
+		m.overallProgress.Width = minInt(progressBarWidth, m.width-4)
 		return m, nil
 
 	case spinner.TickMsg:
@@ -65,23 +102,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
+	case progress.FrameMsg:
+		stepModel, stepCmd := m.stepProgress.Update(msg)
+		m.stepProgress = stepModel.(progress.Model)
+		overallModel, overallCmd := m.overallProgress.Update(msg)
+		m.overallProgress = overallModel.(progress.Model)
+		return m, tea.Batch(stepCmd, overallCmd)
+
+	case installUpdateMsg:
+		return m.handleInstallUpdate(msg)
+
 	case installResultMsg:
-		m.installing = false
-		if !msg.result.Success {
-			m.error = msg.result.Error
-			return m, nil
-		}
-		// Recheck prerequisites
-		return m, m.recheckCmd()
+		return m.handleInstallResult(msg)
 
 	case recheckMsg:
 		m.prereqs = setup.GetStatus()
 		m.error = ""
 		if !setup.NeedsSetup(m.prereqs) {
 			m.done = true
-		} else if m.installAll {
-			// Continue installing next missing prerequisite
-			return m, m.installNextCmd()
+			return m, nil
+		}
+		if m.installAll {
+			missing := m.prereqs.MissingPrereqs()
+			if len(missing) > 0 {
+				ordered, err := setup.OrderForInstall(missing)
+				if err != nil {
+					m.error = err.Error()
+					m.installAll = false
+					return m, nil
+				}
+				return m.beginInstall(ordered[0])
+			}
 		}
 		return m, nil
 	}
@@ -91,7 +142,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.installing {
-		return m, nil // Ignore keys while installing
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			if m.cancelInstall != nil {
+				m.cancelInstall()
+			}
+		}
+		return m, nil // ignore everything else while installing
 	}
 
 	switch msg.String() {
@@ -102,18 +159,25 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Install current item
 		missing := m.prereqs.MissingPrereqs()
 		if len(missing) > 0 && m.cursor < len(missing) {
-			m.installing = true
 			m.installAll = false
-			return m, m.installCmd(missing[m.cursor])
+			m.overallTotal = 0
+			m.overallDone = 0
+			return m.beginInstall(missing[m.cursor])
 		}
 
 	case "a":
-		// Install all
+		// Install all, in dependency order (see setup.OrderForInstall).
 		missing := m.prereqs.MissingPrereqs()
 		if len(missing) > 0 {
-			m.installing = true
+			ordered, err := setup.OrderForInstall(missing)
+			if err != nil {
+				m.error = err.Error()
+				return m, nil
+			}
 			m.installAll = true
-			return m, m.installCmd(missing[0])
+			m.overallTotal = len(ordered)
+			m.overallDone = 0
+			return m.beginInstall(ordered[0])
 		}
 
 	case "n":
@@ -135,6 +199,22 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cursor++
 		}
 
+	case "c":
+		// Cycle the selected item's installer strategy (brew/apt/nix/...).
+		missing := m.prereqs.MissingPrereqs()
+		if len(missing) > 0 && m.cursor < len(missing) {
+			name := missing[m.cursor].Name
+			plan := setup.InstallPlanAt(name, m.altIndex[name])
+			total := len(plan.Alternates) + 1
+			if total > 1 {
+				if m.altIndex == nil {
+					m.altIndex = make(map[string]int)
+				}
+				m.altIndex[name] = (m.altIndex[name] + 1) % total
+			}
+		}
+		return m, nil
+
 	case "enter":
 		if m.done {
 			return m, tea.Quit
@@ -144,19 +224,83 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) installCmd(p setup.Prerequisite) tea.Cmd {
+// beginInstall starts p's install in a background goroutine and puts the
+// model into its installing state, returning the Cmds that listen for the
+// goroutine's InstallUpdate stream and final InstallResult.
+func (m Model) beginInstall(p setup.Prerequisite) (tea.Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan setup.InstallUpdate)
+	resultCh := make(chan setup.InstallResult, 1)
+
+	m.installing = true
+	m.error = ""
+	m.currentStage = ""
+	m.logLines = nil
+	m.cancelInstall = cancel
+	m.updates = updates
+	m.resultCh = resultCh
+
+	go func() {
+		result := setup.InstallCtxAt(ctx, p, m.altIndex[p.Name], updates)
+		close(updates)
+		resultCh <- result
+	}()
+
+	return m, tea.Batch(m.waitForUpdateCmd(), m.waitForResultCmd(), m.stepProgress.SetPercent(0))
+}
+
+func (m Model) handleInstallUpdate(msg installUpdateMsg) (tea.Model, tea.Cmd) {
+	if !msg.ok {
+		// updates is closed; the goroutine is wrapping up and its result
+		// will arrive shortly via resultCh.
+		return m, nil
+	}
+
+	m.currentStage = msg.update.Stage
+	if msg.update.Line != "" {
+		m.logLines = append(m.logLines, msg.update.Line)
+		if len(m.logLines) > maxLogLines {
+			m.logLines = m.logLines[len(m.logLines)-maxLogLines:]
+		}
+	}
+
+	cmds := []tea.Cmd{m.waitForUpdateCmd(), m.stepProgress.SetPercent(msg.update.Percent)}
+	if m.installAll && m.overallTotal > 0 {
+		overallPercent := (float64(m.overallDone) + msg.update.Percent) / float64(m.overallTotal)
+		cmds = append(cmds, m.overallProgress.SetPercent(overallPercent))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) handleInstallResult(msg installResultMsg) (tea.Model, tea.Cmd) {
+	m.installing = false
+	m.cancelInstall = nil
+
+	if !msg.result.Success {
+		m.error = msg.result.Error
+		return m, nil
+	}
+
+	if m.installAll {
+		m.overallDone++
+	}
+	// Recheck prerequisites
+	return m, m.recheckCmd()
+}
+
+func (m Model) waitForUpdateCmd() tea.Cmd {
+	updates := m.updates
 	return func() tea.Msg {
-		result := setup.Install(p)
-		return installResultMsg{result: result}
+		u, ok := <-updates
+		return installUpdateMsg{update: u, ok: ok}
 	}
 }
 
-func (m Model) installNextCmd() tea.Cmd {
-	missing := m.prereqs.MissingPrereqs()
-	if len(missing) == 0 {
-		return m.recheckCmd()
+func (m Model) waitForResultCmd() tea.Cmd {
+	resultCh := m.resultCh
+	return func() tea.Msg {
+		return installResultMsg{result: <-resultCh}
 	}
-	return m.installCmd(missing[0])
 }
 
 func (m Model) recheckCmd() tea.Cmd {
@@ -175,11 +319,13 @@ func (m Model) View() string {
 
 	// Header
 	b.WriteString(styles.HeaderStyle.Render("  Styx Setup  "))
+	b.WriteString("\n")
+	b.WriteString(styles.DescStyle.Render(fmt.Sprintf("Package manager: %s", setup.CurrentManagerKind())))
 	b.WriteString("\n\n")
 
 	// Status message
 	if m.installing {
-		b.WriteString(m.spinner.View() + " Installing...\n\n")
+		b.WriteString(m.renderInstallProgress())
 	} else {
 		b.WriteString("Checking prerequisites...\n\n")
 	}
@@ -211,6 +357,33 @@ func (m Model) View() string {
 	return b.String()
 }
 
+func (m Model) renderInstallProgress() string {
+	var b strings.Builder
+
+	if m.installAll && m.overallTotal > 0 {
+		b.WriteString(styles.DescStyle.Render(fmt.Sprintf("Overall (%d/%d)", m.overallDone, m.overallTotal)))
+		b.WriteString("\n")
+		b.WriteString(m.overallProgress.View())
+		b.WriteString("\n\n")
+	}
+
+	stage := m.currentStage
+	if stage == "" {
+		stage = "starting"
+	}
+	b.WriteString(m.spinner.View() + " " + styles.DescStyle.Render(stage) + "\n")
+	b.WriteString(m.stepProgress.View())
+	b.WriteString("\n")
+
+	if len(m.logLines) > 0 {
+		b.WriteString("\n")
+		b.WriteString(styles.CodeStyle.Render(strings.Join(m.logLines, "\n")))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
 func (m Model) renderPrereqList() string {
 	var b strings.Builder
 
@@ -268,12 +441,22 @@ func (m Model) renderInstallPrompt(p setup.Prerequisite) string {
 	// Prompt
 	b.WriteString(fmt.Sprintf("Install %s?\n\n", styles.SelectedItemStyle.Render(p.Name)))
 
-	// Commands to run
-	if len(p.InstallCmds) > 0 {
-		b.WriteString("Will run:\n")
-		for _, cmd := range p.InstallCmds {
-			b.WriteString("  " + styles.CodeStyle.Render(cmd) + "\n")
-		}
+	plan := setup.InstallPlanAt(p.Name, m.altIndex[p.Name])
+	if plan.NoneAvailable {
+		b.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("No installer available for %s on this host", p.Name)))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(styles.DescStyle.Render(fmt.Sprintf("Backend: %s", plan.Chosen.Manager)))
+	if len(plan.Alternates) > 0 {
+		b.WriteString(styles.DescStyle.Render(fmt.Sprintf(" (%d more available, c to cycle)", len(plan.Alternates))))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString("Will run:\n")
+	for _, cmd := range setup.CommandsForInstaller(p.Name, plan.Chosen) {
+		b.WriteString("  " + styles.CodeStyle.Render(cmd) + "\n")
 	}
 
 	return b.String()
@@ -281,12 +464,13 @@ func (m Model) renderInstallPrompt(p setup.Prerequisite) string {
 
 func (m Model) renderHelp() string {
 	if m.installing {
-		return styles.HelpStyle.Render("Installing... please wait")
+		return styles.HelpStyle.Render(styles.RenderKeyHelp("ctrl+c/esc", "cancel"))
 	}
 
 	return styles.HelpStyle.Render(
 		styles.RenderKeyHelp("y", "install") + "  " +
 			styles.RenderKeyHelp("a", "install all") + "  " +
+			styles.RenderKeyHelp("c", "cycle backend") + "  " +
 			styles.RenderKeyHelp("n", "skip") + "  " +
 			styles.RenderKeyHelp("q", "quit"),
 	)
@@ -313,3 +497,10 @@ func (m Model) renderComplete() string {
 func (m Model) IsDone() bool {
 	return m.done
 }
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
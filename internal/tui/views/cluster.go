@@ -10,8 +10,9 @@ import (
 
 // ClusterModel represents the cluster view.
 type ClusterModel struct {
-	client *api.Client
-	status api.ClusterStatus
+	client         *api.Client
+	status         api.ClusterStatus
+	diagnosticAddr string // set via SetDiagnosticAddr when --diagnostic-addr is in use
 }
 
 // NewClusterModel creates a new cluster view model.
@@ -21,6 +22,13 @@ func NewClusterModel(client *api.Client) ClusterModel {
 	return m
 }
 
+// SetDiagnosticAddr records where this process's diagnostic.Server (if any)
+// is listening, so View can surface it for operators who want /metrics or
+// pprof without digging through the TUI's invocation flags.
+func (m *ClusterModel) SetDiagnosticAddr(addr string) {
+	m.diagnosticAddr = addr
+}
+
 // Refresh fetches the latest cluster status.
 func (m *ClusterModel) Refresh() {
 	m.status = m.client.GetClusterStatus()
@@ -54,6 +62,10 @@ func (m ClusterModel) View() string {
 	nomadIcon := getStatusIcon(m.status.Nomad.Status)
 	b.WriteString(fmt.Sprintf("  %s Nomad:      %s\n", nomadIcon, m.status.Nomad.Status))
 
+	if m.status.Upgrading {
+		b.WriteString(styles.PendingStyle.Render(fmt.Sprintf("  Upgrading to %s...\n", m.status.TargetVersion)))
+	}
+
 	b.WriteString("\n")
 
 	// Node info
@@ -79,6 +91,11 @@ func (m ClusterModel) View() string {
 		b.WriteString(fmt.Sprintf("  Connected to: %s\n", m.status.KnownServers))
 	}
 
+	if m.diagnosticAddr != "" {
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  Diagnostics: http://%s/metrics\n", m.diagnosticAddr))
+	}
+
 	return b.String()
 }
 
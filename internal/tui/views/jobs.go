@@ -1,38 +1,805 @@
 package views
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/kessler-frost/styx/internal/api"
 	"github.com/kessler-frost/styx/internal/tui/styles"
 )
 
-// JobsModel represents the jobs view.
+// jobsState is one entry in JobsModel's view stack: `enter` pushes a more
+// detailed state, `esc` pops back to the previous one.
+type jobsState int
+
+const (
+	jobsStateList jobsState = iota
+	jobsStateSubmit
+	jobsStateDetail
+	jobsStateLogs
+	jobsStatePrompt
+	jobsStatePresets
+)
+
+// jobsFilter is the active set of filters the list view applies to the
+// fetched job list. status/jobType become a Filter expression (see
+// internal/api's Filter) passed straight to GetJobs; node is applied
+// client-side afterward since Filter has no "any allocation matches"
+// operator for a job's Allocations slice.
+type jobsFilter struct {
+	status  string // "", "running", "pending", "dead"
+	jobType string // "", "service", "batch", "system"
+	node    string // node name substring, matched against each allocation
+	search  string // substring matched against the job name
+}
+
+func (f jobsFilter) active() bool {
+	return f.status != "" || f.jobType != "" || f.node != "" || f.search != ""
+}
+
+// expr builds the internal/api Filter expression for the status/type/search
+// parts of f. node is intentionally left out - Refresh applies it itself
+// after the jobs come back.
+func (f jobsFilter) expr() string {
+	var parts []string
+	if f.status != "" {
+		parts = append(parts, fmt.Sprintf("Status == %q", f.status))
+	}
+	if f.jobType != "" {
+		parts = append(parts, fmt.Sprintf("Type == %q", f.jobType))
+	}
+	if f.search != "" {
+		parts = append(parts, fmt.Sprintf("Name contains %q", f.search))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// summary renders f as a short one-line description for the filter bar and
+// for naming/listing presets.
+func (f jobsFilter) summary() string {
+	if !f.active() {
+		return "none"
+	}
+	var parts []string
+	if f.status != "" {
+		parts = append(parts, "status="+f.status)
+	}
+	if f.jobType != "" {
+		parts = append(parts, "type="+f.jobType)
+	}
+	if f.node != "" {
+		parts = append(parts, "node="+f.node)
+	}
+	if f.search != "" {
+		parts = append(parts, "name~"+f.search)
+	}
+	return strings.Join(parts, " ")
+}
+
+var jobTypeCycle = []string{"", "service", "batch", "system"}
+
+// next returns f with jobType advanced to the next entry in jobTypeCycle,
+// wrapping back to "" (no filter) after "system".
+func (f jobsFilter) nextType() jobsFilter {
+	for i, t := range jobTypeCycle {
+		if t == f.jobType {
+			f.jobType = jobTypeCycle[(i+1)%len(jobTypeCycle)]
+			return f
+		}
+	}
+	f.jobType = ""
+	return f
+}
+
+// jobsPromptKind distinguishes the free-text inputs JobsModel can push onto
+// its state stack, so one jobsStatePrompt implementation can serve all of
+// them instead of duplicating near-identical text-input handling three
+// times.
+type jobsPromptKind int
+
+const (
+	promptSearch jobsPromptKind = iota
+	promptNode
+	promptPresetName
+)
+
+// JobsModel represents the jobs view. It keeps a stack of jobsStates so
+// `enter`/`esc` can drill from the job list into a job's allocation detail
+// and from there into a follow-mode log pane, plus a submit form pushed
+// directly from the list with `s`.
 type JobsModel struct {
 	client *api.Client
 	jobs   []api.Job
 	err    error
+
+	stack  []jobsState
+	cursor int // selected job index, list state
+	filter jobsFilter
+
+	promptKind  jobsPromptKind
+	promptInput textinput.Model
+
+	presets      []jobPreset
+	presetCursor int
+	presetErr    string
+
+	submit     textarea.Model
+	submitting bool
+	submitErr  string
+
+	detailJob   api.Job
+	allocCursor int // selected allocation index, detail state
+	allocDetail api.AllocDetail
+	allocStats  api.AllocResourceUsage
+	recentLogs  []string
+	detailErr   error
+
+	logViewport viewport.Model
+	logAlloc    api.Alloc
+	logTask     string
+	logReader   io.ReadCloser
+	logBuf      *bufio.Reader
+	logLines    []string
+	logErr      error
+	searching   bool
+	search      string
 }
 
 // NewJobsModel creates a new jobs view model.
 func NewJobsModel(client *api.Client) JobsModel {
-	m := JobsModel{client: client}
+	m := JobsModel{client: client, stack: []jobsState{jobsStateList}}
+	m.presets, _ = loadPresets()
 	m.Refresh()
 	return m
 }
 
-// Refresh fetches the latest jobs.
+// Refresh fetches the latest jobs matching m.filter. The status/type/search
+// parts are pushed down to GetJobs as a Filter expression; node is matched
+// client-side afterward since a job only has a node indirectly, through its
+// allocations.
 func (m *JobsModel) Refresh() {
-	m.jobs, m.err = m.client.GetJobs()
+	jobs, err := m.client.GetJobs(m.filter.expr())
+	if err == nil && m.filter.node != "" {
+		jobs = filterJobsByNode(jobs, m.filter.node)
+	}
+	m.jobs, m.err = jobs, err
+	if m.cursor >= len(m.jobs) {
+		m.cursor = 0
+	}
+}
+
+func filterJobsByNode(jobs []api.Job, node string) []api.Job {
+	var matched []api.Job
+	for _, job := range jobs {
+		for _, alloc := range job.Allocations {
+			if strings.Contains(alloc.NodeName, node) {
+				matched = append(matched, job)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// state returns the view currently on top of the stack.
+func (m JobsModel) state() jobsState {
+	return m.stack[len(m.stack)-1]
+}
+
+func (m *JobsModel) push(s jobsState) {
+	m.stack = append(m.stack, s)
+}
+
+func (m *JobsModel) pop() {
+	if len(m.stack) > 1 {
+		m.stack = m.stack[:len(m.stack)-1]
+	}
+}
+
+// CapturesInput reports whether the jobs view is currently reading raw
+// keystrokes (the submit textarea, or an in-progress log search), so
+// app.Model should route all keys here instead of treating them as global
+// tab-switch/quit shortcuts.
+func (m JobsModel) CapturesInput() bool {
+	switch m.state() {
+	case jobsStateSubmit, jobsStatePrompt:
+		return true
+	case jobsStateLogs:
+		return m.searching
+	default:
+		return false
+	}
+}
+
+// HelpText returns key-help text for the view's current sub-state, for
+// app.Model to fold into its global help bar alongside the tab/quit keys.
+func (m JobsModel) HelpText() string {
+	switch m.state() {
+	case jobsStateSubmit:
+		return styles.RenderKeyHelp("ctrl+s", "submit") + "  " + styles.RenderKeyHelp("esc", "cancel")
+	case jobsStateDetail:
+		return styles.RenderKeyHelp("↑/↓", "select alloc") + "  " + styles.RenderKeyHelp("l", "logs") + "  " + styles.RenderKeyHelp("esc", "back")
+	case jobsStateLogs:
+		if m.searching {
+			return styles.RenderKeyHelp("enter", "search") + "  " + styles.RenderKeyHelp("esc", "cancel")
+		}
+		return styles.RenderKeyHelp("/", "search") + "  " + styles.RenderKeyHelp("esc", "back")
+	case jobsStatePrompt:
+		return styles.RenderKeyHelp("enter", "apply") + "  " + styles.RenderKeyHelp("esc", "cancel")
+	case jobsStatePresets:
+		return styles.RenderKeyHelp("↑/↓", "select") + "  " + styles.RenderKeyHelp("enter", "apply") + "  " + styles.RenderKeyHelp("x", "delete") + "  " + styles.RenderKeyHelp("esc", "back")
+	default:
+		return styles.RenderKeyHelp("↑/↓", "select") + "  " + styles.RenderKeyHelp("enter", "inspect") + "  " +
+			styles.RenderKeyHelp("r/p/d", "status") + "  " + styles.RenderKeyHelp("t", "type") + "  " +
+			styles.RenderKeyHelp("n", "node") + "  " + styles.RenderKeyHelp("/", "search") + "  " +
+			styles.RenderKeyHelp("c", "clear") + "  " + styles.RenderKeyHelp("S", "save preset") + "  " +
+			styles.RenderKeyHelp("P", "presets") + "  " + styles.RenderKeyHelp("s", "submit")
+	}
+}
+
+// Update handles a message for whichever sub-view is on top of the stack.
+func (m JobsModel) Update(msg tea.Msg) (JobsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case jobSubmitResultMsg:
+		m.submitting = false
+		if msg.err != nil {
+			m.submitErr = msg.err.Error()
+			return m, nil
+		}
+		m.pop()
+		m.Refresh()
+		return m, nil
+
+	case allocDetailMsg:
+		m.allocDetail = msg.detail
+		m.allocStats = msg.stats
+		m.recentLogs = msg.recentLogs
+		m.detailErr = msg.err
+		return m, nil
+
+	case logStreamMsg:
+		if msg.err != nil {
+			m.logErr = msg.err
+			return m, nil
+		}
+		m.logReader = msg.reader
+		m.logBuf = bufio.NewReader(msg.reader)
+		return m, m.readLogLineCmd()
+
+	case logLineMsg:
+		return m.handleLogLine(msg)
+	}
+
+	return m, nil
+}
+
+func (m JobsModel) handleKey(msg tea.KeyMsg) (JobsModel, tea.Cmd) {
+	switch m.state() {
+	case jobsStateSubmit:
+		return m.handleSubmitKey(msg)
+	case jobsStateDetail:
+		return m.handleDetailKey(msg)
+	case jobsStateLogs:
+		return m.handleLogsKey(msg)
+	case jobsStatePrompt:
+		return m.handlePromptKey(msg)
+	case jobsStatePresets:
+		return m.handlePresetsKey(msg)
+	default:
+		return m.handleListKey(msg)
+	}
+}
+
+func (m JobsModel) handleListKey(msg tea.KeyMsg) (JobsModel, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.jobs)-1 {
+			m.cursor++
+		}
+
+	case "s":
+		m.submit = newJobSpecArea()
+		m.submitErr = ""
+		m.push(jobsStateSubmit)
+
+	case "enter":
+		if m.cursor < len(m.jobs) {
+			m.detailJob = m.jobs[m.cursor]
+			m.allocCursor = 0
+			m.detailErr = nil
+			m.push(jobsStateDetail)
+			return m, m.refreshAllocDetailCmd()
+		}
+
+	case "r":
+		m.filter.status = toggleStatus(m.filter.status, "running")
+		m.Refresh()
+
+	case "p":
+		m.filter.status = toggleStatus(m.filter.status, "pending")
+		m.Refresh()
+
+	case "d":
+		m.filter.status = toggleStatus(m.filter.status, "dead")
+		m.Refresh()
+
+	case "t":
+		m.filter = m.filter.nextType()
+		m.Refresh()
+
+	case "n":
+		m.openPrompt(promptNode, "filter by node name...", m.filter.node)
+
+	case "/":
+		m.openPrompt(promptSearch, "filter by job name...", m.filter.search)
+
+	case "c":
+		m.filter = jobsFilter{}
+		m.Refresh()
+
+	case "S":
+		m.openPrompt(promptPresetName, "preset name...", "")
+
+	case "P":
+		m.presetCursor = 0
+		m.presetErr = ""
+		m.push(jobsStatePresets)
+	}
+
+	return m, nil
+}
+
+// toggleStatus returns "" if current already equals want (so pressing the
+// same filter key twice clears it), otherwise want.
+func toggleStatus(current, want string) string {
+	if current == want {
+		return ""
+	}
+	return want
 }
 
-// View renders the jobs view.
+// openPrompt pushes a jobsStatePrompt for kind, pre-filled with initial.
+func (m *JobsModel) openPrompt(kind jobsPromptKind, placeholder, initial string) {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.SetValue(initial)
+	ti.Focus()
+	m.promptKind = kind
+	m.promptInput = ti
+	m.push(jobsStatePrompt)
+}
+
+func (m JobsModel) handlePromptKey(msg tea.KeyMsg) (JobsModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pop()
+		return m, nil
+
+	case "enter":
+		value := strings.TrimSpace(m.promptInput.Value())
+		kind := m.promptKind
+		m.pop()
+
+		switch kind {
+		case promptSearch:
+			m.filter.search = value
+			m.Refresh()
+		case promptNode:
+			m.filter.node = value
+			m.Refresh()
+		case promptPresetName:
+			if value != "" {
+				m.savePreset(value)
+			}
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
+
+// savePreset appends (or overwrites, by name) a preset for the current
+// filter and persists the whole list to DefaultPresetsFile.
+func (m *JobsModel) savePreset(name string) {
+	preset := presetFromFilter(name, m.filter)
+
+	replaced := false
+	for i, p := range m.presets {
+		if p.Name == name {
+			m.presets[i] = preset
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		m.presets = append(m.presets, preset)
+	}
+
+	if err := savePresets(m.presets); err != nil {
+		m.presetErr = err.Error()
+	}
+}
+
+func (m JobsModel) handlePresetsKey(msg tea.KeyMsg) (JobsModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pop()
+		return m, nil
+
+	case "up", "k":
+		if m.presetCursor > 0 {
+			m.presetCursor--
+		}
+
+	case "down", "j":
+		if m.presetCursor < len(m.presets)-1 {
+			m.presetCursor++
+		}
+
+	case "enter":
+		if m.presetCursor < len(m.presets) {
+			m.filter = m.presets[m.presetCursor].toFilter()
+			m.pop()
+			m.Refresh()
+		}
+
+	case "x":
+		if m.presetCursor < len(m.presets) {
+			m.presets = append(m.presets[:m.presetCursor], m.presets[m.presetCursor+1:]...)
+			if m.presetCursor >= len(m.presets) && m.presetCursor > 0 {
+				m.presetCursor--
+			}
+			if err := savePresets(m.presets); err != nil {
+				m.presetErr = err.Error()
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func newJobSpecArea() textarea.Model {
+	t := textarea.New()
+	t.Placeholder = "job \"example\" {\n  ...\n}"
+	t.ShowLineNumbers = true
+	t.Focus()
+	return t
+}
+
+func (m JobsModel) handleSubmitKey(msg tea.KeyMsg) (JobsModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pop()
+		return m, nil
+
+	case "ctrl+s":
+		spec := m.submit.Value()
+		if strings.TrimSpace(spec) == "" {
+			return m, nil
+		}
+		m.submitting = true
+		m.submitErr = ""
+		isJSON := strings.HasPrefix(strings.TrimSpace(spec), "{")
+		return m, m.submitJobCmd(spec, isJSON)
+	}
+
+	var cmd tea.Cmd
+	m.submit, cmd = m.submit.Update(msg)
+	return m, cmd
+}
+
+// jobSubmitResultMsg carries the result of a SubmitJob call back into Update.
+type jobSubmitResultMsg struct{ err error }
+
+func (m JobsModel) submitJobCmd(spec string, isJSON bool) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		return jobSubmitResultMsg{err: client.SubmitJob(spec, isJSON)}
+	}
+}
+
+func (m JobsModel) handleDetailKey(msg tea.KeyMsg) (JobsModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pop()
+		return m, nil
+
+	case "up", "k":
+		if m.allocCursor > 0 {
+			m.allocCursor--
+			return m, m.refreshAllocDetailCmd()
+		}
+
+	case "down", "j":
+		if m.allocCursor < len(m.detailJob.Allocations)-1 {
+			m.allocCursor++
+			return m, m.refreshAllocDetailCmd()
+		}
+
+	case "l":
+		if m.allocCursor < len(m.detailJob.Allocations) {
+			return m.openLogs(m.detailJob.Allocations[m.allocCursor])
+		}
+	}
+
+	return m, nil
+}
+
+// allocDetailMsg carries a GetAllocDetail/GetAllocStats/TailAllocLogs result
+// back into Update for the selected allocation in the detail view.
+type allocDetailMsg struct {
+	detail     api.AllocDetail
+	stats      api.AllocResourceUsage
+	recentLogs []string
+	err        error
+}
+
+func (m JobsModel) refreshAllocDetailCmd() tea.Cmd {
+	if m.allocCursor >= len(m.detailJob.Allocations) {
+		return nil
+	}
+	client := m.client
+	alloc := m.detailJob.Allocations[m.allocCursor]
+
+	return func() tea.Msg {
+		detail, err := client.GetAllocDetail(alloc.ID)
+		if err != nil {
+			return allocDetailMsg{err: err}
+		}
+		stats, _ := client.GetAllocStats(alloc.ID)
+
+		task := alloc.TaskGroup
+		for name := range detail.TaskStates {
+			task = name
+			break
+		}
+
+		var recentLogs []string
+		if data, err := client.TailAllocLogs(alloc.ID, task, 5, false); err == nil {
+			recentLogs = splitLogLines(string(data))
+		}
+
+		return allocDetailMsg{detail: detail, stats: stats, recentLogs: recentLogs}
+	}
+}
+
+// splitLogLines splits a TailAllocLogs blob into non-empty lines, dropping
+// the trailing newline's empty element.
+func splitLogLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// openLogs pushes the log-follow view for alloc, picking its first known
+// task from allocDetail (populated by refreshAllocDetailCmd) and falling
+// back to the allocation's task group name if that hasn't loaded yet.
+func (m JobsModel) openLogs(alloc api.Alloc) (JobsModel, tea.Cmd) {
+	task := alloc.TaskGroup
+	for name := range m.allocDetail.TaskStates {
+		task = name
+		break
+	}
+
+	m.logViewport = viewport.New(80, 20)
+	m.logAlloc = alloc
+	m.logTask = task
+	m.logLines = nil
+	m.logErr = nil
+	m.searching = false
+	m.search = ""
+	m.push(jobsStateLogs)
+
+	return m, m.openLogStreamCmd(alloc.ID, task)
+}
+
+// logStreamMsg carries the opened StreamAllocLogs reader back into Update.
+type logStreamMsg struct {
+	reader io.ReadCloser
+	err    error
+}
+
+func (m JobsModel) openLogStreamCmd(allocID, task string) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		r, err := client.StreamAllocLogs(allocID, task, true)
+		return logStreamMsg{reader: r, err: err}
+	}
+}
+
+// logLineMsg carries one more line read from the open log stream. Update
+// re-issues readLogLineCmd after each one to keep following, so the log
+// pane fills in as output arrives rather than needing a manual refresh.
+type logLineMsg struct {
+	line string
+	err  error
+}
+
+func (m JobsModel) readLogLineCmd() tea.Cmd {
+	buf := m.logBuf
+	return func() tea.Msg {
+		line, err := buf.ReadString('\n')
+		return logLineMsg{line: line, err: err}
+	}
+}
+
+func (m JobsModel) handleLogLine(msg logLineMsg) (JobsModel, tea.Cmd) {
+	if msg.line != "" {
+		m.logLines = append(m.logLines, strings.TrimRight(msg.line, "\n"))
+		m.logViewport.SetContent(strings.Join(m.logLines, "\n"))
+		m.logViewport.GotoBottom()
+	}
+
+	if msg.err != nil {
+		m.logErr = msg.err
+		return m, nil
+	}
+
+	return m, m.readLogLineCmd()
+}
+
+func (m JobsModel) handleLogsKey(msg tea.KeyMsg) (JobsModel, tea.Cmd) {
+	if m.searching {
+		switch msg.String() {
+		case "enter":
+			m.searching = false
+			m.jumpToSearchMatch()
+			return m, nil
+		case "esc":
+			m.searching = false
+			m.search = ""
+			return m, nil
+		case "backspace":
+			if len(m.search) > 0 {
+				m.search = m.search[:len(m.search)-1]
+			}
+			return m, nil
+		default:
+			if msg.Type == tea.KeyRunes {
+				m.search += string(msg.Runes)
+			}
+			return m, nil
+		}
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.closeLogs()
+		m.pop()
+		return m, nil
+
+	case "/":
+		m.searching = true
+		m.search = ""
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.logViewport, cmd = m.logViewport.Update(msg)
+	return m, cmd
+}
+
+func (m *JobsModel) closeLogs() {
+	if m.logReader != nil {
+		m.logReader.Close()
+		m.logReader = nil
+	}
+}
+
+// jumpToSearchMatch scrolls the log viewport to the most recent line
+// (nearest the bottom) containing m.search.
+func (m *JobsModel) jumpToSearchMatch() {
+	if m.search == "" {
+		return
+	}
+	for i := len(m.logLines) - 1; i >= 0; i-- {
+		if strings.Contains(m.logLines[i], m.search) {
+			m.logViewport.SetYOffset(i)
+			return
+		}
+	}
+}
+
+// View renders whichever sub-view is on top of the stack.
 func (m JobsModel) View() string {
+	switch m.state() {
+	case jobsStateSubmit:
+		return m.renderSubmit()
+	case jobsStateDetail:
+		return m.renderDetail()
+	case jobsStateLogs:
+		return m.renderLogs()
+	case jobsStatePrompt:
+		return m.renderPrompt()
+	case jobsStatePresets:
+		return m.renderPresets()
+	default:
+		return m.renderList()
+	}
+}
+
+func (m JobsModel) renderPrompt() string {
 	var b strings.Builder
 
-	b.WriteString(styles.TitleStyle.Render("Jobs"))
+	title := "Filter"
+	switch m.promptKind {
+	case promptNode:
+		title = "Filter by node"
+	case promptSearch:
+		title = "Filter by name"
+	case promptPresetName:
+		title = "Save preset as"
+	}
+
+	b.WriteString(styles.TitleStyle.Render(title))
 	b.WriteString("\n\n")
+	b.WriteString(m.promptInput.View())
+	return b.String()
+}
+
+func (m JobsModel) renderPresets() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Saved Presets"))
+	b.WriteString("\n\n")
+
+	if m.presetErr != "" {
+		b.WriteString(styles.ErrorStyle.Render("Error: " + m.presetErr))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.presets) == 0 {
+		b.WriteString(styles.DescStyle.Render("No presets saved yet - press 'S' on the jobs list to bookmark the current filter"))
+		return b.String()
+	}
+
+	for i, p := range m.presets {
+		cursor := "  "
+		if i == m.presetCursor {
+			cursor = "> "
+		}
+		line := fmt.Sprintf("%s%-20s %s", cursor, p.Name, styles.DescStyle.Render(p.toFilter().summary()))
+		if i == m.presetCursor {
+			b.WriteString(styles.SelectedItemStyle.Render(line))
+		} else {
+			b.WriteString(styles.ListItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func (m JobsModel) renderList() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Jobs"))
+	b.WriteString("\n")
+	if m.filter.active() {
+		b.WriteString(styles.DescStyle.Render("Filter: " + m.filter.summary()))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	if m.err != nil {
 		b.WriteString(styles.ErrorStyle.Render("Error: " + m.err.Error()))
@@ -47,62 +814,178 @@ func (m JobsModel) View() string {
 	}
 
 	if len(m.jobs) == 0 {
+		if m.filter.active() {
+			b.WriteString("No jobs match the current filter\n")
+			b.WriteString("\n")
+			b.WriteString(styles.DescStyle.Render("Press 'c' to clear filters"))
+			return b.String()
+		}
 		b.WriteString("No jobs running\n")
 		b.WriteString("\n")
-		b.WriteString(styles.DescStyle.Render("Run a job with: nomad job run <job.nomad>"))
+		b.WriteString(styles.DescStyle.Render("Press 's' to submit a jobspec, or run: nomad job run <job.nomad>"))
 		return b.String()
 	}
 
-	// Group jobs by status
-	running := make([]api.Job, 0)
-	other := make([]api.Job, 0)
+	for i, job := range m.jobs {
+		b.WriteString(m.renderJob(job, i == m.cursor))
+	}
 
-	for _, job := range m.jobs {
-		if job.Status == "running" {
-			running = append(running, job)
-		} else {
-			other = append(other, job)
-		}
+	return b.String()
+}
+
+func (m JobsModel) renderJob(job api.Job, selected bool) string {
+	var b strings.Builder
+
+	cursor := "  "
+	if selected {
+		cursor = "> "
 	}
 
-	// Running jobs
-	if len(running) > 0 {
-		b.WriteString(styles.SubtitleStyle.Render("Running"))
-		b.WriteString("\n")
-		for _, job := range running {
-			b.WriteString(m.renderJob(job))
-		}
-		b.WriteString("\n")
+	icon := getJobIcon(job.Status)
+	jobType := styles.DescStyle.Render("[" + job.Type + "]")
+	line := fmt.Sprintf("%s%s %-20s %s", cursor, icon, job.Name, jobType)
+	if selected {
+		b.WriteString(styles.SelectedItemStyle.Render(line))
+	} else {
+		b.WriteString(styles.ListItemStyle.Render(line))
 	}
+	b.WriteString("\n")
 
-	// Other jobs
-	if len(other) > 0 {
-		b.WriteString(styles.SubtitleStyle.Render("Other"))
-		b.WriteString("\n")
-		for _, job := range other {
-			b.WriteString(m.renderJob(job))
+	for _, alloc := range job.Allocations {
+		allocIcon := getAllocIcon(alloc.ClientStatus)
+		shortID := alloc.ID
+		if len(shortID) > 8 {
+			shortID = shortID[:8]
 		}
+		nodeName := styles.DescStyle.Render("on " + alloc.NodeName)
+		b.WriteString(fmt.Sprintf("        %s %s %s\n", allocIcon, shortID, nodeName))
 	}
 
 	return b.String()
 }
 
-func (m JobsModel) renderJob(job api.Job) string {
+func (m JobsModel) renderSubmit() string {
 	var b strings.Builder
 
-	icon := getJobIcon(job.Status)
-	jobType := styles.DescStyle.Render("[" + job.Type + "]")
-	b.WriteString(fmt.Sprintf("  %s %-20s %s\n", icon, job.Name, jobType))
+	b.WriteString(styles.TitleStyle.Render("Submit Job"))
+	b.WriteString("\n\n")
+	b.WriteString(m.submit.View())
+	b.WriteString("\n\n")
 
-	// Show allocations
-	for _, alloc := range job.Allocations {
+	switch {
+	case m.submitting:
+		b.WriteString(styles.DescStyle.Render("Submitting..."))
+	case m.submitErr != "":
+		b.WriteString(styles.ErrorStyle.Render("Error: " + m.submitErr))
+	default:
+		b.WriteString(styles.DescStyle.Render("Paste an HCL or JSON jobspec, then ctrl+s to register it"))
+	}
+
+	return b.String()
+}
+
+func (m JobsModel) renderDetail() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Job: " + m.detailJob.Name))
+	b.WriteString("\n\n")
+
+	if m.detailErr != nil {
+		b.WriteString(styles.ErrorStyle.Render("Error: " + m.detailErr.Error()))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(styles.SubtitleStyle.Render("Allocations"))
+	b.WriteString("\n")
+	for i, alloc := range m.detailJob.Allocations {
+		cursor := "  "
+		if i == m.allocCursor {
+			cursor = "> "
+		}
 		allocIcon := getAllocIcon(alloc.ClientStatus)
 		shortID := alloc.ID
 		if len(shortID) > 8 {
 			shortID = shortID[:8]
 		}
-		nodeName := styles.DescStyle.Render("on " + alloc.NodeName)
-		b.WriteString(fmt.Sprintf("      %s %s %s\n", allocIcon, shortID, nodeName))
+		line := fmt.Sprintf("%s%s %s %-12s on %s", cursor, allocIcon, shortID, alloc.TaskGroup, alloc.NodeName)
+		if i == m.allocCursor {
+			b.WriteString(styles.SelectedItemStyle.Render(line))
+		} else {
+			b.WriteString(styles.ListItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(m.allocDetail.TaskStates) > 0 {
+		b.WriteString("\n")
+		b.WriteString(styles.SubtitleStyle.Render("Tasks"))
+		b.WriteString("\n")
+		for name, ts := range m.allocDetail.TaskStates {
+			usage := ""
+			if stats, ok := m.allocStats.Tasks[name]; ok {
+				usage = fmt.Sprintf("  cpu=%.1f%% mem=%dMB",
+					stats.ResourceUsage.CpuStats.Percent, stats.ResourceUsage.MemoryStats.RSS/1024/1024)
+			}
+			b.WriteString(fmt.Sprintf("  %s: %s%s\n", name, ts.State, usage))
+
+			for _, ev := range recentTaskEvents(ts.Events, 3) {
+				b.WriteString(styles.DescStyle.Render(fmt.Sprintf("      %s: %s", ev.Type, ev.DisplayMessage)))
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if len(m.recentLogs) > 0 {
+		b.WriteString("\n")
+		b.WriteString(styles.SubtitleStyle.Render("Recent Logs"))
+		b.WriteString("\n")
+		for _, line := range m.recentLogs {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString(styles.DescStyle.Render("Press 'l' for the full, following log view"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// recentTaskEvents returns up to n of events' most recent entries, newest
+// first - Nomad appends to TaskState.Events in chronological order.
+func recentTaskEvents(events []api.TaskEvent, n int) []api.TaskEvent {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ordered := make([]api.TaskEvent, len(events))
+	copy(ordered, events)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Time > ordered[j].Time })
+
+	if len(ordered) > n {
+		ordered = ordered[:n]
+	}
+	return ordered
+}
+
+func (m JobsModel) renderLogs() string {
+	var b strings.Builder
+
+	shortID := m.logAlloc.ID
+	if len(shortID) > 8 {
+		shortID = shortID[:8]
+	}
+	b.WriteString(styles.TitleStyle.Render(fmt.Sprintf("Logs: %s/%s", shortID, m.logTask)))
+	b.WriteString("\n\n")
+
+	if m.logErr != nil {
+		b.WriteString(styles.ErrorStyle.Render("Error: " + m.logErr.Error()))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(m.logViewport.View())
+	b.WriteString("\n")
+
+	if m.searching {
+		b.WriteString(styles.DescStyle.Render("/" + m.search))
 	}
 
 	return b.String()
@@ -0,0 +1,77 @@
+package views
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// DefaultPresetsFile is where JobsModel persists saved filter presets -
+// ~/.styx/tui/presets.json. Presets are a client-side convenience (which
+// combination of status/type/node/search an operator likes to jump back
+// to), not cluster state, so they live alongside the TUI rather than under
+// the daemon's own config/data directories.
+var DefaultPresetsFile = func() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".styx", "tui", "presets.json")
+}()
+
+// jobPreset is one bookmarked jobsFilter, the way a web dashboard bookmarks
+// a query string.
+type jobPreset struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Type   string `json:"type"`
+	Node   string `json:"node"`
+	Search string `json:"search"`
+}
+
+func (p jobPreset) toFilter() jobsFilter {
+	return jobsFilter{status: p.Status, jobType: p.Type, node: p.Node, search: p.Search}
+}
+
+func presetFromFilter(name string, f jobsFilter) jobPreset {
+	return jobPreset{Name: name, Status: f.status, Type: f.jobType, Node: f.node, Search: f.search}
+}
+
+// loadPresets reads DefaultPresetsFile. A missing file isn't an error - it
+// just means nothing has been bookmarked yet.
+func loadPresets() ([]jobPreset, error) {
+	path := DefaultPresetsFile
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var presets []jobPreset
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+func savePresets(presets []jobPreset) error {
+	path := DefaultPresetsFile
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(presets, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -24,7 +24,7 @@ func NewServicesModel(client *api.Client) ServicesModel {
 
 // Refresh fetches the latest services status.
 func (m *ServicesModel) Refresh() {
-	m.services, m.err = m.client.GetPlatformServices()
+	m.services, m.err = m.client.GetPlatformServices("")
 }
 
 // View renders the services view.
@@ -0,0 +1,62 @@
+// Package upgrade records the progress of a `styx upgrade` run to disk, so
+// GetClusterStatus (internal/api) can report ClusterStatus.Upgrading and
+// ClusterStatus.TargetVersion to the TUI even though the upgrade itself runs
+// in a separate `styx upgrade` process.
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const stateFile = "upgrade-state.json"
+
+// State describes an in-progress rolling upgrade.
+type State struct {
+	TargetVersion string    `json:"target_version"`
+	StartedAt     time.Time `json:"started_at"`
+}
+
+// WriteState persists s to dir, overwriting any previous state. Called when
+// `styx upgrade` begins.
+func WriteState(dir string, s State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode upgrade state: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create upgrade state directory: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, stateFile), data, 0600)
+}
+
+// ReadState loads the upgrade state written by WriteState, if any. A
+// missing file means no upgrade is in progress and is not an error.
+func ReadState(dir string) (*State, bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read upgrade state: %w", err)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, false, fmt.Errorf("failed to parse upgrade state: %w", err)
+	}
+	return &s, true, nil
+}
+
+// ClearState removes the upgrade state file, called once `styx upgrade`
+// finishes (successfully or after rolling back).
+func ClearState(dir string) error {
+	err := os.Remove(filepath.Join(dir, stateFile))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear upgrade state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package vault
+
+import (
+	"fmt"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// apiStore implements SecretStore over the HTTP API shared by HashiCorp
+// Vault and OpenBao, using the official Go client instead of shelling out
+// to the `vault` CLI. This also eliminates the fragile exit-code-2 parsing
+// the old CLI-based GetStatus needed: Sys().SealStatus() returns a normal
+// 200 response with Sealed: true rather than a nonzero exit code.
+type apiStore struct {
+	client *vaultapi.Client
+}
+
+// newAPIStore creates an apiStore pointed at addr. It has no token
+// configured; call WithToken before any operation other than Status.
+func newAPIStore(addr string) (*apiStore, error) {
+	config := vaultapi.DefaultConfig()
+	config.Address = addr
+	client, err := vaultapi.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault API client for %s: %w", addr, err)
+	}
+	return &apiStore{client: client}, nil
+}
+
+func (s *apiStore) WithToken(token string) SecretStore {
+	s.client.SetToken(token)
+	return s
+}
+
+func (s *apiStore) Initialize(shares, threshold int) (*InitOutput, error) {
+	resp, err := s.client.Sys().Init(&vaultapi.InitRequest{
+		SecretShares:    shares,
+		SecretThreshold: threshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("vault init request failed: %w", err)
+	}
+	return &InitOutput{UnsealKeysB64: resp.KeysB64, RootToken: resp.RootToken}, nil
+}
+
+func (s *apiStore) Unseal(key string) (bool, error) {
+	resp, err := s.client.Sys().Unseal(key)
+	if err != nil {
+		return false, fmt.Errorf("unseal request failed: %w", err)
+	}
+	return resp.Sealed, nil
+}
+
+func (s *apiStore) Status() (*VaultStatus, error) {
+	status, err := s.client.Sys().SealStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check vault status: %w", err)
+	}
+	return &VaultStatus{Initialized: status.Initialized, Sealed: status.Sealed}, nil
+}
+
+func (s *apiStore) KVPut(path string, data map[string]string) error {
+	values := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		values[k] = v
+	}
+	if _, err := s.client.Logical().Write("secret/data/"+path, map[string]interface{}{"data": values}); err != nil {
+		return fmt.Errorf("failed to write secret at %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s *apiStore) KVGet(path string) (map[string]string, error) {
+	secret, err := s.client.Logical().Read("secret/data/" + path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret at %s: %w", path, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no secret found at %s", path)
+	}
+
+	raw, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected secret shape at %s", path)
+	}
+	out := make(map[string]string, len(raw))
+	for k, v := range raw {
+		str, _ := v.(string)
+		out[k] = str
+	}
+	return out, nil
+}
+
+// EnableAuth mounts method at path when config is nil, or writes config to
+// the mount's config endpoint when it's not - two separate calls rather
+// than one that does both, so configuring an already-mounted auth method
+// (the common case on every run after the first) doesn't also re-attempt
+// the mount.
+func (s *apiStore) EnableAuth(method, path string, config map[string]string) error {
+	if config == nil {
+		err := s.client.Sys().EnableAuthWithOptions(path, &vaultapi.EnableAuthOptions{Type: method})
+		if err != nil && !strings.Contains(err.Error(), "path is already in use") {
+			return fmt.Errorf("failed to enable %s auth at %s: %w", method, path, err)
+		}
+		return nil
+	}
+
+	values := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		values[k] = v
+	}
+	if _, err := s.client.Logical().Write("auth/"+path+"/config", values); err != nil {
+		return fmt.Errorf("failed to configure %s auth at %s: %w", method, path, err)
+	}
+	return nil
+}
+
+func (s *apiStore) WritePolicy(name, policy string) error {
+	if err := s.client.Sys().PutPolicy(name, policy); err != nil {
+		return fmt.Errorf("failed to write policy %s: %w", name, err)
+	}
+	return nil
+}
+
+// MountKV enables the kv-v2 secrets engine at "secret" (a no-op if already
+// enabled). It's not part of SecretStore since only HTTP-API backends need
+// an explicit mount step - devStore's KV store always exists - so
+// SetupNomadIntegration type-asserts for it via the kvMounter interface.
+func (s *apiStore) MountKV() error {
+	err := s.client.Sys().Mount("secret", &vaultapi.MountInput{Type: "kv-v2"})
+	if err != nil && !strings.Contains(err.Error(), "path is already in use") {
+		return fmt.Errorf("failed to enable kv-v2 secrets engine: %w", err)
+	}
+	return nil
+}
+
+// WriteAuthRole configures an auth method's role bindings, e.g.
+// auth/jwt-nomad/role/nomad-workloads. It's not part of SecretStore since
+// it's JWT/Nomad-specific rather than a generic secrets-store verb;
+// SetupNomadIntegration type-asserts for it via the roleWriter interface
+// and skips role configuration on backends that don't implement it.
+func (s *apiStore) WriteAuthRole(authPath, role string, fields map[string]string) error {
+	values := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		values[k] = v
+	}
+	if _, err := s.client.Logical().Write(fmt.Sprintf("auth/%s/role/%s", authPath, role), values); err != nil {
+		return fmt.Errorf("failed to write auth role %s/%s: %w", authPath, role, err)
+	}
+	return nil
+}
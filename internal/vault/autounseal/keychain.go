@@ -0,0 +1,77 @@
+package autounseal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// keychainService is the macOS Keychain service name the init blob is
+// stored under, matching the convention internal/vault's legacy
+// DestinationKeychain already used.
+const keychainService = "styx-vault-init"
+
+// KeychainProvider stores the init blob as a generic password in the
+// macOS login Keychain via the `security` CLI, access-controlled with
+// `-T ""` so the OS prompts the user to confirm (Touch ID, if the Mac has
+// it enrolled and the user's password fallback otherwise) before any
+// process - including Styx itself on a later run - can read it back.
+//
+// A production build targeting Touch ID specifically rather than "some
+// confirmation" would use github.com/keybase/go-keychain's
+// SetAccessControl, which can require LAContext biometry explicitly;
+// `security` only exposes the coarser ACL used here.
+type KeychainProvider struct {
+	// Account overrides the Keychain item's account attribute; defaults
+	// to $USER.
+	Account string
+}
+
+// Name implements Provider.
+func (p *KeychainProvider) Name() string { return "keychain" }
+
+// Store implements Provider.
+func (p *KeychainProvider) Store(data []byte) error {
+	account, err := p.account()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("security", "add-generic-password",
+		"-s", keychainService,
+		"-a", account,
+		"-w", string(data),
+		"-T", "", // require user confirmation before any app can read this item
+		"-U", // update in place if an item already exists
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store vault init blob in keychain: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// Load implements Provider.
+func (p *KeychainProvider) Load() ([]byte, error) {
+	account, err := p.account()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", account, "-w")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault init blob from keychain: %w", err)
+	}
+	return output, nil
+}
+
+func (p *KeychainProvider) account() (string, error) {
+	if p.Account != "" {
+		return p.Account, nil
+	}
+	user := os.Getenv("USER")
+	if user == "" {
+		return "", fmt.Errorf("USER environment variable not set, cannot address keychain item")
+	}
+	return user, nil
+}
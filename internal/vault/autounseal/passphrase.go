@@ -0,0 +1,128 @@
+package autounseal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// passphraseBlob is what PassphraseProvider persists at BlobPath: the
+// ciphertext plus the salt and nonce needed to re-derive the same AES key
+// from the operator's passphrase at Load time. The passphrase itself is
+// never stored.
+type passphraseBlob struct {
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+)
+
+// PassphraseProvider wraps the init blob with AES-256-GCM under a key
+// derived from an operator-supplied passphrase via scrypt, so no cloud KMS
+// or second Vault is needed to protect it at rest - just something the
+// operator remembers instead of Styx storing it anywhere.
+type PassphraseProvider struct {
+	// Passphrase is the operator-supplied secret the AES key is derived
+	// from. Required for both Store and Load; never persisted in the blob.
+	Passphrase string
+	// BlobPath is where the ciphertext blob is written/read.
+	BlobPath string
+}
+
+// NewPassphraseProvider returns a PassphraseProvider ready to Store.
+func NewPassphraseProvider(passphrase, blobPath string) *PassphraseProvider {
+	return &PassphraseProvider{Passphrase: passphrase, BlobPath: blobPath}
+}
+
+// Name implements Provider.
+func (p *PassphraseProvider) Name() string { return "passphrase" }
+
+// Store implements Provider.
+func (p *PassphraseProvider) Store(data []byte) error {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := p.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	ciphertext := gcm.Seal(nil, nonce, data, nil)
+
+	blob := passphraseBlob{
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}
+	raw, err := json.Marshal(blob)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.BlobPath, raw, 0600)
+}
+
+// Load implements Provider.
+func (p *PassphraseProvider) Load() ([]byte, error) {
+	raw, err := os.ReadFile(p.BlobPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase blob: %w", err)
+	}
+
+	var blob passphraseBlob
+	if err := json.Unmarshal(raw, &blob); err != nil {
+		return nil, fmt.Errorf("failed to parse passphrase blob: %w", err)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(blob.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode salt: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(blob.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(blob.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	gcm, err := p.cipher(salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault init blob: wrong passphrase or corrupted blob: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (p *PassphraseProvider) cipher(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(p.Passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key from passphrase: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
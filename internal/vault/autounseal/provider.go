@@ -0,0 +1,26 @@
+// Package autounseal provides alternatives to storing Vault's unseal keys
+// and root token as a plaintext vault-init.json file: a Provider persists
+// the same JSON blob (see vault.InitOutput) behind some protection
+// mechanism, so a stolen disk or laptop doesn't hand over the keys to
+// Styx's Vault along with it.
+//
+// Providers operate on the raw blob rather than vault.InitOutput directly
+// so this package doesn't need to import internal/vault (which imports
+// this package to pick a Provider from its BootstrapOptions).
+package autounseal
+
+// Provider persists and retrieves the Vault init blob by one protection
+// mechanism. Store/Load round-trip the exact bytes passed in; callers are
+// responsible for whatever encoding (json.Marshal of vault.InitOutput)
+// the blob uses.
+type Provider interface {
+	// Name identifies the provider for flags and diagnostics (e.g.
+	// "keychain", "awskms", "gcpckms", "shamir").
+	Name() string
+
+	// Store persists data, overwriting any previous blob.
+	Store(data []byte) error
+
+	// Load retrieves the most recently stored blob.
+	Load() ([]byte, error)
+}
@@ -0,0 +1,164 @@
+package autounseal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/vault/shamir"
+)
+
+// SharePort is the port ShareServer listens on for share deposit/fetch
+// requests, on the node's Tailscale IP (see internal/bootstrap.Port for
+// the analogous convention on the bootstrap credential server).
+const SharePort = 19997
+
+// shamirMeta is what ShamirProvider persists at MetaPath: just enough to
+// re-run Load later without the caller re-supplying Peers/Threshold. It
+// reveals who holds shares, not the secret itself, so it's safe to leave
+// on disk in the clear - which is the whole point of splitting the blob
+// across peers instead.
+type shamirMeta struct {
+	Owner     string   `json:"owner"`
+	Peers     []string `json:"peers"`
+	Threshold int      `json:"threshold"`
+}
+
+// ShamirProvider splits the init blob via Shamir's Secret Sharing
+// (hashicorp/vault/shamir - the same algorithm Vault itself uses for
+// operator unseal keys, applied here to Styx's own stored copy) and
+// deposits one share with each of Peers over Tailscale. No single
+// machine, including this one once Store returns, holds enough shares to
+// reconstruct the blob: Threshold of the Peers must be reachable to do
+// it. This protects the stored secret from a single stolen/compromised
+// node rather than from Vault's own seal.
+type ShamirProvider struct {
+	// Owner identifies whose shares to deposit/fetch, for ShareServer to
+	// key storage by when it holds shares for more than one node.
+	Owner string
+	// Peers are the Tailscale IPs of the other share-holders.
+	Peers []string
+	// Threshold is how many of Peers must respond for Load to reconstruct
+	// the blob.
+	Threshold int
+	// MetaPath is where {Owner, Peers, Threshold} are persisted so Load
+	// can run without Peers/Threshold being supplied again (e.g. on a
+	// fresh `styx vault unseal` after a reboot).
+	MetaPath string
+
+	client *http.Client
+}
+
+// NewShamirProvider returns a ShamirProvider ready to Store. Load can also
+// be called on a zero-value ShamirProvider{MetaPath: ...}; it reads
+// Owner/Peers/Threshold back from MetaPath first.
+func NewShamirProvider(owner string, peers []string, threshold int, metaPath string) *ShamirProvider {
+	return &ShamirProvider{Owner: owner, Peers: peers, Threshold: threshold, MetaPath: metaPath}
+}
+
+// Name implements Provider.
+func (p *ShamirProvider) Name() string { return "shamir" }
+
+// Store implements Provider.
+func (p *ShamirProvider) Store(data []byte) error {
+	if p.Threshold < 1 || p.Threshold > len(p.Peers) {
+		return fmt.Errorf("shamir provider needs threshold between 1 and len(peers)=%d, got %d", len(p.Peers), p.Threshold)
+	}
+
+	shares, err := shamir.Split(data, len(p.Peers), p.Threshold)
+	if err != nil {
+		return fmt.Errorf("failed to split init blob into %d shares: %w", len(p.Peers), err)
+	}
+
+	for i, peer := range p.Peers {
+		if err := p.httpClient().depositShare(peer, p.Owner, shares[i]); err != nil {
+			return fmt.Errorf("failed to deposit share with peer %s: %w", peer, err)
+		}
+	}
+
+	meta := shamirMeta{Owner: p.Owner, Peers: p.Peers, Threshold: p.Threshold}
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.MetaPath, raw, 0644)
+}
+
+// Load implements Provider.
+func (p *ShamirProvider) Load() ([]byte, error) {
+	if len(p.Peers) == 0 {
+		meta, err := p.loadMeta()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load shamir peer list: %w", err)
+		}
+		p.Owner, p.Peers, p.Threshold = meta.Owner, meta.Peers, meta.Threshold
+	}
+
+	var shares [][]byte
+	for _, peer := range p.Peers {
+		share, err := p.httpClient().fetchShare(peer, p.Owner)
+		if err != nil {
+			continue
+		}
+		shares = append(shares, share)
+		if len(shares) >= p.Threshold {
+			break
+		}
+	}
+	if len(shares) < p.Threshold {
+		return nil, fmt.Errorf("only reached %d of %d required share-holding peers", len(shares), p.Threshold)
+	}
+
+	return shamir.Combine(shares)
+}
+
+func (p *ShamirProvider) loadMeta() (shamirMeta, error) {
+	var meta shamirMeta
+	raw, err := os.ReadFile(p.MetaPath)
+	if err != nil {
+		return meta, err
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return meta, fmt.Errorf("failed to parse %s: %w", p.MetaPath, err)
+	}
+	return meta, nil
+}
+
+type shamirHTTPClient struct{ *http.Client }
+
+func (p *ShamirProvider) httpClient() shamirHTTPClient {
+	if p.client == nil {
+		p.client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return shamirHTTPClient{p.client}
+}
+
+func (c shamirHTTPClient) depositShare(peer, owner string, share []byte) error {
+	url := fmt.Sprintf("http://%s:%d/autounseal/shamir-share?owner=%s", peer, SharePort, owner)
+	resp, err := c.Post(url, "application/octet-stream", bytes.NewReader(share))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("peer returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (c shamirHTTPClient) fetchShare(peer, owner string) ([]byte, error) {
+	url := fmt.Sprintf("http://%s:%d/autounseal/shamir-share?owner=%s", peer, SharePort, owner)
+	resp, err := c.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("peer returned %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
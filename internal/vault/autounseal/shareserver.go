@@ -0,0 +1,107 @@
+package autounseal
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/kessler-frost/styx/internal/network"
+)
+
+// ShareServer holds Shamir shares deposited by other nodes' ShamirProvider
+// and serves them back by owner. It doesn't know what a share encodes, or
+// how many a given owner needs to reconstruct anything - it's just
+// storage, keyed by the owner tag the depositing node chose. Every Styx
+// server runs one (see cmd/styx's supervise command), so any node can list
+// other servers as ShamirProvider.Peers.
+type ShareServer struct {
+	// Dir is where received shares are written, one file per owner.
+	Dir string
+
+	listener net.Listener
+}
+
+// NewShareServer creates a ShareServer rooted at dir.
+func NewShareServer(dir string) *ShareServer {
+	return &ShareServer{Dir: dir}
+}
+
+// Start binds SharePort on this node's Tailscale IP and begins serving in
+// the background. Returns an error if Tailscale isn't up - a ShareServer
+// only makes sense on the same network ShamirProvider's peers reach each
+// other over.
+func (s *ShareServer) Start() error {
+	tsInfo := network.GetTailscaleInfo()
+	if !tsInfo.Running {
+		return fmt.Errorf("tailscale is not running, cannot start shamir share server")
+	}
+
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create share directory: %w", err)
+	}
+
+	addr := fmt.Sprintf("%s:%d", tsInfo.IP, SharePort)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/autounseal/shamir-share", s.handleShare)
+	go http.Serve(listener, mux)
+
+	return nil
+}
+
+// Stop closes the listener.
+func (s *ShareServer) Stop() {
+	if s.listener != nil {
+		s.listener.Close()
+	}
+}
+
+func (s *ShareServer) handleShare(w http.ResponseWriter, r *http.Request) {
+	owner := r.URL.Query().Get("owner")
+	if owner == "" {
+		http.Error(w, "missing owner query parameter", http.StatusBadRequest)
+		return
+	}
+	path := s.sharePath(owner)
+
+	switch r.Method {
+	case http.MethodPost:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read share", http.StatusBadRequest)
+			return
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			http.Error(w, "failed to store share", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+
+	case http.MethodGet:
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			http.Error(w, "no share held for this owner", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to read share", http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *ShareServer) sharePath(owner string) string {
+	return filepath.Join(s.Dir, "shamir-share-"+filepath.Base(owner))
+}
@@ -0,0 +1,160 @@
+package autounseal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// transitBlob is what TransitProvider persists at BlobPath: the ciphertext
+// plus enough metadata to decrypt it again without the caller re-supplying
+// Addr/KeyName. Token is deliberately not included - unlike Addr/KeyName,
+// it authorizes decryption, so it must be supplied fresh at Load time
+// rather than sitting next to the ciphertext it protects.
+type transitBlob struct {
+	Addr       string `json:"addr"`
+	KeyName    string `json:"key_name"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// TransitProvider persists the init blob as ciphertext encrypted via a
+// second Vault's transit secrets engine - the same auto-unseal mechanism
+// Vault's own `seal "transit"` stanza uses. Styx's own Vault never holds a
+// key capable of decrypting its own unseal keys; that capability lives
+// entirely on the second Vault.
+type TransitProvider struct {
+	// Addr is the second Vault's address, e.g. http://100.x.y.z:8200.
+	Addr string
+	// KeyName is the transit key to encrypt/decrypt under.
+	KeyName string
+	// Token authorizes the transit encrypt/decrypt calls. Required for
+	// both Store and Load; never persisted in the blob.
+	Token string
+	// BlobPath is where the ciphertext blob is written/read.
+	BlobPath string
+
+	client *http.Client
+}
+
+// NewTransitProvider returns a TransitProvider ready to Store.
+func NewTransitProvider(addr, keyName, token, blobPath string) *TransitProvider {
+	return &TransitProvider{Addr: addr, KeyName: keyName, Token: token, BlobPath: blobPath}
+}
+
+// Name implements Provider.
+func (p *TransitProvider) Name() string { return "transit" }
+
+// Store implements Provider.
+func (p *TransitProvider) Store(data []byte) error {
+	ciphertext, err := p.encrypt(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt vault init blob via transit: %w", err)
+	}
+
+	blob := transitBlob{Addr: p.Addr, KeyName: p.KeyName, Ciphertext: ciphertext}
+	raw, err := json.Marshal(blob)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.BlobPath, raw, 0600)
+}
+
+// Load implements Provider.
+func (p *TransitProvider) Load() ([]byte, error) {
+	raw, err := os.ReadFile(p.BlobPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transit blob: %w", err)
+	}
+
+	var blob transitBlob
+	if err := json.Unmarshal(raw, &blob); err != nil {
+		return nil, fmt.Errorf("failed to parse transit blob: %w", err)
+	}
+	if p.Token == "" {
+		return nil, fmt.Errorf("transit provider requires a token to decrypt (not persisted in the blob)")
+	}
+	p.Addr, p.KeyName = blob.Addr, blob.KeyName
+
+	plaintext, err := p.decrypt(blob.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault init blob via transit: %w", err)
+	}
+	return plaintext, nil
+}
+
+// TransitBlobMeta reads a TransitProvider blob's Addr/KeyName without
+// decrypting it, for callers (like a rekey) that need to persist a
+// replacement blob to the same second Vault/key but don't otherwise have
+// those values at hand.
+func TransitBlobMeta(blobPath string) (addr, keyName string, err error) {
+	raw, err := os.ReadFile(blobPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read transit blob: %w", err)
+	}
+	var blob transitBlob
+	if err := json.Unmarshal(raw, &blob); err != nil {
+		return "", "", fmt.Errorf("failed to parse transit blob: %w", err)
+	}
+	return blob.Addr, blob.KeyName, nil
+}
+
+func (p *TransitProvider) httpClient() *http.Client {
+	if p.client == nil {
+		p.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return p.client
+}
+
+func (p *TransitProvider) encrypt(plaintext []byte) (string, error) {
+	var result struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	err := p.call("encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	}, &result)
+	return result.Data.Ciphertext, err
+}
+
+func (p *TransitProvider) decrypt(ciphertext string) ([]byte, error) {
+	var result struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := p.call("decrypt", map[string]string{"ciphertext": ciphertext}, &result); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(result.Data.Plaintext)
+}
+
+func (p *TransitProvider) call(op string, body map[string]string, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/transit/%s/%s", p.Addr, op, p.KeyName)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("transit %s request failed (status %d)", op, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,689 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/tracing"
+	"github.com/kessler-frost/styx/internal/vault/autounseal"
+)
+
+const vaultAddr = "http://127.0.0.1:8200"
+
+// KeyDestination selects where a Bootstrapper persists unseal keys and the
+// root token after Vault is initialized.
+type KeyDestination string
+
+const (
+	// DestinationFile writes a single vault-init.json with 0600 perms. This
+	// is the default and matches what the old CLI-based Initialize used.
+	DestinationFile KeyDestination = "file"
+	// DestinationKeychain stores the init output as a single JSON blob in
+	// the macOS login Keychain via the `security` CLI.
+	DestinationKeychain KeyDestination = "keychain"
+	// DestinationShamirFiles writes each unseal key share to its own file
+	// (vault-unseal-key-1, vault-unseal-key-2, ...) plus a separate root
+	// token file, so shares can be handed to different operators/machines.
+	DestinationShamirFiles KeyDestination = "shamir-files"
+	// DestinationAWSKMS encrypts the init output under an AWS KMS key and
+	// writes only the ciphertext to disk (internal/vault/autounseal).
+	DestinationAWSKMS KeyDestination = "awskms"
+	// DestinationGCPKMS encrypts the init output under a Google Cloud KMS
+	// key and writes only the ciphertext to disk (internal/vault/autounseal).
+	DestinationGCPKMS KeyDestination = "gcpckms"
+	// DestinationShamir splits the init output with Shamir's Secret Sharing
+	// and deposits one share per peer over Tailscale
+	// (internal/vault/autounseal), so no single machine's disk holds enough
+	// to reconstruct it. Distinct from DestinationShamirFiles, which writes
+	// all shares locally for an operator to distribute by hand.
+	DestinationShamir KeyDestination = "shamir"
+	// DestinationTransit encrypts the init output via a second Vault's
+	// transit secrets engine (internal/vault/autounseal), so decrypting it
+	// depends on reaching that Vault rather than trusting anything stored
+	// on this host.
+	DestinationTransit KeyDestination = "transit"
+	// DestinationPassphrase wraps the init output with scrypt+AES-GCM
+	// under an operator-supplied passphrase (internal/vault/autounseal),
+	// for operators who don't have a cloud KMS or second Vault handy.
+	DestinationPassphrase KeyDestination = "passphrase"
+)
+
+// BootstrapOptions configures a Bootstrapper.
+type BootstrapOptions struct {
+	SecretShares    int
+	SecretThreshold int
+	Destination     KeyDestination
+	SecretsDir      string
+
+	// KMSKeyID is the AWS/GCP key to encrypt under, for
+	// DestinationAWSKMS/DestinationGCPKMS. Only needed when persisting for
+	// the first time; Rotate reuses whatever key the existing blob names.
+	KMSKeyID string
+	// ShamirPeers are the Tailscale IPs to deposit shares with, for
+	// DestinationShamir. Only needed when persisting for the first time;
+	// Rotate reuses whatever peers the existing meta file names.
+	ShamirPeers []string
+	// ShamirThreshold is how many of ShamirPeers must be reachable to
+	// reconstruct the init output, for DestinationShamir.
+	ShamirThreshold int
+
+	// TransitAddr, TransitKeyName, and TransitToken configure
+	// DestinationTransit. TransitToken authorizes the encrypt/decrypt
+	// calls; like KMSKeyID/ShamirPeers, only TransitAddr/TransitKeyName
+	// are needed again after the first Store - Rotate reuses whatever the
+	// existing blob names - but TransitToken must be supplied every time,
+	// since it's never persisted alongside the ciphertext it protects.
+	TransitAddr    string
+	TransitKeyName string
+	TransitToken   string
+
+	// Passphrase wraps the init output for DestinationPassphrase. Like
+	// TransitToken, it authorizes decryption and so must be supplied every
+	// time rather than just on first Store.
+	Passphrase string
+}
+
+// DefaultBootstrapOptions returns the options `styx init` uses when the
+// operator doesn't override them: 5 key shares with a threshold of 3 -
+// losing any two shares (or any one disclosed) doesn't compromise or
+// strand the install - written to separate files.
+func DefaultBootstrapOptions(secretsDir string) BootstrapOptions {
+	return BootstrapOptions{
+		SecretShares:    5,
+		SecretThreshold: 3,
+		Destination:     DestinationShamirFiles,
+		SecretsDir:      secretsDir,
+	}
+}
+
+// Bootstrapper drives Vault through its first-boot lifecycle over the HTTP
+// API: init, persist the unseal keys/root token, unseal, and provision the
+// classic nomad-cluster token role that SetupNomadIntegration's JWT-based
+// workload identities don't cover.
+type Bootstrapper struct {
+	opts   BootstrapOptions
+	client *http.Client
+}
+
+// NewBootstrapper creates a Bootstrapper with the given options.
+func NewBootstrapper(opts BootstrapOptions) *Bootstrapper {
+	if opts.SecretShares == 0 {
+		opts.SecretShares = 1
+	}
+	if opts.SecretThreshold == 0 {
+		opts.SecretThreshold = 1
+	}
+	if opts.Destination == "" {
+		opts.Destination = DestinationFile
+	}
+	return &Bootstrapper{
+		opts:   opts,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Run initializes Vault if needed, persists the unseal keys and root token,
+// unseals Vault, and provisions the nomad-cluster Vault integration. It is
+// safe to call on an already-initialized Vault: init is skipped and the
+// stored init output is reloaded instead.
+func (b *Bootstrapper) Run() (*InitOutput, error) {
+	ctx, span := tracing.StartSpan(context.Background(), "vault.bootstrap.run")
+	defer span.End()
+
+	status, err := GetStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check vault status: %w", err)
+	}
+
+	var out *InitOutput
+	if status.Initialized {
+		out, err = loadInitOutput(b.opts.SecretsDir)
+		if err != nil {
+			span.SetError(err)
+			return nil, fmt.Errorf("vault is already initialized but its init output could not be loaded: %w", err)
+		}
+	} else {
+		out, err = b.init()
+		if err != nil {
+			span.SetError(err)
+			return nil, fmt.Errorf("failed to initialize vault: %w", err)
+		}
+		if err := b.persist(out); err != nil {
+			span.SetError(err)
+			return nil, fmt.Errorf("failed to persist vault init output: %w", err)
+		}
+	}
+
+	if err := (&Unsealer{SecretsDir: b.opts.SecretsDir}).Unseal(); err != nil {
+		span.SetError(err)
+		return nil, fmt.Errorf("failed to unseal vault: %w", err)
+	}
+
+	if err := b.setupNomadClusterIntegration(ctx, out.RootToken); err != nil {
+		span.SetError(err)
+		return nil, fmt.Errorf("failed to set up nomad-cluster vault integration: %w", err)
+	}
+
+	return out, nil
+}
+
+// MigrateInitOutput moves a previously-persisted init output to
+// opts.Destination and removes the plaintext vault-init.json left behind by
+// DestinationFile, if that's where it was found. It's a no-op if the init
+// output is already at opts.Destination, so `styx init` can call it
+// unconditionally whenever --unseal-provider is set.
+func MigrateInitOutput(opts BootstrapOptions) error {
+	out, from, err := loadInitOutputFrom(opts.SecretsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load existing vault init output: %w", err)
+	}
+	if from == opts.Destination {
+		return nil
+	}
+
+	if err := NewBootstrapper(opts).persist(out); err != nil {
+		return fmt.Errorf("failed to persist vault init output to %s: %w", opts.Destination, err)
+	}
+
+	if from == DestinationFile {
+		path := filepath.Join(opts.SecretsDir, "vault-init.json")
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove plaintext %s after migrating to %s: %w", path, opts.Destination, err)
+		}
+	}
+	return nil
+}
+
+// initAPIResponse matches the JSON shape of the raw /v1/sys/init response,
+// which differs from `vault operator init -format=json` (InitOutput).
+type initAPIResponse struct {
+	KeysB64   []string `json:"keys_base64"`
+	RootToken string   `json:"root_token"`
+}
+
+func (b *Bootstrapper) init() (*InitOutput, error) {
+	payload, err := json.Marshal(map[string]int{
+		"secret_shares":    b.opts.SecretShares,
+		"secret_threshold": b.opts.SecretThreshold,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.Post(vaultAddr+"/v1/sys/init", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault init request failed (status %d)", resp.StatusCode)
+	}
+
+	var api initAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&api); err != nil {
+		return nil, err
+	}
+
+	return &InitOutput{UnsealKeysB64: api.KeysB64, RootToken: api.RootToken}, nil
+}
+
+func (b *Bootstrapper) persist(out *InitOutput) error {
+	if err := os.MkdirAll(b.opts.SecretsDir, 0700); err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+
+	switch b.opts.Destination {
+	case DestinationKeychain:
+		return persistKeychain(out)
+	case DestinationShamirFiles:
+		return persistShamirFiles(b.opts.SecretsDir, out)
+	case DestinationAWSKMS, DestinationGCPKMS:
+		return persistKMS(b.opts, out)
+	case DestinationShamir:
+		return persistShamir(b.opts, out)
+	case DestinationTransit:
+		return persistTransit(b.opts, out)
+	case DestinationPassphrase:
+		return persistPassphrase(b.opts, out)
+	default:
+		return persistFile(b.opts.SecretsDir, out)
+	}
+}
+
+func persistFile(secretsDir string, out *InitOutput) error {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(secretsDir, "vault-init.json"), data, 0600)
+}
+
+func persistShamirFiles(secretsDir string, out *InitOutput) error {
+	for i, key := range out.UnsealKeysB64 {
+		path := filepath.Join(secretsDir, fmt.Sprintf("vault-unseal-key-%d", i+1))
+		if err := os.WriteFile(path, []byte(key), 0600); err != nil {
+			return fmt.Errorf("failed to write unseal key share %d: %w", i+1, err)
+		}
+	}
+	return os.WriteFile(filepath.Join(secretsDir, "vault-root-token"), []byte(out.RootToken), 0600)
+}
+
+const keychainService = "styx-vault-init"
+
+func persistKeychain(out *InitOutput) error {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+
+	user, err := keychainAccount()
+	if err != nil {
+		return err
+	}
+
+	// -U updates the item in place if one already exists for this service/account.
+	cmd := exec.Command("security", "add-generic-password",
+		"-s", keychainService,
+		"-a", user,
+		"-w", string(data),
+		"-U",
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store vault init output in keychain: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+func loadKeychain() (*InitOutput, error) {
+	user, err := keychainAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", user, "-w")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault init output from keychain: %w", err)
+	}
+
+	var out InitOutput
+	if err := json.Unmarshal(output, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse vault init output from keychain: %w", err)
+	}
+	return &out, nil
+}
+
+func keychainAccount() (string, error) {
+	user := os.Getenv("USER")
+	if user == "" {
+		return "", fmt.Errorf("USER environment variable not set, cannot address keychain item")
+	}
+	return user, nil
+}
+
+func loadFile(secretsDir string) (*InitOutput, error) {
+	data, err := os.ReadFile(filepath.Join(secretsDir, "vault-init.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out InitOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse vault init file: %w", err)
+	}
+	return &out, nil
+}
+
+func loadShamirFiles(secretsDir string) (*InitOutput, error) {
+	rootToken, err := os.ReadFile(filepath.Join(secretsDir, "vault-root-token"))
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for i := 1; ; i++ {
+		key, err := os.ReadFile(filepath.Join(secretsDir, fmt.Sprintf("vault-unseal-key-%d", i)))
+		if err != nil {
+			break
+		}
+		keys = append(keys, string(key))
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no vault-unseal-key-* files found in %s", secretsDir)
+	}
+
+	return &InitOutput{UnsealKeysB64: keys, RootToken: string(rootToken)}, nil
+}
+
+// kmsBlobPath and shamirMetaPath are fixed, destination-specific filenames
+// (rather than vault-init.json) so a secrets directory can be migrated
+// between destinations without a stale blob from one shadowing another.
+func kmsBlobPath(secretsDir string) string {
+	return filepath.Join(secretsDir, "vault-init-kms.json")
+}
+
+func shamirMetaPath(secretsDir string) string {
+	return filepath.Join(secretsDir, "vault-init-shamir-meta.json")
+}
+
+func persistKMS(opts BootstrapOptions, out *InitOutput) error {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+
+	blobPath := kmsBlobPath(opts.SecretsDir)
+	keyID := opts.KMSKeyID
+	if keyID == "" {
+		// Rotate doesn't re-supply KMSKeyID; reuse whatever key the
+		// existing blob was encrypted under.
+		if existing, err := os.ReadFile(blobPath); err == nil {
+			var blob struct {
+				KeyID string `json:"key_id"`
+			}
+			if json.Unmarshal(existing, &blob) == nil {
+				keyID = blob.KeyID
+			}
+		}
+	}
+
+	var provider autounseal.Provider
+	if opts.Destination == DestinationGCPKMS {
+		provider = autounseal.NewGCPKMSProvider(keyID, blobPath)
+	} else {
+		provider = autounseal.NewAWSKMSProvider(keyID, blobPath)
+	}
+	return provider.Store(data)
+}
+
+// loadKMS also reports which of DestinationAWSKMS/DestinationGCPKMS the
+// blob was stored under, since KMSProvider.Load reads that back from the
+// blob itself rather than needing it supplied.
+func loadKMS(secretsDir string) (*InitOutput, KeyDestination, error) {
+	blobPath := kmsBlobPath(secretsDir)
+	raw, err := os.ReadFile(blobPath)
+	if err != nil {
+		return nil, "", err
+	}
+	var peek struct {
+		Backend string `json:"backend"`
+	}
+	if err := json.Unmarshal(raw, &peek); err != nil {
+		return nil, "", fmt.Errorf("failed to parse kms blob: %w", err)
+	}
+
+	data, err := (&autounseal.KMSProvider{BlobPath: blobPath}).Load()
+	if err != nil {
+		return nil, "", err
+	}
+	var out InitOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, "", fmt.Errorf("failed to parse kms-backed vault init blob: %w", err)
+	}
+
+	destination := DestinationAWSKMS
+	if peek.Backend == string(DestinationGCPKMS) {
+		destination = DestinationGCPKMS
+	}
+	return &out, destination, nil
+}
+
+func persistShamir(opts BootstrapOptions, out *InitOutput) error {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+
+	metaPath := shamirMetaPath(opts.SecretsDir)
+	peers, threshold := opts.ShamirPeers, opts.ShamirThreshold
+	if len(peers) == 0 {
+		// Rotate doesn't re-supply ShamirPeers/ShamirThreshold; reuse
+		// whatever the existing meta file names.
+		if existing, err := os.ReadFile(metaPath); err == nil {
+			var meta struct {
+				Peers     []string `json:"peers"`
+				Threshold int      `json:"threshold"`
+			}
+			if json.Unmarshal(existing, &meta) == nil {
+				peers, threshold = meta.Peers, meta.Threshold
+			}
+		}
+	}
+
+	return autounseal.NewShamirProvider("vault-init", peers, threshold, metaPath).Store(data)
+}
+
+func transitBlobPath(secretsDir string) string {
+	return filepath.Join(secretsDir, "vault-init-transit.json")
+}
+
+func passphraseBlobPath(secretsDir string) string {
+	return filepath.Join(secretsDir, "vault-init-passphrase.json")
+}
+
+func persistTransit(opts BootstrapOptions, out *InitOutput) error {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	provider := autounseal.NewTransitProvider(opts.TransitAddr, opts.TransitKeyName, opts.TransitToken, transitBlobPath(opts.SecretsDir))
+	return provider.Store(data)
+}
+
+func loadTransit(secretsDir string, token string) (*InitOutput, error) {
+	data, err := (&autounseal.TransitProvider{BlobPath: transitBlobPath(secretsDir), Token: token}).Load()
+	if err != nil {
+		return nil, err
+	}
+	var out InitOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse transit-decrypted vault init blob: %w", err)
+	}
+	return &out, nil
+}
+
+func persistPassphrase(opts BootstrapOptions, out *InitOutput) error {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	provider := autounseal.NewPassphraseProvider(opts.Passphrase, passphraseBlobPath(opts.SecretsDir))
+	return provider.Store(data)
+}
+
+func loadPassphrase(secretsDir string, passphrase string) (*InitOutput, error) {
+	data, err := (&autounseal.PassphraseProvider{Passphrase: passphrase, BlobPath: passphraseBlobPath(secretsDir)}).Load()
+	if err != nil {
+		return nil, err
+	}
+	var out InitOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse passphrase-decrypted vault init blob: %w", err)
+	}
+	return &out, nil
+}
+
+func loadShamir(secretsDir string) (*InitOutput, error) {
+	data, err := (&autounseal.ShamirProvider{MetaPath: shamirMetaPath(secretsDir)}).Load()
+	if err != nil {
+		return nil, err
+	}
+	var out InitOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse shamir-reconstructed vault init blob: %w", err)
+	}
+	return &out, nil
+}
+
+// transitTokenEnv and passphraseEnv are where Unsealer, Rotate, and
+// MigrateInitOutput read the decryption secret for
+// DestinationTransit/DestinationPassphrase from. Unlike the other
+// destinations, decrypting these needs something only the operator knows -
+// not just local disk state or ambient cloud CLI credentials - so there's
+// no BootstrapOptions field for callers that only have a secretsDir.
+const (
+	transitTokenEnv = "STYX_VAULT_TRANSIT_TOKEN"
+	passphraseEnv   = "STYX_VAULT_PASSPHRASE"
+)
+
+// loadInitOutput reloads a previously-persisted init output, trying each
+// destination in turn since the caller may not know which one was used.
+func loadInitOutput(secretsDir string) (*InitOutput, error) {
+	out, _, err := loadInitOutputFrom(secretsDir)
+	return out, err
+}
+
+// loadInitOutputFrom is like loadInitOutput but also reports which
+// destination it found the init output in, so callers like Rotate can
+// persist an update back to the same place.
+func loadInitOutputFrom(secretsDir string) (*InitOutput, KeyDestination, error) {
+	if out, err := loadFile(secretsDir); err == nil {
+		return out, DestinationFile, nil
+	}
+	if out, err := loadShamirFiles(secretsDir); err == nil {
+		return out, DestinationShamirFiles, nil
+	}
+	if out, err := loadKeychain(); err == nil {
+		return out, DestinationKeychain, nil
+	}
+	if out, dest, err := loadKMS(secretsDir); err == nil {
+		return out, dest, nil
+	}
+	if out, err := loadTransit(secretsDir, os.Getenv(transitTokenEnv)); err == nil {
+		return out, DestinationTransit, nil
+	}
+	if out, err := loadPassphrase(secretsDir, os.Getenv(passphraseEnv)); err == nil {
+		return out, DestinationPassphrase, nil
+	}
+	if out, err := loadShamir(secretsDir); err == nil {
+		return out, DestinationShamir, nil
+	}
+	return nil, "", fmt.Errorf("no vault init output found in %s (file, shamir-files, keychain, awskms, gcpckms, shamir, transit, or passphrase)", secretsDir)
+}
+
+// StampClusterIdentity writes Styx's own cluster_id/bootstrap_token (see
+// internal/cluster) to Vault's KV store at secret/styx/cluster, so any node
+// that can read Vault - not just the one that generated them - can look up
+// the identity of the cluster it's part of.
+func StampClusterIdentity(rootToken, clusterID, bootstrapToken string) error {
+	store, err := NewStore(StoreKindVault, "")
+	if err != nil {
+		return err
+	}
+	if err := store.WithToken(rootToken).KVPut("styx/cluster", map[string]string{
+		"cluster_id":      clusterID,
+		"bootstrap_token": bootstrapToken,
+	}); err != nil {
+		return fmt.Errorf("failed to write cluster identity to vault kv: %w", err)
+	}
+	return nil
+}
+
+// setupNomadClusterIntegration creates the classic Nomad Vault integration:
+// a policy scoped to what Nomad servers themselves need, and a
+// nomad-cluster token role, then mints the token that gets injected into
+// ServerConfig.VaultToken. This is the token-based predecessor to
+// SetupNomadIntegration's JWT workload identity auth, and is what Nomad's
+// own `vault` stanza uses before a `token` is configured.
+func (b *Bootstrapper) setupNomadClusterIntegration(ctx context.Context, rootToken string) error {
+	ctx, span := tracing.StartSpan(ctx, "vault.bootstrap.setup_nomad_cluster_integration")
+	defer span.End()
+
+	env := append(os.Environ(), "VAULT_ADDR="+vaultAddr, "VAULT_TOKEN="+rootToken)
+
+	nomadServerPolicy := `
+# Allow Nomad servers to mint and manage nomad-cluster tokens for tasks
+path "auth/token/create/nomad-cluster" {
+  capabilities = ["update"]
+}
+
+path "auth/token/roles/nomad-cluster" {
+  capabilities = ["read"]
+}
+
+path "auth/token/lookup-self" {
+  capabilities = ["read"]
+}
+
+path "auth/token/renew-self" {
+  capabilities = ["update"]
+}
+
+path "auth/token/revoke-accessor" {
+  capabilities = ["update"]
+}
+
+path "sys/capabilities-self" {
+  capabilities = ["update"]
+}
+`
+	policyFile := filepath.Join(b.opts.SecretsDir, "nomad-server-policy.hcl")
+	if err := os.WriteFile(policyFile, []byte(nomadServerPolicy), 0600); err != nil {
+		return fmt.Errorf("failed to write nomad-server policy: %w", err)
+	}
+
+	cmd := exec.Command("vault", "policy", "write", "nomad-server", policyFile)
+	cmd.Env = env
+	if output, err := runTracedVaultCmd(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to create nomad-server policy: %w\nOutput: %s", err, output)
+	}
+
+	cmd = exec.Command("vault", "write", "auth/token/roles/nomad-cluster",
+		"allowed_policies=nomad-server",
+		"orphan=true",
+		"token_period=259200",
+		"renewable=true",
+	)
+	cmd.Env = env
+	if output, err := runTracedVaultCmd(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to create nomad-cluster token role: %w\nOutput: %s", err, output)
+	}
+
+	cmd = exec.Command("vault", "token", "create", "-role=nomad-cluster", "-field=token")
+	cmd.Env = env
+	token, err := runTracedVaultCmd(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to generate nomad-cluster token: %w", err)
+	}
+
+	tokenFile := filepath.Join(b.opts.SecretsDir, "nomad-vault-token")
+	if err := os.WriteFile(tokenFile, token, 0600); err != nil {
+		return fmt.Errorf("failed to write nomad vault token: %w", err)
+	}
+
+	return nil
+}
+
+// runTracedVaultCmd runs cmd, recording a child span with its args and exit
+// code. Returned output is stdout alone on success (so callers reading a
+// field value, like the nomad-cluster token, get a clean result) and
+// stdout+stderr on failure, matching the CombinedOutput this replaces for
+// error-message purposes.
+func runTracedVaultCmd(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	_, span := tracing.StartSpan(ctx, "vault.cli."+strings.Join(cmd.Args[:2], "_"))
+	defer span.End()
+	span.SetAttribute("args", strings.Join(cmd.Args, " "))
+
+	output, err := cmd.Output()
+	exitCode := 0
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+			output = append(output, exitErr.Stderr...)
+		} else {
+			exitCode = -1
+		}
+		span.SetError(err)
+	}
+	span.SetAttributeInt("exit_code", int64(exitCode))
+
+	return output, err
+}
@@ -0,0 +1,99 @@
+package vault
+
+import (
+	"fmt"
+	"sync"
+)
+
+// devStore is an in-memory SecretStore with no persistence, for local
+// development and tests where no real Vault/OpenBao process is running.
+// It accepts exactly the unseal key it minted at Initialize and has no
+// concept of auth methods, so EnableAuth always returns ErrUnsupported -
+// SetupNomadIntegration treats that as "skip JWT workload identity setup",
+// which is the right behavior for a store with no JWKS validation to do.
+type devStore struct {
+	mu          sync.Mutex
+	initialized bool
+	sealed      bool
+	unsealKey   string
+	rootToken   string
+	kv          map[string]map[string]string
+	policies    map[string]string
+}
+
+func newDevStore() *devStore {
+	return &devStore{
+		kv:       make(map[string]map[string]string),
+		policies: make(map[string]string),
+	}
+}
+
+func (s *devStore) WithToken(token string) SecretStore {
+	// No auth checks to enforce in memory; token is accepted but unused.
+	return s
+}
+
+func (s *devStore) Initialize(shares, threshold int) (*InitOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unsealKey = "dev-unseal-key"
+	s.rootToken = "dev-root-token"
+	s.initialized = true
+	s.sealed = true
+	return &InitOutput{UnsealKeysB64: []string{s.unsealKey}, RootToken: s.rootToken}, nil
+}
+
+func (s *devStore) Unseal(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key == s.unsealKey {
+		s.sealed = false
+	}
+	return s.sealed, nil
+}
+
+func (s *devStore) Status() (*VaultStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &VaultStatus{Initialized: s.initialized, Sealed: s.sealed}, nil
+}
+
+func (s *devStore) KVPut(path string, data map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string]string, len(data))
+	for k, v := range data {
+		values[k] = v
+	}
+	s.kv[path] = values
+	return nil
+}
+
+func (s *devStore) KVGet(path string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, ok := s.kv[path]
+	if !ok {
+		return nil, fmt.Errorf("no secret found at %s", path)
+	}
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (s *devStore) EnableAuth(method, path string, config map[string]string) error {
+	return ErrUnsupported
+}
+
+func (s *devStore) WritePolicy(name, policy string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policies[name] = policy
+	return nil
+}
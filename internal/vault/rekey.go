@@ -0,0 +1,95 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/kessler-frost/styx/internal/vault/autounseal"
+)
+
+// Rekey generates a new set of unseal key shares via `vault operator
+// rekey`, authorized by submitting the existing shares, and persists the
+// replacement to the same destination the original init output was loaded
+// from. Root token generation isn't involved - Rotate handles that
+// separately - so the root token carries over unchanged.
+//
+// For DestinationTransit/DestinationPassphrase, the decryption secret isn't
+// on disk - it's read from STYX_VAULT_TRANSIT_TOKEN/STYX_VAULT_PASSPHRASE,
+// same as Unsealer and MigrateInitOutput.
+//
+// `vault operator rekey` is a multi-step nonce-tracked exchange, like
+// `vault operator generate-root` (see Rotate); shelling out to the CLI,
+// which already implements it, is simpler and safer than reimplementing it
+// over raw HTTP.
+func Rekey(secretsDir string, shares, threshold int) (*InitOutput, error) {
+	out, destination, err := loadInitOutputFrom(secretsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load vault init output: %w", err)
+	}
+
+	env := append(os.Environ(), "VAULT_ADDR="+vaultAddr, "VAULT_TOKEN="+out.RootToken)
+
+	initCmd := exec.Command("vault", "operator", "rekey", "-init", "-format=json",
+		fmt.Sprintf("-key-shares=%d", shares),
+		fmt.Sprintf("-key-threshold=%d", threshold),
+	)
+	initCmd.Env = env
+	initOutput, err := initCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rekey: %w", err)
+	}
+
+	var initResp struct {
+		Nonce string `json:"nonce"`
+	}
+	if err := json.Unmarshal(initOutput, &initResp); err != nil {
+		return nil, fmt.Errorf("failed to parse rekey init response: %w", err)
+	}
+
+	var newKeys []string
+	for _, key := range out.UnsealKeysB64 {
+		cmd := exec.Command("vault", "operator", "rekey", "-format=json", "-nonce="+initResp.Nonce, key)
+		cmd.Env = env
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to submit rekey share: %w", err)
+		}
+
+		var resp struct {
+			Complete bool     `json:"complete"`
+			KeysB64  []string `json:"keys_base64"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse rekey response: %w", err)
+		}
+		if resp.Complete {
+			newKeys = resp.KeysB64
+			break
+		}
+	}
+	if len(newKeys) == 0 {
+		return nil, fmt.Errorf("rekey did not complete after submitting all %d existing key shares", len(out.UnsealKeysB64))
+	}
+
+	newOut := &InitOutput{UnsealKeysB64: newKeys, RootToken: out.RootToken}
+
+	opts := DefaultBootstrapOptions(secretsDir)
+	opts.Destination = destination
+	opts.SecretShares, opts.SecretThreshold = shares, threshold
+	opts.TransitToken = os.Getenv(transitTokenEnv)
+	opts.Passphrase = os.Getenv(passphraseEnv)
+	if destination == DestinationTransit {
+		addr, keyName, err := autounseal.TransitBlobMeta(transitBlobPath(secretsDir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read existing transit blob metadata: %w", err)
+		}
+		opts.TransitAddr, opts.TransitKeyName = addr, keyName
+	}
+	if err := NewBootstrapper(opts).persist(newOut); err != nil {
+		return nil, fmt.Errorf("failed to persist rekeyed shares: %w", err)
+	}
+
+	return newOut, nil
+}
@@ -0,0 +1,51 @@
+package vault
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Rotate generates a new Vault root token, revokes the previous one, and
+// persists the replacement to the same destination the original init
+// output was loaded from. It is idempotent: calling it repeatedly just
+// rotates again, leaving exactly one valid root token stored.
+//
+// Root token generation is a multi-step OTP/nonce exchange
+// (`vault operator generate-root`); shelling out to the CLI, which already
+// implements that exchange, is simpler and safer than reimplementing it
+// over raw HTTP.
+func Rotate(secretsDir string) (string, error) {
+	out, destination, err := loadInitOutputFrom(secretsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to load vault init output: %w", err)
+	}
+
+	env := append(os.Environ(), "VAULT_ADDR="+vaultAddr, "VAULT_TOKEN="+out.RootToken)
+
+	cmd := exec.Command("vault", "token", "create", "-policy=root", "-field=token")
+	cmd.Env = env
+	newTokenOutput, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate new root token: %w", err)
+	}
+	newToken := strings.TrimSpace(string(newTokenOutput))
+
+	oldToken := out.RootToken
+	out.RootToken = newToken
+
+	opts := DefaultBootstrapOptions(secretsDir)
+	opts.Destination = destination
+	if err := NewBootstrapper(opts).persist(out); err != nil {
+		return "", fmt.Errorf("failed to persist rotated root token: %w", err)
+	}
+
+	revokeCmd := exec.Command("vault", "token", "revoke", oldToken)
+	revokeCmd.Env = append(os.Environ(), "VAULT_ADDR="+vaultAddr, "VAULT_TOKEN="+newToken)
+	if output, err := revokeCmd.CombinedOutput(); err != nil {
+		return newToken, fmt.Errorf("new root token %s is active, but revoking the old one failed: %w\nOutput: %s", newToken, err, output)
+	}
+
+	return newToken, nil
+}
@@ -1,16 +1,20 @@
 package vault
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/kessler-frost/styx/internal/readiness"
+	"github.com/kessler-frost/styx/internal/tracing"
 )
 
 // InitOutput holds the output from vault operator init.
@@ -19,100 +23,21 @@ type InitOutput struct {
 	RootToken     string   `json:"root_token"`
 }
 
-// Initialize runs vault operator init and saves the unseal keys.
-// Returns the root token on success.
-func Initialize(secretsDir string) (string, error) {
-	// Check if already initialized
-	initFile := filepath.Join(secretsDir, "vault-init.json")
-	if _, err := os.Stat(initFile); err == nil {
-		// Already initialized, load and return root token
-		data, err := os.ReadFile(initFile)
-		if err != nil {
-			return "", fmt.Errorf("failed to read vault init file: %w", err)
-		}
-		var output InitOutput
-		if err := json.Unmarshal(data, &output); err != nil {
-			return "", fmt.Errorf("failed to parse vault init file: %w", err)
-		}
-		return output.RootToken, nil
-	}
-
-	// Initialize Vault
-	cmd := exec.Command("vault", "operator", "init", "-format=json", "-key-shares=1", "-key-threshold=1")
-	cmd.Env = append(os.Environ(), "VAULT_ADDR=http://127.0.0.1:8200")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to initialize vault: %w", err)
-	}
-
-	var initOutput InitOutput
-	if err := json.Unmarshal(output, &initOutput); err != nil {
-		return "", fmt.Errorf("failed to parse vault init output: %w", err)
-	}
-
-	// Save init output to secrets directory
-	if err := os.MkdirAll(secretsDir, 0700); err != nil {
-		return "", fmt.Errorf("failed to create secrets directory: %w", err)
-	}
-
-	if err := os.WriteFile(initFile, output, 0600); err != nil {
-		return "", fmt.Errorf("failed to write vault init file: %w", err)
-	}
-
-	return initOutput.RootToken, nil
-}
-
-// Unseal attempts to unseal Vault using stored unseal keys.
-func Unseal(secretsDir string) error {
-	initFile := filepath.Join(secretsDir, "vault-init.json")
-	data, err := os.ReadFile(initFile)
-	if err != nil {
-		return fmt.Errorf("failed to read vault init file: %w", err)
-	}
-
-	var initOutput InitOutput
-	if err := json.Unmarshal(data, &initOutput); err != nil {
-		return fmt.Errorf("failed to parse vault init file: %w", err)
-	}
-
-	// Unseal with first key
-	cmd := exec.Command("vault", "operator", "unseal", initOutput.UnsealKeysB64[0])
-	cmd.Env = append(os.Environ(), "VAULT_ADDR=http://127.0.0.1:8200")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to unseal vault: %w", err)
-	}
-
-	return nil
-}
-
 // VaultStatus holds the parsed status from vault status command.
 type VaultStatus struct {
 	Initialized bool `json:"initialized"`
 	Sealed      bool `json:"sealed"`
 }
 
-// GetStatus returns the current Vault status.
+// GetStatus returns the current Vault status, over the HTTP API rather
+// than the `vault` CLI - which used to require special-casing exit code 2
+// ("sealed") to still parse its JSON output.
 func GetStatus() (*VaultStatus, error) {
-	cmd := exec.Command("vault", "status", "-format=json")
-	cmd.Env = append(os.Environ(), "VAULT_ADDR=http://127.0.0.1:8200")
-
-	// vault status returns exit code 2 when sealed, but still outputs JSON
-	output, err := cmd.CombinedOutput()
+	store, err := NewStore(StoreKindVault, "")
 	if err != nil {
-		// Check if it's just a "sealed" exit code (2) - we can still parse the JSON
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 2 {
-			// Continue to parse the JSON output
-		} else {
-			return nil, fmt.Errorf("failed to check vault status: %w", err)
-		}
+		return nil, err
 	}
-
-	var status VaultStatus
-	if err := json.Unmarshal(output, &status); err != nil {
-		return nil, fmt.Errorf("failed to parse vault status: %w", err)
-	}
-
-	return &status, nil
+	return store.Status()
 }
 
 // IsSealed checks if Vault is sealed.
@@ -163,50 +88,35 @@ func generateRandomPassword(length int) (string, error) {
 	return string(b), nil
 }
 
-// waitForNomadJWKS waits for Nomad's JWKS endpoint to become available.
-func waitForNomadJWKS(timeout time.Duration) error {
+// waitForNomadJWKS waits for Nomad's JWKS endpoint to become available,
+// using the shared readiness.Wait polling primitive.
+func waitForNomadJWKS(ctx context.Context, timeout time.Duration) error {
+	_, span := tracing.StartSpan(ctx, "vault.setup.wait_for_nomad_jwks")
+	defer span.End()
+
 	jwksURL := "http://127.0.0.1:4646/.well-known/jwks.json"
+	span.SetAttribute("jwks_url", jwksURL)
 	client := &http.Client{Timeout: 5 * time.Second}
 
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
+	probe := func() (bool, error) {
 		resp, err := client.Get(jwksURL)
-		if err == nil && resp.StatusCode == 200 {
-			resp.Body.Close()
-			return nil
-		}
-		if resp != nil {
-			resp.Body.Close()
+		if err != nil {
+			return false, err
 		}
-		time.Sleep(2 * time.Second)
+		defer resp.Body.Close()
+		return resp.StatusCode == 200, nil
 	}
 
-	return fmt.Errorf("timeout waiting for Nomad JWKS endpoint at %s", jwksURL)
-}
-
-// SetupNomadIntegration configures Vault JWT auth for Nomad workload identities.
-// This is the modern approach for Nomad 1.7+ that uses short-lived tokens.
-func SetupNomadIntegration(secretsDir string) error {
-	rootToken, err := GetRootToken(secretsDir)
-	if err != nil {
+	if err := readiness.Wait(ctx, "nomad JWKS endpoint at "+jwksURL, timeout, 2*time.Second, probe); err != nil {
+		span.SetError(err)
 		return err
 	}
+	return nil
+}
 
-	env := append(os.Environ(),
-		"VAULT_ADDR=http://127.0.0.1:8200",
-		"VAULT_TOKEN="+rootToken,
-	)
-
-	// Enable KV secrets engine
-	cmd := exec.Command("vault", "secrets", "enable", "-path=secret", "kv-v2")
-	cmd.Env = env
-	// Ignore error if already enabled
-	if err := cmd.Run(); err != nil {
-		fmt.Printf("Note: KV secrets engine may already be enabled: %v\n", err)
-	}
-
-	// Create Nomad workload policy for reading secrets
-	nomadPolicy := `
+// nomadWorkloadsPolicy lets Nomad workload identities read (but not list
+// or write) secrets under secret/.
+const nomadWorkloadsPolicy = `
 # Allow reading secrets
 path "secret/data/*" {
   capabilities = ["read"]
@@ -216,57 +126,101 @@ path "secret/metadata/*" {
   capabilities = ["read", "list"]
 }
 `
-	policyFile := filepath.Join(secretsDir, "nomad-workloads-policy.hcl")
-	if err := os.WriteFile(policyFile, []byte(nomadPolicy), 0600); err != nil {
-		return fmt.Errorf("failed to write nomad workloads policy: %w", err)
-	}
 
-	cmd = exec.Command("vault", "policy", "write", "nomad-workloads", policyFile)
-	cmd.Env = env
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to create nomad workloads policy: %w", err)
-	}
+// kvMounter is an optional SecretStore capability for backends (like
+// apiStore) that need their kv-v2 engine explicitly enabled before first
+// use. Backends without it, like devStore, don't need this step.
+type kvMounter interface {
+	MountKV() error
+}
+
+// roleWriter is an optional SecretStore capability for backends that can
+// configure an auth method's role bindings (e.g. auth/jwt-nomad/role/...).
+// It's kept out of SecretStore since it's JWT/Nomad-specific rather than a
+// generic secrets-store verb.
+type roleWriter interface {
+	WriteAuthRole(authPath, role string, fields map[string]string) error
+}
 
-	// Enable JWT auth method for Nomad workload identities
-	cmd = exec.Command("vault", "auth", "enable", "-path=jwt-nomad", "jwt")
-	cmd.Env = env
-	// Ignore error if already enabled
-	if err := cmd.Run(); err != nil {
-		fmt.Printf("Note: JWT auth method may already be enabled: %v\n", err)
+// SetupNomadIntegration configures JWT auth for Nomad workload identities
+// against store - the modern approach for Nomad 1.7+ that uses short-lived
+// tokens, as opposed to bootstrap.go's classic nomad-cluster token role.
+// store must already carry a token authorized to perform these writes (see
+// SecretStore.WithToken).
+//
+// Backends that can't support auth methods (identified via the roleWriter
+// capability, which a JWT-capable backend must implement) skip JWT setup
+// entirely rather than failing: SetupNomadIntegration still creates the
+// workloads policy and default secrets, which every backend supports.
+func SetupNomadIntegration(store SecretStore, secretsDir string) error {
+	ctx, span := tracing.StartSpan(context.Background(), "vault.setup_nomad_integration")
+	defer span.End()
+
+	if mounter, ok := store.(kvMounter); ok {
+		if err := mounter.MountKV(); err != nil {
+			fmt.Printf("Note: %v\n", err)
+		}
 	}
 
-	// Wait for Nomad's JWKS endpoint to be ready before configuring JWT auth
-	// Vault validates the JWKS URL when writing config, so it must be available
-	if err := waitForNomadJWKS(60 * time.Second); err != nil {
-		return fmt.Errorf("Nomad JWKS not available: %w", err)
+	if err := store.WritePolicy("nomad-workloads", nomadWorkloadsPolicy); err != nil {
+		err = fmt.Errorf("failed to create nomad workloads policy: %w", err)
+		span.SetError(err)
+		return err
 	}
 
-	// Configure JWT auth with Nomad's JWKS endpoint
-	// The JWKS endpoint is served by Nomad at /.well-known/jwks.json
-	cmd = exec.Command("vault", "write", "auth/jwt-nomad/config",
-		"jwks_url=http://127.0.0.1:4646/.well-known/jwks.json",
-		"default_role=nomad-workloads",
-	)
-	cmd.Env = env
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to configure JWT auth: %w\nOutput: %s", err, output)
+	// Enable JWT auth method for Nomad workload identities. A failure here
+	// (e.g. a transient Vault API error) doesn't stop the rest of setup -
+	// the config write below will fail loudly if the mount never actually
+	// came up, same as the old CLI-based code only hard-failed on
+	// `vault write auth/jwt-nomad/config`, not `vault auth enable`.
+	rw, supportsRoles := store.(roleWriter)
+	skipJWT := false
+	if err := store.EnableAuth("jwt", "jwt-nomad", nil); err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			fmt.Println("Note: this secret store backend doesn't support auth methods; skipping Nomad JWT workload identity setup")
+			skipJWT = true
+		} else {
+			fmt.Printf("Note: failed to enable jwt auth method, trying to configure it anyway: %v\n", err)
+		}
+	}
+	if !skipJWT && !supportsRoles {
+		fmt.Println("Note: this secret store backend can't configure auth roles; skipping Nomad JWT workload identity setup")
+		skipJWT = true
 	}
 
-	// Create a role for Nomad workloads
-	// This role maps Nomad workload identities to Vault policies
-	cmd = exec.Command("vault", "write", "auth/jwt-nomad/role/nomad-workloads",
-		"role_type=jwt",
-		"bound_audiences=vault.io",
-		"user_claim=/nomad_job_id",
-		"user_claim_json_pointer=true",
-		"token_type=service",
-		"token_policies=nomad-workloads",
-		"token_period=30m",
-		"token_ttl=1h",
-	)
-	cmd.Env = env
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to create JWT role: %w\nOutput: %s", err, output)
+	if !skipJWT {
+		// Vault validates the JWKS URL when writing config, so it must be
+		// reachable before we configure it.
+		if err := waitForNomadJWKS(ctx, 60*time.Second); err != nil {
+			err = fmt.Errorf("Nomad JWKS not available: %w", err)
+			span.SetError(err)
+			return err
+		}
+
+		if err := store.EnableAuth("jwt", "jwt-nomad", map[string]string{
+			"jwks_url":     "http://127.0.0.1:4646/.well-known/jwks.json",
+			"default_role": "nomad-workloads",
+		}); err != nil {
+			err = fmt.Errorf("failed to configure jwt auth: %w", err)
+			span.SetError(err)
+			return err
+		}
+
+		// Maps Nomad workload identities to the nomad-workloads policy.
+		if err := rw.WriteAuthRole("jwt-nomad", "nomad-workloads", map[string]string{
+			"role_type":               "jwt",
+			"bound_audiences":         "vault.io",
+			"user_claim":              "/nomad_job_id",
+			"user_claim_json_pointer": "true",
+			"token_type":              "service",
+			"token_policies":          "nomad-workloads",
+			"token_period":            "30m",
+			"token_ttl":               "1h",
+		}); err != nil {
+			err = fmt.Errorf("failed to create jwt role: %w", err)
+			span.SetError(err)
+			return err
+		}
 	}
 
 	// Create random postgres password
@@ -274,9 +228,7 @@ path "secret/metadata/*" {
 	if err != nil {
 		return fmt.Errorf("failed to generate postgres password: %w", err)
 	}
-	cmd = exec.Command("vault", "kv", "put", "secret/postgres", fmt.Sprintf("password=%s", postgresPassword))
-	cmd.Env = env
-	if err := cmd.Run(); err != nil {
+	if err := store.KVPut("postgres", map[string]string{"password": postgresPassword}); err != nil {
 		fmt.Printf("Note: failed to create default postgres secret: %v\n", err)
 	}
 
@@ -289,11 +241,7 @@ path "secret/metadata/*" {
 	if err != nil {
 		return fmt.Errorf("failed to generate rustfs secret key: %w", err)
 	}
-	cmd = exec.Command("vault", "kv", "put", "secret/rustfs",
-		fmt.Sprintf("access_key=%s", rustfsAccessKey),
-		fmt.Sprintf("secret_key=%s", rustfsSecretKey))
-	cmd.Env = env
-	if err := cmd.Run(); err != nil {
+	if err := store.KVPut("rustfs", map[string]string{"access_key": rustfsAccessKey, "secret_key": rustfsSecretKey}); err != nil {
 		fmt.Printf("Note: failed to create default rustfs secret: %v\n", err)
 	}
 
@@ -302,16 +250,16 @@ path "secret/metadata/*" {
 	if err != nil {
 		return fmt.Errorf("failed to generate grafana password: %w", err)
 	}
-	cmd = exec.Command("vault", "kv", "put", "secret/grafana", fmt.Sprintf("admin_password=%s", grafanaPassword))
-	cmd.Env = env
-	if err := cmd.Run(); err != nil {
+	if err := store.KVPut("grafana", map[string]string{"admin_password": grafanaPassword}); err != nil {
 		fmt.Printf("Note: failed to create default grafana secret: %v\n", err)
 	}
 
 	// Save a marker file to indicate workload identity is configured
 	markerFile := filepath.Join(secretsDir, "vault-workload-identity-configured")
 	if err := os.WriteFile(markerFile, []byte("configured"), 0600); err != nil {
-		return fmt.Errorf("failed to write marker file: %w", err)
+		err = fmt.Errorf("failed to write marker file: %w", err)
+		span.SetError(err)
+		return err
 	}
 
 	return nil
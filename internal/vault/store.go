@@ -0,0 +1,88 @@
+package vault
+
+import "fmt"
+
+// SecretStore abstracts the secrets backend so styx isn't permanently wired
+// to the `vault` CLI or even to HashiCorp Vault: HTTP API implementations
+// (Vault, OpenBao) and an in-memory dev store all satisfy the same set of
+// operations. Bootstrapper, Unsealer, and SetupNomadIntegration are written
+// against this interface rather than calling exec.Command directly.
+//
+// The method set deliberately covers only what styx itself needs - it's
+// not a general Vault client. Operations beyond this set (minting
+// nomad-cluster tokens, generate-root) stay as direct `vault` CLI calls in
+// bootstrap.go/rotate.go, since they either need a multi-step OTP exchange
+// the CLI already implements or aren't something every backend could
+// support anyway.
+type SecretStore interface {
+	// Initialize generates shares unseal key shares (threshold of which are
+	// needed to unseal) and a root token. Called once, on first boot.
+	Initialize(shares, threshold int) (*InitOutput, error)
+	// Unseal submits one unseal key share and reports whether the store is
+	// still sealed afterward, so callers can stop as soon as enough shares
+	// have been submitted.
+	Unseal(key string) (sealed bool, err error)
+	// Status reports whether the store is initialized and sealed.
+	Status() (*VaultStatus, error)
+	// KVPut writes a secret at path.
+	KVPut(path string, data map[string]string) error
+	// KVGet reads the secret at path.
+	KVGet(path string) (map[string]string, error)
+	// EnableAuth enables an auth method at path and, if config is non-nil,
+	// writes it to the method's config endpoint. A backend that can't
+	// support auth methods at all (e.g. the in-memory dev store) returns
+	// ErrUnsupported so callers like SetupNomadIntegration can skip JWT
+	// workload identity setup instead of failing outright.
+	EnableAuth(method, path string, config map[string]string) error
+	// WritePolicy writes an HCL or JSON policy document under name.
+	WritePolicy(name, policy string) error
+	// WithToken sets the token used for authenticated operations (every
+	// method but Status) and returns the receiver, so it chains onto
+	// NewStore.
+	WithToken(token string) SecretStore
+}
+
+// ErrUnsupported is returned by SecretStore methods a backend can't
+// implement, rather than failing the caller outright.
+var ErrUnsupported = fmt.Errorf("operation not supported by this secret store backend")
+
+// StoreKind selects a SecretStore implementation, typically from config.
+type StoreKind string
+
+const (
+	// StoreKindVault is the default: a real HashiCorp Vault server.
+	StoreKindVault StoreKind = "vault"
+	// StoreKindOpenBao is HashiCorp Vault's open-source fork; it speaks the
+	// same HTTP API, so it reuses apiStore with a different default address.
+	StoreKindOpenBao StoreKind = "openbao"
+	// StoreKindDev is an in-memory store with no persistence and no auth
+	// method support, for local development and tests where no real
+	// backend is running.
+	StoreKindDev StoreKind = "dev"
+)
+
+const openBaoAddr = "http://127.0.0.1:8200"
+
+// NewStore constructs the SecretStore for kind. addr overrides the
+// backend's default address; pass "" to use it (vaultAddr for
+// StoreKindVault, openBaoAddr for StoreKindOpenBao - they default to the
+// same local address since either is typically the one process listening
+// on it).
+func NewStore(kind StoreKind, addr string) (SecretStore, error) {
+	switch kind {
+	case StoreKindVault, "":
+		if addr == "" {
+			addr = vaultAddr
+		}
+		return newAPIStore(addr)
+	case StoreKindOpenBao:
+		if addr == "" {
+			addr = openBaoAddr
+		}
+		return newAPIStore(addr)
+	case StoreKindDev:
+		return newDevStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown secret store kind %q", kind)
+	}
+}
@@ -0,0 +1,92 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kessler-frost/styx/internal/tracing"
+)
+
+// Unsealer re-unseals Vault after a host restart, using whichever key
+// destination Bootstrapper persisted the init output to. It is meant to run
+// on every boot, before workloads are scheduled.
+type Unsealer struct {
+	SecretsDir string
+}
+
+// Unseal posts the stored key shares to /v1/sys/unseal until Vault reports
+// sealed=false. It is a no-op if Vault is already unsealed.
+func (u *Unsealer) Unseal() error {
+	ctx, span := tracing.StartSpan(context.Background(), "vault.unseal")
+	defer span.End()
+
+	status, err := GetStatus()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	if !status.Sealed {
+		return nil
+	}
+
+	out, err := loadInitOutput(u.SecretsDir)
+	if err != nil {
+		span.SetError(err)
+		return fmt.Errorf("failed to load vault init output to unseal: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	for i, key := range out.UnsealKeysB64 {
+		sealed, err := postUnsealKey(ctx, client, i, key)
+		if err != nil {
+			span.SetError(err)
+			return err
+		}
+		if !sealed {
+			return nil
+		}
+	}
+
+	err = fmt.Errorf("vault still sealed after submitting %d key share(s)", len(out.UnsealKeysB64))
+	span.SetError(err)
+	return err
+}
+
+func postUnsealKey(ctx context.Context, client *http.Client, index int, key string) (sealed bool, err error) {
+	_, span := tracing.StartSpan(ctx, "vault.unseal.submit_key")
+	defer span.End()
+	span.SetAttributeInt("key_index", int64(index))
+
+	body, err := json.Marshal(map[string]string{"key": key})
+	if err != nil {
+		span.SetError(err)
+		return false, err
+	}
+
+	resp, err := client.Post(vaultAddr+"/v1/sys/unseal", "application/json", bytes.NewReader(body))
+	if err != nil {
+		span.SetError(err)
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("unseal request failed (status %d)", resp.StatusCode)
+		span.SetError(err)
+		return false, err
+	}
+
+	var result struct {
+		Sealed bool `json:"sealed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		span.SetError(err)
+		return false, err
+	}
+	span.SetAttribute("sealed", fmt.Sprintf("%t", result.Sealed))
+	return result.Sealed, nil
+}